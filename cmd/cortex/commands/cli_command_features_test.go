@@ -32,6 +32,7 @@ func TestCLICommandFeatures(t *testing.T) {
 		"overview",
 		"graph",
 		"impact",
+		"annotate",
 	}
 
 	for _, sub := range expectedSubs {
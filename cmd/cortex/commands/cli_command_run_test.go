@@ -2,8 +2,15 @@ package commands
 
 import (
 	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/skills"
 )
 
 // Feature: CLI_COMMAND_RUN
@@ -34,3 +41,59 @@ func TestCLICommandRun(t *testing.T) {
 		t.Errorf("expected 'run' in help output")
 	}
 }
+
+func TestSelectTargetFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"a.go", "internal/b.go", "internal/c.md", "docs/d.md"} {
+		p := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scn := scanner.New(root)
+	wd := filepath.Join(root, "internal")
+
+	got, err := selectTargetFiles(context.Background(), wd, root, scn, []string{"*.go", "../docs"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, " ") != "docs/d.md internal/b.go" {
+		t.Errorf("paths are relative to the working directory, got %v", got)
+	}
+
+	_, err = selectTargetFiles(context.Background(), wd, root, scn, []string{"missing.go"})
+	if err == nil || !strings.Contains(err.Error(), "no tracked files match missing.go") {
+		t.Errorf("expected an unmatched path error, got %v", err)
+	}
+	_, err = selectTargetFiles(context.Background(), wd, root, scn, []string{"../.."})
+	if err == nil || !strings.Contains(err.Error(), "outside the repository") {
+		t.Errorf("expected an outside path error, got %v", err)
+	}
+}
+
+func TestRun_FileSkillsHonorTargetFiles(t *testing.T) {
+	root := t.TempDir()
+	for f, content := range map[string]string{
+		"spec/features.yaml": "features: []\n",
+		"spec/bad.yaml":      "a: [\n",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deps := &runner.Deps{RepoRoot: root, Scanner: scanner.New(root)}
+	if res := skills.NewDocsYaml().Run(context.Background(), deps); res.Status != runner.StatusFail {
+		t.Fatalf("expected the whole tree to fail on spec/bad.yaml, got %s: %s", res.Status, res.Note)
+	}
+	deps.TargetFiles = []string{"spec/features.yaml"}
+	if res := skills.NewDocsYaml().Run(context.Background(), deps); res.Status != runner.StatusPass {
+		t.Errorf("expected only spec/features.yaml to be checked, got %s: %s", res.Status, res.Note)
+	}
+}
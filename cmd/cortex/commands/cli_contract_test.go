@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
 )
 
 // Feature: CLI_CONTRACT
@@ -40,3 +42,20 @@ func TestCLIContract(t *testing.T) {
 		}
 	}
 }
+
+func TestCLIContractFlagErrorExitCode(t *testing.T) {
+	cmd := NewRootCmd()
+	cmd.SetOut(bytes.NewBufferString(""))
+	cmd.SetArgs([]string{"version", "--no-such-flag"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected unknown flag error")
+	}
+	if got := clierr.ExitCodeOf(err); got != clierr.ExitConfig {
+		t.Errorf("exit code = %d, want %d", got, clierr.ExitConfig)
+	}
+	if !strings.Contains(err.Error(), "unknown flag: --no-such-flag") {
+		t.Errorf("error %q does not name the flag", err)
+	}
+}
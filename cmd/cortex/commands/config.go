@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: CLI_COMMAND_CONFIG
+// Spec: spec/cli/config.md
+
+// NewConfigCommand returns the `cortex config` command.
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Validate and show the repository configuration",
+		Long: "Validates .cortex/config.yaml and the nested .cortex.yaml files against their schema and shows the " +
+			"configuration commands run with, after the profile ($CORTEX_PROFILE), the CORTEX_CONFIG_* environment " +
+			"overrides and the per-directory rules of nested .cortex.yaml files.",
+	}
+	cmd.AddCommand(newConfigValidateCommand())
+	cmd.AddCommand(newConfigShowCommand())
+	return cmd
+}
+
+func newConfigValidateCommand() *cobra.Command {
+	var (
+		asJSON  bool
+		profile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate .cortex/config.yaml, its profiles, environment overrides and nested files",
+		Long: `Parses .cortex/config.yaml and checks it, each of its profiles and the CORTEX_CONFIG_*
+environment overrides against the embedded config schema, then the nested .cortex.yaml
+files of subdirectories. Unknown keys are reported with the closest known key. Every
+problem is printed as source:line:column: path: message. Exits 1 on a problem.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			o := config.DefaultOptions()
+			if cmd.Flags().Changed("profile") {
+				o.Profile = profile
+			}
+			diags, err := config.Validate(repoRoot, o)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "config validate", err)
+			}
+			tracked, err := scanner.New(repoRoot).TrackedFiles(cmd.Context())
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "config validate", err)
+			}
+			nested, err := config.ValidateNested(repoRoot, tracked)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "config validate", err)
+			}
+			diags = append(diags, nested...)
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				if diags == nil {
+					diags = []config.Diagnostic{}
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(diags); err != nil {
+					return err
+				}
+			} else {
+				for _, d := range diags {
+					_, _ = fmt.Fprintf(out, "✗ %s\n", d)
+				}
+				if len(diags) == 0 {
+					_, _ = fmt.Fprintf(out, "✓ %s is valid\n", config.File)
+				}
+			}
+
+			if len(diags) > 0 {
+				return clierr.Newf(clierr.ExitValidation, "config validate: %d problem(s)", len(diags))
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the problems as JSON")
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to check exists (default $CORTEX_PROFILE)")
+
+	return cmd
+}
+
+// effectiveDir is the configuration in effect below a directory with a
+// nested config file.
+type effectiveDir struct {
+	Rules map[string]string `yaml:"rules"`
+}
+
+func newConfigShowCommand() *cobra.Command {
+	var (
+		effective bool
+		profile   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the configuration",
+		Long: `Prints .cortex/config.yaml as written. With --effective prints the configuration commands
+run with instead: every key with its default, the profile merged over the file and the
+CORTEX_CONFIG_* environment overrides applied, preceded by comments naming them, then the
+rules in effect in each directory with a nested .cortex.yaml.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			out := cmd.OutOrStdout()
+
+			if !effective {
+				data, err := os.ReadFile(config.Path(repoRoot))
+				if errors.Is(err, fs.ErrNotExist) {
+					_, err = fmt.Fprintf(out, "# no %s\n", config.File)
+					return err
+				}
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "config show", err)
+				}
+				_, err = out.Write(data)
+				return err
+			}
+
+			o := config.DefaultOptions()
+			if cmd.Flags().Changed("profile") {
+				o.Profile = profile
+			}
+			cfg, err := config.LoadWith(repoRoot, o)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "config show", err)
+			}
+			tracked, err := scanner.New(repoRoot).TrackedFiles(cmd.Context())
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "config show", err)
+			}
+			nested, err := config.LoadNested(repoRoot, tracked)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "config show", err)
+			}
+			if o.Profile != "" {
+				_, _ = fmt.Fprintf(out, "# profile: %s\n", o.Profile)
+			}
+			for _, ov := range config.Overrides(o.Environ) {
+				_, _ = fmt.Fprintf(out, "# override: %s\n", ov.Var)
+			}
+			for _, dc := range nested {
+				_, _ = fmt.Fprintf(out, "# nested: %s/%s\n", dc.Dir, config.NestedFile)
+			}
+			enc := yaml.NewEncoder(out)
+			enc.SetIndent(2)
+			if err := enc.Encode(cfg); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "config show", err)
+			}
+			if len(nested) > 0 {
+				// The rules of each subtree with a nested file, after merging
+				// the root rules and those of the nested files above it.
+				dirs := map[string]effectiveDir{}
+				for _, dc := range nested {
+					dirs[dc.Dir] = effectiveDir{Rules: config.EffectiveRules(cfg.Rules, nested, dc.Dir)}
+				}
+				if err := enc.Encode(map[string]any{"directories": dirs}); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "config show", err)
+				}
+			}
+			return enc.Close()
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&effective, "effective", false, "Print the merged configuration with defaults, profile and environment overrides")
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to apply (default $CORTEX_PROFILE)")
+
+	return cmd
+}
@@ -22,9 +22,19 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/bartekus/cortex/internal/builder"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/contextdocs"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/log"
 	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/site"
+	"github.com/bartekus/cortex/internal/tokens"
+	"github.com/bartekus/cortex/internal/version"
 	"github.com/bartekus/cortex/internal/xray"
 
 	"github.com/spf13/cobra"
@@ -41,11 +51,22 @@ func NewContextCommand() *cobra.Command {
 		Long:  "Commands for building and managing AI-readable context representations of the repository.",
 	}
 
+	cmd.AddCommand(NewContextAgentsCommand())
 	cmd.AddCommand(NewContextBuildCommand())
+	cmd.AddCommand(NewContextChangesCommand())
+	cmd.AddCommand(NewContextComposeCommand())
 	cmd.AddCommand(NewContextDocsCommand())
+	cmd.AddCommand(NewContextEmbedCommand())
+	cmd.AddCommand(NewContextGraphCommand())
+	cmd.AddCommand(NewContextQueryCommand())
+	cmd.AddCommand(NewContextSearchCommand())
+	cmd.AddCommand(NewContextSummarizeCommand())
+	cmd.AddCommand(NewContextTokensCommand())
+	cmd.AddCommand(NewContextVerifyCommand())
 	cmd.AddCommand(NewContextXrayCommand())
 
-	// Shared flag for all context commands (needed by build and xray)
+	// Shared flags for all context commands (needed by build and xray)
+	cmd.PersistentFlags().String("engine", xray.EngineXray, "Scan engine: xray (Rust binary) or go (built-in)")
 	cmd.PersistentFlags().String("xray-bin", "", "Path to xray binary")
 
 	return cmd
@@ -53,12 +74,20 @@ func NewContextCommand() *cobra.Command {
 
 // NewContextBuildCommand returns the `cortex context build` command.
 func NewContextBuildCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "build",
 		Short: "Build AI context representation",
-		Long:  "Builds a deterministic AI-readable context representation in .cortex/",
-		RunE:  runContextBuild,
+		Long: "Builds a deterministic AI-readable context representation in .cortex/\n" +
+			"Unchanged files are not rescanned or rechunked (see .cortex/data/cache.json); the output is identical to a full build.",
+		RunE: runContextBuild,
 	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().Bool("full", false, "Ignore the incremental cache and rebuild everything")
+	cmd.Flags().Int64("max-bytes", 0, "Maximum total bytes of files in the context (0 = unlimited; overrides config)")
+	cmd.Flags().Int("max-files", 0, "Maximum number of files in the context (0 = unlimited; overrides config)")
+
+	return cmd
 }
 
 // NewContextXrayCommand returns the `cortex context xray` command.
@@ -89,20 +118,26 @@ func NewContextXrayCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("reading --output: %w", err)
 			}
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return fmt.Errorf("finding repo root: %w", err)
+			}
 			if out == "" {
 				// Enforce explicit output per contract
-				repoRoot, err := projectroot.Find(".")
-				if err != nil {
-					return fmt.Errorf("finding repo root: %w", err)
-				}
 				// slug := filepath.Base(repoRoot)
 				// out = filepath.Join(repoRoot, ".cortex", slug, "data")
 				out = filepath.Join(repoRoot, ".cortex", "data")
 			}
+			if out, err = filepath.Abs(out); err != nil {
+				return err
+			}
 
-			// Forward args in the Rust CLI order: scan <target> --output <dir>
-			xrayArgs := []string{target, "--output", out}
-			return runXraySubcommand(c, "scan", xrayArgs)
+			gen := reportindex.Begin(c, args, repoRoot)
+			engine, _ := c.Flags().GetString("engine")
+			if _, err := runScan(c, engine, target, out, nil); err != nil {
+				return err
+			}
+			return gen.Record(repoRoot, reportindex.KindContext, filepath.Join(out, "index.json"))
 		},
 	}
 	scanCmd.Flags().String("output", "", "Output directory for index.json (default: .cortex/data)")
@@ -129,56 +164,169 @@ func NewContextXrayCommand() *cobra.Command {
 
 // NewContextDocsCommand returns the `cortex context docs` command.
 func NewContextDocsCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "docs",
 		Short: "Generate AI-Agent documentation",
-		Long:  "Generates human-readable documentation from AI-Agent outputs (chunks.ndjson, manifest.json, XRAY index.json)",
-		RunE:  runContextDocs,
-		Args:  cobra.NoArgs,
+		Long: "Generates human-readable documentation from AI-Agent outputs (chunks.ndjson, manifest.json, XRAY index.json).\n" +
+			"With --format html the pages are written as a static site under docs/__generated__/site.",
+		RunE: runContextDocs,
+		Args: cobra.NoArgs,
 	}
+	cmd.Flags().String("format", "markdown", "Output format: markdown (docs/__generated__/context) or html (docs/__generated__/site)")
+	return cmd
 }
 
 // runContextBuild executes the context:build npm script.
-func runContextBuild(cmd *cobra.Command, _ []string) error {
+func runContextBuild(cmd *cobra.Command, args []string) error {
 	repoRoot, err := projectroot.Find(".")
 	if err != nil {
 		return fmt.Errorf("finding repo root: %w", err)
 	}
+	gen := reportindex.Begin(cmd, args, repoRoot)
 
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] building AI context...\n")
 
-	// 1. Run XRAY scan (Rust)
-	// slug := filepath.Base(repoRoot)
-	// outputDir := filepath.Join(repoRoot, ".cortex", slug, "data")
-	outputDir := filepath.Join(repoRoot, ".cortex", "data")
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	policy, err := budgetPolicy(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	full, _ := cmd.Flags().GetBool("full")
+	var cache *builder.Cache
+	if !full {
+		cache = builder.LoadCache(repoRoot)
+	}
 
-	// Rust CLI order: scan <target> --output <dir>
-	xrayArgs := []string{".", "--output", outputDir}
+	engine, _ := cmd.Flags().GetString("engine")
+	scannedAt := time.Now()
+	stats, scanned, err := buildContext(cmd, repoRoot, filepath.Join(repoRoot, ".cortex"), engine, cfg, policy, cache)
+	if err != nil {
+		return err
+	}
+	if err := builder.SaveCache(repoRoot, builder.NewCache(scannedAt, scanned)); err != nil {
+		return fmt.Errorf("writing build cache: %w", err)
+	}
+
+	outputs := append([]string{indexFile}, builder.Outputs...)
+	prov, err := provenance(repoRoot, gen.SourceFingerprint, engine, cfg, policy, outputs)
+	if err != nil {
+		return err
+	}
+	if err := builder.WriteProvenance(repoRoot, prov); err != nil {
+		return fmt.Errorf("writing provenance: %w", err)
+	}
+	if err := gen.Record(repoRoot, reportindex.KindContext, append(outputs, builder.ProvenanceFile)...); err != nil {
+		return fmt.Errorf("indexing context: %w", err)
+	}
+	if cache != nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] incremental: %d/%d files reused\n", stats.ReusedFiles, stats.Files)
+	}
+
+	if n := stats.Pruned.Excluded.Files; n > 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] budget: excluded %d files (%d bytes), see .cortex/data/pruned.json\n", n, stats.Pruned.Excluded.Bytes)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] AI context ready → .cortex/\n")
+
+	return nil
+}
 
-	if err := runXraySubcommand(cmd, "scan", xrayArgs); err != nil {
-		return fmt.Errorf("xray scan pre-build failed: %w", err)
+// indexFile is the repo-relative path of the XRAY index a build scans to.
+const indexFile = ".cortex/data/index.json"
+
+// buildContext scans repoRoot with engine into ctxDir/data and builds the
+// context in ctxDir from the scan, reusing cache (nil rebuilds everything).
+// It returns the build statistics and the per-file scan cache.
+func buildContext(cmd *cobra.Command, repoRoot, ctxDir, engine string, cfg *config.Config, policy config.Budget, cache *builder.Cache) (*builder.Stats, map[string]xray.CachedFile, error) {
+	var recency map[string]int64
+	if policy.Recency && (policy.MaxBytes > 0 || policy.MaxFiles > 0) {
+		var err error
+		recency, err = git.LastCommitTimes(cmd.Context(), repoRoot)
+		if err != nil {
+			// Without history, ranking falls back to weights and path.
+			log.FromContext(cmd.Context()).Warn("recency unavailable", "err", err)
+		}
+	}
+
+	// 1. Run XRAY scan
+	outputDir := filepath.Join(ctxDir, "data")
+	scanned, err := runScan(cmd, engine, ".", outputDir, cache)
+	if err != nil {
+		return nil, nil, fmt.Errorf("xray scan pre-build failed: %w", err)
 	}
 
 	// 2. Read XRAY Index
 	indexPath := filepath.Join(outputDir, "index.json")
 	indexData, err := os.ReadFile(indexPath)
 	if err != nil {
-		return fmt.Errorf("failed to read xray index at %s: %w", indexPath, err)
+		return nil, nil, fmt.Errorf("failed to read xray index at %s: %w", indexPath, err)
 	}
 
 	var index xray.Index
 	if err := json.Unmarshal(indexData, &index); err != nil {
-		return fmt.Errorf("unmarshaling xray index: %w", err)
+		return nil, nil, fmt.Errorf("unmarshaling xray index: %w", err)
+	}
+
+	counter, err := tokens.New(repoRoot, cfg.Context.Tokens)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// 3. Build .cortex structure
-	if err := builder.BuildContext(repoRoot, &index); err != nil {
-		return fmt.Errorf("building .cortex: %w", err)
+	stats, err := builder.BuildContextWithOptions(repoRoot, &index, builder.Options{
+		Previous:  cache,
+		Budget:    policy,
+		Recency:   recency,
+		Files:     scanner.Policy{MaxFileBytes: cfg.Files.MaxBytes},
+		Generated: scanner.NewGenerated(repoRoot, cfg.Files.Generated),
+		Dir:       ctxDir,
+		Tokens:    counter,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("building .cortex: %w", err)
 	}
+	return stats, scanned, nil
+}
 
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] AI context ready → .cortex/\n")
+// provenance describes a build of outputs (repo-relative) from the
+// repository with the given fingerprint digest.
+func provenance(repoRoot, fingerprint, engine string, cfg *config.Config, policy config.Budget, outputs []string) (*builder.Provenance, error) {
+	configDigest, err := builder.ConfigDigest(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("digesting config: %w", err)
+	}
+	digests, err := builder.DigestOutputs(repoRoot, outputs)
+	if err != nil {
+		return nil, err
+	}
+	return &builder.Provenance{
+		Fingerprint:   fingerprint,
+		CortexVersion: version.String(),
+		Engine:        engine,
+		ConfigDigest:  configDigest,
+		MaxBytes:      policy.MaxBytes,
+		MaxFiles:      policy.MaxFiles,
+		Outputs:       digests,
+	}, nil
+}
 
-	return nil
+// budgetPolicy returns context.budget from .cortex/config.yaml with the
+// --max-bytes/--max-files flags applied on top.
+func budgetPolicy(cmd *cobra.Command, cfg *config.Config) (config.Budget, error) {
+	policy := cfg.Context.Budget
+	if cmd.Flags().Changed("max-bytes") {
+		policy.MaxBytes, _ = cmd.Flags().GetInt64("max-bytes")
+	}
+	if cmd.Flags().Changed("max-files") {
+		policy.MaxFiles, _ = cmd.Flags().GetInt("max-files")
+	}
+	if policy.MaxBytes < 0 || policy.MaxFiles < 0 {
+		return config.Budget{}, fmt.Errorf("budget limits must not be negative")
+	}
+	return policy, nil
 }
 
 // runContextXray acts as a fallback if no subcommand given?
@@ -187,37 +335,47 @@ func runContextXray(cmd *cobra.Command, args []string) error {
 	return cmd.Help()
 }
 
+// runScan produces outDir/index.json for target using engine.
+// The go engine reuses unchanged entries from cache (nil scans everything) and
+// returns the per-file cache for the next build; the xray engine returns nil.
+func runScan(cmd *cobra.Command, engine, target, outDir string, cache *builder.Cache) (map[string]xray.CachedFile, error) {
+	switch engine {
+	case xray.EngineXray:
+		// Rust CLI order: scan <target> --output <dir>
+		return nil, runXraySubcommand(cmd, "scan", []string{target, "--output", outDir})
+	case xray.EngineGo:
+		repoRoot, err := projectroot.Find(".")
+		if err != nil {
+			return nil, fmt.Errorf("finding repo root: %w", err)
+		}
+		index, files, err := xray.ScanWith(repoRoot, target, cache.ScanOptions())
+		if err != nil {
+			return nil, err
+		}
+		outFile, err := xray.WriteIndex(index, outDir)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "XRAY scan complete. Digest: %s\n", index.Digest)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Written to: %s\n", outFile)
+		return files, nil
+	default:
+		return nil, fmt.Errorf("invalid --engine %q (must be %q or %q)", engine, xray.EngineXray, xray.EngineGo)
+	}
+}
+
 func resolveXrayBin(cmd *cobra.Command) (string, error) {
-	// 1. Flag
 	bin, _ := cmd.Flags().GetString("xray-bin")
 	if bin != "" {
 		return bin, nil
 	}
 
-	// 2. Env
-	if bin = os.Getenv("XRAY_BIN"); bin != "" {
-		return bin, nil
-	}
-
-	// 3. Default (Repo Relative)
-	// We need repo root.
 	repoRoot, err := projectroot.Find(".")
 	if err != nil {
 		return "", fmt.Errorf("finding repo root: %w", err)
 	}
 
-	// Try release first, then debug
-	releasePath := filepath.Join(repoRoot, "rust/target/release/xray")
-	if _, err := os.Stat(releasePath); err == nil {
-		return releasePath, nil
-	}
-
-	debugPath := filepath.Join(repoRoot, "rust/target/debug/xray")
-	if _, err := os.Stat(debugPath); err == nil {
-		return debugPath, nil
-	}
-
-	return "", fmt.Errorf("xray binary not found. Build it with `cargo build` in rust/xray/ or specify --xray-bin")
+	return xray.ResolveBin("", repoRoot)
 }
 
 func runXraySubcommand(cmd *cobra.Command, sub string, args []string) error {
@@ -260,36 +418,46 @@ func runXraySubcommand(cmd *cobra.Command, sub string, args []string) error {
 	return nil
 }
 
-// runContextDocs executes the context:docs generation by invoking XRAY.
-func runContextDocs(cmd *cobra.Command, _ []string) error {
+// runContextDocs renders the context documentation set from the pipeline outputs.
+func runContextDocs(cmd *cobra.Command, args []string) error {
 	repoRoot, err := projectroot.Find(".")
 	if err != nil {
 		return fmt.Errorf("finding repo root: %w", err)
 	}
+	gen := reportindex.Begin(cmd, args, repoRoot)
 
-	// 1. Inputs/Outputs
-	// Standard input: .cortex/data/index.json
-	inPath := filepath.Join(repoRoot, ".cortex", "data", "index.json")
-
-	// Standard output: docs/__generated__/context
+	format, _ := cmd.Flags().GetString("format")
+	generate := contextdocs.Generate
 	outDir := filepath.Join(repoRoot, "docs", "__generated__", "context")
-
-	// Ensure output dir exists (safeguard, though XRAY creates it too)
-	if err := os.MkdirAll(outDir, 0o755); err != nil {
-		return fmt.Errorf("creating output directory: %w", err)
+	pages := contextdocs.Pages
+	switch format {
+	case "markdown":
+	case "html":
+		generate = contextdocs.GenerateSite
+		outDir = filepath.Join(repoRoot, filepath.FromSlash(site.Dir))
+		pages = contextdocs.SitePages()
+	default:
+		return fmt.Errorf("invalid --format %q (must be markdown or html)", format)
 	}
 
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] invoking xray docs...\n")
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  input:  %s\n", inPath)
+	// Inputs: .cortex/data/index.json (+ .cortex/data/{manifest.json,chunks.ndjson})
+	paths := contextdocs.DefaultPaths(repoRoot)
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] generating context docs...\n")
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  input:  %s\n", paths.Index)
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  output: %s\n", outDir)
 
-	// 2. Call XRAY docs
-	// xray docs --input <in> --output <out>
-	xrayArgs := []string{"--input", inPath, "--output", outDir}
-	if err := runXraySubcommand(cmd, "docs", xrayArgs); err != nil {
-		return fmt.Errorf("xray docs failed: %w", err)
+	if err := generate(paths, outDir); err != nil {
+		return fmt.Errorf("generating context docs: %w", err)
+	}
+	written := make([]string, len(pages))
+	for i, page := range pages {
+		written[i] = filepath.Join(outDir, page)
+	}
+	if err := gen.Record(repoRoot, reportindex.KindContext, written...); err != nil {
+		return fmt.Errorf("indexing context docs: %w", err)
 	}
 
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] \u2713 docs generated via XRAY\n")
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] \u2713 docs generated\n")
 	return nil
 }
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/agents"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextAgentsCommand returns the `cortex context agents` command.
+func NewContextAgentsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agents",
+		Short: "Generate the agent instruction file (AGENTS.md)",
+		Long: "Generates AGENTS.md, CLAUDE.md or a Cursor rule from the feature registry, spec index, " +
+			"skills registry and governance rules. Output is deterministic; use --check in CI to keep it current.",
+		Args: cobra.NoArgs,
+		RunE: runContextAgents,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().Bool("check", false, "Fail if the file is missing or out of date instead of writing it")
+	cmd.Flags().String("format", agents.FormatAgents, "Output format: "+strings.Join(agents.Formats, ", "))
+	cmd.Flags().String("output", "", "Output path relative to the repo root, or - for stdout (default depends on --format)")
+
+	return cmd
+}
+
+func runContextAgents(cmd *cobra.Command, args []string) error {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+	gen := reportindex.Begin(cmd, args, repoRoot)
+
+	check, _ := cmd.Flags().GetBool("check")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	if output == "" {
+		if output, err = agents.DefaultPath(format); err != nil {
+			return err
+		}
+	}
+
+	in, err := agents.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	content, err := agents.Render(in, format)
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), content)
+		return err
+	}
+
+	path := filepath.Join(repoRoot, output)
+	if check {
+		existing, err := os.ReadFile(path)
+		if err != nil || string(existing) != content {
+			return fmt.Errorf("%s is out of date; run `cortex context agents --format %s`", output, format)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] %s is up to date\n", output)
+		return nil
+	}
+
+	if err := projection.AtomicWrite(path, []byte(content)); err != nil {
+		return err
+	}
+	if err := gen.Record(repoRoot, reportindex.KindContext, path); err != nil {
+		return fmt.Errorf("indexing %s: %w", output, err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] ✓ wrote %s\n", output)
+	return nil
+}
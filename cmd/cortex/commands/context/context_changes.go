@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/contextdelta"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/snapshot"
+	"github.com/bartekus/cortex/internal/symbols"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextChangesCommand returns the `cortex context changes` command.
+func NewContextChangesCommand() *cobra.Command {
+	var (
+		since  string
+		output string
+		mcpBin string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "changes",
+		Short: "Print the context delta since a git revision or a snapshot",
+		Long: "Compares the context in .cortex/data with the files of a git revision or, for a sha256: ID, a snapshot, " +
+			"and prints the delta as JSON: changed files, chunks to add or update, chunk IDs to drop and changed Go declarations. " +
+			"Run `cortex context build` first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			gen := reportindex.Begin(cmd, args, repoRoot)
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context changes", err)
+			}
+
+			dataDir := filepath.Join(repoRoot, ".cortex", "data")
+			current, err := chunker.Read(dataDir)
+			if errors.Is(err, fs.ErrNotExist) {
+				return clierr.New(clierr.ExitConfig, "context changes: no context; run `cortex context build` first")
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context changes", err)
+			}
+			var graph *symbols.Graph
+			if data, err := os.ReadFile(filepath.Join(dataDir, "symbols.json")); err == nil {
+				graph = &symbols.Graph{}
+				if err := json.Unmarshal(data, graph); err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context changes: parsing symbols.json", err)
+				}
+			}
+			digest, err := os.ReadFile(filepath.Join(repoRoot, ".cortex", "digest.txt"))
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context changes", err)
+			}
+
+			var (
+				base     contextdelta.Baseline
+				resolved string
+			)
+			if strings.HasPrefix(since, "sha256:") {
+				bin, err := mcp.ResolveBin(mcpBin, repoRoot)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context changes", err)
+				}
+				client, err := mcp.Start(cmd.Context(), bin, repoRoot, cmd.ErrOrStderr())
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "context changes", err)
+				}
+				defer func() { _ = client.Close() }()
+				base = contextdelta.Snapshot{Store: &snapshot.MCPStore{Client: client, RepoRoot: repoRoot}, ID: since}
+				resolved = since
+			} else {
+				if resolved, err = git.RevParse(cmd.Context(), repoRoot, since); err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context changes", err)
+				}
+				base = contextdelta.Revision{Root: repoRoot, Rev: resolved}
+			}
+
+			delta, err := contextdelta.Compute(cmd.Context(), base, current, graph, scanner.Policy{MaxFileBytes: cfg.Files.MaxBytes})
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "context changes", err)
+			}
+			delta.Since, delta.Base = since, resolved
+			delta.ContextDigest = strings.TrimSpace(string(digest))
+
+			data, err := json.MarshalIndent(delta, "", "  ")
+			if err != nil {
+				return err
+			}
+			data = append(data, '\n')
+			if output == "" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if output, err = filepath.Abs(output); err != nil {
+				return err
+			}
+			if err := projection.AtomicWrite(output, data); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "context changes", err)
+			}
+			if err := gen.Record(repoRoot, reportindex.KindContext, output); err != nil {
+				return fmt.Errorf("indexing context changes: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%d files, %d changed and %d removed chunks → %s\n",
+				len(delta.Files), len(delta.ChangedChunks), len(delta.RemovedChunks), output)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&mcpBin, "mcp-bin", "", "Path to the cortex-mcp binary (default: $CORTEX_MCP_BIN, then rust/target)")
+	cmd.Flags().StringVar(&output, "output", "", "Write the delta to this file instead of stdout")
+	cmd.Flags().StringVar(&since, "since", "", "Baseline: a git revision, or a snapshot ID (sha256:...)")
+	_ = cmd.MarkFlagRequired("since")
+
+	return cmd
+}
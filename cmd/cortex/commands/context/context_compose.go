@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/compose"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+	"github.com/bartekus/cortex/internal/symbols"
+	"github.com/bartekus/cortex/internal/tokens"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextComposeCommand returns the `cortex context compose` command.
+func NewContextComposeCommand() *cobra.Command {
+	var (
+		opts   compose.Options
+		asJSON bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compose",
+		Short: "Assemble a prompt bundle for a task",
+		Long: "Assembles a ready-to-paste prompt bundle from the built context: the governance rules, the specs of the " +
+			"selected features and the symbols and chunks of the files selected by --feature header or --path glob, " +
+			"admitted in a fixed order until the token budget is spent. Run `cortex context build` first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			gen := reportindex.Begin(cmd, args, repoRoot)
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context compose", err)
+			}
+			counter, err := tokens.New(repoRoot, cfg.Context.Tokens)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context compose", err)
+			}
+			if !cmd.Flags().Changed("budget") {
+				opts.Budget = smallestWindow(cfg.Context.Tokens.Models)
+			}
+
+			dataDir := filepath.Join(repoRoot, ".cortex", "data")
+			res, err := chunker.Read(dataDir)
+			if errors.Is(err, fs.ErrNotExist) {
+				return clierr.New(clierr.ExitConfig, "context compose: no context; run `cortex context build` first")
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context compose", err)
+			}
+			var graph *symbols.Graph
+			if data, err := os.ReadFile(filepath.Join(dataDir, "symbols.json")); err == nil {
+				graph = &symbols.Graph{}
+				if err := json.Unmarshal(data, graph); err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context compose: parsing symbols.json", err)
+				}
+			}
+
+			bundle, err := compose.Compose(repoRoot, res, graph, counter, opts)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context compose", err)
+			}
+
+			var data []byte
+			if asJSON {
+				if data, err = json.MarshalIndent(bundle, "", "  "); err != nil {
+					return err
+				}
+				data = append(data, '\n')
+			} else {
+				data = []byte(compose.Render(bundle))
+			}
+			if output == "" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if output, err = filepath.Abs(output); err != nil {
+				return err
+			}
+			if err := projection.AtomicWrite(output, data); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "context compose", err)
+			}
+			if err := gen.Record(repoRoot, reportindex.KindContext, output); err != nil {
+				return fmt.Errorf("indexing context compose: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%d files, %d tokens (%d items omitted) → %s\n",
+				len(bundle.Files), bundle.Tokens, len(bundle.Omitted), output)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().IntVar(&opts.Budget, "budget", 0, "Token budget (default: the smallest window of context.tokens.models; 0: no limit)")
+	cmd.Flags().StringArrayVar(&opts.Features, "feature", nil, "Select the spec and the files of this feature ID (repeatable)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the bundle as JSON (for MCP clients)")
+	cmd.Flags().StringVar(&output, "output", "", "Write the bundle to this file instead of stdout")
+	cmd.Flags().StringArrayVar(&opts.Paths, "path", nil, "Select the files matching this glob (repeatable)")
+	cmd.Flags().StringVar(&opts.Task, "task", "", "Task description heading the bundle")
+
+	return cmd
+}
+
+// smallestWindow returns the smallest model window, or 0 without models.
+func smallestWindow(models map[string]int) int {
+	n := 0
+	for _, window := range models {
+		if n == 0 || window < n {
+			n = window
+		}
+	}
+	return n
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/embed"
+	"github.com/bartekus/cortex/internal/log"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextEmbedCommand returns the `cortex context embed` command.
+func NewContextEmbedCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "embed",
+		Short: "Generate embeddings for context chunks",
+		Long: "Embeds .cortex/data/chunks.ndjson with the selected provider and writes the vector index to .cortex/index.\n" +
+			"Vectors of unchanged chunks are reused. The API key is read from $CORTEX_EMBED_API_KEY or $OPENAI_API_KEY.",
+		Args: cobra.NoArgs,
+		RunE: runContextEmbed,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("base-url", "", "Provider base URL (default: provider-specific)")
+	cmd.Flags().Int("batch-size", embed.DefaultBatchSize, "Chunks per provider request")
+	cmd.Flags().String("model", "", "Embedding model (default: provider-specific)")
+	cmd.Flags().String("provider", embed.ProviderOpenAI, "Embedding provider: openai (OpenAI-compatible HTTP) or ollama")
+
+	return cmd
+}
+
+// NewContextSearchCommand returns the `cortex context search` command.
+func NewContextSearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Semantic search over context chunks",
+		Long:  "Embeds the query with the provider and model recorded in .cortex/index and returns the most similar chunks.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runContextSearch,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("base-url", "", "Provider base URL (default: provider-specific)")
+	cmd.Flags().String("format", "text", "Output format: text or json")
+	cmd.Flags().Int("top-k", 10, "Number of results to return")
+
+	return cmd
+}
+
+func runContextEmbed(cmd *cobra.Command, args []string) error {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+	gen := reportindex.Begin(cmd, args, repoRoot)
+
+	providerName, _ := cmd.Flags().GetString("provider")
+	model, _ := cmd.Flags().GetString("model")
+	baseURL, _ := cmd.Flags().GetString("base-url")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+	provider, err := embed.NewProvider(embed.Config{
+		Provider: providerName,
+		Model:    model,
+		BaseURL:  baseURL,
+		APIKey:   embedAPIKey(),
+	})
+	if err != nil {
+		return err
+	}
+
+	chunksPath := filepath.Join(repoRoot, ".cortex", "data", "chunks.ndjson")
+	chunks, err := chunker.ReadChunks(chunksPath)
+	if err != nil {
+		return fmt.Errorf("reading chunks (run `cortex context build` first): %w", err)
+	}
+
+	indexDir := filepath.Join(repoRoot, ".cortex", "index")
+	previous, err := embed.Load(indexDir)
+	if err != nil && !os.IsNotExist(err) {
+		log.FromContext(cmd.Context()).Warn("ignoring unreadable vector index", "err", err)
+	}
+
+	ix, stats, err := embed.Build(cmd.Context(), provider, chunks, previous, batchSize)
+	if err != nil {
+		return err
+	}
+	if err := ix.Write(indexDir); err != nil {
+		return fmt.Errorf("writing vector index: %w", err)
+	}
+	if err := gen.Record(repoRoot, reportindex.KindContext,
+		filepath.Join(indexDir, embed.MetaFile), filepath.Join(indexDir, embed.VectorsFile)); err != nil {
+		return fmt.Errorf("indexing vector index: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[cortex] embedded %d chunks (%d reused) with %s/%s → .cortex/index/\n",
+		stats.Embedded, stats.Reused, ix.Meta.Provider, ix.Meta.Model)
+	return nil
+}
+
+func runContextSearch(cmd *cobra.Command, args []string) error {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+
+	baseURL, _ := cmd.Flags().GetString("base-url")
+	format, _ := cmd.Flags().GetString("format")
+	topK, _ := cmd.Flags().GetInt("top-k")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", format)
+	}
+
+	ix, err := embed.Load(filepath.Join(repoRoot, ".cortex", "index"))
+	if err != nil {
+		return fmt.Errorf("loading vector index (run `cortex context embed` first): %w", err)
+	}
+
+	// The query must be embedded with the same model as the index.
+	provider, err := embed.NewProvider(embed.Config{
+		Provider: ix.Meta.Provider,
+		Model:    ix.Meta.Model,
+		BaseURL:  baseURL,
+		APIKey:   embedAPIKey(),
+	})
+	if err != nil {
+		return err
+	}
+
+	vectors, err := provider.Embed(cmd.Context(), []string{args[0]})
+	if err != nil {
+		return fmt.Errorf("embedding query: %w", err)
+	}
+	hits, err := ix.Search(vectors[0], topK)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(hits, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return err
+	}
+
+	for _, h := range hits {
+		loc := fmt.Sprintf("%s:%d-%d", h.FilePath, h.StartLine, h.EndLine)
+		if h.Symbol != "" {
+			loc += " (" + h.Symbol + ")"
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%.4f  %s\n", h.Score, loc)
+	}
+	return nil
+}
+
+func embedAPIKey() string {
+	if key := os.Getenv("CORTEX_EMBED_API_KEY"); key != "" {
+		return key
+	}
+	return os.Getenv("OPENAI_API_KEY")
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/depgraph"
+	"github.com/bartekus/cortex/internal/projectroot"
+
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextGraphCommand returns the `cortex context graph` command.
+func NewContextGraphCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Print the Go package dependency graph",
+		Long:  "Prints the package import graph from .cortex/data/dependencies.json as edges, or as Graphviz DOT with --dot.",
+		Args:  cobra.NoArgs,
+		RunE:  runContextGraph,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().Bool("dot", false, "Emit Graphviz DOT instead of plain edges")
+	cmd.Flags().Bool("external", false, "Include stdlib and external module imports")
+	cmd.Flags().Bool("tests", false, "Include imports made only by _test.go files")
+
+	return cmd
+}
+
+func runContextGraph(cmd *cobra.Command, _ []string) error {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+
+	dot, _ := cmd.Flags().GetBool("dot")
+	external, _ := cmd.Flags().GetBool("external")
+	tests, _ := cmd.Flags().GetBool("tests")
+
+	data, err := os.ReadFile(filepath.Join(repoRoot, ".cortex", "data", "dependencies.json"))
+	if err != nil {
+		return fmt.Errorf("reading dependency graph (run `cortex context build` first): %w", err)
+	}
+	var g depgraph.Graph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return fmt.Errorf("parsing dependency graph: %w", err)
+	}
+
+	opts := depgraph.DOTOptions{External: external, Tests: tests}
+	if dot {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), g.DOT(opts))
+		return err
+	}
+
+	for _, e := range g.Edges {
+		if (e.Test && !opts.Tests) || (e.Kind != depgraph.KindInternal && !opts.External) {
+			continue
+		}
+		suffix := ""
+		if e.Kind != depgraph.KindInternal {
+			suffix = " (" + e.Kind + ")"
+		}
+		if e.Test {
+			suffix += " [test]"
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s -> %s%s\n", e.From, e.To, suffix)
+	}
+	return nil
+}
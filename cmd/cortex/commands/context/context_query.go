@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/embed"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/retrieve"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextQueryCommand returns the `cortex context query` command.
+func NewContextQueryCommand() *cobra.Command {
+	var (
+		baseURL  string
+		format   string
+		semantic bool
+		topK     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "query <question>",
+		Short: "Rank context chunks for a question",
+		Long: "Ranks the chunks of .cortex/data against a question by BM25 over their content, path and symbol and " +
+			"prints them with file and line provenance. No model is called; with --semantic the ranking is fused with " +
+			"a search of the vector index of `cortex context embed`, which embeds the question.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return clierr.Newf(clierr.ExitConfig, "invalid format: %s (must be 'text' or 'json')", format)
+			}
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			chunks, err := chunker.ReadChunks(filepath.Join(repoRoot, ".cortex", "data", "chunks.ndjson"))
+			if errors.Is(err, fs.ErrNotExist) {
+				return clierr.New(clierr.ExitConfig, "context query: no context; run `cortex context build` first")
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context query", err)
+			}
+
+			ix := retrieve.NewIndex(chunks)
+			var hits []retrieve.Hit
+			if semantic {
+				vectors, err := embed.Load(filepath.Join(repoRoot, ".cortex", "index"))
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context query: loading vector index (run `cortex context embed` first)", err)
+				}
+				provider, err := embed.NewProvider(embed.Config{
+					Provider: vectors.Meta.Provider,
+					Model:    vectors.Meta.Model,
+					BaseURL:  baseURL,
+					APIKey:   embedAPIKey(),
+				})
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context query", err)
+				}
+				query, err := provider.Embed(cmd.Context(), []string{args[0]})
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "context query: embedding question", err)
+				}
+				similar, err := vectors.Search(query[0], 0)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context query", err)
+				}
+				ids := make([]string, len(similar))
+				for i, h := range similar {
+					ids[i] = h.ID
+				}
+				hits = ix.Fuse(args[0], topK, retrieve.IDs(ix.Query(args[0], 0)), ids)
+			} else {
+				hits = ix.Query(args[0], topK)
+			}
+
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				if hits == nil {
+					hits = []retrieve.Hit{}
+				}
+				data, err := json.MarshalIndent(hits, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshaling JSON: %w", err)
+				}
+				_, err = fmt.Fprintln(out, string(data))
+				return err
+			}
+			if len(hits) == 0 {
+				_, _ = fmt.Fprintln(out, "no matching chunks")
+				return nil
+			}
+			for _, h := range hits {
+				loc := fmt.Sprintf("%s:%d-%d", h.FilePath, h.StartLine, h.EndLine)
+				if h.Symbol != "" {
+					loc += " (" + h.Symbol + ")"
+				}
+				_, _ = fmt.Fprintf(out, "%.4f  %s\n", h.Score, loc)
+				if h.Line > 0 {
+					_, _ = fmt.Fprintf(out, "        %d: %s\n", h.Line, h.Preview)
+				}
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "Embedding provider base URL with --semantic (default: provider-specific)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.Flags().BoolVar(&semantic, "semantic", false, "Fuse the ranking with a search of the vector index (embeds the question)")
+	cmd.Flags().IntVar(&topK, "top-k", 10, "Number of chunks to return (0: all)")
+
+	return cmd
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/cmd/cortex/internal/llmprovider"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/summarize"
+	"github.com/bartekus/cortex/pkg/llm"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextSummarizeCommand returns the `cortex context summarize` command.
+func NewContextSummarizeCommand() *cobra.Command {
+	var opts summarize.Options
+
+	cmd := &cobra.Command{
+		Use:   "summarize",
+		Short: "Summarize files and directories with the configured LLM",
+		Long: "Asks the LLM of .cortex/config.yaml for a short summary of every file of the built context and of every " +
+			"directory above them, and writes them to " + summarize.Dir + "/. Summaries are cached by content hash: " +
+			"only new or changed files, and the directories above them, are summarized again. The output is not " +
+			"deterministic and is kept apart from every deterministic artifact. Run `cortex context build` first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			res, err := chunker.Read(filepath.Join(repoRoot, ".cortex", "data"))
+			if errors.Is(err, fs.ErrNotExist) {
+				return clierr.New(clierr.ExitConfig, "context summarize: no context; run `cortex context build` first")
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context summarize", err)
+			}
+			var paths []string
+			for _, e := range res.Manifest {
+				if e.Skipped == "" {
+					paths = append(paths, e.Path)
+				}
+			}
+			previous, err := summarize.ReadOrNil(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context summarize", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.DryRun {
+				// The model is never called, so none needs to be configured,
+				// and cached summaries of any model count.
+				_, stats, err := summarize.Run(cmd.Context(), repoRoot, paths, previous, nil, opts)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "context summarize", err)
+				}
+				_, _ = fmt.Fprintf(out, "%d files and %d directories: %d to summarize, %d cached\n",
+					stats.Files, stats.Dirs, stats.Summarized, stats.Reused)
+				return nil
+			}
+
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context summarize", err)
+			}
+			provider, err := llmprovider.New(cfg.LLM)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context summarize", err)
+			}
+			opts.Model = provider.Name() + "/" + provider.Model()
+			complete := func(ctx context.Context, system, prompt string) (string, error) {
+				resp, err := provider.Complete(ctx, llm.Request{
+					System:    system,
+					Messages:  []llm.Message{{Role: llm.RoleUser, Content: prompt}},
+					MaxTokens: 200,
+				})
+				if err != nil {
+					return "", err
+				}
+				return resp.Text, nil
+			}
+
+			s, stats, runErr := summarize.Run(cmd.Context(), repoRoot, paths, previous, complete, opts)
+			// Keep what was summarized before a failure, so a rerun resumes.
+			if s != nil {
+				if err := summarize.Write(repoRoot, s); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "context summarize", err)
+				}
+			}
+			if runErr != nil {
+				return clierr.Wrap(clierr.ExitExecution, "context summarize", runErr)
+			}
+			_, _ = fmt.Fprintf(out, "%d files and %d directories: %d summarized with %s, %d cached → %s/\n",
+				stats.Files, stats.Dirs, stats.Summarized, opts.Model, stats.Reused, summarize.Dir)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Count what would be summarized without calling the LLM")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Summarize everything again, ignoring the cache")
+
+	return cmd
+}
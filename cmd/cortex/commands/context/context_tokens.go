@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/tokens"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextTokensCommand returns the `cortex context tokens` command.
+func NewContextTokensCommand() *cobra.Command {
+	var (
+		by     string
+		check  bool
+		asJSON bool
+		top    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "Count the tokens of the built context",
+		Long: "Counts the tokens of the chunks in .cortex/data per directory, file or chunk and checks the total " +
+			"against the model context windows of context.tokens.models in .cortex/config.yaml. " +
+			"Counts are exact with context.tokens.encoding set to a tiktoken file, estimated otherwise.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context tokens", err)
+			}
+			counter, err := tokens.New(repoRoot, cfg.Context.Tokens)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context tokens", err)
+			}
+			res, err := chunker.Read(filepath.Join(repoRoot, ".cortex", "data"))
+			if errors.Is(err, fs.ErrNotExist) {
+				return clierr.New(clierr.ExitConfig, "context tokens: no context; run `cortex context build` first")
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context tokens", err)
+			}
+			report, err := tokens.Measure(counter, res, by, cfg.Context.Tokens.Models)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "context tokens", err)
+			}
+			if top > 0 && len(report.Usage) > top {
+				report.Usage = report.Usage[:top]
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(out, "%d tokens (%s) in %d chunks of %d files\n\n", report.Tokens, report.Tokenizer, report.Chunks, report.Files)
+				_, _ = fmt.Fprintf(out, "%8s  %6s  %5s  %s\n", "TOKENS", "CHUNKS", "FILES", "PATH")
+				for _, u := range report.Usage {
+					_, _ = fmt.Fprintf(out, "%8d  %6d  %5d  %s\n", u.Tokens, u.Chunks, u.Files, u.Path)
+				}
+				if len(report.Budgets) > 0 {
+					_, _ = fmt.Fprintln(out)
+				}
+				for _, b := range report.Budgets {
+					mark := "✓"
+					if !b.Fits {
+						mark = "✗"
+					}
+					_, _ = fmt.Fprintf(out, "%s %s: %d of %d tokens (%d%%)\n", mark, b.Model, report.Tokens, b.Window, report.Tokens*100/b.Window)
+				}
+			}
+
+			if check {
+				for _, b := range report.Budgets {
+					if !b.Fits {
+						return clierr.Newf(clierr.ExitValidation, "context tokens: %d tokens exceed the %d-token window of %s", report.Tokens, b.Window, b.Model)
+					}
+				}
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&by, "by", tokens.ByDir, "Break the counts down by dir, file or chunk")
+	cmd.Flags().BoolVar(&check, "check", false, "Exit 1 when the context exceeds a model's context window")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the report as JSON")
+	cmd.Flags().IntVar(&top, "top", 0, "Only list the N largest entries (0: all)")
+
+	return cmd
+}
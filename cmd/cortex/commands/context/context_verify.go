@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/builder"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/fingerprint"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/version"
+)
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+// NewContextVerifyCommand returns the `cortex context verify` command.
+func NewContextVerifyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Check that the context build is reproducible",
+		Long: "Rebuilds the context in a temporary directory from the inputs recorded in .cortex/provenance.json " +
+			"and checks that every output is byte-identical to the attested one and to the file in .cortex/. " +
+			"Inputs that changed since the build are reported as notes. Exits 1 when an output differs.",
+		Args: cobra.NoArgs,
+		RunE: runContextVerify,
+	}
+}
+
+func runContextVerify(cmd *cobra.Command, _ []string) error {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+	prov, err := builder.ReadProvenance(repoRoot)
+	if errors.Is(err, fs.ErrNotExist) {
+		return clierr.Newf(clierr.ExitConfig, "context verify: no %s; run `cortex context build` first", builder.ProvenanceFile)
+	}
+	if err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "context verify", err)
+	}
+
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		return err
+	}
+	// Changed inputs do not fail verification by themselves (a first build
+	// changes the fingerprint by adding .cortex/), but they explain a
+	// differing rebuild.
+	var notes, problems []string
+	if prov.Fingerprint != "" {
+		if fp, err := fingerprint.Compute(cmd.Context(), repoRoot); err == nil && fp.Digest() != prov.Fingerprint {
+			notes = append(notes, "fingerprint: the repository changed since the build")
+		}
+	}
+	if v := version.String(); v != prov.CortexVersion {
+		notes = append(notes, fmt.Sprintf("cortex_version: built by %s, verifying with %s", prov.CortexVersion, v))
+	}
+	if digest, err := builder.ConfigDigest(cfg); err != nil {
+		return fmt.Errorf("digesting config: %w", err)
+	} else if digest != prov.ConfigDigest {
+		notes = append(notes, "config_digest: the configuration changed since the build")
+	}
+
+	tmp, err := os.MkdirTemp("", "cortex-verify-*")
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "context verify", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	// The rebuild mirrors the repository layout so output paths compare
+	// directly; it starts without a cache so incremental reuse is checked too.
+	policy := cfg.Context.Budget
+	policy.MaxBytes, policy.MaxFiles = prov.MaxBytes, prov.MaxFiles
+	if _, _, err := buildContext(cmd, repoRoot, filepath.Join(tmp, ".cortex"), prov.Engine, cfg, policy, nil); err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "context verify: rebuilding", err)
+	}
+
+	paths := make([]string, len(prov.Outputs))
+	for i, o := range prov.Outputs {
+		paths[i] = o.Path
+	}
+	rebuilt, err := builder.DigestOutputs(tmp, paths)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "context verify: rebuilding", err)
+	}
+	for _, p := range prov.Mismatches(rebuilt) {
+		problems = append(problems, p+": the rebuild differs")
+	}
+	for _, p := range prov.Mismatches(onDisk(repoRoot, paths)) {
+		problems = append(problems, p+": modified since the build")
+	}
+
+	out := cmd.OutOrStdout()
+	for _, n := range notes {
+		_, _ = fmt.Fprintf(out, "! %s\n", n)
+	}
+	for _, p := range problems {
+		_, _ = fmt.Fprintf(out, "✗ %s\n", p)
+	}
+	if len(problems) > 0 {
+		return clierr.Newf(clierr.ExitValidation, "context verify: %d problem(s)", len(problems))
+	}
+	_, _ = fmt.Fprintf(out, "✓ %d outputs reproduced byte for byte\n", len(prov.Outputs))
+	return nil
+}
+
+// onDisk digests the outputs at paths in repoRoot; missing files are left
+// out.
+func onDisk(repoRoot string, paths []string) []builder.Output {
+	var outputs []builder.Output
+	for _, p := range paths {
+		if o, err := builder.DigestOutputs(repoRoot, []string{p}); err == nil {
+			outputs = append(outputs, o...)
+		}
+	}
+	return outputs
+}
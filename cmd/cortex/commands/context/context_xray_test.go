@@ -150,13 +150,13 @@ func TestIntegrationContextBuild(t *testing.T) {
 	// 5. Assertions
 	ctxDir := filepath.Join(root, ".cortex")
 	assertExists(t, filepath.Join(ctxDir, "meta.json"))
-	assertExists(t, filepath.Join(ctxDir, "files", "manifest.json"))
-	assertExists(t, filepath.Join(ctxDir, "files", "chunks.ndjson")) // Checks Phase 4B
+	assertExists(t, filepath.Join(ctxDir, "data", "manifest.json"))
+	assertExists(t, filepath.Join(ctxDir, "data", "chunks.ndjson")) // Checks Phase 4B
 	assertExists(t, filepath.Join(ctxDir, "digest.txt"))
 
 	// Check manifest sorted
 	// (README.md, main.go)
-	mBytes, _ := os.ReadFile(filepath.Join(ctxDir, "files", "manifest.json"))
+	mBytes, _ := os.ReadFile(filepath.Join(ctxDir, "data", "manifest.json"))
 	if !strings.Contains(string(mBytes), "main.go") {
 		t.Errorf("Manifest missing main.go")
 	}
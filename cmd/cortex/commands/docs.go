@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/docs"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_DOCS
+// Spec: spec/cli/docs.md
+
+// NewDocsCommand returns the `cortex docs` command.
+func NewDocsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate reference documentation",
+	}
+	cmd.AddCommand(newDocsCLICommand())
+	return cmd
+}
+
+func newDocsCLICommand() *cobra.Command {
+	var (
+		out      string
+		specRoot string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cli",
+		Short: "Render the CLI reference as markdown",
+		Long: "Renders a markdown page for every command from the command tree: usage, flags with their defaults, " +
+			"subcommands, and the exit codes declared in the frontmatter of the command's spec. Pages no longer " +
+			"generated are removed from --out.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			outDir := out
+			if !filepath.IsAbs(outDir) {
+				outDir = filepath.Join(repoRoot, outDir)
+			}
+			if !filepath.IsAbs(specRoot) {
+				specRoot = filepath.Join(repoRoot, specRoot)
+			}
+
+			if err := docs.GenerateCLIReference(cmd.Root(), specRoot, clierr.Descriptions(), outDir); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "docs cli", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote the CLI reference to %s\n", out)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&out, "out", "docs/__generated__/cli", "Output directory")
+	cmd.Flags().StringVar(&specRoot, "spec-root", "spec", "Root directory containing spec files")
+
+	return cmd
+}
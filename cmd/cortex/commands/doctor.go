@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/doctor"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_DOCTOR
+// Spec: spec/cli/doctor.md
+
+// NewDoctorCommand returns the `cortex doctor` command.
+func NewDoctorCommand() *cobra.Command {
+	var (
+		asJSON       bool
+		featuresPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the toolchain and repository health",
+		Long: `Checks the tools skills run (git and go are required; gofumpt, golangci-lint, docker and
+xray are optional) and their versions against the minimums of tools.versions in
+.cortex/config.yaml, then that spec/ exists, the feature registry is a valid DAG and the
+config validates. Every problem comes with a remediation step. Exits 1 when a required
+check fails; missing optional tools are warnings.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			// A broken config is reported as a check; the tool minimums
+			// are then skipped.
+			cfg, _ := config.Load(repoRoot)
+
+			report := doctor.Run(cmd.Context(), execProber{}, doctor.Options{
+				RepoRoot:     repoRoot,
+				FeaturesPath: featuresPath,
+				Config:       cfg,
+			})
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				for _, c := range report.Checks {
+					mark := map[string]string{doctor.StatusOK: "✓", doctor.StatusWarn: "!", doctor.StatusFail: "✗"}[c.Status]
+					line := fmt.Sprintf("%s %-14s %s", mark, c.Name, c.Message)
+					if c.Version != "" {
+						line += " (" + c.Version + ")"
+					}
+					_, _ = fmt.Fprintln(out, line)
+					if c.Remediation != "" {
+						_, _ = fmt.Fprintf(out, "  → %s\n", c.Remediation)
+					}
+				}
+				_, _ = fmt.Fprintf(out, "%d check(s): %d failed, %d warning(s)\n", len(report.Checks), report.Failed, report.Warnings)
+			}
+
+			if report.Failed > 0 {
+				return clierr.Newf(clierr.ExitValidation, "doctor: %d check(s) failed", report.Failed)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml, relative to the repository root")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the report as JSON")
+
+	return cmd
+}
+
+// execProber runs tools with os/exec.
+type execProber struct{}
+
+func (execProber) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+func (execProber) Output(ctx context.Context, path string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, path, args...).CombinedOutput() //nolint:gosec // G204: path is a resolved tool
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/rules"
+)
+
+// Feature: CLI_COMMAND_EXPLAIN
+// Spec: spec/cli/explain.md
+
+// explanation is the --json output of `cortex explain`.
+type explanation struct {
+	// Finding is nil for a rule ID or a finding not in the last run.
+	Finding *explainedFinding `json:"finding,omitempty"`
+	Rule    rules.Rule        `json:"rule"`
+}
+
+type explainedFinding struct {
+	ID      string `json:"id"`
+	Skill   string `json:"skill"`
+	Path    string `json:"path,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewExplainCommand returns the `cortex explain` command.
+func NewExplainCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "explain <finding-id|rule>",
+		Short: "Explain the rule behind a finding",
+		Long: "Prints why the rule behind a finding exists, the spec section it enforces, examples of compliant code " +
+			"and how to fix a violation. Finding IDs (<rule>#<hash>) are listed by `cortex run report`; a finding of " +
+			"the last run is printed with its location. A rule or skill ID explains the rule alone.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _, isFinding := strings.Cut(args[0], "#")
+			rule, ok := rules.Lookup(id)
+			if !ok {
+				return clierr.Newf(clierr.ExitConfig, "explain: no rule for %q", id)
+			}
+			out := explanation{Rule: rule}
+
+			var match *findings.Finding
+			if isFinding {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				_, _, fs, err := readLastRunFindings(cmd.Context(), wd)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "explain: reading the last run", err)
+				}
+				for _, f := range fs {
+					if f.ID() == args[0] {
+						match = &f
+						out.Finding = &explainedFinding{ID: f.ID(), Skill: f.Skill, Path: f.Path, Line: f.Line, Message: f.Message}
+						break
+					}
+				}
+			}
+
+			w := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(w)
+				enc.SetEscapeHTML(false)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+			if isFinding {
+				if match != nil {
+					_, _ = fmt.Fprintf(w, "Finding %s\n  %s\n\n", args[0], describeFinding(*match))
+				} else {
+					_, _ = fmt.Fprintf(w, "Finding %s (not in the last run)\n\n", args[0])
+				}
+			}
+			printRule(w, rule)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the finding and rule as JSON")
+
+	return cmd
+}
+
+// describeFinding returns "path:line: message", without the parts a
+// finding lacks.
+func describeFinding(f findings.Finding) string {
+	switch {
+	case f.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", f.Path, f.Line, f.Message)
+	case f.Path != "":
+		return f.Path + ": " + f.Message
+	default:
+		return f.Message
+	}
+}
+
+func printRule(w io.Writer, r rules.Rule) {
+	fixable := "no"
+	if r.Fixable {
+		fixable = "yes"
+	}
+	_, _ = fmt.Fprintf(w, "%s: %s\nSkill: %s; severity: %s; fixable: %s\n\n%s\n\nSpec: %s, section %q\n",
+		r.ID, r.Description, r.Skill, r.Severity, fixable, r.Rationale, r.Spec, r.Section)
+	if len(r.Examples) > 0 {
+		_, _ = fmt.Fprintln(w, "\nCompliant:")
+		for i, ex := range r.Examples {
+			if i > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+			for _, line := range strings.Split(ex, "\n") {
+				_, _ = fmt.Fprintln(w, strings.TrimRight("    "+line, " "))
+			}
+		}
+	}
+	if r.Fix != "" {
+		_, _ = fmt.Fprintf(w, "\nFix: %s\n", r.Fix)
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package commands
+
+// Feature: CLI_COMMAND_EXPLAIN
+// Spec: spec/cli/explain.md
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/rules"
+)
+
+func TestDescribeFinding(t *testing.T) {
+	assert.Equal(t, "a.go:3: m", describeFinding(findings.Finding{Path: "a.go", Line: 3, Message: "m"}))
+	assert.Equal(t, "a.go: m", describeFinding(findings.Finding{Path: "a.go", Message: "m"}))
+	assert.Equal(t, "m", describeFinding(findings.Finding{Message: "m"}))
+}
+
+func TestPrintRule(t *testing.T) {
+	var buf bytes.Buffer
+	printRule(&buf, rules.Rule{
+		ID: "lint:x/y", Skill: "lint:x", Description: "Y holds", Severity: rules.SeverityWarning, Fixable: true,
+		Rationale: "Because.", Spec: "spec/x.md", Section: "X",
+		Examples: []string{"a\n  b", "c"}, Fix: "cortex run lint:x",
+	})
+	assert.Equal(t, `lint:x/y: Y holds
+Skill: lint:x; severity: warning; fixable: yes
+
+Because.
+
+Spec: spec/x.md, section "X"
+
+Compliant:
+    a
+      b
+
+    c
+
+Fix: cortex run lint:x
+`, buf.String())
+}
@@ -29,9 +29,15 @@ func NewFeaturesCommand() *cobra.Command {
 		Long:  "Tools for visualizing, analyzing, and documenting the feature graph defined in spec/features.yaml",
 	}
 
+	cmd.AddCommand(NewFeaturesAddCommand())
+	cmd.AddCommand(NewFeaturesAnnotateCommand())
+	cmd.AddCommand(NewFeaturesCriticalPathCommand())
 	cmd.AddCommand(NewFeaturesGraphCommand())
 	cmd.AddCommand(NewFeaturesImpactCommand())
 	cmd.AddCommand(NewFeaturesOverviewCommand())
+	cmd.AddCommand(NewFeaturesRenameCommand())
+	cmd.AddCommand(NewFeaturesServeCommand())
+	cmd.AddCommand(NewFeaturesSetCommand())
 
 	return cmd
 }
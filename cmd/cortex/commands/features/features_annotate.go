@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package features
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/annotate"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/pkg/gov"
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_FEATURES
+// Spec: spec/cli/features.md
+
+func NewFeaturesAnnotateCommand() *cobra.Command {
+	var (
+		dryRun       bool
+		featuresPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Insert or update Feature/Spec header comments from config path rules",
+		Long: `Assigns tracked files to features using the features.annotate path rules in
+.cortex/config.yaml and inserts or updates their "Feature:" and "Spec:" header
+comments. With --dry-run the changes are printed as unified diffs instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return fmt.Errorf("finding repo root: %w", err)
+			}
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return err
+			}
+			if len(cfg.Features.Annotate) == 0 {
+				return fmt.Errorf("no features.annotate rules in %s", config.Path(repoRoot))
+			}
+
+			if !filepath.IsAbs(featuresPath) {
+				featuresPath = filepath.Join(repoRoot, featuresPath)
+			}
+			reg, err := gov.LoadRegistry(featuresPath)
+			if err != nil {
+				return err
+			}
+			specs := make(map[string]string, len(reg.Features))
+			for _, f := range reg.Features {
+				specs[f.ID] = f.Spec
+			}
+
+			files, err := scanner.New(repoRoot).TrackedFiles(cmd.Context())
+			if err != nil {
+				return err
+			}
+			changes, err := annotate.Plan(repoRoot, files, cfg.Features.Annotate, specs)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				for _, c := range changes {
+					d := gov.UnifiedDiff("a/"+c.Path, "b/"+c.Path, c.Old, c.New, gov.DefaultDiffOptions())
+					_, _ = fmt.Fprint(out, d.String())
+				}
+				_, _ = fmt.Fprintf(out, "%d file(s) would be annotated\n", len(changes))
+				return nil
+			}
+
+			for _, c := range changes {
+				path := filepath.Join(repoRoot, filepath.FromSlash(c.Path))
+				info, err := os.Stat(path)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, []byte(c.New), info.Mode().Perm()); err != nil {
+					return fmt.Errorf("writing %s: %w", c.Path, err)
+				}
+				_, _ = fmt.Fprintf(out, "annotated %s (%s)\n", c.Path, c.Feature)
+			}
+			_, _ = fmt.Fprintf(out, "✓ Annotated %d file(s)\n", len(changes))
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print unified diffs instead of writing files")
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+
+	return cmd
+}
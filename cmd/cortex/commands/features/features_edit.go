@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package features
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/pkg/gov"
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_FEATURES
+// Spec: spec/cli/features.md
+
+// editableFields are the `features set` flags, in alphabetical order; each
+// maps to the features.yaml key with dashes replaced by underscores.
+var editableFields = []string{"depends-on", "governance", "group", "implementation", "owner", "spec", "tests", "title"}
+
+func NewFeaturesAddCommand() *cobra.Command {
+	var (
+		featuresPath string
+		f            gov.Feature
+		governance   string
+		impl         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a feature to the registry",
+		Long:  "Appends a feature to spec/features.yaml through the canonical writer. The registry is validated before it is written.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := gov.LoadDocument(featuresPath)
+			if err != nil {
+				return err
+			}
+			f.Governance = gov.GovernanceState(governance)
+			f.Implementation = gov.ImplementationState(impl)
+			if err := doc.Add(f); err != nil {
+				return err
+			}
+			if err := doc.Write(featuresPath); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Added %s to %s\n", f.ID, featuresPath)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringSliceVar(&f.DependsOn, "depends-on", nil, "Feature IDs this feature depends on")
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().StringVar(&governance, "governance", string(gov.GovDraft), "Governance state: draft, review, approved or deprecated")
+	cmd.Flags().StringVar(&f.Group, "group", "", "Feature group (e.g. cli)")
+	cmd.Flags().StringVar(&f.ID, "id", "", "Feature ID")
+	cmd.Flags().StringVar(&impl, "implementation", string(gov.ImplTodo), "Implementation state: todo, wip, done or deprecated")
+	cmd.Flags().StringVar(&f.Owner, "owner", "", "Feature owner")
+	cmd.Flags().StringVar(&f.Spec, "spec", "", "Spec path relative to the repo root")
+	cmd.Flags().StringSliceVar(&f.Tests, "tests", nil, "Test files covering the feature")
+	cmd.Flags().StringVar(&f.Title, "title", "", "Feature title")
+
+	return cmd
+}
+
+func NewFeaturesSetCommand() *cobra.Command {
+	var (
+		featuresPath string
+		featureID    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Change fields of a registry feature",
+		Long: "Updates the given fields of a feature in spec/features.yaml, keeping ordering, styling and comments. " +
+			"The registry is validated before it is written.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if featureID == "" {
+				return fmt.Errorf("--id is required")
+			}
+			doc, err := gov.LoadDocument(featuresPath)
+			if err != nil {
+				return err
+			}
+
+			var changed []string
+			for _, name := range editableFields {
+				if !cmd.Flags().Changed(name) {
+					continue
+				}
+				value, _ := cmd.Flags().GetString(name)
+				if err := doc.Set(featureID, strings.ReplaceAll(name, "-", "_"), value); err != nil {
+					return err
+				}
+				changed = append(changed, name)
+			}
+			if len(changed) == 0 {
+				return fmt.Errorf("nothing to set; pass at least one of --%s", strings.Join(editableFields, ", --"))
+			}
+
+			if err := doc.Write(featuresPath); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Updated %s (%s)\n", featureID, strings.Join(changed, ", "))
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("depends-on", "", "Comma-separated feature IDs (empty clears)")
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().String("governance", "", "Governance state: draft, review, approved or deprecated")
+	cmd.Flags().String("group", "", "Feature group")
+	cmd.Flags().StringVar(&featureID, "id", "", "Feature ID to change")
+	cmd.Flags().String("implementation", "", "Implementation state: todo, wip, done or deprecated")
+	cmd.Flags().String("owner", "", "Feature owner")
+	cmd.Flags().String("spec", "", "Spec path relative to the repo root")
+	cmd.Flags().String("tests", "", "Comma-separated test files (empty clears)")
+	cmd.Flags().String("title", "", "Feature title")
+
+	return cmd
+}
+
+func NewFeaturesRenameCommand() *cobra.Command {
+	var featuresPath string
+
+	cmd := &cobra.Command{
+		Use:   "rename <old-id> <new-id>",
+		Short: "Rename a registry feature",
+		Long: "Renames a feature in spec/features.yaml and updates depends_on references to it. " +
+			"Feature: headers and spec frontmatter are not rewritten.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := gov.LoadDocument(featuresPath)
+			if err != nil {
+				return err
+			}
+			if err := doc.Rename(args[0], args[1]); err != nil {
+				return err
+			}
+			if err := doc.Write(featuresPath); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Renamed %s to %s\n", args[0], args[1])
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Update `Feature: %s` headers and the spec frontmatter by hand.\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+
+	return cmd
+}
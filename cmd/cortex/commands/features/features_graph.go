@@ -28,6 +28,7 @@ func NewFeaturesGraphCommand() *cobra.Command {
 	var (
 		featuresPath string
 		dot          bool
+		format       string
 	)
 
 	cmd := &cobra.Command{
@@ -44,10 +45,25 @@ func NewFeaturesGraphCommand() *cobra.Command {
 			}
 
 			if dot {
-				fmt.Println(features.ToDOT(g))
-			} else {
+				format = features.FormatDOT
+			}
+
+			switch format {
+			case features.FormatText:
 				fmt.Printf("✓ Feature dependency graph is valid (acyclic)\n")
 				fmt.Printf("  Total features: %d\n", len(g.Nodes))
+			case features.FormatDOT:
+				fmt.Println(features.ToDOT(g))
+			case features.FormatJSON:
+				out, err := features.ToJSON(g)
+				if err != nil {
+					return err
+				}
+				fmt.Print(out)
+			case features.FormatMermaid:
+				fmt.Print(features.ToMermaid(g))
+			default:
+				return fmt.Errorf("invalid --format %q (must be text, dot, json or mermaid)", format)
 			}
 
 			return nil
@@ -55,7 +71,8 @@ func NewFeaturesGraphCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
-	cmd.Flags().BoolVar(&dot, "dot", false, "Output in DOT format")
+	cmd.Flags().BoolVar(&dot, "dot", false, "Output in DOT format (same as --format dot)")
+	cmd.Flags().StringVar(&format, "format", features.FormatText, "Output format: text, dot, json or mermaid")
 
 	return cmd
 }
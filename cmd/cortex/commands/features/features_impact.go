@@ -28,11 +28,12 @@ func NewFeaturesImpactCommand() *cobra.Command {
 	var (
 		featuresPath string
 		featureID    string
+		opts         features.ImpactOptions
 	)
 
 	cmd := &cobra.Command{
 		Use:   "impact [feature-id]",
-		Short: "Analyze downstream impact of a feature",
+		Short: "Analyze downstream impact (or upstream prerequisites) of a feature",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -42,16 +43,28 @@ func NewFeaturesImpactCommand() *cobra.Command {
 				return fmt.Errorf("feature-id is required")
 			}
 
+			if opts.Depth < 0 {
+				return fmt.Errorf("--depth must not be negative")
+			}
+
 			g, err := features.LoadGraph(featuresPath)
 			if err != nil {
 				return fmt.Errorf("failed to load graph: %w", err)
 			}
+			if _, ok := g.Nodes[featureID]; !ok {
+				return fmt.Errorf("unknown feature %q", featureID)
+			}
 
-			impacted := features.Impact(g, featureID)
+			relation := "depend on"
+			if opts.Upstream {
+				relation = "are required by"
+			}
+
+			impacted := features.ImpactWith(g, featureID, opts)
 			if len(impacted) == 0 {
-				fmt.Printf("No features depend on %s\n", featureID)
+				fmt.Printf("No features %s %s\n", relation, featureID)
 			} else {
-				fmt.Printf("Features that depend on %s:\n", featureID)
+				fmt.Printf("Features that %s %s:\n", relation, featureID)
 				for _, id := range impacted {
 					fmt.Printf("  - %s\n", id)
 				}
@@ -61,8 +74,46 @@ func NewFeaturesImpactCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().IntVar(&opts.Depth, "depth", 0, "Maximum number of dependency hops to follow (0 = unlimited)")
 	cmd.Flags().StringVar(&featureID, "feature", "", "Feature ID (deprecated: use arg)")
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().BoolVar(&opts.Upstream, "upstream", false, "List transitive prerequisites instead of dependents")
+
+	return cmd
+}
+
+func NewFeaturesCriticalPathCommand() *cobra.Command {
+	var featuresPath string
+
+	cmd := &cobra.Command{
+		Use:   "critical-path",
+		Short: "Show the longest dependency chain of unfinished features",
+		Long:  "Prints the longest chain of features whose implementation is not done, from first prerequisite to last dependent.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, err := features.LoadGraph(featuresPath)
+			if err != nil {
+				return fmt.Errorf("failed to load graph: %w", err)
+			}
+			if err := features.ValidateDAG(g); err != nil {
+				return fmt.Errorf("feature DAG invalid: %w", err)
+			}
+
+			path := features.CriticalPath(g)
+			if len(path) == 0 {
+				fmt.Println("All features are done; no critical path")
+				return nil
+			}
+
+			fmt.Printf("Critical path (%d features):\n", len(path))
+			for i, id := range path {
+				fmt.Printf("  %d. %s [impl=%s]\n", i+1, id, g.Nodes[id].Implementation)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
 
 	return cmd
 }
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package features
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/bartekus/cortex/internal/featureview"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_FEATURES
+// Spec: spec/cli/features.md
+
+func NewFeaturesServeCommand() *cobra.Command {
+	var (
+		addr         string
+		featuresPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an interactive view of the feature graph",
+		Long: `Starts a local, read-only web UI rendering the feature DAG of spec/features.yaml:
+zoom and pan, filter by implementation, governance, owner and roadmap phase,
+and click through to dependencies and specs. The page is embedded in the
+binary and reads the graph from GET /graph.json.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return fmt.Errorf("finding repo root: %w", err)
+			}
+			if !filepath.IsAbs(featuresPath) {
+				featuresPath = filepath.Join(repoRoot, featuresPath)
+			}
+			// Fail before listening when the registry is broken.
+			if _, err := featureview.Load(featuresPath); err != nil {
+				return err
+			}
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("listen: %w", err)
+			}
+			srv := &http.Server{Handler: featureview.New(repoRoot, featuresPath), ReadHeaderTimeout: 10 * time.Second}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				_ = srv.Shutdown(shutdownCtx)
+			}()
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Serving feature graph on http://%s\n", ln.Addr())
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("serve: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8090", "Address to listen on")
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+
+	return cmd
+}
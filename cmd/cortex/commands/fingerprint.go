@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/fingerprint"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_FINGERPRINT
+// Spec: spec/cli/fingerprint.md
+
+// NewFingerprintCommand returns the `cortex fingerprint` command.
+func NewFingerprintCommand() *cobra.Command {
+	var digest bool
+
+	cmd := &cobra.Command{
+		Use:   "fingerprint",
+		Short: "Print the repository fingerprint",
+		Long: "Prints the fingerprint of HEAD, the index and the working tree as canonical JSON: the state snapshot IDs, " +
+			"leases and run history IDs are derived from. With --digest prints its sha256 digest instead.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			fp, err := fingerprint.Compute(cmd.Context(), repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "fingerprint", err)
+			}
+
+			if digest {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), fp.Digest())
+				return err
+			}
+			data, err := fp.JSON()
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "fingerprint", err)
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+			return err
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&digest, "digest", false, "Print the sha256 digest of the canonical JSON instead")
+
+	return cmd
+}
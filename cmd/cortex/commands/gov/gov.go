@@ -36,6 +36,13 @@ func NewGovCommand() *cobra.Command {
 	cmd.AddCommand(NewGovSpecVsCLICommand())
 	cmd.AddCommand(NewGovValidateCommand())
 	cmd.AddCommand(NewGovDriftCommand())
+	cmd.AddCommand(NewGovSchemaCommand())
+	cmd.AddCommand(NewGovMCPSchemaCommand())
+	cmd.AddCommand(NewGovAPIDriftCommand())
+	cmd.AddCommand(NewGovOwnersCommand())
+	cmd.AddCommand(NewGovSuppressionsCommand())
+	cmd.AddCommand(NewGovExportInputCommand())
+	cmd.AddCommand(NewGovOPAEvalCommand())
 
 	return cmd
 }
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/apidrift"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+// NewGovAPIDriftCommand returns `cortex gov api-drift`.
+func NewGovAPIDriftCommand() *cobra.Command {
+	var (
+		asJSON  bool
+		fixture string
+		update  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "api-drift [PATTERN...]",
+		Short: "Compare the exported Go API of pkg/... with the committed baseline",
+		Long: `Dumps the exported API of the packages matched by the patterns (default pkg/...):
+constants, variables, types with their exported fields and methods, and function
+signatures, one feature per line. A baseline feature that was removed or changed is a
+breaking change and exits 1; new features are reported as compatible additions.
+--update rewrites the baseline instead, to accept an intentional change.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			patterns := args
+			if len(patterns) == 0 {
+				patterns = apidrift.DefaultPatterns
+			}
+			fixturePath := fixture
+			if !filepath.IsAbs(fixturePath) {
+				fixturePath = filepath.Join(repoRoot, fixturePath)
+			}
+
+			api, err := apidrift.Dump(repoRoot, patterns)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "gov api-drift", err)
+			}
+
+			if update {
+				if err := os.MkdirAll(filepath.Dir(fixturePath), 0o755); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov api-drift", err)
+				}
+				if err := os.WriteFile(fixturePath, apidrift.Canonical(api), 0o644); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov api-drift", err)
+				}
+			}
+
+			report, err := apidrift.Check(api, repoRoot, fixture)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov api-drift", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(out, "API: %d packages, %d features\n", report.Packages, report.Features)
+				switch {
+				case report.Diff != nil:
+					_, _ = fmt.Fprint(out, report.Diff.String())
+					_, _ = fmt.Fprintf(out, "%d breaking change(s), %d addition(s)\n", len(report.Removed), len(report.Added))
+				case update:
+					_, _ = fmt.Fprintf(out, "✓ Updated %s\n", fixture)
+				default:
+					_, _ = fmt.Fprintf(out, "✓ API matches %s\n", fixture)
+				}
+			}
+
+			if report.Breaking() {
+				return clierr.Newf(clierr.ExitValidation, "gov api-drift: %d breaking change(s) against %s (rerun with --update to accept)", len(report.Removed), fixture)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&fixture, "fixture", apidrift.DefaultFixture, "Path to the API baseline")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the report as JSON")
+	cmd.Flags().BoolVar(&update, "update", false, "Rewrite the baseline from the current API")
+
+	return cmd
+}
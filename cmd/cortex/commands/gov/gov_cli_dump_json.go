@@ -42,7 +42,7 @@ func NewGovCLIDumpJSONCommand() *cobra.Command {
 			if root == nil {
 				return fmt.Errorf("failed to resolve root command")
 			}
-			tree := introspect.Introspect(root)
+			tree := introspect.NewDump(root)
 
 			if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
 				return fmt.Errorf("failed to create output dir: %w", err)
@@ -2,9 +2,21 @@ package gov
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/contextdocs"
+	"github.com/bartekus/cortex/internal/docs"
+	"github.com/bartekus/cortex/internal/log"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/roadmap"
+	"github.com/bartekus/cortex/internal/templates"
+	"github.com/bartekus/cortex/internal/xray"
 	"github.com/bartekus/cortex/pkg/gov"
 	"github.com/spf13/cobra"
 )
@@ -16,24 +28,89 @@ func NewGovDriftCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "drift",
 		Short: "Detect drift between implementation and fixtures",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("diff-format")
+			return gov.ValidateDiffFormat(format)
+		},
 	}
 
+	cmd.PersistentFlags().String("diff-format", gov.DiffFormatText, "Diff output format on drift (text|json)")
+
 	cmd.AddCommand(newDriftHelpCommand())
 	cmd.AddCommand(newDriftXrayCommand())
+	cmd.AddCommand(newDriftGeneratedCommand())
 
 	return cmd
 }
 
+// reportDrift renders a drift diff in the requested --diff-format.
+// Text diffs are already part of the error message; JSON diffs are written
+// to stdout so tooling can consume them, and only the summary is returned.
+func reportDrift(cmd *cobra.Command, err error) error {
+	format, _ := cmd.Flags().GetString("diff-format")
+
+	if format != gov.DiffFormatJSON {
+		return err
+	}
+
+	var generated *gov.GeneratedDriftError
+	if errors.As(err, &generated) {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(generated.Drifts); encErr != nil {
+			return fmt.Errorf("failed to encode drift JSON: %w", encErr)
+		}
+		return fmt.Errorf("generated docs drift detected in %d file(s)", len(generated.Drifts))
+	}
+
+	var help *gov.HelpDriftError
+	if errors.As(err, &help) {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(help.Drifts); encErr != nil {
+			return fmt.Errorf("failed to encode drift JSON: %w", encErr)
+		}
+		return fmt.Errorf("CLI help drift detected in %d file(s)", len(help.Drifts))
+	}
+
+	var drift *gov.DriftError
+	if !errors.As(err, &drift) {
+		return err
+	}
+
+	out, rerr := drift.Diff.Render(format)
+	if rerr != nil {
+		return rerr
+	}
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), out)
+	return errors.New(drift.Message)
+}
+
 func newDriftHelpCommand() *cobra.Command {
 	var (
 		binaryPath  string
+		fixtureDir  string
 		fixturePath string
+		update      bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "help",
 		Short: "Check for CLI help output drift",
+		Long: `Compares the root help of the built binary with --fixture, then the help of every
+command with its fixture under --fixture-dir (gov drift help is gov/drift/help.txt).
+Fixtures of commands that no longer exist are drift too. --update rewrites the
+fixture directory from the command tree instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if update {
+				pages := gov.WalkHelp(cmd.Root())
+				if err := gov.WriteHelpTree(pages, fixtureDir); err != nil {
+					return fmt.Errorf("failed to write help fixtures: %w", err)
+				}
+				fmt.Printf("✓ Updated the help fixtures of %d commands in %s\n", len(pages), fixtureDir)
+				return nil
+			}
+
 			// Run the binary to get help output
 			c := exec.Command(binaryPath, "--help")
 			var out bytes.Buffer
@@ -45,16 +122,23 @@ func newDriftHelpCommand() *cobra.Command {
 			}
 
 			if err := gov.CompareHelp(out.String(), fixturePath); err != nil {
-				return err
+				return reportDrift(cmd, err)
 			}
-
 			fmt.Println("✓ CLI help matches fixture")
+
+			pages := gov.WalkHelp(cmd.Root())
+			if err := gov.CompareHelpTree(pages, fixtureDir, gov.DefaultDiffOptions()); err != nil {
+				return reportDrift(cmd, err)
+			}
+			fmt.Printf("✓ Help of %d commands matches %s\n", len(pages), fixtureDir)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&binaryPath, "binary", "bin/cortex", "Path to cortex binary")
 	cmd.Flags().StringVar(&fixturePath, "fixture", "spec/fixtures/cli/help.sample.txt", "Path to help fixture")
+	cmd.Flags().StringVar(&fixtureDir, "fixture-dir", "spec/fixtures/cli", "Directory of per-command help fixtures")
+	cmd.Flags().BoolVar(&update, "update", false, "Rewrite the per-command help fixtures")
 
 	return cmd
 }
@@ -67,7 +151,7 @@ func newDriftXrayCommand() *cobra.Command {
 		Short: "Check for XRAY index fixture drift",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := gov.CheckXrayDrift(fixturePath); err != nil {
-				return err
+				return reportDrift(cmd, err)
 			}
 			fmt.Println("✓ XRAY index fixture is valid")
 			return nil
@@ -78,3 +162,110 @@ func newDriftXrayCommand() *cobra.Command {
 
 	return cmd
 }
+
+func newDriftGeneratedCommand() *cobra.Command {
+	var (
+		featuresPath string
+		specRoot     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generated",
+		Short: "Check that committed docs/__generated__ outputs are up to date",
+		Long: `Re-renders every generated artifact (feature completion analysis, feature overview,
+context docs) into a temporary directory and diffs it against the committed copy.
+Fails with a unified diff when an artifact was not regenerated after a change.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return fmt.Errorf("finding repo root: %w", err)
+			}
+			if !filepath.IsAbs(featuresPath) {
+				featuresPath = filepath.Join(repoRoot, featuresPath)
+			}
+			if !filepath.IsAbs(specRoot) {
+				specRoot = filepath.Join(repoRoot, specRoot)
+			}
+
+			artifacts := []gov.GeneratedArtifact{
+				{
+					Path: "docs/__generated__/feature-completion-analysis.md",
+					Render: func(dst string) error {
+						return renderRoadmap(repoRoot, featuresPath, dst)
+					},
+				},
+				{
+					Path: "docs/__generated__/features-overview.md",
+					Render: func(dst string) error {
+						return docs.GenerateFeatureOverview(featuresPath, specRoot, dst)
+					},
+				},
+				{
+					Path: "docs/__generated__/cli",
+					Dir:  true,
+					Render: func(dst string) error {
+						return docs.GenerateCLIReference(cmd.Root(), specRoot, clierr.Descriptions(), dst)
+					},
+				},
+				{
+					Path: "docs/__generated__/context",
+					Dir:  true,
+					Render: func(dst string) error {
+						return renderContextDocs(repoRoot, dst)
+					},
+				},
+			}
+
+			skipped, err := gov.CheckGeneratedDrift(repoRoot, artifacts, gov.DefaultDiffOptions())
+			for _, path := range skipped {
+				log.FromContext(cmd.Context()).Warn("skipping drift check", "path", path, "reason", "not committed")
+			}
+			if err != nil {
+				return reportDrift(cmd, err)
+			}
+
+			fmt.Println("✓ Generated docs match committed versions")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().StringVar(&specRoot, "spec-root", "spec", "Root directory containing spec files")
+
+	return cmd
+}
+
+// renderRoadmap mirrors `cortex reports status-roadmap` without touching the repository.
+func renderRoadmap(repoRoot, featuresPath, dst string) error {
+	phases, err := roadmap.DetectPhases(featuresPath)
+	if err != nil {
+		return fmt.Errorf("detect phases: %w", err)
+	}
+	stats := roadmap.CalculateStats(phases)
+	blockers := roadmap.IdentifyBlockers(phases)
+	md, err := roadmap.GenerateMarkdownWith(templates.New(repoRoot), stats, blockers)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, []byte(md), 0o600)
+}
+
+// renderContextDocs scans the repository with the native engine (the index
+// of `cortex context build --engine go`, identical to the XRAY one) into a
+// scratch directory and projects it into dst exactly as `cortex context
+// docs` would, so the check needs no xray binary.
+func renderContextDocs(repoRoot, dst string) error {
+	index, err := xray.Scan(repoRoot, ".")
+	if err != nil {
+		return fmt.Errorf("scanning: %w", err)
+	}
+	indexFile, err := xray.WriteIndex(index, dst+".data")
+	if err != nil {
+		return err
+	}
+
+	paths := contextdocs.DefaultPaths(repoRoot)
+	paths.Index = indexFile
+	return contextdocs.Generate(paths, dst)
+}
@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+func TestRenderContextDocs_NativeScan(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o600))
+	// No xray binary is needed.
+	t.Setenv("PATH", t.TempDir())
+
+	dst := filepath.Join(t.TempDir(), "context")
+	require.NoError(t, renderContextDocs(root, dst))
+	index, err := os.ReadFile(filepath.Join(dst, "index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "- **Files**: 1")
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/policy"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+// NewGovExportInputCommand returns `cortex gov export-input`.
+func NewGovExportInputCommand() *cobra.Command {
+	var (
+		out      string
+		stateDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-input",
+		Short: "Export governance facts as a JSON input document for OPA",
+		Long: `Writes a single JSON document with the features of spec/features.yaml, the skill results,
+findings and coverage of the last run, the feature mapping and the HEAD commit. Keys are
+sorted, so the same repository state always exports the same document. The document is
+the input of policy engines such as OPA (see gov opa-eval).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			data, err := exportInput(cmd, repoRoot, stateDir)
+			if err != nil {
+				return err
+			}
+			if out == "-" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := projection.AtomicWrite(out, data); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "writing "+out, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %s\n", out)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&out, "out", "-", "Output file (- for stdout)")
+	cmd.Flags().StringVar(&stateDir, "state-dir", ".cortex/run", "Run state directory, relative to the repository root")
+
+	return cmd
+}
+
+// exportInput collects the sources of repoRoot and encodes the input
+// document.
+func exportInput(cmd *cobra.Command, repoRoot, stateDir string) ([]byte, error) {
+	if !filepath.IsAbs(stateDir) {
+		stateDir = filepath.Join(repoRoot, stateDir)
+	}
+	src, err := policy.Collect(cmd.Context(), repoRoot, stateDir)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitExecution, "collecting governance facts", err)
+	}
+	data, err := policy.Input(src)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitExecution, "encoding input", err)
+	}
+	return data, nil
+}
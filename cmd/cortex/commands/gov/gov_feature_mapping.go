@@ -38,27 +38,27 @@ func NewGovFeatureMappingCommand() *cobra.Command {
 			report, err := mapping.Analyze(opts)
 			if err != nil {
 				// Internal error – use exit code 2.
-				return clierr.New(2, fmt.Sprintf("governance feature mapping failed: %v", err))
+				return clierr.New(clierr.ExitExecution, fmt.Sprintf("governance feature mapping failed: %v", err))
 			}
 
 			switch format {
 			case "json":
 				if err := renderReportJSON(cmd, report); err != nil {
 					// Rendering issues are internal errors.
-					return clierr.New(2, fmt.Sprintf("render mapping report (json): %v", err))
+					return clierr.New(clierr.ExitExecution, fmt.Sprintf("render mapping report (json): %v", err))
 				}
 			case "text", "":
 				if err := renderReportText(cmd, report); err != nil {
 					// Rendering issues are internal errors.
-					return clierr.New(2, fmt.Sprintf("render mapping report (text): %v", err))
+					return clierr.New(clierr.ExitExecution, fmt.Sprintf("render mapping report (text): %v", err))
 				}
 			default:
-				return clierr.New(2, fmt.Sprintf("unsupported format %q (expected text or json)", format))
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("unsupported format %q (expected text or json)", format))
 			}
 
 			// After rendering, decide exit code based on violations.
 			if len(report.Violations) > 0 {
-				return clierr.New(1, fmt.Sprintf("feature mapping validation failed with %d violation(s)", len(report.Violations)))
+				return clierr.New(clierr.ExitValidation, fmt.Sprintf("feature mapping validation failed with %d violation(s)", len(report.Violations)))
 			}
 
 			return nil
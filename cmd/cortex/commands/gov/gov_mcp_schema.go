@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/mcpschema"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+// NewGovMCPSchemaCommand returns `cortex gov mcp-schema`.
+func NewGovMCPSchemaCommand() *cobra.Command {
+	var (
+		asJSON  bool
+		fixture string
+		mcpBin  string
+		update  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mcp-schema",
+		Short: "Lint MCP tool schemas and compare them with golden fixtures",
+		Long: `Starts cortex-mcp and lists the tools it registers. Each input schema must describe
+an object with typed properties, and the Go argument struct of every tool Cortex calls
+must match it: each field a property of the same type, each required property a field
+that is always sent. The tools are then compared with the golden fixture; --update
+rewrites the fixture instead. Exits 1 on a problem or drift.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			bin, err := mcp.ResolveBin(mcpBin, repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov mcp-schema", err)
+			}
+			fixturePath := fixture
+			if !filepath.IsAbs(fixturePath) {
+				fixturePath = filepath.Join(repoRoot, fixturePath)
+			}
+
+			tools, err := mcpschema.ListTools(cmd.Context(), bin, cmd.ErrOrStderr())
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "gov mcp-schema", err)
+			}
+
+			if update {
+				data, err := mcpschema.Canonical(tools)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov mcp-schema", err)
+				}
+				if err := os.MkdirAll(filepath.Dir(fixturePath), 0o755); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov mcp-schema", err)
+				}
+				if err := os.WriteFile(fixturePath, data, 0o644); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov mcp-schema", err)
+				}
+			}
+
+			report, err := mcpschema.Check(tools, repoRoot, fixture)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov mcp-schema", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(out, "MCP tools: %d registered, %d bound to Go argument structs\n", report.Tools, report.Bound)
+				for _, p := range report.Problems {
+					_, _ = fmt.Fprintf(out, "✗ %s: %s\n", p.Tool, p.Message)
+				}
+				if report.Diff != nil {
+					_, _ = fmt.Fprint(out, report.Diff.String())
+				} else if update {
+					_, _ = fmt.Fprintf(out, "✓ Updated %s\n", fixture)
+				} else {
+					_, _ = fmt.Fprintf(out, "✓ Tool schemas match %s\n", fixture)
+				}
+			}
+
+			switch {
+			case len(report.Problems) > 0:
+				return clierr.Newf(clierr.ExitValidation, "gov mcp-schema: %d schema problem(s)", len(report.Problems))
+			case report.Diff != nil:
+				return clierr.Newf(clierr.ExitValidation, "gov mcp-schema: tool schemas drifted from %s (rerun with --update to accept)", fixture)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&fixture, "fixture", mcpschema.DefaultFixture, "Path to the golden tool schema fixture")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the report as JSON")
+	cmd.Flags().StringVar(&mcpBin, "mcp-bin", "", "Path to the cortex-mcp binary (default: $CORTEX_MCP_BIN, then rust/target)")
+	cmd.Flags().BoolVar(&update, "update", false, "Rewrite the fixture from the server's tools")
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/policy"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+// NewGovOPAEvalCommand returns `cortex gov opa-eval`.
+func NewGovOPAEvalCommand() *cobra.Command {
+	var (
+		opaBin    string
+		policyDir string
+		query     string
+		stateDir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "opa-eval",
+		Short: "Evaluate Rego policies against the exported governance input with opa",
+		Long: `Exports the input document of gov export-input and runs opa eval for --query against it,
+loading the policies in --policy (the bundled policies when empty). Each message the query
+yields is a violation. Exits 1 on a violation and 2 when opa is not installed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			opa, err := exec.LookPath(opaBin)
+			if err != nil {
+				return clierr.Newf(clierr.ExitConfig, "gov opa-eval: %s not found; install OPA from https://www.openpolicyagent.org", opaBin)
+			}
+			if policyDir == "" {
+				tmp, err := os.MkdirTemp("", "cortex-opa-")
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov opa-eval", err)
+				}
+				defer func() { _ = os.RemoveAll(tmp) }()
+				if err := policy.WriteBundled(tmp); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "gov opa-eval", err)
+				}
+				policyDir = tmp
+			} else if _, err := os.Stat(policyDir); err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov opa-eval", err)
+			}
+
+			input, err := exportInput(cmd, repoRoot, stateDir)
+			if err != nil {
+				return err
+			}
+			msgs, err := evalOPA(cmd.Context(), opa, policyDir, query, input)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "gov opa-eval", err)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, m := range msgs {
+				_, _ = fmt.Fprintf(out, "✗ %s\n", m)
+			}
+			if len(msgs) > 0 {
+				return clierr.Newf(clierr.ExitValidation, "gov opa-eval: %d violation(s)", len(msgs))
+			}
+			_, _ = fmt.Fprintf(out, "✓ %s is empty\n", query)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&opaBin, "opa", "opa", "OPA binary")
+	cmd.Flags().StringVar(&policyDir, "policy", "", "Directory of Rego policies (default: the bundled policies)")
+	cmd.Flags().StringVar(&query, "query", policy.DefaultQuery, "Query yielding the violation messages")
+	cmd.Flags().StringVar(&stateDir, "state-dir", ".cortex/run", "Run state directory, relative to the repository root")
+
+	return cmd
+}
+
+// evalOPA runs `opa eval` for query against input, loading the policies
+// under dir, and returns the messages of the result. The query must yield a
+// set or array; an undefined result has no messages.
+func evalOPA(ctx context.Context, opa, dir, query string, input []byte) ([]string, error) {
+	cmd := exec.CommandContext(ctx, opa, "eval", "--format", "json", "--stdin-input", "--data", dir, query)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(string(out))
+		}
+		return nil, fmt.Errorf("opa eval failed: %w: %s", err, msg)
+	}
+	return policy.ParseOPAResult(out)
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/policy"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+func TestEvalOPA(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as opa")
+	}
+	dir := t.TempDir()
+	opa := filepath.Join(dir, "opa")
+	// The fake opa echoes its arguments and input back as violations.
+	script := `#!/bin/sh
+input=$(cat)
+printf '{"result":[{"expressions":[{"value":["%s","%s"]}]}]}' "$*" "$input"
+`
+	require.NoError(t, os.WriteFile(opa, []byte(script), 0o755))
+
+	msgs, err := evalOPA(context.Background(), opa, "policies", policy.DefaultQuery, []byte("in"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eval --format json --stdin-input --data policies data.cortex.deny", "in"}, msgs)
+
+	require.NoError(t, os.WriteFile(opa, []byte("#!/bin/sh\necho 'rego_parse_error' >&2\nexit 1\n"), 0o755))
+	_, err = evalOPA(context.Background(), opa, "policies", policy.DefaultQuery, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rego_parse_error")
+}
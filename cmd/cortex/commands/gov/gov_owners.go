@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/owners"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+// NewGovOwnersCommand returns `cortex gov owners`.
+func NewGovOwnersCommand() *cobra.Command {
+	var (
+		asJSON       bool
+		featuresPath string
+		since        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "owners",
+		Short: "Check feature owners against CODEOWNERS and commit history",
+		Long: `Cross-references the owner of each feature in spec/features.yaml with CODEOWNERS and
+with the authors of recent commits to the feature's files (its spec and the files with
+its Feature header). Reports features without an owner, files CODEOWNERS does not cover
+or assigns to someone else, and owners who authored none of the recent commits to their
+features. Owner aliases are read from features.owners in .cortex/config.yaml. Exits 1
+on a problem.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov owners", err)
+			}
+			codeOwners, err := owners.FindCodeOwners(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov owners", err)
+			}
+			if codeOwners == nil {
+				return clierr.Newf(clierr.ExitConfig, "gov owners: no CODEOWNERS file (looked for %v)", owners.CodeOwnersPaths)
+			}
+			feats, err := owners.LoadFeatures(cmd.Context(), repoRoot, featuresPath)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "gov owners", err)
+			}
+			authors, err := git.FileAuthors(cmd.Context(), repoRoot, since)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "gov owners", err)
+			}
+
+			report := owners.Check(owners.Input{
+				Features:   feats,
+				CodeOwners: codeOwners,
+				Authors:    authors,
+				Since:      since,
+				Aliases:    cfg.Features.Owners,
+			})
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				for _, p := range report.Problems {
+					_, _ = fmt.Fprintf(out, "✗ %s\n", p)
+				}
+				if len(report.Problems) == 0 {
+					_, _ = fmt.Fprintf(out, "✓ %d features (%d files) match %s and their history\n", report.Features, report.Files, report.CodeOwners)
+				}
+			}
+
+			if len(report.Problems) > 0 {
+				return clierr.Newf(clierr.ExitValidation, "gov owners: %d problem(s)", len(report.Problems))
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&featuresPath, "features", "spec/features.yaml", "Path to features.yaml, relative to the repository root")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the report as JSON")
+	cmd.Flags().StringVar(&since, "since", "1 year ago", "Commit window as a git date; empty for the whole history")
+
+	return cmd
+}
@@ -0,0 +1,95 @@
+package gov
+
+import (
+	"fmt"
+
+	"github.com/bartekus/cortex/internal/schemas"
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+func NewGovSchemaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect and validate JSON artifact schemas",
+	}
+
+	cmd.AddCommand(newSchemaListCommand())
+	cmd.AddCommand(newSchemaShowCommand())
+	cmd.AddCommand(newSchemaValidateCommand())
+
+	return cmd
+}
+
+func newSchemaListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List embedded artifact schemas",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, s := range schemas.All() {
+				file := s.FileName
+				if file == "" {
+					file = "-"
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%-22s %-6s %s\n", s.Name, s.Version, file)
+			}
+			return nil
+		},
+	}
+}
+
+func newSchemaShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Print an embedded artifact schema",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, ok := schemas.Lookup(args[0])
+			if !ok {
+				return fmt.Errorf("unknown schema %q", args[0])
+			}
+			raw, err := s.Raw()
+			if err != nil {
+				return err
+			}
+			_, _ = cmd.OutOrStdout().Write(raw)
+			return nil
+		},
+	}
+}
+
+func newSchemaValidateCommand() *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "validate <file>...",
+		Short: "Validate JSON artifacts against their embedded schema",
+		Long: `Validates each file against its embedded JSON Schema.
+The schema is detected from the file name (e.g. commit-health.json, last-run.json)
+unless --schema is given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failed := 0
+			for _, path := range args {
+				s, err := schemas.ValidateFile(path, name)
+				if err != nil {
+					failed++
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✗ %s: %v\n", path, err)
+					continue
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ %s matches %s@%s\n", path, s.Name, s.Version)
+			}
+			if failed > 0 {
+				return fmt.Errorf("schema validation failed for %d file(s)", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "schema", "", "Schema name to validate against (default: detect from file name)")
+
+	return cmd
+}
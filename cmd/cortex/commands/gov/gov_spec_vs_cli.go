@@ -5,8 +5,11 @@ package gov
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/schemas"
 	"github.com/bartekus/cortex/internal/specschema"
 	"github.com/bartekus/cortex/internal/specvscli"
 	"github.com/bartekus/cortex/pkg/introspect"
@@ -20,6 +23,7 @@ func NewGovSpecVsCLICommand() *cobra.Command {
 	var (
 		specPath   string
 		binaryPath string
+		format     string
 		strict     bool
 	)
 
@@ -27,67 +31,86 @@ func NewGovSpecVsCLICommand() *cobra.Command {
 		Use:   "spec-vs-cli",
 		Short: "Validate alignment between CLI help output and Spec flags",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			specs, err := specschema.LoadAllSpecs(specPath)
-			if err != nil {
-				return fmt.Errorf("failed to load specs: %w", err)
+			if format != "text" && format != "json" {
+				return clierr.Newf(clierr.ExitConfig, "unsupported format %q (expected text or json)", format)
 			}
-
-			// Load CLI definition from JSON file
 			if binaryPath == "" {
-				return fmt.Errorf("--binary-json is required")
+				return clierr.New(clierr.ExitConfig, "--binary-json is required")
 			}
 
-			f, err := os.Open(binaryPath)
+			specs, err := specschema.LoadAllSpecs(specPath)
 			if err != nil {
-				return fmt.Errorf("failed to open binary json file: %w", err)
+				return clierr.Wrap(clierr.ExitConfig, "failed to load specs", err)
 			}
-			defer func() { _ = f.Close() }()
 
-			var cliCommands []introspect.CommandInfo
-			if err := json.NewDecoder(f).Decode(&cliCommands); err != nil {
-				return fmt.Errorf("failed to decode binary json: %w", err)
+			data, err := os.ReadFile(binaryPath)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "failed to open binary json file", err)
 			}
 
-			// Use CompareAllCommands from specvscli
-			results := specvscli.CompareAllCommands(specs, cliCommands)
+			var dump introspect.Dump
+			if err := schemas.Decode(schemas.CLI, data, &dump); err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "failed to decode binary json (regenerate it with `cortex gov cli-dump-json`)", err)
+			}
 
-			// Report results
-			hasErrors := false
-			hasWarnings := false
+			report := specvscli.NewReport(specvscli.CompareAllCommands(specs, dump.Commands), strict)
 
-			for _, result := range results {
-				if len(result.Errors) > 0 {
-					hasErrors = true
-					fmt.Printf("ERROR: Command %q:\n", result.CommandName)
-					for _, err := range result.Errors {
-						fmt.Printf("  - %s\n", err)
-					}
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "render spec-vs-cli report (json)", err)
 				}
-				if len(result.Warnings) > 0 {
-					hasWarnings = true
-					fmt.Printf("WARNING: Command %q:\n", result.CommandName)
-					for _, warn := range result.Warnings {
-						fmt.Printf("  - %s\n", warn)
-					}
-				}
-			}
-
-			if hasErrors {
-				return fmt.Errorf("CLI alignment check failed")
+			} else {
+				renderSpecVsCLIText(out, report)
 			}
 
-			if hasWarnings {
-				fmt.Printf("\n⚠ Flag alignment warnings (non-blocking)\n")
-			} else {
-				fmt.Println("✓ CLI matches Spec")
+			switch {
+			case report.Errors > 0:
+				return clierr.Newf(clierr.ExitValidation, "CLI alignment check failed: %d error(s), %d warning(s)", report.Errors, report.Warnings)
+			case !report.OK:
+				return clierr.Newf(clierr.ExitWarning, "CLI alignment check failed: %d warning(s) (--strict)", report.Warnings)
 			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&specPath, "spec-root", "spec", "Root directory containing spec files")
+	// Flags in alphabetical order for deterministic help output
 	cmd.Flags().StringVar(&binaryPath, "binary-json", "", "Path to JSON output from cli-dump-json")
-	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on warnings (not implemented yet)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.Flags().StringVar(&specPath, "spec-root", "spec", "Root directory containing spec files")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on warnings (exit code 3)")
 
 	return cmd
 }
+
+// renderSpecVsCLIText prints the findings of report per command.
+func renderSpecVsCLIText(out io.Writer, report specvscli.Report) {
+	for _, result := range report.Commands {
+		if len(result.Errors) > 0 {
+			_, _ = fmt.Fprintf(out, "ERROR: Command %q:\n", result.CommandName)
+			for _, err := range result.Errors {
+				_, _ = fmt.Fprintf(out, "  - %s\n", err)
+			}
+		}
+		if len(result.Warnings) > 0 {
+			label := "WARNING"
+			if report.Strict {
+				label = "ERROR"
+			}
+			_, _ = fmt.Fprintf(out, "%s: Command %q:\n", label, result.CommandName)
+			for _, warn := range result.Warnings {
+				_, _ = fmt.Fprintf(out, "  - %s\n", warn)
+			}
+		}
+	}
+
+	switch {
+	case !report.OK:
+	case report.Warnings > 0:
+		_, _ = fmt.Fprintf(out, "\n⚠ Flag alignment warnings (non-blocking)\n")
+	default:
+		_, _ = fmt.Fprintln(out, "✓ CLI matches Spec")
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/rules"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/skills"
+)
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+// suppressionEntry is a suppression of the `gov suppressions` report.
+type suppressionEntry struct {
+	findings.Suppression
+	Status string `json:"status"`
+}
+
+// NewGovSuppressionsCommand returns `cortex gov suppressions`.
+func NewGovSuppressionsCommand() *cobra.Command {
+	var (
+		asJSON bool
+		today  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "suppressions",
+		Short: "List the inline cortex:ignore suppressions for audit",
+		Long: `Lists every inline suppression comment in the tracked files with its skill, reason and
+expiry. A suppression is a line comment holding cortex:ignore, a skill or rule ID
+(cortex rules list), a required reason="..." and an optional expires=YYYY-MM-DD; it drops
+the skill's or rule's findings on its own line and the next one. Suppressions without a
+reason, for an unknown skill or rule or otherwise malformed are invalid, and suppressions past their expiry no longer apply. Exits 1 when
+any suppression is invalid or expired.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			day := time.Now()
+			if today != "" {
+				var err error
+				if day, err = time.Parse(findings.DateLayout, today); err != nil {
+					return clierr.Newf(clierr.ExitConfig, "gov suppressions: invalid --today %q: want YYYY-MM-DD", today)
+				}
+			}
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			files, err := scanner.New(repoRoot).TrackedFiles(cmd.Context())
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "gov suppressions", err)
+			}
+
+			known := map[string]bool{}
+			for _, s := range skills.Registry {
+				known[runner.BaseSkillID(s.ID())] = true
+			}
+			var entries []suppressionEntry
+			problems := 0
+			for _, s := range findings.ScanSuppressions(repoRoot, files) {
+				if _, isRule := rules.Lookup(s.Skill); s.Error == "" && !known[s.Skill] && !isRule {
+					s.Error = fmt.Sprintf("unknown skill or rule %q", s.Skill)
+				}
+				e := suppressionEntry{Suppression: s, Status: s.Status(day)}
+				if e.Status != findings.SuppressionActive {
+					problems++
+				}
+				entries = append(entries, e)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				if entries == nil {
+					entries = []suppressionEntry{}
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(entries); err != nil {
+					return err
+				}
+			} else {
+				for _, e := range entries {
+					_, _ = fmt.Fprintf(out, "%s:%d\t%s\t%s\t%s\n", e.Path, e.Line, e.Skill, e.Status, describeSuppression(e))
+				}
+				_, _ = fmt.Fprintf(out, "%d suppression(s), %d invalid or expired\n", len(entries), problems)
+			}
+
+			if problems > 0 {
+				return clierr.Newf(clierr.ExitValidation, "gov suppressions: %d invalid or expired suppression(s)", problems)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the suppressions as JSON")
+	cmd.Flags().StringVar(&today, "today", "", "Date expiries are checked against, as YYYY-MM-DD (default: the current date)")
+
+	return cmd
+}
+
+func describeSuppression(e suppressionEntry) string {
+	switch {
+	case e.Error != "":
+		return e.Error
+	case e.Expires != "":
+		return fmt.Sprintf("%s (expires %s)", e.Reason, e.Expires)
+	default:
+		return e.Reason
+	}
+}
@@ -2,7 +2,6 @@ package gov
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/bartekus/cortex/pkg/gov"
 	"github.com/spf13/cobra"
@@ -48,10 +47,10 @@ func NewGovValidateCommand() *cobra.Command {
 		},
 	}
 
-	// Default to assume running from repo root
-	cwd, _ := os.Getwd()
+	// Default to assume running from repo root. The default is "." rather
+	// than the working directory so that the help output is deterministic.
 	cmd.Flags().StringVar(&registryPath, "registry", "spec/features.yaml", "Path to features.yaml")
-	cmd.Flags().StringVar(&rootDir, "root", cwd, "Root directory of the repository")
+	cmd.Flags().StringVar(&rootDir, "root", ".", "Root directory of the repository")
 
 	return cmd
 }
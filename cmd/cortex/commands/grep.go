@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/search"
+	"github.com/bartekus/cortex/internal/snapshot"
+)
+
+// Feature: CLI_COMMAND_GREP
+// Spec: spec/cli/grep.md
+
+// grepOutput is the --json document.
+type grepOutput struct {
+	Pattern    string `json:"pattern"`
+	Mode       string `json:"mode"`
+	SnapshotID string `json:"snapshot_id,omitempty"`
+	*search.Result
+}
+
+// NewGrepCommand returns the `cortex grep` command.
+func NewGrepCommand() *cobra.Command {
+	var (
+		opts       search.Options
+		caseMode   string
+		asJSON     bool
+		mcpBin     string
+		snapshotID string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "grep <PATTERN>",
+		Short: "Search the worktree or a snapshot",
+		Long: "Searches the files git tracks, or with --snapshot the files of a snapshot, for an RE2 regular expression " +
+			"(or a literal with --fixed-strings). Matches are printed in path and line order. Exits 1 when nothing matches.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "grep", err)
+			}
+
+			opts.Pattern = args[0]
+			opts.Case = search.CaseMode(caseMode)
+			opts.Files = scanner.Policy{MaxFileBytes: cfg.Files.MaxBytes}
+			searcher, err := search.Compile(opts)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "grep", err)
+			}
+
+			out := grepOutput{Pattern: opts.Pattern, Mode: "worktree"}
+			var src search.Source = search.Worktree{Root: repoRoot}
+			if snapshotID != "" {
+				bin, err := mcp.ResolveBin(mcpBin, repoRoot)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "grep", err)
+				}
+				client, err := mcp.Start(cmd.Context(), bin, repoRoot, cmd.ErrOrStderr())
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "grep", err)
+				}
+				defer func() { _ = client.Close() }()
+				out.Mode, out.SnapshotID = "snapshot", snapshotID
+				src = search.Snapshot{Store: &snapshot.MCPStore{Client: client, RepoRoot: repoRoot}, ID: snapshotID}
+			}
+
+			out.Result, err = searcher.Search(cmd.Context(), src)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "grep", err)
+			}
+
+			w := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(out); err != nil {
+					return err
+				}
+			} else {
+				printMatches(w, out.Matches, opts.Context)
+				if out.Truncated {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "cortex grep: stopped after %d matches (--max-matches)\n", opts.MaxMatches)
+				}
+			}
+			if len(out.Matches) == 0 {
+				return clierr.New(clierr.ExitValidation, "grep: no matches")
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&caseMode, "case", string(search.CaseSensitive), "Case mode: sensitive, insensitive or smart (insensitive unless the pattern has an upper-case letter)")
+	cmd.Flags().IntVar(&opts.Context, "context", 0, "Lines of context to show before and after each match")
+	cmd.Flags().StringArrayVar(&opts.Exclude, "exclude", nil, "Skip files matching this glob (repeatable)")
+	cmd.Flags().BoolVar(&opts.FixedStrings, "fixed-strings", false, "Treat the pattern as a literal string")
+	cmd.Flags().StringArrayVar(&opts.Include, "include", nil, "Only search files matching this glob (repeatable)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the matches as JSON")
+	cmd.Flags().IntVar(&opts.MaxMatches, "max-matches", 0, "Stop after this many matches (0: no limit)")
+	cmd.Flags().StringVar(&mcpBin, "mcp-bin", "", "Path to the cortex-mcp binary (default: $CORTEX_MCP_BIN, then rust/target)")
+	cmd.Flags().StringVar(&snapshotID, "snapshot", "", "Search this snapshot instead of the worktree")
+
+	return cmd
+}
+
+// printMatches prints matches as grep does: "path:line:text" for matches
+// and "path-line-text" for context lines, each printed once. With context,
+// "--" separates groups of lines that are not adjacent.
+func printMatches(w io.Writer, matches []search.Match, context int) {
+	path, last := "", 0 // last line printed in path
+	for i, m := range matches {
+		first := m.Line - len(m.Before)
+		switch {
+		case m.Path != path:
+			if context > 0 && path != "" {
+				_, _ = fmt.Fprintln(w, "--")
+			}
+			path, last = m.Path, 0
+		case context > 0 && first > last+1:
+			_, _ = fmt.Fprintln(w, "--")
+		}
+		line := func(n int, sep, text string) {
+			if n > last {
+				_, _ = fmt.Fprintf(w, "%s%s%d%s%s\n", m.Path, sep, n, sep, text)
+				last = n
+			}
+		}
+		for j, text := range m.Before {
+			line(first+j, "-", text)
+		}
+		line(m.Line, ":", m.Text)
+		for j, text := range m.After {
+			// A following match prints its own line.
+			if i+1 < len(matches) && matches[i+1].Path == m.Path && matches[i+1].Line <= m.Line+1+j {
+				break
+			}
+			line(m.Line+1+j, "-", text)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package commands
+
+// Feature: CLI_COMMAND_GREP
+// Spec: spec/cli/grep.md
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bartekus/cortex/internal/search"
+)
+
+func TestPrintMatches(t *testing.T) {
+	var buf bytes.Buffer
+	printMatches(&buf, []search.Match{
+		{Path: "a.go", Line: 2, Text: "hit", Before: []string{"one"}, After: []string{"hit again"}},
+		{Path: "a.go", Line: 3, Text: "hit again", Before: []string{"hit"}, After: []string{"four"}},
+		{Path: "a.go", Line: 9, Text: "hit", Before: []string{"eight"}},
+		{Path: "b.go", Line: 1, Text: "hit", After: []string{"two"}},
+	}, 1)
+
+	assert.Equal(t, `a.go-1-one
+a.go:2:hit
+a.go:3:hit again
+a.go-4-four
+--
+a.go-8-eight
+a.go:9:hit
+--
+b.go:1:hit
+b.go-2-two
+`, buf.String())
+
+	buf.Reset()
+	printMatches(&buf, []search.Match{{Path: "a.go", Line: 2, Text: "hit"}, {Path: "b.go", Line: 5, Text: "hit"}}, 0)
+	assert.Equal(t, "a.go:2:hit\nb.go:5:hit\n", buf.String())
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/scaffold"
+)
+
+// Feature: CLI_COMMAND_INIT
+// Spec: spec/cli/init.md
+
+// NewInitCommand returns the `cortex init` bootstrap command.
+func NewInitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init [dir]",
+		Short: "Bootstrap the governance layout in a repository",
+		Long: "Creates the spec skeleton, spec/features.yaml with its JSON Schema, .cortex/config.yaml, the docs index " +
+			"and git hooks. Existing files are kept, so re-running only adds what is missing.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: runInit,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().Bool("json", false, "Output the scaffolded files as JSON")
+	cmd.Flags().String("profile", scaffold.ProfileMinimal, "Template profile: "+strings.Join(scaffold.Profiles(), " or "))
+
+	return cmd
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	profile, _ := cmd.Flags().GetString("profile")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if !slices.Contains(scaffold.Profiles(), profile) {
+		return clierr.Newf(clierr.ExitConfig, "unknown profile %q (want one of: %s)", profile, strings.Join(scaffold.Profiles(), ", "))
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return clierr.Newf(clierr.ExitConfig, "%s is not a directory", dir)
+	}
+
+	entries, err := scaffold.Init(dir, profile)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "init", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	created := 0
+	for _, e := range entries {
+		line := fmt.Sprintf("%-8s %s", e.Action, e.Path)
+		if e.Note != "" {
+			line += " (" + e.Note + ")"
+		}
+		_, _ = fmt.Fprintln(out, line)
+		if e.Action == scaffold.ActionCreated {
+			created++
+		}
+	}
+	_, _ = fmt.Fprintf(out, "✓ Initialized %d file(s) with profile %s\n", created, profile)
+	return nil
+}
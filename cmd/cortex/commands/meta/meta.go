@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package meta contains Cobra subcommands for the Cortex CLI.
+package meta
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/meta"
+)
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+// NewMetaCommand returns the `cortex meta` command.
+func NewMetaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meta",
+		Short: "Govern a workspace of repositories listed in a manifest",
+		Long: "Operates over the repositories of a workspace manifest (local paths or git URLs): " +
+			"clone them, run a profile across all of them and aggregate their features and run state",
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.PersistentFlags().String("manifest", meta.ManifestFile, "Workspace manifest")
+	cmd.PersistentFlags().StringSlice("repo", nil, "Only these repositories (repeatable; default: all)")
+
+	cmd.AddCommand(NewMetaRunCommand())
+	cmd.AddCommand(NewMetaStatusCommand())
+	cmd.AddCommand(NewMetaSyncCommand())
+
+	return cmd
+}
+
+// loadManifest loads the manifest and the repositories selected by --repo.
+func loadManifest(cmd *cobra.Command) (*meta.Manifest, []meta.Repo, error) {
+	path, _ := cmd.Flags().GetString("manifest")
+	names, _ := cmd.Flags().GetStringSlice("repo")
+	m, err := meta.Load(path)
+	if err != nil {
+		return nil, nil, clierr.Wrap(clierr.ExitConfig, "loading workspace manifest", err)
+	}
+	repos, err := m.Select(names)
+	if err != nil {
+		return nil, nil, clierr.Wrap(clierr.ExitConfig, "selecting repositories", err)
+	}
+	return m, repos, nil
+}
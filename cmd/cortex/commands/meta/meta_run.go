@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/meta"
+)
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+// NewMetaRunCommand returns `cortex meta run`.
+func NewMetaRunCommand() *cobra.Command {
+	var (
+		asJSON  bool
+		profile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a profile in every repository and aggregate the results",
+		Long: `Runs the skills of --profile (every skill without one) in each repository in turn, as
+cortex run does there, then prints one line per repository. Exits with the highest exit
+code of the repositories.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, repos, err := loadManifest(cmd)
+			if err != nil {
+				return err
+			}
+			p, err := m.Profile(profile)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "meta run", err)
+			}
+
+			out := cmd.OutOrStdout()
+			progress := out
+			if asJSON {
+				progress = cmd.ErrOrStderr()
+			}
+			sum := meta.Run(cmd.Context(), m, repos, profile, p, progress)
+
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(sum); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintln(out)
+				for _, r := range sum.Repos {
+					switch {
+					case r.Error != "":
+						_, _ = fmt.Fprintf(out, "✗ %s: %s\n", r.Repo, r.Error)
+					case r.ExitCode != 0:
+						var failed []string
+						for _, res := range r.Report.Failed() {
+							failed = append(failed, res.Skill)
+						}
+						_, _ = fmt.Fprintf(out, "✗ %s: exit %d (%s)\n", r.Repo, r.ExitCode, strings.Join(failed, ", "))
+					default:
+						_, _ = fmt.Fprintf(out, "✓ %s: %d skills\n", r.Repo, len(r.Report.Results))
+					}
+				}
+			}
+
+			if sum.ExitCode != 0 {
+				failed := 0
+				for _, r := range sum.Repos {
+					if r.ExitCode != 0 {
+						failed++
+					}
+				}
+				return clierr.Newf(sum.ExitCode, "meta run: %d of %d repositories failed", failed, len(sum.Repos))
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the aggregated results as JSON (progress goes to stderr)")
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile of the manifest to run (default: every skill)")
+
+	return cmd
+}
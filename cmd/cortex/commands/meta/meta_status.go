@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/meta"
+	"github.com/bartekus/cortex/internal/projection"
+)
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+// NewMetaStatusCommand returns `cortex meta status`.
+func NewMetaStatusCommand() *cobra.Command {
+	var (
+		format string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Generate a cross-repository feature and status dashboard",
+		Long: `Reads spec/features.yaml and the last cortex run of each repository and renders one
+dashboard: feature completion and last run per repository, feature IDs defined in several
+repositories, and every feature with its repository and state.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "markdown" && format != "json" {
+				return clierr.Newf(clierr.ExitConfig, "meta status: unknown format %q (want markdown or json)", format)
+			}
+			m, repos, err := loadManifest(cmd)
+			if err != nil {
+				return err
+			}
+			d := meta.Status(cmd.Context(), m, repos)
+
+			var data []byte
+			if format == "json" {
+				data, err = json.MarshalIndent(d, "", "  ")
+				if err != nil {
+					return err
+				}
+				data = append(data, '\n')
+			} else {
+				data = []byte(d.Markdown())
+			}
+
+			if output == "-" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := projection.AtomicWrite(output, data); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "writing "+output, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %s\n", output)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&format, "format", "markdown", "Output format: markdown or json")
+	cmd.Flags().StringVar(&output, "output", "-", "Output file (- for stdout)")
+
+	return cmd
+}
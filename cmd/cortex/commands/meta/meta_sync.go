@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/meta"
+)
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+// NewMetaSyncCommand returns `cortex meta sync`.
+func NewMetaSyncCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Clone or update the repositories listed by URL",
+		Long: `Clones each repository listed by URL into .cortex/meta/repos/<name> next to the manifest,
+or fetches it when already cloned, and checks out its ref (the remote HEAD without one)
+detached. Repositories listed by path are only checked to exist. Exits 4 when a
+repository cannot be synced.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			m, repos, err := loadManifest(cmd)
+			if err != nil {
+				return err
+			}
+			results := meta.Sync(cmd.Context(), m, repos)
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for _, r := range results {
+				if r.Error != "" {
+					failed++
+				}
+			}
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(results); err != nil {
+					return err
+				}
+			} else {
+				for _, r := range results {
+					if r.Error != "" {
+						_, _ = fmt.Fprintf(out, "✗ %s: %s\n", r.Repo, r.Error)
+						continue
+					}
+					_, _ = fmt.Fprintf(out, "✓ %s %s %s\n", r.Repo, r.Action, shortHash(r.Commit))
+				}
+			}
+			if failed > 0 {
+				return clierr.Newf(clierr.ExitExecution, "meta sync: %d of %d repositories failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the results as JSON")
+
+	return cmd
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
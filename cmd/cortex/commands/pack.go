@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/bundle"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_PACK
+// Spec: spec/cli/pack.md
+
+// NewPackCommand returns the `cortex pack` command.
+func NewPackCommand() *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "pack",
+		Short: "Archive context artifacts, reports and the feature registry",
+		Long: "Packs the context artifacts under .cortex, the reports and spec/features.yaml into a single deterministic " +
+			"bundle with a digest manifest. The compression follows the --out extension: .tar.zst, .tar.gz or .tar.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			c, err := bundle.CompressionFor(out)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "pack", err)
+			}
+			paths, err := bundle.Members(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "pack", err)
+			}
+			if len(paths) == 0 {
+				return clierr.New(clierr.ExitConfig, "pack: nothing to pack (run `cortex context build` first)")
+			}
+
+			var buf bytes.Buffer
+			m, err := bundle.Pack(&buf, repoRoot, paths, c)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "pack", err)
+			}
+			if err := projection.AtomicWrite(out, buf.Bytes()); err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "pack", err)
+			}
+			digest, err := m.Digest()
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "pack", err)
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Packed %d files into %s (%s)\n", len(m.Files), out, digest)
+			return err
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&out, "out", "cortex-bundle.tar.zst", "Bundle to write (.tar.zst, .tar.gz or .tar)")
+
+	return cmd
+}
+
+// NewUnpackCommand returns the `cortex unpack` command.
+func NewUnpackCommand() *cobra.Command {
+	var (
+		dir        string
+		force      bool
+		verifyOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "unpack <BUNDLE>",
+		Short: "Verify and extract a bundle written by cortex pack",
+		Long: "Verifies every file of a bundle against its manifest, then extracts the bundle. Nothing is written " +
+			"unless the whole bundle verifies, and existing files with other content are only replaced with --force.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := bundle.CompressionFor(args[0])
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "unpack", err)
+			}
+			f, err := os.Open(args[0])
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "unpack", err)
+			}
+			defer func() { _ = f.Close() }()
+
+			m, contents, err := bundle.Read(f, c)
+			if errors.Is(err, bundle.ErrInvalid) {
+				return clierr.Wrap(clierr.ExitValidation, "unpack", err)
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "unpack", err)
+			}
+			digest, err := m.Digest()
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "unpack", err)
+			}
+
+			if !verifyOnly {
+				err := bundle.Unpack(dir, m, contents, force)
+				if errors.Is(err, bundle.ErrConflict) {
+					return clierr.Wrap(clierr.ExitConfig, "unpack", err)
+				}
+				if errors.Is(err, bundle.ErrInvalid) {
+					return clierr.Wrap(clierr.ExitValidation, "unpack", err)
+				}
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "unpack", err)
+				}
+			}
+			verb := "Unpacked"
+			if verifyOnly {
+				verb = "Verified"
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s %d files (%s)\n", verb, len(m.Files), digest)
+			return err
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&dir, "dir", ".", "Directory to extract into")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace existing files that differ from the bundle")
+	cmd.Flags().BoolVar(&verifyOnly, "verify-only", false, "Verify the bundle without extracting it")
+
+	return cmd
+}
@@ -27,10 +27,16 @@ func NewReportsCommand() *cobra.Command {
 		Long:  "Report commands for Cortex's commit discipline & health, feature traceability and status roadmap analysis",
 	}
 
+	cmd.AddCommand(NewChangelogCommand())
+	cmd.AddCommand(NewCommitDraftCommand())
 	cmd.AddCommand(NewCommitReportCommand())
 	cmd.AddCommand(NewCommitSuggestCommand())
 	cmd.AddCommand(NewFeatureTraceabilityCommand())
+	cmd.AddCommand(NewListCommand())
+	cmd.AddCommand(NewPRSummaryCommand())
+	cmd.AddCommand(NewSignCommand())
 	cmd.AddCommand(NewStatusRoadmapCommand())
+	cmd.AddCommand(NewVerifyCommand())
 
 	return cmd
 }
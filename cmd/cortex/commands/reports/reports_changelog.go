@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/mapping"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/changelog"
+	"github.com/bartekus/cortex/internal/reports/commitdraft"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+	"github.com/bartekus/cortex/internal/templates"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+// NewChangelogCommand returns the `cortex reports changelog` command.
+func NewChangelogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate a CHANGELOG section from feature status transitions and commits",
+		Long: "Combines the status transitions of each feature in the history of features.yaml since --since " +
+			"with the Conventional Commits of the range, grouped by feature and type",
+		Args: cobra.NoArgs,
+		RunE: runChangelog,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("features", "spec/features.yaml", "Path to features.yaml, relative to the repository root")
+	cmd.Flags().String("output", "-", "Output file (- for stdout)")
+	cmd.Flags().String("since", "", "Ref the range starts from, such as the last release tag (required)")
+	cmd.Flags().String("title", "Unreleased", "Heading of the section")
+	cmd.Flags().String("to", "HEAD", "Ref the range ends at")
+	_ = cmd.MarkFlagRequired("since")
+
+	return cmd
+}
+
+// runChangelog executes the changelog command.
+func runChangelog(cmd *cobra.Command, args []string) error {
+	featuresPath, _ := cmd.Flags().GetString("features")
+	output, _ := cmd.Flags().GetString("output")
+	since, _ := cmd.Flags().GetString("since")
+	title, _ := cmd.Flags().GetString("title")
+	to, _ := cmd.Flags().GetString("to")
+
+	repoPath, err := projectroot.Find(".")
+	if err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+	}
+	gen := reportindex.Begin(cmd, args, repoPath)
+	ctx := cmd.Context()
+	for _, ref := range []string{since, to} {
+		if !git.RefExists(ctx, repoPath, ref) {
+			return clierr.Newf(clierr.ExitConfig, "changelog: unknown ref %q", ref)
+		}
+	}
+	featuresPath = filepath.ToSlash(featuresPath)
+
+	in := changelog.Input{Title: title, Since: since}
+	revisions, err := git.PathCommits(ctx, repoPath, since, to, featuresPath)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "changelog", err)
+	}
+	for _, rev := range append([]string{since}, revisions...) {
+		reg, err := registryAt(cmd, repoPath, rev, featuresPath)
+		if err != nil {
+			return err
+		}
+		in.Registries = append(in.Registries, reg)
+	}
+
+	commits, err := git.Commits(ctx, repoPath, since, to)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "changelog", err)
+	}
+	files, err := git.CommitFiles(ctx, repoPath, since, to)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "changelog", err)
+	}
+	for _, c := range commits {
+		in.Commits = append(in.Commits, changelog.Commit{Hash: c.Hash, Message: c.Message, Files: files[c.Hash]})
+	}
+
+	report, err := mapping.Analyze(mapping.Options{RootDir: repoPath})
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "mapping features", err)
+	}
+	in.Index = commitdraft.FeatureIndex(report)
+
+	md, err := changelog.RenderWith(templates.New(repoPath), in)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "changelog", err)
+	}
+	if output == "-" {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), md)
+		return err
+	}
+	if err := projection.AtomicWrite(output, []byte(md)); err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "writing "+output, err)
+	}
+	if err := recordOutput(gen, repoPath, output); err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "indexing "+output, err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %s\n", output)
+	return nil
+}
+
+// registryAt loads features.yaml at rev; nil when it does not exist there.
+func registryAt(cmd *cobra.Command, repoPath, rev, featuresPath string) (*gov.Registry, error) {
+	data, err := git.ShowFile(cmd.Context(), repoPath, rev, featuresPath)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitExecution, "changelog", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	reg, err := changelog.ParseRegistry(data)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitValidation, fmt.Sprintf("changelog: %s at %s", featuresPath, rev), err)
+	}
+	return reg, nil
+}
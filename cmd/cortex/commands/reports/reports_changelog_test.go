@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestChangelog_FeatureTransitionsAndCommits(t *testing.T) {
+	// NOTE: This test MUST NOT use t.Parallel() because it changes directory.
+	repoDir := initTestRepo(t, map[string]string{
+		"spec/features.yaml": "features:\n  - id: CLI_DEMO\n    title: Demo\n    governance: approved\n    implementation: todo\n    spec: spec/cli/demo.md\n",
+		"spec/cli/demo.md":   "# Demo\n",
+	})
+	gitTest(t, repoDir, "tag", "v0.1.0")
+
+	writeTestFile(t, repoDir, "cmd/demo.go", "package cmd\n\n// Feature: CLI_DEMO\n// Spec: spec/cli/demo.md\n")
+	gitTest(t, repoDir, "add", "-A")
+	gitTest(t, repoDir, "commit", "-q", "-m", "feat(CLI_DEMO): add demo")
+
+	writeTestFile(t, repoDir, "spec/features.yaml", "features:\n  - id: CLI_DEMO\n    title: Demo\n    governance: approved\n    implementation: done\n    spec: spec/cli/demo.md\n")
+	gitTest(t, repoDir, "commit", "-q", "-am", "docs: mark demo done\n\nFeature: CLI_DEMO")
+
+	writeTestFile(t, repoDir, "README.md", "# Readme\n")
+	gitTest(t, repoDir, "add", "-A")
+	gitTest(t, repoDir, "commit", "-q", "-m", "chore: add readme")
+	t.Chdir(repoDir)
+
+	var out bytes.Buffer
+	cmd := NewChangelogCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--since", "v0.1.0", "--title", "v0.2.0"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("changelog failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"## v0.2.0\n\nChanges since `v0.1.0`: 3 commit(s), 1 feature(s).",
+		"### `CLI_DEMO` Demo\n\n- Implementation: `todo` → `done`\n\n#### Features\n\n- add demo (",
+		"#### Documentation\n\n- mark demo done (",
+		"### Other Changes\n\n#### Chores\n\n- add readme (",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestChangelog_UnknownRef(t *testing.T) {
+	repoDir := initTestRepo(t, map[string]string{"README.md": "# Readme\n"})
+	t.Chdir(repoDir)
+
+	cmd := NewChangelogCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--since", "v9.9.9"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), `unknown ref "v9.9.9"`) {
+		t.Errorf("changelog error = %v, want unknown ref", err)
+	}
+}
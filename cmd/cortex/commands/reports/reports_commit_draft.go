@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/cmd/cortex/internal/llmprovider"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/mapping"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/commitdraft"
+	"github.com/bartekus/cortex/pkg/llm"
+)
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+// draftTypes are the commit types accepted by the commit health report.
+var draftTypes = []string{"chore", "ci", "docs", "feat", "fix", "refactor", "test"}
+
+// NewCommitDraftCommand returns the `cortex reports commit-draft` command.
+func NewCommitDraftCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commit-draft",
+		Short: "Draft a Conventional Commit message for the staged changes",
+		Long:  "Inspects the staged diff, maps touched files to features via the feature mapping, and prints a Conventional Commit message draft",
+		Args:  cobra.NoArgs,
+		RunE:  runCommitDraft,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().Bool("json", false, "Output the draft and the data it was derived from as JSON")
+	cmd.Flags().Bool("polish", false, "Let the configured LLM rewrite the summary (see llm in .cortex/config.yaml)")
+	cmd.Flags().String("scope", "", "Override the inferred scope (default: most-touched feature)")
+	cmd.Flags().Bool("trailer", false, "Append a Feature: trailer for every touched feature")
+	cmd.Flags().String("type", "", "Override the inferred commit type (chore|ci|docs|feat|fix|refactor|test)")
+
+	return cmd
+}
+
+// runCommitDraft executes the commit draft command.
+func runCommitDraft(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	polish, _ := cmd.Flags().GetBool("polish")
+	var opts commitdraft.Options
+	opts.Scope, _ = cmd.Flags().GetString("scope")
+	opts.Trailer, _ = cmd.Flags().GetBool("trailer")
+	opts.Type, _ = cmd.Flags().GetString("type")
+	if opts.Type != "" && !slices.Contains(draftTypes, opts.Type) {
+		return fmt.Errorf("invalid type: %s (must be one of %v)", opts.Type, draftTypes)
+	}
+
+	repoPath, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+
+	changes, err := git.StagedChanges(cmd.Context(), repoPath)
+	if err != nil {
+		return fmt.Errorf("reading staged changes: %w", err)
+	}
+
+	report, err := mapping.Analyze(mapping.Options{RootDir: repoPath})
+	if err != nil {
+		return fmt.Errorf("mapping features: %w", err)
+	}
+
+	draft, err := commitdraft.Build(changes, commitdraft.FeatureIndex(report), opts)
+	if err != nil {
+		return err
+	}
+
+	if polish {
+		cfg, err := config.Load(repoPath)
+		if err != nil {
+			return clierr.Wrap(clierr.ExitConfig, "commit draft", err)
+		}
+		provider, err := llmprovider.New(cfg.LLM)
+		if err != nil {
+			return clierr.Wrap(clierr.ExitConfig, "commit draft", err)
+		}
+		system, prompt := commitdraft.PolishPrompt(draft)
+		resp, err := provider.Complete(cmd.Context(), llm.Request{
+			System:    system,
+			Messages:  []llm.Message{{Role: llm.RoleUser, Content: prompt}},
+			MaxTokens: 100,
+		})
+		if err != nil {
+			return clierr.Wrap(clierr.ExitExecution, "polishing commit draft", err)
+		}
+		if draft, err = commitdraft.Polish(draft, resp.Text, opts); err != nil {
+			return clierr.Wrap(clierr.ExitExecution, "polishing commit draft", err)
+		}
+	}
+
+	if !asJSON {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), draft.Message)
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+	jsonData = append(jsonData, '\n')
+	if _, err := cmd.OutOrStdout().Write(jsonData); err != nil {
+		return fmt.Errorf("writing JSON output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/reports/commitdraft"
+)
+
+func TestCommitDraft_StagedFiles(t *testing.T) {
+	// NOTE: This test MUST NOT use t.Parallel() because it changes directory.
+	repoDir := initTestRepo(t, map[string]string{
+		"spec/features.yaml": "features:\n  - id: CLI_DEMO\n    title: Demo\n    spec: spec/cli/demo.md\n",
+		"spec/cli/demo.md":   "# Demo\n",
+	})
+	writeTestFile(t, repoDir, "cmd/demo.go", "package cmd\n\n// Feature: CLI_DEMO\n// Spec: spec/cli/demo.md\n")
+	gitTest(t, repoDir, "add", "cmd/demo.go")
+	t.Chdir(repoDir)
+
+	cmd := NewCommitDraftCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--json", "--trailer"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("commit-draft failed: %v", err)
+	}
+
+	var draft commitdraft.Draft
+	if err := json.Unmarshal(out.Bytes(), &draft); err != nil {
+		t.Fatalf("parsing JSON output: %v\n%s", err, out.String())
+	}
+	want := "feat(CLI_DEMO): add cmd/demo.go\n\nFeature: CLI_DEMO\n"
+	if draft.Message != want {
+		t.Errorf("message = %q, want %q", draft.Message, want)
+	}
+	if len(draft.Files) != 1 || draft.Files[0].Feature != "CLI_DEMO" {
+		t.Errorf("files = %+v, want cmd/demo.go mapped to CLI_DEMO", draft.Files)
+	}
+}
+
+func TestCommitDraft_InvalidType(t *testing.T) {
+	cmd := NewCommitDraftCommand()
+	cmd.SetArgs([]string{"--type", "feature"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for invalid --type")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/bartekus/cortex/internal/projectroot"
 	"github.com/bartekus/cortex/internal/reports"
 	"github.com/bartekus/cortex/internal/reports/commithealth"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
 )
 
 // Feature: CLI_COMMAND_COMMIT
@@ -57,6 +58,7 @@ func runCommitReport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("finding repo root: %w", err)
 	}
+	gen := reportindex.Begin(cmd, args, repoPath)
 
 	// 2. Get commit range flags
 	fromFlag, _ := cmd.Flags().GetString("from")
@@ -99,6 +101,9 @@ func runCommitReport(cmd *cobra.Command, args []string) error {
 	if err := reports.WriteJSONAtomic(reportPath, report); err != nil {
 		return fmt.Errorf("writing report: %w", err)
 	}
+	if err := gen.Record(repoPath, reportindex.KindReport, reportPath); err != nil {
+		return fmt.Errorf("indexing report: %w", err)
+	}
 
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/bartekus/cortex/internal/reports/commithealth"
 	"github.com/bartekus/cortex/internal/reports/featuretrace"
 	"github.com/bartekus/cortex/internal/reports/suggestions"
+	"github.com/bartekus/cortex/internal/schemas"
 )
 
 // Feature: CLI_COMMAND_COMMIT
@@ -65,7 +66,7 @@ func runCommitSuggest(cmd *cobra.Command, args []string) error {
 	}
 
 	var commitReport commithealth.Report
-	if err := json.Unmarshal(commitReportData, &commitReport); err != nil {
+	if err := schemas.Decode(schemas.CommitHealth, commitReportData, &commitReport); err != nil {
 		return fmt.Errorf("parsing commit health report: %w", err)
 	}
 
@@ -77,7 +78,7 @@ func runCommitSuggest(cmd *cobra.Command, args []string) error {
 	}
 
 	var featureReport featuretrace.Report
-	if err := json.Unmarshal(featureReportData, &featureReport); err != nil {
+	if err := schemas.Decode(schemas.FeatureTraceability, featureReportData, &featureReport); err != nil {
 		return fmt.Errorf("parsing feature traceability report: %w", err)
 	}
 
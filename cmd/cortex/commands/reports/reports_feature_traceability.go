@@ -23,6 +23,7 @@ import (
 	"github.com/bartekus/cortex/internal/projectroot"
 	"github.com/bartekus/cortex/internal/reports"
 	"github.com/bartekus/cortex/internal/reports/featuretrace"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
 )
 
 // Feature: CLI_COMMAND_FEATURE
@@ -49,6 +50,7 @@ func runFeatureTraceability(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("finding repo root: %w", err)
 	}
+	gen := reportindex.Begin(cmd, args, repoPath)
 
 	// 2. Scan repository for feature presence
 	scanConfig := featuretrace.ScanConfig{
@@ -71,6 +73,9 @@ func runFeatureTraceability(cmd *cobra.Command, args []string) error {
 	if err := reports.WriteJSONAtomic(reportPath, report); err != nil {
 		return fmt.Errorf("writing report: %w", err)
 	}
+	if err := gen.Record(repoPath, reportindex.KindReport, reportPath); err != nil {
+		return fmt.Errorf("indexing report: %w", err)
+	}
 
 	return nil
 }
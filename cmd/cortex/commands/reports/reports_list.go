@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+)
+
+// Feature: REPORTS_CORE
+// Spec: spec/reports/core.md
+
+// NewListCommand returns the `cortex reports list` command.
+func NewListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the generated reports and context artifacts",
+		Long: `Lists the artifacts recorded in .cortex/reports/index.json by the report and
+context generators, with whether each file still matches the recorded digest
+(ok, modified or missing), the command and version that generated it.`,
+		Args: cobra.NoArgs,
+		RunE: runList,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().Bool("json", false, "Print the artifacts as JSON, with their status")
+	cmd.Flags().String("kind", "", "Only list artifacts of this kind: report or context")
+
+	return cmd
+}
+
+// listedArtifact is an index entry with its current status.
+type listedArtifact struct {
+	reportindex.Artifact
+	Status string `json:"status"`
+}
+
+func runList(cmd *cobra.Command, _ []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	kind, _ := cmd.Flags().GetString("kind")
+	if kind != "" && kind != reportindex.KindReport && kind != reportindex.KindContext {
+		return clierr.Newf(clierr.ExitConfig, "reports list: invalid --kind %q (must be report or context)", kind)
+	}
+
+	repoPath, err := projectroot.Find(".")
+	if err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+	}
+	idx, err := reportindex.Read(repoPath)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitExecution, "reports list", err)
+	}
+
+	listed := []listedArtifact{}
+	for _, a := range idx.Artifacts {
+		if kind == "" || a.Kind == kind {
+			listed = append(listed, listedArtifact{Artifact: a, Status: a.Status(repoPath)})
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(listed)
+	}
+	if len(listed) == 0 {
+		_, err := fmt.Fprintln(out, "No artifacts recorded.")
+		return err
+	}
+	for _, a := range listed {
+		_, _ = fmt.Fprintf(out, "%-8s  %-7s  %s  (%s %s)\n", a.Status, a.Kind, a.Path, a.Generator, a.GeneratorVersion)
+	}
+	return nil
+}
+
+// recordOutput indexes a report written to output, a path relative to the
+// working directory.
+func recordOutput(gen reportindex.Generation, repoPath, output string) error {
+	path, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+	return gen.Record(repoPath, reportindex.KindReport, path)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: REPORTS_CORE
+// Spec: spec/reports/core.md
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestList_IndexesGeneratedReports(t *testing.T) {
+	// NOTE: This test MUST NOT use t.Parallel() because it changes directory.
+	repoDir := initTestRepo(t, map[string]string{
+		"spec/features.yaml": "features:\n  - id: CLI_DEMO\n    title: Demo\n    implementation: wip\n    spec: spec/cli/demo.md\n",
+		"spec/cli/demo.md":   "# Demo\n",
+	})
+	t.Chdir(repoDir)
+	t.Setenv("CORTEX_VERSION", "1.0.0-test")
+
+	gen := NewPRSummaryCommand()
+	gen.SetOut(&bytes.Buffer{})
+	gen.SetArgs([]string{"--output", "docs/pr.md"})
+	if err := gen.Execute(); err != nil {
+		t.Fatalf("pr-summary failed: %v", err)
+	}
+
+	list := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := NewListCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("reports list %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	var listed []listedArtifact
+	if err := json.Unmarshal([]byte(list("--json")), &listed); err != nil {
+		t.Fatalf("parsing JSON: %v", err)
+	}
+	if len(listed) != 1 {
+		t.Fatalf("want 1 artifact, got %+v", listed)
+	}
+	a := listed[0]
+	if a.Path != "docs/pr.md" || a.Kind != "report" || a.Status != "ok" || a.Generator != "pr-summary" ||
+		a.GeneratorVersion != "1.0.0-test" || a.Inputs["output"] != "docs/pr.md" {
+		t.Errorf("unexpected artifact: %+v", a)
+	}
+	if !strings.HasPrefix(a.SourceFingerprint, "sha256:") {
+		t.Errorf("source fingerprint = %q, want a digest", a.SourceFingerprint)
+	}
+
+	writeTestFile(t, repoDir, "docs/pr.md", "edited\n")
+	if got, want := list(), "modified  report   docs/pr.md  (pr-summary 1.0.0-test)\n"; got != want {
+		t.Errorf("text output = %q, want %q", got, want)
+	}
+	if got := list("--kind", "context", "--json"); strings.TrimSpace(got) != "[]" {
+		t.Errorf("--kind context = %q, want []", got)
+	}
+}
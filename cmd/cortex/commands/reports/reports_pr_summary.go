@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/mapping"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/commitdraft"
+	"github.com/bartekus/cortex/internal/reports/prsummary"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+// NewPRSummaryCommand returns the `cortex reports pr-summary` command.
+func NewPRSummaryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pr-summary",
+		Short: "Generate a Markdown pull request description for the current branch",
+		Long:  "Summarizes the diff against the merge-base with --base: impacted features and specs, last skill results, coverage delta and a change inventory",
+		Args:  cobra.NoArgs,
+		RunE:  runPRSummary,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("base", "main", "Base ref the pull request targets")
+	cmd.Flags().String("base-coverage", "", "Coverage profile of the base for the coverage delta")
+	cmd.Flags().String("coverage", "", "Coverage profile of the branch (default: <state-dir>/coverage.out)")
+	cmd.Flags().String("features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().String("output", "-", "Output file (- for stdout)")
+	cmd.Flags().String("state-dir", ".cortex/run", "Run state directory holding the last skill results")
+
+	return cmd
+}
+
+// runPRSummary executes the PR summary command.
+func runPRSummary(cmd *cobra.Command, args []string) error {
+	base, _ := cmd.Flags().GetString("base")
+	baseCoverage, _ := cmd.Flags().GetString("base-coverage")
+	coverage, _ := cmd.Flags().GetString("coverage")
+	featuresPath, _ := cmd.Flags().GetString("features")
+	output, _ := cmd.Flags().GetString("output")
+	stateDir, _ := cmd.Flags().GetString("state-dir")
+
+	repoPath, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+	gen := reportindex.Begin(cmd, args, repoPath)
+	abs := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(repoPath, p)
+	}
+
+	ctx := cmd.Context()
+	in := prsummary.Input{Base: base}
+	if in.MergeBase, err = git.MergeBase(ctx, repoPath, base, "HEAD"); err != nil {
+		return err
+	}
+	if in.Changes, err = git.DiffChanges(ctx, repoPath, in.MergeBase, "HEAD"); err != nil {
+		return err
+	}
+	if in.Lines, err = git.NumStat(ctx, repoPath, in.MergeBase, "HEAD"); err != nil {
+		return err
+	}
+
+	report, err := mapping.Analyze(mapping.Options{RootDir: repoPath})
+	if err != nil {
+		return fmt.Errorf("mapping features: %w", err)
+	}
+	in.Index = commitdraft.FeatureIndex(report)
+	if in.Graph, err = features.LoadGraph(abs(featuresPath)); err != nil {
+		return fmt.Errorf("failed to load graph: %w", err)
+	}
+
+	if in.Skills, err = lastSkillResults(abs(stateDir)); err != nil {
+		return err
+	}
+
+	if coverage == "" {
+		coverage = filepath.Join(stateDir, "coverage.out")
+		if _, err := os.Stat(abs(coverage)); errors.Is(err, fs.ErrNotExist) {
+			coverage = ""
+		}
+	}
+	if in.Coverage, err = prsummary.ReadCoverage(abs(coverage), repoPath); err != nil {
+		return err
+	}
+	if in.BaseCoverage, err = prsummary.ReadCoverage(abs(baseCoverage), repoPath); err != nil {
+		return err
+	}
+
+	md, err := prsummary.RenderWith(templates.New(repoPath), in)
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), md)
+		return err
+	}
+	if err := projection.AtomicWrite(output, []byte(md)); err != nil {
+		return fmt.Errorf("writing %s: %w", output, err)
+	}
+	if err := recordOutput(gen, repoPath, output); err != nil {
+		return fmt.Errorf("indexing %s: %w", output, err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %s\n", output)
+	return nil
+}
+
+// lastSkillResults loads the results of the last run, in run order.
+func lastSkillResults(stateDir string) ([]runner.SkillResult, error) {
+	store := runner.NewStateStore(stateDir)
+	last, err := store.ReadLastRun()
+	if err != nil || last == nil {
+		return nil, err
+	}
+	var results []runner.SkillResult
+	for _, id := range last.Skills {
+		res, err := store.ReadSkill(id)
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			results = append(results, *res)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPRSummary_WritesFile(t *testing.T) {
+	// NOTE: This test MUST NOT use t.Parallel() because it changes directory.
+	repoDir := initTestRepo(t, map[string]string{
+		"spec/features.yaml": "features:\n  - id: CLI_DEMO\n    title: Demo\n    implementation: wip\n    spec: spec/cli/demo.md\n",
+		"spec/cli/demo.md":   "# Demo\n",
+	})
+	gitTest(t, repoDir, "checkout", "-q", "-b", "feature/CLI_DEMO-x")
+	writeTestFile(t, repoDir, "cmd/demo.go", "package cmd\n\n// Feature: CLI_DEMO\n// Spec: spec/cli/demo.md\n")
+	gitTest(t, repoDir, "add", "-A")
+	gitTest(t, repoDir, "commit", "-q", "-m", "feat(CLI_DEMO): add demo")
+	t.Chdir(repoDir)
+
+	outPath := filepath.Join(t.TempDir(), "pr.md")
+	cmd := NewPRSummaryCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--output", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("pr-summary failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outPath) //nolint:gosec // G304: test file path
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	for _, want := range []string{
+		"1 file(s), +4/-0 lines.",
+		"| `CLI_DEMO` | Demo | wip | [spec/cli/demo.md](spec/cli/demo.md) | 1 |",
+		"| `cmd/demo.go` | added | `CLI_DEMO` | 4 | 0 |",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %q:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package reports
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/signing"
+)
+
+// Feature: REPORTS_SIGNING
+// Spec: spec/reports/signing.md
+
+// NewSignCommand returns the `cortex reports sign` command.
+func NewSignCommand() *cobra.Command {
+	var keyPath string
+
+	cmd := &cobra.Command{
+		Use:   "sign [FILE...]",
+		Short: "Sign reports and bundles with an Ed25519 key",
+		Long: "Writes a detached Ed25519 signature next to each file as <file>.sig. Without arguments every file " +
+			"under .cortex/reports is signed. The key is a PEM PKCS #8 private key read from --key or, when it is " +
+			"not given, from $CORTEX_SIGNING_KEY.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pem := []byte(os.Getenv("CORTEX_SIGNING_KEY"))
+			if keyPath != "" {
+				var err error
+				if pem, err = os.ReadFile(keyPath); err != nil {
+					return clierr.Wrap(clierr.ExitConfig, "reports sign", err)
+				}
+			}
+			if len(pem) == 0 {
+				return clierr.New(clierr.ExitConfig, "reports sign: no key (pass --key or set CORTEX_SIGNING_KEY)")
+			}
+			key, err := signing.ParsePrivateKey(pem)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "reports sign", err)
+			}
+
+			files, err := signedFiles(args)
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				data, err := os.ReadFile(f)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "reports sign", err)
+				}
+				if err := projection.AtomicWrite(f+signing.SigSuffix, signing.Sign(key, data)); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "reports sign", err)
+				}
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Signed %d files with %s\n",
+				len(files), signing.KeyID(key.Public().(ed25519.PublicKey)))
+			return err
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&keyPath, "key", "", "Private key file (default: the PEM in $CORTEX_SIGNING_KEY)")
+
+	return cmd
+}
+
+// NewVerifyCommand returns the `cortex reports verify` command.
+func NewVerifyCommand() *cobra.Command {
+	var keyPath string
+
+	cmd := &cobra.Command{
+		Use:   "verify [FILE...]",
+		Short: "Verify the signatures of reports and bundles",
+		Long: "Checks each file against its <file>.sig with an Ed25519 public key. Without arguments every file " +
+			"under .cortex/reports is checked. Exits 1 when any file is unsigned or does not verify.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keyPath == "" {
+				return clierr.New(clierr.ExitConfig, "reports verify: --key is required")
+			}
+			pem, err := os.ReadFile(keyPath)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "reports verify", err)
+			}
+			pub, err := signing.ParsePublicKey(pem)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "reports verify", err)
+			}
+
+			files, err := signedFiles(args)
+			if err != nil {
+				return err
+			}
+			w := cmd.OutOrStdout()
+			failed := 0
+			for _, f := range files {
+				data, err := os.ReadFile(f)
+				if err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "reports verify", err)
+				}
+				sig, err := os.ReadFile(f + signing.SigSuffix)
+				switch {
+				case errors.Is(err, fs.ErrNotExist):
+					err = errors.New("unsigned")
+				case err == nil:
+					err = signing.Verify(pub, data, sig)
+				}
+				if err != nil {
+					failed++
+					_, _ = fmt.Fprintf(w, "FAIL %s: %v\n", f, err)
+					continue
+				}
+				_, _ = fmt.Fprintf(w, "OK   %s\n", f)
+			}
+			if failed > 0 {
+				return clierr.New(clierr.ExitValidation, fmt.Sprintf("reports verify: %d of %d files failed verification", failed, len(files)))
+			}
+			_, err = fmt.Fprintf(w, "Verified %d files with %s\n", len(files), signing.KeyID(pub))
+			return err
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringVar(&keyPath, "key", "", "Public key file (PEM)")
+
+	return cmd
+}
+
+// signedFiles returns the files named on the command line or, without any,
+// the files under .cortex/reports, relative to the working directory.
+func signedFiles(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+	}
+	files, err := signing.Files(filepath.Join(repoRoot, ".cortex", "reports"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, clierr.Wrap(clierr.ExitExecution, "listing reports", err)
+	}
+	if len(files) == 0 {
+		return nil, clierr.New(clierr.ExitConfig, "no reports under .cortex/reports")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return files, nil
+	}
+	for i, f := range files {
+		if rel, err := filepath.Rel(wd, f); err == nil {
+			files[i] = rel
+		}
+	}
+	return files, nil
+}
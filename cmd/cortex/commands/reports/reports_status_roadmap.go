@@ -18,21 +18,26 @@ See https://www.gnu.org/licenses/ for license details.
 package reports
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/reports/reportindex"
 	roadmap2 "github.com/bartekus/cortex/internal/reports/roadmap"
 
 	"github.com/spf13/cobra"
 
 	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/site"
+	"github.com/bartekus/cortex/internal/templates"
 )
 
 const (
 	defaultFeaturesPath = "spec/features.yaml"
 	defaultOutputPath   = "docs/__generated__/feature-completion-analysis.md"
+	roadmapPage         = "roadmap.html"
 )
 
 func NewStatusRoadmapCommand() *cobra.Command {
@@ -41,28 +46,43 @@ func NewStatusRoadmapCommand() *cobra.Command {
 		Short: "Generate phase-level feature completion analysis from spec/features.yaml",
 		Long: `Generate a deterministic phase-level feature completion analysis document
 based on spec/features.yaml and write it to docs/__generated__/feature-completion-analysis.md.
+With --format json the analysis is printed to stdout as JSON instead; with
+--format html it is written as the roadmap page of the static site
+(docs/__generated__/site/roadmap.html unless --output is set).
 
 This command is part of CLI_COMMAND_STATUS and is used by governance tooling.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			featuresPath, err := cmd.Flags().GetString("features")
 			if err != nil {
-				return clierr.New(2, fmt.Sprintf("status roadmap: get features flag: %v", err))
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: get features flag: %v", err))
 			}
 
 			outputPath, err := cmd.Flags().GetString("output")
 			if err != nil {
-				return clierr.New(2, fmt.Sprintf("status roadmap: get output flag: %v", err))
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: get output flag: %v", err))
+			}
+
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: get format flag: %v", err))
+			}
+			if format != "markdown" && format != "json" && format != "html" {
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: invalid format %q (must be 'markdown', 'json' or 'html')", format))
 			}
 
 			// Resolve paths relative to repository root
 			repoRoot, err := projectroot.Find(".")
 			if err != nil {
-				return clierr.New(2, fmt.Sprintf("status roadmap: finding repo root: %v", err))
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: finding repo root: %v", err))
 			}
+			gen := reportindex.Begin(cmd, args, repoRoot)
 
 			if !filepath.IsAbs(featuresPath) {
 				featuresPath = filepath.Join(repoRoot, featuresPath)
 			}
+			if format == "html" && !cmd.Flags().Changed("output") {
+				outputPath = filepath.Join(site.Dir, roadmapPage)
+			}
 			if !filepath.IsAbs(outputPath) {
 				outputPath = filepath.Join(repoRoot, outputPath)
 			}
@@ -71,25 +91,47 @@ This command is part of CLI_COMMAND_STATUS and is used by governance tooling.`,
 			if err != nil {
 				// Check if it's a file not found error
 				if os.IsNotExist(err) {
-					return clierr.New(1, fmt.Sprintf("status roadmap: features file not found: %s", featuresPath))
+					return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: features file not found: %s", featuresPath))
 				}
-				// YAML parsing errors are validation errors (exit code 1)
-				return clierr.New(1, fmt.Sprintf("status roadmap: detect phases: %v", err))
+				// A features file that does not parse (or has invalid
+				// milestones) is a config error
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: detect phases: %v", err))
 			}
 
 			stats := roadmap2.CalculateStats(phases)
 			blockers := roadmap2.IdentifyBlockers(phases)
 
-			markdown := roadmap2.GenerateMarkdown(stats, blockers)
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(roadmap2.BuildReport(stats, blockers)); err != nil {
+					return clierr.New(clierr.ExitExecution, fmt.Sprintf("status roadmap: write JSON: %v", err))
+				}
+				return nil
+			}
+
+			set := templates.New(repoRoot)
+			markdown, err := roadmap2.GenerateMarkdownWith(set, stats, blockers)
+			if err != nil {
+				return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: %v", err))
+			}
+			if format == "html" {
+				if markdown, err = site.Render(set, site.Page{File: roadmapPage, Markdown: markdown}); err != nil {
+					return clierr.New(clierr.ExitConfig, fmt.Sprintf("status roadmap: %v", err))
+				}
+			}
 
 			// Ensure output directory exists
 			outputDir := filepath.Dir(outputPath)
 			if err := os.MkdirAll(outputDir, 0o750); err != nil {
-				return clierr.New(1, fmt.Sprintf("status roadmap: create output directory: %v", err))
+				return clierr.New(clierr.ExitExecution, fmt.Sprintf("status roadmap: create output directory: %v", err))
 			}
 
 			if err := os.WriteFile(outputPath, []byte(markdown), 0o600); err != nil {
-				return clierr.New(1, fmt.Sprintf("status roadmap: write output %q: %v", outputPath, err))
+				return clierr.New(clierr.ExitExecution, fmt.Sprintf("status roadmap: write output %q: %v", outputPath, err))
+			}
+			if err := gen.Record(repoRoot, reportindex.KindReport, outputPath); err != nil {
+				return clierr.New(clierr.ExitExecution, fmt.Sprintf("status roadmap: index output %q: %v", outputPath, err))
 			}
 
 			return nil
@@ -101,6 +143,11 @@ This command is part of CLI_COMMAND_STATUS and is used by governance tooling.`,
 		defaultFeaturesPath,
 		"path to spec/features.yaml",
 	)
+	cmd.Flags().String(
+		"format",
+		"markdown",
+		"output format: markdown or html (written to --output) or json (printed to stdout)",
+	)
 	cmd.Flags().String(
 		"output",
 		defaultOutputPath,
@@ -19,9 +19,13 @@ package reports
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	roadmap2 "github.com/bartekus/cortex/internal/reports/roadmap"
 )
 
 func TestStatusRoadmapCommand_ExecutesSuccessfully(t *testing.T) {
@@ -142,9 +146,9 @@ func TestStatusRoadmapCommand_HandlesMissingFeaturesYAML(t *testing.T) {
 		t.Error("status roadmap command expected error for missing features.yaml, got nil")
 	}
 
-	// Should exit with code 1 (validation error)
-	if exitCode := getExitCode(err); exitCode != 1 {
-		t.Errorf("expected exit code 1 (validation error), got %d", exitCode)
+	// A missing features file is a config error
+	if exitCode := getExitCode(err); exitCode != clierr.ExitConfig {
+		t.Errorf("expected exit code %d (config error), got %d", clierr.ExitConfig, exitCode)
 	}
 }
 
@@ -197,10 +201,9 @@ func TestStatusRoadmapCommand_HandlesInvalidYAML(t *testing.T) {
 		t.Error("status roadmap command expected error for invalid YAML, got nil")
 	}
 
-	// Should exit with code 1 (validation error) or 2 (internal error)
-	exitCode := getExitCode(err)
-	if exitCode != 1 && exitCode != 2 {
-		t.Errorf("expected exit code 1 or 2, got %d", exitCode)
+	// A malformed features file is a config error
+	if exitCode := getExitCode(err); exitCode != clierr.ExitConfig {
+		t.Errorf("expected exit code %d (config error), got %d", clierr.ExitConfig, exitCode)
 	}
 }
 
@@ -353,6 +356,88 @@ func TestStatusRoadmapCommand_OverwritesExistingFile(t *testing.T) {
 	}
 }
 
+func TestStatusRoadmapCommand_FormatJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	specDir := filepath.Join(tmpDir, "spec")
+	if err := os.MkdirAll(specDir, 0o750); err != nil {
+		t.Fatalf("failed to create spec directory: %v", err)
+	}
+
+	featuresYAML := `features:
+  - id: TEST_FEATURE
+    title: "Test feature"
+    implementation: done
+    phase: "Phase 0: Foundation"
+  - id: NEXT_FEATURE
+    title: "Next feature"
+    implementation: todo
+    phase: "Phase 1: Next"
+    depends_on: [MISSING]
+
+milestones:
+  - name: v1
+    target: 2026-03-31
+    phases: ["Phase 0: Foundation", "Phase 1: Next"]
+`
+	if err := os.WriteFile(filepath.Join(specDir, "features.yaml"), []byte(featuresYAML), 0o600); err != nil {
+		t.Fatalf("failed to write features.yaml: %v", err)
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(originalDir); err != nil {
+			t.Logf("failed to restore directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	cmd := NewReportsCommand()
+	cmd.SetArgs([]string{"status-roadmap", "--format", "json"})
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("status roadmap command failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	var report roadmap2.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout.String())
+	}
+	if report.Total != 2 || report.Done != 1 || len(report.Phases) != 2 || len(report.Blockers) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.Milestones) != 1 || report.Milestones[0].Target != "2026-03-31" || len(report.Milestones[0].Burndown) != 2 {
+		t.Errorf("unexpected milestones: %+v", report.Milestones)
+	}
+
+	// JSON goes to stdout only.
+	if _, err := os.Stat(filepath.Join(tmpDir, "docs", "__generated__", "feature-completion-analysis.md")); !os.IsNotExist(err) {
+		t.Errorf("--format json should not write the markdown report, stat err = %v", err)
+	}
+}
+
+func TestStatusRoadmapCommand_RejectsUnknownFormat(t *testing.T) {
+	cmd := NewReportsCommand()
+	cmd.SetArgs([]string{"status-roadmap", "--format", "yaml"})
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("status roadmap command expected error for unknown format, got nil")
+	}
+}
+
 // contains checks if substr is contained in s (case-sensitive).
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || substr == "" || findSubstring(s, substr))
@@ -367,13 +452,7 @@ func findSubstring(s, substr string) bool {
 	return false
 }
 
-// getExitCode extracts exit code from error (for testing purposes).
-// Returns 0 if error is nil, 1 for validation errors, 2 for internal errors.
+// getExitCode returns the exit code the CLI would exit with for err.
 func getExitCode(err error) int {
-	if err == nil {
-		return 0
-	}
-	// In a real implementation, this would check the error type
-	// For now, assume any error is a validation error (code 1)
-	return 1
+	return clierr.ExitCodeOf(err)
 }
@@ -15,6 +15,11 @@ See https://www.gnu.org/licenses/ for license details.
 package reports
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
 	"github.com/bartekus/cortex/internal/reports/commithealth"
 )
 
@@ -26,3 +31,38 @@ type fakeHistorySource struct {
 func (f fakeHistorySource) Commits() ([]commithealth.CommitMetadata, error) {
 	return f.commits, nil
 }
+
+// initTestRepo creates a git repository on branch main with files committed.
+func initTestRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	repoDir := t.TempDir()
+	gitTest(t, repoDir, "init", "-q", "-b", "main")
+	gitTest(t, repoDir, "config", "user.name", "Test User")
+	gitTest(t, repoDir, "config", "user.email", "test@example.com")
+	for rel, content := range files {
+		writeTestFile(t, repoDir, rel, content)
+	}
+	gitTest(t, repoDir, "add", "-A")
+	gitTest(t, repoDir, "commit", "-q", "-m", "chore: initial commit")
+	return repoDir
+}
+
+func writeTestFile(t *testing.T, repoDir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(repoDir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("creating dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", rel, err)
+	}
+}
+
+func gitTest(t *testing.T, repoDir string, args ...string) {
+	t.Helper()
+	c := exec.Command("git", args...)
+	c.Dir = repoDir
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
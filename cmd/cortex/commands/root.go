@@ -16,23 +16,26 @@ package commands
 
 import (
 	"fmt"
-	"os"
+	"log/slog"
 
 	"github.com/bartekus/cortex/cmd/cortex/commands/reports"
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
 	"github.com/spf13/cobra"
 
 	"github.com/bartekus/cortex/cmd/cortex/commands/context"
 	"github.com/bartekus/cortex/cmd/cortex/commands/features"
 	"github.com/bartekus/cortex/cmd/cortex/commands/gov"
+	"github.com/bartekus/cortex/cmd/cortex/commands/meta"
+	"github.com/bartekus/cortex/cmd/cortex/commands/snapshot"
+	"github.com/bartekus/cortex/cmd/cortex/commands/spec"
+	"github.com/bartekus/cortex/internal/i18n"
+	"github.com/bartekus/cortex/internal/log"
+	"github.com/bartekus/cortex/internal/version"
+	"github.com/bartekus/cortex/pkg/introspect"
 )
 
 // NewRootCmd constructs the Cortex root Cobra command.
 func NewRootCmd() *cobra.Command {
-	version := os.Getenv("CORTEX_VERSION")
-	if version == "" {
-		version = "0.0.0-dev"
-	}
-
 	cmd := &cobra.Command{
 		Use:           "cortex",
 		Short:         "Cortex - Developer & Governance Tooling for Cortex",
@@ -41,25 +44,84 @@ func NewRootCmd() *cobra.Command {
 		SilenceErrors: true,
 	}
 
+	// Flag parsing errors are usage errors.
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return clierr.Wrapf(clierr.ExitConfig, err, "%s", c.CommandPath())
+	})
+
 	// Global flags
+	cmd.PersistentFlags().String("log-format", log.FormatText, "log format: text or json")
+	cmd.PersistentFlags().String("log-level", "warn", "minimum log level: debug, info, warn or error")
 	cmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
 
+	// Every command may exit with any code of the taxonomy; commands with a
+	// narrower contract annotate their own.
+	var codes []int
+	for _, c := range clierr.Codes() {
+		codes = append(codes, c.Code)
+	}
+	introspect.SetExitCodes(cmd, codes...)
+
+	// Every command logs through the logger on its context. Subcommands with
+	// their own PersistentPreRunE (gov drift) still get it.
+	cobra.EnableTraverseRunHooks = true
+	cmd.PersistentPreRunE = setupLogger
+
 	// Version command
 	cmd.AddCommand(&cobra.Command{
 		Use:   "version",
 		Short: "Print the version number of Cortex",
 		Run: func(cmd *cobra.Command, args []string) {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cortex version %s\n", version)
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), i18n.Sprintf("Cortex version %s", version.String()))
 		},
 	})
 
 	// Register existing context commands
 	// Note: We register NewContextCommand which provides subcommands like build, docs, xray.
+	cmd.AddCommand(NewConfigCommand())
 	cmd.AddCommand(context.NewContextCommand())
+	cmd.AddCommand(NewDocsCommand())
+	cmd.AddCommand(NewDoctorCommand())
+	cmd.AddCommand(NewExplainCommand())
 	cmd.AddCommand(features.NewFeaturesCommand())
+	cmd.AddCommand(NewFingerprintCommand())
 	cmd.AddCommand(reports.NewReportsCommand())
 	cmd.AddCommand(gov.NewGovCommand())
+	cmd.AddCommand(NewGrepCommand())
+	cmd.AddCommand(NewInitCommand())
+	cmd.AddCommand(meta.NewMetaCommand())
+	cmd.AddCommand(NewPackCommand())
+	cmd.AddCommand(NewRulesCommand())
 	cmd.AddCommand(GetRunCmd())
+	cmd.AddCommand(NewServeCommand())
+	cmd.AddCommand(snapshot.NewSnapshotCommand())
+	cmd.AddCommand(spec.NewSpecCommand())
+	cmd.AddCommand(NewStatusCommand())
+	cmd.AddCommand(NewSyncCommand())
+	cmd.AddCommand(NewToolsCommand())
+	cmd.AddCommand(NewUnpackCommand())
 
 	return cmd
 }
+
+// setupLogger puts the logger configured by the global flags on the
+// command's context. --verbose lowers the level to debug.
+func setupLogger(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("log-format")
+	levelName, _ := cmd.Flags().GetString("log-level")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	level, err := log.ParseLevel(levelName)
+	if err != nil {
+		return clierr.New(clierr.ExitConfig, err.Error())
+	}
+	if verbose {
+		level = min(level, slog.LevelDebug)
+	}
+	logger, err := log.New(cmd.ErrOrStderr(), log.Options{Level: level, Format: format})
+	if err != nil {
+		return clierr.New(clierr.ExitConfig, err.Error())
+	}
+	cmd.SetContext(log.WithContext(cmd.Context(), logger))
+	return nil
+}
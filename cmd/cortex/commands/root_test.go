@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/pkg/gov"
 )
 
 func TestMain(m *testing.M) {
@@ -35,3 +38,12 @@ func TestCortexHelp_Golden(t *testing.T) {
 	expected := readGoldenFile(t, goldenName)
 	assert.Equal(t, expected, output, "help output does not match golden file")
 }
+
+func TestCommandHelp_Golden(t *testing.T) {
+	const dir = "../../../spec/fixtures/cli"
+	pages := gov.WalkHelp(NewRootCmd())
+	if *updateGolden {
+		require.NoError(t, gov.WriteHelpTree(pages, dir))
+	}
+	assert.NoError(t, gov.CompareHelpTree(pages, dir, gov.DefaultDiffOptions()), "help fixtures drifted; regenerate with `go test ./cmd/cortex/commands -run Golden -update`")
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/rules"
+)
+
+// Feature: CLI_COMMAND_RULES
+// Spec: spec/cli/rules.md
+
+// NewRulesCommand returns the `cortex rules` command.
+func NewRulesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "List the rules skills check",
+		Long: "Lists the registry of rules: every check a skill can report findings for, with its ID, description, " +
+			"default severity, whether a command fixes it and the skill that owns it. Rule IDs name findings " +
+			"(cortex run report), SARIF and Code Climate results and cortex:ignore suppressions.",
+	}
+	cmd.AddCommand(newRulesListCommand())
+	cmd.AddCommand(newRulesShowCommand())
+	return cmd
+}
+
+func newRulesListCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List every rule",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			all := rules.All()
+			out := cmd.OutOrStdout()
+			if asJSON {
+				return encodeRules(out, all)
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "ID\tSEVERITY\tFIXABLE\tDESCRIPTION")
+			for _, r := range all {
+				fixable := "no"
+				if r.Fixable {
+					fixable = "yes"
+				}
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.ID, r.Severity, fixable, r.Description)
+			}
+			return tw.Flush()
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the rules as JSON")
+
+	return cmd
+}
+
+func newRulesShowCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "show <rule>",
+		Short: "Show one rule",
+		Long:  "Shows a rule with its rationale, the spec section it enforces, compliant examples and its fix. A module variant of a skill (test:go@services/api) shows the rule of the skill.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, ok := rules.Lookup(args[0])
+			if !ok {
+				return clierr.Newf(clierr.ExitConfig, "rules show: unknown rule %q", args[0])
+			}
+			if asJSON {
+				return encodeRules(cmd.OutOrStdout(), r)
+			}
+			printRule(cmd.OutOrStdout(), r)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the rule as JSON")
+
+	return cmd
+}
+
+func encodeRules(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -5,12 +5,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/fingerprint"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/log"
 	"github.com/bartekus/cortex/internal/projectroot"
 	"github.com/bartekus/cortex/internal/runner"
 	"github.com/bartekus/cortex/internal/scanner"
@@ -25,19 +36,31 @@ var (
 	runStateDir      string
 	runFailOnWarning bool
 	runFiles0        bool
+	runForce         bool
+	runStaged        bool
 )
 
 var runCmd = &cobra.Command{
-	Use:   "run <command|skill> [flags]",
+	Use:   "run <command|skill> [flags] [-- paths...]",
 	Short: "Orchestrate Cortex skills and governance checks",
 	Long: `Deterministiacally run skills, tests, and governance checks.
-Maintains state in .cortex/run to allow resuming failures.`,
+Maintains state in .cortex/run to allow resuming failures.
+
+Paths after -- (files, directories or globs) or --staged restrict the skills
+that check individual files to those tracked files.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		skillIDs, paths := args, []string(nil)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			skillIDs, paths = args[:dash], args[dash:]
+		}
+		if len(skillIDs) == 0 {
+			if len(paths) > 0 || runStaged {
+				return clierr.New(clierr.ExitConfig, "paths and --staged need a skill to run")
+			}
 			return cmd.Help()
 		}
 		// If argument is not a subcommand, treat it as a skill name
-		return runSkill(cmd.Context(), args)
+		return runSkill(cmd.Context(), skillIDs, paths)
 	},
 }
 
@@ -46,12 +69,32 @@ func init() {
 	runCmd.PersistentFlags().StringVar(&runStateDir, "state-dir", ".cortex/run", "Directory to store run state")
 	runCmd.PersistentFlags().BoolVar(&runFailOnWarning, "fail-on-warning", false, "Fail if warnings occur")
 	runCmd.PersistentFlags().BoolVar(&runFiles0, "files0", false, "Read NULL-delimited file list from stdin")
+	runCmd.PersistentFlags().BoolVar(&runForce, "force", false, "Run even if another cortex run holds the state directory")
+	runCmd.Flags().BoolVar(&runStaged, "staged", false, "Check only the files staged in the git index")
+
+	// Flags in alphabetical order for deterministic help output
+	runAllCmd.Flags().StringVar(&runAllContinueFrom, "continue-from", "", "Start at this skill instead of the first")
+	runAllCmd.Flags().BoolVar(&runAllFailFast, "fail-fast", false, "Stop at the first failed skill")
+	runAllCmd.Flags().BoolVar(&runAllOnlyFailed, "only-failed", false, "Re-run only the failed and not-run skills of the last run (like resume)")
+
+	runReportCmd.Flags().StringVar(&runReportArtifactsURL, "artifacts-url", "", "Base URL of the CI artifacts, linked from --format markdown-summary")
+	runReportCmd.Flags().StringVar(&runReportBaseCoverage, "base-coverage", "", "Coverage profile of the base for the markdown-summary coverage delta")
+	runReportCmd.Flags().StringVar(&runReportBaseline, "baseline", "", "Code Climate JSON of the base (report --format codeclimate) to list new findings against")
+	runReportCmd.Flags().StringVar(&runReportCoverage, "coverage", "", "Coverage profile for markdown-summary (default: <state-dir>/coverage.out)")
+	runReportCmd.Flags().BoolVar(&runReportExitCodes, "exit-codes", false, "Print the exit code mapping instead of the last run")
+	runReportCmd.Flags().StringVar(&runReportFormat, "format", "text", "Output format: text, json, codeclimate, sarif or markdown-summary")
+	runReportCmd.Flags().BoolVar(&runReportTiming, "timing", false, "Print per-skill duration and resource limits of the last run")
 
 	runCmd.AddCommand(runListCmd)
 	runCmd.AddCommand(runAllCmd)
 	runCmd.AddCommand(runResumeCmd)
 	runCmd.AddCommand(runReportCmd)
 	runCmd.AddCommand(runResetCmd)
+	runCmd.AddCommand(runPublishCmd)
+	runCmd.AddCommand(runHistoryCmd)
+	runCmd.AddCommand(runShowCmd)
+	runCmd.AddCommand(runDiffCmd)
+	runCmd.AddCommand(runGraphCmd)
 
 	// Register with root (assuming rootCmd exists in package, but usually it's passed or init-ed)
 	// We'll export RunCmd or similar?
@@ -64,6 +107,52 @@ func GetRunCmd() *cobra.Command {
 	return runCmd
 }
 
+// readLastRunFindings loads the last run, its skill results and the findings
+// of failed skills. Findings on paths that are not tracked files are kept
+// without a location so consumers can safely anchor the rest to the tree.
+// last is nil when there is no run state.
+func readLastRunFindings(ctx context.Context, wd string) (*runner.LastRun, []runner.SkillResult, []findings.Finding, error) {
+	repoRoot, err := projectroot.Find(wd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	store, err := resolveStateStore(wd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	last, err := store.ReadLastRun()
+	if err != nil || last == nil {
+		return nil, nil, nil, err
+	}
+
+	var results []runner.SkillResult
+	for _, id := range last.Skills {
+		res, err := store.ReadSkill(id)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if res != nil {
+			results = append(results, *res)
+		}
+	}
+
+	tracked, err := scanner.New(repoRoot).TrackedFiles(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	known := make(map[string]bool, len(tracked))
+	for _, f := range tracked {
+		known[f] = true
+	}
+	fs := findings.FromResults(repoRoot, results)
+	for i, f := range fs {
+		if f.Path != "" && !known[f.Path] {
+			fs[i].Message, fs[i].Path, fs[i].Line = f.Path+": "+f.Message, "", 0
+		}
+	}
+	return last, results, fs, nil
+}
+
 func resolveStateStore(wd string) (*runner.StateStore, error) {
 	repoRoot, err := projectroot.Find(wd)
 	if err != nil {
@@ -76,7 +165,13 @@ func resolveStateStore(wd string) (*runner.StateStore, error) {
 	return runner.NewStateStore(stateDir), nil
 }
 
-func setupRunner(ctx context.Context) (*runner.Runner, error) {
+// errNoTargetFiles is returned by setupRunner when paths or --staged select
+// no file, so there is nothing to check.
+var errNoTargetFiles = errors.New("no files selected")
+
+// setupRunner builds the runner. paths, resolved against the working
+// directory, and --staged add to the files --files0 selects.
+func setupRunner(ctx context.Context, paths ...string) (*runner.Runner, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -133,15 +228,73 @@ func setupRunner(ctx context.Context) (*runner.Runner, error) {
 		}
 	}
 
+	if len(paths) > 0 || runStaged {
+		selected, err := selectTargetFiles(ctx, wd, repoRoot, scn, paths)
+		if err != nil {
+			return nil, err
+		}
+		if len(selected) == 0 {
+			return nil, errNoTargetFiles
+		}
+		targetFiles = append(targetFiles, selected...)
+	}
+
+	// Outside a git checkout history records simply carry no commit or
+	// fingerprint.
+	commit, _ := git.RevParse(ctx, repoRoot, "HEAD")
+	var fpDigest string
+	if fp, err := fingerprint.Compute(ctx, repoRoot); err == nil {
+		fpDigest = fp.Digest()
+	}
+
 	deps := &runner.Deps{
 		RepoRoot:      repoRoot,
 		StateDir:      stateDir,
 		Scanner:       scn,
 		FailOnWarning: runFailOnWarning,
+		Commit:        commit,
+		Fingerprint:   fpDigest,
 		TargetFiles:   targetFiles,
+		Log:           log.FromContext(ctx),
+	}
+
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitConfig, "run", err)
+	}
+	tracked, err := scn.TrackedFiles(ctx)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitExecution, "run", err)
+	}
+	nested, err := config.LoadNested(repoRoot, tracked)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitConfig, "run", err)
+	}
+	registry, err := moduleRegistry(ctx, scn)
+	if err != nil {
+		return nil, err
 	}
 
-	return runner.NewRunner(skills.Registry, store, deps), nil
+	r := runner.NewRunner(registry, store, deps)
+	if err := r.ApplyConfig(cfg.Skills); err != nil {
+		return nil, clierr.New(clierr.ExitConfig, err.Error())
+	}
+	gen := findings.NewGeneratedFilter(repoRoot, scanner.NewGenerated(repoRoot, cfg.Files.Generated))
+	sup := findings.NewSuppressor(repoRoot, time.Now())
+	overrides := findings.NewOverrides(repoRoot, cfg.Rules, nested, runFailOnWarning)
+	r.SetResultFilter(func(res runner.SkillResult) runner.SkillResult { return overrides.Apply(sup.Apply(gen.Apply(res))) })
+	r.SetForce(runForce)
+	return r, nil
+}
+
+// moduleRegistry returns the skills registry with the per-module skills
+// expanded for every Go module of the repository.
+func moduleRegistry(ctx context.Context, scn *scanner.Scanner) ([]runner.Skill, error) {
+	mods, err := scn.Modules(ctx)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitConfig, "discovering Go modules", err)
+	}
+	return skills.ForModules(skills.Registry, mods), nil
 }
 
 type SkillListItem struct {
@@ -152,8 +305,21 @@ var runListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available skills",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		list := make([]SkillListItem, 0, len(skills.Registry))
-		for _, s := range skills.Registry {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		repoRoot, err := projectroot.Find(wd)
+		if err != nil {
+			return err
+		}
+		registry, err := moduleRegistry(cmd.Context(), scanner.New(repoRoot))
+		if err != nil {
+			return err
+		}
+
+		list := make([]SkillListItem, 0, len(registry))
+		for _, s := range registry {
 			list = append(list, SkillListItem{ID: s.ID()})
 		}
 
@@ -175,15 +341,42 @@ var runListCmd = &cobra.Command{
 	},
 }
 
+var (
+	runAllContinueFrom string
+	runAllFailFast     bool
+	runAllOnlyFailed   bool
+)
+
 var runAllCmd = &cobra.Command{
 	Use:   "all",
 	Short: "Run all skills",
+	Long: `Runs every skill in order, continuing after failures.
+
+--fail-fast stops at the first failed skill; the skills it did not reach are
+recorded in the last run and run by resume. --continue-from starts at a
+skill. --only-failed re-runs the failed and not-run skills of the last run,
+like resume. The mode is recorded in the last run and shown by report.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if runAllContinueFrom != "" && runAllOnlyFailed {
+			return clierr.New(clierr.ExitConfig, "--continue-from and --only-failed cannot be combined")
+		}
 		r, err := setupRunner(cmd.Context())
 		if err != nil {
 			return err
 		}
-		return r.RunAll(cmd.Context())
+		r.SetFailFast(runAllFailFast)
+		switch {
+		case runAllOnlyFailed:
+			return r.Resume(cmd.Context())
+		case runAllContinueFrom != "":
+			err = r.RunAllFrom(cmd.Context(), runAllContinueFrom)
+			if errors.Is(err, runner.ErrSkillNotFound) {
+				return clierr.Wrap(clierr.ExitConfig, "--continue-from", err)
+			}
+			return err
+		default:
+			return r.RunAll(cmd.Context())
+		}
 	},
 }
 
@@ -211,39 +404,106 @@ var runResetCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		return store.Reset()
+		unlock, err := store.Lock(runner.RunLock{PID: os.Getpid(), Started: time.Now().UTC()}, runForce)
+		if err != nil {
+			return err
+		}
+		if err := store.Reset(); err != nil {
+			_ = unlock()
+			return err
+		}
+		return unlock()
 	},
 }
 
+var (
+	runReportExitCodes bool
+	runReportFormat    string
+	runReportTiming    bool
+)
+
 var runReportCmd = &cobra.Command{
 	Use:   "report",
 	Short: "Show last run status",
+	Long: `Shows the status of the last run.
+
+Formats:
+  text              status, failed skills and their findings with IDs (default)
+  json              the last run summary (same as --json)
+  codeclimate       Code Climate / GitLab code quality JSON of failed skill findings
+  sarif             SARIF 2.1.0 of failed skill findings with the rules they violate
+  markdown-summary  pull request comment: skill status table, findings new against
+                    --baseline, coverage delta and links to artifacts
+
+With --exit-codes, prints the exit code mapping used by skills and commands
+instead (text or json). With --timing, prints each skill's duration, attempts
+and resource limits from the last run (text or json).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		format := runReportFormat
+		if runJSON {
+			format = "json"
+		}
+		if format != "text" && format != "json" && format != "codeclimate" && format != "sarif" && format != "markdown-summary" {
+			return clierr.Newf(clierr.ExitConfig, "unknown format %q (want text, json, codeclimate, sarif or markdown-summary)", format)
+		}
+
+		if runReportExitCodes {
+			return printExitCodes(format)
+		}
+		if runReportTiming {
+			return printTiming(format)
+		}
+
 		wd, err := os.Getwd()
 		if err != nil {
 			return err
 		}
-		store, err := resolveStateStore(wd)
-		if err != nil {
-			return err
+
+		if format == "markdown-summary" {
+			return printRunSummary(cmd.Context(), wd, os.Stdout)
 		}
-		last, err := store.ReadLastRun()
-		if err != nil {
-			return err
+		if format == "codeclimate" || format == "sarif" {
+			_, _, fs, err := readLastRunFindings(cmd.Context(), wd)
+			if err != nil {
+				return err
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if format == "sarif" {
+				return encoder.Encode(findings.SARIF(fs))
+			}
+			return encoder.Encode(findings.CodeClimate(fs))
 		}
 
-		if runJSON {
+		if format == "json" {
+			store, err := resolveStateStore(wd)
+			if err != nil {
+				return err
+			}
+			last, err := store.ReadLastRun()
+			if err != nil {
+				return err
+			}
 			encoder := json.NewEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			return encoder.Encode(last)
 		}
 
+		last, _, fs, err := readLastRunFindings(cmd.Context(), wd)
+		if err != nil {
+			return err
+		}
+
 		if last == nil {
 			fmt.Println("No run state found.")
 			return nil
 		}
 
 		fmt.Printf("Status: %s\n", last.Status)
+		if last.Mode != nil {
+			fmt.Printf("Mode: %s\n", describeRunMode(*last.Mode))
+		}
 		if len(last.Failed) > 0 {
 			fmt.Println("Failed:")
 			for _, f := range last.Failed {
@@ -252,12 +512,120 @@ var runReportCmd = &cobra.Command{
 		} else {
 			fmt.Println("All passed.")
 		}
+		if len(fs) > 0 {
+			fmt.Println("Findings (cortex explain <id> for the rule):")
+			for _, f := range fs {
+				fmt.Printf("  - %s  %s\n", f.ID(), describeFinding(f))
+			}
+		}
+		if len(last.NotRun) > 0 {
+			fmt.Println("Not run (fail-fast; resume runs them):")
+			for _, id := range last.NotRun {
+				fmt.Printf("  - %s\n", id)
+			}
+		}
+		if len(last.Flaky) > 0 {
+			fmt.Println("Flaky (passed on retry):")
+			for _, f := range last.Flaky {
+				fmt.Printf("  - %s\n", f)
+			}
+		}
+		if aggs := runner.AggregateModules(last); len(aggs) > 0 {
+			fmt.Println("Modules:")
+			for _, a := range aggs {
+				fmt.Printf("  - %s: %d/%d passed", a.Skill, a.Modules-len(a.Failed), a.Modules)
+				if len(a.Failed) > 0 {
+					fmt.Printf(" (failed: %s)", strings.Join(a.Failed, ", "))
+				}
+				fmt.Println()
+			}
+		}
 		return nil
 	},
 }
 
-func runSkill(ctx context.Context, skillIDs []string) error {
-	r, err := setupRunner(ctx)
+func printExitCodes(format string) error {
+	codes := clierr.Codes()
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(codes)
+	case "text":
+		for _, c := range codes {
+			fmt.Printf("%d  %-10s  %s\n", c.Code, c.Name, c.Description)
+		}
+		return nil
+	default:
+		return clierr.Newf(clierr.ExitConfig, "--exit-codes supports text or json, not %q", format)
+	}
+}
+
+// SkillTiming is one row of `run report --timing`.
+type SkillTiming struct {
+	Skill      string             `json:"skill"`
+	Status     runner.SkillStatus `json:"status"`
+	DurationMS int64              `json:"duration_ms"`
+	Attempts   int                `json:"attempts"`
+	Limits     string             `json:"limits,omitempty"`
+}
+
+func printTiming(format string) error {
+	if format != "text" && format != "json" {
+		return clierr.Newf(clierr.ExitConfig, "--timing supports text or json, not %q", format)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	_, results, _, err := readLastRunFindings(context.Background(), wd)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]SkillTiming, 0, len(results))
+	var total int64
+	for _, res := range results {
+		rows = append(rows, SkillTiming{
+			Skill:      res.Skill,
+			Status:     res.Status,
+			DurationMS: res.DurationMS,
+			Attempts:   max(res.Attempts, 1),
+			Limits:     res.Limits,
+		})
+		total += res.DurationMS
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Println("No run state found.")
+		return nil
+	}
+	for _, row := range rows {
+		limits := row.Limits
+		if limits == "" {
+			limits = "-"
+		}
+		fmt.Printf("%-28s  %-7s  %9s  %d  %s\n", row.Skill, row.Status, formatMS(row.DurationMS), row.Attempts, limits)
+	}
+	fmt.Printf("%-28s  %-7s  %9s\n", "total", "", formatMS(total))
+	return nil
+}
+
+func formatMS(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(10 * time.Millisecond).String()
+}
+
+func runSkill(ctx context.Context, skillIDs, paths []string) error {
+	r, err := setupRunner(ctx, paths...)
+	if errors.Is(err, errNoTargetFiles) {
+		fmt.Println("No files selected; nothing to check.")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -266,3 +634,70 @@ func runSkill(ctx context.Context, skillIDs []string) error {
 	// Runner.RunList handles it
 	return r.RunList(ctx, skillIDs)
 }
+
+// selectTargetFiles returns the tracked files that paths, relative to wd,
+// and with --staged the staged files select, sorted. A path outside the
+// repository or matching no tracked file is a config error.
+func selectTargetFiles(ctx context.Context, wd, repoRoot string, scn *scanner.Scanner, paths []string) ([]string, error) {
+	patterns := make([]string, 0, len(paths))
+	args := make(map[string]string, len(paths)) // pattern -> argument
+	for _, arg := range paths {
+		p := arg
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(wd, p)
+		}
+		rel, ok := scanner.RelPath(repoRoot, p)
+		if !ok {
+			return nil, clierr.Newf(clierr.ExitConfig, "%s is outside the repository", arg)
+		}
+		patterns = append(patterns, rel)
+		args[rel] = arg
+	}
+
+	tracked, err := scn.TrackedFiles(ctx)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.ExitExecution, "listing tracked files", err)
+	}
+	selected, unmatched := scanner.SelectFiles(tracked, patterns)
+	if len(unmatched) > 0 {
+		for i, p := range unmatched {
+			unmatched[i] = args[p]
+		}
+		return nil, clierr.Newf(clierr.ExitConfig, "no tracked files match %s", strings.Join(unmatched, ", "))
+	}
+
+	if runStaged {
+		changes, err := git.StagedChanges(ctx, repoRoot)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.ExitExecution, "listing staged files", err)
+		}
+		isTracked := make(map[string]bool, len(tracked))
+		for _, f := range tracked {
+			isTracked[f] = true
+		}
+		// Deleted files are staged but no longer tracked.
+		for _, c := range changes {
+			if isTracked[c.Path] && !slices.Contains(selected, c.Path) {
+				selected = append(selected, c.Path)
+			}
+		}
+		sort.Strings(selected)
+	}
+	return selected, nil
+}
+
+// describeRunMode lists the options of a run mode, e.g.
+// "fail-fast, continue-from lint:gofumpt".
+func describeRunMode(m runner.RunMode) string {
+	var parts []string
+	if m.FailFast {
+		parts = append(parts, "fail-fast")
+	}
+	if m.ContinueFrom != "" {
+		parts = append(parts, "continue-from "+m.ContinueFrom)
+	}
+	if m.OnlyFailed {
+		parts = append(parts, "only-failed")
+	}
+	return strings.Join(parts, ", ")
+}
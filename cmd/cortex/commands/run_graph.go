@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+var (
+	runGraphDOT     bool
+	runGraphMermaid bool
+)
+
+var runGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Render the skill run flow with last-run status",
+	Long: `Renders the skills in the order run all executes them, colored by their status
+in the last run (pass, fail, timeout, skip or not run). Skipped and failed
+skills show the first line of their note, and the module variants of a skill
+are grouped. Output is Graphviz DOT (default, --dot) or a Mermaid flowchart
+(--mermaid).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if runGraphDOT && runGraphMermaid {
+			return clierr.New(clierr.ExitConfig, "--dot and --mermaid are mutually exclusive")
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		repoRoot, err := projectroot.Find(wd)
+		if err != nil {
+			return err
+		}
+		registry, err := moduleRegistry(cmd.Context(), scanner.New(repoRoot))
+		if err != nil {
+			return err
+		}
+		store, err := resolveStateStore(wd)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(registry))
+		for _, s := range registry {
+			ids = append(ids, s.ID())
+		}
+		nodes, err := runner.Flow(ids, store)
+		if err != nil {
+			return err
+		}
+
+		if runGraphMermaid {
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), runner.FlowMermaid(nodes))
+			return nil
+		}
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), runner.FlowDOT(nodes))
+		return nil
+	},
+}
+
+func init() {
+	// Flags in alphabetical order for deterministic help output
+	runGraphCmd.Flags().BoolVar(&runGraphDOT, "dot", false, "Output Graphviz DOT (default)")
+	runGraphCmd.Flags().BoolVar(&runGraphMermaid, "mermaid", false, "Output a Mermaid flowchart")
+}
@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+var runHistoryFlaky bool
+
+var runHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List retained runs, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := historyStore()
+		if err != nil {
+			return err
+		}
+		runs, err := store.ListRuns()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if runHistoryFlaky {
+			return printFlakiness(out, runner.Flakiness(runs))
+		}
+		if runJSON {
+			if runs == nil {
+				runs = []runner.RunRecord{}
+			}
+			return encodeJSON(out, runs)
+		}
+		if len(runs) == 0 {
+			_, _ = fmt.Fprintln(out, "No run history found.")
+			return nil
+		}
+		for _, rec := range runs {
+			counts := map[runner.SkillStatus]int{}
+			for _, res := range rec.Results {
+				counts[res.Status]++
+			}
+			_, _ = fmt.Fprintf(out, "%s  %s  %-7s  %-4s  %d passed, %d failed, %d skipped",
+				rec.ID, formatCreatedAt(rec.CreatedAt), shortCommit(rec.Commit), rec.Status,
+				counts[runner.StatusPass], counts[runner.StatusFail]+counts[runner.StatusTimeout], counts[runner.StatusSkip])
+			if n := countFlaky(rec.Results); n > 0 {
+				_, _ = fmt.Fprintf(out, ", %d flaky", n)
+			}
+			_, _ = fmt.Fprintln(out)
+		}
+		return nil
+	},
+}
+
+func init() {
+	runHistoryCmd.Flags().BoolVar(&runHistoryFlaky, "flaky", false, "Show per-skill flakiness scores across the history instead of runs")
+}
+
+func printFlakiness(out io.Writer, scores []runner.FlakyScore) error {
+	if runJSON {
+		return encodeJSON(out, scores)
+	}
+	if len(scores) == 0 {
+		_, _ = fmt.Fprintln(out, "No run history found.")
+		return nil
+	}
+	for _, sc := range scores {
+		_, _ = fmt.Fprintf(out, "%5.1f%%  %d/%d  %s\n", 100*sc.Score, sc.Flaky, sc.Runs, sc.Skill)
+	}
+	return nil
+}
+
+func countFlaky(results []runner.SkillResult) int {
+	n := 0
+	for _, res := range results {
+		if res.Flaky {
+			n++
+		}
+	}
+	return n
+}
+
+var runShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a retained run",
+	Long:  "Shows the skill results of a run from the history. The ID may be abbreviated to any unique prefix.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := historyStore()
+		if err != nil {
+			return err
+		}
+		rec, err := store.ReadRun(args[0])
+		if err != nil {
+			return clierr.Wrap(clierr.ExitConfig, "run show", err)
+		}
+
+		out := cmd.OutOrStdout()
+		if runJSON {
+			return encodeJSON(out, rec)
+		}
+		_, _ = fmt.Fprintf(out, "Run:     %s\n", rec.ID)
+		_, _ = fmt.Fprintf(out, "Commit:  %s\n", orNone(rec.Commit))
+		_, _ = fmt.Fprintf(out, "Created: %s\n", formatCreatedAt(rec.CreatedAt))
+		_, _ = fmt.Fprintf(out, "Status:  %s\n", rec.Status)
+		for _, res := range rec.Results {
+			_, _ = fmt.Fprintf(out, "  %-4s  %s", res.Status, res.Skill)
+			if res.Status.Failed() {
+				_, _ = fmt.Fprintf(out, " (exit %d)", res.ExitCode)
+			}
+			if res.Attempts > 1 {
+				_, _ = fmt.Fprintf(out, " [%d attempts", res.Attempts)
+				if res.Flaky {
+					_, _ = fmt.Fprint(out, ", flaky")
+				}
+				_, _ = fmt.Fprint(out, "]")
+			}
+			_, _ = fmt.Fprintln(out)
+		}
+		return nil
+	},
+}
+
+var runDiffCmd = &cobra.Command{
+	Use:   "diff <id1> <id2>",
+	Short: "Compare two retained runs and list regressed skills",
+	Long: `Compares the skill statuses of run id1 (older) and id2 (newer).
+Exits with code 1 when any skill regressed, i.e. fails in id2 but did not in id1.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := historyStore()
+		if err != nil {
+			return err
+		}
+		from, err := store.ReadRun(args[0])
+		if err != nil {
+			return clierr.Wrap(clierr.ExitConfig, "run diff", err)
+		}
+		to, err := store.ReadRun(args[1])
+		if err != nil {
+			return clierr.Wrap(clierr.ExitConfig, "run diff", err)
+		}
+
+		changes := runner.DiffRuns(*from, *to)
+		var regressed []string
+		for _, c := range changes {
+			if c.Kind == runner.ChangeRegressed {
+				regressed = append(regressed, c.Skill)
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if runJSON {
+			if changes == nil {
+				changes = []runner.SkillChange{}
+			}
+			if err := encodeJSON(out, changes); err != nil {
+				return err
+			}
+		} else if len(changes) == 0 {
+			_, _ = fmt.Fprintf(out, "No skill status changes between %s and %s\n", from.ID, to.ID)
+		} else {
+			for _, c := range changes {
+				_, _ = fmt.Fprintf(out, "%-9s  %s: %s -> %s\n", c.Kind, c.Skill, orNone(string(c.From)), orNone(string(c.To)))
+			}
+		}
+
+		if len(regressed) > 0 {
+			return clierr.Newf(clierr.ExitValidation, "%d skill(s) regressed: %v", len(regressed), regressed)
+		}
+		return nil
+	},
+}
+
+func historyStore() (*runner.StateStore, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return resolveStateStore(wd)
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func shortCommit(sha string) string {
+	if sha == "" {
+		return "-"
+	}
+	return sha[:min(len(sha), 7)]
+}
+
+func formatCreatedAt(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.Format(time.RFC3339)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/github"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+var (
+	publishAPIURL string
+	publishDryRun bool
+	publishGitHub bool
+	publishName   string
+	publishRepo   string
+	publishSHA    string
+)
+
+var runPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish last run results as GitHub check annotations",
+	Long: `Creates a GitHub check run from the last run: failed skills become
+annotations on the files and lines they report, so they appear inline on pull
+request diffs. The token is read from GITHUB_TOKEN; --repo, --sha and --api-url
+default to GITHUB_REPOSITORY, GITHUB_SHA (else HEAD) and GITHUB_API_URL.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !publishGitHub {
+			return errors.New("no publish target: pass --github")
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		repoRoot, err := projectroot.Find(wd)
+		if err != nil {
+			return err
+		}
+		last, results, fs, err := readLastRunFindings(cmd.Context(), wd)
+		if err != nil {
+			return err
+		}
+		if last == nil {
+			return errors.New("no run state found; run `cortex run all` first")
+		}
+
+		run := github.CheckRun{
+			Name:       publishName,
+			HeadSHA:    orEnv(publishSHA, "GITHUB_SHA"),
+			Conclusion: "success",
+			Output:     github.NewOutput(results, fs),
+		}
+		if last.Status != "pass" {
+			run.Conclusion = "failure"
+		}
+		if run.HeadSHA == "" {
+			if run.HeadSHA, err = git.RevParse(cmd.Context(), repoRoot, "HEAD"); err != nil {
+				return err
+			}
+		}
+		annotations := github.Annotations(fs)
+
+		if publishDryRun {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			for _, batch := range github.Batches(annotations) {
+				req := run
+				req.Output.Annotations = batch
+				if err := enc.Encode(req); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		client := &github.Client{
+			BaseURL: orEnv(publishAPIURL, "GITHUB_API_URL"),
+			Token:   os.Getenv("GITHUB_TOKEN"),
+			Repo:    orEnv(publishRepo, "GITHUB_REPOSITORY"),
+		}
+		if client.BaseURL == "" {
+			client.BaseURL = github.DefaultAPIURL
+		}
+		if client.Repo == "" {
+			return errors.New("repository unknown: pass --repo or set GITHUB_REPOSITORY")
+		}
+		if client.Token == "" {
+			return errors.New("GITHUB_TOKEN is not set")
+		}
+
+		url, err := client.Publish(cmd.Context(), run, annotations)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Published %d annotation(s) to %s\n", len(annotations), url)
+		return nil
+	},
+}
+
+func init() {
+	// Flags in alphabetical order for deterministic help output
+	runPublishCmd.Flags().StringVar(&publishAPIURL, "api-url", "", "GitHub API URL (default: $GITHUB_API_URL or https://api.github.com)")
+	runPublishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false, "Print the check run requests as JSON instead of sending them")
+	runPublishCmd.Flags().BoolVar(&publishGitHub, "github", false, "Publish to the GitHub Checks API")
+	runPublishCmd.Flags().StringVar(&publishName, "name", "cortex", "Check run name")
+	runPublishCmd.Flags().StringVar(&publishRepo, "repo", "", "Repository as owner/name (default: $GITHUB_REPOSITORY)")
+	runPublishCmd.Flags().StringVar(&publishSHA, "sha", "", "Commit to attach the check run to (default: $GITHUB_SHA or HEAD)")
+}
+
+func orEnv(v, key string) string {
+	if v != "" {
+		return v
+	}
+	return os.Getenv(key)
+}
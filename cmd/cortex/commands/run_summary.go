@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/reports/prsummary"
+	"github.com/bartekus/cortex/internal/reports/runsummary"
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+var (
+	runReportArtifactsURL string
+	runReportBaseCoverage string
+	runReportBaseline     string
+	runReportCoverage     string
+)
+
+// printRunSummary writes the markdown-summary of the last run to out.
+func printRunSummary(ctx context.Context, wd string, out io.Writer) error {
+	repoRoot, err := projectroot.Find(wd)
+	if err != nil {
+		return err
+	}
+	last, results, found, err := readLastRunFindings(ctx, wd)
+	if err != nil {
+		return err
+	}
+	in := runsummary.Input{Last: last, Skills: results, Findings: found, ArtifactsURL: runReportArtifactsURL}
+
+	if runReportBaseline != "" {
+		data, err := os.ReadFile(runReportBaseline) //nolint:gosec // G304: path is from a CLI flag
+		if err != nil {
+			return clierr.Wrap(clierr.ExitConfig, "reading baseline", err)
+		}
+		in.Baseline = []findings.CodeClimateIssue{}
+		if err := json.Unmarshal(data, &in.Baseline); err != nil {
+			return clierr.Wrapf(clierr.ExitConfig, err, "parsing baseline %s", runReportBaseline)
+		}
+	}
+
+	stateDir := runStateDir
+	if !filepath.IsAbs(stateDir) {
+		stateDir = filepath.Join(repoRoot, stateDir)
+	}
+	coverage := runReportCoverage
+	if coverage == "" {
+		coverage = filepath.Join(stateDir, "coverage.out")
+		if _, err := os.Stat(coverage); errors.Is(err, fs.ErrNotExist) {
+			coverage = ""
+		}
+	}
+	if in.Coverage, err = prsummary.ReadCoverage(coverage, repoRoot); err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "coverage", err)
+	}
+	if in.BaseCoverage, err = prsummary.ReadCoverage(runReportBaseCoverage, repoRoot); err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "base coverage", err)
+	}
+
+	if last != nil {
+		for _, p := range []string{filepath.Join(stateDir, "last-run.json"), coverage} {
+			if p == "" {
+				continue
+			}
+			if rel, err := filepath.Rel(repoRoot, p); err == nil {
+				p = rel
+			}
+			in.Artifacts = append(in.Artifacts, filepath.ToSlash(p))
+		}
+	}
+
+	md, err := runsummary.RenderWith(templates.New(repoRoot), in)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "run summary", err)
+	}
+	_, err = fmt.Fprint(out, md)
+	return err
+}
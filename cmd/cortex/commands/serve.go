@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/github"
+	"github.com/bartekus/cortex/internal/log"
+	"github.com/bartekus/cortex/internal/server"
+	"github.com/bartekus/cortex/pkg/cortexrun"
+)
+
+// Feature: CLI_COMMAND_SERVE
+// Spec: spec/cli/serve.md
+
+// Environment fallbacks for the secrets, which should stay out of process
+// listings.
+const (
+	serveTokenEnv    = "CORTEX_SERVE_TOKEN"
+	webhookSecretEnv = "CORTEX_WEBHOOK_SECRET"
+)
+
+// NewServeCommand returns the `cortex serve` HTTP API command.
+func NewServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve skill runs, the feature registry and reports over HTTP",
+		Long: "Starts a long-running HTTP server exposing POST /runs and GET /runs/{id} to trigger and inspect skill runs, " +
+			"GET /features for the feature registry and GET /reports/* for generated artifacts. With a webhook secret, " +
+			"POST /webhooks/github runs the configured profile on pushed commits and pull requests and reports back as " +
+			"GitHub check runs.",
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().String("features", "spec/features.yaml", "Feature registry served under /features")
+	cmd.Flags().String("repo", ".", "Repository root")
+	cmd.Flags().String("state-dir", ".cortex/run", "Run state directory, relative to the repository root")
+	cmd.Flags().String("token", "", "Bearer token required by every endpoint but /healthz (default $"+serveTokenEnv+")")
+	cmd.Flags().String("webhook-secret", "", "Enable POST /webhooks/github with this signing secret (default $"+webhookSecretEnv+")")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	features, _ := cmd.Flags().GetString("features")
+	repo, _ := cmd.Flags().GetString("repo")
+	stateDir, _ := cmd.Flags().GetString("state-dir")
+	token, _ := cmd.Flags().GetString("token")
+	token = orEnv(token, serveTokenEnv)
+	webhookSecret, _ := cmd.Flags().GetString("webhook-secret")
+	webhookSecret = orEnv(webhookSecret, webhookSecretEnv)
+
+	if info, err := os.Stat(repo); err != nil || !info.IsDir() {
+		return clierr.Newf(clierr.ExitConfig, "%s is not a directory", repo)
+	}
+
+	logger := log.FromContext(cmd.Context())
+	opts := server.Options{
+		RepoRoot:      repo,
+		FeaturesPath:  features,
+		Token:         token,
+		RunOptions:    []cortexrun.Option{cortexrun.WithStateDir(stateDir), cortexrun.WithLogger(logger)},
+		WebhookSecret: webhookSecret,
+		Log:           logger,
+	}
+	// Webhook results are published like `cortex run publish --github`.
+	if ghToken := os.Getenv("GITHUB_TOKEN"); webhookSecret != "" && ghToken != "" {
+		opts.GitHub = &github.Client{BaseURL: orEnv("", "GITHUB_API_URL"), Token: ghToken}
+		if opts.GitHub.BaseURL == "" {
+			opts.GitHub.BaseURL = github.DefaultAPIURL
+		}
+	}
+	srv := server.New(opts)
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return clierr.Wrap(clierr.ExitConfig, "listen", err)
+	}
+	httpSrv := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpSrv.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Serving on http://%s\n", ln.Addr())
+	if err := httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return clierr.Wrap(clierr.ExitExecution, "serve", err)
+	}
+	return nil
+}
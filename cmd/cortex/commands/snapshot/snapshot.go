@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package snapshot contains the `cortex snapshot` subcommands.
+package snapshot
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/snapshot"
+)
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+// NewSnapshotCommand returns the `cortex snapshot` command group.
+func NewSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Inspect workspace snapshots",
+		Long:  "Tools for inspecting the snapshots that cortex-mcp records of the workspace",
+	}
+
+	cmd.AddCommand(NewSnapshotDiffCommand())
+	cmd.AddCommand(NewSnapshotHistoryCommand())
+	cmd.AddCommand(NewSnapshotVerifyCommand())
+
+	return cmd
+}
+
+// connect starts cortex-mcp in the repo root, where it keeps its store, and
+// returns a store reading through it and a func that stops the server.
+func connect(cmd *cobra.Command, mcpBin, op string) (*snapshot.MCPStore, func(), error) {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return nil, nil, clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+	}
+	bin, err := mcp.ResolveBin(mcpBin, repoRoot)
+	if err != nil {
+		return nil, nil, clierr.Wrap(clierr.ExitConfig, op, err)
+	}
+
+	client, err := mcp.Start(cmd.Context(), bin, repoRoot, cmd.ErrOrStderr())
+	if err != nil {
+		return nil, nil, clierr.Wrap(clierr.ExitExecution, op, err)
+	}
+	return &snapshot.MCPStore{Client: client, RepoRoot: repoRoot}, func() { _ = client.Close() }, nil
+}
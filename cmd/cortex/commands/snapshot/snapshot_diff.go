@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/snapshot"
+)
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+// NewSnapshotDiffCommand returns `cortex snapshot diff`.
+func NewSnapshotDiffCommand() *cobra.Command {
+	var (
+		asJSON bool
+		mcpBin string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <FROM_SNAPSHOT_ID> <TO_SNAPSHOT_ID>",
+		Short: "Show what changed between two snapshots",
+		Long: "Prints a git-style unified diff of the files that changed between two snapshots, in path order. " +
+			"With --json, prints the change manifest (added, modified and deleted files with their blob hashes) and the diff.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, stop, err := connect(cmd, mcpBin, "snapshot diff")
+			if err != nil {
+				return err
+			}
+			defer stop()
+
+			res, err := snapshot.Diff(cmd.Context(), store, args[0], args[1])
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "snapshot diff", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(res)
+			}
+			_, _ = fmt.Fprint(out, res.Patch)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the change manifest and diff as JSON")
+	cmd.Flags().StringVar(&mcpBin, "mcp-bin", "", "Path to the cortex-mcp binary (default: $CORTEX_MCP_BIN, then rust/target)")
+
+	return cmd
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/snapshot"
+)
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+// NewSnapshotHistoryCommand returns `cortex snapshot history`.
+func NewSnapshotHistoryCommand() *cobra.Command {
+	var (
+		asJSON bool
+		mcpBin string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history <SNAPSHOT_ID>",
+		Short: "Show the provenance chain of a snapshot",
+		Long: "Walks a snapshot's parents back to the snapshot its chain started from, printing for each the tool that " +
+			"created it, its parent, the digest of the patch applied to the parent and the base HEAD commit, newest first.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, stop, err := connect(cmd, mcpBin, "snapshot history")
+			if err != nil {
+				return err
+			}
+			defer stop()
+
+			history, err := store.History(cmd.Context(), args[0])
+			var rpcErr *mcp.Error
+			if errors.As(err, &rpcErr) && rpcErr.Code == "NOT_FOUND" {
+				return clierr.Wrap(clierr.ExitConfig, "snapshot history", err)
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "snapshot history", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(history)
+			}
+			writeHistory(out, history)
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the provenance chain as JSON")
+	cmd.Flags().StringVar(&mcpBin, "mcp-bin", "", "Path to the cortex-mcp binary (default: $CORTEX_MCP_BIN, then rust/target)")
+
+	return cmd
+}
+
+func writeHistory(w io.Writer, h *snapshot.History) {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	for i, p := range h.Chain {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "snapshot %s\n", p.SnapshotID)
+		_, _ = fmt.Fprintf(w, "  created by: %s\n", orDash(p.CreatedBy))
+		_, _ = fmt.Fprintf(w, "  parent:     %s\n", orDash(p.Parent))
+		_, _ = fmt.Fprintf(w, "  patch:      %s\n", orDash(p.PatchDigest))
+		_, _ = fmt.Fprintf(w, "  base head:  %s\n", orDash(p.BaseHeadSHA))
+		if p.Label != "" {
+			_, _ = fmt.Fprintf(w, "  label:      %s\n", p.Label)
+		}
+	}
+	if !h.Complete {
+		_, _ = fmt.Fprintf(w, "\nparent %s is no longer in the store; history is incomplete\n", h.Missing)
+	}
+}
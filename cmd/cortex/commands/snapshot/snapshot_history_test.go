@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package snapshot
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bartekus/cortex/internal/snapshot"
+)
+
+func TestWriteHistory(t *testing.T) {
+	var buf bytes.Buffer
+	writeHistory(&buf, &snapshot.History{
+		SnapshotID: "sha256:s2",
+		Chain: []snapshot.Provenance{
+			{SnapshotID: "sha256:s2", Parent: "sha256:s1", CreatedBy: "workspace.apply_patch", PatchDigest: "sha256:p1", BaseHeadSHA: "abc123"},
+			{SnapshotID: "sha256:s1", Parent: "sha256:s0", CreatedBy: "snapshot.create", BaseHeadSHA: "abc123", Label: "baseline"},
+		},
+		Missing: "sha256:s0",
+	})
+
+	assert.Equal(t, `snapshot sha256:s2
+  created by: workspace.apply_patch
+  parent:     sha256:s1
+  patch:      sha256:p1
+  base head:  abc123
+
+snapshot sha256:s1
+  created by: snapshot.create
+  parent:     sha256:s0
+  patch:      -
+  base head:  abc123
+  label:      baseline
+
+parent sha256:s0 is no longer in the store; history is incomplete
+`, buf.String())
+}
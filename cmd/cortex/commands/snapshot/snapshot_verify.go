@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+)
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+// NewSnapshotVerifyCommand returns `cortex snapshot verify`.
+func NewSnapshotVerifyCommand() *cobra.Command {
+	var (
+		asJSON bool
+		mcpBin string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Revalidate every stored blob",
+		Long: "Re-reads every blob of the snapshot store and checks that it is present, has its recorded size, " +
+			"decodes with its recorded codec and matches its content hash. Exits 1 when any blob is corrupt.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, stop, err := connect(cmd, mcpBin, "snapshot verify")
+			if err != nil {
+				return err
+			}
+			defer stop()
+
+			report, err := store.Verify(cmd.Context())
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "snapshot verify", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				for _, p := range report.Problems {
+					_, _ = fmt.Fprintf(out, "✗ %s: %s\n", p.Hash, p.Problem)
+				}
+				if len(report.Problems) == 0 {
+					_, _ = fmt.Fprintf(out, "✓ %d blobs verified (%d bytes stored)\n", report.Blobs, report.StoredBytes)
+				}
+			}
+			if len(report.Problems) > 0 {
+				return clierr.Newf(clierr.ExitValidation, "snapshot verify: %d of %d blobs failed verification", len(report.Problems), report.Blobs)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the verification report as JSON")
+	cmd.Flags().StringVar(&mcpBin, "mcp-bin", "", "Path to the cortex-mcp binary (default: $CORTEX_MCP_BIN, then rust/target)")
+
+	return cmd
+}
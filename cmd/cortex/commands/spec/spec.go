@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package spec contains the `cortex spec` subcommands.
+package spec
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMMAND_SPEC
+// Spec: spec/cli/spec.md
+
+// NewSpecCommand returns the `cortex spec` command group.
+func NewSpecCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spec",
+		Short: "Author feature specifications",
+		Long:  "Tools for creating specs under spec/ that stay in sync with spec/features.yaml",
+	}
+
+	cmd.AddCommand(NewSpecNewCommand())
+
+	return cmd
+}
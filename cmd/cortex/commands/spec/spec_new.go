@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/scaffold"
+)
+
+// Feature: CLI_COMMAND_SPEC
+// Spec: spec/cli/spec.md
+
+// NewSpecNewCommand returns `cortex spec new`.
+func NewSpecNewCommand() *cobra.Command {
+	var (
+		opts   scaffold.SpecOptions
+		asJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "new <FEATURE_ID>",
+		Short: "Create a spec and register its feature",
+		Long: "Creates spec/<domain>/<id>.md with valid frontmatter, registers the feature in spec/features.yaml " +
+			"as draft/todo and links the spec from the index doc. Nothing is written when the registry would be invalid.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Domain == "" {
+				return clierr.New(clierr.ExitConfig, "--domain is required")
+			}
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+
+			opts.ID = args[0]
+			res, err := scaffold.NewSpec(repoRoot, opts)
+			if errors.Is(err, scaffold.ErrInvalidSpec) || errors.Is(err, fs.ErrNotExist) {
+				return clierr.Wrap(clierr.ExitConfig, "spec new", err)
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.ExitExecution, "spec new", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(res)
+			}
+			_, _ = fmt.Fprintf(out, "✓ Created %s\n", res.Path)
+			_, _ = fmt.Fprintf(out, "✓ Registered %s in %s\n", opts.ID, res.Features)
+			switch {
+			case res.Index == "":
+				_, _ = fmt.Fprintln(out, "  No index doc found (docs/README.md); link the spec from the docs manually")
+			case res.Linked:
+				_, _ = fmt.Fprintf(out, "✓ Linked from %s\n", res.Index)
+			default:
+				_, _ = fmt.Fprintf(out, "  Already linked from %s\n", res.Index)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().StringSliceVar(&opts.DependsOn, "depends-on", nil, "Feature IDs this feature depends on")
+	cmd.Flags().StringVar(&opts.Domain, "domain", "", "Spec domain, the directory under spec/ (e.g. cli)")
+	cmd.Flags().StringVar(&opts.Features, "features", "spec/features.yaml", "Path to features.yaml relative to the repo root")
+	cmd.Flags().StringVar(&opts.Group, "group", "", "Feature group (default: the domain)")
+	cmd.Flags().StringVar(&opts.Index, "index", "", "Doc to link the spec from (default: docs/<domain>/README.md, else docs/README.md)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the result as JSON")
+	cmd.Flags().StringVar(&opts.Owner, "owner", "unassigned", "Feature owner")
+	cmd.Flags().StringVar(&opts.Path, "path", "", "Spec path (default: spec/<domain>/<id in kebab case>.md)")
+	cmd.Flags().StringVar(&opts.Title, "title", "", "Feature title (default: the ID in words)")
+
+	return cmd
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/commands/reports"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/status"
+)
+
+// Feature: CLI_COMMAND_STATUS
+// Spec: spec/cli/status.md
+
+// NewStatusCommand returns the `cortex status` dashboard command.
+func NewStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show a repository health snapshot",
+		Long: "Combines roadmap progress, the last `cortex run`, drift checks and the freshness of generated " +
+			"artifacts into one dashboard. Use --json for machine-readable output.",
+		Args: cobra.NoArgs,
+		RunE: runStatus,
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().String("features", "spec/features.yaml", "Path to features.yaml")
+	cmd.Flags().Bool("json", false, "Output the snapshot as JSON")
+	cmd.Flags().String("state-dir", ".cortex/run", "Directory holding `cortex run` state")
+
+	roadmap := reports.NewStatusRoadmapCommand()
+	roadmap.Use = "roadmap"
+	cmd.AddCommand(roadmap)
+
+	return cmd
+}
+
+func runStatus(cmd *cobra.Command, _ []string) error {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return fmt.Errorf("finding repo root: %w", err)
+	}
+
+	featuresPath, _ := cmd.Flags().GetString("features")
+	stateDir, _ := cmd.Flags().GetString("state-dir")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	report := status.Collect(cmd.Context(), status.Options{
+		RepoRoot:     repoRoot,
+		FeaturesPath: featuresPath,
+		StateDir:     stateDir,
+		Help:         renderRootHelp,
+	})
+
+	if asJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), status.RenderText(report))
+	return err
+}
+
+// renderRootHelp returns `cortex --help` as built into this binary.
+func renderRootHelp() (string, error) {
+	root := NewRootCmd()
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs([]string{"--help"})
+	if err := root.Execute(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
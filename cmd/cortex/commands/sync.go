@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/remote"
+)
+
+// Feature: CLI_COMMAND_SYNC
+// Spec: spec/cli/sync.md
+
+// syncFlags are the flags shared by `cortex sync push` and `pull`.
+type syncFlags struct {
+	dryRun   bool
+	json     bool
+	remote   string
+	stateDir string
+}
+
+// NewSyncCommand returns the `cortex sync` command group.
+func NewSyncCommand() *cobra.Command {
+	var flags syncFlags
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Share snapshot blobs, run history and reports through a remote",
+		Long: "Exchanges cortex-mcp snapshot blobs, run history records and reports with an S3, GCS or directory " +
+			"remote, so CI machines and developer laptops share them.",
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.PersistentFlags().BoolVar(&flags.dryRun, "dry-run", false, "Report what would be transferred without transferring it")
+	cmd.PersistentFlags().BoolVar(&flags.json, "json", false, "Output the summary as JSON")
+	cmd.PersistentFlags().StringVar(&flags.remote, "remote", "", "Remote URL: s3://bucket/prefix, gs://bucket/prefix or file:///path (default: sync.remote)")
+	cmd.PersistentFlags().StringVar(&flags.stateDir, "state-dir", ".cortex/run", "Directory of the run state")
+
+	cmd.AddCommand(newSyncPushCommand(&flags))
+	cmd.AddCommand(newSyncPullCommand(&flags))
+
+	return cmd
+}
+
+func newSyncPushCommand(flags *syncFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "push",
+		Short: "Upload the artifacts the remote lacks",
+		Long: "Uploads the snapshot blobs and run records the remote lacks, and the reports of the current commit. " +
+			"Runs without a commit are not pushed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			b, url, local, err := openSync(cmd, flags)
+			if err != nil {
+				return err
+			}
+			sum, err := remote.Push(cmd.Context(), b, local, flags.dryRun)
+			if err != nil {
+				return syncError("sync push", err)
+			}
+			return printSyncSummary(cmd, flags, "push", url, sum)
+		},
+	}
+}
+
+func newSyncPullCommand(flags *syncFlags) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Download the artifacts of the current commit",
+		Long: "Downloads the snapshot blobs the local store lacks, and the run records and reports of the current " +
+			"commit. Blobs and records are verified before they are written; local reports that differ are only " +
+			"replaced with --force.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			b, url, local, err := openSync(cmd, flags)
+			if err != nil {
+				return err
+			}
+			sum, err := remote.Pull(cmd.Context(), b, local, force, flags.dryRun)
+			if err != nil {
+				return syncError("sync pull", err)
+			}
+			return printSyncSummary(cmd, flags, "pull", url, sum)
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&force, "force", false, "Replace local reports that differ from the remote")
+
+	return cmd
+}
+
+// openSync resolves the remote and the local artifact locations.
+func openSync(cmd *cobra.Command, flags *syncFlags) (remote.Backend, string, remote.Local, error) {
+	repoRoot, err := projectroot.Find(".")
+	if err != nil {
+		return nil, "", remote.Local{}, clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+	}
+	cfg, err := config.Load(repoRoot)
+	if err != nil {
+		return nil, "", remote.Local{}, clierr.Wrap(clierr.ExitConfig, "sync", err)
+	}
+	url := flags.remote
+	if url == "" {
+		url = cfg.Sync.Remote
+	}
+	if url == "" {
+		return nil, "", remote.Local{}, clierr.New(clierr.ExitConfig, "sync: no remote (pass --remote or set sync.remote in .cortex/config.yaml)")
+	}
+	b, err := remote.Open(url, remote.Options{Region: cfg.Sync.Region, Endpoint: cfg.Sync.Endpoint})
+	if err != nil {
+		return nil, "", remote.Local{}, clierr.Wrap(clierr.ExitConfig, "sync", err)
+	}
+
+	// cortex-mcp keeps its store in .cortex/data unless $CORTEX_DATA_DIR is set.
+	dataDir := os.Getenv("CORTEX_DATA_DIR")
+	if dataDir == "" {
+		dataDir = filepath.Join(repoRoot, ".cortex", "data")
+	}
+	stateDir := flags.stateDir
+	if !filepath.IsAbs(stateDir) {
+		stateDir = filepath.Join(repoRoot, stateDir)
+	}
+	// Outside git, or on an unborn branch, only blobs are exchanged.
+	commit, _ := git.RevParse(cmd.Context(), repoRoot, "HEAD")
+	return b, url, remote.Local{
+		DataDir:    dataDir,
+		StateDir:   stateDir,
+		ReportsDir: filepath.Join(repoRoot, ".cortex", "reports"),
+		Commit:     commit,
+	}, nil
+}
+
+func syncError(op string, err error) error {
+	switch {
+	case errors.Is(err, remote.ErrInvalid):
+		return clierr.Wrap(clierr.ExitValidation, op, err)
+	case errors.Is(err, remote.ErrConflict):
+		return clierr.Wrap(clierr.ExitConfig, op, err)
+	}
+	return clierr.Wrap(clierr.ExitExecution, op, err)
+}
+
+// syncOutput is the --json document.
+type syncOutput struct {
+	Direction string `json:"direction"`
+	Remote    string `json:"remote"`
+	DryRun    bool   `json:"dry_run"`
+	remote.Summary
+}
+
+func printSyncSummary(cmd *cobra.Command, flags *syncFlags, direction, url string, sum remote.Summary) error {
+	w := cmd.OutOrStdout()
+	if flags.json {
+		return encodeJSON(w, syncOutput{Direction: direction, Remote: url, DryRun: flags.dryRun, Summary: sum})
+	}
+	verb, prep := "Pushed", "to"
+	if direction == "pull" {
+		verb, prep = "Pulled", "from"
+	}
+	if flags.dryRun {
+		verb = "Would " + direction
+	}
+	_, err := fmt.Fprintf(w, "%s %d blobs, %d runs and %d reports %s %s (%d up to date)\n",
+		verb, sum.Blobs, sum.Runs, sum.Reports, prep, url, sum.UpToDate)
+	return err
+}
@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/doctor"
+	"github.com/bartekus/cortex/internal/projectroot"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/skills"
+)
+
+// Feature: CLI_COMMAND_TOOLS
+// Spec: spec/cli/tools.md
+
+// NewToolsCommand returns the `cortex tools` command.
+func NewToolsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Manage the Go tools skills run",
+		Long: "Installs the pinned versions of the Go tools skills run (gofumpt, golangci-lint) into " +
+			runner.ToolBinDir + ", which skills and doctor search before PATH, and records their digests in " +
+			runner.ToolsLockFile + ", which skills verify before running a tool.",
+	}
+	cmd.AddCommand(newToolsInstallCommand())
+	cmd.AddCommand(newToolsVerifyCommand())
+	return cmd
+}
+
+// toolInstall is the outcome of installing one tool.
+type toolInstall struct {
+	skills.GoTool
+	Path   string `json:"path"`
+	Digest string `json:"digest,omitempty"`
+	// Status is "installed", "up-to-date" or "failed".
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newToolsInstallCommand() *cobra.Command {
+	var (
+		asJSON     bool
+		force      bool
+		updateLock bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install [tool...]",
+		Short: "Install the pinned Go tools into " + runner.ToolBinDir,
+		Long: `Installs the Go tools skills run with go install, at the versions pinned by
+tools.versions in .cortex/config.yaml or the defaults (gofumpt v0.6.0, golangci-lint v2.6.2),
+into ` + runner.ToolBinDir + ` of the repository, isolated from GOPATH, and records each
+version and binary digest in ` + runner.ToolsLockFile + `. Arguments select tools; none installs
+all. A tool already installed at its pinned version and locked digest is kept unless --force.
+A build whose digest differs from the one locked for its version fails with VERSION_MISMATCH
+unless --update-lock. Exits 4 when an install fails.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "loading config", err)
+			}
+			tools, err := selectGoTools(skills.PinnedGoTools(cfg.Tools.Versions), args)
+			if err != nil {
+				return err
+			}
+			lock, err := runner.ReadToolsLock(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "tools install", err)
+			}
+			if lock == nil {
+				lock = &runner.ToolsLock{}
+			}
+
+			out := cmd.OutOrStdout()
+			results := make([]toolInstall, 0, len(tools))
+			failed := 0
+			for _, t := range tools {
+				r := toolInstall{GoTool: t, Path: filepath.ToSlash(filepath.Join(runner.ToolBinDir, t.Name)), Status: "up-to-date"}
+				want := ""
+				if l := lock.Tool(t.Name); l != nil && l.Package == t.Package && l.Version == t.Version {
+					want = l.Digests[runner.Platform()]
+				}
+
+				path, current := installedTool(cmd, repoRoot, t)
+				if force || !current || (want != "" && digestOf(path) != want) {
+					r.Status = "installed"
+					if !asJSON {
+						_, _ = fmt.Fprintf(out, "Installing %s@%s\n", t.Package, t.Version)
+					}
+					if err := t.Install(cmd.Context(), repoRoot, cmd.ErrOrStderr()); err != nil {
+						r.Status, r.Error = "failed", err.Error()
+						failed++
+						results = append(results, r)
+						continue
+					}
+					path, _ = installedTool(cmd, repoRoot, t)
+				}
+
+				r.Digest = digestOf(path)
+				switch {
+				case r.Digest == "":
+					r.Status, r.Error = "failed", "no binary in "+runner.ToolBinDir
+					failed++
+				case want != "" && r.Digest != want && !updateLock:
+					r.Status, r.Error = "failed", runner.VerifyTool(repoRoot, t.Name, path).Error()
+					failed++
+				default:
+					lock.Lock(t.Name, t.Package, t.Version, r.Digest)
+				}
+				results = append(results, r)
+			}
+			if len(lock.Tools) > 0 {
+				if err := runner.WriteToolsLock(repoRoot, lock); err != nil {
+					return clierr.Wrap(clierr.ExitExecution, "tools install", err)
+				}
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(results); err != nil {
+					return err
+				}
+			} else {
+				for _, r := range results {
+					switch r.Status {
+					case "failed":
+						_, _ = fmt.Fprintf(out, "✗ %s %s: %s\n", r.Name, r.Version, r.Error)
+					default:
+						_, _ = fmt.Fprintf(out, "✓ %s %s %s (%s)\n", r.Name, r.Version, r.Status, r.Path)
+					}
+				}
+			}
+
+			if failed > 0 {
+				return clierr.Newf(clierr.ExitExecution, "tools install: %d tool(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&force, "force", false, "Reinstall tools already at their pinned version")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the results as JSON")
+	cmd.Flags().BoolVar(&updateLock, "update-lock", false, "Record the digest of a build that differs from "+runner.ToolsLockFile)
+
+	return cmd
+}
+
+// toolCheck is the outcome of verifying one tool.
+type toolCheck struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+	// Status is "ok", "unlocked", "missing" or "mismatch".
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func newToolsVerifyCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the Go tools against " + runner.ToolsLockFile,
+		Long: `Checks each Go tool skills run, as they find it (` + runner.ToolBinDir + `, then PATH), against
+` + runner.ToolsLockFile + `: the locked version must be the one tools.versions pins and the binary
+digest the one locked for this platform. Exits 1 when a tool is missing or mismatches;
+tools without a lock entry or a digest for this platform are reported as unlocked.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoRoot, err := projectroot.Find(".")
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "finding repo root", err)
+			}
+			cfg, err := config.Load(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "loading config", err)
+			}
+			lock, err := runner.ReadToolsLock(repoRoot)
+			if err != nil {
+				return clierr.Wrap(clierr.ExitConfig, "tools verify", err)
+			}
+
+			var checks []toolCheck
+			bad := 0
+			for _, t := range skills.PinnedGoTools(cfg.Tools.Versions) {
+				c := toolCheck{Name: t.Name, Status: "ok"}
+				l := lock.Tool(t.Name)
+				path, err := runner.LookTool(repoRoot, t.Name)
+				switch {
+				case err != nil:
+					c.Status, c.Message = "missing", "not found. Run: cortex tools install "+t.Name
+				case l == nil || l.Digests[runner.Platform()] == "":
+					c.Path, c.Status, c.Message = path, "unlocked", fmt.Sprintf("no digest for %s in %s. Run: cortex tools install %s", runner.Platform(), runner.ToolsLockFile, t.Name)
+				case l.Version != t.Version:
+					c.Path, c.Status = path, "mismatch"
+					c.Message = fmt.Sprintf("%s: %s locks %s, tools.versions pins %s. Run: cortex tools install %s",
+						runner.CodeVersionMismatch, runner.ToolsLockFile, l.Version, t.Version, t.Name)
+				default:
+					c.Path, c.Message = path, fmt.Sprintf("%s matches %s", l.Version, runner.ToolsLockFile)
+					if err := runner.VerifyTool(repoRoot, t.Name, path); err != nil {
+						c.Status, c.Message = "mismatch", err.Error()
+					}
+				}
+				if c.Status == "missing" || c.Status == "mismatch" {
+					bad++
+				}
+				checks = append(checks, c)
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(checks); err != nil {
+					return err
+				}
+			} else {
+				for _, c := range checks {
+					mark := map[string]string{"ok": "✓", "unlocked": "!"}[c.Status]
+					if mark == "" {
+						mark = "✗"
+					}
+					_, _ = fmt.Fprintf(out, "%s %-14s %s\n", mark, c.Name, c.Message)
+				}
+			}
+
+			if bad > 0 {
+				return clierr.Newf(clierr.ExitValidation, "tools verify: %d tool(s) missing or mismatched", bad)
+			}
+			return nil
+		},
+	}
+
+	// Flags in alphabetical order for deterministic help output
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the checks as JSON")
+
+	return cmd
+}
+
+// selectGoTools returns the tools named by args, all when args is empty.
+func selectGoTools(tools []skills.GoTool, args []string) ([]skills.GoTool, error) {
+	if len(args) == 0 {
+		return tools, nil
+	}
+	byName := map[string]skills.GoTool{}
+	var names []string
+	for _, t := range tools {
+		byName[t.Name] = t
+		names = append(names, t.Name)
+	}
+	var out []skills.GoTool
+	for _, a := range args {
+		t, ok := byName[a]
+		if !ok {
+			return nil, clierr.Newf(clierr.ExitValidation, "unknown tool %q (known: %s)", a, strings.Join(names, ", "))
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// installedTool returns the path of t in runner.ToolBinDir, "" when it is
+// missing, and whether it reports the pinned version.
+func installedTool(cmd *cobra.Command, repoRoot string, t skills.GoTool) (string, bool) {
+	path, err := exec.LookPath(filepath.Join(repoRoot, filepath.FromSlash(runner.ToolBinDir), t.Name))
+	if err != nil {
+		return "", false
+	}
+	out, err := exec.CommandContext(cmd.Context(), path, "--version").CombinedOutput() //nolint:gosec // G204: path is an installed tool
+	return path, err == nil && doctor.ParseVersion(string(out)) == strings.TrimPrefix(t.Version, "v")
+}
+
+// digestOf returns the runner.FileDigest of path, "" when it cannot be read.
+func digestOf(path string) string {
+	if path == "" {
+		return ""
+	}
+	d, _ := runner.FileDigest(path)
+	return d
+}
@@ -0,0 +1,41 @@
+package clierr
+
+import "github.com/bartekus/cortex/internal/runner"
+
+// Exit code taxonomy shared by commands and skills. Skills report the same
+// values through runner.SkillResult.ExitCode; `cortex run` exits with the
+// highest code among failed skills.
+const (
+	ExitOK         = runner.ExitOK
+	ExitValidation = runner.ExitValidation
+	ExitConfig     = runner.ExitConfig
+	ExitWarning    = runner.ExitWarning
+	ExitExecution  = runner.ExitExecution
+)
+
+// Code documents one exit code.
+type Code struct {
+	Code        int    `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Codes returns the exit code mapping in ascending order.
+func Codes() []Code {
+	return []Code{
+		{ExitOK, "ok", "Success; all checks passed or were skipped"},
+		{ExitValidation, "validation", "Checks ran and found violations"},
+		{ExitConfig, "config", "Invalid usage, flags or configuration, or a missing prerequisite tool"},
+		{ExitWarning, "warning", "Only warnings were found and --fail-on-warning promoted them to a failure"},
+		{ExitExecution, "execution", "A check could not complete: I/O, subprocess or environment error"},
+	}
+}
+
+// Descriptions returns the description of each exit code by code.
+func Descriptions() map[int]string {
+	m := make(map[int]string)
+	for _, c := range Codes() {
+		m[c.Code] = c.Description
+	}
+	return m
+}
@@ -3,6 +3,9 @@ package clierr
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/i18n"
 )
 
 type ExitCoder interface {
@@ -16,6 +19,9 @@ type ExitError struct {
 	code  int
 	msg   string
 	cause error
+	// format and args build msg; Localize translates format.
+	format string
+	args   []any
 }
 
 func (e *ExitError) Error() string {
@@ -29,6 +35,16 @@ func (e *ExitError) Error() string {
 
 func (e *ExitError) ExitCode() int { return e.code }
 
+// Localize implements i18n.Localizer: the message in the current locale,
+// followed by the cause's.
+func (e *ExitError) Localize() string {
+	msg := i18n.Sprintf(e.format, e.args...)
+	if e.cause == nil {
+		return msg
+	}
+	return msg + ": " + i18n.Error(e.cause)
+}
+
 // Unwrap enables errors.Is/As to traverse the underlying cause.
 func (e *ExitError) Unwrap() error { return e.cause }
 
@@ -40,7 +56,7 @@ func (e *ExitError) Message() string { return e.msg }
 
 // New creates an ExitError with a message.
 func New(code int, msg string) error {
-	return &ExitError{code: normalize(code), msg: msg}
+	return &ExitError{code: normalize(code), msg: msg, format: strings.ReplaceAll(msg, "%", "%%")}
 }
 
 // Wrap creates an ExitError that wraps an underlying cause.
@@ -48,18 +64,20 @@ func Wrap(code int, msg string, cause error) error {
 	if cause == nil {
 		return New(code, msg)
 	}
-	return &ExitError{code: normalize(code), msg: msg, cause: cause}
+	return &ExitError{code: normalize(code), msg: msg, cause: cause, format: strings.ReplaceAll(msg, "%", "%%")}
 }
 
 // Newf is a formatted variant.
 func Newf(code int, format string, args ...any) error {
-	return &ExitError{code: normalize(code), msg: fmt.Sprintf(format, args...)}
+	return &ExitError{code: normalize(code), msg: fmt.Sprintf(format, args...), format: format, args: args}
 }
 
 // Wrapf is a formatted variant that wraps.
 func Wrapf(code int, cause error, format string, args ...any) error {
-	msg := fmt.Sprintf(format, args...)
-	return Wrap(code, msg, cause)
+	if cause == nil {
+		return Newf(code, format, args...)
+	}
+	return &ExitError{code: normalize(code), msg: fmt.Sprintf(format, args...), cause: cause, format: format, args: args}
 }
 
 // ExitCodeOf extracts an exit code from any error, defaulting to 1.
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package llmprovider is the injection point of pkg/llm into commands: it
+// builds the provider `llm` of .cortex/config.yaml configures, or one that
+// records or replays exchanges when the environment asks for it.
+package llmprovider
+
+// Feature: LLM_PROVIDER
+// Spec: spec/system/llm.md
+
+import (
+	"errors"
+	"os"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/pkg/llm"
+)
+
+// Environment variables selecting a cassette.
+const (
+	// EnvReplay names a cassette to answer from instead of a provider.
+	EnvReplay = "CORTEX_LLM_REPLAY"
+	// EnvRecord names a cassette to record the configured provider to.
+	EnvRecord = "CORTEX_LLM_RECORD"
+)
+
+// ErrDisabled reports that no provider is configured.
+var ErrDisabled = errors.New("no LLM configured; set llm.provider in .cortex/config.yaml")
+
+// New returns the provider of cfg. $CORTEX_LLM_REPLAY replays a cassette
+// without any provider configured; $CORTEX_LLM_RECORD records to one.
+func New(cfg config.LLM) (llm.Provider, error) {
+	if path := os.Getenv(EnvReplay); path != "" {
+		return llm.Replay(path)
+	}
+	if cfg.Provider == "" {
+		return nil, ErrDisabled
+	}
+	p, err := llm.New(llm.Config{Provider: cfg.Provider, Model: cfg.Model, BaseURL: cfg.BaseURL})
+	if err != nil {
+		return nil, err
+	}
+	if path := os.Getenv(EnvRecord); path != "" {
+		return llm.Record(p, path)
+	}
+	return p, nil
+}
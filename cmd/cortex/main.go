@@ -8,6 +8,7 @@ import (
 
 	"github.com/bartekus/cortex/cmd/cortex/commands"
 	"github.com/bartekus/cortex/cmd/cortex/internal/clierr"
+	"github.com/bartekus/cortex/internal/i18n"
 )
 
 // Feature: CLI_CONTRACT
@@ -15,7 +16,7 @@ import (
 
 func main() {
 	if err := commands.NewRootCmd().Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, i18n.Error(err))
 		os.Exit(clierr.ExitCodeOf(err))
 	}
 }
@@ -3,6 +3,7 @@ module github.com/bartekus/cortex
 go 1.24.10
 
 require (
+	github.com/klauspost/compress v1.18.0
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/pflag v1.0.9
 	github.com/stretchr/testify v1.11.1
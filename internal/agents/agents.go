@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package agents renders the agent instruction file (AGENTS.md, CLAUDE.md or
+// a Cursor rule) from the feature registry, the spec index, the skills
+// registry and the governance rules those skills enforce.
+//
+// Output depends only on repository content: no timestamps, and every list
+// is sorted (skills keep registry order, which is itself canonical).
+package agents
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/skills"
+	"github.com/bartekus/cortex/internal/specschema"
+)
+
+// Output formats.
+const (
+	FormatAgents = "agents"
+	FormatClaude = "claude"
+	FormatCursor = "cursor"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{FormatAgents, FormatClaude, FormatCursor}
+
+// DefaultPath returns the conventional location of the file for format,
+// relative to the repository root.
+func DefaultPath(format string) (string, error) {
+	switch format {
+	case FormatAgents:
+		return "AGENTS.md", nil
+	case FormatClaude:
+		return "CLAUDE.md", nil
+	case FormatCursor:
+		return filepath.Join(".cursor", "rules", "cortex.mdc"), nil
+	default:
+		return "", fmt.Errorf("invalid format %q (must be one of %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Spec is an entry of the spec index.
+type Spec struct {
+	Path    string
+	Feature string
+	Status  string
+	Domain  string
+}
+
+// Skill is an entry of the skills registry.
+type Skill struct {
+	ID          string
+	Implemented bool
+}
+
+// Rule is a governance rule and the skill that enforces it.
+type Rule struct {
+	Skill string `json:"skill"`
+	Text  string `json:"text"`
+}
+
+// Inputs holds everything Render needs.
+type Inputs struct {
+	Features []features.FeatureNode // sorted by ID
+	Specs    []Spec                 // sorted by path
+	Skills   []Skill                // registry order
+	Rules    []Rule
+}
+
+// Load collects inputs from repoRoot. spec/features.yaml is required; specs
+// without frontmatter are skipped.
+func Load(repoRoot string) (*Inputs, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, "spec", "features.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read features.yaml: %w", err)
+	}
+	var registry features.YAML
+	if err := yaml.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse features.yaml: %w", err)
+	}
+	in := &Inputs{Features: registry.Features}
+	sort.Slice(in.Features, func(i, j int) bool { return in.Features[i].ID < in.Features[j].ID })
+
+	specs, err := specschema.LoadAllSpecs(filepath.Join(repoRoot, "spec"))
+	if err != nil {
+		return nil, fmt.Errorf("loading specs: %w", err)
+	}
+	for _, s := range specs {
+		if s.Frontmatter.Feature == "" {
+			continue
+		}
+		rel, err := filepath.Rel(repoRoot, s.Path)
+		if err != nil {
+			return nil, err
+		}
+		in.Specs = append(in.Specs, Spec{
+			Path:    filepath.ToSlash(rel),
+			Feature: s.Frontmatter.Feature,
+			Status:  s.Frontmatter.Status,
+			Domain:  s.Frontmatter.Domain,
+		})
+	}
+
+	for _, s := range skills.Registry {
+		_, placeholder := s.(*skills.PlaceholderSkill)
+		in.Skills = append(in.Skills, Skill{ID: s.ID(), Implemented: !placeholder})
+	}
+
+	in.Rules = Rules()
+	return in, nil
+}
+
+// Rules returns the governance rules enforced by the skills registry.
+func Rules() []Rule {
+	rules := []Rule{
+		{Skill: "docs:header-comments", Text: "Every Go file starts with `// SPDX-License-Identifier: AGPL-3.0-or-later`."},
+		{Skill: "docs:feature-integrity", Text: "Every feature in `spec/features.yaml` has a unique ID and an existing spec."},
+		{Skill: "docs:orphan-specs", Text: "Every spec under `spec/` is referenced by a feature in `spec/features.yaml`."},
+		{Skill: "docs:validate-spec", Text: "Specs carry YAML frontmatter (`feature`, `version`, `status`, `domain`) matching their feature."},
+		{Skill: "lint:gofumpt", Text: "Go code is formatted with gofumpt."},
+	}
+	banned := skills.BannedImports()
+	pkgs := make([]string, 0, len(banned))
+	for pkg := range banned {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		rules = append(rules, Rule{
+			Skill: "purity",
+			Text:  fmt.Sprintf("`%s` may only be imported under: %s.", pkg, codeList(banned[pkg])),
+		})
+	}
+	return rules
+}
+
+// Render returns the instruction file for format.
+func Render(in *Inputs, format string) (string, error) {
+	if _, err := DefaultPath(format); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if format == FormatCursor {
+		b.WriteString("---\n")
+		b.WriteString("description: Cortex repository governance and workflow\n")
+		b.WriteString("alwaysApply: true\n")
+		b.WriteString("---\n\n")
+	}
+	b.WriteString("<!-- Code generated by cortex context agents. DO NOT EDIT. -->\n\n")
+	b.WriteString("# Agent Instructions\n\n")
+	b.WriteString("How to work in this repository. Generated from `spec/features.yaml`, the specs under `spec/` and the skills registry; regenerate with `cortex context agents`.\n\n")
+
+	b.WriteString("## Workflow\n\n")
+	b.WriteString("1. Find the feature you are changing below and read its spec first; the spec is the contract.\n")
+	b.WriteString("2. Change the spec together with the behaviour it describes.\n")
+	b.WriteString("3. Mark code that implements a feature with `// Feature: <ID>` and `// Spec: <path>` comments.\n")
+	b.WriteString("4. New capabilities need a feature in `spec/features.yaml` and a spec before code.\n")
+	b.WriteString("5. Run `cortex run all` and fix every failure before handing off.\n\n")
+
+	b.WriteString("## Governance Rules\n\n")
+	for _, r := range in.Rules {
+		fmt.Fprintf(&b, "- %s (`%s`)\n", r.Text, r.Skill)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Skills\n\n")
+	b.WriteString("Run one with `cortex run <id>`; `cortex run all` runs them in this order.\n\n")
+	for _, s := range in.Skills {
+		if s.Implemented {
+			fmt.Fprintf(&b, "- `%s`\n", s.ID)
+		} else {
+			fmt.Fprintf(&b, "- `%s` (not implemented)\n", s.ID)
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Features\n\n")
+	rows := make([][]string, 0, len(in.Features))
+	for _, f := range in.Features {
+		rows = append(rows, []string{
+			"`" + f.ID + "`",
+			escapeCell(f.Title),
+			f.Governance + "/" + f.Implementation,
+			"`" + f.Spec + "`",
+			codeList(f.DependsOn),
+		})
+	}
+	b.WriteString(projection.RenderTable([]string{"ID", "Title", "Status", "Spec", "Depends On"}, rows))
+	b.WriteString("\n")
+
+	b.WriteString("## Specs\n\n")
+	rows = make([][]string, 0, len(in.Specs))
+	for _, s := range in.Specs {
+		rows = append(rows, []string{"`" + s.Path + "`", "`" + s.Feature + "`", s.Domain, s.Status})
+	}
+	b.WriteString(projection.RenderTable([]string{"Path", "Feature", "Domain", "Status"}, rows))
+
+	return b.String(), nil
+}
+
+func codeList(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = "`" + s + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package agents
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/projectroot"
+)
+
+func TestRender(t *testing.T) {
+	in := &Inputs{
+		Features: []features.FeatureNode{
+			{ID: "A", Title: "Pipes | and more", Governance: "approved", Implementation: "done", Spec: "spec/a.md"},
+		},
+		Specs:  []Spec{{Path: "spec/a.md", Feature: "A", Status: "approved", Domain: "cli"}},
+		Skills: []Skill{{ID: "lint:x", Implemented: true}, {ID: "docs:y"}},
+		Rules:  []Rule{{Skill: "lint:x", Text: "Be tidy."}},
+	}
+
+	out, err := Render(in, FormatAgents)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "<!-- Code generated by cortex context agents. DO NOT EDIT. -->\n"))
+	assert.Contains(t, out, "- Be tidy. (`lint:x`)\n")
+	assert.Contains(t, out, "- `lint:x`\n- `docs:y` (not implemented)\n")
+	assert.Contains(t, out, "| `A` | Pipes \\| and more | approved/done | `spec/a.md` | - |\n")
+	assert.Contains(t, out, "| `spec/a.md` | `A` | cli | approved |\n")
+
+	claude, err := Render(in, FormatClaude)
+	require.NoError(t, err)
+	assert.Equal(t, out, claude)
+
+	cursor, err := Render(in, FormatCursor)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(cursor, "---\ndescription: "))
+	assert.True(t, strings.HasSuffix(cursor, out))
+
+	_, err = Render(in, "vim")
+	assert.Error(t, err)
+}
+
+func TestLoad_Repo(t *testing.T) {
+	root, err := projectroot.Find(".")
+	require.NoError(t, err)
+
+	in, err := Load(root)
+	require.NoError(t, err)
+	require.NotEmpty(t, in.Features)
+	require.NotEmpty(t, in.Specs)
+	for i := 1; i < len(in.Features); i++ {
+		assert.Less(t, in.Features[i-1].ID, in.Features[i].ID)
+	}
+	assert.Equal(t, "spec/cli/commit.md", in.Specs[0].Path)
+
+	first, err := Render(in, FormatAgents)
+	require.NoError(t, err)
+	again, err := Load(root)
+	require.NoError(t, err)
+	second, err := Render(again, FormatAgents)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package annotate writes the `Feature:` and `Spec:` header comments read by
+// the feature mapping. Files are assigned to features by the path rules in
+// .cortex/config.yaml (features.annotate); existing headers are updated in
+// place and missing ones are inserted after the package clause (Go) or the
+// license line (everything else).
+package annotate
+
+// Feature: CLI_COMMAND_FEATURES
+// Spec: spec/cli/features.md
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/mapping"
+)
+
+// Change is a file whose headers differ from the ones its rule requires.
+type Change struct {
+	Path    string
+	Feature string
+	Old     string
+	New     string
+}
+
+// Match returns the feature of the first rule matching rel.
+func Match(rules []config.AnnotateRule, rel string) (string, bool) {
+	for _, r := range rules {
+		if strings.HasSuffix(r.Path, "/") {
+			if strings.HasPrefix(rel, r.Path) {
+				return r.Feature, true
+			}
+			continue
+		}
+		if ok, _ := path.Match(r.Path, rel); ok {
+			return r.Feature, true
+		}
+	}
+	return "", false
+}
+
+// Plan returns the changes needed to annotate files (repo-relative, slash
+// separated) under repoRoot. specs maps feature IDs to their spec path; a
+// rule naming a feature missing from specs is an error. Files that cannot
+// carry header comments are ignored.
+func Plan(repoRoot string, files []string, rules []config.AnnotateRule, specs map[string]string) ([]Change, error) {
+	for _, r := range rules {
+		if _, ok := specs[r.Feature]; !ok {
+			return nil, fmt.Errorf("annotate rule %q: unknown feature %q", r.Path, r.Feature)
+		}
+	}
+
+	var changes []Change
+	for _, rel := range files {
+		feature, ok := Match(rules, rel)
+		if !ok || !mapping.SupportsHeaders(rel) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(rel))) //nolint:gosec // path is a tracked repository file
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		old := string(data)
+		updated := Apply(old, rel, feature, specs[feature])
+		if updated != old {
+			changes = append(changes, Change{Path: rel, Feature: feature, Old: old, New: updated})
+		}
+	}
+	return changes, nil
+}
+
+// Apply returns content with its first Feature and Spec header comments set
+// to feature and spec, inserting whichever is missing.
+func Apply(content, rel, feature, spec string) string {
+	prefix := "//"
+	switch strings.ToLower(path.Ext(rel)) {
+	case ".yml", ".yaml":
+		prefix = "#"
+	case "":
+		if path.Base(rel) == "Makefile" {
+			prefix = "#"
+		}
+	}
+	featureLine := prefix + " Feature: " + feature
+	specLine := prefix + " Spec: " + spec
+
+	lines := strings.Split(content, "\n")
+	featureAt, specAt := header(lines, prefix, "feature:"), header(lines, prefix, "spec:")
+	switch {
+	case featureAt >= 0 && specAt >= 0:
+		lines[featureAt] = indent(lines[featureAt]) + featureLine
+		lines[specAt] = indent(lines[specAt]) + specLine
+	case featureAt >= 0:
+		lines[featureAt] = indent(lines[featureAt]) + featureLine
+		lines = insert(lines, featureAt+1, indent(lines[featureAt])+specLine)
+	case specAt >= 0:
+		lines[specAt] = indent(lines[specAt]) + specLine
+		lines = insert(lines, specAt, indent(lines[specAt])+featureLine)
+	default:
+		at := insertionPoint(lines, rel)
+		block := []string{featureLine, specLine}
+		if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+			block = append([]string{""}, block...)
+		}
+		if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+			block = append(block, "")
+		}
+		lines = insert(lines, at, block...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// header returns the index of the first line comment whose key is key
+// (case-insensitive, as read by mapping.ParseHeaders), or -1.
+func header(lines []string, prefix, key string) int {
+	for i, line := range lines {
+		body, ok := strings.CutPrefix(strings.TrimSpace(line), prefix)
+		if ok && strings.HasPrefix(strings.ToLower(strings.TrimSpace(body)), key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertionPoint is the line index where a new header block goes: after the
+// package clause in Go files, otherwise after a leading shebang and SPDX line.
+func insertionPoint(lines []string, rel string) int {
+	if path.Ext(rel) == ".go" {
+		for i, line := range lines {
+			if strings.HasPrefix(line, "package ") {
+				return i + 1
+			}
+		}
+	}
+	at := 0
+	if at < len(lines) && strings.HasPrefix(lines[at], "#!") {
+		at++
+	}
+	if at < len(lines) && strings.Contains(lines[at], "SPDX-License-Identifier") {
+		at++
+	}
+	return at
+}
+
+func indent(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func insert(lines []string, at int, items ...string) []string {
+	out := make([]string, 0, len(lines)+len(items))
+	out = append(out, lines[:at]...)
+	out = append(out, items...)
+	return append(out, lines[at:]...)
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package annotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	rules := []config.AnnotateRule{
+		{Path: "internal/budget/", Feature: "A"},
+		{Path: "cmd/*.go", Feature: "B"},
+		{Path: "internal/", Feature: "C"},
+	}
+
+	for rel, want := range map[string]string{
+		"internal/budget/x/y.go": "A",
+		"cmd/main.go":            "B",
+		"internal/git/git.go":    "C",
+		"cmd/sub/x.go":           "",
+	} {
+		got, ok := Match(rules, rel)
+		assert.Equal(t, want != "", ok, rel)
+		assert.Equal(t, want, got, rel)
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name, rel, in, want string
+	}{
+		{
+			name: "go insert after package",
+			rel:  "a.go",
+			in:   "// SPDX-License-Identifier: AGPL-3.0-or-later\npackage a\n\nimport \"fmt\"\n",
+			want: "// SPDX-License-Identifier: AGPL-3.0-or-later\npackage a\n\n// Feature: F\n// Spec: spec/f.md\n\nimport \"fmt\"\n",
+		},
+		{
+			name: "go update existing",
+			rel:  "a.go",
+			in:   "package a\n\n// Feature: OLD\n// Spec: spec/old.md\n",
+			want: "package a\n\n// Feature: F\n// Spec: spec/f.md\n",
+		},
+		{
+			name: "go insert missing spec",
+			rel:  "a.go",
+			in:   "// Package a does things.\n//\n// Feature: F\npackage a\n",
+			want: "// Package a does things.\n//\n// Feature: F\n// Spec: spec/f.md\npackage a\n",
+		},
+		{
+			name: "yaml insert after spdx",
+			rel:  "ci/x.yaml",
+			in:   "# SPDX-License-Identifier: AGPL-3.0-or-later\nname: x\n",
+			want: "# SPDX-License-Identifier: AGPL-3.0-or-later\n\n# Feature: F\n# Spec: spec/f.md\n\nname: x\n",
+		},
+		{
+			name: "already annotated",
+			rel:  "a.rs",
+			in:   "// Feature: F\n// Spec: spec/f.md\nfn main() {}\n",
+			want: "// Feature: F\n// Spec: spec/f.md\nfn main() {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Apply(tt.in, tt.rel, "F", "spec/f.md"))
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(rel)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, rel), []byte(content), 0o644))
+	}
+	write("pkg/a.go", "package pkg\n")
+	write("pkg/b.go", "package pkg\n\n// Feature: F\n// Spec: spec/f.md\n")
+	write("pkg/README.md", "# pkg\n")
+
+	rules := []config.AnnotateRule{{Path: "pkg/", Feature: "F"}}
+	specs := map[string]string{"F": "spec/f.md"}
+
+	changes, err := Plan(root, []string{"pkg/README.md", "pkg/a.go", "pkg/b.go"}, rules, specs)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "pkg/a.go", changes[0].Path)
+	assert.Equal(t, "package pkg\n\n// Feature: F\n// Spec: spec/f.md\n", changes[0].New)
+
+	_, err = Plan(root, nil, []config.AnnotateRule{{Path: "pkg/", Feature: "NOPE"}}, specs)
+	assert.ErrorContains(t, err, `unknown feature "NOPE"`)
+}
@@ -0,0 +1,375 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package apidrift dumps the exported Go API of a set of packages as
+// feature lines, in the format of Go's own api/*.txt files, and compares
+// it with a committed baseline. A feature that disappears or changes is a
+// breaking change; a new feature is a compatible addition.
+package apidrift
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/gomod"
+)
+
+// DefaultPatterns are the package patterns whose API is tracked.
+var DefaultPatterns = []string{"pkg/..."}
+
+// API is the exported surface of a set of packages.
+type API struct {
+	// Packages are the import paths dumped, sorted.
+	Packages []string
+	// Features are the feature lines, sorted and unique. Each is
+	// "pkg <import path>, <declaration>", for example
+	// "pkg example.com/m/pkg/a, func Parse(string) (*File, error)".
+	Features []string
+}
+
+// Dump parses the packages matched by patterns, directories relative to
+// repoRoot with an optional "/..." suffix, and returns their exported API.
+// Test files, internal and testdata directories, and main packages are
+// skipped.
+func Dump(repoRoot string, patterns []string) (*API, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("reading go.mod: %w", err)
+	}
+	module := gomod.Parse(data).Module
+	if module == "" {
+		return nil, fmt.Errorf("go.mod declares no module")
+	}
+
+	dirs := map[string]bool{}
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(path.Clean(filepath.ToSlash(pattern)), "/...")
+		if root == "..." {
+			root, recursive = ".", true
+		}
+		if !recursive {
+			dirs[root] = true
+			continue
+		}
+		err := filepath.WalkDir(filepath.Join(repoRoot, filepath.FromSlash(root)), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if p != filepath.Join(repoRoot, filepath.FromSlash(root)) &&
+				(name == "internal" || name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			rel, err := filepath.Rel(repoRoot, p)
+			if err != nil {
+				return err
+			}
+			dirs[filepath.ToSlash(rel)] = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("pattern %s: %w", pattern, err)
+		}
+	}
+
+	api := &API{Packages: []string{}, Features: []string{}}
+	seen := map[string]bool{}
+	for dir := range dirs {
+		importPath := module
+		if dir != "." {
+			importPath += "/" + dir
+		}
+		features, ok, err := dumpDir(filepath.Join(repoRoot, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dir, err)
+		}
+		if !ok {
+			continue
+		}
+		api.Packages = append(api.Packages, importPath)
+		for _, f := range features {
+			line := "pkg " + importPath + ", " + f
+			if !seen[line] {
+				seen[line] = true
+				api.Features = append(api.Features, line)
+			}
+		}
+	}
+	sort.Strings(api.Packages)
+	sort.Strings(api.Features)
+	return api, nil
+}
+
+// dumpDir returns the features of the package in dir; ok is false when dir
+// holds no library package.
+func dumpDir(dir string) (features []string, ok bool, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	fset := token.NewFileSet()
+	d := &dumper{fset: fset}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, false, err
+		}
+		if f.Name.Name == "main" {
+			return nil, false, nil
+		}
+		ok = true
+		d.file(f)
+	}
+	return d.features, ok, nil
+}
+
+type dumper struct {
+	fset     *token.FileSet
+	features []string
+}
+
+func (d *dumper) emit(format string, args ...any) {
+	d.features = append(d.features, fmt.Sprintf(format, args...))
+}
+
+func (d *dumper) file(f *ast.File) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			d.funcDecl(decl)
+		case *ast.GenDecl:
+			d.genDecl(decl)
+		}
+	}
+}
+
+func (d *dumper) funcDecl(fn *ast.FuncDecl) {
+	if !fn.Name.IsExported() {
+		return
+	}
+	if fn.Recv == nil {
+		d.emit("func %s%s%s", fn.Name.Name, d.typeParams(fn.Type.TypeParams), d.signature(fn.Type))
+		return
+	}
+	recv := fn.Recv.List[0].Type
+	base := recv
+	if star, ok := base.(*ast.StarExpr); ok {
+		base = star.X
+	}
+	switch b := base.(type) {
+	case *ast.IndexExpr:
+		base = b.X
+	case *ast.IndexListExpr:
+		base = b.X
+	}
+	if id, ok := base.(*ast.Ident); !ok || !id.IsExported() {
+		return
+	}
+	d.emit("method (%s) %s%s", d.expr(recv), fn.Name.Name, d.signature(fn.Type))
+}
+
+func (d *dumper) genDecl(decl *ast.GenDecl) {
+	// Constants in a group repeat the type and value of the previous
+	// specification when they omit them.
+	var lastType ast.Expr
+	var lastValues []ast.Expr
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			typ, values := spec.Type, spec.Values
+			if decl.Tok == token.CONST {
+				if typ == nil && values == nil {
+					typ, values = lastType, lastValues
+				}
+				lastType, lastValues = typ, values
+			}
+			for i, name := range spec.Names {
+				if !name.IsExported() {
+					continue
+				}
+				kind := strings.ToLower(decl.Tok.String())
+				switch {
+				case typ != nil:
+					d.emit("%s %s %s", kind, name.Name, d.expr(typ))
+				case decl.Tok == token.CONST && i < len(values) && isLiteral(values[i]):
+					d.emit("const %s = %s", name.Name, d.expr(values[i]))
+				default:
+					d.emit("%s %s", kind, name.Name)
+				}
+			}
+		case *ast.TypeSpec:
+			d.typeSpec(spec)
+		}
+	}
+}
+
+func (d *dumper) typeSpec(spec *ast.TypeSpec) {
+	if !spec.Name.IsExported() {
+		return
+	}
+	name := spec.Name.Name + d.typeParams(spec.TypeParams)
+	if spec.Assign.IsValid() {
+		d.emit("type %s = %s", name, d.expr(spec.Type))
+		return
+	}
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		d.emit("type %s struct", name)
+		for _, field := range t.Fields.List {
+			if len(field.Names) == 0 {
+				if embeddedName(field.Type).IsExported() {
+					d.emit("type %s struct, embedded %s", name, d.expr(field.Type))
+				}
+				continue
+			}
+			for _, n := range field.Names {
+				if n.IsExported() {
+					d.emit("type %s struct, %s %s", name, n.Name, d.expr(field.Type))
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		// The header lists every method, so that adding one to an
+		// interface, which breaks its implementations, changes a line.
+		var members []string
+		unexported := false
+		for _, m := range t.Methods.List {
+			if len(m.Names) == 0 {
+				members = append(members, d.expr(m.Type))
+				d.emit("type %s interface, embedded %s", name, d.expr(m.Type))
+				continue
+			}
+			for _, n := range m.Names {
+				if !n.IsExported() {
+					unexported = true
+					continue
+				}
+				members = append(members, n.Name)
+				d.emit("type %s interface, %s%s", name, n.Name, d.signature(m.Type.(*ast.FuncType)))
+			}
+		}
+		sort.Strings(members)
+		if unexported {
+			members = append(members, "unexported methods")
+		}
+		d.emit("type %s interface { %s }", name, strings.Join(members, ", "))
+	default:
+		d.emit("type %s %s", name, d.expr(spec.Type))
+	}
+}
+
+// signature renders the parameters and results of fn by type only, as in
+// "(string, ...int) (bool, error)".
+func (d *dumper) signature(fn *ast.FuncType) string {
+	s := "(" + strings.Join(d.fieldTypes(fn.Params), ", ") + ")"
+	results := d.fieldTypes(fn.Results)
+	switch len(results) {
+	case 0:
+	case 1:
+		s += " " + results[0]
+	default:
+		s += " (" + strings.Join(results, ", ") + ")"
+	}
+	return s
+}
+
+func (d *dumper) fieldTypes(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var types []string
+	for _, f := range fields.List {
+		typ := d.expr(f.Type)
+		for range max(len(f.Names), 1) {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+func (d *dumper) typeParams(fields *ast.FieldList) string {
+	if fields == nil || len(fields.List) == 0 {
+		return ""
+	}
+	var params []string
+	for _, f := range fields.List {
+		for _, n := range f.Names {
+			params = append(params, n.Name+" "+d.expr(f.Type))
+		}
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// expr prints a type or constant expression on a single line.
+func (d *dumper) expr(e ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, d.fset, stripNames(e))
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// stripNames drops parameter names from function types nested in e, so
+// that renaming a parameter of a func-typed field is not a change.
+func stripNames(e ast.Expr) ast.Expr {
+	fn, ok := e.(*ast.FuncType)
+	if !ok {
+		return e
+	}
+	strip := func(fields *ast.FieldList) *ast.FieldList {
+		if fields == nil {
+			return nil
+		}
+		out := &ast.FieldList{}
+		for _, f := range fields.List {
+			for range max(len(f.Names), 1) {
+				out.List = append(out.List, &ast.Field{Type: stripNames(f.Type)})
+			}
+		}
+		return out
+	}
+	return &ast.FuncType{Params: strip(fn.Params), Results: strip(fn.Results)}
+}
+
+func embeddedName(e ast.Expr) *ast.Ident {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel
+	case *ast.IndexExpr:
+		return embeddedName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedName(t.X)
+	case *ast.Ident:
+		return t
+	}
+	return ast.NewIdent("_")
+}
+
+func isLiteral(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.UnaryExpr:
+		return isLiteral(e.X)
+	}
+	return false
+}
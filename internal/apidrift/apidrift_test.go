@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package apidrift
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+const lib = `package lib
+
+import "io"
+
+const (
+	A Mode = iota
+	B
+	c
+)
+
+const Name = "lib"
+
+var ErrBad error
+
+type Mode int
+
+type Set[K comparable] map[K]struct{}
+
+type Alias = Mode
+
+type Config struct {
+	Path    string
+	Hook    func(name string, n int) error
+	secret  string
+	io.Reader
+}
+
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	io.Closer
+	sync()
+}
+
+func New(path string, opts ...Option) (*Config, error) { return nil, nil }
+
+func Keys[K comparable](s Set[K]) []K { return nil }
+
+func (c *Config) Open(a, b int) error { return nil }
+
+func (m Mode) String() string { return "" }
+
+func (s Set[K]) Has(k K) bool { return false }
+
+func (c *Config) close() {}
+
+func helper() {}
+
+type Option func(*Config)
+
+type hidden struct{}
+
+func (hidden) Exported() {}
+`
+
+func TestDump(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":                      "module example.com/m\n\ngo 1.24\n",
+		"pkg/lib/lib.go":              lib,
+		"pkg/lib/lib_test.go":         "package lib\n\nfunc TestOnly() {}\n",
+		"pkg/lib/internal/x/x.go":     "package x\n\nfunc X() {}\n",
+		"pkg/lib/testdata/t.go":       "package t\n\nfunc T() {}\n",
+		"pkg/tool/main.go":            "package main\n\nfunc Run() {}\n",
+		"pkg/empty/README.md":         "no go files\n",
+		"internal/private/private.go": "package private\n\nfunc P() {}\n",
+	})
+
+	api, err := Dump(root, DefaultPatterns)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/m/pkg/lib"}, api.Packages)
+
+	p := "pkg example.com/m/pkg/lib, "
+	assert.Equal(t, []string{
+		p + "const A Mode",
+		p + "const B Mode",
+		p + `const Name = "lib"`,
+		p + "func Keys[K comparable](Set[K]) []K",
+		p + "func New(string, ...Option) (*Config, error)",
+		p + "method (*Config) Open(int, int) error",
+		p + "method (Mode) String() string",
+		p + "method (Set[K]) Has(K) bool",
+		p + "type Alias = Mode",
+		p + "type Config struct",
+		p + "type Config struct, Hook func(string, int) error",
+		p + "type Config struct, Path string",
+		p + "type Config struct, embedded io.Reader",
+		p + "type Mode int",
+		p + "type Option func(*Config)",
+		p + "type Set[K comparable] map[K]struct{}",
+		p + "type Store interface { Get, Put, io.Closer, unexported methods }",
+		p + "type Store interface, Get(string) ([]byte, error)",
+		p + "type Store interface, Put(string, []byte) error",
+		p + "type Store interface, embedded io.Closer",
+		p + "var ErrBad error",
+	}, api.Features)
+
+	api, err = Dump(root, []string{"pkg/tool", "internal/..."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/m/internal/private"}, api.Packages)
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":         "module example.com/m\n",
+		"pkg/lib/lib.go": "package lib\n\nfunc Parse(s string) error { return nil }\n\nfunc Old() {}\n",
+	})
+	api, err := Dump(root, DefaultPatterns)
+	require.NoError(t, err)
+	writeFiles(t, root, map[string]string{DefaultFixture: string(Canonical(api))})
+
+	report, err := Check(api, root, DefaultFixture)
+	require.NoError(t, err)
+	assert.False(t, report.Breaking())
+	assert.Nil(t, report.Diff)
+	assert.Equal(t, 1, report.Packages)
+	assert.Equal(t, 2, report.Features)
+
+	// Adding a function is compatible.
+	writeFiles(t, root, map[string]string{"pkg/lib/new.go": "package lib\n\nfunc New() {}\n"})
+	api, err = Dump(root, DefaultPatterns)
+	require.NoError(t, err)
+	report, err = Check(api, root, DefaultFixture)
+	require.NoError(t, err)
+	assert.False(t, report.Breaking())
+	assert.Equal(t, []string{"pkg example.com/m/pkg/lib, func New()"}, report.Added)
+	require.NotNil(t, report.Diff)
+
+	// Changing a signature and removing a function are breaking.
+	writeFiles(t, root, map[string]string{"pkg/lib/lib.go": "package lib\n\nfunc Parse(s string, strict bool) error { return nil }\n"})
+	api, err = Dump(root, DefaultPatterns)
+	require.NoError(t, err)
+	report, err = Check(api, root, DefaultFixture)
+	require.NoError(t, err)
+	assert.True(t, report.Breaking())
+	assert.Equal(t, []string{
+		"pkg example.com/m/pkg/lib, func Old()",
+		"pkg example.com/m/pkg/lib, func Parse(string) error",
+	}, report.Removed)
+	assert.Equal(t, []string{
+		"pkg example.com/m/pkg/lib, func New()",
+		"pkg example.com/m/pkg/lib, func Parse(string, bool) error",
+	}, report.Added)
+	assert.Contains(t, report.Diff.String(), "-pkg example.com/m/pkg/lib, func Old()\n")
+
+	_, err = Check(api, root, "missing.txt")
+	assert.Error(t, err)
+}
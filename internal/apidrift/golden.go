@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package apidrift
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// DefaultFixture is the committed API baseline, relative to the
+// repository root.
+const DefaultFixture = "spec/fixtures/api/pkg.txt"
+
+// Report is the outcome of comparing an API with the baseline.
+type Report struct {
+	Packages int `json:"packages"`
+	Features int `json:"features"`
+	// Removed are baseline features the API no longer has: breaking changes.
+	Removed []string `json:"removed"`
+	// Added are features the baseline does not have yet.
+	Added []string `json:"added"`
+	// Diff goes from the baseline to the API; nil when they match.
+	Diff *gov.Diff `json:"diff"`
+}
+
+// Breaking reports whether a baseline feature was removed or changed.
+func (r *Report) Breaking() bool {
+	return len(r.Removed) > 0
+}
+
+// Canonical renders api as the baseline: one feature per line.
+func Canonical(api *API) []byte {
+	if len(api.Features) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(api.Features, "\n") + "\n")
+}
+
+// Check compares api with fixture, a path relative to repoRoot unless
+// absolute.
+func Check(api *API, repoRoot, fixture string) (*Report, error) {
+	path := fixture
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+
+	r := &Report{Packages: len(api.Packages), Features: len(api.Features), Removed: []string{}, Added: []string{}}
+	current := make(map[string]bool, len(api.Features))
+	for _, f := range api.Features {
+		current[f] = true
+	}
+	baseline := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		baseline[line] = true
+		if !current[line] {
+			r.Removed = append(r.Removed, line)
+		}
+	}
+	for _, f := range api.Features {
+		if !baseline[f] {
+			r.Added = append(r.Added, f)
+		}
+	}
+
+	if want, got := string(data), string(Canonical(api)); want != got {
+		d := gov.UnifiedDiff(fixture, "api", want, got, gov.DefaultDiffOptions())
+		r.Diff = &d
+	}
+	return r, nil
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package branchpolicy checks branch names against configured patterns and
+// attributes files to features for branch scope checks.
+package branchpolicy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/annotate"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/mapping"
+)
+
+// DefaultPatterns are used when branches.patterns is empty.
+var DefaultPatterns = []string{"feature/{feature}-*", "fix/*"}
+
+const featurePlaceholder = "{feature}"
+
+// Pattern is a compiled branch name pattern.
+type Pattern struct {
+	Raw string
+	re  *regexp.Regexp
+}
+
+// Compile compiles branch globs. "*" and "?" do not match "/", and
+// "{feature}" matches a SCREAMING_SNAKE_CASE feature ID at most once.
+func Compile(patterns []string) ([]Pattern, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultPatterns
+	}
+
+	out := make([]Pattern, 0, len(patterns))
+	for _, raw := range patterns {
+		if strings.Count(raw, featurePlaceholder) > 1 {
+			return nil, fmt.Errorf("branch pattern %q: %s may appear at most once", raw, featurePlaceholder)
+		}
+		var sb strings.Builder
+		sb.WriteString("^")
+		for rest := raw; rest != ""; {
+			switch {
+			case strings.HasPrefix(rest, featurePlaceholder):
+				sb.WriteString("([A-Z][A-Z0-9_]*)")
+				rest = rest[len(featurePlaceholder):]
+				continue
+			case rest[0] == '*':
+				sb.WriteString("[^/]*")
+			case rest[0] == '?':
+				sb.WriteString("[^/]")
+			default:
+				sb.WriteString(regexp.QuoteMeta(rest[:1]))
+			}
+			rest = rest[1:]
+		}
+		sb.WriteString("$")
+		out = append(out, Pattern{Raw: raw, re: regexp.MustCompile(sb.String())})
+	}
+	return out, nil
+}
+
+// Match reports whether branch matches one of patterns and returns the
+// feature ID captured by the first matching pattern, if it has one.
+func Match(patterns []Pattern, branch string) (feature string, ok bool) {
+	for _, p := range patterns {
+		if m := p.re.FindStringSubmatch(branch); m != nil {
+			if len(m) > 1 {
+				feature = m[1]
+			}
+			return feature, true
+		}
+	}
+	return "", false
+}
+
+// Owner returns the feature a repo-relative file belongs to: the first
+// matching features.annotate rule, else its Feature header. Files that no
+// longer exist or carry no header are unowned.
+func Owner(repoRoot, rel string, rules []config.AnnotateRule) string {
+	if feature, ok := annotate.Match(rules, rel); ok {
+		return feature
+	}
+	if !mapping.SupportsHeaders(rel) {
+		return ""
+	}
+	feature, _, err := mapping.ParseHeaders(filepath.Join(repoRoot, filepath.FromSlash(rel)))
+	if err != nil {
+		return ""
+	}
+	return feature
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package branchpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	patterns, err := Compile(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		branch  string
+		feature string
+		ok      bool
+	}{
+		{"feature/CLI_COMMAND_GOV-drift-json", "CLI_COMMAND_GOV", true},
+		{"fix/help-output", "", true},
+		{"feature/cli-gov-drift", "", false},
+		{"feature/CLI_GOV", "", false},
+		{"fix/nested/path", "", false},
+		{"main", "", false},
+	}
+	for _, tt := range tests {
+		feature, ok := Match(patterns, tt.branch)
+		assert.Equal(t, tt.ok, ok, tt.branch)
+		assert.Equal(t, tt.feature, feature, tt.branch)
+	}
+
+	patterns, err = Compile([]string{"release/v?.*", "{feature}/*"})
+	require.NoError(t, err)
+	_, ok := Match(patterns, "release/v1.2")
+	assert.True(t, ok)
+	feature, ok := Match(patterns, "CORE/cleanup")
+	assert.True(t, ok)
+	assert.Equal(t, "CORE", feature)
+
+	_, err = Compile([]string{"{feature}/{feature}"})
+	assert.Error(t, err)
+}
+
+func TestOwner(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "a.go"), []byte("package pkg\n\n// Feature: FROM_HEADER\n"), 0o644))
+
+	assert.Equal(t, "FROM_HEADER", Owner(root, "pkg/a.go", nil))
+	assert.Equal(t, "FROM_RULE", Owner(root, "pkg/a.go", []config.AnnotateRule{{Path: "pkg/", Feature: "FROM_RULE"}}))
+	assert.Equal(t, "", Owner(root, "pkg/deleted.go", nil))
+	assert.Equal(t, "", Owner(root, "README.md", nil))
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package budget prunes the XRAY file list to fit a context size budget.
+//
+// Files are ranked by the weights declared in .cortex/config.yaml
+// (context.budget) and admitted greedily until --max-files or --max-bytes is
+// reached. Everything left out is recorded in a pruning report so agents know
+// what the context does not contain.
+package budget
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// SchemaVersion is the version of the pruning report format.
+const SchemaVersion = "1.0"
+
+// Exclusion reasons.
+const (
+	ReasonMaxFiles = "max_files"
+	ReasonMaxBytes = "max_bytes"
+	// ReasonGenerated excludes generated files (scanner.Generated) before
+	// the limits apply.
+	ReasonGenerated = "generated"
+)
+
+// Report is .cortex/data/pruned.json.
+type Report struct {
+	SchemaVersion string     `json:"schema_version"`
+	MaxBytes      int64      `json:"max_bytes"`
+	MaxFiles      int        `json:"max_files"`
+	Included      Totals     `json:"included"`
+	Excluded      Totals     `json:"excluded"`
+	Files         []Excluded `json:"excluded_files"`
+}
+
+// Totals counts files and their bytes.
+type Totals struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// Excluded is a file left out of the context.
+type Excluded struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Priority int    `json:"priority"`
+	Reason   string `json:"reason"`
+}
+
+// Apply selects the files that fit policy. kept preserves the order of files;
+// the report lists excluded files sorted by path. recency maps paths to their
+// last commit time and is only consulted when policy.Recency is set.
+func Apply(files []xray.FileNode, policy config.Budget, recency map[string]int64) ([]xray.FileNode, *Report) {
+	report := &Report{
+		SchemaVersion: SchemaVersion,
+		MaxBytes:      policy.MaxBytes,
+		MaxFiles:      policy.MaxFiles,
+		Files:         []Excluded{},
+	}
+
+	ranked := make([]int, len(files))
+	priority := make([]int, len(files))
+	for i, f := range files {
+		ranked[i] = i
+		priority[i] = Priority(f, policy)
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		i, j := ranked[a], ranked[b]
+		if priority[i] != priority[j] {
+			return priority[i] > priority[j]
+		}
+		if policy.Recency && recency[files[i].Path] != recency[files[j].Path] {
+			return recency[files[i].Path] > recency[files[j].Path]
+		}
+		return files[i].Path < files[j].Path
+	})
+
+	keep := make([]bool, len(files))
+	for _, i := range ranked {
+		f := files[i]
+		reason := ""
+		switch {
+		case policy.MaxFiles > 0 && report.Included.Files >= policy.MaxFiles:
+			reason = ReasonMaxFiles
+		case policy.MaxBytes > 0 && report.Included.Bytes+f.Size > policy.MaxBytes:
+			// Smaller, lower-priority files may still fit.
+			reason = ReasonMaxBytes
+		}
+		if reason != "" {
+			report.Excluded.Files++
+			report.Excluded.Bytes += f.Size
+			report.Files = append(report.Files, Excluded{Path: f.Path, Size: f.Size, Priority: priority[i], Reason: reason})
+			continue
+		}
+		keep[i] = true
+		report.Included.Files++
+		report.Included.Bytes += f.Size
+	}
+
+	kept := make([]xray.FileNode, 0, report.Included.Files)
+	for i, f := range files {
+		if keep[i] {
+			kept = append(kept, f)
+		}
+	}
+	sort.Slice(report.Files, func(a, b int) bool { return report.Files[a].Path < report.Files[b].Path })
+	return kept, report
+}
+
+// Exclude records files left out of the context for reason before Apply
+// ran, keeping the report sorted by path.
+func (r *Report) Exclude(files []xray.FileNode, policy config.Budget, reason string) {
+	for _, f := range files {
+		r.Excluded.Files++
+		r.Excluded.Bytes += f.Size
+		r.Files = append(r.Files, Excluded{Path: f.Path, Size: f.Size, Priority: Priority(f, policy), Reason: reason})
+	}
+	sort.Slice(r.Files, func(a, b int) bool { return r.Files[a].Path < r.Files[b].Path })
+}
+
+// Priority returns the weight of f under policy: the weight of the longest
+// matching directory plus the weight of its extension (or, failing that, its
+// language).
+func Priority(f xray.FileNode, policy config.Budget) int {
+	p := 0
+	best := -1
+	for dir, w := range policy.Dirs {
+		dir = strings.Trim(path.Clean(dir), "/")
+		if dir == "." || dir == "" {
+			if best < 0 {
+				p, best = w, 0
+			}
+			continue
+		}
+		if (f.Path == dir || strings.HasPrefix(f.Path, dir+"/")) && len(dir) > best {
+			p, best = w, len(dir)
+		}
+	}
+	if w, ok := policy.Types[path.Ext(f.Path)]; ok {
+		return p + w
+	}
+	return p + policy.Types[f.Lang]
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package budget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func paths(files []xray.FileNode) []string {
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		out = append(out, f.Path)
+	}
+	return out
+}
+
+func TestPriority(t *testing.T) {
+	policy := config.Budget{
+		Dirs:  map[string]int{".": 1, "internal": 5, "internal/gen/": -10},
+		Types: map[string]int{".go": 3, "Markdown": 2, ".md": 1},
+	}
+	assert.Equal(t, 1, Priority(xray.FileNode{Path: "LICENSE"}, policy))
+	assert.Equal(t, 8, Priority(xray.FileNode{Path: "internal/x.go", Lang: "Go"}, policy))
+	assert.Equal(t, -7, Priority(xray.FileNode{Path: "internal/gen/x.go", Lang: "Go"}, policy))
+	assert.Equal(t, 4, Priority(xray.FileNode{Path: "internalx/y.go"}, policy))
+	// Extension wins over language.
+	assert.Equal(t, 2, Priority(xray.FileNode{Path: "README.md", Lang: "Markdown"}, policy))
+}
+
+func TestApply(t *testing.T) {
+	files := []xray.FileNode{
+		{Path: "a.go", Size: 50},
+		{Path: "b.md", Size: 40},
+		{Path: "c.go", Size: 30},
+		{Path: "d.txt", Size: 10},
+	}
+
+	kept, report := Apply(files, config.Budget{}, nil)
+	assert.Equal(t, files, kept)
+	assert.Empty(t, report.Files)
+	assert.Equal(t, Totals{Files: 4, Bytes: 130}, report.Included)
+
+	policy := config.Budget{MaxBytes: 70, Types: map[string]int{".go": 1}}
+	kept, report = Apply(files, policy, nil)
+	// a.go (50) fits, c.go (30) does not, b.md (40) does not, d.txt (10) fits.
+	assert.Equal(t, []string{"a.go", "d.txt"}, paths(kept))
+	assert.Equal(t, []Excluded{
+		{Path: "b.md", Size: 40, Priority: 0, Reason: ReasonMaxBytes},
+		{Path: "c.go", Size: 30, Priority: 1, Reason: ReasonMaxBytes},
+	}, report.Files)
+	assert.Equal(t, Totals{Files: 2, Bytes: 70}, report.Excluded)
+
+	policy = config.Budget{MaxFiles: 2, Recency: true}
+	recency := map[string]int64{"d.txt": 300, "b.md": 200}
+	kept, report = Apply(files, policy, recency)
+	assert.Equal(t, []string{"b.md", "d.txt"}, paths(kept))
+	assert.Equal(t, ReasonMaxFiles, report.Files[0].Reason)
+
+	// Recency is ignored unless enabled.
+	policy.Recency = false
+	kept, _ = Apply(files, policy, recency)
+	assert.Equal(t, []string{"a.go", "b.md"}, paths(kept))
+}
+
+func TestReportExclude(t *testing.T) {
+	policy := config.Budget{MaxFiles: 1, Types: map[string]int{".go": 1}}
+	_, report := Apply([]xray.FileNode{{Path: "a.go", Size: 5}, {Path: "c.go", Size: 7}}, policy, nil)
+	report.Exclude([]xray.FileNode{{Path: "b.pb.go", Size: 100}}, policy, ReasonGenerated)
+	assert.Equal(t, []Excluded{
+		{Path: "b.pb.go", Size: 100, Priority: 1, Reason: ReasonGenerated},
+		{Path: "c.go", Size: 7, Priority: 1, Reason: ReasonMaxFiles},
+	}, report.Files)
+	assert.Equal(t, Totals{Files: 2, Bytes: 107}, report.Excluded)
+	assert.Equal(t, Totals{Files: 1, Bytes: 5}, report.Included)
+}
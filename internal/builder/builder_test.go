@@ -6,8 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/bartekus/cortex/internal/budget"
 	"github.com/bartekus/cortex/internal/builder"
+	"github.com/bartekus/cortex/internal/scanner"
 	"github.com/bartekus/cortex/internal/xray"
 )
 
@@ -44,7 +47,7 @@ func TestBuildContext_Golden(t *testing.T) {
 	// Optionally check content
 
 	// Verify manifest.json
-	manifestPath := filepath.Join(tempDir, ".cortex", "files", "manifest.json")
+	manifestPath := filepath.Join(tempDir, ".cortex", "data", "manifest.json")
 	assertFileExists(t, manifestPath)
 
 	bytes, _ := os.ReadFile(manifestPath)
@@ -76,3 +79,114 @@ func assertFileExists(t *testing.T, path string) {
 		t.Errorf("Expected file at %s, but missing", path)
 	}
 }
+
+func TestBuildContext_SkipsGenerated(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"main.go":     "package main\n",
+		"api.pb.go":   "package main\n",
+		"mock_api.go": "// Code generated by mockgen. DO NOT EDIT.\n\npackage main\n",
+	}
+	index := &xray.Index{SchemaVersion: "1.0.0", Root: tempDir}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		index.Files = append(index.Files, xray.FileNode{Path: rel, Size: int64(len(content))})
+	}
+
+	stats, err := builder.BuildContextWithOptions(tempDir, index, builder.Options{
+		Generated: scanner.NewGenerated(tempDir, []string{"*.pb.go"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Files != 1 {
+		t.Errorf("Expected only main.go in the context, got %d files", stats.Files)
+	}
+	var got []string
+	for _, e := range stats.Pruned.Files {
+		if e.Reason != budget.ReasonGenerated {
+			t.Errorf("Unexpected reason %q for %s", e.Reason, e.Path)
+		}
+		got = append(got, e.Path)
+	}
+	if len(got) != 2 || got[0] != "api.pb.go" || got[1] != "mock_api.go" {
+		t.Errorf("Expected generated files in pruned.json, got %v", got)
+	}
+}
+
+func TestBuildContext_IncrementalMatchesFull(t *testing.T) {
+	files := map[string]string{
+		"go.mod":      "module example.com/x\n\ngo 1.24\n",
+		"main.go":     "package main\n\nfunc main() { helper() }\n",
+		"helper.go":   "package main\n\nfunc helper() {}\n",
+		"README.md":   "# X\n\nIntro.\n\n## Usage\n\nRun it.\n",
+		"docs/doc.md": "# Doc\n",
+	}
+	write := func(root string) {
+		for rel, content := range files {
+			path := filepath.Join(root, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	build := func(root string, cache *builder.Cache) *builder.Stats {
+		started := time.Now()
+		index, scanned, err := xray.ScanWith(root, ".", cache.ScanOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := builder.BuildContextWithOptions(root, index, builder.Options{Previous: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := builder.SaveCache(root, builder.NewCache(started, scanned)); err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+
+	// Both trees share a base name so meta.json matches.
+	incremental := filepath.Join(t.TempDir(), "repo")
+	write(incremental)
+	build(incremental, nil)
+
+	files["helper.go"] = "package main\n\n// helper does nothing.\nfunc helper() {}\n"
+	files["docs/new.md"] = "# New\n"
+	write(incremental)
+	stats := build(incremental, builder.LoadCache(incremental))
+	if stats.ReusedFiles == 0 || stats.ReusedFiles == stats.Files {
+		t.Errorf("expected partial reuse, got %d/%d", stats.ReusedFiles, stats.Files)
+	}
+
+	full := filepath.Join(t.TempDir(), "repo")
+	write(full)
+	build(full, nil)
+
+	for _, rel := range []string{
+		"meta.json",
+		"digest.txt",
+		"data/manifest.json",
+		"data/chunks.ndjson",
+		"data/symbols.json",
+		"data/dependencies.json",
+		"repo-map.md",
+	} {
+		want, err := os.ReadFile(filepath.Join(full, ".cortex", rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(incremental, ".cortex", rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs between incremental and full build", rel)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package builder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// CacheSchemaVersion is the version of .cortex/data/cache.json.
+const CacheSchemaVersion = "1.0"
+
+// Cache is .cortex/data/cache.json: the per-file digest cache used by
+// incremental context builds. It is not part of digest.txt.
+type Cache struct {
+	SchemaVersion  string `json:"schema_version"`
+	ChunkerVersion string `json:"chunker_version"`
+	// ScannedAt is when the scan that produced Files started (Unix nanoseconds).
+	ScannedAt int64                      `json:"scanned_at"`
+	Files     map[string]xray.CachedFile `json:"files"`
+}
+
+// CachePath returns the cache location under repoRoot.
+func CachePath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".cortex", "data", "cache.json")
+}
+
+// LoadCache reads the cache. A missing, unreadable or outdated cache yields
+// nil, which callers treat as "full build".
+func LoadCache(repoRoot string) *Cache {
+	data, err := os.ReadFile(CachePath(repoRoot))
+	if err != nil {
+		return nil
+	}
+	var c Cache
+	if err := json.Unmarshal(data, &c); err != nil || c.SchemaVersion != CacheSchemaVersion {
+		return nil
+	}
+	return &c
+}
+
+// NewCache returns a cache for a scan that started at scannedAt.
+func NewCache(scannedAt time.Time, files map[string]xray.CachedFile) *Cache {
+	if files == nil {
+		files = map[string]xray.CachedFile{}
+	}
+	return &Cache{
+		SchemaVersion:  CacheSchemaVersion,
+		ChunkerVersion: chunker.Version,
+		ScannedAt:      scannedAt.UnixNano(),
+		Files:          files,
+	}
+}
+
+// ScanOptions turns the cache into options for an incremental scan.
+// A nil cache yields options that re-read every file.
+func (c *Cache) ScanOptions() xray.ScanOptions {
+	if c == nil {
+		return xray.ScanOptions{}
+	}
+	return xray.ScanOptions{
+		Cache:       c.Files,
+		TrustBefore: time.Unix(0, c.ScannedAt).Add(-time.Second),
+	}
+}
+
+// SaveCache writes the cache atomically.
+func SaveCache(repoRoot string, c *Cache) error {
+	_, err := writeJSON(CachePath(repoRoot), c)
+	return err
+}
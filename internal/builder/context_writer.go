@@ -2,17 +2,22 @@
 package builder
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
-	"unicode/utf8"
 
+	"github.com/bartekus/cortex/internal/budget"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/depgraph"
+	"github.com/bartekus/cortex/internal/repomap"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/symbols"
+	"github.com/bartekus/cortex/internal/tokens"
 	"github.com/bartekus/cortex/internal/xray"
 )
 
@@ -20,167 +25,175 @@ import (
 type Meta struct {
 	ProjectName string `json:"project_name"`
 	Generator   string `json:"generator"`
+	// Tokenizer names the tokenizer of the token counts in manifest.json.
+	Tokenizer string `json:"tokenizer"`
 }
 
-// ManifestEntry represents an item in .cortex/files/manifest.json
-type ManifestEntry struct {
-	Path string `json:"path"`
-	Hash string `json:"hash"`
+// ManifestEntry represents an item in .cortex/data/manifest.json
+type ManifestEntry = chunker.ManifestEntry
+
+// Chunk represents a segment of code in .cortex/data/chunks.ndjson
+type Chunk = chunker.Chunk
+
+// Options controls BuildContextWithOptions.
+type Options struct {
+	// Previous is the cache from the last build. Chunks of files whose hash is
+	// unchanged are reused when it was written by the same chunker version.
+	// nil forces a full rebuild.
+	Previous *Cache
+	// Budget limits which files enter the context; see package budget.
+	// The zero value keeps every file.
+	Budget config.Budget
+	// Recency maps paths to their last commit time for Budget.Recency.
+	Recency map[string]int64
+	// Files decides which files are too large to chunk.
+	Files scanner.Policy
+	// Generated excludes generated files from the context, recorded in
+	// pruned.json with reason generated; nil keeps them.
+	Generated *scanner.Generated
+	// Dir is the directory the context is written to; empty means
+	// repoRoot/.cortex.
+	Dir string
+	// Tokens counts the tokens of each file for manifest.json; nil means
+	// tokens.Estimate.
+	Tokens tokens.Counter
 }
 
-// BuildContext generates the deterministic .cortex/ structure.
+// Stats reports what a build kept, pruned and reused.
+type Stats struct {
+	Files       int // files in the context after pruning
+	ReusedFiles int // files whose chunks were reused
+	Pruned      *budget.Report
+}
+
+// Outputs are the repo-relative files BuildContextWithOptions writes.
+var Outputs = []string{
+	".cortex/data/chunks.ndjson",
+	".cortex/data/dependencies.json",
+	".cortex/data/manifest.json",
+	".cortex/data/pruned.json",
+	".cortex/data/symbols.json",
+	".cortex/digest.txt",
+	".cortex/meta.json",
+	".cortex/" + repomap.FileName,
+}
+
+// BuildContext generates the deterministic .cortex/ structure from scratch.
 func BuildContext(repoRoot string, index *xray.Index) error {
-	ctxDir := filepath.Join(repoRoot, ".cortex")
-	if err := os.MkdirAll(filepath.Join(ctxDir, "files"), 0o755); err != nil {
-		return fmt.Errorf("creating context structure: %w", err)
+	_, err := BuildContextWithOptions(repoRoot, index, Options{})
+	return err
+}
+
+// BuildContextWithOptions generates the .cortex/ structure, reusing previous
+// outputs where possible. The written artifacts are byte-identical to those
+// of a full build.
+func BuildContextWithOptions(repoRoot string, index *xray.Index, opts Options) (*Stats, error) {
+	ctxDir := opts.Dir
+	if ctxDir == "" {
+		ctxDir = filepath.Join(repoRoot, ".cortex")
+	}
+	if err := os.MkdirAll(ctxDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating context structure: %w", err)
+	}
+
+	counter := opts.Tokens
+	if counter == nil {
+		counter = tokens.Estimate{}
 	}
 
 	// 1. Generate meta.json
 	meta := Meta{
 		ProjectName: filepath.Base(repoRoot),
 		Generator:   "cortex-v0.1.0",
+		Tokenizer:   counter.Name(),
 	}
 	metaBytes, err := writeJSON(filepath.Join(ctxDir, "meta.json"), meta)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 2. Generate files/manifest.json
-	// Sort files by path (XRAY index should be sorted, but enforce it)
-	// We preserve the ordering from XRAY if it's already sorted, but explicit sort ensures safety.
-	manifest := make([]ManifestEntry, 0, len(index.Files))
-	for _, f := range index.Files {
-		manifest = append(manifest, ManifestEntry{
-			Path: f.Path,
-			Hash: f.Hash,
-		})
+	// 2. Apply the size budget; everything downstream only sees kept files.
+	authored, generated := index.Files, []xray.FileNode(nil)
+	if opts.Generated != nil {
+		authored = nil
+		for _, f := range index.Files {
+			if opts.Generated.IsGenerated(f.Path) {
+				generated = append(generated, f)
+			} else {
+				authored = append(authored, f)
+			}
+		}
+	}
+	files, pruned := budget.Apply(authored, opts.Budget, opts.Recency)
+	pruned.Exclude(generated, opts.Budget, budget.ReasonGenerated)
+	if _, err := writeJSON(filepath.Join(ctxDir, "data", "pruned.json"), pruned); err != nil {
+		return nil, err
 	}
-	sort.Slice(manifest, func(i, j int) bool {
-		return manifest[i].Path < manifest[j].Path
-	})
 
-	manifestBytes, err := writeJSON(filepath.Join(ctxDir, "files", "manifest.json"), manifest)
+	// 3. Generate data/manifest.json and data/chunks.ndjson
+	// Ordering: manifest sorted by path, chunks by path then start line.
+	var previous *chunker.Result
+	if opts.Previous != nil && opts.Previous.ChunkerVersion == chunker.Version {
+		// A missing or unreadable previous output just means a full chunking pass.
+		previous, _ = chunker.Read(filepath.Join(ctxDir, "data"))
+	}
+	chunks, reused, err := chunker.BuildIncremental(repoRoot, files, previous, opts.Files)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	// Token counts are not cached: the tokenizer may have changed.
+	counts := make(map[string]int)
+	for _, c := range chunks.Chunks {
+		counts[c.FilePath] += counter.Count(c.Content)
+	}
+	for i := range chunks.Manifest {
+		chunks.Manifest[i].Tokens = counts[chunks.Manifest[i].Path]
+	}
+	manifestBytes, chunksBytes, err := chunker.Write(filepath.Join(ctxDir, "data"), chunks)
+	if err != nil {
+		return nil, err
 	}
 
-	// 3. Generate files/chunks.ndjson
-	// Loop manifest, read file, chunk.
-	// Contract: Max 200 lines. UTF-8 only.
-	// Ordering: Manifest order (Sorted), then StartLine.
-
-	var chunksBuffer []byte
-	for _, item := range manifest {
-		fullPath := filepath.Join(repoRoot, item.Path)
-
-		// Read file
-		content, err := os.ReadFile(fullPath)
-		if err != nil {
-			// If file missing (race condition vs XRAY?), skip or error?
-			// XRAY index says it exists. Error is safer.
-			return fmt.Errorf("reading source file %s: %w", item.Path, err)
-		}
-
-		// Skip binary / invalid UTF-8 (simplistic check: standard library utf8.Valid)
-		// Contract says: "Text-only: define 'binary' as 'invalid UTF-8'"
-		// Actually utf8.Valid(content) is better.
-		if !isText(content) {
-			continue
-		}
-
-		// Skip huge files? User said: "pick a cap... reuse 2MB from LOC".
-		// Let's explicitly skip > 2MB for now to be safe.
-		const MaxFileSize = 2 * 1024 * 1024
-		if len(content) > MaxFileSize {
-			continue
-		}
+	// 4. Generate data/symbols.json (Go symbol graph; carries its own digest)
+	graph, err := symbols.Extract(repoRoot, files)
+	if err != nil {
+		return nil, fmt.Errorf("extracting symbols: %w", err)
+	}
+	if _, err := writeJSON(filepath.Join(ctxDir, "data", "symbols.json"), graph); err != nil {
+		return nil, err
+	}
 
-		fileChunks := chunkContent(item.Path, string(content))
-		for _, c := range fileChunks {
-			// Marshal individually for NDJSON
-			line, err := json.Marshal(c)
-			if err != nil {
-				return fmt.Errorf("marshaling chunk: %w", err)
-			}
-			chunksBuffer = append(chunksBuffer, line...)
-			chunksBuffer = append(chunksBuffer, '\n')
-		}
+	// 5. Generate data/dependencies.json (Go import graph + module requirements)
+	deps, err := depgraph.Extract(repoRoot, files)
+	if err != nil {
+		return nil, fmt.Errorf("extracting dependencies: %w", err)
+	}
+	if _, err := writeJSON(filepath.Join(ctxDir, "data", "dependencies.json"), deps); err != nil {
+		return nil, err
 	}
 
-	chunksPath := filepath.Join(ctxDir, "files", "chunks.ndjson")
-	if err := writeFileAtomic(chunksPath, chunksBuffer, 0o644); err != nil {
-		return fmt.Errorf("writing chunks.ndjson: %w", err)
+	// 6. Generate repo-map.md (navigational summary of the kept files)
+	repoMap, err := repomap.Build(repoRoot, files)
+	if err != nil {
+		return nil, fmt.Errorf("building repo map: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(ctxDir, repomap.FileName), []byte(repomap.Render(repoMap)), 0o644); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", repomap.FileName, err)
 	}
 
-	// 4. Generate digest.txt
+	// 7. Generate digest.txt
 	// Digest is SHA-256 over the exact bytes written for: manifest.json then meta.json then chunks.ndjson
 	hasher := sha256.New()
 	_, _ = hasher.Write(manifestBytes)
 	_, _ = hasher.Write(metaBytes)
-	_, _ = hasher.Write(chunksBuffer)
+	_, _ = hasher.Write(chunksBytes)
 	digest := hex.EncodeToString(hasher.Sum(nil))
 
 	if err := writeFileAtomic(filepath.Join(ctxDir, "digest.txt"), []byte(digest+"\n"), 0o644); err != nil {
-		return fmt.Errorf("writing digest.txt: %w", err)
+		return nil, fmt.Errorf("writing digest.txt: %w", err)
 	}
 
-	return nil
-}
-
-// Chunk represents a segment of code in chunks.ndjson
-type Chunk struct {
-	FilePath  string `json:"file_path"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
-	Content   string `json:"content"`
-}
-
-func isText(b []byte) bool {
-	// Basic null byte check + UTF8 validity
-	// XRAY does this too.
-	if bytes.IndexByte(b, 0) >= 0 {
-		return false
-	}
-	// Also use utf8.ValidString if we want to be strict
-	return utf8.Valid(b)
-}
-
-func chunkContent(path, content string) []Chunk {
-	// Normalize newlines to \n
-	content = strings.ReplaceAll(content, "\r\n", "\n")
-
-	lines := strings.Split(content, "\n")
-	// If last line is empty (due to trailing newline), keep it?
-	// Usually editors treat trailing newline as end of line.
-	// XRAY might count LOC differently.
-	// For context, we want textual representation.
-	// If split gives empty string at end, it means content file ended with \n.
-
-	// Chunk by 200
-	const ChunkSize = 200
-	var chunks []Chunk
-
-	totalLines := len(lines)
-	for i := 0; i < totalLines; i += ChunkSize {
-		end := i + ChunkSize
-		if end > totalLines {
-			end = totalLines
-		}
-
-		// Join lines back
-		// We need to preserve newlines between lines.
-		// Slice is lines[i:end]
-		chunkLines := lines[i:end]
-		chunkStr := strings.Join(chunkLines, "\n")
-
-		chunks = append(chunks, Chunk{
-			FilePath:  path,
-			StartLine: i + 1,
-			EndLine:   i + len(chunkLines), // Actual end line
-			Content:   chunkStr,
-		})
-	}
-	return chunks
+	return &Stats{Files: len(files), ReusedFiles: reused, Pruned: pruned}, nil
 }
 
 // writeJSON marshals and writes a file with consistent indentation.
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bartekus/cortex/internal/canonjson"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+// ProvenanceFile is the repo-relative path of the provenance attestation.
+const ProvenanceFile = ".cortex/provenance.json"
+
+// ProvenanceSchemaVersion is the schema_version of ProvenanceFile.
+const ProvenanceSchemaVersion = "1.0"
+
+// Provenance is .cortex/provenance.json: what a context build was generated
+// from and the digests of what it wrote. Building again from the same
+// inputs must reproduce every output byte for byte.
+type Provenance struct {
+	SchemaVersion string `json:"schema_version"`
+	// Fingerprint is the fingerprint digest of the repository before the
+	// build; empty outside git.
+	Fingerprint   string `json:"fingerprint,omitempty"`
+	CortexVersion string `json:"cortex_version"`
+	// Engine is the scan engine, "xray" or "go".
+	Engine string `json:"engine"`
+	// ConfigDigest is "sha256:<hex>" of the effective configuration, see
+	// ConfigDigest.
+	ConfigDigest string `json:"config_digest"`
+	// MaxBytes and MaxFiles are the budget limits of the build, after the
+	// command-line overrides.
+	MaxBytes int64 `json:"max_bytes"`
+	MaxFiles int   `json:"max_files"`
+	// Outputs are sorted by path.
+	Outputs []Output `json:"outputs"`
+}
+
+// Output is the digest of one artifact.
+type Output struct {
+	// Path is repo-relative, with forward slashes.
+	Path string `json:"path"`
+	// Digest is "sha256:<hex>" of the file.
+	Digest string `json:"digest"`
+}
+
+// ConfigDigest returns "sha256:<hex>" of the canonical JSON of cfg, the
+// configuration after profiles and environment overrides.
+func ConfigDigest(cfg *config.Config) (string, error) {
+	data, err := canonjson.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// DigestOutputs digests the files at paths, relative to root, sorted by
+// path.
+func DigestOutputs(root string, paths []string) ([]Output, error) {
+	outputs := make([]Output, 0, len(paths))
+	for _, p := range paths {
+		digest, err := fileDigest(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, fmt.Errorf("digesting %s: %w", p, err)
+		}
+		outputs = append(outputs, Output{Path: p, Digest: digest})
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Path < outputs[j].Path })
+	return outputs, nil
+}
+
+// Mismatches returns the paths of the outputs of p whose digest differs
+// in outputs or that outputs lacks, sorted.
+func (p *Provenance) Mismatches(outputs []Output) []string {
+	got := make(map[string]string, len(outputs))
+	for _, o := range outputs {
+		got[o.Path] = o.Digest
+	}
+	var paths []string
+	for _, o := range p.Outputs {
+		if got[o.Path] != o.Digest {
+			paths = append(paths, o.Path)
+		}
+	}
+	return paths
+}
+
+// ProvenancePath returns the provenance location under repoRoot.
+func ProvenancePath(repoRoot string) string {
+	return filepath.Join(repoRoot, filepath.FromSlash(ProvenanceFile))
+}
+
+// WriteProvenance stamps the schema version and writes p atomically.
+func WriteProvenance(repoRoot string, p *Provenance) error {
+	p.SchemaVersion = ProvenanceSchemaVersion
+	_, err := writeJSON(ProvenancePath(repoRoot), p)
+	return err
+}
+
+// ReadProvenance loads and validates the provenance of repoRoot.
+func ReadProvenance(repoRoot string) (*Provenance, error) {
+	data, err := os.ReadFile(ProvenancePath(repoRoot))
+	if err != nil {
+		return nil, err
+	}
+	var p Provenance
+	if err := schemas.Decode(schemas.Provenance, data, &p); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", ProvenanceFile, err)
+	}
+	return &p, nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
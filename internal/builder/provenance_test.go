@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package builder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/builder"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func TestProvenance_Reproducible(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "A.txt"), []byte("content A"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	index := &xray.Index{SchemaVersion: "1.0.0", Files: []xray.FileNode{{Path: "A.txt", Hash: "sha256:aaa", Size: 9}}}
+
+	if _, err := builder.BuildContextWithOptions(repo, index, builder.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := builder.DigestOutputs(repo, builder.Outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := builder.ConfigDigest(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &builder.Provenance{CortexVersion: "1.2.3", Engine: xray.EngineGo, ConfigDigest: digest, Outputs: outputs}
+	if err := builder.WriteProvenance(repo, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := builder.ReadProvenance(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SchemaVersion != builder.ProvenanceSchemaVersion || len(got.Outputs) != len(builder.Outputs) {
+		t.Fatalf("round trip: got %+v", got)
+	}
+
+	// A rebuild into another directory reproduces every output.
+	tmp := t.TempDir()
+	if _, err := builder.BuildContextWithOptions(repo, index, builder.Options{Dir: filepath.Join(tmp, ".cortex")}); err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := builder.DigestOutputs(tmp, builder.Outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := got.Mismatches(rebuilt); len(m) != 0 {
+		t.Errorf("rebuild differs: %v", m)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, ".cortex", "digest.txt"), []byte("tampered\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	onDisk, err := builder.DigestOutputs(repo, builder.Outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := got.Mismatches(onDisk); len(m) != 1 || m[0] != ".cortex/digest.txt" {
+		t.Errorf("Mismatches = %v, want [.cortex/digest.txt]", m)
+	}
+}
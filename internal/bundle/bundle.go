@@ -0,0 +1,389 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package bundle packs the governance state of a repository (context
+// artifacts, reports and the feature registry) into a deterministic tar
+// archive with a digest manifest, and verifies and unpacks such archives.
+package bundle
+
+// Feature: CLI_COMMAND_PACK
+// Spec: spec/cli/pack.md
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/bartekus/cortex/internal/canonjson"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+// ManifestName is the first entry of every bundle.
+const ManifestName = "cortex-bundle.json"
+
+// SchemaVersion is the schema_version of the manifest.
+const SchemaVersion = "1.0"
+
+// members are the repository paths a bundle holds when they exist, besides
+// everything under reportsDir. Caches, run state and the cortex-mcp store
+// are machine-local and left out.
+var members = []string{
+	".cortex/data/chunks.ndjson",
+	".cortex/data/dependencies.json",
+	".cortex/data/index.json",
+	".cortex/data/manifest.json",
+	".cortex/data/pruned.json",
+	".cortex/data/symbols.json",
+	".cortex/digest.txt",
+	".cortex/index/meta.json",
+	".cortex/index/vectors.ndjson",
+	".cortex/meta.json",
+	".cortex/provenance.json",
+	".cortex/repo-map.md",
+	"spec/features.yaml",
+}
+
+const reportsDir = ".cortex/reports"
+
+// ErrInvalid marks a bundle that fails verification.
+var ErrInvalid = errors.New("invalid bundle")
+
+// ErrConflict marks an unpack that would overwrite different content.
+var ErrConflict = errors.New("bundle conflicts with existing files")
+
+// Manifest lists the files of a bundle in archive order.
+type Manifest struct {
+	SchemaVersion string `json:"schema_version"`
+	Files         []File `json:"files"`
+}
+
+// File is one bundled file.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"` // sha256:<hex> of the content
+}
+
+// Digest identifies the bundle: sha256:<hex> of the canonical JSON of the
+// manifest. Bundles of the same files have the same digest whatever their
+// compression.
+func (m *Manifest) Digest() (string, error) {
+	sum, err := canonjson.Sum256(m)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + sum, nil
+}
+
+// Compression is the codec around the tar stream.
+type Compression string
+
+// Supported compressions.
+const (
+	Zstd Compression = "zstd"
+	Gzip Compression = "gzip"
+	None Compression = "none"
+)
+
+// CompressionFor picks the compression from a bundle file name:
+// .tar.zst/.tzst, .tar.gz/.tgz or .tar.
+func CompressionFor(name string) (Compression, error) {
+	switch {
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		return Zstd, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return Gzip, nil
+	case strings.HasSuffix(name, ".tar"):
+		return None, nil
+	}
+	return "", fmt.Errorf("unsupported bundle name %q: use .tar.zst, .tar.gz or .tar", name)
+}
+
+// Members returns the repository paths to bundle that exist under
+// repoRoot, sorted.
+func Members(repoRoot string) ([]string, error) {
+	var paths []string
+	for _, p := range members {
+		info, err := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(p)))
+		switch {
+		case err == nil && info.Mode().IsRegular():
+			paths = append(paths, p)
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+	}
+	err := filepath.WalkDir(filepath.Join(repoRoot, reportsDir), func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == filepath.Join(repoRoot, reportsDir) {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(repoRoot, p)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing reports: %w", err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Pack writes a bundle of the given repository paths to w: the manifest,
+// then the files in order. Entries carry no timestamps or owners, so the
+// same files always produce the same bytes.
+func Pack(w io.Writer, repoRoot string, paths []string, c Compression) (*Manifest, error) {
+	m := &Manifest{SchemaVersion: SchemaVersion, Files: make([]File, 0, len(paths))}
+	contents := make([][]byte, len(paths))
+	for i, p := range paths {
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = data
+		m.Files = append(m.Files, File{Path: p, Size: int64(len(data)), SHA256: digest(data)})
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	cw, err := compressor(w, c)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(cw)
+	if err := writeEntry(tw, ManifestName, append(manifest, '\n')); err != nil {
+		return nil, err
+	}
+	for i, f := range m.Files {
+		if err := writeEntry(tw, f.Path, contents[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := cw.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Unix(0, 0),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Read reads the bundle in r and verifies it: the manifest comes first and
+// matches its schema, and the archive holds exactly the files it lists, in
+// order, with their sizes and digests. It returns the manifest and the
+// contents of the files in manifest order. Verification failures wrap
+// ErrInvalid.
+func Read(r io.Reader, c Compression) (*Manifest, [][]byte, error) {
+	dr, err := decompressor(r, c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	defer func() { _ = dr.Close() }()
+	tr := tar.NewReader(dr)
+
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+	}
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, nil, invalid("reading manifest: %v", err)
+	}
+	if hdr.Name != ManifestName {
+		return nil, nil, invalid("first entry is %q, not %s", hdr.Name, ManifestName)
+	}
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, nil, invalid("reading manifest: %v", err)
+	}
+	var m Manifest
+	if err := schemas.Decode(schemas.BundleManifest, data, &m); err != nil {
+		return nil, nil, invalid("%v", err)
+	}
+
+	contents := make([][]byte, 0, len(m.Files))
+	for i := 0; ; i++ {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			if i < len(m.Files) {
+				return nil, nil, invalid("%s is missing", m.Files[i].Path)
+			}
+			return &m, contents, nil
+		}
+		if err != nil {
+			return nil, nil, invalid("reading entry %d: %v", i+1, err)
+		}
+		if i >= len(m.Files) {
+			return nil, nil, invalid("%s is not in the manifest", hdr.Name)
+		}
+		f := m.Files[i]
+		switch {
+		case !validPath(f.Path):
+			return nil, nil, invalid("unsafe path %q", f.Path)
+		case !isMember(f.Path):
+			return nil, nil, invalid("%s is not a bundle member", f.Path)
+		case hdr.Name != f.Path:
+			return nil, nil, invalid("entry %d is %q, manifest lists %q", i+1, hdr.Name, f.Path)
+		case hdr.Typeflag != tar.TypeReg:
+			return nil, nil, invalid("%s is not a regular file", hdr.Name)
+		case hdr.Size != f.Size:
+			return nil, nil, invalid("%s: size %d, manifest lists %d", f.Path, hdr.Size, f.Size)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, invalid("reading %s: %v", f.Path, err)
+		}
+		if got := digest(data); got != f.SHA256 {
+			return nil, nil, invalid("%s: digest %s, manifest lists %s", f.Path, got, f.SHA256)
+		}
+		contents = append(contents, data)
+	}
+}
+
+// Unpack writes the files of a verified bundle under dir. Files that exist
+// with other content are only replaced with force; otherwise nothing is
+// written and the error, wrapping ErrConflict, lists them. Paths Pack could
+// not have produced, and paths below an existing symlinked directory, are
+// rejected before anything is written, with errors wrapping ErrInvalid.
+func Unpack(dir string, m *Manifest, contents [][]byte, force bool) error {
+	for _, f := range m.Files {
+		if !validPath(f.Path) || !isMember(f.Path) {
+			return fmt.Errorf("%w: %s is not a bundle member", ErrInvalid, f.Path)
+		}
+		if err := checkParents(dir, f.Path); err != nil {
+			return err
+		}
+	}
+	if !force {
+		var conflicts []string
+		for i, f := range m.Files {
+			existing, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+			if err == nil && !bytes.Equal(existing, contents[i]) {
+				conflicts = append(conflicts, f.Path)
+			}
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("%w (use --force to replace): %s", ErrConflict, strings.Join(conflicts, ", "))
+		}
+	}
+	for i, f := range m.Files {
+		if err := projection.AtomicWrite(filepath.Join(dir, filepath.FromSlash(f.Path)), contents[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validPath accepts clean, relative, slash-separated paths that stay inside
+// the unpack directory.
+func validPath(p string) bool {
+	return p != "" && p != ManifestName && !path.IsAbs(p) && path.Clean(p) == p &&
+		p != ".." && !strings.HasPrefix(p, "../") && !strings.Contains(p, `\`)
+}
+
+// isMember reports whether Pack could have bundled the path p: one of
+// members or a file under reportsDir.
+func isMember(p string) bool {
+	for _, m := range members {
+		if p == m {
+			return true
+		}
+	}
+	return strings.HasPrefix(p, reportsDir+"/")
+}
+
+// checkParents rejects p when one of its parent directories under dir
+// exists as a symlink, which would redirect the write outside dir.
+func checkParents(dir, p string) error {
+	elems := strings.Split(path.Dir(p), "/")
+	for i := range elems {
+		parent := strings.Join(elems[:i+1], "/")
+		info, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(parent)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s: parent directory %s is a symlink", ErrInvalid, p, parent)
+		}
+	}
+	return nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func compressor(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case Zstd:
+		// One encoder goroutine keeps the output independent of the CPU count.
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	case Gzip:
+		return gzip.NewWriterLevel(w, gzip.BestCompression)
+	case None:
+		return nopWriteCloser{w}, nil
+	}
+	return nil, fmt.Errorf("unknown compression %q", c)
+}
+
+func decompressor(r io.Reader, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case Zstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	case Gzip:
+		return gzip.NewReader(r)
+	case None:
+		return io.NopCloser(r), nil
+	}
+	return nil, fmt.Errorf("unknown compression %q", c)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package bundle
+
+// Feature: CLI_COMMAND_PACK
+// Spec: spec/cli/pack.md
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/builder"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func writeRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		".cortex/data/index.json":         `{"files":[]}`,
+		".cortex/data/cache.json":         `{"stale":true}`,
+		".cortex/meta.json":               `{"schema_version":"1.0"}`,
+		".cortex/reports/gov/drift.json":  `{"ok":true}`,
+		".cortex/reports/run/latest.json": `{"ok":false}`,
+		"spec/features.yaml":              "features: []\n",
+		"main.go":                         "package main\n",
+	}
+	for p, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return root
+}
+
+func TestMembers(t *testing.T) {
+	paths, err := Members(writeRepo(t))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		".cortex/data/index.json",
+		".cortex/meta.json",
+		".cortex/reports/gov/drift.json",
+		".cortex/reports/run/latest.json",
+		"spec/features.yaml",
+	}, paths)
+
+	paths, err = Members(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
+func TestCompressionFor(t *testing.T) {
+	for name, want := range map[string]Compression{
+		"b.tar.zst": Zstd, "b.tzst": Zstd, "b.tar.gz": Gzip, "b.tgz": Gzip, "b.tar": None,
+	} {
+		c, err := CompressionFor(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, c, name)
+	}
+	_, err := CompressionFor("b.zip")
+	assert.Error(t, err)
+}
+
+func TestPack_Deterministic(t *testing.T) {
+	root := writeRepo(t)
+	paths, err := Members(root)
+	require.NoError(t, err)
+
+	for _, c := range []Compression{Zstd, Gzip, None} {
+		var a, b bytes.Buffer
+		ma, err := Pack(&a, root, paths, c)
+		require.NoError(t, err)
+		// Timestamps are not recorded.
+		require.NoError(t, os.Chtimes(filepath.Join(root, "spec/features.yaml"), time.Unix(1e9, 0), time.Unix(1e9, 0)))
+		mb, err := Pack(&b, root, paths, c)
+		require.NoError(t, err)
+		assert.Equal(t, a.Bytes(), b.Bytes(), c)
+
+		da, err := ma.Digest()
+		require.NoError(t, err)
+		db, err := mb.Digest()
+		require.NoError(t, err)
+		assert.Equal(t, da, db)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	root := writeRepo(t)
+	paths, err := Members(root)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	packed, err := Pack(&buf, root, paths, Zstd)
+	require.NoError(t, err)
+
+	m, contents, err := Read(bytes.NewReader(buf.Bytes()), Zstd)
+	require.NoError(t, err)
+	assert.Equal(t, packed, m)
+
+	dest := t.TempDir()
+	require.NoError(t, Unpack(dest, m, contents, false))
+	for _, p := range paths {
+		want, err := os.ReadFile(filepath.Join(root, p))
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(dest, p))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, p)
+	}
+	// Unpacking the same bundle again is not a conflict.
+	require.NoError(t, Unpack(dest, m, contents, false))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "spec/features.yaml"), []byte("edited\n"), 0o600))
+	err = Unpack(dest, m, contents, false)
+	require.ErrorIs(t, err, ErrConflict)
+	assert.Contains(t, err.Error(), "spec/features.yaml")
+	require.NoError(t, Unpack(dest, m, contents, true))
+	got, err := os.ReadFile(filepath.Join(dest, "spec/features.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "features: []\n", string(got))
+}
+
+// TestRoundTrip_ContextVerifies checks that an unpacked context carries its
+// provenance and passes the checks of `cortex context verify`: a rebuild
+// from the same sources reproduces every attested output, and the unpacked
+// outputs match their attestation.
+func TestRoundTrip_ContextVerifies(t *testing.T) {
+	// Checkouts share the directory name, which is the project name.
+	checkout := func() string {
+		root := filepath.Join(t.TempDir(), "repo")
+		require.NoError(t, os.Mkdir(root, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "A.txt"), []byte("content A"), 0o600))
+		return root
+	}
+	index := &xray.Index{SchemaVersion: "1.0.0", Files: []xray.FileNode{{Path: "A.txt", Hash: "sha256:aaa", Size: 9}}}
+
+	root := checkout()
+	_, err := builder.BuildContextWithOptions(root, index, builder.Options{})
+	require.NoError(t, err)
+	outputs, err := builder.DigestOutputs(root, builder.Outputs)
+	require.NoError(t, err)
+	configDigest, err := builder.ConfigDigest(&config.Config{})
+	require.NoError(t, err)
+	require.NoError(t, builder.WriteProvenance(root, &builder.Provenance{
+		CortexVersion: "1.2.3", Engine: xray.EngineGo, ConfigDigest: configDigest, Outputs: outputs,
+	}))
+
+	paths, err := Members(root)
+	require.NoError(t, err)
+	assert.Contains(t, paths, builder.ProvenanceFile)
+	var buf bytes.Buffer
+	_, err = Pack(&buf, root, paths, Zstd)
+	require.NoError(t, err)
+	m, contents, err := Read(bytes.NewReader(buf.Bytes()), Zstd)
+	require.NoError(t, err)
+
+	dest := checkout()
+	require.NoError(t, Unpack(dest, m, contents, false))
+
+	prov, err := builder.ReadProvenance(dest)
+	require.NoError(t, err)
+	tmp := t.TempDir()
+	_, err = builder.BuildContextWithOptions(dest, index, builder.Options{Dir: filepath.Join(tmp, ".cortex")})
+	require.NoError(t, err)
+	rebuilt, err := builder.DigestOutputs(tmp, builder.Outputs)
+	require.NoError(t, err)
+	assert.Empty(t, prov.Mismatches(rebuilt), "the rebuild differs")
+	unpacked, err := builder.DigestOutputs(dest, builder.Outputs)
+	require.NoError(t, err)
+	assert.Empty(t, prov.Mismatches(unpacked), "modified since the build")
+}
+
+// rawBundle writes an uncompressed bundle of the given entries as is.
+func rawBundle(t *testing.T, entries ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		require.NoError(t, writeEntry(tw, e[0], []byte(e[1])))
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestRead_RejectsTampering(t *testing.T) {
+	const a = ".cortex/reports/a.txt"
+	manifest := func(path, content string) [2]string {
+		return [2]string{ManifestName, `{"schema_version":"1.0","files":[{"path":"` + path +
+			`","size":` + strconv.Itoa(len(content)) + `,"sha256":"` + digest([]byte(content)) + `"}]}`}
+	}
+	tests := map[string][]byte{
+		"valid":              rawBundle(t, manifest(a, "hi"), [2]string{a, "hi"}),
+		"manifest not first": rawBundle(t, [2]string{a, "hi"}, manifest(a, "hi")),
+		"content changed":    rawBundle(t, manifest(a, "hi"), [2]string{a, "ho"}),
+		"file missing":       rawBundle(t, manifest(a, "hi")),
+		"extra file":         rawBundle(t, manifest(a, "hi"), [2]string{a, "hi"}, [2]string{"b.txt", ""}),
+		"wrong name":         rawBundle(t, manifest(a, "hi"), [2]string{".cortex/reports/b.txt", "hi"}),
+		"path escapes":       rawBundle(t, manifest("../a.txt", "hi"), [2]string{"../a.txt", "hi"}),
+		"git hook":           rawBundle(t, manifest(".git/hooks/pre-commit", "hi"), [2]string{".git/hooks/pre-commit", "hi"}),
+		"source file":        rawBundle(t, manifest("cmd/evil/main.go", "hi"), [2]string{"cmd/evil/main.go", "hi"}),
+		"bad manifest":       rawBundle(t, [2]string{ManifestName, `{"files":[]}`}),
+	}
+	for name, data := range tests {
+		_, _, err := Read(bytes.NewReader(data), None)
+		if name == "valid" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrInvalid, name)
+	}
+}
+
+func TestUnpack_RejectsForeignPaths(t *testing.T) {
+	content := []byte("#!/bin/sh\n")
+	m := &Manifest{SchemaVersion: SchemaVersion, Files: []File{{Path: ".git/hooks/pre-commit", Size: int64(len(content)), SHA256: digest(content)}}}
+	dest := t.TempDir()
+	require.ErrorIs(t, Unpack(dest, m, [][]byte{content}, true), ErrInvalid)
+	_, err := os.Stat(filepath.Join(dest, ".git"))
+	assert.True(t, os.IsNotExist(err), "nothing is written")
+}
+
+func TestUnpack_RejectsSymlinkedParent(t *testing.T) {
+	outside := t.TempDir()
+	dest := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(dest, ".cortex")))
+
+	content := []byte(`{"ok":true}`)
+	m := &Manifest{SchemaVersion: SchemaVersion, Files: []File{{Path: ".cortex/reports/gov/drift.json", Size: int64(len(content)), SHA256: digest(content)}}}
+	err := Unpack(dest, m, [][]byte{content}, true)
+	require.ErrorIs(t, err, ErrInvalid)
+	assert.Contains(t, err.Error(), "parent directory .cortex is a symlink")
+	entries, err := os.ReadDir(outside)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is written through the symlink")
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package canonjson implements the Canonical JSON Algorithm used for every
+// Cortex digest and content address:
+//
+//   - UTF-8 encoding.
+//   - Object keys sorted lexicographically (byte order), recursively.
+//   - No insignificant whitespace (compact).
+//   - Standard JSON string escapes only; no \u escapes for printable characters
+//     (unlike encoding/json, which escapes <, >, & and U+2028/U+2029).
+//   - Arrays preserved in the given order.
+//   - No trailing newline.
+//
+// Spec: spec/mcp/snapshot-workspace-v1.md (§1.1), spec/xray/index-format.md
+package canonjson
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Marshal returns the canonical JSON encoding of v.
+// v is first encoded with encoding/json, so struct tags and custom
+// marshalers are honoured; the result is then re-encoded canonically.
+func Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("canonjson: marshal: %w", err)
+	}
+	return Canonicalize(data)
+}
+
+// Canonicalize re-encodes an existing JSON document canonically.
+// Numbers are preserved verbatim.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("canonjson: decode: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("canonjson: trailing data after JSON value")
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Sum256 returns the lowercase hex SHA-256 of the canonical encoding of v.
+func Sum256(v any) (string, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SnapshotID derives a content-addressed snapshot ID:
+//
+//	sha256:<hex(sha256(canonical(fingerprint) + "\n" + canonical(manifest)))>
+//
+// Spec: spec/mcp/snapshot-workspace-v1.md (§2.4)
+func SnapshotID(fingerprint, manifest any) (string, error) {
+	fp, err := Marshal(fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("canonjson: fingerprint: %w", err)
+	}
+	mf, err := Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("canonjson: manifest: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write(fp)
+	h.Write([]byte("\n"))
+	h.Write(mf)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func encode(buf *bytes.Buffer, v any) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if val {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		buf.WriteString(val.String())
+	case string:
+		encodeString(buf, val)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodeString(buf, k)
+			buf.WriteByte(':')
+			if err := encode(buf, val[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("canonjson: unsupported value of type %T", v)
+	}
+	return nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf.WriteString(string(utf8.RuneError))
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\b':
+			buf.WriteString(`\b`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hexDigits[r>>4])
+			buf.WriteByte(hexDigits[r&0xF])
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
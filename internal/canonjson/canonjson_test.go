@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package canonjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	Path string `json:"path"`
+	Blob string `json:"blob"`
+}
+
+type manifest struct {
+	Entries []entry `json:"entries"`
+}
+
+func TestMarshal_SortsKeysRecursively(t *testing.T) {
+	out, err := Marshal(manifest{Entries: []entry{{Path: "src/a.ts", Blob: "sha256:aa"}}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"entries":[{"blob":"sha256:aa","path":"src/a.ts"}]}`, string(out))
+}
+
+func TestMarshal_NoHTMLOrUnicodeEscapes(t *testing.T) {
+	out, err := Marshal(map[string]any{"s": "a<b>&c é\n\x01\"\\"})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"s\":\"a<b>&c é\\n\\u0001\\\"\\\\\"}", string(out))
+}
+
+func TestCanonicalize_PreservesNumbersAndArrays(t *testing.T) {
+	out, err := Canonicalize([]byte(`{ "z": [3, 1, 2], "a": 12345678901234567890, "m": 1.50 }`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":12345678901234567890,"m":1.50,"z":[3,1,2]}`, string(out))
+
+	_, err = Canonicalize([]byte(`{} {}`))
+	assert.Error(t, err)
+}
+
+// Vectors below were produced independently with Python's
+// json.dumps(sort_keys=True, separators=(",", ":"), ensure_ascii=False).
+func TestSum256_Vector(t *testing.T) {
+	sum, err := Sum256(map[string]any{"b": []any{1, 2.5, "x&y"}, "a": nil})
+	require.NoError(t, err)
+	assert.Equal(t, "42e716676a59ffa1bab4d56fc46e7b750db4953f6aee48fe16b004df3a3b1479", sum)
+}
+
+func TestSnapshotID_Vector(t *testing.T) {
+	fp := map[string]string{"status_hash": "s", "head_oid": "h", "index_oid": "i"}
+	mf := manifest{Entries: []entry{
+		{Path: "src/a.ts", Blob: "sha256:aa"},
+		{Path: "src/b<c>.ts", Blob: "sha256:bb"},
+	}}
+
+	id, err := SnapshotID(fp, mf)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:9b0606be7b8d88c0c6642b3de1901657b25af63fc6740246da46d1d3fae7a5be", id)
+}
+
+// vectors are shared with the Rust encoder of cortex-mcp
+// (rust/mcp/src/util/canonical_json.rs), so both derive the same digests
+// and snapshot IDs.
+type vectors struct {
+	Encodings []struct {
+		Name      string `json:"name"`
+		Input     string `json:"input"`
+		Canonical string `json:"canonical"`
+	} `json:"encodings"`
+	SnapshotIDs []struct {
+		Name        string          `json:"name"`
+		Fingerprint json.RawMessage `json:"fingerprint"`
+		Manifest    json.RawMessage `json:"manifest"`
+		SnapshotID  string          `json:"snapshot_id"`
+	} `json:"snapshot_ids"`
+}
+
+func TestSharedVectors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "spec", "fixtures", "canonjson", "vectors.json"))
+	require.NoError(t, err)
+	var v vectors
+	require.NoError(t, json.Unmarshal(data, &v))
+	require.NotEmpty(t, v.Encodings)
+	require.NotEmpty(t, v.SnapshotIDs)
+
+	for _, e := range v.Encodings {
+		out, err := Canonicalize([]byte(e.Input))
+		require.NoError(t, err, e.Name)
+		assert.Equal(t, e.Canonical, string(out), e.Name)
+	}
+	for _, s := range v.SnapshotIDs {
+		id, err := SnapshotID(s.Fingerprint, s.Manifest)
+		require.NoError(t, err, s.Name)
+		assert.Equal(t, s.SnapshotID, id, s.Name)
+	}
+}
@@ -0,0 +1,363 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package chunker splits repository files into AI-consumable chunks.
+//
+// Go sources are split per top-level declaration, Markdown per heading and
+// everything else into fixed line windows. Every chunk carries a content hash
+// and a stable ID so downstream consumers (e.g. embedding caches) can reuse
+// work across runs.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package chunker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// Version identifies the chunking rules. Bump it whenever Split output
+// changes so incremental builds discard chunks made by older rules.
+const Version = "1"
+
+// MaxLines is the maximum number of lines in a single chunk.
+// Larger declarations or sections are split into consecutive windows.
+const MaxLines = 200
+
+// Chunk kinds.
+const (
+	KindPreamble = "preamble" // package clause and imports, or text before the first heading
+	KindFunc     = "func"
+	KindMethod   = "method"
+	KindType     = "type"
+	KindVar      = "var"
+	KindConst    = "const"
+	KindSection  = "section" // Markdown heading section
+	KindLines    = "lines"   // fixed line window
+)
+
+// Reasons a file has no chunks, as classified by scanner.Policy.
+const (
+	SkippedBinary   = string(scanner.ClassBinary)
+	SkippedTooLarge = string(scanner.ClassLarge)
+)
+
+// Chunk is one line of chunks.ndjson.
+type Chunk struct {
+	// ID is stable across runs and line shifts: it depends only on the file
+	// path, the chunk content and the occurrence of that content in the file.
+	ID          string `json:"id"`
+	FilePath    string `json:"file_path"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	Kind        string `json:"kind"`
+	Symbol      string `json:"symbol,omitempty"`
+	ContentHash string `json:"content_hash"`
+	Content     string `json:"content"`
+}
+
+// ManifestEntry is one item of manifest.json.
+type ManifestEntry struct {
+	Path    string   `json:"path"`
+	Hash    string   `json:"hash"`
+	Chunks  []string `json:"chunks"`
+	Skipped string   `json:"skipped,omitempty"`
+	// Tokens is the token count of the chunks of the file, filled in by the
+	// context builder.
+	Tokens int `json:"tokens"`
+}
+
+// Result is the full chunking output for a repository.
+type Result struct {
+	Manifest []ManifestEntry
+	Chunks   []Chunk
+}
+
+// span is a 1-based inclusive line range with its classification.
+type span struct {
+	start, end int
+	kind       string
+	symbol     string
+}
+
+// Build chunks every file in files (paths relative to repoRoot) under the
+// default scanner.Policy. Output is ordered by path, then start line.
+func Build(repoRoot string, files []xray.FileNode) (*Result, error) {
+	res, _, err := BuildIncremental(repoRoot, files, nil, scanner.Policy{})
+	return res, err
+}
+
+// BuildIncremental is Build reusing previous chunks for files whose content
+// hash is unchanged. The result is identical to Build's as long as previous
+// was produced by the same Version. It returns the number of reused files.
+// Files over the size limit of policy are skipped without being read.
+func BuildIncremental(repoRoot string, files []xray.FileNode, previous *Result, policy scanner.Policy) (*Result, int, error) {
+	prevEntries, prevChunks := previous.byPath()
+
+	sorted := make([]xray.FileNode, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	res := &Result{
+		Manifest: make([]ManifestEntry, 0, len(sorted)),
+		Chunks:   []Chunk{},
+	}
+	reused := 0
+	for _, f := range sorted {
+		path := filepath.Join(repoRoot, filepath.FromSlash(f.Path))
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading source file %s: %w", f.Path, err)
+		}
+		large := policy.TooLarge(info.Size())
+
+		// A changed size limit invalidates the previous classification.
+		if prev, ok := prevEntries[f.Path]; ok && f.Hash != "" && prev.Hash == f.Hash && len(prevChunks[f.Path]) == len(prev.Chunks) &&
+			(prev.Skipped == SkippedTooLarge) == large {
+			res.Manifest = append(res.Manifest, prev)
+			res.Chunks = append(res.Chunks, prevChunks[f.Path]...)
+			reused++
+			continue
+		}
+
+		entry := ManifestEntry{Path: f.Path, Hash: f.Hash, Chunks: []string{}}
+		if large {
+			entry.Skipped = SkippedTooLarge
+			res.Manifest = append(res.Manifest, entry)
+			continue
+		}
+
+		content, err := os.ReadFile(path) //nolint:gosec // paths come from the XRAY index
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading source file %s: %w", f.Path, err)
+		}
+
+		switch {
+		case !isText(content):
+			entry.Skipped = SkippedBinary
+		default:
+			for _, c := range Split(f.Path, content) {
+				entry.Chunks = append(entry.Chunks, c.ID)
+				res.Chunks = append(res.Chunks, c)
+			}
+		}
+		res.Manifest = append(res.Manifest, entry)
+	}
+	return res, reused, nil
+}
+
+// SplitFile chunks content, the file at path, the way BuildIncremental
+// does: a file over the size limit of policy or that is not text has no
+// chunks and its manifest entry says why.
+func SplitFile(path string, content []byte, policy scanner.Policy) (ManifestEntry, []Chunk) {
+	entry := ManifestEntry{Path: path, Hash: "sha256:" + hashHex(string(content)), Chunks: []string{}}
+	switch {
+	case policy.TooLarge(int64(len(content))):
+		entry.Skipped = SkippedTooLarge
+		return entry, nil
+	case !isText(content):
+		entry.Skipped = SkippedBinary
+		return entry, nil
+	}
+	chunks := Split(path, content)
+	for _, c := range chunks {
+		entry.Chunks = append(entry.Chunks, c.ID)
+	}
+	return entry, chunks
+}
+
+// Read loads a previous result from manifest.json and chunks.ndjson in dir.
+func Read(dir string) (*Result, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json")) //nolint:gosec // dir is the context data directory
+	if err != nil {
+		return nil, err
+	}
+	var manifest []ManifestEntry
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest.json: %w", err)
+	}
+	chunks, err := ReadChunks(filepath.Join(dir, "chunks.ndjson"))
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Manifest: manifest, Chunks: chunks}, nil
+}
+
+func (r *Result) byPath() (map[string]ManifestEntry, map[string][]Chunk) {
+	entries := make(map[string]ManifestEntry)
+	chunks := make(map[string][]Chunk)
+	if r == nil {
+		return entries, chunks
+	}
+	for _, e := range r.Manifest {
+		entries[e.Path] = e
+	}
+	for _, c := range r.Chunks {
+		chunks[c.FilePath] = append(chunks[c.FilePath], c)
+	}
+	return entries, chunks
+}
+
+// Split chunks a single text file, choosing the strategy from its extension.
+func Split(path string, content []byte) []Chunk {
+	lines := splitLines(string(content))
+	if len(lines) == 0 {
+		return nil
+	}
+
+	var spans []span
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		spans = goSpans(content, lines)
+	case ".md", ".markdown":
+		spans = markdownSpans(lines)
+	}
+	if spans == nil {
+		spans = []span{{start: 1, end: len(lines), kind: KindLines}}
+	}
+
+	seen := make(map[string]int)
+	var chunks []Chunk
+	for _, s := range spans {
+		for _, w := range window(s) {
+			text := strings.Join(lines[w.start-1:w.end], "\n")
+			contentHash := hashHex(text)
+			occurrence := seen[contentHash]
+			seen[contentHash]++
+
+			chunks = append(chunks, Chunk{
+				ID:          chunkID(path, contentHash, occurrence),
+				FilePath:    path,
+				StartLine:   w.start,
+				EndLine:     w.end,
+				Kind:        w.kind,
+				Symbol:      w.symbol,
+				ContentHash: "sha256:" + contentHash,
+				Content:     text,
+			})
+		}
+	}
+	return chunks
+}
+
+// Write writes manifest.json and chunks.ndjson into dir and returns the exact
+// bytes written for each.
+func Write(dir string, res *Result) (manifest, chunks []byte, err error) {
+	manifest, err = json.MarshalIndent(res.Manifest, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling manifest: %w", err)
+	}
+	manifest = append(manifest, '\n')
+
+	var buf bytes.Buffer
+	for _, c := range res.Chunks {
+		line, err := json.Marshal(c)
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshaling chunk: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	chunks = buf.Bytes()
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if err := projection.AtomicWrite(filepath.Join(dir, "manifest.json"), manifest); err != nil {
+		return nil, nil, fmt.Errorf("writing manifest.json: %w", err)
+	}
+	if err := projection.AtomicWrite(filepath.Join(dir, "chunks.ndjson"), chunks); err != nil {
+		return nil, nil, fmt.Errorf("writing chunks.ndjson: %w", err)
+	}
+	return manifest, chunks, nil
+}
+
+// window splits spans longer than MaxLines into consecutive windows.
+func window(s span) []span {
+	if s.end-s.start+1 <= MaxLines {
+		return []span{s}
+	}
+	var out []span
+	for start := s.start; start <= s.end; start += MaxLines {
+		end := start + MaxLines - 1
+		if end > s.end {
+			end = s.end
+		}
+		out = append(out, span{start: start, end: end, kind: s.kind, symbol: s.symbol})
+	}
+	return out
+}
+
+// splitLines normalizes CRLF and splits into lines; a trailing newline does
+// not produce an extra empty line.
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// trimTrailingBlank moves end back over blank lines, never before start.
+func trimTrailingBlank(lines []string, start, end int) int {
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+func isBlank(lines []string, start, end int) bool {
+	for i := start; i <= end; i++ {
+		if strings.TrimSpace(lines[i-1]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func chunkID(path, contentHash string, occurrence int) string {
+	return hashHex(fmt.Sprintf("%s\n%s\n%d", path, contentHash, occurrence))
+}
+
+func hashHex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func isText(b []byte) bool {
+	return bytes.IndexByte(b, 0) < 0 && utf8.Valid(b)
+}
+
+// ReadChunks parses a chunks.ndjson file.
+func ReadChunks(path string) ([]Chunk, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is the standard chunks location
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []Chunk
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var c Chunk
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, fmt.Errorf("parsing %s line %d: %w", filepath.Base(path), i+1, err)
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
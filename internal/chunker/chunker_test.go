@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package chunker
+
+// We are testing internal functions, so we use package chunker instead of chunker_test
+// This allows access to the isText helper.
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func TestChunker_Golden(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []Chunk
+	}{
+		{
+			name:    "Small file (1 chunk)",
+			content: "line1\nline2",
+			expected: []Chunk{
+				{FilePath: "test.txt", StartLine: 1, EndLine: 2, Content: "line1\nline2"},
+			},
+		},
+		{
+			name:    "Exact 200 lines",
+			content: strings.Repeat("line\n", 199) + "line", // 200 lines
+			expected: []Chunk{
+				{FilePath: "test.txt", StartLine: 1, EndLine: 200, Content: strings.Repeat("line\n", 199) + "line"},
+			},
+		},
+		{
+			name:    "201 lines (2 chunks)",
+			content: strings.Repeat("line\n", 200) + "line201",
+			expected: []Chunk{
+				{FilePath: "test.txt", StartLine: 1, EndLine: 200, Content: strings.Repeat("line\n", 199) + "line"},
+				{FilePath: "test.txt", StartLine: 201, EndLine: 201, Content: "line201"},
+			},
+		},
+		{
+			name:    "CRLFs normalized",
+			content: "line1\r\nline2",
+			expected: []Chunk{
+				{FilePath: "test.txt", StartLine: 1, EndLine: 2, Content: "line1\nline2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split("test.txt", []byte(tt.content))
+			if len(got) != len(tt.expected) {
+				t.Errorf("got %d chunks, want %d", len(got), len(tt.expected))
+				return
+			}
+			for i, c := range got {
+				if c.StartLine != tt.expected[i].StartLine {
+					t.Errorf("chunk[%d] StartLine=%d, want %d", i, c.StartLine, tt.expected[i].StartLine)
+				}
+				if c.EndLine != tt.expected[i].EndLine {
+					t.Errorf("chunk[%d] EndLine=%d, want %d", i, c.EndLine, tt.expected[i].EndLine)
+				}
+				if c.Content != tt.expected[i].Content {
+					// Truncate long content for logging
+					gotC := c.Content
+					if len(gotC) > 20 {
+						gotC = gotC[:20] + "..."
+					}
+					wantC := tt.expected[i].Content
+					if len(wantC) > 20 {
+						wantC = wantC[:20] + "..."
+					}
+					t.Errorf("chunk[%d] Content mismatch. Got %q, want %q", i, gotC, wantC)
+				}
+				// Check JSON marshalling stability?
+				_, err := json.Marshal(c)
+				if err != nil {
+					t.Errorf("chunk[%d] failed to marshal: %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsText(t *testing.T) {
+	if !isText([]byte("hello world")) {
+		t.Error("expected text to be text")
+	}
+	if isText([]byte("hello\x00world")) {
+		t.Error("expected null byte to be non-text")
+	}
+	// Invalid UTF8
+	if isText([]byte{0xff, 0xfe, 0xfd}) {
+		t.Error("expected invalid utf8 to be non-text")
+	}
+}
+
+func TestSplit_Go(t *testing.T) {
+	src := `// Package demo is a demo.
+package demo
+
+import "fmt"
+
+// Greeter greets.
+type Greeter struct{}
+
+// Hello says hello.
+func (g *Greeter) Hello() { fmt.Println("hi") }
+
+const (
+	A = 1
+	B = 2
+)
+
+func main() {}
+`
+	got := Split("demo.go", []byte(src))
+
+	want := []struct {
+		start, end   int
+		kind, symbol string
+	}{
+		{1, 4, KindPreamble, ""},
+		{6, 7, KindType, "Greeter"},
+		{9, 10, KindMethod, "Greeter.Hello"},
+		{12, 15, KindConst, "A, B"},
+		{17, 17, KindFunc, "main"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.StartLine != w.start || c.EndLine != w.end || c.Kind != w.kind || c.Symbol != w.symbol {
+			t.Errorf("chunk[%d] = %d-%d %s %q, want %d-%d %s %q", i, c.StartLine, c.EndLine, c.Kind, c.Symbol, w.start, w.end, w.kind, w.symbol)
+		}
+	}
+}
+
+func TestSplit_GoParseErrorFallsBackToLines(t *testing.T) {
+	got := Split("broken.go", []byte("package x\nfunc {"))
+	if len(got) != 1 || got[0].Kind != KindLines {
+		t.Fatalf("expected a single lines chunk, got %+v", got)
+	}
+}
+
+func TestSplit_Markdown(t *testing.T) {
+	src := "Intro text\n\n# Title\n\nBody\n\n```sh\n# not a heading\n```\n\n## Sub ##\nMore\n"
+	got := Split("README.md", []byte(src))
+
+	want := []struct {
+		start, end   int
+		kind, symbol string
+	}{
+		{1, 1, KindPreamble, ""},
+		{3, 9, KindSection, "Title"},
+		{11, 12, KindSection, "Sub"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d chunks, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.StartLine != w.start || c.EndLine != w.end || c.Kind != w.kind || c.Symbol != w.symbol {
+			t.Errorf("chunk[%d] = %d-%d %s %q, want %d-%d %s %q", i, c.StartLine, c.EndLine, c.Kind, c.Symbol, w.start, w.end, w.kind, w.symbol)
+		}
+	}
+}
+
+func TestSplit_StableIDs(t *testing.T) {
+	before := Split("doc.md", []byte("# A\nsame\n# B\nbody\n"))
+	after := Split("doc.md", []byte("new line\n\n# A\nsame\n# B\nbody\n"))
+
+	// Shifting content down keeps IDs of unchanged sections.
+	if before[0].ID != after[1].ID || before[1].ID != after[2].ID {
+		t.Errorf("IDs changed after line shift: %v vs %v", before, after)
+	}
+	if before[0].ContentHash != "sha256:"+hashHex(before[0].Content) {
+		t.Errorf("unexpected content hash %s", before[0].ContentHash)
+	}
+
+	// Identical content in the same file still gets distinct IDs.
+	dup := Split("dup.md", []byte("# X\n# X\n"))
+	if dup[0].ID == dup[1].ID {
+		t.Error("duplicate sections must not share an ID")
+	}
+
+	// The same content in another file gets another ID but the same content hash.
+	other := Split("other.md", []byte("# A\nsame\n"))
+	if other[0].ID == before[0].ID || other[0].ContentHash != before[0].ContentHash {
+		t.Error("expected same content hash and different ID across files")
+	}
+}
+
+func TestBuildAndWrite(t *testing.T) {
+	root := t.TempDir()
+	files := map[string][]byte{
+		"b.txt":   []byte("b\n"),
+		"a.md":    []byte("# A\n"),
+		"bin.dat": {0, 1, 2},
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := Build(root, []xray.FileNode{{Path: "b.txt", Hash: "sha256:b"}, {Path: "bin.dat"}, {Path: "a.md"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Manifest) != 3 || res.Manifest[0].Path != "a.md" || res.Manifest[2].Skipped != SkippedBinary {
+		t.Fatalf("unexpected manifest: %+v", res.Manifest)
+	}
+	if len(res.Chunks) != 2 || res.Chunks[0].FilePath != "a.md" || res.Manifest[1].Chunks[0] != res.Chunks[1].ID {
+		t.Fatalf("unexpected chunks: %+v", res.Chunks)
+	}
+
+	out := filepath.Join(root, "out")
+	manifest1, chunks1, err := Write(out, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest2, chunks2, err := Write(out, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(manifest1) != string(manifest2) || string(chunks1) != string(chunks2) {
+		t.Error("Write is not deterministic")
+	}
+	written, err := os.ReadFile(filepath.Join(out, "chunks.ndjson"))
+	if err != nil || string(written) != string(chunks1) {
+		t.Errorf("chunks.ndjson does not match returned bytes: %v", err)
+	}
+}
+
+func TestBuildIncremental_SizeLimit(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "big.txt"), []byte("0123456789\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := []xray.FileNode{{Path: "big.txt", Hash: "sha256:big"}}
+
+	small := scanner.Policy{MaxFileBytes: 4}
+	res, _, err := BuildIncremental(root, files, nil, small)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Manifest[0].Skipped != SkippedTooLarge || len(res.Chunks) != 0 {
+		t.Fatalf("expected big.txt to be skipped: %+v", res.Manifest)
+	}
+
+	// Raising the limit rechunks the file even though its hash is unchanged.
+	res, reused, err := BuildIncremental(root, files, res, scanner.Policy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reused != 0 || res.Manifest[0].Skipped != "" || len(res.Chunks) != 1 {
+		t.Fatalf("expected big.txt to be chunked, reused %d: %+v", reused, res.Manifest)
+	}
+}
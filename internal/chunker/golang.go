@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package chunker
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// goSpans splits a Go file at top-level declarations. Doc comments belong to
+// the declaration they document; package clause and imports form the
+// preamble. Returns nil if the file does not parse.
+func goSpans(content []byte, lines []string) []span {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+
+	type decl struct {
+		start  int
+		kind   string
+		symbol string
+	}
+	var decls []decl
+	for _, d := range file.Decls {
+		var doc *ast.CommentGroup
+		var kind, symbol string
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			doc = d.Doc
+			kind, symbol = KindFunc, d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				kind = KindMethod
+				if recv := receiverName(d.Recv.List[0].Type); recv != "" {
+					symbol = recv + "." + symbol
+				}
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			doc = d.Doc
+			kind, symbol = genDeclKind(d)
+		default:
+			continue
+		}
+
+		start := fset.Position(d.Pos()).Line
+		if doc != nil {
+			start = fset.Position(doc.Pos()).Line
+		}
+		decls = append(decls, decl{start: start, kind: kind, symbol: symbol})
+	}
+
+	var spans []span
+	next := len(lines) + 1
+	if len(decls) > 0 {
+		next = decls[0].start
+	}
+	if next > 1 && !isBlank(lines, 1, next-1) {
+		spans = append(spans, span{start: 1, end: trimTrailingBlank(lines, 1, next-1), kind: KindPreamble})
+	}
+	for i, d := range decls {
+		end := len(lines)
+		if i+1 < len(decls) {
+			end = decls[i+1].start - 1
+		}
+		spans = append(spans, span{start: d.start, end: trimTrailingBlank(lines, d.start, end), kind: d.kind, symbol: d.symbol})
+	}
+	return spans
+}
+
+func genDeclKind(d *ast.GenDecl) (kind, symbol string) {
+	switch d.Tok {
+	case token.TYPE:
+		kind = KindType
+	case token.CONST:
+		kind = KindConst
+	default:
+		kind = KindVar
+	}
+
+	var names []string
+	for _, spec := range d.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			names = append(names, s.Name.Name)
+		case *ast.ValueSpec:
+			for _, n := range s.Names {
+				names = append(names, n.Name)
+			}
+		}
+	}
+	return kind, strings.Join(names, ", ")
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
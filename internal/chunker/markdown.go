@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package chunker
+
+import "strings"
+
+// markdownSpans splits a Markdown file at ATX headings outside fenced code
+// blocks. Text before the first heading forms the preamble.
+func markdownSpans(lines []string) []span {
+	type heading struct {
+		line  int
+		title string
+	}
+	var headings []heading
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if title, ok := headingTitle(line); ok {
+			headings = append(headings, heading{line: i + 1, title: title})
+		}
+	}
+
+	var spans []span
+	next := len(lines) + 1
+	if len(headings) > 0 {
+		next = headings[0].line
+	}
+	if next > 1 && !isBlank(lines, 1, next-1) {
+		spans = append(spans, span{start: 1, end: trimTrailingBlank(lines, 1, next-1), kind: KindPreamble})
+	}
+	for i, h := range headings {
+		end := len(lines)
+		if i+1 < len(headings) {
+			end = headings[i+1].line - 1
+		}
+		spans = append(spans, span{start: h.line, end: trimTrailingBlank(lines, h.line, end), kind: KindSection, symbol: h.title})
+	}
+	return spans
+}
+
+// headingTitle reports whether line is an ATX heading (# to ######) and
+// returns its text.
+func headingTitle(line string) (string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return "", false
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#")), true
+}
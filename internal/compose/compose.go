@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package compose assembles a prompt bundle for a task: the governance
+// rules, the specs of the selected features and the symbols and chunks of
+// the selected files, admitted in a fixed order until a token budget is
+// spent.
+//
+// Files are selected from the built context (.cortex/data) by path glob or
+// by their `// Feature:` header; the features of the selected files bring
+// their specs along.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/agents"
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/mapping"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/symbols"
+	"github.com/bartekus/cortex/internal/tokens"
+)
+
+// SchemaVersion is the schema_version of a Bundle.
+const SchemaVersion = "1.0"
+
+// Kinds of bundle items.
+const (
+	KindRule   = "rule"
+	KindSpec   = "spec"
+	KindSymbol = "symbol"
+	KindChunk  = "chunk"
+)
+
+// Options selects what goes into a bundle.
+type Options struct {
+	// Task describes the work; it heads the bundle and is not counted
+	// against the budget.
+	Task string
+	// Features are feature IDs of spec/features.yaml.
+	Features []string
+	// Paths are globs as in scanner.MatchGlob.
+	Paths []string
+	// Budget is the token budget; 0 means no limit.
+	Budget int
+}
+
+// Bundle is a composed prompt bundle.
+type Bundle struct {
+	SchemaVersion string `json:"schema_version"`
+	Task          string `json:"task,omitempty"`
+	// Features are the requested features and those of the selected files,
+	// sorted.
+	Features []string `json:"features"`
+	// Files are the selected files, sorted.
+	Files     []string `json:"files"`
+	Tokenizer string   `json:"tokenizer"`
+	Budget    int      `json:"budget"`
+	// Tokens is the token count of the admitted items.
+	Tokens  int             `json:"tokens"`
+	Rules   []agents.Rule   `json:"rules"`
+	Specs   []Spec          `json:"specs"`
+	Symbols []Symbol        `json:"symbols"`
+	Chunks  []chunker.Chunk `json:"chunks"`
+	// Omitted are the items that did not fit the budget, in admission
+	// order.
+	Omitted []Omission `json:"omitted"`
+}
+
+// Spec is the spec of a feature.
+type Spec struct {
+	Feature string `json:"feature"`
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Symbol is a declaration of a selected file.
+type Symbol struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Signature is the declaration line: the signature of a function, the
+	// first line of a type, or the kind and name of a variable or constant.
+	Signature string `json:"signature"`
+}
+
+// Omission is an item left out of the bundle.
+type Omission struct {
+	Kind string `json:"kind"`
+	// Ref is the skill of a rule, the path of a spec, the ID of a symbol or
+	// the path and line range of a chunk ("a.go:3-10").
+	Ref    string `json:"ref"`
+	Tokens int    `json:"tokens"`
+}
+
+// Compose builds the bundle for opts from the context res and graph (nil
+// without Go symbols) of repoRoot, counting tokens with c. Items are
+// admitted rules first, then specs, symbols and chunks, each in bundle
+// order; an item that does not fit the remaining budget is omitted and
+// smaller items after it may still be admitted.
+func Compose(repoRoot string, res *chunker.Result, graph *symbols.Graph, c tokens.Counter, opts Options) (*Bundle, error) {
+	if len(opts.Features) == 0 && len(opts.Paths) == 0 {
+		return nil, fmt.Errorf("select a feature or a path")
+	}
+	for _, g := range opts.Paths {
+		if err := scanner.ValidateGlob(g); err != nil {
+			return nil, err
+		}
+	}
+	registry, err := features.LoadGraph(filepath.Join(repoRoot, "spec", "features.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(opts.Features))
+	for _, id := range opts.Features {
+		if _, ok := registry.Nodes[id]; !ok {
+			return nil, fmt.Errorf("unknown feature %q", id)
+		}
+		wanted[id] = true
+	}
+
+	b := &Bundle{
+		SchemaVersion: SchemaVersion,
+		Task:          opts.Task,
+		Features:      []string{},
+		Files:         []string{},
+		Tokenizer:     c.Name(),
+		Budget:        opts.Budget,
+		Rules:         []agents.Rule{},
+		Specs:         []Spec{},
+		Symbols:       []Symbol{},
+		Chunks:        []chunker.Chunk{},
+		Omitted:       []Omission{},
+	}
+	selected := make(map[string]bool)
+	featureSet := make(map[string]bool, len(wanted))
+	for id := range wanted {
+		featureSet[id] = true
+	}
+	for _, e := range res.Manifest {
+		if e.Skipped != "" {
+			continue
+		}
+		var feature string
+		if mapping.SupportsHeaders(e.Path) {
+			if feature, _, err = mapping.ParseHeaders(filepath.Join(repoRoot, filepath.FromSlash(e.Path))); err != nil {
+				return nil, err
+			}
+		}
+		if !wanted[feature] && !matchAny(opts.Paths, e.Path) {
+			continue
+		}
+		selected[e.Path] = true
+		b.Files = append(b.Files, e.Path)
+		if _, ok := registry.Nodes[feature]; ok {
+			featureSet[feature] = true
+		}
+	}
+	for id := range featureSet {
+		b.Features = append(b.Features, id)
+	}
+	sort.Strings(b.Features)
+	sort.Strings(b.Files)
+
+	admit := func(kind, ref, text string) bool {
+		n := c.Count(text)
+		if opts.Budget > 0 && b.Tokens+n > opts.Budget {
+			b.Omitted = append(b.Omitted, Omission{Kind: kind, Ref: ref, Tokens: n})
+			return false
+		}
+		b.Tokens += n
+		return true
+	}
+
+	for _, r := range agents.Rules() {
+		if admit(KindRule, r.Skill, ruleLine(r)) {
+			b.Rules = append(b.Rules, r)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range b.Features {
+		path := registry.Nodes[id].Spec
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(path))) //nolint:gosec // G304: spec path from the feature registry
+		if err != nil {
+			return nil, fmt.Errorf("reading spec of %s: %w", id, err)
+		}
+		if admit(KindSpec, path, string(data)) {
+			b.Specs = append(b.Specs, Spec{Feature: id, Path: path, Content: string(data)})
+		}
+	}
+
+	if graph != nil {
+		var syms []Symbol
+		for _, s := range graph.Symbols {
+			if selected[s.File] {
+				syms = append(syms, newSymbol(s))
+			}
+		}
+		sort.Slice(syms, func(i, j int) bool {
+			if syms[i].File != syms[j].File {
+				return syms[i].File < syms[j].File
+			}
+			if syms[i].Line != syms[j].Line {
+				return syms[i].Line < syms[j].Line
+			}
+			return syms[i].ID < syms[j].ID
+		})
+		for _, s := range syms {
+			if admit(KindSymbol, s.ID, symbolLine(s)) {
+				b.Symbols = append(b.Symbols, s)
+			}
+		}
+	}
+
+	// Chunks are ordered by path, then start line.
+	for _, ch := range res.Chunks {
+		if !selected[ch.FilePath] {
+			continue
+		}
+		if admit(KindChunk, fmt.Sprintf("%s:%d-%d", ch.FilePath, ch.StartLine, ch.EndLine), ch.Content) {
+			b.Chunks = append(b.Chunks, ch)
+		}
+	}
+	return b, nil
+}
+
+func matchAny(globs []string, p string) bool {
+	for _, g := range globs {
+		if scanner.MatchGlob(g, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func newSymbol(s symbols.Symbol) Symbol {
+	sig := s.Signature
+	if sig == "" {
+		sig = s.Kind + " " + s.Name
+	}
+	return Symbol{ID: s.ID, Kind: s.Kind, File: s.File, Line: s.StartLine, Signature: sig}
+}
+
+func ruleLine(r agents.Rule) string {
+	return fmt.Sprintf("- %s (`%s`)\n", r.Text, r.Skill)
+}
+
+func symbolLine(s Symbol) string {
+	return fmt.Sprintf("- `%s` (%s:%d)\n", strings.ReplaceAll(s.Signature, "\n", " "), s.File, s.Line)
+}
+
+// Render returns the bundle as Markdown, ready to paste into a prompt.
+func Render(b *Bundle) string {
+	var sb strings.Builder
+	sb.WriteString("# Task\n\n")
+	if b.Task != "" {
+		sb.WriteString(strings.TrimSpace(b.Task) + "\n\n")
+	}
+	fmt.Fprintf(&sb, "Features: %s\n\n", codeList(b.Features))
+
+	if len(b.Rules) > 0 {
+		sb.WriteString("## Governance Rules\n\n")
+		for _, r := range b.Rules {
+			sb.WriteString(ruleLine(r))
+		}
+		sb.WriteString("\n")
+	}
+	for _, s := range b.Specs {
+		fmt.Fprintf(&sb, "## Spec: %s (`%s`)\n\n", s.Path, s.Feature)
+		sb.WriteString(strings.TrimRight(s.Content, "\n") + "\n\n")
+	}
+	if len(b.Symbols) > 0 {
+		sb.WriteString("## Symbols\n\n")
+		for _, s := range b.Symbols {
+			sb.WriteString(symbolLine(s))
+		}
+		sb.WriteString("\n")
+	}
+	if len(b.Chunks) > 0 {
+		sb.WriteString("## Code\n\n")
+		for _, ch := range b.Chunks {
+			fmt.Fprintf(&sb, "### %s:%d-%d\n\n", ch.FilePath, ch.StartLine, ch.EndLine)
+			fence := "```"
+			for strings.Contains(ch.Content, fence) {
+				fence += "`"
+			}
+			fmt.Fprintf(&sb, "%s%s\n%s\n%s\n\n", fence, language(ch.FilePath), strings.TrimRight(ch.Content, "\n"), fence)
+		}
+	}
+	if len(b.Omitted) > 0 {
+		fmt.Fprintf(&sb, "<!-- %d items omitted to stay within %d tokens -->\n", len(b.Omitted), b.Budget)
+	}
+	return sb.String()
+}
+
+// language returns the info string of a fenced code block for path.
+func language(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return "go"
+	case ".rs":
+		return "rust"
+	case ".ts", ".tsx":
+		return "typescript"
+	case ".js", ".jsx":
+		return "javascript"
+	case ".py":
+		return "python"
+	case ".md":
+		return "markdown"
+	case ".yml", ".yaml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".sh":
+		return "sh"
+	}
+	return ""
+}
+
+func codeList(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = "`" + s + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
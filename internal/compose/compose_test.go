@@ -0,0 +1,120 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/symbols"
+	"github.com/bartekus/cortex/internal/tokens"
+)
+
+const registry = `features:
+  - id: GREET
+    title: Greeting
+    spec: spec/greet.md
+  - id: OTHER
+    title: Other
+    spec: spec/other.md
+`
+
+// setup writes a repository and chunks its files the way a build does.
+func setup(t *testing.T) (string, *chunker.Result) {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"spec/features.yaml": registry,
+		"spec/greet.md":      "# Greeting\n\nSay hello.\n",
+		"spec/other.md":      "# Other\n",
+		"greet/greet.go":     "// Feature: GREET\npackage greet\n\n// Hello greets.\nfunc Hello() string { return \"hi\" }\n",
+		"other/other.go":     "// Feature: OTHER\npackage other\n\nvar X = 1\n",
+		"README.md":          "# Readme\n",
+	}
+	res := &chunker.Result{}
+	for _, path := range []string{"README.md", "greet/greet.go", "other/other.go", "spec/features.yaml", "spec/greet.md", "spec/other.md"} {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o750))
+		require.NoError(t, os.WriteFile(full, []byte(files[path]), 0o600))
+		entry, chunks := chunker.SplitFile(path, []byte(files[path]), scanner.Policy{})
+		res.Manifest = append(res.Manifest, entry)
+		res.Chunks = append(res.Chunks, chunks...)
+	}
+	return root, res
+}
+
+func TestCompose_Feature(t *testing.T) {
+	root, res := setup(t)
+	graph := &symbols.Graph{Symbols: []symbols.Symbol{
+		{ID: "x/greet.Hello", Kind: "func", Name: "Hello", File: "greet/greet.go", StartLine: 4, Signature: "func Hello() string"},
+		{ID: "x/other.X", Kind: "var", Name: "X", File: "other/other.go", StartLine: 4},
+	}}
+
+	b, err := Compose(root, res, graph, tokens.Estimate{}, Options{Task: "Greet louder", Features: []string{"GREET"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GREET"}, b.Features)
+	assert.Equal(t, []string{"greet/greet.go"}, b.Files)
+	require.Len(t, b.Specs, 1)
+	assert.Equal(t, Spec{Feature: "GREET", Path: "spec/greet.md", Content: "# Greeting\n\nSay hello.\n"}, b.Specs[0])
+	assert.Equal(t, []Symbol{{ID: "x/greet.Hello", Kind: "func", File: "greet/greet.go", Line: 4, Signature: "func Hello() string"}}, b.Symbols)
+	require.NotEmpty(t, b.Chunks)
+	for _, ch := range b.Chunks {
+		assert.Equal(t, "greet/greet.go", ch.FilePath)
+	}
+	assert.NotEmpty(t, b.Rules)
+	assert.Empty(t, b.Omitted)
+
+	out := Render(b)
+	assert.Contains(t, out, "Greet louder")
+	assert.Contains(t, out, "## Spec: spec/greet.md (`GREET`)")
+	assert.Contains(t, out, "- `func Hello() string` (greet/greet.go:4)")
+	assert.Contains(t, out, "```go\n")
+}
+
+func TestCompose_PathBringsFeature(t *testing.T) {
+	root, res := setup(t)
+
+	b, err := Compose(root, res, nil, tokens.Estimate{}, Options{Paths: []string{"other/*.go", "README.md"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OTHER"}, b.Features)
+	assert.Equal(t, []string{"README.md", "other/other.go"}, b.Files)
+	require.Len(t, b.Specs, 1)
+	assert.Equal(t, "spec/other.md", b.Specs[0].Path)
+	assert.Empty(t, b.Symbols)
+}
+
+func TestCompose_Budget(t *testing.T) {
+	root, res := setup(t)
+
+	full, err := Compose(root, res, nil, tokens.Estimate{}, Options{Features: []string{"GREET"}})
+	require.NoError(t, err)
+
+	b, err := Compose(root, res, nil, tokens.Estimate{}, Options{Features: []string{"GREET"}, Budget: full.Tokens - 1})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, b.Tokens, full.Tokens-1)
+	require.NotEmpty(t, b.Omitted)
+	omitted := 0
+	for _, o := range b.Omitted {
+		omitted += o.Tokens
+	}
+	assert.Equal(t, full.Tokens, b.Tokens+omitted)
+
+	again, err := Compose(root, res, nil, tokens.Estimate{}, Options{Features: []string{"GREET"}, Budget: full.Tokens - 1})
+	require.NoError(t, err)
+	assert.Equal(t, b, again)
+}
+
+func TestCompose_Errors(t *testing.T) {
+	root, res := setup(t)
+
+	_, err := Compose(root, res, nil, tokens.Estimate{}, Options{})
+	assert.Error(t, err)
+	_, err = Compose(root, res, nil, tokens.Estimate{}, Options{Features: []string{"NOPE"}})
+	assert.ErrorContains(t, err, "unknown feature")
+	_, err = Compose(root, res, nil, tokens.Estimate{}, Options{Paths: []string{"[a"}})
+	assert.Error(t, err)
+}
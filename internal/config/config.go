@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package config loads the repository configuration from .cortex/config.yaml.
+// A missing file is equivalent to an empty one; every section has usable
+// zero-value defaults. Named profiles in the file and CORTEX_CONFIG_
+// environment variables override it (see Options), and nested .cortex.yaml
+// files override its rules for their subtree (see NestedFile).
+package config
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Config is the parsed .cortex/config.yaml.
+type Config struct {
+	Branches Branches `yaml:"branches"`
+	Commits  Commits  `yaml:"commits"`
+	Context  Context  `yaml:"context"`
+	Deps     Deps     `yaml:"deps"`
+	Env      Env      `yaml:"env"`
+	Features Features `yaml:"features"`
+	Files    Files    `yaml:"files"`
+	LLM      LLM      `yaml:"llm"`
+	// Rules maps a rule or skill ID (cortex rules list) to the severity of
+	// its findings: off, warning or error. Nested .cortex.yaml files
+	// override it for their subtree (see NestedFile).
+	Rules  map[string]string `yaml:"rules"`
+	Server Server            `yaml:"server"`
+	Skills Skills            `yaml:"skills"`
+	Sync   Sync              `yaml:"sync"`
+	Tools  Tools             `yaml:"tools"`
+}
+
+// Branches configures branch validation (git:branch-policy). The base ref
+// is commits.base, resolved the same way as for commit validation.
+type Branches struct {
+	// Patterns lists the allowed branch name globs; "{feature}" matches a
+	// feature ID. Empty means feature/{feature}-* and fix/*.
+	Patterns []string `yaml:"patterns"`
+	// MaxBehind is how many base commits the branch may be missing.
+	MaxBehind int `yaml:"max_behind"`
+}
+
+// Commits configures commit message validation (git:commit-conventions).
+// Empty fields fall back to the Conventional Commits defaults.
+type Commits struct {
+	// Base is the ref the current branch is compared against; when empty the
+	// first existing of origin/main, main, origin/master and master is used.
+	Base string `yaml:"base"`
+	// Types lists the allowed commit types.
+	Types []string `yaml:"types"`
+	// Scopes lists the allowed scopes; empty allows any scope.
+	Scopes []string `yaml:"scopes"`
+	// RequireScope rejects headers without a scope.
+	RequireScope bool `yaml:"require_scope"`
+	// MaxHeaderLength caps the header length in characters; zero means 72.
+	MaxHeaderLength int `yaml:"max_header_length"`
+}
+
+// Context configures the context pipeline.
+type Context struct {
+	Budget Budget `yaml:"budget"`
+	Tokens Tokens `yaml:"tokens"`
+}
+
+// Tokens configures token counting (`cortex context tokens` and the token
+// counts of manifest.json).
+type Tokens struct {
+	// Encoding is a tiktoken encoding file, such as cl100k_base.tiktoken,
+	// relative to the repository root. Empty counts with the built-in
+	// estimate.
+	Encoding string `yaml:"encoding"`
+	// Models maps a model name to its context window in tokens.
+	Models map[string]int `yaml:"models"`
+}
+
+// Budget limits the size of the built context and declares which files are
+// kept first when the limits are exceeded. Zero limits mean unlimited.
+type Budget struct {
+	MaxBytes int64 `yaml:"max_bytes"`
+	MaxFiles int   `yaml:"max_files"`
+	// Dirs maps a repo-relative directory to a priority weight; the longest
+	// matching directory applies to each file.
+	Dirs map[string]int `yaml:"dirs"`
+	// Types maps a file extension (".go") or XRAY language ("Markdown") to a
+	// priority weight; the extension takes precedence.
+	Types map[string]int `yaml:"types"`
+	// Recency prefers recently committed files among files of equal weight.
+	Recency bool `yaml:"recency"`
+}
+
+// Deps configures the dependency policy (deps:policy) applied to the
+// requirements of every go.mod. Module patterns match a module path
+// exactly; a pattern ending in "/..." also matches the modules below it.
+type Deps struct {
+	// Deny lists modules that must not be required.
+	Deny []DeniedModule `yaml:"deny"`
+	// MaxMajor maps a module pattern, matched against the path without its
+	// /vN suffix, to the highest major version allowed.
+	MaxMajor map[string]int `yaml:"max_major"`
+	// MinVersion maps a module pattern to the lowest version allowed, such
+	// as the release with a security fix.
+	MinVersion map[string]string `yaml:"min_version"`
+	// Licenses lists the allowed SPDX license IDs; empty allows any license
+	// and skips license detection.
+	Licenses []string `yaml:"licenses"`
+	// DirectOnly skips requirements marked // indirect.
+	DirectOnly bool `yaml:"direct_only"`
+	// Exceptions waive rules for modules.
+	Exceptions []DepException `yaml:"exceptions"`
+}
+
+// DeniedModule is a module that must not be required, and why.
+type DeniedModule struct {
+	Module string `yaml:"module"`
+	Reason string `yaml:"reason"`
+}
+
+// DepException waives rules for the modules matching Module. Rules lists
+// any of deny, max_major, min_version and license; empty waives them all.
+// Reason is required.
+type DepException struct {
+	Module string   `yaml:"module"`
+	Rules  []string `yaml:"rules"`
+	Reason string   `yaml:"reason"`
+}
+
+// Env configures environment variable checks (compose:env-consistency).
+type Env struct {
+	// Ignore lists variables that need no .env.example entry, e.g. ones the
+	// CI platform provides; they are added to the OS defaults.
+	Ignore []string `yaml:"ignore"`
+}
+
+// Features configures feature registry tooling.
+type Features struct {
+	// Annotate maps files to features for `cortex features annotate`; the
+	// first matching rule wins.
+	Annotate []AnnotateRule `yaml:"annotate"`
+	// Owners maps a feature owner to the other identities it goes by for
+	// `cortex gov owners`: CODEOWNERS handles ("@bartekus"), commit author
+	// names and emails.
+	Owners map[string][]string `yaml:"owners"`
+}
+
+// AnnotateRule assigns the files matching Path to Feature. A Path ending in
+// "/" matches every file below that directory; anything else is a
+// path.Match glob against the repo-relative file path.
+type AnnotateRule struct {
+	Path    string `yaml:"path"`
+	Feature string `yaml:"feature"`
+}
+
+// Files configures the binary and large-file policy of context chunking
+// and `cortex grep` (scanner.Policy). Binary files and files over the size
+// limit are recorded by metadata only; their content is never read whole.
+// The cortex-mcp server reads its limit from $CORTEX_MAX_FILE_BYTES.
+type Files struct {
+	// MaxBytes is the size limit in bytes. Zero means 2 MiB; a negative
+	// value disables the limit.
+	MaxBytes int64 `yaml:"max_bytes"`
+	// Generated lists globs of generated files (scanner.Generated), in
+	// addition to those carrying a "Code generated ... DO NOT EDIT." header.
+	Generated []string `yaml:"generated"`
+}
+
+// LLM configures the language model of the optional generative features
+// (pkg/llm). The API key is read from the environment, never from the file.
+type LLM struct {
+	// Provider is openai, anthropic or ollama; empty disables the features.
+	Provider string `yaml:"provider"`
+	// Model and BaseURL default per provider.
+	Model   string `yaml:"model"`
+	BaseURL string `yaml:"base_url"`
+}
+
+// Server configures `cortex serve`.
+type Server struct {
+	Webhook Webhook `yaml:"webhook"`
+}
+
+// Webhook is the profile run for GitHub push and pull request events.
+type Webhook struct {
+	// Skills lists the skill IDs to run; empty runs every skill.
+	Skills []string `yaml:"skills"`
+	// FailOnWarning fails skills that only found warnings.
+	FailOnWarning bool `yaml:"fail_on_warning"`
+}
+
+// Skills configures how `cortex run` executes skills.
+type Skills struct {
+	// Retry maps a skill ID, or "*" for every skill without its own entry,
+	// to a retry policy for transient failures.
+	Retry map[string]Retry `yaml:"retry"`
+	// Timeout maps a skill ID, or "*" for every skill without its own entry,
+	// to the time one attempt may take (a duration such as "10m"); zero
+	// disables the limit. Without a "*" entry the runner default applies.
+	Timeout map[string]time.Duration `yaml:"timeout"`
+	// Limits maps a skill ID, or "*" for every skill without its own entry,
+	// to resource limits for the processes the skill starts.
+	Limits map[string]Limits `yaml:"limits"`
+	// Sandbox maps a skill ID, or "*" for every skill without its own
+	// entry, to the sandbox the processes the skill starts run in.
+	Sandbox map[string]Sandbox `yaml:"sandbox"`
+	// Env maps a skill ID, or "*" for every skill without its own entry,
+	// to the environment the processes the skill starts get.
+	Env map[string]SkillEnv `yaml:"env"`
+}
+
+// Limits bounds the child processes of a skill (go test, golangci-lint).
+// Zero fields impose no limit.
+type Limits struct {
+	// GOMAXPROCS is exported to the child.
+	GOMAXPROCS int `yaml:"gomaxprocs"`
+	// GOFLAGS is appended to the inherited GOFLAGS.
+	GOFLAGS string `yaml:"goflags"`
+	// Parallel adds -p=N to GOFLAGS.
+	Parallel int `yaml:"parallel"`
+	// Nice lowers the child's scheduling priority (0-19, Unix only).
+	Nice int `yaml:"nice"`
+	// MemoryMB sets GOMEMLIMIT and, on Linux, an address space limit.
+	MemoryMB int `yaml:"memory_mb"`
+	// CPUSeconds caps CPU time (Linux only).
+	CPUSeconds int `yaml:"cpu_seconds"`
+}
+
+// Sandbox isolates the child processes of a skill from the network, the
+// environment and the repository. The zero value runs them unsandboxed.
+type Sandbox struct {
+	// Mode is "off" (default), "bwrap", "docker" or "auto" (bwrap, else
+	// docker when Image is set, else only the environment is restricted).
+	Mode string `yaml:"mode"`
+	// Network allows network access, off in a sandbox by default.
+	Network bool `yaml:"network"`
+	// Env lists the variables passed through besides the toolchain ones.
+	Env []string `yaml:"env"`
+	// Writable lists the repository paths the processes may modify; the
+	// rest of the repository is read-only.
+	Writable []string `yaml:"writable"`
+	// Image is the container image of docker mode.
+	Image string `yaml:"image"`
+}
+
+// SkillEnv is the environment policy of a skill's child processes. By
+// default they only get PATH, HOME and GOCACHE.
+type SkillEnv struct {
+	// Passthrough lists further variables to pass; a trailing "*" matches a
+	// prefix.
+	Passthrough []string `yaml:"passthrough"`
+	// Inherit passes the whole environment.
+	Inherit bool `yaml:"inherit"`
+}
+
+// Retry re-runs a failed skill up to Count more times, waiting Backoff
+// (a duration such as "2s") before the first retry and doubling it after.
+type Retry struct {
+	Count   int           `yaml:"count"`
+	Backoff time.Duration `yaml:"backoff"`
+}
+
+// Sync configures `cortex sync`. Credentials are never read from the
+// config, only from the environment.
+type Sync struct {
+	// Remote is the default remote: s3://bucket/prefix, gs://bucket/prefix
+	// or file:///path.
+	Remote string `yaml:"remote"`
+	// Region is the S3 region; empty means $AWS_REGION, then us-east-1.
+	Region string `yaml:"region"`
+	// Endpoint is the base URL of an S3-compatible service (MinIO, R2) or a
+	// GCS emulator.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Tools configures the external programs skills run.
+type Tools struct {
+	// Versions maps a tool name ("go", "golangci-lint") to the lowest
+	// version `cortex doctor` accepts, such as "1.24" or "v2.6.2".
+	Versions map[string]string `yaml:"versions"`
+}
+
+// Path returns the config location under repoRoot.
+func Path(repoRoot string) string {
+	return filepath.Join(repoRoot, ".cortex", "config.yaml")
+}
+
+// Load reads the config for repoRoot with the profile named by
+// $CORTEX_PROFILE and the CORTEX_CONFIG_ environment overrides applied.
+func Load(repoRoot string) (*Config, error) {
+	return LoadWith(repoRoot, DefaultOptions())
+}
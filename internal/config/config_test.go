@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+
+	cfg, err := Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, cfg)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(Path(root)), 0o755))
+	data := "context:\n  budget:\n    max_bytes: 1024\n    dirs:\n      docs: -1\n    recency: true\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Budget{MaxBytes: 1024, Dirs: map[string]int{"docs": -1}, Recency: true}, cfg.Context.Budget)
+
+	data = "commits:\n  base: develop\n  types: [feat, fix]\n  require_scope: true\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Commits{Base: "develop", Types: []string{"feat", "fix"}, RequireScope: true}, cfg.Commits)
+
+	data = "branches:\n  patterns: [\"feat/{feature}-*\"]\n  max_behind: 3\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Branches{Patterns: []string{"feat/{feature}-*"}, MaxBehind: 3}, cfg.Branches)
+
+	data = "env:\n  ignore: [GITHUB_TOKEN, CI]\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Env{Ignore: []string{"GITHUB_TOKEN", "CI"}}, cfg.Env)
+
+	data = "features:\n  annotate:\n    - path: internal/\n      feature: CORE\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, []AnnotateRule{{Path: "internal/", Feature: "CORE"}}, cfg.Features.Annotate)
+
+	data = "features:\n  owners:\n    bart: [\"@bartekus\", bart@example.com]\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"bart": {"@bartekus", "bart@example.com"}}, cfg.Features.Owners)
+
+	data = "files:\n  max_bytes: -1\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Files{MaxBytes: -1}, cfg.Files)
+
+	data = "server:\n  webhook:\n    skills: [test:go]\n    fail_on_warning: true\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Webhook{Skills: []string{"test:go"}, FailOnWarning: true}, cfg.Server.Webhook)
+
+	data = "skills:\n  retry:\n    \"*\": {count: 1}\n    test:go: {count: 2, backoff: 500ms}\n  timeout:\n    \"*\": 10m\n    lint:golangci: 0s\n  limits:\n    test:go: {gomaxprocs: 2, parallel: 2, nice: 10, memory_mb: 4096}\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Retry{"*": {Count: 1}, "test:go": {Count: 2, Backoff: 500 * time.Millisecond}}, cfg.Skills.Retry)
+	assert.Equal(t, map[string]time.Duration{"*": 10 * time.Minute, "lint:golangci": 0}, cfg.Skills.Timeout)
+	assert.Equal(t, map[string]Limits{"test:go": {GOMAXPROCS: 2, Parallel: 2, Nice: 10, MemoryMB: 4096}}, cfg.Skills.Limits)
+
+	data = "sync:\n  remote: s3://ci-cache/cortex\n  region: eu-west-1\n  endpoint: http://localhost:9000\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Sync{Remote: "s3://ci-cache/cortex", Region: "eu-west-1", Endpoint: "http://localhost:9000"}, cfg.Sync)
+
+	data = "deps:\n  deny:\n    - {module: github.com/pkg/errors, reason: use fmt}\n  max_major: {github.com/go-chi/...: 5}\n  licenses: [MIT]\n  direct_only: true\n  exceptions:\n    - {module: github.com/a/b, rules: [license], reason: approved}\n"
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	cfg, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, Deps{
+		Deny:       []DeniedModule{{Module: "github.com/pkg/errors", Reason: "use fmt"}},
+		MaxMajor:   map[string]int{"github.com/go-chi/...": 5},
+		Licenses:   []string{"MIT"},
+		DirectOnly: true,
+		Exceptions: []DepException{{Module: "github.com/a/b", Rules: []string{"license"}, Reason: "approved"}},
+	}, cfg.Deps)
+
+	require.NoError(t, os.WriteFile(Path(root), []byte("context: ["), 0o644))
+	_, err = Load(root)
+	assert.Error(t, err)
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv names the environment variable selecting the profile Load
+// applies.
+const ProfileEnv = "CORTEX_PROFILE"
+
+// EnvPrefix starts the environment variables that override single values:
+// the rest of the name is the key path with "__" between levels, matched
+// case-insensitively, and the value is parsed as YAML. For example
+// CORTEX_CONFIG_SYNC__REMOTE=s3://bucket sets sync.remote.
+const EnvPrefix = "CORTEX_CONFIG_"
+
+// profilesKey is the top-level key holding the named profiles. A profile
+// has the sections of the config and is merged over the file: mappings are
+// merged key by key, any other value replaces the file's.
+const profilesKey = "profiles"
+
+// Options selects the overrides applied on top of config.yaml, in order:
+// the profile, then the environment overrides.
+type Options struct {
+	// Profile names the entry of profiles to apply; empty applies none.
+	Profile string
+	// Environ holds KEY=value pairs; the EnvPrefix entries apply.
+	Environ []string
+}
+
+// DefaultOptions returns the options of the process: the profile named by
+// $CORTEX_PROFILE and its environment.
+func DefaultOptions() Options {
+	return Options{Profile: os.Getenv(ProfileEnv), Environ: os.Environ()}
+}
+
+// Override is a value set by an EnvPrefix environment variable.
+type Override struct {
+	// Var is the variable name.
+	Var string
+	// Path is the lowercase key path.
+	Path []string
+	// Value is the raw value, parsed as YAML when applied.
+	Value string
+}
+
+// Overrides returns the EnvPrefix overrides of environ, sorted by variable.
+func Overrides(environ []string) []Override {
+	var out []Override
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		rest, found := strings.CutPrefix(name, EnvPrefix)
+		if !ok || !found || rest == "" {
+			continue
+		}
+		out = append(out, Override{Var: name, Path: strings.Split(strings.ToLower(rest), "__"), Value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Var < out[j].Var })
+	return out
+}
+
+// LoadWith reads the config for repoRoot with the overrides of o applied.
+// An unknown profile is an error.
+func LoadWith(repoRoot string, o Options) (*Config, error) {
+	root, err := readNode(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	profiles := removeKey(root, profilesKey)
+	if o.Profile != "" {
+		p := mappingValue(profiles, o.Profile)
+		if p == nil {
+			return nil, fmt.Errorf("unknown profile %q (defined: %s)", o.Profile, strings.Join(profileNames(profiles), ", "))
+		}
+		merge(root, p)
+	}
+	for _, ov := range Overrides(o.Environ) {
+		if err := setPath(root, ov.Path, ov.Value); err != nil {
+			return nil, fmt.Errorf("%s: %w", ov.Var, err)
+		}
+	}
+
+	var cfg Config
+	if err := root.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config.yaml: %w", err)
+	}
+	return &cfg, nil
+}
+
+// profileNames returns the names of the profiles mapping, sorted.
+func profileNames(profiles *yaml.Node) []string {
+	var names []string
+	if profiles != nil && profiles.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(profiles.Content); i += 2 {
+			names = append(names, profiles.Content[i].Value)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readNode returns the top-level mapping of config.yaml; an empty mapping
+// when the file is missing or empty.
+func readNode(repoRoot string) (*yaml.Node, error) {
+	data, err := os.ReadFile(Path(repoRoot))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config.yaml: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse config.yaml: line %d: the top level must be a mapping", root.Line)
+	}
+	return root, nil
+}
+
+// mappingValue returns the value of key in mapping m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeKey deletes key from mapping m and returns its value, or nil.
+func removeKey(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			v := m.Content[i+1]
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return v
+		}
+	}
+	return nil
+}
+
+// merge merges mapping src into mapping dst: nested mappings are merged,
+// other values replace dst's.
+func merge(dst, src *yaml.Node) {
+	if src.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		cur := mappingValue(dst, key.Value)
+		switch {
+		case cur == nil:
+			dst.Content = append(dst.Content, key, val)
+		case cur.Kind == yaml.MappingNode && val.Kind == yaml.MappingNode:
+			merge(cur, val)
+		default:
+			*cur = *val
+		}
+	}
+}
+
+// setPath sets the value at path below mapping m to value parsed as YAML,
+// creating the mappings on the way.
+func setPath(m *yaml.Node, path []string, value string) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	if len(doc.Content) > 0 {
+		val = doc.Content[0]
+	}
+
+	for i, key := range path {
+		if m.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", strings.Join(path[:i], "."))
+		}
+		cur := mappingValue(m, key)
+		if i == len(path)-1 {
+			if cur != nil {
+				*cur = *val
+			} else {
+				m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, val)
+			}
+			return nil
+		}
+		if cur == nil || cur.Tag == "!!null" {
+			next := &yaml.Node{Kind: yaml.MappingNode}
+			if cur != nil {
+				*cur = *next
+				next = cur
+			} else {
+				m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, next)
+			}
+			cur = next
+		}
+		m = cur
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Dir(Path(root)), 0o755))
+	require.NoError(t, os.WriteFile(Path(root), []byte(data), 0o644))
+	return root
+}
+
+func TestLoadWith_ProfileAndOverrides(t *testing.T) {
+	root := writeConfig(t, `
+skills:
+  timeout:
+    "*": 10m
+  retry:
+    test:go: {count: 1}
+sync:
+  remote: s3://base
+profiles:
+  ci:
+    skills:
+      timeout:
+        test:go: 20m
+    env:
+      ignore: [CI]
+`)
+
+	cfg, err := LoadWith(root, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"*": 10 * time.Minute}, cfg.Skills.Timeout)
+	assert.Empty(t, cfg.Env.Ignore)
+
+	cfg, err = LoadWith(root, Options{Profile: "ci", Environ: []string{
+		"CORTEX_CONFIG_SYNC__REMOTE=s3://override",
+		"CORTEX_CONFIG_files__max_bytes=1024",
+		"CORTEX_CONFIG_COMMITS__TYPES=[feat, fix]",
+		"CORTEX_PROFILE=ignored",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"*": 10 * time.Minute, "test:go": 20 * time.Minute}, cfg.Skills.Timeout)
+	assert.Equal(t, map[string]Retry{"test:go": {Count: 1}}, cfg.Skills.Retry)
+	assert.Equal(t, []string{"CI"}, cfg.Env.Ignore)
+	assert.Equal(t, "s3://override", cfg.Sync.Remote)
+	assert.Equal(t, int64(1024), cfg.Files.MaxBytes)
+	assert.Equal(t, []string{"feat", "fix"}, cfg.Commits.Types)
+
+	_, err = LoadWith(root, Options{Profile: "nope"})
+	assert.EqualError(t, err, `unknown profile "nope" (defined: ci)`)
+}
+
+func TestLoadWith_OverridesWithoutFile(t *testing.T) {
+	cfg, err := LoadWith(t.TempDir(), Options{Environ: []string{"CORTEX_CONFIG_SERVER__WEBHOOK__FAIL_ON_WARNING=true"}})
+	require.NoError(t, err)
+	assert.True(t, cfg.Server.Webhook.FailOnWarning)
+}
+
+func TestOverrides(t *testing.T) {
+	got := Overrides([]string{"CORTEX_CONFIG_SYNC__REGION=eu", "CORTEX_CONFIG_=x", "HOME=/root", "CORTEX_CONFIG_A__B=1"})
+	assert.Equal(t, []Override{
+		{Var: "CORTEX_CONFIG_A__B", Path: []string{"a", "b"}, Value: "1"},
+		{Var: "CORTEX_CONFIG_SYNC__REGION", Path: []string{"sync", "region"}, Value: "eu"},
+	}, got)
+}
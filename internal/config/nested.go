@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NestedFile is the name of the per-directory config files. A .cortex.yaml
+// in a subdirectory overrides the rule severities of Config.Rules for the
+// files below it; the root directory's rules live in config.yaml.
+const NestedFile = ".cortex.yaml"
+
+// Rule severities, the values of Config.Rules and DirConfig.Rules. Off
+// drops findings, warning keeps them without failing the skill (unless
+// --fail-on-warning) and error fails it.
+const (
+	RuleOff     = "off"
+	RuleWarning = "warning"
+	RuleError   = "error"
+)
+
+// DirConfig is a nested config file.
+type DirConfig struct {
+	// Dir is the repo-relative, slash separated directory of the file.
+	Dir string `yaml:"-" json:"dir"`
+	// Rules maps a rule or skill ID to the severity of its findings in the
+	// files below Dir.
+	Rules map[string]string `yaml:"rules" json:"rules"`
+}
+
+// NestedFiles returns the nested config files among the repo-relative,
+// slash separated files, sorted by directory so a parent comes before its
+// subdirectories ("a" before "a/-x", which sorts first as a full path).
+func NestedFiles(files []string) []string {
+	var out []string
+	for _, f := range files {
+		if path.Base(f) == NestedFile && path.Dir(f) != "." {
+			out = append(out, f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return path.Dir(out[i]) < path.Dir(out[j]) })
+	return out
+}
+
+// LoadNested reads the nested config files among files, sorted by
+// directory. A file with a problem ValidateNested reports is an error.
+func LoadNested(repoRoot string, files []string) ([]DirConfig, error) {
+	var out []DirConfig
+	for _, rel := range NestedFiles(files) {
+		root, diags, err := readNested(repoRoot, rel)
+		if err != nil {
+			return nil, err
+		}
+		if len(diags) > 0 {
+			return nil, errors.New(diags[0].String())
+		}
+		dc := DirConfig{Dir: path.Dir(rel)}
+		if root != nil {
+			if err := root.Decode(&dc); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", rel, err)
+			}
+		}
+		out = append(out, dc)
+	}
+	return out, nil
+}
+
+// ValidateNested checks the nested config files among files: the only key
+// is rules, mapping IDs to off, warning or error. Only an unreadable file
+// is an error.
+func ValidateNested(repoRoot string, files []string) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	for _, rel := range NestedFiles(files) {
+		_, d, err := readNested(repoRoot, rel)
+		if err != nil {
+			return nil, err
+		}
+		diags = append(diags, d...)
+	}
+	return diags, nil
+}
+
+// readNested parses the nested config file rel and checks it; the node is
+// nil when the file is empty or does not parse.
+func readNested(repoRoot, rel string) (*yaml.Node, []Diagnostic, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(rel))) //nolint:gosec // G304: rel is a tracked file
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	root, diags := parseDocument(rel, data)
+	if root == nil || root.Tag == "!!null" {
+		return nil, diags, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, []Diagnostic{{Source: rel, Line: root.Line, Column: root.Column, Message: "the top level must be a mapping"}}, nil
+	}
+	diags = unknownKeys(rel, root, reflect.TypeOf(DirConfig{}), nil)
+	if rules := mappingValue(root, "rules"); rules != nil {
+		diags = append(diags, checkRules(rel, rules, "rules")...)
+	}
+	return root, diags, nil
+}
+
+// checkRules reports the entries of a rules mapping that are not a
+// severity.
+func checkRules(source string, n *yaml.Node, prefix string) []Diagnostic {
+	if n.Tag == "!!null" {
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return []Diagnostic{{Source: source, Line: n.Line, Column: n.Column, Path: prefix, Message: "must be a mapping"}}
+	}
+	var diags []Diagnostic
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		if val.Kind != yaml.ScalarNode || !validSeverity(val.Value) {
+			diags = append(diags, Diagnostic{Source: source, Line: val.Line, Column: val.Column, Path: prefix + "." + key.Value,
+				Message: fmt.Sprintf("invalid severity %q (want off, warning or error)", val.Value)})
+		}
+	}
+	return diags
+}
+
+func validSeverity(s string) bool {
+	return s == RuleOff || s == RuleWarning || s == RuleError
+}
+
+// applies reports whether the nested config of dir covers the file at rel.
+func applies(dir, rel string) bool {
+	return dir == "." || strings.HasPrefix(rel, dir+"/")
+}
+
+// RuleSeverity returns the severity set for the findings of rule, a rule
+// of skill, in the file at rel (repo-relative, slash separated; empty for
+// findings without a file), and false when no rules entry sets one. The
+// root rules apply first, then the nested configs of the directories of
+// rel from the top down, each deeper one winning; within one of them the
+// entry of the rule wins over that of its skill. Nested configs must be
+// sorted by directory, as LoadNested returns them.
+func RuleSeverity(root map[string]string, nested []DirConfig, rel, rule, skill string) (string, bool) {
+	sev, ok := layerSeverity(root, rule, skill)
+	for _, dc := range nested {
+		if rel == "" || !applies(dc.Dir, rel) {
+			continue
+		}
+		if s, found := layerSeverity(dc.Rules, rule, skill); found {
+			sev, ok = s, true
+		}
+	}
+	return sev, ok
+}
+
+func layerSeverity(rules map[string]string, rule, skill string) (string, bool) {
+	if s, ok := rules[rule]; ok {
+		return s, true
+	}
+	s, ok := rules[skill]
+	return s, ok
+}
+
+// EffectiveRules returns the rules entries in effect for the files below
+// dir: the root rules with the nested configs of dir and its parents merged
+// over them, deeper ones winning per ID.
+func EffectiveRules(root map[string]string, nested []DirConfig, dir string) map[string]string {
+	out := map[string]string{}
+	for id, sev := range root {
+		out[id] = sev
+	}
+	for _, dc := range nested {
+		if dc.Dir == dir || applies(dc.Dir, dir) {
+			for id, sev := range dc.Rules {
+				out[id] = sev
+			}
+		}
+	}
+	return out
+}
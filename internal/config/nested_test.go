@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: CLI_COMMAND_CONFIG
+// Spec: spec/cli/config.md
+
+func writeNested(t *testing.T, root string, files map[string]string) []string {
+	t.Helper()
+	var rels []string
+	for rel, data := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(data), 0o644))
+		rels = append(rels, rel)
+	}
+	return append(rels, "main.go", ".cortex.yaml")
+}
+
+func TestLoadNested(t *testing.T) {
+	root := t.TempDir()
+	files := writeNested(t, root, map[string]string{
+		"tools/.cortex.yaml":        "rules:\n  purity/os-exec: off\n",
+		"docs/archive/.cortex.yaml": "rules:\n  docs:doc-patterns: warning\n",
+		"docs/.cortex.yaml":         "",
+	})
+
+	nested, err := LoadNested(root, files)
+	require.NoError(t, err)
+	assert.Equal(t, []DirConfig{
+		{Dir: "docs"},
+		{Dir: "docs/archive", Rules: map[string]string{"docs:doc-patterns": RuleWarning}},
+		{Dir: "tools", Rules: map[string]string{"purity/os-exec": RuleOff}},
+	}, nested, "sorted by directory; a root .cortex.yaml is not nested")
+}
+
+func TestNestedFiles_ParentFirst(t *testing.T) {
+	// As full paths "a/-x/.cortex.yaml" sorts before "a/.cortex.yaml".
+	files := []string{"a/.cortex.yaml", "a/-x/.cortex.yaml", "a/b.go", ".cortex.yaml"}
+	assert.Equal(t, []string{"a/.cortex.yaml", "a/-x/.cortex.yaml"}, NestedFiles(files))
+
+	root := t.TempDir()
+	files = writeNested(t, root, map[string]string{
+		"a/-x/.cortex.yaml": "rules:\n  purity: error\n",
+		"a/.cortex.yaml":    "rules:\n  purity: off\n",
+	})
+	nested, err := LoadNested(root, files)
+	require.NoError(t, err)
+	sev, _ := RuleSeverity(nil, nested, "a/-x/main.go", "purity/os-exec", "purity")
+	assert.Equal(t, RuleError, sev, "the subdirectory overrides its parent")
+}
+
+func TestValidateNested(t *testing.T) {
+	root := t.TempDir()
+	files := writeNested(t, root, map[string]string{
+		"a/.cortex.yaml": "rules:\n  purity: loose\n  test:go: error\nskills: {}\n",
+		"b/.cortex.yaml": "rules: [purity]\n",
+		"c/.cortex.yaml": "- x\n",
+	})
+
+	diags, err := ValidateNested(root, files)
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{Source: "a/.cortex.yaml", Line: 4, Column: 1, Path: "skills", Message: "unknown key"},
+		{Source: "a/.cortex.yaml", Line: 2, Column: 11, Path: "rules.purity", Message: `invalid severity "loose" (want off, warning or error)`},
+		{Source: "b/.cortex.yaml", Line: 1, Column: 8, Path: "rules", Message: "must be a mapping"},
+		{Source: "c/.cortex.yaml", Line: 1, Column: 1, Message: "the top level must be a mapping"},
+	}, diags)
+
+	_, err = LoadNested(root, files)
+	assert.EqualError(t, err, "a/.cortex.yaml:4:1: skills: unknown key")
+}
+
+func TestRuleSeverity(t *testing.T) {
+	rootRules := map[string]string{"purity/os-exec": RuleError, "docs:doc-patterns": RuleWarning}
+	nested := []DirConfig{
+		{Dir: "docs", Rules: map[string]string{"docs:doc-patterns/filename": RuleError}},
+		{Dir: "docs/archive", Rules: map[string]string{"docs:doc-patterns": RuleOff}},
+		{Dir: "tools", Rules: map[string]string{"purity": RuleOff}},
+	}
+
+	tests := []struct {
+		rel, rule, skill string
+		want             string
+		ok               bool
+	}{
+		{"cmd/x.go", "purity/os-exec", "purity", RuleError, true},
+		{"tools/gen/x.go", "purity/os-exec", "purity", RuleOff, true},
+		{"toolsx/x.go", "purity/os-exec", "purity", RuleError, true},
+		{"docs/a b.md", "docs:doc-patterns/spaces", "docs:doc-patterns", RuleWarning, true},
+		{"docs/x.md", "docs:doc-patterns/filename", "docs:doc-patterns", RuleError, true},
+		{"docs/archive/x.md", "docs:doc-patterns/filename", "docs:doc-patterns", RuleOff, true},
+		{"", "purity/os-exec", "purity", RuleError, true},
+		{"tools/x.go", "test:go", "test:go", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RuleSeverity(rootRules, nested, tt.rel, tt.rule, tt.skill)
+		assert.Equal(t, tt.want, got, tt.rel+" "+tt.rule)
+		assert.Equal(t, tt.ok, ok, tt.rel+" "+tt.rule)
+	}
+
+	assert.Equal(t, map[string]string{
+		"purity/os-exec":             RuleError,
+		"docs:doc-patterns":          RuleOff,
+		"docs:doc-patterns/filename": RuleError,
+	}, EffectiveRules(rootRules, nested, "docs/archive"))
+	assert.Equal(t, rootRules, EffectiveRules(rootRules, nested, "cmd"))
+}
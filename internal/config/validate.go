@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+// File is the config location relative to the repository root, the source
+// of file diagnostics.
+const File = ".cortex/config.yaml"
+
+// Diagnostic is a problem found by Validate.
+type Diagnostic struct {
+	// Source is File, a nested config file, the name of an environment
+	// override or "profile".
+	Source string `json:"source"`
+	// Line and Column locate the problem in a file; 0 elsewhere.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Path is the dotted key path, empty for the whole document.
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	loc := d.Source
+	if d.Line > 0 {
+		loc += fmt.Sprintf(":%d:%d", d.Line, d.Column)
+	}
+	if d.Path != "" {
+		loc += ": " + d.Path
+	}
+	return loc + ": " + d.Message
+}
+
+// Validate checks the config of repoRoot, its profiles and the overrides of
+// o against the embedded config schema. Unknown keys are reported with the
+// closest known key. Only an unreadable file is an error.
+func Validate(repoRoot string, o Options) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	profiles := &yaml.Node{Kind: yaml.MappingNode}
+
+	data, err := os.ReadFile(Path(repoRoot))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read config.yaml: %w", err)
+	default:
+		root, d := parseDocument(File, data)
+		diags = append(diags, d...)
+		if root != nil {
+			d := checkDocument(File, root)
+			sort.SliceStable(d, func(i, j int) bool {
+				if d[i].Line != d[j].Line {
+					return d[i].Line < d[j].Line
+				}
+				return d[i].Column < d[j].Column
+			})
+			diags = append(diags, d...)
+			if p := mappingValue(root, profilesKey); p != nil {
+				profiles = p
+			}
+		}
+	}
+
+	if o.Profile != "" && mappingValue(profiles, o.Profile) == nil {
+		diags = append(diags, Diagnostic{Source: "profile", Message: fmt.Sprintf("unknown profile %q (defined: %s)",
+			o.Profile, strings.Join(profileNames(profiles), ", "))})
+	}
+
+	for _, ov := range Overrides(o.Environ) {
+		doc := &yaml.Node{Kind: yaml.MappingNode}
+		if err := setPath(doc, ov.Path, ov.Value); err != nil {
+			diags = append(diags, Diagnostic{Source: ov.Var, Path: strings.Join(ov.Path, "."), Message: err.Error()})
+			continue
+		}
+		for _, d := range checkDocument(ov.Var, doc) {
+			d.Line, d.Column = 0, 0
+			diags = append(diags, d)
+		}
+	}
+	return diags, nil
+}
+
+var yamlLine = regexp.MustCompile(`line (\d+)`)
+
+// parseDocument parses data, the content of source, into its top-level
+// node; nil when the document is empty or does not parse.
+func parseDocument(source string, data []byte) (*yaml.Node, []Diagnostic) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		d := Diagnostic{Source: source, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+		if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+			d.Column = 1
+			d.Message = strings.TrimPrefix(d.Message, m[0]+": ")
+		}
+		return nil, []Diagnostic{d}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// checkDocument returns the unknown keys and schema violations of root.
+func checkDocument(source string, root *yaml.Node) []Diagnostic {
+	diags := unknownKeys(source, root, reflect.TypeOf(Config{}), nil)
+
+	var doc any
+	if err := root.Decode(&doc); err != nil {
+		return append(diags, Diagnostic{Source: source, Line: root.Line, Column: root.Column, Message: err.Error()})
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return append(diags, Diagnostic{Source: source, Line: root.Line, Column: root.Column, Message: "keys must be strings"})
+	}
+	var verr *schemas.ValidationError
+	if err := schemas.Validate(schemas.Config, data); errors.As(err, &verr) {
+		for _, v := range verr.Violations {
+			// Unknown keys are reported above, with a suggestion.
+			if v.Message == "unexpected property" {
+				continue
+			}
+			path := strings.TrimPrefix(strings.TrimPrefix(v.Path, "$"), ".")
+			d := Diagnostic{Source: source, Path: path, Message: v.Message}
+			if n := locate(root, path); n != nil {
+				d.Line, d.Column = n.Line, n.Column
+			}
+			diags = append(diags, d)
+		}
+	} else if err != nil {
+		return append(diags, Diagnostic{Source: source, Message: err.Error()})
+	}
+	if len(diags) > 0 {
+		return diags
+	}
+
+	// The schema cannot express every decoding rule; report the rest.
+	var cfg Config
+	var terr *yaml.TypeError
+	if err := root.Decode(&cfg); errors.As(err, &terr) {
+		for _, e := range terr.Errors {
+			d := Diagnostic{Source: source, Message: e}
+			if m := yamlLine.FindStringSubmatch(e); m != nil {
+				d.Line, _ = strconv.Atoi(m[1])
+				d.Column = 1
+				d.Message = strings.TrimPrefix(e, m[0]+": ")
+			}
+			diags = append(diags, d)
+		}
+	} else if err != nil {
+		diags = append(diags, Diagnostic{Source: source, Line: root.Line, Column: root.Column, Message: strings.TrimPrefix(err.Error(), "yaml: ")})
+	}
+	return diags
+}
+
+// unknownKeys walks n along type t and reports the mapping keys that are
+// not fields of a struct. Top-level profiles are walked as configs.
+func unknownKeys(source string, n *yaml.Node, t reflect.Type, path []string) []Diagnostic {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var diags []Diagnostic
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			p := append(append([]string(nil), path...), key.Value)
+			switch {
+			case t.Kind() == reflect.Map:
+				diags = append(diags, unknownKeys(source, val, t.Elem(), p)...)
+			case t.Kind() != reflect.Struct:
+			case len(path) == 0 && key.Value == profilesKey && t == reflect.TypeOf(Config{}):
+				for j := 0; val.Kind == yaml.MappingNode && j+1 < len(val.Content); j += 2 {
+					diags = append(diags, unknownKeys(source, val.Content[j+1], t, append(p, val.Content[j].Value))...)
+				}
+			default:
+				field, ok := yamlField(t, key.Value)
+				if !ok {
+					msg := "unknown key"
+					if s := suggest(key.Value, yamlFields(t)); s != "" {
+						msg += fmt.Sprintf(" (did you mean %q?)", s)
+					}
+					diags = append(diags, Diagnostic{Source: source, Line: key.Line, Column: key.Column, Path: strings.Join(p, "."), Message: msg})
+					continue
+				}
+				diags = append(diags, unknownKeys(source, val, field.Type, p)...)
+			}
+		}
+	case yaml.SequenceNode:
+		if t.Kind() == reflect.Slice {
+			for i, item := range n.Content {
+				p := append([]string(nil), path...)
+				if len(p) > 0 {
+					p[len(p)-1] += "[" + strconv.Itoa(i) + "]"
+				}
+				diags = append(diags, unknownKeys(source, item, t.Elem(), p)...)
+			}
+		}
+	}
+	return diags
+}
+
+// yamlFields returns the yaml keys of struct type t.
+func yamlFields(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// yamlField returns the field of struct type t with yaml key name.
+func yamlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if key, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); key == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// locate returns the node at a schema violation path ("skills.timeout.x",
+// "deps.deny[0].module"). Keys may contain dots, so each step takes the key
+// of the mapping the rest of the path starts with.
+func locate(n *yaml.Node, path string) *yaml.Node {
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			i, err := strconv.Atoi(path[1:max(end, 1)])
+			if end < 0 || err != nil || n.Kind != yaml.SequenceNode || i >= len(n.Content) {
+				return nil
+			}
+			n, path = n.Content[i], path[end+1:]
+		case n.Kind == yaml.MappingNode:
+			path = strings.TrimPrefix(path, ".")
+			var next *yaml.Node
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k := n.Content[i].Value
+				rest, ok := strings.CutPrefix(path, k)
+				if ok && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+					next, path = n.Content[i+1], rest
+					break
+				}
+			}
+			if next == nil {
+				return nil
+			}
+			n = next
+		default:
+			return nil
+		}
+	}
+	return n
+}
+
+// suggest returns the candidate closest to key by edit distance, if it is
+// close enough to be a typo.
+func suggest(key string, candidates []string) string {
+	best, bestDist := "", len(key)/2+1
+	sort.Strings(candidates)
+	for _, c := range candidates {
+		if d := distance(strings.ToLower(key), c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance of a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
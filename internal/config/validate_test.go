@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: CLI_COMMAND_CONFIG
+// Spec: spec/cli/config.md
+
+func TestValidate(t *testing.T) {
+	root := writeConfig(t, `skills:
+  timout:
+    "*": 10m
+  retry:
+    test:go: {count: -1}
+  timeout:
+    test:go: 10x
+deps:
+  deny:
+    - module: example.com/x
+      reasn: old
+sync:
+  remote: ftp://host
+profiles:
+  ci:
+    sink: {}
+`)
+
+	diags, err := Validate(root, Options{Profile: "nope", Environ: []string{
+		"CORTEX_CONFIG_FILES__MAX_BYTS=1",
+		"CORTEX_CONFIG_FILES__MAX_BYTES=big",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{Source: File, Line: 2, Column: 3, Path: "skills.timout", Message: `unknown key (did you mean "timeout"?)`},
+		{Source: File, Line: 5, Column: 22, Path: "skills.retry.test:go.count", Message: "value -1 is less than minimum 0"},
+		{Source: File, Line: 7, Column: 14, Path: "skills.timeout.test:go", Message: `value "10x" does not match pattern "^(0|([0-9]+(\\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$"`},
+		{Source: File, Line: 11, Column: 7, Path: "deps.deny[0].reasn", Message: `unknown key (did you mean "reason"?)`},
+		{Source: File, Line: 13, Column: 11, Path: "sync.remote", Message: `value "ftp://host" does not match pattern "^((s3|gs|file)://.+)?$"`},
+		{Source: File, Line: 16, Column: 5, Path: "profiles.ci.sink", Message: `unknown key (did you mean "sync"?)`},
+		{Source: "profile", Message: `unknown profile "nope" (defined: ci)`},
+		{Source: "CORTEX_CONFIG_FILES__MAX_BYTES", Path: "files.max_bytes", Message: "expected integer, got string"},
+		{Source: "CORTEX_CONFIG_FILES__MAX_BYTS", Path: "files.max_byts", Message: `unknown key (did you mean "max_bytes"?)`},
+	}, diags)
+}
+
+func TestValidate_Valid(t *testing.T) {
+	diags, err := Validate(t.TempDir(), Options{})
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+
+	root := writeConfig(t, "skills:\n  timeout:\n    \"*\": 10m\n    lint:golangci: \"0\"\nprofiles:\n  ci:\n    files:\n      max_bytes: -1\n")
+	diags, err = Validate(root, Options{Profile: "ci"})
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+}
+
+func TestValidate_Rules(t *testing.T) {
+	root := writeConfig(t, "rules:\n  purity: off\n  test:go: never\n")
+	diags, err := Validate(root, Options{})
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "rules.test:go", diags[0].Path)
+	assert.Equal(t, 3, diags[0].Line)
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	root := writeConfig(t, "skills:\n  timeout: [\n")
+	diags, err := Validate(root, Options{})
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, Diagnostic{Source: File, Line: 2, Column: 1, Message: "did not find expected node content"}, diags[0])
+}
+
+func TestSuggest(t *testing.T) {
+	keys := []string{"retry", "timeout", "limits"}
+	assert.Equal(t, "timeout", suggest("timout", keys))
+	assert.Equal(t, "limits", suggest("Limit", keys))
+	assert.Equal(t, "", suggest("something", keys))
+}
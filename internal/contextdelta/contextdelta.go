@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package contextdelta computes how the context changed since a baseline,
+// a git revision or a snapshot: the chunks to add or update, the chunk IDs
+// to drop and the declarations that changed. MCP clients apply the delta to
+// their copy of .cortex/data instead of ingesting it again.
+//
+// The baseline side is chunked from the baseline files with the rules of
+// the current build; the current side is .cortex/data as built.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package contextdelta
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/snapshot"
+	"github.com/bartekus/cortex/internal/symbols"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// SchemaVersion is the schema_version of a Delta.
+const SchemaVersion = "1.0"
+
+// Changes of files and symbols.
+const (
+	Added    = "added"
+	Modified = "modified"
+	Removed  = "removed"
+)
+
+// Baseline is the state a delta starts from.
+type Baseline interface {
+	// Digests returns the "sha256:<hex>" content digest of every baseline
+	// file, keyed by repo-relative path.
+	Digests(ctx context.Context) (map[string]string, error)
+	// Read returns the content of a baseline file.
+	Read(ctx context.Context, path string) ([]byte, error)
+}
+
+// Revision is git revision Rev of the repository at Root.
+type Revision struct {
+	Root string
+	Rev  string
+}
+
+// Digests hashes the files of the revision.
+func (r Revision) Digests(ctx context.Context) (map[string]string, error) {
+	return git.TreeDigests(ctx, r.Root, r.Rev)
+}
+
+// Read reads a file of the revision.
+func (r Revision) Read(ctx context.Context, path string) ([]byte, error) {
+	return git.ShowFile(ctx, r.Root, r.Rev, path)
+}
+
+// Snapshot is snapshot ID of Store. Blob hashes are content digests, so
+// only changed files are read.
+type Snapshot struct {
+	Store snapshot.Store
+	ID    string
+}
+
+// Digests lists the blobs of the snapshot.
+func (s Snapshot) Digests(ctx context.Context) (map[string]string, error) {
+	entries, err := s.Store.Entries(ctx, s.ID)
+	if err != nil {
+		return nil, err
+	}
+	digests := make(map[string]string, len(entries))
+	for _, e := range entries {
+		digests[e.Path] = e.Blob
+	}
+	return digests, nil
+}
+
+// Read reads a file from the snapshot.
+func (s Snapshot) Read(ctx context.Context, path string) ([]byte, error) {
+	return s.Store.Blob(ctx, s.ID, path)
+}
+
+// Delta is the change of the context since a baseline. Applying it to the
+// chunks of the baseline yields the chunks of the current context.
+type Delta struct {
+	SchemaVersion string `json:"schema_version"`
+	// Since is the baseline as given: a git revision or a snapshot ID.
+	Since string `json:"since"`
+	// Base is the resolved commit or the snapshot ID.
+	Base string `json:"base"`
+	// ContextDigest is the content of .cortex/digest.txt the delta leads to.
+	ContextDigest string `json:"context_digest"`
+	// Files are the changed files, sorted by path.
+	Files []FileChange `json:"files"`
+	// ChangedChunks are the chunks that are new or whose lines moved,
+	// ordered by path, then start line.
+	ChangedChunks []chunker.Chunk `json:"changed_chunks"`
+	// RemovedChunks are the IDs of the baseline chunks that are gone,
+	// sorted.
+	RemovedChunks []string `json:"removed_chunks"`
+	// Symbols are the changed Go declarations, sorted by file and symbol.
+	Symbols []SymbolChange `json:"symbols"`
+}
+
+// FileChange is a file whose content changed since the baseline.
+type FileChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+}
+
+// SymbolChange is a Go declaration that was added, modified or removed.
+type SymbolChange struct {
+	File string `json:"file"`
+	// Symbol is the chunk symbol: "Name", "Receiver.Name" for methods, or
+	// the names of a grouped declaration joined by ", ".
+	Symbol string `json:"symbol"`
+	Kind   string `json:"kind"`
+	Change string `json:"change"`
+	// IDs are the symbols.json IDs of the declaration; none for removed
+	// declarations.
+	IDs []string `json:"ids,omitempty"`
+}
+
+// Compute compares the files of base with the current context: its
+// chunking result and symbol graph (nil without one). Baseline files the
+// scan ignores are left out; policy chunks the baseline files the way the
+// build did.
+func Compute(ctx context.Context, base Baseline, current *chunker.Result, graph *symbols.Graph, policy scanner.Policy) (*Delta, error) {
+	digests, err := base.Digests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	old := make(map[string]string, len(digests))
+	for path, digest := range digests {
+		if !xray.Ignored(path) {
+			old[path] = digest
+		}
+	}
+	entries := make(map[string]chunker.ManifestEntry, len(current.Manifest))
+	for _, e := range current.Manifest {
+		entries[e.Path] = e
+	}
+	chunks := make(map[string][]chunker.Chunk)
+	for _, c := range current.Chunks {
+		chunks[c.FilePath] = append(chunks[c.FilePath], c)
+	}
+
+	paths := make([]string, 0, len(old)+len(entries))
+	for path := range old {
+		paths = append(paths, path)
+	}
+	for path := range entries {
+		if _, ok := old[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	d := &Delta{
+		SchemaVersion: SchemaVersion,
+		Files:         []FileChange{},
+		ChangedChunks: []chunker.Chunk{},
+		RemovedChunks: []string{},
+		Symbols:       []SymbolChange{},
+	}
+	for _, path := range paths {
+		digest, inOld := old[path]
+		entry, inCurrent := entries[path]
+		var oldChunks []chunker.Chunk
+		change := Modified
+		switch {
+		case inOld && inCurrent && digest == entry.Hash:
+			continue
+		case !inOld:
+			change = Added
+		default:
+			content, err := base.Read(ctx, path)
+			if err != nil {
+				return nil, err
+			}
+			_, oldChunks = chunker.SplitFile(path, content, policy)
+			if !inCurrent {
+				change = Removed
+			}
+		}
+		d.Files = append(d.Files, FileChange{Path: path, Change: change})
+
+		newChunks := chunks[path]
+		before := make(map[string]chunker.Chunk, len(oldChunks))
+		for _, c := range oldChunks {
+			before[c.ID] = c
+		}
+		kept := make(map[string]bool, len(newChunks))
+		for _, c := range newChunks {
+			kept[c.ID] = true
+			if o, ok := before[c.ID]; !ok || o != c {
+				d.ChangedChunks = append(d.ChangedChunks, c)
+			}
+		}
+		for _, c := range oldChunks {
+			if !kept[c.ID] {
+				d.RemovedChunks = append(d.RemovedChunks, c.ID)
+			}
+		}
+		d.Symbols = append(d.Symbols, symbolChanges(path, oldChunks, newChunks, graph)...)
+	}
+	sort.Strings(d.RemovedChunks)
+	return d, nil
+}
+
+// declaration is a Go declaration of a file as its chunks show it.
+type declaration struct {
+	kind    string
+	content string // content hashes of its chunks
+}
+
+// declarations returns the Go declarations among chunks by symbol.
+func declarations(chunks []chunker.Chunk) map[string]declaration {
+	decls := make(map[string]declaration)
+	for _, c := range chunks {
+		switch c.Kind {
+		case chunker.KindFunc, chunker.KindMethod, chunker.KindType, chunker.KindVar, chunker.KindConst:
+		default:
+			continue
+		}
+		d := decls[c.Symbol]
+		d.kind = c.Kind
+		// Long declarations span several windows.
+		d.content += c.ContentHash + "\n"
+		decls[c.Symbol] = d
+	}
+	return decls
+}
+
+// symbolChanges compares the declarations of a file, sorted by symbol.
+func symbolChanges(path string, oldChunks, newChunks []chunker.Chunk, graph *symbols.Graph) []SymbolChange {
+	before, after := declarations(oldChunks), declarations(newChunks)
+	var changes []SymbolChange
+	for symbol, d := range after {
+		o, ok := before[symbol]
+		change := Added
+		switch {
+		case ok && o.content == d.content:
+			continue
+		case ok:
+			change = Modified
+		}
+		changes = append(changes, SymbolChange{File: path, Symbol: symbol, Kind: d.kind, Change: change, IDs: symbolIDs(graph, path, symbol)})
+	}
+	for symbol, o := range before {
+		if _, ok := after[symbol]; !ok {
+			changes = append(changes, SymbolChange{File: path, Symbol: symbol, Kind: o.kind, Change: Removed})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Symbol < changes[j].Symbol })
+	return changes
+}
+
+// symbolIDs returns the IDs of the symbols of graph declared by symbol in
+// file, sorted.
+func symbolIDs(graph *symbols.Graph, file, symbol string) []string {
+	if graph == nil {
+		return nil
+	}
+	names := make(map[string]bool)
+	for _, name := range strings.Split(symbol, ", ") {
+		names[name] = true
+	}
+	var ids []string
+	for _, s := range graph.Symbols {
+		name := s.Name
+		if s.Receiver != "" {
+			name = s.Receiver + "." + s.Name
+		}
+		if s.File == file && names[name] {
+			ids = append(ids, s.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,93 @@
+package contextdelta
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/symbols"
+)
+
+// files is a Baseline of file contents by path.
+type files map[string]string
+
+func (f files) Digests(context.Context) (map[string]string, error) {
+	out := make(map[string]string, len(f))
+	for path, content := range f {
+		sum := sha256.Sum256([]byte(content))
+		out[path] = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return out, nil
+}
+
+func (f files) Read(_ context.Context, path string) ([]byte, error) {
+	return []byte(f[path]), nil
+}
+
+// build chunks f the way a context build does.
+func build(f files) *chunker.Result {
+	res := &chunker.Result{}
+	for _, path := range []string{"a.go", "b.md", "c.txt", "node_modules/x.js"} {
+		content, ok := f[path]
+		if !ok || path == "node_modules/x.js" {
+			continue
+		}
+		entry, chunks := chunker.SplitFile(path, []byte(content), scanner.Policy{})
+		res.Manifest = append(res.Manifest, entry)
+		res.Chunks = append(res.Chunks, chunks...)
+	}
+	return res
+}
+
+func TestCompute(t *testing.T) {
+	before := files{
+		"a.go":              "package a\n\nfunc A() {}\n\nfunc B() {}\n",
+		"c.txt":             "gone\n",
+		"node_modules/x.js": "ignored\n",
+	}
+	after := files{
+		"a.go": "package a\n\n// A is new.\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n",
+		"b.md": "# B\n",
+	}
+	graph := &symbols.Graph{Symbols: []symbols.Symbol{{ID: "x/a.A", Name: "A", File: "a.go"}, {ID: "x/a.C", Name: "C", File: "a.go"}}}
+
+	d, err := Compute(context.Background(), before, build(after), graph, scanner.Policy{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []FileChange{{"a.go", Modified}, {"b.md", Added}, {"c.txt", Removed}}, d.Files, "ignored paths are left out")
+
+	var changed []string
+	for _, c := range d.ChangedChunks {
+		changed = append(changed, c.FilePath+":"+c.Symbol)
+	}
+	assert.Equal(t, []string{"a.go:A", "a.go:B", "a.go:C", "b.md:B"}, changed, "B is unchanged but moved")
+
+	removed := map[string]bool{}
+	for _, c := range build(before).Chunks {
+		removed[c.ID] = true
+	}
+	for _, c := range build(after).Chunks {
+		delete(removed, c.ID)
+	}
+	assert.Len(t, d.RemovedChunks, len(removed))
+	for _, id := range d.RemovedChunks {
+		assert.True(t, removed[id], id)
+	}
+
+	assert.Equal(t, []SymbolChange{
+		{File: "a.go", Symbol: "A", Kind: "func", Change: Modified, IDs: []string{"x/a.A"}},
+		{File: "a.go", Symbol: "C", Kind: "func", Change: Added, IDs: []string{"x/a.C"}},
+	}, d.Symbols)
+
+	same, err := Compute(context.Background(), after, build(after), graph, scanner.Policy{})
+	require.NoError(t, err)
+	assert.Empty(t, same.Files)
+	assert.Empty(t, same.ChangedChunks)
+	assert.Empty(t, same.RemovedChunks)
+}
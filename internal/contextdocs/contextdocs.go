@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package contextdocs projects the context pipeline outputs (XRAY index,
+// files manifest and chunks) into deterministic Markdown documentation.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package contextdocs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/depgraph"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/site"
+	"github.com/bartekus/cortex/internal/templates"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// Paths locates the pipeline artifacts consumed by the generator.
+type Paths struct {
+	// RepoRoot is used to read module files for dependency pages.
+	RepoRoot string
+	// Index is the XRAY index.json (required).
+	Index string
+	// Manifest is the files manifest.json written by `cortex context build` (optional).
+	Manifest string
+	// Chunks is the chunks.ndjson written by `cortex context build` (optional).
+	Chunks string
+	// Dependencies is the dependencies.json written by `cortex context build` (optional).
+	Dependencies string
+}
+
+// DefaultPaths returns the standard artifact locations under repoRoot/.cortex.
+func DefaultPaths(repoRoot string) Paths {
+	return Paths{
+		RepoRoot: repoRoot,
+		Index:    filepath.Join(repoRoot, ".cortex", "data", "index.json"),
+		Manifest: filepath.Join(repoRoot, ".cortex", "data", "manifest.json"),
+		Chunks:   filepath.Join(repoRoot, ".cortex", "data", "chunks.ndjson"),
+
+		Dependencies: filepath.Join(repoRoot, ".cortex", "data", "dependencies.json"),
+	}
+}
+
+// Inputs is everything the renderer needs, already loaded and validated.
+type Inputs struct {
+	Index    *xray.Index
+	Manifest []chunker.ManifestEntry
+	// HasContext is false when manifest/chunks were not built yet.
+	HasContext bool
+	// ChunkCounts maps a file path to the number of chunks emitted for it.
+	ChunkCounts map[string]int
+	Modules     []Module
+	// Graph is the Go dependency graph, nil when not built yet.
+	Graph *depgraph.Graph
+}
+
+// Load reads and validates all inputs.
+// A missing index is an error; a missing manifest or chunks file only drops
+// the chunk information from the rendered docs.
+func Load(p Paths) (*Inputs, error) {
+	data, err := os.ReadFile(p.Index)
+	if err != nil {
+		return nil, fmt.Errorf("reading xray index %s (run `cortex context build` first): %w", p.Index, err)
+	}
+	var index xray.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing xray index: %w", err)
+	}
+	if err := xray.ValidateInvariants(&index); err != nil {
+		return nil, fmt.Errorf("refusing to document invalid index: %w", err)
+	}
+
+	in := &Inputs{Index: &index, ChunkCounts: map[string]int{}}
+
+	manifest, err := loadManifest(p.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := loadChunkCounts(p.Chunks)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil && counts != nil {
+		in.HasContext = true
+		in.Manifest = manifest
+		in.ChunkCounts = counts
+	}
+
+	for _, mf := range index.ModuleFiles {
+		m := Module{Path: mf, Kind: moduleKind(mf)}
+		if m.Kind != "" {
+			content, err := os.ReadFile(filepath.Join(p.RepoRoot, filepath.FromSlash(mf)))
+			if err != nil {
+				return nil, fmt.Errorf("reading module file %s: %w", mf, err)
+			}
+			deps, err := parseDependencies(m.Kind, content)
+			if err != nil {
+				return nil, fmt.Errorf("parsing module file %s: %w", mf, err)
+			}
+			m.Dependencies = deps
+		}
+		in.Modules = append(in.Modules, m)
+	}
+
+	graph, err := loadGraph(p.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	in.Graph = graph
+
+	return in, nil
+}
+
+func loadGraph(path string) (*depgraph.Graph, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading dependency graph: %w", err)
+	}
+	var g depgraph.Graph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("parsing dependency graph: %w", err)
+	}
+	return &g, nil
+}
+
+func loadManifest(path string) ([]chunker.ManifestEntry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var manifest []chunker.ManifestEntry
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if manifest == nil {
+		manifest = []chunker.ManifestEntry{}
+	}
+	return manifest, nil
+}
+
+func loadChunkCounts(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading chunks: %w", err)
+	}
+
+	counts := map[string]int{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var c chunker.Chunk
+		if err := json.Unmarshal(sc.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("parsing chunks.ndjson line %d: %w", line, err)
+		}
+		counts[c.FilePath]++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading chunks: %w", err)
+	}
+	return counts, nil
+}
+
+// Page file names produced by Render.
+const (
+	PageIndex        = "index.md"
+	PageFiles        = "files.md"
+	PageModules      = "modules.md"
+	PageDependencies = "dependencies.md"
+
+	PageModuleDependencies = "module-dependencies.md"
+)
+
+// Pages are the file names of the doc set, sorted.
+var Pages = []string{PageDependencies, PageFiles, PageIndex, PageModuleDependencies, PageModules}
+
+// SitePages returns the file names GenerateSite writes for Pages.
+func SitePages() []string {
+	out := make([]string, len(Pages))
+	for i, name := range Pages {
+		out[i] = strings.TrimSuffix(name, ".md") + ".html"
+	}
+	return out
+}
+
+// Render produces the full doc set as file name -> content with the
+// embedded default templates.
+// Output depends only on the inputs: maps are rendered in sorted key order,
+// lists in index order, and no timestamps or absolute paths are emitted.
+func Render(in *Inputs) map[string]string {
+	pages, err := RenderWith(templates.New(""), in)
+	if err != nil {
+		panic(err)
+	}
+	return pages
+}
+
+// RenderWith produces the full doc set with the templates of set,
+// which may be overridden by the repository.
+func RenderWith(set *templates.Set, in *Inputs) (map[string]string, error) {
+	v := newView(in)
+	pages := map[string]string{}
+	for page, name := range map[string]string{
+		PageIndex:        templates.ContextIndex,
+		PageFiles:        templates.ContextFiles,
+		PageModules:      templates.ContextModules,
+		PageDependencies: templates.ContextDependencies,
+
+		PageModuleDependencies: templates.ContextModuleDependencies,
+	} {
+		out, err := set.Render(name, v)
+		if err != nil {
+			return nil, err
+		}
+		pages[page] = out
+	}
+	return pages, nil
+}
+
+// Generate loads the inputs and writes every page into outDir atomically,
+// rendered with the templates of p.RepoRoot.
+func Generate(p Paths, outDir string) error {
+	in, err := Load(p)
+	if err != nil {
+		return err
+	}
+
+	pages, err := RenderWith(templates.New(p.RepoRoot), in)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := projection.AtomicWrite(filepath.Join(outDir, name), []byte(pages[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateSite loads the inputs and writes every page as HTML into siteDir
+// (see package site). The file inventory page gets the search box.
+func GenerateSite(p Paths, siteDir string) error {
+	in, err := Load(p)
+	if err != nil {
+		return err
+	}
+
+	set := templates.New(p.RepoRoot)
+	pages, err := RenderWith(set, in)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]site.Page, 0, len(names))
+	for _, name := range names {
+		out = append(out, site.Page{
+			File:     strings.TrimSuffix(name, ".md") + ".html",
+			Markdown: pages[name],
+			Search:   name == PageFiles,
+		})
+	}
+	return site.Write(set, siteDir, out)
+}
+
+// view is the data of the context doc templates.
+type view struct {
+	*Inputs
+	// Chunks is the total number of chunks.
+	Chunks int
+	// Declared are the module files with a recognized kind.
+	Declared []Module
+	// Imports is the Go import graph, nil when not built yet.
+	Imports *graphView
+}
+
+func newView(in *Inputs) view {
+	v := view{Inputs: in, Imports: newGraphView(in.Graph)}
+	for _, n := range in.ChunkCounts {
+		v.Chunks += n
+	}
+	for _, m := range in.Modules {
+		if m.Kind != "" {
+			v.Declared = append(v.Declared, m)
+		}
+	}
+	return v
+}
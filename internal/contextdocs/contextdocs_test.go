@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package contextdocs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIndex = `{
+  "schemaVersion": "1.0.0",
+  "root": "repo",
+  "target": ".",
+  "stats": {"fileCount": 3, "totalSize": 120},
+  "files": [
+    {"path": "Cargo.toml", "size": 40, "hash": "sha256:a", "lang": "TOML", "loc": 4, "complexity": 0},
+    {"path": "go.mod", "size": 50, "hash": "sha256:b", "lang": "Go Module", "loc": 8, "complexity": 0},
+    {"path": "src/a|b.go", "size": 30, "hash": "sha256:c", "lang": "Go", "loc": 3, "complexity": 0}
+  ],
+  "languages": {"TOML": 1, "Go": 1, "Go Module": 1},
+  "topDirs": {"src": 1, ".": 2},
+  "moduleFiles": ["Cargo.toml", "Makefile", "go.mod"],
+  "digest": "abc"
+}`
+
+const testGoMod = `module example.com/repo
+
+go 1.24
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+`
+
+const testCargo = `[package]
+name = "repo"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+anyhow = "1"
+local = { path = "../local" }
+
+[dev-dependencies]
+tempfile = "3"
+`
+
+func writeTestRepo(t *testing.T, withContext bool) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		".cortex/data/index.json": testIndex,
+		"go.mod":                  testGoMod,
+		"Cargo.toml":              testCargo,
+		"Makefile":                "all:\n",
+	}
+	if withContext {
+		files[".cortex/data/manifest.json"] = `[{"path":"Cargo.toml","hash":"a"},{"path":"go.mod","hash":"b"}]`
+		files[".cortex/data/chunks.ndjson"] = `{"file_path":"go.mod","start_line":1,"end_line":8,"content":"x"}` + "\n" +
+			`{"file_path":"Cargo.toml","start_line":1,"end_line":4,"content":"y"}` + "\n"
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+func TestRender(t *testing.T) {
+	root := writeTestRepo(t, true)
+
+	in, err := Load(DefaultPaths(root))
+	require.NoError(t, err)
+	pages := Render(in)
+
+	assert.Equal(t, `# Context Index
+
+## Summary
+
+- **Root**: `+"`repo`"+`
+- **Target**: `+"`.`"+`
+- **Digest**: `+"`abc`"+`
+- **Files**: 3
+- **Total Size**: 120 bytes
+- **Manifest Entries**: 2
+- **Chunks**: 2
+
+## Languages
+
+| Language | Files |
+| --- | --- |
+| Go | 1 |
+| Go Module | 1 |
+| TOML | 1 |
+
+## Top Directories
+
+| Directory | Files |
+| --- | --- |
+| . | 2 |
+| src | 1 |
+
+## Pages
+
+- [Files](files.md)
+- [Modules](modules.md)
+- [Dependencies](dependencies.md)
+- [Module Dependencies](module-dependencies.md)
+`, pages[PageIndex])
+
+	assert.Equal(t, `# File Inventory
+
+| Path | Size | Language | LOC | Chunks |
+| --- | --- | --- | --- | --- |
+| Cargo.toml | 40 | TOML | 4 | 1 |
+| go.mod | 50 | Go Module | 8 | 1 |
+| src/a\|b.go | 30 | Go | 3 | 0 |
+`, pages[PageFiles])
+
+	assert.Equal(t, "# Module Files\n\nKey configuration files defining modules or dependencies.\n\n"+
+		"- `Cargo.toml` — cargo, 4 dependencies ([details](dependencies.md#cargotoml))\n"+
+		"- `Makefile`\n"+
+		"- `go.mod` — go, 3 dependencies ([details](dependencies.md#gomod))\n", pages[PageModules])
+
+	assert.Equal(t, `# Dependencies
+
+Dependencies declared by module files at the repository root.
+
+## Cargo.toml
+
+| Name | Version | Scope |
+| --- | --- | --- |
+| anyhow | 1 | dependencies |
+| local | path:../local | dependencies |
+| serde | 1.0 | dependencies |
+| tempfile | 3 | dev-dependencies |
+
+## go.mod
+
+| Name | Version | Scope |
+| --- | --- | --- |
+| gopkg.in/yaml.v3 | v3.0.1 | indirect |
+| github.com/spf13/cobra | v1.8.0 | require |
+| github.com/stretchr/testify | v1.9.0 | require |
+
+`, pages[PageDependencies])
+}
+
+func TestRender_WithoutContext(t *testing.T) {
+	root := writeTestRepo(t, false)
+
+	in, err := Load(DefaultPaths(root))
+	require.NoError(t, err)
+	pages := Render(in)
+
+	assert.NotContains(t, pages[PageIndex], "Chunks")
+	assert.Contains(t, pages[PageModuleDependencies], "No dependency graph found")
+	assert.Contains(t, pages[PageFiles], "| Path | Size | Language | LOC |\n")
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	root := writeTestRepo(t, true)
+	out1 := filepath.Join(t.TempDir(), "a")
+	out2 := filepath.Join(t.TempDir(), "b")
+
+	require.NoError(t, Generate(DefaultPaths(root), out1))
+	require.NoError(t, Generate(DefaultPaths(root), out2))
+
+	for _, name := range []string{PageIndex, PageFiles, PageModules, PageDependencies, PageModuleDependencies} {
+		a, err := os.ReadFile(filepath.Join(out1, name))
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(out2, name))
+		require.NoError(t, err)
+		assert.Equal(t, string(a), string(b), name)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(DefaultPaths(t.TempDir()))
+	assert.ErrorContains(t, err, "cortex context build")
+
+	root := writeTestRepo(t, false)
+	unsorted := `{"files":[{"path":"b"},{"path":"a"}],"moduleFiles":[]}`
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".cortex", "data", "index.json"), []byte(unsorted), 0o644))
+	_, err = Load(DefaultPaths(root))
+	assert.ErrorContains(t, err, "not sorted")
+}
+
+func TestRender_ModuleDependencies(t *testing.T) {
+	root := writeTestRepo(t, false)
+	graph := `{
+  "schema_version": "1.0",
+  "modules": [{"path": "example.com/repo", "dir": ".", "go": "1.24", "requires": [
+    {"path": "github.com/spf13/cobra", "version": "v1.8.0"},
+    {"path": "gopkg.in/yaml.v3", "version": "v3.0.1", "indirect": true}
+  ]}],
+  "packages": [
+    {"path": "example.com/repo/cmd", "dir": "cmd", "module": "example.com/repo", "imports": ["example.com/repo/lib", "fmt", "github.com/spf13/cobra"]},
+    {"path": "example.com/repo/lib", "dir": "lib", "module": "example.com/repo", "imports": []}
+  ],
+  "edges": [
+    {"from": "example.com/repo/cmd", "to": "example.com/repo/lib", "kind": "internal"},
+    {"from": "example.com/repo/cmd", "to": "fmt", "kind": "stdlib"},
+    {"from": "example.com/repo/cmd", "to": "github.com/spf13/cobra", "kind": "external", "module": "github.com/spf13/cobra"}
+  ],
+  "digest": "d1"
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".cortex", "data", "dependencies.json"), []byte(graph), 0o644))
+
+	in, err := Load(DefaultPaths(root))
+	require.NoError(t, err)
+
+	assert.Equal(t, `# Module Dependencies
+
+Go import graph for 2 packages in 1 modules (digest `+"`d1`"+`).
+
+## Modules
+
+### `+"`example.com/repo`"+`
+
+- **Directory**: `+"`.`"+`
+- **Go**: 1.24
+
+| Requirement | Version | Indirect | Used By |
+| --- | --- | --- | --- |
+| github.com/spf13/cobra | v1.8.0 |  | 1 |
+| gopkg.in/yaml.v3 | v3.0.1 | yes | 0 |
+
+## Packages
+
+| Package | Imports | Imported By | External Modules |
+| --- | --- | --- | --- |
+| `+"`example.com/repo/cmd`"+` | lib | 0 | github.com/spf13/cobra |
+| `+"`example.com/repo/lib`"+` |  | 1 |  |
+`, Render(in)[PageModuleDependencies])
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package contextdocs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/gomod"
+)
+
+// Module kinds with dependency parsers.
+const (
+	KindGo    = "go"
+	KindCargo = "cargo"
+	KindNPM   = "npm"
+)
+
+// Module is a module file from the index together with its declared dependencies.
+// Kind is empty for module files without dependency declarations (Makefile, Dockerfile, .git).
+type Module struct {
+	Path         string
+	Kind         string
+	Dependencies []Dependency
+}
+
+// Dependency is a single declared dependency.
+type Dependency struct {
+	Name    string
+	Version string
+	// Scope is the section the dependency was declared in, e.g. "require",
+	// "indirect", "dependencies" or "dev-dependencies".
+	Scope string
+}
+
+func moduleKind(path string) string {
+	switch path {
+	case "go.mod":
+		return KindGo
+	case "Cargo.toml":
+		return KindCargo
+	case "package.json":
+		return KindNPM
+	default:
+		return ""
+	}
+}
+
+func parseDependencies(kind string, content []byte) ([]Dependency, error) {
+	var (
+		deps []Dependency
+		err  error
+	)
+	switch kind {
+	case KindGo:
+		deps = parseGoMod(content)
+	case KindCargo:
+		deps = parseCargoToml(content)
+	case KindNPM:
+		deps, err = parsePackageJSON(content)
+	default:
+		return nil, fmt.Errorf("unknown module kind: %s", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sortDependencies(deps)
+	return deps, nil
+}
+
+func sortDependencies(deps []Dependency) {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Scope != deps[j].Scope {
+			return deps[i].Scope < deps[j].Scope
+		}
+		return deps[i].Name < deps[j].Name
+	})
+}
+
+// parseGoMod extracts require directives, both single-line and block form.
+func parseGoMod(content []byte) []Dependency {
+	var deps []Dependency
+	for _, r := range gomod.Parse(content).Require {
+		scope := "require"
+		if r.Indirect {
+			scope = "indirect"
+		}
+		deps = append(deps, Dependency{Name: r.Path, Version: r.Version, Scope: scope})
+	}
+	return deps
+}
+
+// parseCargoToml extracts dependency tables. It understands the subset of TOML
+// used for dependency declarations: `name = "1.0"` and inline tables with a
+// version, path or workspace key.
+func parseCargoToml(content []byte) []Dependency {
+	var deps []Dependency
+	section := ""
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if !isCargoDependencySection(section) {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		name = strings.Trim(strings.TrimSpace(name), `"`)
+		deps = append(deps, Dependency{Name: name, Version: cargoVersion(strings.TrimSpace(value)), Scope: section})
+	}
+	return deps
+}
+
+func isCargoDependencySection(section string) bool {
+	switch section {
+	case "dependencies", "dev-dependencies", "build-dependencies", "workspace.dependencies":
+		return true
+	default:
+		return false
+	}
+}
+
+func cargoVersion(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		return strings.Trim(value, `"`)
+	}
+	if !strings.HasPrefix(value, "{") {
+		return value
+	}
+	for _, part := range strings.Split(strings.Trim(value, "{} "), ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		k, v = strings.TrimSpace(k), strings.Trim(strings.TrimSpace(v), `"`)
+		switch k {
+		case "version":
+			return v
+		case "path":
+			return "path:" + v
+		case "workspace":
+			if v == "true" {
+				return "workspace"
+			}
+		}
+	}
+	return ""
+}
+
+func parsePackageJSON(content []byte) ([]Dependency, error) {
+	var pkg map[string]json.RawMessage
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, scope := range []string{"dependencies", "devDependencies", "peerDependencies"} {
+		raw, ok := pkg[scope]
+		if !ok {
+			continue
+		}
+		var entries map[string]string
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		for name, version := range entries {
+			deps = append(deps, Dependency{Name: name, Version: version, Scope: scope})
+		}
+	}
+	return deps, nil
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package contextdocs
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/depgraph"
+)
+
+// graphView is the Go import graph as the module dependencies page shows it.
+type graphView struct {
+	Digest   string
+	Modules  []graphModule
+	Packages []graphPackage
+}
+
+type graphModule struct {
+	Path, Dir, Go string
+	Requires      []graphRequire
+}
+
+type graphRequire struct {
+	Path, Version string
+	Indirect      bool
+	// UsedBy counts the internal packages importing the module (non-test).
+	UsedBy int
+}
+
+type graphPackage struct {
+	Path string
+	// Imports are the internal imports, module-relative.
+	Imports    []string
+	ImportedBy int
+	// External are the external modules (or packages outside any module) imported.
+	External []string
+}
+
+// newGraphView summarizes the Go module requirements and the package import
+// graph from dependencies.json; nil when g is nil.
+func newGraphView(g *depgraph.Graph) *graphView {
+	if g == nil {
+		return nil
+	}
+	v := &graphView{Digest: g.Digest}
+
+	// Which internal packages use each required module (non-test imports).
+	usedBy := make(map[string]map[string]bool)
+	for _, e := range g.Edges {
+		if e.Kind == depgraph.KindExternal && e.Module != "" && !e.Test {
+			if usedBy[e.Module] == nil {
+				usedBy[e.Module] = make(map[string]bool)
+			}
+			usedBy[e.Module][e.From] = true
+		}
+	}
+
+	for _, m := range g.Modules {
+		gm := graphModule{Path: m.Path, Dir: m.Dir, Go: m.Go}
+		for _, r := range m.Requires {
+			gm.Requires = append(gm.Requires, graphRequire{Path: r.Path, Version: r.Version, Indirect: r.Indirect, UsedBy: len(usedBy[r.Path])})
+		}
+		v.Modules = append(v.Modules, gm)
+	}
+
+	importedBy := make(map[string]int)
+	imports := make(map[string][]string)
+	external := make(map[string]map[string]bool)
+	for _, e := range g.Edges {
+		if e.Test {
+			continue
+		}
+		switch e.Kind {
+		case depgraph.KindInternal:
+			importedBy[e.To]++
+			imports[e.From] = append(imports[e.From], e.To)
+		case depgraph.KindExternal:
+			if external[e.From] == nil {
+				external[e.From] = make(map[string]bool)
+			}
+			name := e.Module
+			if name == "" {
+				name = e.To
+			}
+			external[e.From][name] = true
+		}
+	}
+
+	for _, p := range g.Packages {
+		v.Packages = append(v.Packages, graphPackage{
+			Path:       p.Path,
+			Imports:    relativeTo(p.Module, imports[p.Path]),
+			ImportedBy: importedBy[p.Path],
+			External:   sortedSet(external[p.Path]),
+		})
+	}
+	return v
+}
+
+// relativeTo shortens import paths inside module to their module-relative form.
+func relativeTo(module string, paths []string) []string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if module != "" && strings.HasPrefix(p, module+"/") {
+			p = strings.TrimPrefix(p, module+"/")
+		}
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortedSet(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package conventional validates commit messages against the Conventional
+// Commits specification (https://www.conventionalcommits.org/en/v1.0.0/).
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// DefaultTypes are the commit types allowed when a policy lists none.
+var DefaultTypes = []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+
+// DefaultMaxHeaderLength is the header limit used when a policy sets none.
+const DefaultMaxHeaderLength = 72
+
+// Policy configures which headers are accepted.
+type Policy struct {
+	Types           []string
+	Scopes          []string // empty allows any scope
+	RequireScope    bool
+	MaxHeaderLength int
+}
+
+// Header is the parsed first line of a commit message.
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// ParseHeader parses a `type(scope)!: description` header.
+func ParseHeader(line string) (Header, error) {
+	m := headerPattern.FindStringSubmatch(line)
+	if m == nil {
+		return Header{}, fmt.Errorf("header does not match \"type(scope): description\"")
+	}
+	return Header{Type: m[1], Scope: m[2], Breaking: m[3] == "!", Description: m[4]}, nil
+}
+
+// Check validates message against p and returns one problem per violation,
+// in a stable order. A nil result means the message is valid.
+func Check(message string, p Policy) []string {
+	header, rest, hasBody := strings.Cut(strings.TrimRight(message, "\n"), "\n")
+
+	var problems []string
+	limit := p.MaxHeaderLength
+	if limit <= 0 {
+		limit = DefaultMaxHeaderLength
+	}
+	if n := utf8.RuneCountInString(header); n > limit {
+		problems = append(problems, fmt.Sprintf("header is %d characters, limit is %d", n, limit))
+	}
+	if hasBody && rest != "" && !strings.HasPrefix(rest, "\n") {
+		problems = append(problems, "body must be separated from the header by a blank line")
+	}
+
+	h, err := ParseHeader(header)
+	if err != nil {
+		return append(problems, err.Error())
+	}
+
+	types := p.Types
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+	if !slices.Contains(types, h.Type) {
+		problems = append(problems, fmt.Sprintf("type %q is not one of %s", h.Type, strings.Join(types, ", ")))
+	}
+
+	switch {
+	case h.Scope == "" && p.RequireScope:
+		problems = append(problems, "scope is required")
+	case h.Scope != "" && len(p.Scopes) > 0:
+		for _, scope := range strings.Split(h.Scope, ",") {
+			scope = strings.TrimSpace(scope)
+			if !slices.Contains(p.Scopes, scope) {
+				problems = append(problems, fmt.Sprintf("scope %q is not one of %s", scope, strings.Join(p.Scopes, ", ")))
+			}
+		}
+	}
+
+	if strings.TrimSpace(h.Description) == "" {
+		problems = append(problems, "description is empty")
+	} else if strings.HasSuffix(h.Description, ".") {
+		problems = append(problems, "description must not end with a period")
+	}
+
+	return problems
+}
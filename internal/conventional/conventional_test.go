@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package conventional
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeader(t *testing.T) {
+	h, err := ParseHeader("feat(cli,xray)!: add scan cache")
+	require.NoError(t, err)
+	assert.Equal(t, Header{Type: "feat", Scope: "cli,xray", Breaking: true, Description: "add scan cache"}, h)
+
+	h, err = ParseHeader("fix: handle empty index")
+	require.NoError(t, err)
+	assert.Equal(t, Header{Type: "fix", Description: "handle empty index"}, h)
+
+	for _, bad := range []string{"Fix the thing", "feat:missing space", "feat(a)(b): x", "(cli): no type"} {
+		_, err := ParseHeader(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	policy := Policy{Scopes: []string{"cli", "xray"}}
+
+	tests := []struct {
+		name    string
+		message string
+		policy  Policy
+		want    []string
+	}{
+		{name: "valid", message: "feat(cli): add status command\n\nLonger body.\n", policy: policy},
+		{name: "valid without scope", message: "docs: fix typo", policy: policy},
+		{name: "malformed", message: "Add status command", policy: policy, want: []string{`header does not match "type(scope): description"`}},
+		{name: "unknown type", message: "feature: add status", policy: Policy{Types: []string{"feat", "fix"}}, want: []string{`type "feature" is not one of feat, fix`}},
+		{name: "unknown scope", message: "fix(cli, api): handle flags", policy: policy, want: []string{`scope "api" is not one of cli, xray`}},
+		{name: "scope required", message: "fix: handle flags", policy: Policy{RequireScope: true}, want: []string{"scope is required"}},
+		{name: "period", message: "fix: handle flags.", want: []string{"description must not end with a period"}},
+		{name: "empty description", message: "fix(cli): ", want: []string{"description is empty"}},
+		{name: "no blank line", message: "fix: handle flags\nbody", want: []string{"body must be separated from the header by a blank line"}},
+		{
+			name:    "too long",
+			message: "fix: " + strings.Repeat("x", 20),
+			policy:  Policy{MaxHeaderLength: 10},
+			want:    []string{"header is 25 characters, limit is 10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Check(tt.message, tt.policy))
+		})
+	}
+}
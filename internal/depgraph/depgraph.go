@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package depgraph extracts the Go import graph (package → package edges) and
+// go.mod module requirements for the context pipeline.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package depgraph
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/canonjson"
+	"github.com/bartekus/cortex/internal/gomod"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// SchemaVersion is the version of dependencies.json.
+const SchemaVersion = "1.0"
+
+// Edge kinds, by where the imported package lives.
+const (
+	KindInternal = "internal" // a package in this repository
+	KindStdlib   = "stdlib"
+	KindExternal = "external" // a package from a required module
+)
+
+// Graph is the content of dependencies.json.
+type Graph struct {
+	SchemaVersion string    `json:"schema_version"`
+	Modules       []Module  `json:"modules"`
+	Packages      []Package `json:"packages"`
+	Edges         []Edge    `json:"edges"`
+	// Digest is the SHA-256 of the canonical JSON of the graph with an empty digest.
+	Digest string `json:"digest"`
+}
+
+// Module is a go.mod in the repository.
+type Module struct {
+	Path     string    `json:"path"` // module path
+	Dir      string    `json:"dir"`  // repo-relative directory
+	Go       string    `json:"go,omitempty"`
+	Requires []Require `json:"requires"`
+}
+
+// Require is a module requirement.
+type Require struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect,omitempty"`
+}
+
+// Package is a Go package in the repository.
+type Package struct {
+	Path   string `json:"path"`
+	Dir    string `json:"dir"`
+	Module string `json:"module,omitempty"`
+	// Imports are the imports of non-test files.
+	Imports []string `json:"imports"`
+	// TestImports are imports that only appear in _test.go files.
+	TestImports []string `json:"test_imports,omitempty"`
+}
+
+// Edge is an import of To by From. For external edges Module is the
+// required module providing To; Test marks imports only made by tests.
+type Edge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Kind   string `json:"kind"`
+	Module string `json:"module,omitempty"`
+	Test   bool   `json:"test,omitempty"`
+}
+
+// Extract builds the dependency graph for the Go files among files.
+func Extract(repoRoot string, files []xray.FileNode) (*Graph, error) {
+	mods, err := gomod.Find(repoRoot, files)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{SchemaVersion: SchemaVersion, Modules: []Module{}, Packages: []Package{}, Edges: []Edge{}}
+	for dir, m := range mods {
+		mod := Module{Path: m.Module, Dir: dir, Go: m.Go, Requires: []Require{}}
+		for _, r := range m.Require {
+			mod.Requires = append(mod.Requires, Require{Path: r.Path, Version: r.Version, Indirect: r.Indirect})
+		}
+		sort.Slice(mod.Requires, func(i, j int) bool { return mod.Requires[i].Path < mod.Requires[j].Path })
+		g.Modules = append(g.Modules, mod)
+	}
+	sort.Slice(g.Modules, func(i, j int) bool { return g.Modules[i].Path < g.Modules[j].Path })
+
+	type imports struct{ normal, test map[string]bool }
+	byDir := make(map[string]*imports)
+	var dirs []string
+	for _, f := range files {
+		if !strings.HasSuffix(f.Path, ".go") {
+			continue
+		}
+		paths, ok, err := fileImports(repoRoot, f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		dir := path.Dir(f.Path)
+		imp, seen := byDir[dir]
+		if !seen {
+			imp = &imports{normal: map[string]bool{}, test: map[string]bool{}}
+			byDir[dir] = imp
+			dirs = append(dirs, dir)
+		}
+		target := imp.normal
+		if strings.HasSuffix(f.Path, "_test.go") {
+			target = imp.test
+		}
+		for _, p := range paths {
+			target[p] = true
+		}
+	}
+	sort.Strings(dirs)
+
+	internal := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		internal[gomod.ImportPath(mods, dir)] = true
+	}
+
+	for _, dir := range dirs {
+		imp := byDir[dir]
+		pkg := Package{
+			Path:    gomod.ImportPath(mods, dir),
+			Dir:     dir,
+			Module:  moduleFor(mods, dir),
+			Imports: sortedKeys(imp.normal, nil),
+		}
+		pkg.TestImports = sortedKeys(imp.test, imp.normal)
+		g.Packages = append(g.Packages, pkg)
+
+		for _, to := range pkg.Imports {
+			g.Edges = append(g.Edges, classify(g.Modules, internal, pkg, to, false))
+		}
+		for _, to := range pkg.TestImports {
+			if to == pkg.Path {
+				continue // external test package importing its own package
+			}
+			g.Edges = append(g.Edges, classify(g.Modules, internal, pkg, to, true))
+		}
+	}
+	sort.Slice(g.Packages, func(i, j int) bool { return g.Packages[i].Path < g.Packages[j].Path })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return !a.Test && b.Test
+	})
+
+	digest, err := canonjson.Sum256(g)
+	if err != nil {
+		return nil, fmt.Errorf("computing dependencies digest: %w", err)
+	}
+	g.Digest = digest
+	return g, nil
+}
+
+// fileImports returns the import paths of a Go file; ok is false for files
+// that do not parse.
+func fileImports(repoRoot, rel string) ([]string, bool, error) {
+	src, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(rel))) //nolint:gosec // paths come from the XRAY index
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s: %w", rel, err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), rel, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, false, nil
+	}
+	var paths []string
+	for _, imp := range file.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	return paths, true, nil
+}
+
+func classify(modules []Module, internal map[string]bool, from Package, to string, test bool) Edge {
+	e := Edge{From: from.Path, To: to, Test: test}
+	switch {
+	case internal[to]:
+		e.Kind = KindInternal
+	case IsStdlib(to):
+		e.Kind = KindStdlib
+	default:
+		e.Kind = KindExternal
+		e.Module = providingModule(modules, from.Module, to)
+	}
+	return e
+}
+
+// IsStdlib reports whether an import path belongs to the standard library:
+// its first element contains no dot.
+func IsStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// providingModule finds the longest requirement of module that prefixes to.
+func providingModule(modules []Module, module, to string) string {
+	best := ""
+	for _, m := range modules {
+		if m.Path != module {
+			continue
+		}
+		for _, r := range m.Requires {
+			if (to == r.Path || strings.HasPrefix(to, r.Path+"/")) && len(r.Path) > len(best) {
+				best = r.Path
+			}
+		}
+	}
+	return best
+}
+
+func moduleFor(mods map[string]*gomod.File, dir string) string {
+	for d := dir; ; d = path.Dir(d) {
+		if m, ok := mods[d]; ok {
+			return m.Module
+		}
+		if d == "." || d == "/" {
+			return ""
+		}
+	}
+}
+
+func sortedKeys(m, exclude map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !exclude[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func writeRepo(t *testing.T, files map[string]string) (string, []xray.FileNode) {
+	t.Helper()
+	root := t.TempDir()
+	var nodes []xray.FileNode
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+		nodes = append(nodes, xray.FileNode{Path: rel})
+	}
+	return root, nodes
+}
+
+func testRepo(t *testing.T) (string, []xray.FileNode) {
+	return writeRepo(t, map[string]string{
+		"go.mod": `module example.com/demo
+
+go 1.24
+
+require (
+	github.com/spf13/cobra v1.8.0
+	github.com/stretchr/testify v1.9.0
+)
+`,
+		"cmd/main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/demo/lib"
+	"github.com/spf13/cobra"
+)
+`,
+		"lib/lib.go":      "package lib\n\nimport \"strings\"\n",
+		"lib/lib_test.go": "package lib_test\n\nimport (\n\t\"example.com/demo/lib\"\n\t\"github.com/stretchr/testify/assert\"\n)\n",
+		"broken/x.go":     "package",
+	})
+}
+
+func TestExtract(t *testing.T) {
+	root, files := testRepo(t)
+	g, err := Extract(root, files)
+	require.NoError(t, err)
+
+	require.Len(t, g.Modules, 1)
+	assert.Equal(t, Module{
+		Path: "example.com/demo", Dir: ".", Go: "1.24",
+		Requires: []Require{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0"},
+			{Path: "github.com/stretchr/testify", Version: "v1.9.0"},
+		},
+	}, g.Modules[0])
+
+	assert.Equal(t, []Package{
+		{Path: "example.com/demo/cmd", Dir: "cmd", Module: "example.com/demo", Imports: []string{"example.com/demo/lib", "fmt", "github.com/spf13/cobra"}, TestImports: []string{}},
+		{Path: "example.com/demo/lib", Dir: "lib", Module: "example.com/demo", Imports: []string{"strings"}, TestImports: []string{"example.com/demo/lib", "github.com/stretchr/testify/assert"}},
+	}, g.Packages)
+
+	assert.Equal(t, []Edge{
+		{From: "example.com/demo/cmd", To: "example.com/demo/lib", Kind: KindInternal},
+		{From: "example.com/demo/cmd", To: "fmt", Kind: KindStdlib},
+		{From: "example.com/demo/cmd", To: "github.com/spf13/cobra", Kind: KindExternal, Module: "github.com/spf13/cobra"},
+		{From: "example.com/demo/lib", To: "github.com/stretchr/testify/assert", Kind: KindExternal, Module: "github.com/stretchr/testify", Test: true},
+		{From: "example.com/demo/lib", To: "strings", Kind: KindStdlib},
+	}, g.Edges)
+
+	again, err := Extract(root, files)
+	require.NoError(t, err)
+	assert.Equal(t, g.Digest, again.Digest)
+}
+
+func TestDOT(t *testing.T) {
+	root, files := testRepo(t)
+	g, err := Extract(root, files)
+	require.NoError(t, err)
+
+	assert.Equal(t, `digraph dependencies {
+  rankdir=LR;
+  node [shape=box];
+  "example.com/demo/cmd";
+  "example.com/demo/lib";
+  "example.com/demo/cmd" -> "example.com/demo/lib";
+}
+`, g.DOT(DOTOptions{}))
+
+	full := g.DOT(DOTOptions{External: true, Tests: true})
+	assert.Contains(t, full, `"fmt" [style=dashed];`)
+	assert.Contains(t, full, `"github.com/spf13/cobra" [style=dotted];`)
+	assert.Contains(t, full, `"example.com/demo/lib" -> "github.com/stretchr/testify/assert" [style=dashed];`)
+}
+
+func TestIsStdlib(t *testing.T) {
+	assert.True(t, IsStdlib("net/http"))
+	assert.True(t, IsStdlib("fmt"))
+	assert.False(t, IsStdlib("github.com/x/y"))
+	assert.False(t, IsStdlib("gopkg.in/yaml.v3"))
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DOTOptions controls which edges are exported.
+type DOTOptions struct {
+	// External includes stdlib and external module edges.
+	External bool
+	// Tests includes edges that only come from _test.go files.
+	Tests bool
+}
+
+// DOT renders the package graph in Graphviz format. Nodes and edges are
+// emitted in sorted order so output is stable.
+func (g *Graph) DOT(opts DOTOptions) string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	nodes := make(map[string]string)
+	for _, p := range g.Packages {
+		nodes[p.Path] = KindInternal
+	}
+	var edges []Edge
+	for _, e := range g.Edges {
+		if e.Test && !opts.Tests {
+			continue
+		}
+		if e.Kind != KindInternal && !opts.External {
+			continue
+		}
+		edges = append(edges, e)
+		if _, ok := nodes[e.To]; !ok {
+			nodes[e.To] = e.Kind
+		}
+	}
+
+	names := make([]string, 0, len(nodes))
+	for n := range nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		attrs := ""
+		switch nodes[n] {
+		case KindStdlib:
+			attrs = " [style=dashed]"
+		case KindExternal:
+			attrs = " [style=dotted]"
+		}
+		fmt.Fprintf(&b, "  %s%s;\n", quote(n), attrs)
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range edges {
+		key := e.From + "\x00" + e.To
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		attrs := ""
+		if e.Test {
+			attrs = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", quote(e.From), quote(e.To), attrs)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
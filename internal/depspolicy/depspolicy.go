@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package depspolicy checks go.mod requirements against the dependency
+// policy of .cortex/config.yaml: denied modules, maximum major versions,
+// minimum versions and allowed licenses, with exceptions.
+package depspolicy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/gomod"
+)
+
+// Rules a requirement can break; exceptions name them.
+const (
+	RuleDeny       = "deny"
+	RuleMaxMajor   = "max_major"
+	RuleMinVersion = "min_version"
+	RuleLicense    = "license"
+)
+
+var rules = []string{RuleDeny, RuleMaxMajor, RuleMinVersion, RuleLicense}
+
+// Violation is a requirement that breaks a rule.
+type Violation struct {
+	GoMod   string // repo-relative path of the go.mod
+	Line    int
+	Module  string
+	Version string
+	Rule    string
+	Message string
+	// Warning marks a license that could not be determined: the policy
+	// cannot be checked, but it is not known to be broken.
+	Warning bool
+	// Waiver is the reason of the exception that waives the violation.
+	Waiver string
+}
+
+// String formats v as a finding: "go.mod:12: module version: message",
+// with "WARNING: " before the module of a warning.
+func (v Violation) String() string {
+	warning := ""
+	if v.Warning {
+		warning = "WARNING: "
+	}
+	return fmt.Sprintf("%s:%d: %s%s %s: %s", v.GoMod, v.Line, warning, v.Module, v.Version, v.Message)
+}
+
+// LicenseFunc returns the SPDX license expression of a module version. ok
+// is false when the module's files are not available.
+type LicenseFunc func(path, version string) (license string, ok bool, err error)
+
+// Policy is a validated dependency policy.
+type Policy struct {
+	cfg  config.Deps
+	used []bool // by exception
+}
+
+// Compile validates cfg.
+func Compile(cfg config.Deps) (*Policy, error) {
+	for pattern, min := range cfg.MinVersion {
+		if !IsValid(min) {
+			return nil, fmt.Errorf("deps.min_version: %s: invalid version %q", pattern, min)
+		}
+	}
+	for i, e := range cfg.Exceptions {
+		if e.Module == "" || strings.TrimSpace(e.Reason) == "" {
+			return nil, fmt.Errorf("deps.exceptions[%d]: module and reason are required", i)
+		}
+		for _, r := range e.Rules {
+			if !slices.Contains(rules, r) {
+				return nil, fmt.Errorf("deps.exceptions[%d]: unknown rule %q (want %s)", i, r, strings.Join(rules, ", "))
+			}
+		}
+	}
+	return &Policy{cfg: cfg, used: make([]bool, len(cfg.Exceptions))}, nil
+}
+
+// ChecksLicenses reports whether the policy has a license allowlist.
+func (p *Policy) ChecksLicenses() bool {
+	return len(p.cfg.Licenses) > 0
+}
+
+// Check returns the violations of the requirements of the go.mod at
+// goModPath, in requirement order. license is only called with a license
+// allowlist.
+func (p *Policy) Check(goModPath string, f *gomod.File, license LicenseFunc) ([]Violation, error) {
+	var out []Violation
+	for _, r := range f.Require {
+		if r.Indirect && p.cfg.DirectOnly {
+			continue
+		}
+		add := func(rule string, warning bool, format string, args ...any) {
+			out = append(out, Violation{
+				GoMod: goModPath, Line: r.Line, Module: r.Path, Version: r.Version,
+				Rule: rule, Message: fmt.Sprintf(format, args...), Warning: warning, Waiver: p.waive(r.Path, rule),
+			})
+		}
+
+		for _, d := range p.cfg.Deny {
+			if Match(d.Module, r.Path) {
+				msg := "denied"
+				if d.Reason != "" {
+					msg += ": " + d.Reason
+				}
+				add(RuleDeny, false, "%s", msg)
+				break
+			}
+		}
+		if max, ok := lookup(p.cfg.MaxMajor, trimMajorSuffix(r.Path)); ok && Major(r.Version) > max {
+			add(RuleMaxMajor, false, "major version %d exceeds the maximum %d", Major(r.Version), max)
+		}
+		if min, ok := lookup(p.cfg.MinVersion, r.Path); ok && Compare(r.Version, min) < 0 {
+			add(RuleMinVersion, false, "older than the minimum version %s", min)
+		}
+		if !p.ChecksLicenses() {
+			continue
+		}
+		lic, found, err := license(r.Path, r.Version)
+		switch {
+		case err != nil:
+			return nil, err
+		case !found:
+			add(RuleLicense, true, "not in the module cache; run `go mod download` to check its license")
+		case lic == "":
+			add(RuleLicense, true, "license not detected")
+		default:
+			var denied []string
+			for _, id := range strings.Split(lic, " AND ") {
+				if !slices.Contains(p.cfg.Licenses, id) {
+					denied = append(denied, id)
+				}
+			}
+			if len(denied) > 0 {
+				add(RuleLicense, false, "license %s is not allowed (allowed: %s)", strings.Join(denied, ", "), strings.Join(p.cfg.Licenses, ", "))
+			}
+		}
+	}
+	return out, nil
+}
+
+// Unused returns the exceptions that have waived nothing so far.
+func (p *Policy) Unused() []config.DepException {
+	var out []config.DepException
+	for i, e := range p.cfg.Exceptions {
+		if !p.used[i] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// waive returns the reason of the first exception covering rule for
+// module, or "".
+func (p *Policy) waive(module, rule string) string {
+	for i, e := range p.cfg.Exceptions {
+		if Match(e.Module, module) && (len(e.Rules) == 0 || slices.Contains(e.Rules, rule)) {
+			p.used[i] = true
+			return e.Reason
+		}
+	}
+	return ""
+}
+
+// Match reports whether a module pattern matches path: exactly, or for a
+// pattern ending in "/..." also every path below it.
+func Match(pattern, path string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return path == base || strings.HasPrefix(path, base+"/")
+	}
+	return pattern == path
+}
+
+// lookup returns the value of the most specific pattern in m matching
+// path: an exact match, then the longest "/..." pattern.
+func lookup[V any](m map[string]V, path string) (V, bool) {
+	if v, ok := m[path]; ok {
+		return v, true
+	}
+	patterns := make([]string, 0, len(m))
+	for p := range m {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool { return len(patterns[i]) > len(patterns[j]) })
+	for _, p := range patterns {
+		if Match(p, path) {
+			return m[p], true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+var majorSuffix = regexp.MustCompile(`(/v[0-9]+|\.v[0-9]+)$`)
+
+// trimMajorSuffix strips the major version suffix of a module path:
+// example.com/m/v3 and gopkg.in/yaml.v3 become example.com/m and
+// gopkg.in/yaml.
+func trimMajorSuffix(path string) string {
+	return majorSuffix.ReplaceAllString(path, "")
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package depspolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/gomod"
+)
+
+var goMod = gomod.Parse([]byte(`module example.com/app
+
+go 1.24
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/go-chi/chi/v5 v5.0.12
+	golang.org/x/net v0.21.0 // indirect
+	github.com/hashicorp/vault/api v1.12.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+`))
+
+func licenses(m map[string]string) LicenseFunc {
+	return func(path, _ string) (string, bool, error) {
+		lic, ok := m[path]
+		return lic, ok, nil
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p, err := Compile(config.Deps{
+		Deny:       []config.DeniedModule{{Module: "github.com/pkg/errors", Reason: "use fmt.Errorf with %w"}},
+		MaxMajor:   map[string]int{"github.com/go-chi/...": 6, "github.com/go-chi/chi": 4, "gopkg.in/yaml": 3},
+		MinVersion: map[string]string{"golang.org/x/...": "v0.23.0"},
+		Licenses:   []string{"MIT", "BSD-2-Clause"},
+		Exceptions: []config.DepException{
+			{Module: "github.com/hashicorp/...", Rules: []string{RuleLicense}, Reason: "approved by legal"},
+			{Module: "github.com/unused/module", Reason: "stale"},
+		},
+	})
+	require.NoError(t, err)
+
+	vs, err := p.Check("go.mod", goMod, licenses(map[string]string{
+		"github.com/pkg/errors":          "BSD-2-Clause",
+		"github.com/go-chi/chi/v5":       "MIT",
+		"github.com/hashicorp/vault/api": "MPL-2.0",
+		"gopkg.in/yaml.v3":               "Apache-2.0 AND MIT",
+	}))
+	require.NoError(t, err)
+
+	var lines []string
+	for _, v := range vs {
+		if v.Waiver != "" {
+			assert.Equal(t, "approved by legal", v.Waiver)
+			assert.Equal(t, "github.com/hashicorp/vault/api", v.Module)
+			continue
+		}
+		lines = append(lines, v.String())
+	}
+	assert.Equal(t, []string{
+		"go.mod:6: github.com/pkg/errors v0.9.1: denied: use fmt.Errorf with %w",
+		"go.mod:7: github.com/go-chi/chi/v5 v5.0.12: major version 5 exceeds the maximum 4",
+		"go.mod:8: golang.org/x/net v0.21.0: older than the minimum version v0.23.0",
+		"go.mod:8: WARNING: golang.org/x/net v0.21.0: not in the module cache; run `go mod download` to check its license",
+		"go.mod:10: gopkg.in/yaml.v3 v3.0.1: license Apache-2.0 is not allowed (allowed: MIT, BSD-2-Clause)",
+	}, lines)
+	assert.Equal(t, []config.DepException{{Module: "github.com/unused/module", Reason: "stale"}}, p.Unused())
+}
+
+func TestCheck_DirectOnly(t *testing.T) {
+	p, err := Compile(config.Deps{MinVersion: map[string]string{"golang.org/x/net": "v0.23.0"}, DirectOnly: true})
+	require.NoError(t, err)
+	assert.False(t, p.ChecksLicenses())
+	vs, err := p.Check("go.mod", goMod, nil)
+	require.NoError(t, err)
+	assert.Empty(t, vs)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, cfg := range []config.Deps{
+		{MinVersion: map[string]string{"golang.org/x/net": "0.23"}},
+		{Exceptions: []config.DepException{{Module: "m"}}},
+		{Exceptions: []config.DepException{{Module: "m", Reason: "r", Rules: []string{"licence"}}}},
+	} {
+		_, err := Compile(cfg)
+		assert.Error(t, err, "%+v", cfg)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, Match("github.com/a/b", "github.com/a/b"))
+	assert.False(t, Match("github.com/a/b", "github.com/a/b/v2"))
+	assert.True(t, Match("github.com/a/...", "github.com/a"))
+	assert.True(t, Match("github.com/a/...", "github.com/a/b/v2"))
+	assert.False(t, Match("github.com/a/...", "github.com/ab"))
+
+	assert.Equal(t, "github.com/a/b", trimMajorSuffix("github.com/a/b/v12"))
+	assert.Equal(t, "gopkg.in/yaml", trimMajorSuffix("gopkg.in/yaml.v3"))
+	assert.Equal(t, "github.com/a/b", trimMajorSuffix("github.com/a/b"))
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"v0.9.1",
+		"v1.0.0-0.20240101000000-abcdefabcdef",
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0",
+		"v1.2.0",
+		"v1.10.0",
+		"v2.0.0+incompatible",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			assert.Equal(t, want, Compare(ordered[i], ordered[j]), "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+	assert.Equal(t, 0, Compare("v1.0.0+build", "v1.0.0"))
+	assert.Equal(t, -1, Compare("latest", "v0.0.1"))
+
+	assert.Equal(t, 2, Major("v2.0.0+incompatible"))
+	assert.Equal(t, 0, Major("v0.0.0-20240101000000-abcdefabcdef"))
+	assert.Equal(t, -1, Major("master"))
+	assert.False(t, IsValid("v1.2"))
+}
+
+func TestDetectLicense(t *testing.T) {
+	tests := map[string]map[string]string{
+		"MIT":          {"LICENSE": "MIT License\n\nPermission is hereby granted, free of charge, to any person"},
+		"Apache-2.0":   {"LICENSE.txt": "                 Apache License\n           Version 2.0, January 2004"},
+		"BSD-3-Clause": {"LICENSE": "Redistribution and use in source and binary forms, with or without\nmodification... Neither the name of"},
+		"BSD-2-Clause": {"LICENCE.md": "Redistribution and use in source and binary\n  forms, with or without modification"},
+		"GPL-3.0":      {"COPYING": "GNU GENERAL PUBLIC LICENSE\n Version 3, 29 June 2007"},
+		"LGPL-3.0":     {"COPYING.LESSER": "GNU LESSER GENERAL PUBLIC LICENSE\n Version 3, 29 June 2007"},
+		"Apache-2.0 AND MIT": {
+			"LICENSE":         "Apache License, Version 2.0",
+			"LICENSE-MIT":     "Permission is hereby granted, free of charge",
+			"license_test.go": "Permission to use, copy, modify, and/or distribute this software for any purpose",
+		},
+		"": {"README.md": "Permission is hereby granted, free of charge"},
+	}
+	for want, files := range tests {
+		dir := t.TempDir()
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+		got, err := DetectLicense(dir)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestCacheLicenses(t *testing.T) {
+	cache := t.TempDir()
+	dir := filepath.Join(cache, "github.com", "!burnt!sushi", "toml@v1.3.2")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "COPYING"), []byte("Permission is hereby granted, free of charge"), 0o600))
+
+	lic, ok, err := CacheLicenses(cache)("github.com/BurntSushi/toml", "v1.3.2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "MIT", lic)
+
+	_, ok, err = CacheLicenses(cache)("github.com/BurntSushi/toml", "v1.4.0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
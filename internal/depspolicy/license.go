@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package depspolicy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CacheLicenses returns a LicenseFunc reading the license files of modules
+// extracted in the module cache at dir.
+func CacheLicenses(dir string) LicenseFunc {
+	return func(path, version string) (string, bool, error) {
+		modDir := filepath.Join(dir, filepath.FromSlash(escape(path))+"@"+escape(version))
+		if _, err := os.Stat(modDir); errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		lic, err := DetectLicense(modDir)
+		return lic, true, err
+	}
+}
+
+// escape applies the module cache case encoding: "!" followed by the
+// lower-case letter for every upper-case letter.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var licenseFile = regexp.MustCompile(`(?i)^(licen[cs]e|copying)([.-].*)?$`)
+
+// signatures identify licenses by phrases of their text, matched
+// case-insensitively with whitespace collapsed. More specific licenses come
+// first; a later signature is skipped when an earlier one it names in
+// excludes matched.
+var signatures = []struct {
+	id       string
+	phrases  []string
+	excludes []string
+}{
+	{id: "AGPL-3.0", phrases: []string{"gnu affero general public license", "version 3"}},
+	{id: "LGPL-3.0", phrases: []string{"gnu lesser general public license", "version 3"}},
+	{id: "LGPL-2.1", phrases: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "GPL-3.0", phrases: []string{"gnu general public license", "version 3"}, excludes: []string{"AGPL-3.0", "LGPL-3.0", "LGPL-2.1"}},
+	{id: "GPL-2.0", phrases: []string{"gnu general public license", "version 2"}, excludes: []string{"AGPL-3.0", "LGPL-3.0", "LGPL-2.1", "GPL-3.0"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license", "2.0"}},
+	{id: "Apache-2.0", phrases: []string{"apache license", "version 2.0"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}, excludes: []string{"BSD-3-Clause"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+// DetectLicense returns the licenses of the module extracted at dir as an
+// SPDX expression ("MIT", or "Apache-2.0 AND MIT" when its license files
+// carry several), or "" when none is recognized. Only license files at the
+// module root are read.
+func DetectLicense(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, e := range entries {
+		if e.Type().IsRegular() && licenseFile.MatchString(e.Name()) {
+			data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+			if err != nil {
+				return "", err
+			}
+			text.WriteString(strings.ToLower(strings.Join(strings.Fields(string(data)), " ")))
+			text.WriteByte(' ')
+		}
+	}
+	return classify(text.String()), nil
+}
+
+// classify returns the SPDX expression of normalized license text.
+func classify(text string) string {
+	found := map[string]bool{}
+	for _, sig := range signatures {
+		skip := false
+		for _, ex := range sig.excludes {
+			skip = skip || found[ex]
+		}
+		matches := !skip
+		for _, p := range sig.phrases {
+			matches = matches && strings.Contains(text, p)
+		}
+		found[sig.id] = matches
+	}
+	var ids []string
+	for id, ok := range found {
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, " AND ")
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package depspolicy
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var semverRE = regexp.MustCompile(`^v([0-9]+)\.([0-9]+)\.([0-9]+)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// IsValid reports whether v is a semantic version as Go modules use them:
+// vMAJOR.MINOR.PATCH with optional pre-release and build metadata.
+func IsValid(v string) bool {
+	return semverRE.MatchString(v)
+}
+
+// Major returns the major version of v, or -1 when v is invalid.
+// "+incompatible" versions keep their major: v2.0.0+incompatible is 2.
+func Major(v string) int {
+	m := semverRE.FindStringSubmatch(v)
+	if m == nil {
+		return -1
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// Compare returns -1, 0 or 1 as a is lower than, equal to or higher than b
+// in semantic version precedence; build metadata is ignored and invalid
+// versions sort before valid ones. Pseudo-versions are pre-releases, so
+// they sort before the release they precede.
+func Compare(a, b string) int {
+	ma, mb := semverRE.FindStringSubmatch(a), semverRE.FindStringSubmatch(b)
+	switch {
+	case ma == nil && mb == nil:
+		return strings.Compare(a, b)
+	case ma == nil:
+		return -1
+	case mb == nil:
+		return 1
+	}
+	for i := 1; i <= 3; i++ {
+		if c := compareNumeric(ma[i], mb[i]); c != 0 {
+			return c
+		}
+	}
+	switch pa, pb := ma[4], mb[4]; {
+	case pa == pb:
+		return 0
+	case pa == "":
+		return 1
+	case pb == "":
+		return -1
+	default:
+		return comparePrerelease(pa, pb)
+	}
+}
+
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		_, errA := strconv.Atoi(as[i])
+		_, errB := strconv.Atoi(bs[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareNumeric(as[i], bs[i])
+		case errA == nil:
+			c = -1 // numeric identifiers sort first
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// compareNumeric compares decimal strings of any length.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
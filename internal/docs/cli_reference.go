@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartekus/cortex/internal/specschema"
+	"github.com/bartekus/cortex/pkg/introspect"
+)
+
+// Feature: CLI_COMMAND_DOCS
+// Spec: spec/cli/docs.md
+
+// GenerateCLIReference renders a markdown page for root and every
+// subcommand into outDir, replacing the pages already there. Flags come
+// from the introspected tree; the exit codes of a command are those in
+// the frontmatter of the nearest CLI_COMMAND_<PATH> spec under specRoot,
+// described by codes. The completion command, which cobra adds on
+// execution, is added first so that the pages do not depend on how root
+// was run.
+func GenerateCLIReference(root *cobra.Command, specRoot string, codes map[int]string, outDir string) error {
+	specs, err := specschema.LoadAllSpecs(specRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load specs: %w", err)
+	}
+	root.InitDefaultCompletionCmd()
+	pages := renderCLIReference(introspect.Introspect(root)[0], specs, filepath.Dir(specRoot), codes)
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil { //nolint:gosec // output directory needs write permissions
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	stale, err := filepath.Glob(filepath.Join(outDir, "*.md"))
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if _, ok := pages[filepath.Base(path)]; !ok {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove stale page: %w", err)
+			}
+		}
+	}
+	for name, content := range pages {
+		if err := os.WriteFile(filepath.Join(outDir, name), []byte(content), 0o644); err != nil { //nolint:gosec // output file needs read permissions
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// cliPage is a command with the context needed to render its page.
+type cliPage struct {
+	cmd       introspect.CommandInfo
+	path      []string // command names from the root
+	parent    *cliPage
+	inherited map[string]bool // flags inherited from the parent
+}
+
+func (p *cliPage) name() string     { return strings.Join(p.path, " ") }
+func (p *cliPage) fileName() string { return strings.Join(p.path, "_") + ".md" }
+
+// renderCLIReference returns the pages of the tree by file name. base is
+// the directory spec paths are shown relative to.
+func renderCLIReference(root introspect.CommandInfo, specs []specschema.Spec, base string, codes map[int]string) map[string]string {
+	byFeature := make(map[string]specschema.Spec, len(specs))
+	for _, s := range specs {
+		byFeature[s.Frontmatter.Feature] = s
+	}
+
+	pages := map[string]string{}
+	var walk func(p *cliPage)
+	walk = func(p *cliPage) {
+		var children []*cliPage
+		for _, sub := range p.cmd.Subcommands {
+			// cobra's generated help command documents nothing, and
+			// hidden commands are not documented.
+			if (len(p.path) == 1 && sub.Use == "help [command]") || sub.Hidden {
+				continue
+			}
+			child := &cliPage{cmd: sub, path: append(append([]string{}, p.path...), commandName(sub.Use)), parent: p, inherited: map[string]bool{}}
+			for _, f := range p.cmd.Flags {
+				if f.Persistent {
+					child.inherited[f.Name] = true
+				}
+			}
+			children = append(children, child)
+		}
+		sort.Slice(children, func(i, j int) bool { return children[i].name() < children[j].name() })
+
+		spec, hasSpec := specFor(p, byFeature)
+		pages[p.fileName()] = renderCLIPage(p, children, spec, hasSpec, base, codes)
+		for _, child := range children {
+			walk(child)
+		}
+	}
+	walk(&cliPage{cmd: root, path: []string{commandName(root.Use)}, inherited: map[string]bool{}})
+	return pages
+}
+
+// specFor returns the spec of the nearest command on p's path with a
+// CLI_COMMAND_<PATH> feature, such as CLI_COMMAND_SNAPSHOT for
+// `cortex snapshot diff`.
+func specFor(p *cliPage, byFeature map[string]specschema.Spec) (specschema.Spec, bool) {
+	for n := len(p.path); n > 1; n-- {
+		id := "CLI_COMMAND_" + strings.ToUpper(strings.ReplaceAll(strings.Join(p.path[1:n], "_"), "-", "_"))
+		if s, ok := byFeature[id]; ok {
+			return s, true
+		}
+	}
+	return specschema.Spec{}, false
+}
+
+func renderCLIPage(p *cliPage, children []*cliPage, spec specschema.Spec, hasSpec bool, base string, codes map[int]string) string {
+	var sb strings.Builder
+	sb.WriteString("<!-- Generated by `cortex docs cli`. DO NOT EDIT. -->\n\n")
+	fmt.Fprintf(&sb, "# %s\n\n", p.name())
+	if p.cmd.Short != "" {
+		fmt.Fprintf(&sb, "%s\n\n", p.cmd.Short)
+	}
+	if long := strings.TrimSpace(p.cmd.Long); long != "" && long != p.cmd.Short {
+		fmt.Fprintf(&sb, "%s\n\n", long)
+	}
+	if p.cmd.Deprecated != "" {
+		fmt.Fprintf(&sb, "> **Deprecated:** %s\n\n", strings.TrimSpace(p.cmd.Deprecated))
+	}
+
+	var own, inherited []introspect.FlagInfo
+	for _, f := range p.cmd.Flags {
+		switch {
+		case f.Name == "help" || f.Hidden:
+		case p.inherited[f.Name]:
+			inherited = append(inherited, f)
+		default:
+			own = append(own, f)
+		}
+	}
+
+	// The usage lines follow cobra's: the parent path, the Use line and
+	// "[flags]" unless it is already there.
+	sb.WriteString("## Usage\n\n```\n")
+	use := strings.TrimSpace(strings.Join(p.path[:len(p.path)-1], " ") + " " + p.cmd.Use)
+	if len(own)+len(inherited) > 0 && !strings.Contains(use, "[flags]") {
+		use += " [flags]"
+	}
+	fmt.Fprintf(&sb, "%s\n", use)
+	if len(children) > 0 {
+		fmt.Fprintf(&sb, "%s [command]\n", p.name())
+	}
+	sb.WriteString("```\n\n")
+	if len(p.cmd.Aliases) > 0 {
+		fmt.Fprintf(&sb, "Aliases: `%s`\n\n", strings.Join(p.cmd.Aliases, "`, `"))
+	}
+	writeFlagTable(&sb, "Flags", own)
+	writeFlagTable(&sb, "Inherited Flags", inherited)
+
+	if len(children) > 0 {
+		sb.WriteString("## Subcommands\n\n")
+		sb.WriteString("| Command | Description |\n")
+		sb.WriteString("|---------|-------------|\n")
+		for _, c := range children {
+			fmt.Fprintf(&sb, "| [`%s`](%s) | %s |\n", c.name(), c.fileName(), cell(c.cmd.Short))
+		}
+		sb.WriteString("\n")
+	}
+
+	// The exit codes are the spec's, or those the command declares when
+	// no spec does.
+	var exits []int
+	if hasSpec {
+		for key := range spec.Frontmatter.Outputs.ExitCodes {
+			if code, err := strconv.Atoi(key); err == nil {
+				exits = append(exits, code)
+			}
+		}
+		sort.Ints(exits)
+	}
+	if len(exits) == 0 {
+		exits = p.cmd.ExitCodes
+	}
+	if len(exits) > 0 {
+		sb.WriteString("## Exit Codes\n\n")
+		sb.WriteString("| Code | Meaning |\n")
+		sb.WriteString("|------|---------|\n")
+		for _, code := range exits {
+			fmt.Fprintf(&sb, "| %d | %s |\n", code, cell(codes[code]))
+		}
+		sb.WriteString("\n")
+	}
+
+	if hasSpec {
+
+		specPath := spec.Path
+		if rel, err := filepath.Rel(base, spec.Path); err == nil {
+			specPath = rel
+		}
+		fmt.Fprintf(&sb, "Spec: `%s` (%s)\n\n", filepath.ToSlash(specPath), spec.Frontmatter.Feature)
+	}
+
+	if p.parent != nil {
+		sb.WriteString("## See Also\n\n")
+		fmt.Fprintf(&sb, "- [`%s`](%s): %s\n", p.parent.name(), p.parent.fileName(), p.parent.cmd.Short)
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func writeFlagTable(sb *strings.Builder, title string, flags []introspect.FlagInfo) {
+	if len(flags) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "## %s\n\n", title)
+	sb.WriteString("| Flag | Type | Default | Description |\n")
+	sb.WriteString("|------|------|---------|-------------|\n")
+	for _, f := range flags {
+		name := "`--" + f.Name + "`"
+		if f.Shorthand != "" {
+			name = "`-" + f.Shorthand + "`, " + name
+		}
+		def := ""
+		if f.Default != "" && f.Default != "[]" {
+			def = "`" + f.Default + "`"
+		}
+		fmt.Fprintf(sb, "| %s | %s | %s | %s |\n", name, f.Type, cell(def), cell(f.Usage))
+	}
+	sb.WriteString("\n")
+}
+
+// commandName is the first word of a cobra Use line.
+func commandName(use string) string {
+	name, _, _ := strings.Cut(use, " ")
+	return name
+}
+
+// cell escapes text for a markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
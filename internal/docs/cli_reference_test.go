@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/pkg/introspect"
+)
+
+// Feature: CLI_COMMAND_DOCS
+// Spec: spec/cli/docs.md
+
+func cliTree() *cobra.Command {
+	root := &cobra.Command{Use: "tool", Short: "A tool"}
+	root.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	db := &cobra.Command{Use: "db", Short: "Database commands"}
+	db.PersistentFlags().String("dsn", "", "Database | connection string")
+	migrate := &cobra.Command{Use: "migrate <DIR>", Short: "Run migrations", Long: "Applies every pending migration.", Run: func(*cobra.Command, []string) {}}
+	migrate.Flags().Int("steps", 3, "Steps to run")
+	db.AddCommand(migrate)
+	db.AddCommand(&cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(db)
+	return root
+}
+
+func TestGenerateCLIReference(t *testing.T) {
+	tmp := t.TempDir()
+	specRoot := filepath.Join(tmp, "spec")
+	require.NoError(t, os.MkdirAll(filepath.Join(specRoot, "cli"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specRoot, "cli", "db.md"), []byte(`---
+feature: CLI_COMMAND_DB
+version: v1
+status: approved
+domain: cli
+outputs:
+  exit_codes:
+    0: 0
+    4: 4
+---
+# DB
+`), 0o600))
+
+	out := filepath.Join(tmp, "docs")
+	require.NoError(t, os.MkdirAll(out, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "tool_gone.md"), []byte("stale\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "README.txt"), []byte("kept\n"), 0o600))
+
+	codes := map[int]string{0: "Success", 4: "Execution error"}
+	require.NoError(t, GenerateCLIReference(cliTree(), specRoot, codes, out))
+
+	entries, err := os.ReadDir(out)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{
+		"README.txt",
+		"tool.md",
+		"tool_completion.md",
+		"tool_completion_bash.md",
+		"tool_completion_fish.md",
+		"tool_completion_powershell.md",
+		"tool_completion_zsh.md",
+		"tool_db.md",
+		"tool_db_migrate.md",
+	}, names)
+
+	data, err := os.ReadFile(filepath.Join(out, "tool_db_migrate.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "<!-- Generated by `cortex docs cli`. DO NOT EDIT. -->\n"+`
+# tool db migrate
+
+Run migrations
+
+Applies every pending migration.
+
+## Usage
+
+`+"```"+`
+tool db migrate <DIR> [flags]
+`+"```"+`
+
+## Flags
+
+| Flag | Type | Default | Description |
+|------|------|---------|-------------|
+| `+"`--steps`"+` | int | `+"`3`"+` | Steps to run |
+
+## Inherited Flags
+
+| Flag | Type | Default | Description |
+|------|------|---------|-------------|
+| `+"`--dsn`"+` | string |  | Database \| connection string |
+| `+"`-v`, `--verbose`"+` | bool | `+"`false`"+` | verbose output |
+
+## Exit Codes
+
+| Code | Meaning |
+|------|---------|
+| 0 | Success |
+| 4 | Execution error |
+
+Spec: `+"`spec/cli/db.md`"+` (CLI_COMMAND_DB)
+
+## See Also
+
+- [`+"`tool db`"+`](tool_db.md): Database commands
+`, string(data))
+
+	data, err = os.ReadFile(filepath.Join(out, "tool.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "| [`tool db`](tool_db.md) | Database commands |\n")
+	assert.NotContains(t, string(data), "## Exit Codes")
+	assert.NotContains(t, string(data), "tool help")
+
+	data, err = os.ReadFile(filepath.Join(out, "tool_db.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "tool db [flags]\ntool db [command]\n")
+	assert.Contains(t, string(data), "| `--dsn` | string |  | Database \\| connection string |\n")
+}
+
+func TestRenderCLIReference_CommandContract(t *testing.T) {
+	root := &cobra.Command{Use: "tool"}
+	introspect.SetExitCodes(root, 0, 4)
+	old := &cobra.Command{Use: "old", Aliases: []string{"legacy", "o"}, Deprecated: "use new instead", Run: func(*cobra.Command, []string) {}}
+	old.Flags().String("mode", "", "Mode")
+	old.Flags().String("internal", "", "Internal knob")
+	require.NoError(t, old.Flags().MarkHidden("internal"))
+	old.Flags().Bool("fast", false, "Go fast")
+	require.NoError(t, old.Flags().MarkDeprecated("fast", "it is always fast"))
+	root.AddCommand(old)
+
+	pages := renderCLIReference(introspect.Introspect(root)[0], nil, ".", map[int]string{0: "Success", 4: "Execution error"})
+	page := pages["tool_old.md"]
+	assert.Contains(t, page, "> **Deprecated:** use new instead\n")
+	assert.Contains(t, page, "Aliases: `legacy`, `o`\n")
+	assert.NotContains(t, page, "--internal")
+	assert.NotContains(t, page, "--fast", "deprecated flags are hidden, as in --help")
+	assert.Contains(t, page, "| 4 | Execution error |\n", "without a spec the declared exit codes are listed")
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package doctor checks that the environment can run Cortex: the external
+// tools skills call, their versions against the minimums of tools.versions
+// in .cortex/config.yaml, and the health of the repository's spec. Every
+// failed check carries a remediation step.
+//
+// Feature: CLI_COMMAND_DOCTOR
+// Spec: spec/cli/doctor.md
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// Check statuses.
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// Check is the outcome of one check.
+type Check struct {
+	// Name is the tool or repository check ("go", "features").
+	Name string `json:"name"`
+	// Kind is "tool" or "repo".
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	// Path is where a tool was found.
+	Path string `json:"path,omitempty"`
+	// Version is the version a tool reported.
+	Version string `json:"version,omitempty"`
+	// Message explains the status.
+	Message string `json:"message"`
+	// Remediation is the step that fixes a failed or warned check.
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Report is the outcome of all checks.
+type Report struct {
+	Checks   []Check `json:"checks"`
+	Failed   int     `json:"failed"`
+	Warnings int     `json:"warnings"`
+}
+
+// Tool is an external program Cortex runs. A missing required tool fails
+// the report; a missing optional one only warns.
+type Tool struct {
+	Name     string
+	Required bool
+	// UsedBy names what needs the tool.
+	UsedBy string
+	// VersionArgs print the version.
+	VersionArgs []string
+	// Install is the remediation when the tool is missing or too old.
+	Install string
+}
+
+// Tools are the programs checked, in report order.
+var Tools = []Tool{
+	{Name: "git", Required: true, UsedBy: "file discovery, fingerprints and history", VersionArgs: []string{"--version"},
+		Install: "Install git: https://git-scm.com/downloads"},
+	{Name: "go", Required: true, UsedBy: "test:*, deps:* and module discovery", VersionArgs: []string{"version"},
+		Install: "Install Go: https://go.dev/dl/"},
+	{Name: "gofumpt", UsedBy: "lint:gofumpt, format:gofumpt", VersionArgs: []string{"--version"},
+		Install: "Run: cortex tools install"},
+	{Name: "golangci-lint", UsedBy: "lint:golangci", VersionArgs: []string{"--version"},
+		Install: "Run: cortex tools install"},
+	{Name: "docker", UsedBy: "container workflows", VersionArgs: []string{"--version"},
+		Install: "Install Docker: https://docs.docker.com/get-docker/"},
+	{Name: "xray", UsedBy: "context build and xray drift checks", VersionArgs: []string{"--version"},
+		Install: "Build it with `cargo build --release` in rust/xray/ or set $XRAY_BIN"},
+}
+
+// Prober finds and runs programs. The command implements it with os/exec.
+type Prober interface {
+	LookPath(name string) (string, error)
+	Output(ctx context.Context, path string, args ...string) ([]byte, error)
+}
+
+// Options configures Run.
+type Options struct {
+	RepoRoot string
+	// FeaturesPath is the registry, relative to RepoRoot unless absolute.
+	FeaturesPath string
+	// Config holds the tool minimums; nil when the config did not load.
+	Config *config.Config
+}
+
+// Run performs every check.
+func Run(ctx context.Context, p Prober, o Options) Report {
+	var r Report
+	var mins map[string]string
+	if o.Config != nil {
+		mins = o.Config.Tools.Versions
+	}
+	for _, t := range Tools {
+		r.add(checkTool(ctx, p, t, o.RepoRoot, mins[t.Name]))
+	}
+	for _, c := range checkRepo(o) {
+		r.add(c)
+	}
+	return r
+}
+
+func (r *Report) add(c Check) {
+	switch c.Status {
+	case StatusFail:
+		r.Failed++
+	case StatusWarn:
+		r.Warnings++
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+func checkTool(ctx context.Context, p Prober, t Tool, repoRoot, minimum string) Check {
+	c := Check{Name: t.Name, Kind: "tool"}
+	missing := StatusWarn
+	if t.Required {
+		missing = StatusFail
+	}
+
+	path, err := lookTool(p, t.Name, repoRoot)
+	if err != nil {
+		c.Status, c.Message, c.Remediation = missing, fmt.Sprintf("not found (needed by %s)", t.UsedBy), t.Install
+		return c
+	}
+	c.Path = path
+
+	out, err := p.Output(ctx, path, t.VersionArgs...)
+	c.Version = ParseVersion(string(out))
+	if err != nil || c.Version == "" {
+		c.Status, c.Message, c.Remediation = missing, fmt.Sprintf("%s %s did not report a version", t.Name, strings.Join(t.VersionArgs, " ")), t.Install
+		return c
+	}
+
+	if minimum != "" && CompareVersions(c.Version, minimum) < 0 {
+		c.Status = missing
+		c.Message = fmt.Sprintf("version %s is below the minimum %s (tools.versions)", c.Version, minimum)
+		c.Remediation = fmt.Sprintf("Upgrade %s to %s or later. %s", t.Name, strings.TrimPrefix(minimum, "v"), t.Install)
+		return c
+	}
+	c.Status, c.Message = StatusOK, "found"
+	if minimum != "" {
+		c.Message = fmt.Sprintf("meets the minimum %s", minimum)
+	}
+	return c
+}
+
+// lookTool finds a tool like the skills that run it: in the repository's
+// tool directory, then on PATH; xray is first resolved like the commands
+// that run it.
+func lookTool(p Prober, name, repoRoot string) (string, error) {
+	if path, err := p.LookPath(filepath.Join(repoRoot, filepath.FromSlash(runner.ToolBinDir), name)); err == nil {
+		return path, nil
+	}
+	if name == "xray" {
+		if bin, err := xray.ResolveBin("", repoRoot); err == nil {
+			if _, err := os.Stat(bin); err == nil {
+				return bin, nil
+			}
+		}
+	}
+	return p.LookPath(name)
+}
+
+func checkRepo(o Options) []Check {
+	var out []Check
+
+	spec := Check{Name: "spec", Kind: "repo", Status: StatusOK, Message: "spec/ present"}
+	if fi, err := os.Stat(filepath.Join(o.RepoRoot, "spec")); err != nil || !fi.IsDir() {
+		spec.Status, spec.Message, spec.Remediation = StatusFail, "spec/ directory missing", "Run: cortex init"
+	}
+	out = append(out, spec)
+
+	featuresPath := o.FeaturesPath
+	if !filepath.IsAbs(featuresPath) {
+		featuresPath = filepath.Join(o.RepoRoot, featuresPath)
+	}
+	feats := Check{Name: "features", Kind: "repo", Status: StatusOK}
+	if g, err := features.LoadGraph(featuresPath); err != nil {
+		feats.Status, feats.Message = StatusFail, err.Error()
+		feats.Remediation = "Fix the error in " + o.FeaturesPath + ", or scaffold one with: cortex init"
+	} else if err := features.ValidateDAG(g); err != nil {
+		feats.Status, feats.Message = StatusFail, err.Error()
+		feats.Remediation = "Fix the depends_on entries of " + o.FeaturesPath + "; cortex features graph shows the graph"
+	} else {
+		feats.Message = fmt.Sprintf("%s valid (%d features)", o.FeaturesPath, len(g.Nodes))
+	}
+	out = append(out, feats)
+
+	cfg := Check{Name: "config", Kind: "repo", Status: StatusOK, Message: config.File + " valid"}
+	if diags, err := config.Validate(o.RepoRoot, config.DefaultOptions()); err != nil {
+		cfg.Status, cfg.Message, cfg.Remediation = StatusFail, err.Error(), "Make "+config.File+" readable"
+	} else if len(diags) > 0 {
+		cfg.Status = StatusFail
+		cfg.Message = fmt.Sprintf("%d problem(s), first: %s", len(diags), diags[0])
+		cfg.Remediation = "Run: cortex config validate"
+	}
+	out = append(out, cfg)
+	return out
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+\.\d+(?:\.\d+)?)`)
+
+// ParseVersion returns the first dotted version in the output of a version
+// command ("go version go1.24.2 linux/amd64" → "1.24.2"), or "".
+func ParseVersion(out string) string {
+	if m := versionPattern.FindStringSubmatch(out); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// CompareVersions compares dotted versions numerically, ignoring a leading
+// "v"; missing components are 0.
+func CompareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
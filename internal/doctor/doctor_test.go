@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package doctor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/config"
+)
+
+// Feature: CLI_COMMAND_DOCTOR
+// Spec: spec/cli/doctor.md
+
+// fakeProber knows the tools of its map, with their version output.
+type fakeProber map[string]string
+
+func (f fakeProber) LookPath(name string) (string, error) {
+	if _, ok := f[name]; !ok {
+		return "", errors.New("not found")
+	}
+	return "/bin/" + name, nil
+}
+
+func (f fakeProber) Output(_ context.Context, path string, _ ...string) ([]byte, error) {
+	return []byte(f[filepath.Base(path)]), nil
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XRAY_BIN", filepath.Join(root, "missing-xray"))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "spec"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "spec", "features.yaml"), []byte("features:\n  - id: A\n    title: A\n"), 0o644))
+
+	p := fakeProber{
+		"git":     "git version 2.39.5",
+		"go":      "go version go1.22.1 linux/amd64",
+		"gofumpt": "v0.6.0 (go1.22.1)",
+	}
+	cfg := &config.Config{Tools: config.Tools{Versions: map[string]string{"go": "1.24", "gofumpt": "v0.6.0"}}}
+	r := Run(context.Background(), p, Options{RepoRoot: root, FeaturesPath: "spec/features.yaml", Config: cfg})
+
+	byName := map[string]Check{}
+	for _, c := range r.Checks {
+		byName[c.Name] = c
+	}
+	assert.Equal(t, Check{Name: "git", Kind: "tool", Status: StatusOK, Path: "/bin/git", Version: "2.39.5", Message: "found"}, byName["git"])
+	assert.Equal(t, StatusFail, byName["go"].Status)
+	assert.Equal(t, "version 1.22.1 is below the minimum 1.24 (tools.versions)", byName["go"].Message)
+	assert.Contains(t, byName["go"].Remediation, "Upgrade go to 1.24 or later")
+	assert.Equal(t, StatusOK, byName["gofumpt"].Status)
+	assert.Equal(t, StatusWarn, byName["golangci-lint"].Status)
+	assert.Equal(t, "Run: cortex tools install", byName["golangci-lint"].Remediation)
+	assert.Equal(t, StatusWarn, byName["xray"].Status)
+	assert.Equal(t, StatusOK, byName["spec"].Status)
+	assert.Equal(t, StatusOK, byName["features"].Status, byName["features"].Message)
+	assert.Equal(t, StatusOK, byName["config"].Status)
+	assert.Equal(t, 1, r.Failed)
+	assert.Equal(t, 3, r.Warnings)
+}
+
+func TestRun_BrokenRepo(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Dir(config.Path(root)), 0o755))
+	require.NoError(t, os.WriteFile(config.Path(root), []byte("skils: {}\n"), 0o644))
+
+	r := Run(context.Background(), fakeProber{}, Options{RepoRoot: root, FeaturesPath: "spec/features.yaml"})
+	var repo []Check
+	for _, c := range r.Checks {
+		if c.Kind == "repo" {
+			repo = append(repo, c)
+		}
+	}
+	require.Len(t, repo, 3)
+	assert.Equal(t, "Run: cortex init", repo[0].Remediation)
+	assert.Equal(t, StatusFail, repo[1].Status)
+	assert.Equal(t, `1 problem(s), first: .cortex/config.yaml:1:1: skils: unknown key (did you mean "skills"?)`, repo[2].Message)
+	assert.Equal(t, 5, r.Failed, "git, go and the three repository checks")
+}
+
+func TestVersions(t *testing.T) {
+	assert.Equal(t, "1.24.2", ParseVersion("go version go1.24.2 linux/amd64"))
+	assert.Equal(t, "2.6.2", ParseVersion("golangci-lint has version 2.6.2 built with go1.25.1"))
+	assert.Equal(t, "27.0", ParseVersion("Docker version 27.0, build 7d4bcd8"))
+	assert.Equal(t, "", ParseVersion("unknown"))
+
+	assert.Equal(t, 0, CompareVersions("1.24.0", "v1.24"))
+	assert.Equal(t, -1, CompareVersions("1.9", "1.10"))
+	assert.Equal(t, 1, CompareVersions("2.0.1", "2"))
+}
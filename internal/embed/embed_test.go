@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/chunker"
+)
+
+// fakeProvider embeds a text as [len, 1] and counts calls.
+type fakeProvider struct {
+	model string
+	calls int
+	texts int
+}
+
+func (f *fakeProvider) Name() string  { return "fake" }
+func (f *fakeProvider) Model() string { return f.model }
+func (f *fakeProvider) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	f.calls++
+	f.texts += len(texts)
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = []float32{float32(len(t)), 1}
+	}
+	return out, nil
+}
+
+func testChunks() []chunker.Chunk {
+	return []chunker.Chunk{
+		{ID: "1", FilePath: "a.go", StartLine: 1, EndLine: 2, ContentHash: "sha256:a", Content: "aaaa"},
+		{ID: "2", FilePath: "b.go", StartLine: 1, EndLine: 1, ContentHash: "sha256:b", Content: "b"},
+		{ID: "3", FilePath: "c.go", StartLine: 5, EndLine: 9, ContentHash: "sha256:c", Content: "cc"},
+	}
+}
+
+func TestBuild_BatchesAndReuses(t *testing.T) {
+	p := &fakeProvider{model: "m1"}
+	ix, stats, err := Build(context.Background(), p, testChunks(), nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Embedded: 3}, stats)
+	assert.Equal(t, 2, p.calls)
+	assert.Equal(t, Meta{SchemaVersion: IndexSchemaVersion, Provider: "fake", Model: "m1", Dimensions: 2, Count: 3}, ix.Meta)
+
+	chunks := testChunks()
+	chunks[1].ContentHash, chunks[1].Content = "sha256:b2", "bbb"
+	p2 := &fakeProvider{model: "m1"}
+	_, stats, err = Build(context.Background(), p2, chunks, ix, 10)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Embedded: 1, Reused: 2}, stats)
+	assert.Equal(t, 1, p2.texts)
+
+	// A different model invalidates the cache.
+	p3 := &fakeProvider{model: "m2"}
+	_, stats, err = Build(context.Background(), p3, chunks, ix, 10)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Embedded: 3}, stats)
+}
+
+func TestIndex_WriteLoadDeterministic(t *testing.T) {
+	ix, _, err := Build(context.Background(), &fakeProvider{model: "m"}, testChunks(), nil, 0)
+	require.NoError(t, err)
+
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	require.NoError(t, ix.Write(dir1))
+	require.NoError(t, ix.Write(dir2))
+	for _, name := range []string{MetaFile, VectorsFile} {
+		a, err := os.ReadFile(filepath.Join(dir1, name))
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(dir2, name))
+		require.NoError(t, err)
+		assert.Equal(t, string(a), string(b), name)
+	}
+
+	loaded, err := Load(dir1)
+	require.NoError(t, err)
+	assert.Equal(t, ix, loaded)
+
+	_, err = Load(t.TempDir())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestIndex_Search(t *testing.T) {
+	ix, _, err := Build(context.Background(), &fakeProvider{model: "m"}, testChunks(), nil, 0)
+	require.NoError(t, err)
+
+	hits, err := ix.Search([]float32{4, 1}, 2)
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "a.go", hits[0].FilePath)
+	assert.InDelta(t, 1.0, hits[0].Score, 1e-9)
+	assert.Equal(t, "c.go", hits[1].FilePath)
+
+	_, err = ix.Search([]float32{1}, 1)
+	assert.ErrorContains(t, err, "dimensions")
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, DefaultOpenAIModel, req.Model)
+		// Out of order on purpose; the provider must reorder by index.
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(Config{Provider: ProviderOpenAI, BaseURL: srv.URL + "/v1/", APIKey: "secret"})
+	require.NoError(t, err)
+	vectors, err := p.Embed(context.Background(), []string{"x", "y"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+}
+
+func TestOllamaProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		_, _ = w.Write([]byte(`{"embeddings":[[0.5,0.5]]}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(Config{Provider: ProviderOllama, BaseURL: srv.URL})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultOllamaModel, p.Model())
+	vectors, err := p.Embed(context.Background(), []string{"x"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.5, 0.5}}, vectors)
+}
+
+func TestProvider_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(Config{Provider: ProviderOpenAI, BaseURL: srv.URL})
+	require.NoError(t, err)
+	_, err = p.Embed(context.Background(), []string{"x"})
+	assert.ErrorContains(t, err, "401")
+
+	_, err = NewProvider(Config{Provider: "nope"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/projection"
+)
+
+// IndexSchemaVersion is the version of the on-disk vector index layout.
+const IndexSchemaVersion = "1.0"
+
+// DefaultBatchSize is the number of chunks sent per provider request.
+const DefaultBatchSize = 64
+
+// Index file names inside the index directory (.cortex/index).
+const (
+	MetaFile    = "meta.json"
+	VectorsFile = "vectors.ndjson"
+)
+
+// Meta describes how the vectors in an index were produced.
+type Meta struct {
+	SchemaVersion string `json:"schema_version"`
+	Provider      string `json:"provider"`
+	Model         string `json:"model"`
+	Dimensions    int    `json:"dimensions"`
+	Count         int    `json:"count"`
+}
+
+// Entry is one embedded chunk.
+type Entry struct {
+	ID          string    `json:"id"`
+	ContentHash string    `json:"content_hash"`
+	FilePath    string    `json:"file_path"`
+	StartLine   int       `json:"start_line"`
+	EndLine     int       `json:"end_line"`
+	Symbol      string    `json:"symbol,omitempty"`
+	Vector      []float32 `json:"vector"`
+}
+
+// Index is the vector index: meta.json plus vectors.ndjson in chunk order.
+type Index struct {
+	Meta    Meta
+	Entries []Entry
+}
+
+// Stats reports how much of an index was reused from the previous run.
+type Stats struct {
+	Embedded int
+	Reused   int
+}
+
+// Build embeds chunks with p. Vectors from previous are reused for chunks
+// whose content hash is unchanged, as long as provider and model match.
+func Build(ctx context.Context, p Provider, chunks []chunker.Chunk, previous *Index, batchSize int) (*Index, Stats, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	cache := make(map[string][]float32)
+	if previous != nil && previous.Meta.Provider == p.Name() && previous.Meta.Model == p.Model() {
+		for _, e := range previous.Entries {
+			cache[e.ContentHash] = e.Vector
+		}
+	}
+
+	var stats Stats
+	entries := make([]Entry, len(chunks))
+	var pending []int
+	for i, c := range chunks {
+		entries[i] = Entry{
+			ID:          c.ID,
+			ContentHash: c.ContentHash,
+			FilePath:    c.FilePath,
+			StartLine:   c.StartLine,
+			EndLine:     c.EndLine,
+			Symbol:      c.Symbol,
+		}
+		if v, ok := cache[c.ContentHash]; ok {
+			entries[i].Vector = v
+			stats.Reused++
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+
+		texts := make([]string, len(batch))
+		for j, i := range batch {
+			texts[j] = chunks[i].Content
+		}
+		vectors, err := p.Embed(ctx, texts)
+		if err != nil {
+			return nil, stats, fmt.Errorf("embedding chunks %d-%d: %w", start+1, end, err)
+		}
+		if len(vectors) != len(batch) {
+			return nil, stats, fmt.Errorf("provider returned %d vectors for %d chunks", len(vectors), len(batch))
+		}
+		for j, i := range batch {
+			entries[i].Vector = vectors[j]
+			cache[chunks[i].ContentHash] = vectors[j]
+		}
+		stats.Embedded += len(batch)
+	}
+
+	dims := 0
+	for _, e := range entries {
+		if dims == 0 {
+			dims = len(e.Vector)
+		}
+		if len(e.Vector) != dims {
+			return nil, stats, fmt.Errorf("chunk %s has %d dimensions, expected %d (rebuild the index after changing models)", e.ID, len(e.Vector), dims)
+		}
+	}
+
+	return &Index{
+		Meta: Meta{
+			SchemaVersion: IndexSchemaVersion,
+			Provider:      p.Name(),
+			Model:         p.Model(),
+			Dimensions:    dims,
+			Count:         len(entries),
+		},
+		Entries: entries,
+	}, stats, nil
+}
+
+// Load reads an index directory. A missing index returns an error satisfying
+// os.IsNotExist.
+func Load(dir string) (*Index, error) {
+	metaData, err := os.ReadFile(filepath.Join(dir, MetaFile)) //nolint:gosec // dir is the index location
+	if err != nil {
+		return nil, err
+	}
+	var ix Index
+	if err := json.Unmarshal(metaData, &ix.Meta); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", MetaFile, err)
+	}
+	if ix.Meta.SchemaVersion != IndexSchemaVersion {
+		return nil, fmt.Errorf("unsupported vector index schema_version %q (expected %q); re-run `cortex context embed`", ix.Meta.SchemaVersion, IndexSchemaVersion)
+	}
+
+	vectors, err := os.ReadFile(filepath.Join(dir, VectorsFile)) //nolint:gosec // dir is the index location
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range bytes.Split(vectors, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("parsing %s line %d: %w", VectorsFile, i+1, err)
+		}
+		ix.Entries = append(ix.Entries, e)
+	}
+	if len(ix.Entries) != ix.Meta.Count {
+		return nil, fmt.Errorf("vector index is inconsistent: meta count %d, %d vectors", ix.Meta.Count, len(ix.Entries))
+	}
+	return &ix, nil
+}
+
+// Write stores the index in dir. Output is byte-identical for identical
+// inputs: entries keep chunk order and vectors use Go's shortest float encoding.
+func (ix *Index) Write(dir string) error {
+	meta, err := json.MarshalIndent(ix.Meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling meta: %w", err)
+	}
+	meta = append(meta, '\n')
+
+	var buf bytes.Buffer
+	for _, e := range ix.Entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshaling vector %s: %w", e.ID, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	// Vectors first so a crash never leaves meta pointing at stale vectors of another count.
+	if err := projection.AtomicWrite(filepath.Join(dir, VectorsFile), buf.Bytes()); err != nil {
+		return err
+	}
+	return projection.AtomicWrite(filepath.Join(dir, MetaFile), meta)
+}
+
+// Hit is a search result.
+type Hit struct {
+	ID        string  `json:"id"`
+	FilePath  string  `json:"file_path"`
+	StartLine int     `json:"start_line"`
+	EndLine   int     `json:"end_line"`
+	Symbol    string  `json:"symbol,omitempty"`
+	Score     float64 `json:"score"`
+}
+
+// Search returns the k entries most similar to query by cosine similarity.
+// Ties are broken by file path, then start line, so results are stable.
+func (ix *Index) Search(query []float32, k int) ([]Hit, error) {
+	if len(query) != ix.Meta.Dimensions {
+		return nil, fmt.Errorf("query has %d dimensions, index has %d", len(query), ix.Meta.Dimensions)
+	}
+
+	hits := make([]Hit, 0, len(ix.Entries))
+	for _, e := range ix.Entries {
+		hits = append(hits, Hit{
+			ID:        e.ID,
+			FilePath:  e.FilePath,
+			StartLine: e.StartLine,
+			EndLine:   e.EndLine,
+			Symbol:    e.Symbol,
+			Score:     cosine(query, e.Vector),
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].FilePath != hits[j].FilePath {
+			return hits[i].FilePath < hits[j].FilePath
+		}
+		return hits[i].StartLine < hits[j].StartLine
+	})
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits, nil
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package embed generates embeddings for context chunks and maintains the
+// on-disk vector index used by `cortex context search`.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by NewProvider.
+const (
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+)
+
+// Default endpoints and models per provider.
+const (
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+	DefaultOpenAIModel   = "text-embedding-3-small"
+	DefaultOllamaBaseURL = "http://localhost:11434"
+	DefaultOllamaModel   = "nomic-embed-text"
+)
+
+// Provider turns texts into embedding vectors.
+// Implementations must return exactly one vector per input, in input order.
+type Provider interface {
+	Name() string
+	Model() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Config selects and configures a provider.
+type Config struct {
+	Provider string
+	Model    string
+	BaseURL  string
+	APIKey   string
+	// Client defaults to an http.Client with a 60s timeout.
+	Client *http.Client
+}
+
+// NewProvider builds the provider named in cfg, filling in defaults.
+func NewProvider(cfg Config) (Provider, error) {
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		return &openAIProvider{
+			baseURL: strings.TrimRight(orDefault(cfg.BaseURL, DefaultOpenAIBaseURL), "/"),
+			model:   orDefault(cfg.Model, DefaultOpenAIModel),
+			apiKey:  cfg.APIKey,
+			client:  client,
+		}, nil
+	case ProviderOllama:
+		return &ollamaProvider{
+			baseURL: strings.TrimRight(orDefault(cfg.BaseURL, DefaultOllamaBaseURL), "/"),
+			model:   orDefault(cfg.Model, DefaultOllamaModel),
+			client:  client,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q (must be %q or %q)", cfg.Provider, ProviderOpenAI, ProviderOllama)
+	}
+}
+
+// openAIProvider talks to any OpenAI-compatible /embeddings endpoint.
+type openAIProvider struct {
+	baseURL string
+	model   string
+	apiKey  string
+	client  *http.Client
+}
+
+func (p *openAIProvider) Name() string  { return ProviderOpenAI }
+func (p *openAIProvider) Model() string { return p.model }
+
+func (p *openAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	req := map[string]any{"model": p.model, "input": texts}
+	if err := postJSON(ctx, p.client, p.baseURL+"/embeddings", p.apiKey, req, &resp); err != nil {
+		return nil, err
+	}
+
+	out := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding response index %d out of range", d.Index)
+		}
+		out[d.Index] = d.Embedding
+	}
+	return out, checkVectors(out)
+}
+
+// ollamaProvider talks to a local ollama server's /api/embed endpoint.
+type ollamaProvider struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func (p *ollamaProvider) Name() string  { return ProviderOllama }
+func (p *ollamaProvider) Model() string { return p.model }
+
+func (p *ollamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	req := map[string]any{"model": p.model, "input": texts}
+	if err := postJSON(ctx, p.client, p.baseURL+"/api/embed", "", req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(resp.Embeddings), len(texts))
+	}
+	return resp.Embeddings, checkVectors(resp.Embeddings)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response from %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return nil
+}
+
+func checkVectors(vectors [][]float32) error {
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return fmt.Errorf("missing embedding for input %d", i)
+		}
+		if len(v) != len(vectors[0]) {
+			return fmt.Errorf("inconsistent embedding dimensions: %d vs %d", len(v), len(vectors[0]))
+		}
+	}
+	return nil
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package envcheck cross-checks the environment variables a repository uses
+// against the ones documented in its .env.example files.
+//
+// Variables are used when compose files interpolate them (${VAR}, $VAR) or
+// pass them through from the host (an environment entry without a value),
+// or when Go code reads them with os.Getenv or os.LookupEnv.
+package envcheck
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Ref is one place a variable is used or documented. Path is repo-relative.
+type Ref struct {
+	Name string
+	Path string
+	Line int
+}
+
+// DefaultIgnore lists variables set by the OS or shell that need no
+// documentation.
+var DefaultIgnore = []string{"HOME", "HOSTNAME", "LANG", "PATH", "PWD", "SHELL", "TERM", "TMPDIR", "USER"}
+
+var (
+	nameRE      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	interpRE    = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)|\$([A-Za-z_][A-Za-z0-9_]*)`)
+	getenvRE    = regexp.MustCompile(`\bos\.(?:Getenv|LookupEnv)\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`)
+	exampleLine = regexp.MustCompile(`^#?\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=`)
+)
+
+// IsComposeFile reports whether the file is a Compose file:
+// compose.yaml, docker-compose.yml and their compose.<env>.yaml variants.
+func IsComposeFile(p string) bool {
+	base := path.Base(p)
+	ext := path.Ext(base)
+	if ext != ".yml" && ext != ".yaml" {
+		return false
+	}
+	name := strings.TrimSuffix(base, ext)
+	for _, prefix := range []string{"compose", "docker-compose"} {
+		if name == prefix || strings.HasPrefix(name, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExampleFile reports whether the file is a .env.example file.
+func IsExampleFile(p string) bool {
+	return path.Base(p) == ".env.example"
+}
+
+// ParseExample returns the variables documented in a .env.example file.
+// Commented-out assignments ("# DEBUG=1") document optional variables.
+func ParseExample(p string, data []byte) []Ref {
+	var refs []Ref
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		if m := exampleLine.FindStringSubmatch(strings.TrimSpace(sc.Text())); m != nil {
+			refs = append(refs, Ref{Name: m[1], Path: p, Line: n})
+		}
+	}
+	return refs
+}
+
+// ParseCompose returns the variables a compose file interpolates or passes
+// through from the host. "$$" escapes are not references.
+func ParseCompose(p string, data []byte) []Ref {
+	var refs []Ref
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.ReplaceAll(sc.Text(), "$$", "")
+		if i := strings.Index(line, "#"); i >= 0 && strings.TrimSpace(line[:i]) == "" {
+			continue
+		}
+		for _, m := range interpRE.FindAllStringSubmatch(line, -1) {
+			refs = append(refs, Ref{Name: m[1] + m[2], Path: p, Line: n})
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return refs
+	}
+	services := mapValue(doc.Content[0], "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return refs
+	}
+	for i := 1; i < len(services.Content); i += 2 {
+		env := mapValue(services.Content[i], "environment")
+		if env == nil {
+			continue
+		}
+		switch env.Kind {
+		case yaml.SequenceNode:
+			for _, item := range env.Content {
+				if nameRE.MatchString(item.Value) {
+					refs = append(refs, Ref{Name: item.Value, Path: p, Line: item.Line})
+				}
+			}
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(env.Content); j += 2 {
+				key, val := env.Content[j], env.Content[j+1]
+				if val.Tag == "!!null" && val.Value == "" && nameRE.MatchString(key.Value) {
+					refs = append(refs, Ref{Name: key.Value, Path: p, Line: key.Line})
+				}
+			}
+		}
+	}
+	return refs
+}
+
+func mapValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ParseGo returns the variables Go source reads with a literal name.
+func ParseGo(p string, data []byte) []Ref {
+	var refs []Ref
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		for _, m := range getenvRE.FindAllStringSubmatch(sc.Text(), -1) {
+			refs = append(refs, Ref{Name: m[1], Path: p, Line: n})
+		}
+	}
+	return refs
+}
+
+// Report is the outcome of a check.
+type Report struct {
+	// Undocumented are the first use of each variable missing from every
+	// .env.example.
+	Undocumented []Ref
+	// Unused are documented variables that nothing uses.
+	Unused []Ref
+}
+
+// Check compares used against documented variables. Variables in ignore
+// are neither undocumented nor unused. Both lists are sorted by path, line
+// and name.
+func Check(used, documented []Ref, ignore []string) Report {
+	skip := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		skip[name] = true
+	}
+	usedSet := map[string]bool{}
+	for _, r := range used {
+		usedSet[r.Name] = true
+	}
+	docSet := map[string]bool{}
+	for _, r := range documented {
+		docSet[r.Name] = true
+	}
+
+	var rep Report
+	reported := map[string]bool{}
+	for _, r := range sorted(used) {
+		if !docSet[r.Name] && !skip[r.Name] && !reported[r.Name] {
+			reported[r.Name] = true
+			rep.Undocumented = append(rep.Undocumented, r)
+		}
+	}
+	for _, r := range sorted(documented) {
+		if !usedSet[r.Name] && !skip[r.Name] {
+			rep.Unused = append(rep.Unused, r)
+		}
+	}
+	return rep
+}
+
+func sorted(refs []Ref) []Ref {
+	out := append([]Ref(nil), refs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		if out[i].Line != out[j].Line {
+			return out[i].Line < out[j].Line
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package envcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsComposeFile(t *testing.T) {
+	for _, p := range []string{"compose.yaml", "docker-compose.yml", "deploy/docker-compose.prod.yml", "compose.override.yaml"} {
+		assert.True(t, IsComposeFile(p), p)
+	}
+	for _, p := range []string{"composer.json", "compose.md", "mycompose.yml", "docker-compose"} {
+		assert.False(t, IsComposeFile(p), p)
+	}
+}
+
+func TestParseExample(t *testing.T) {
+	refs := ParseExample(".env.example", []byte("# Database\nDB_URL=postgres://\nexport API_KEY=\n# DEBUG=1\n# just a comment\nbad line\n"))
+	assert.Equal(t, []Ref{
+		{Name: "DB_URL", Path: ".env.example", Line: 2},
+		{Name: "API_KEY", Path: ".env.example", Line: 3},
+		{Name: "DEBUG", Path: ".env.example", Line: 4},
+	}, refs)
+}
+
+func TestParseCompose(t *testing.T) {
+	data := []byte(`services:
+  api:
+    image: "api:${TAG:-latest}"
+    command: sh -c 'echo $$HOME'
+    environment:
+      - DB_URL
+      - LOG_LEVEL=info
+  web:
+    # port: ${COMMENTED}
+    ports: ["$WEB_PORT:80"]
+    environment:
+      API_KEY:
+      MODE: prod
+`)
+	assert.Equal(t, []Ref{
+		{Name: "TAG", Path: "compose.yaml", Line: 3},
+		{Name: "WEB_PORT", Path: "compose.yaml", Line: 10},
+		{Name: "DB_URL", Path: "compose.yaml", Line: 6},
+		{Name: "API_KEY", Path: "compose.yaml", Line: 12},
+	}, ParseCompose("compose.yaml", data))
+}
+
+func TestParseGo(t *testing.T) {
+	data := []byte("package main\n\nfunc f() {\n\t_ = os.Getenv(\"DB_URL\")\n\tv, ok := os.LookupEnv( \"API_KEY\" )\n\t_ = os.Getenv(name)\n}\n")
+	assert.Equal(t, []Ref{
+		{Name: "DB_URL", Path: "main.go", Line: 4},
+		{Name: "API_KEY", Path: "main.go", Line: 5},
+	}, ParseGo("main.go", data))
+}
+
+func TestCheck(t *testing.T) {
+	used := []Ref{
+		{Name: "DB_URL", Path: "main.go", Line: 9},
+		{Name: "SECRET", Path: "main.go", Line: 12},
+		{Name: "SECRET", Path: "compose.yaml", Line: 4},
+		{Name: "HOME", Path: "main.go", Line: 3},
+	}
+	documented := []Ref{
+		{Name: "DB_URL", Path: ".env.example", Line: 1},
+		{Name: "LEGACY", Path: ".env.example", Line: 2},
+		{Name: "PATH", Path: ".env.example", Line: 3},
+	}
+	rep := Check(used, documented, DefaultIgnore)
+	assert.Equal(t, []Ref{{Name: "SECRET", Path: "compose.yaml", Line: 4}}, rep.Undocumented)
+	assert.Equal(t, []Ref{{Name: "LEGACY", Path: ".env.example", Line: 2}}, rep.Unused)
+}
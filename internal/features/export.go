@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package features
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph output formats for `features graph --format`.
+const (
+	FormatText    = "text"
+	FormatDOT     = "dot"
+	FormatJSON    = "json"
+	FormatMermaid = "mermaid"
+)
+
+// GraphJSON is the `features graph --format json` document.
+type GraphJSON struct {
+	Nodes []GraphJSONNode `json:"nodes"`
+	Edges []GraphJSONEdge `json:"edges"`
+}
+
+// GraphJSONNode is a feature with its status metadata.
+type GraphJSONNode struct {
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Governance     string `json:"governance"`
+	Implementation string `json:"implementation"`
+	Spec           string `json:"spec"`
+	Owner          string `json:"owner"`
+}
+
+// GraphJSONEdge points from a dependency to the feature that depends on it,
+// matching the DOT output.
+type GraphJSONEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ToJSON renders the graph as indented JSON with nodes sorted by ID and
+// edges sorted by (from, to).
+func ToJSON(g *Graph) (string, error) {
+	data, err := json.MarshalIndent(NewGraphJSON(g), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling graph: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// NewGraphJSON returns the JSON document of the graph, with nodes sorted by
+// ID and edges sorted by (from, to).
+func NewGraphJSON(g *Graph) GraphJSON {
+	out := GraphJSON{Nodes: []GraphJSONNode{}, Edges: []GraphJSONEdge{}}
+	for _, id := range sortedNodeIDs(g) {
+		n := g.Nodes[id]
+		out.Nodes = append(out.Nodes, GraphJSONNode{
+			ID:             n.ID,
+			Title:          n.Title,
+			Governance:     n.Governance,
+			Implementation: n.Implementation,
+			Spec:           n.Spec,
+			Owner:          n.Owner,
+		})
+		for _, dep := range n.DependsOn {
+			out.Edges = append(out.Edges, GraphJSONEdge{From: dep, To: id})
+		}
+	}
+	sort.Slice(out.Edges, func(i, j int) bool {
+		if out.Edges[i].From != out.Edges[j].From {
+			return out.Edges[i].From < out.Edges[j].From
+		}
+		return out.Edges[i].To < out.Edges[j].To
+	})
+	return out
+}
+
+// ToMermaid renders the graph as a Mermaid flowchart. Nodes are styled by
+// implementation status with the same colors as ToDOT.
+func ToMermaid(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	nodeIDs := sortedNodeIDs(g)
+	classes := map[string][]string{}
+	for _, id := range nodeIDs {
+		node := g.Nodes[id]
+		impl := orDash(node.Implementation)
+		label := fmt.Sprintf("%s<br/>impl=%s, gov=%s", id, impl, orDash(node.Governance))
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", mermaidID(id), strings.ReplaceAll(label, `"`, "#quot;")))
+		classes[impl] = append(classes[impl], mermaidID(id))
+	}
+
+	for _, id := range nodeIDs {
+		deps := append([]string(nil), g.Nodes[id].DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			sb.WriteString(fmt.Sprintf("  %s --> %s\n", mermaidID(dep), mermaidID(id)))
+		}
+	}
+
+	statuses := make([]string, 0, len(classes))
+	for status := range classes {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		class := "status_" + mermaidID(status)
+		sb.WriteString(fmt.Sprintf("  classDef %s fill:%s\n", class, getStatusColor(status)))
+		sb.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(classes[status], ","), class))
+	}
+	return sb.String()
+}
+
+func sortedNodeIDs(g *Graph) []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// mermaidID maps s to a Mermaid-safe identifier (letters, digits, _).
+func mermaidID(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -15,8 +15,10 @@ See https://www.gnu.org/licenses/ for license details.
 package features
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -303,3 +305,105 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func exportGraph() *Graph {
+	g := NewGraph()
+	g.AddNode(&FeatureNode{ID: "B", Title: "Say \"B\"", Implementation: "wip", Governance: "review", DependsOn: []string{"A"}})
+	g.AddNode(&FeatureNode{ID: "A", Title: "A", Implementation: "done", Governance: "approved"})
+	g.AddNode(&FeatureNode{ID: "C", Implementation: "done", DependsOn: []string{"B", "A"}})
+	return g
+}
+
+func TestToJSON(t *testing.T) {
+	out, err := ToJSON(exportGraph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc GraphJSON
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Nodes) != 3 || doc.Nodes[0].ID != "A" || doc.Nodes[1].Implementation != "wip" {
+		t.Errorf("unexpected nodes: %+v", doc.Nodes)
+	}
+	want := []GraphJSONEdge{{From: "A", To: "B"}, {From: "A", To: "C"}, {From: "B", To: "C"}}
+	if !reflect.DeepEqual(doc.Edges, want) {
+		t.Errorf("edges = %+v, want %+v", doc.Edges, want)
+	}
+}
+
+func TestToMermaid(t *testing.T) {
+	got := ToMermaid(exportGraph())
+	want := `graph LR
+  A["A<br/>impl=done, gov=approved"]
+  B["B<br/>impl=wip, gov=review"]
+  C["C<br/>impl=done, gov=-"]
+  A --> B
+  A --> C
+  B --> C
+  classDef status_done fill:lightgreen
+  class A,C status_done
+  classDef status_wip fill:lightyellow
+  class B status_wip
+`
+	if got != want {
+		t.Errorf("ToMermaid() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func chainGraph() *Graph {
+	g := NewGraph()
+	for _, n := range []*FeatureNode{
+		{ID: "CORE", Implementation: "done"},
+		{ID: "A", Implementation: "todo", DependsOn: []string{"CORE"}},
+		{ID: "B", Implementation: "wip", DependsOn: []string{"A"}},
+		{ID: "C", Implementation: "todo", DependsOn: []string{"B", "X"}},
+		{ID: "X", Implementation: "todo", DependsOn: []string{"CORE"}},
+		{ID: "Y", Implementation: "todo", DependsOn: []string{"X"}},
+	} {
+		g.AddNode(n)
+	}
+	for _, n := range g.Nodes {
+		for _, dep := range n.DependsOn {
+			g.AddEdge(n.ID, dep)
+		}
+	}
+	return g
+}
+
+func TestImpactWith_UpstreamAndDepth(t *testing.T) {
+	g := chainGraph()
+
+	if got := ImpactWith(g, "C", ImpactOptions{Upstream: true}); !reflect.DeepEqual(got, []string{"A", "B", "CORE", "X"}) {
+		t.Errorf("upstream = %v", got)
+	}
+	if got := ImpactWith(g, "C", ImpactOptions{Upstream: true, Depth: 1}); !reflect.DeepEqual(got, []string{"B", "X"}) {
+		t.Errorf("upstream depth 1 = %v", got)
+	}
+	if got := ImpactWith(g, "CORE", ImpactOptions{Depth: 1}); !reflect.DeepEqual(got, []string{"A", "X"}) {
+		t.Errorf("downstream depth 1 = %v", got)
+	}
+	if got := ImpactWith(g, "CORE", ImpactOptions{}); !reflect.DeepEqual(got, Impact(g, "CORE")) || len(got) != 5 {
+		t.Errorf("downstream = %v", got)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := chainGraph()
+	if got := CriticalPath(g); !reflect.DeepEqual(got, []string{"A", "B", "C"}) {
+		t.Errorf("CriticalPath() = %v", got)
+	}
+
+	// Equal lengths resolve to the lexicographically smallest chain.
+	g.Nodes["B"].Implementation = "done"
+	if got := CriticalPath(g); !reflect.DeepEqual(got, []string{"X", "C"}) {
+		t.Errorf("CriticalPath() after B done = %v", got)
+	}
+
+	for _, n := range g.Nodes {
+		n.Implementation = "done"
+	}
+	if got := CriticalPath(g); len(got) != 0 {
+		t.Errorf("CriticalPath() with everything done = %v", got)
+	}
+}
@@ -20,16 +20,58 @@ import "sort"
 // This is the "impact analysis" - if feature ID changes, which features are affected?
 // Results are sorted lexicographically for deterministic output.
 func Impact(g *Graph, featureID string) []string {
+	return ImpactWith(g, featureID, ImpactOptions{})
+}
+
+// ImpactOptions controls ImpactWith.
+type ImpactOptions struct {
+	// Upstream follows depends_on instead: the transitive prerequisites of
+	// the feature rather than its dependents.
+	Upstream bool
+	// Depth limits how many hops are followed; 0 means unlimited.
+	Depth int
+}
+
+// ImpactWith returns the features reachable from featureID in the direction
+// and up to the depth given by opts, sorted lexicographically.
+func ImpactWith(g *Graph, featureID string, opts ImpactOptions) []string {
 	if _, exists := g.Nodes[featureID]; !exists {
 		return nil
 	}
 
-	impacted := make(map[string]bool)
-	collectImpacted(g, featureID, impacted)
+	next := func(id string) []string {
+		// Edges point from dependency to dependent.
+		return g.Edges[id]
+	}
+	if opts.Upstream {
+		next = func(id string) []string {
+			if node := g.Nodes[id]; node != nil {
+				return node.DependsOn
+			}
+			return nil
+		}
+	}
+
+	// Breadth-first so that Depth counts shortest distances.
+	visited := map[string]bool{featureID: true}
+	frontier := []string{featureID}
+	for depth := 1; len(frontier) > 0 && (opts.Depth == 0 || depth <= opts.Depth); depth++ {
+		var following []string
+		for _, id := range frontier {
+			for _, n := range next(id) {
+				if _, known := g.Nodes[n]; known && !visited[n] {
+					visited[n] = true
+					following = append(following, n)
+				}
+			}
+		}
+		frontier = following
+	}
+	delete(visited, featureID)
 
 	// Convert to slice and sort for deterministic output
-	result := make([]string, 0, len(impacted))
-	for id := range impacted {
+	result := make([]string, 0, len(visited))
+	for id := range visited {
 		result = append(result, id)
 	}
 	sort.Strings(result)
@@ -37,15 +79,65 @@ func Impact(g *Graph, featureID string) []string {
 	return result
 }
 
-// collectImpacted recursively collects all features that depend on the given feature.
-func collectImpacted(g *Graph, featureID string, visited map[string]bool) {
-	// Get all features that depend on this one (edges point from dependency to dependent)
-	dependents := g.Edges[featureID]
-	for _, dependentID := range dependents {
-		if !visited[dependentID] {
-			visited[dependentID] = true
-			// Recursively collect features that depend on this dependent
-			collectImpacted(g, dependentID, visited)
+// CriticalPath returns the longest dependency chain made only of features
+// whose implementation is not "done", ordered from prerequisite to
+// dependent. Among chains of equal length the lexicographically smallest
+// is returned. g must be acyclic (see ValidateDAG).
+func CriticalPath(g *Graph) []string {
+	open := func(id string) bool {
+		node := g.Nodes[id]
+		return node != nil && node.Implementation != "done"
+	}
+
+	// ending[id] is the best chain that ends at id.
+	ending := make(map[string][]string)
+	var chainTo func(id string) []string
+	chainTo = func(id string) []string {
+		if chain, ok := ending[id]; ok {
+			return chain
+		}
+		var best []string
+		for _, dep := range g.Nodes[id].DependsOn {
+			if !open(dep) {
+				continue
+			}
+			if candidate := chainTo(dep); betterChain(candidate, best) {
+				best = candidate
+			}
+		}
+		chain := append(append([]string(nil), best...), id)
+		ending[id] = chain
+		return chain
+	}
+
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var best []string
+	for _, id := range ids {
+		if !open(id) {
+			continue
+		}
+		if candidate := chainTo(id); betterChain(candidate, best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// betterChain reports whether a is longer than b, or equally long and
+// lexicographically smaller.
+func betterChain(a, b []string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
 		}
 	}
+	return false
 }
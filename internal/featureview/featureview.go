@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package featureview serves a read-only web UI rendering the feature DAG
+// of spec/features.yaml: a single embedded page (no build toolchain) that
+// lays out the graph.json document, with zoom, filters by status, owner
+// and phase, and links to the specs.
+//
+// Feature: CLI_COMMAND_FEATURES
+// Spec: spec/cli/features.md
+package featureview
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/reports/roadmap"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Document is the graph.json document: the `features graph --format json`
+// document with the phase of each feature.
+type Document struct {
+	Nodes []Node                   `json:"nodes"`
+	Edges []features.GraphJSONEdge `json:"edges"`
+}
+
+// Node is a feature of the graph with its roadmap phase.
+type Node struct {
+	features.GraphJSONNode
+	Phase string `json:"phase"`
+}
+
+// Handler serves the UI. The registry is read on every request, so edits
+// show on reload.
+type Handler struct {
+	repoRoot     string
+	featuresPath string
+	mux          *http.ServeMux
+}
+
+// New returns the handler of the registry at featuresPath. Spec paths are
+// resolved against repoRoot.
+func New(repoRoot, featuresPath string) *Handler {
+	h := &Handler{repoRoot: repoRoot, featuresPath: featuresPath, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /{$}", h.handleIndex)
+	h.mux.HandleFunc("GET /graph.json", h.handleGraph)
+	h.mux.HandleFunc("GET /spec/{path...}", h.handleSpec)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Load reads the registry, validates the DAG and assigns the roadmap phases
+// (see `cortex status roadmap`).
+func Load(featuresPath string) (*Document, error) {
+	g, err := features.LoadGraph(featuresPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := features.ValidateDAG(g); err != nil {
+		return nil, err
+	}
+	phases, err := roadmap.DetectPhases(featuresPath)
+	if err != nil {
+		return nil, err
+	}
+	phaseOf := map[string]string{}
+	for _, p := range phases {
+		for _, f := range p.Features {
+			phaseOf[f.ID] = p.Name
+		}
+	}
+
+	doc := features.NewGraphJSON(g)
+	out := &Document{Nodes: make([]Node, 0, len(doc.Nodes)), Edges: doc.Edges}
+	for _, n := range doc.Nodes {
+		out.Nodes = append(out.Nodes, Node{GraphJSONNode: n, Phase: phaseOf[n.ID]})
+	}
+	return out, nil
+}
+
+func (h *Handler) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(indexHTML)
+}
+
+func (h *Handler) handleGraph(w http.ResponseWriter, _ *http.Request) {
+	doc, err := Load(h.featuresPath)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// handleSpec serves the spec of a feature; other paths are not found.
+func (h *Handler) handleSpec(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean(r.PathValue("path"))
+	doc, err := Load(h.featuresPath)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	for _, n := range doc.Nodes {
+		if n.Spec == "" || path.Clean(n.Spec) != name {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(h.repoRoot, filepath.FromSlash(name))) //nolint:gosec // G304: name is a spec path of the registry
+		if err != nil {
+			break
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write(data)
+		return
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"error": "spec not found"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
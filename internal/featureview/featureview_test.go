@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package featureview
+
+// Feature: CLI_COMMAND_FEATURES
+// Spec: spec/cli/features.md
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const registry = `features:
+  - id: CORE
+    title: Core
+    governance: approved
+    implementation: done
+    spec: spec/core.md
+    owner: alice
+  - id: CLI
+    title: CLI
+    governance: draft
+    implementation: wip
+    spec: spec/cli.md
+    owner: bob
+    depends_on: [CORE]
+`
+
+func newHandler(t *testing.T) *Handler {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "spec"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "spec", "features.yaml"), []byte(registry), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "spec", "core.md"), []byte("# Core\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret\n"), 0o644))
+	return New(root, filepath.Join(root, "spec", "features.yaml"))
+}
+
+func get(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestHandler_Index(t *testing.T) {
+	rec := get(newHandler(t), http.MethodGet, "/")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `fetch("graph.json")`)
+}
+
+func TestHandler_Graph(t *testing.T) {
+	rec := get(newHandler(t), http.MethodGet, "/graph.json")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc Document
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	require.Len(t, doc.Nodes, 2)
+	assert.Equal(t, "CLI", doc.Nodes[0].ID)
+	assert.Equal(t, "bob", doc.Nodes[0].Owner)
+	assert.NotEmpty(t, doc.Nodes[0].Phase)
+	assert.NotEmpty(t, doc.Nodes[1].Phase)
+	require.Len(t, doc.Edges, 1)
+	assert.Equal(t, "CORE", doc.Edges[0].From)
+	assert.Equal(t, "CLI", doc.Edges[0].To)
+}
+
+func TestHandler_Spec(t *testing.T) {
+	h := newHandler(t)
+
+	rec := get(h, http.MethodGet, "/spec/spec/core.md")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "# Core\n", rec.Body.String())
+
+	// Missing specs and files that are not a feature spec are not served.
+	for _, target := range []string{"/spec/spec/cli.md", "/spec/secret.txt"} {
+		assert.Equal(t, http.StatusNotFound, get(h, http.MethodGet, target).Code, target)
+	}
+	rec = get(h, http.MethodGet, "/spec/spec/../secret.txt")
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	assert.Equal(t, "/spec/secret.txt", rec.Header().Get("Location"))
+}
+
+func TestHandler_ReadOnly(t *testing.T) {
+	assert.Equal(t, http.StatusMethodNotAllowed, get(newHandler(t), http.MethodPost, "/graph.json").Code)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package findings
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bartekus/cortex/internal/rules"
+)
+
+// CodeClimateIssue is one entry of a Code Climate / GitLab code quality
+// report.
+type CodeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    CodeClimateLocation `json:"location"`
+}
+
+// CodeClimateLocation points at the first line of a finding.
+type CodeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeClimateLines `json:"lines"`
+}
+
+// CodeClimateLines holds the line range of a location.
+type CodeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// CodeClimate converts findings into code quality issues, keeping their
+// order. The check name is the rule ID, and the severity is "major" for
+// rules of severity error and "minor" for warnings. Consumers require a
+// location, so findings without a path are reported at the repository root
+// and findings without a line at line 1. Identical findings are reported
+// once since fingerprints must be unique.
+func CodeClimate(fs []Finding) []CodeClimateIssue {
+	out := make([]CodeClimateIssue, 0, len(fs))
+	seen := map[string]bool{}
+	for _, f := range fs {
+		sum := md5.Sum(fmt.Appendf(nil, "%s\x00%s\x00%d\x00%s", f.Skill, f.Path, f.Line, f.Message))
+		fingerprint := hex.EncodeToString(sum[:])
+		if seen[fingerprint] {
+			continue
+		}
+		seen[fingerprint] = true
+
+		severity := "major"
+		if r, ok := rules.Lookup(f.RuleID()); ok && r.Severity == rules.SeverityWarning {
+			severity = "minor"
+		}
+		loc := CodeClimateLocation{Path: f.Path, Lines: CodeClimateLines{Begin: max(f.Line, 1)}}
+		if loc.Path == "" {
+			loc.Path = "."
+		}
+		out = append(out, CodeClimateIssue{
+			Type:        "issue",
+			CheckName:   f.RuleID(),
+			Description: f.Message,
+			Categories:  []string{"Style"},
+			Severity:    severity,
+			Fingerprint: fingerprint,
+			Location:    loc,
+		})
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package findings extracts file-level findings from skill result notes.
+//
+// Skills report failures as free-form notes; most put one finding per line
+// as "path:line[:col]: message" or "path: message". Lines that do not start
+// with a repository path are kept as skill-level findings without a location.
+package findings
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/rules"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Finding is a single problem reported by a skill. Path is repo-relative and
+// slash separated; Line is 0 when the finding has no line.
+type Finding struct {
+	Skill   string
+	Path    string
+	Line    int
+	Message string
+}
+
+// RuleID returns the ID of the rule of the registry f violates (see
+// rules.Classify).
+func (f Finding) RuleID() string {
+	return rules.Classify(f.Skill, f.Message)
+}
+
+// ID identifies a finding across runs: "<rule>#<8 hex digits>", the digits
+// from a SHA-256 of the skill, path and message. Module variants share the
+// ID of their skill, and the line is left out so edits elsewhere in the
+// file do not change it. `cortex explain` resolves the rule part.
+func (f Finding) ID() string {
+	sum := sha256.Sum256([]byte(runner.BaseSkillID(f.Skill) + "\x00" + f.Path + "\x00" + f.Message))
+	return f.RuleID() + "#" + hex.EncodeToString(sum[:4])
+}
+
+var (
+	withLine = regexp.MustCompile(`^([^\s:]+):(\d+)(?::\d+)?:\s*(.+)$`)
+	withPath = regexp.MustCompile(`^([^\s:]+):\s+(.+)$`)
+)
+
+// FromResults returns the findings of failed (or timed out) results, sorted by path, line,
+// skill and message. Absolute paths under repoRoot are made relative.
+func FromResults(repoRoot string, results []runner.SkillResult) []Finding {
+	var out []Finding
+	for _, r := range results {
+		if !r.Status.Failed() {
+			continue
+		}
+		for _, line := range strings.Split(r.Note, "\n") {
+			if f, ok := Parse(repoRoot, line); ok {
+				f.Skill = r.Skill
+				out = append(out, f)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Skill != b.Skill {
+			return a.Skill < b.Skill
+		}
+		return a.Message < b.Message
+	})
+	return out
+}
+
+// Parse turns one note line into a finding. Blank lines are skipped.
+func Parse(repoRoot, line string) (Finding, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Finding{}, false
+	}
+
+	if m := withLine.FindStringSubmatch(line); m != nil {
+		if p, ok := repoPath(repoRoot, m[1]); ok {
+			n, _ := strconv.Atoi(m[2])
+			return Finding{Path: p, Line: n, Message: m[3]}, true
+		}
+	}
+	if m := withPath.FindStringSubmatch(line); m != nil {
+		if p, ok := repoPath(repoRoot, m[1]); ok {
+			return Finding{Path: p, Message: m[2]}, true
+		}
+	}
+	return Finding{Message: line}, true
+}
+
+// repoPath reports whether s looks like a file path (it has a directory or
+// an extension) and returns it relative to repoRoot.
+func repoPath(repoRoot, s string) (string, bool) {
+	s = strings.ReplaceAll(s, "\\", "/")
+	if root := strings.ReplaceAll(repoRoot, "\\", "/"); root != "" && strings.HasPrefix(s, root+"/") {
+		s = strings.TrimPrefix(s, root+"/")
+	}
+	if path.IsAbs(s) || strings.HasPrefix(s, "../") {
+		return "", false
+	}
+	if !strings.Contains(s, "/") && path.Ext(s) == "" {
+		return "", false
+	}
+	return path.Clean(s), true
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package findings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Finding
+	}{
+		{"internal/a.go:12:5: unused variable", Finding{Path: "internal/a.go", Line: 12, Message: "unused variable"}},
+		{"/repo/cmd/main.go:3: bad import", Finding{Path: "cmd/main.go", Line: 3, Message: "bad import"}},
+		{"README.md: missing header", Finding{Path: "README.md", Message: "missing header"}},
+		{"coverage 41% below threshold 60%", Finding{Message: "coverage 41% below threshold 60%"}},
+		{"error: exit status 1", Finding{Message: "error: exit status 1"}},
+		{"../outside.go:1: nope", Finding{Message: "../outside.go:1: nope"}},
+	}
+	for _, tt := range tests {
+		got, ok := Parse("/repo", tt.line)
+		require.True(t, ok, tt.line)
+		assert.Equal(t, tt.want, got, tt.line)
+	}
+
+	_, ok := Parse("/repo", "   ")
+	assert.False(t, ok)
+}
+
+func TestFromResults(t *testing.T) {
+	results := []runner.SkillResult{
+		{Skill: "lint:golangci", Status: runner.StatusFail, Note: "b.go:2: x\n\na/c.go:9: y"},
+		{Skill: "purity", Status: runner.StatusPass, Note: "a/c.go:1: ignored"},
+		{Skill: "docs:yaml", Status: runner.StatusFail, Note: "a/c.go:9: z"},
+	}
+
+	got := FromResults("/repo", results)
+	assert.Equal(t, []Finding{
+		{Skill: "docs:yaml", Path: "a/c.go", Line: 9, Message: "z"},
+		{Skill: "lint:golangci", Path: "a/c.go", Line: 9, Message: "y"},
+		{Skill: "lint:golangci", Path: "b.go", Line: 2, Message: "x"},
+	}, got)
+}
+
+func TestFindingID(t *testing.T) {
+	f := Finding{Skill: "test:go@services/api", Path: "a/c.go", Line: 9, Message: "y"}
+	id := f.ID()
+	assert.Regexp(t, `^test:go#[0-9a-f]{8}$`, id)
+	assert.Regexp(t, `^docs:doc-patterns/spaces#[0-9a-f]{8}$`, Finding{Skill: "docs:doc-patterns", Path: "a b.md", Message: "path contains spaces"}.ID())
+
+	moved := f
+	moved.Skill, moved.Line = "test:go", 40
+	assert.Equal(t, id, moved.ID(), "variant and line do not change the ID")
+	other := f
+	other.Message = "z"
+	assert.NotEqual(t, id, other.ID())
+}
+
+func TestCodeClimate(t *testing.T) {
+	fs := []Finding{
+		{Skill: "test:coverage", Message: "41% below 60%"},
+		{Skill: "lint:golangci", Path: "a/c.go", Line: 9, Message: "y"},
+		{Skill: "lint:golangci", Path: "a/c.go", Line: 9, Message: "y"},
+		{Skill: "docs:yaml", Path: "x.yaml", Message: "bad"},
+		{Skill: "compose:env-consistency", Path: ".env.example", Message: "WARNING: FOO is documented but not used"},
+	}
+
+	got := CodeClimate(fs)
+	require.Len(t, got, 4)
+
+	assert.Equal(t, "issue", got[0].Type)
+	assert.Equal(t, "test:coverage", got[0].CheckName)
+	assert.Equal(t, "41% below 60%", got[0].Description)
+	assert.Equal(t, "major", got[0].Severity)
+	assert.Equal(t, CodeClimateLocation{Path: ".", Lines: CodeClimateLines{Begin: 1}}, got[0].Location)
+	assert.Equal(t, CodeClimateLocation{Path: "a/c.go", Lines: CodeClimateLines{Begin: 9}}, got[1].Location)
+	assert.Equal(t, 1, got[2].Location.Lines.Begin)
+	assert.Equal(t, "compose:env-consistency/unused", got[3].CheckName)
+	assert.Equal(t, "minor", got[3].Severity, "warning rules are minor")
+
+	assert.Len(t, got[0].Fingerprint, 32)
+	assert.NotEqual(t, got[0].Fingerprint, got[1].Fingerprint)
+	assert.Equal(t, got, CodeClimate(fs), "fingerprints must be stable")
+	assert.Empty(t, CodeClimate(nil))
+}
+
+func TestSARIF(t *testing.T) {
+	fs := []Finding{
+		{Skill: "test:coverage", Message: "41% below 60%"},
+		{Skill: "lint:golangci", Path: "a/c.go", Line: 9, Message: "y"},
+		{Skill: "compose:env-consistency", Path: ".env.example", Message: "WARNING: FOO is documented but not used"},
+		{Skill: "custom:check", Path: "x.go", Line: 1, Message: "bad"},
+	}
+
+	log := SARIF(fs)
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	driver := log.Runs[0].Tool.Driver
+	assert.Equal(t, "cortex", driver.Name)
+	var ids []string
+	for _, r := range driver.Rules {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"compose:env-consistency/unused", "custom:check", "lint:golangci", "test:coverage"}, ids)
+	assert.Equal(t, "warning", driver.Rules[0].DefaultConfiguration.Level)
+	assert.Equal(t, "compose:env-consistency", driver.Rules[0].Properties["skill"])
+	assert.Nil(t, driver.Rules[1].ShortDescription, "rules missing from the registry are bare")
+	assert.Equal(t, true, driver.Rules[2].Properties["fixable"])
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 4)
+	assert.Equal(t, "test:coverage", results[0].RuleID)
+	assert.Equal(t, 3, results[0].RuleIndex)
+	assert.Empty(t, results[0].Locations, "skill-level findings have no location")
+	assert.Equal(t, "a/c.go", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 9, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Nil(t, results[2].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "warning", results[2].Level)
+	assert.Equal(t, fs[1].ID(), results[1].PartialFingerprints["cortex/v1"])
+
+	assert.Empty(t, SARIF(nil).Runs[0].Results)
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package findings
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// GeneratedMarkersSkill is the skill checking generated files for their
+// header; its findings are the only ones kept in generated files.
+const GeneratedMarkersSkill = "docs:generated-markers"
+
+// GeneratedFilter drops the findings skills report in generated files
+// (scanner.Generated): they are fixed in the generator, not in the file.
+type GeneratedFilter struct {
+	repoRoot string
+	gen      *scanner.Generated
+}
+
+// NewGeneratedFilter returns the filter for the repository at repoRoot.
+func NewGeneratedFilter(repoRoot string, gen *scanner.Generated) *GeneratedFilter {
+	return &GeneratedFilter{repoRoot: repoRoot, gen: gen}
+}
+
+// Apply removes the findings in generated files from the note of a failed
+// result. A result whose every note line is such a finding passes. Results
+// that did not fail, including timeouts, and those of docs:generated-markers
+// are returned unchanged.
+func (g *GeneratedFilter) Apply(res runner.SkillResult) runner.SkillResult {
+	if res.Status != runner.StatusFail || runner.BaseSkillID(res.Skill) == GeneratedMarkersSkill {
+		return res
+	}
+	var kept []string
+	skipped := 0
+	for _, line := range strings.Split(res.Note, "\n") {
+		f, ok := Parse(g.repoRoot, line)
+		if !ok {
+			continue
+		}
+		if g.gen.IsGenerated(f.Path) {
+			skipped++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if skipped == 0 {
+		return res
+	}
+
+	summary := fmt.Sprintf("%d finding(s) in generated files skipped", skipped)
+	if len(kept) == 0 {
+		res.Status, res.ExitCode, res.Note = runner.StatusPass, runner.ExitOK, summary
+		return res
+	}
+	res.Note = strings.Join(kept, "\n") + "\n(" + summary + ")"
+	return res
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package findings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+func TestGeneratedFilterApply(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "mocks"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "mocks", "store.go"),
+		[]byte("// Code generated by mockgen. DO NOT EDIT.\npackage mocks\n"), 0o644))
+	g := NewGeneratedFilter(root, scanner.NewGenerated(root, []string{"**/*.pb.go"}))
+
+	res := g.Apply(runner.SkillResult{Skill: "lint:golangci", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "mocks/store.go:2:1: unused x\napi/api.pb.go:9:1: unused y\nmain.go:3:1: unused z"})
+	assert.Equal(t, runner.StatusFail, res.Status)
+	assert.Equal(t, "main.go:3:1: unused z\n(2 finding(s) in generated files skipped)", res.Note)
+
+	res = g.Apply(runner.SkillResult{Skill: "purity", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "api/api.pb.go: banned import \"os/exec\""})
+	assert.Equal(t, runner.SkillResult{Skill: "purity", Status: runner.StatusPass, ExitCode: runner.ExitOK,
+		Note: "1 finding(s) in generated files skipped"}, res)
+
+	markers := runner.SkillResult{Skill: GeneratedMarkersSkill, Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "api/api.pb.go: missing \"Code generated ... DO NOT EDIT.\" header"}
+	assert.Equal(t, markers, g.Apply(markers), "docs:generated-markers reports on generated files")
+
+	pass := runner.SkillResult{Skill: "purity", Status: runner.StatusPass, Note: "api/api.pb.go: ok"}
+	assert.Equal(t, pass, g.Apply(pass))
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package findings
+
+// Feature: CLI_COMMAND_CONFIG
+// Spec: spec/cli/config.md
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/rules"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Overrides applies the rule severities of config.yaml and the nested
+// .cortex.yaml files (see config.RuleSeverity) to skill results.
+type Overrides struct {
+	repoRoot      string
+	root          map[string]string
+	nested        []config.DirConfig
+	failOnWarning bool
+}
+
+// NewOverrides returns the overrides of the root rules and the nested
+// configs, sorted by directory, for the repository at repoRoot. With
+// failOnWarning, findings lowered to warnings still fail their skill.
+func NewOverrides(repoRoot string, root map[string]string, nested []config.DirConfig, failOnWarning bool) *Overrides {
+	return &Overrides{repoRoot: repoRoot, root: root, nested: nested, failOnWarning: failOnWarning}
+}
+
+// severity returns the severity of f and whether a rules entry changed it
+// from the default: that of f's rule in the registry, error for rules
+// missing from it.
+func (o *Overrides) severity(f Finding) (string, bool) {
+	def := config.RuleError
+	if isWarningRule(f) {
+		def = config.RuleWarning
+	}
+	sev, ok := config.RuleSeverity(o.root, o.nested, f.Path, f.RuleID(), runner.BaseSkillID(f.Skill))
+	if !ok || sev == def {
+		return def, false
+	}
+	return sev, true
+}
+
+// Apply changes a result by the overridden severities of its findings. In a failed
+// result, findings turned off are removed from the note, and the result
+// passes when no finding of severity error is left (with failOnWarning it
+// still fails on the warnings, with exit code 3). In a passing result, the
+// warnings raised to errors fail it, and warnings turned off are removed.
+// Results without an overridden finding, skipped ones and timeouts are
+// returned unchanged.
+func (o *Overrides) Apply(res runner.SkillResult) runner.SkillResult {
+	if res.Status != runner.StatusFail && res.Status != runner.StatusPass {
+		return res
+	}
+	var kept []string
+	errs, warnings := 0, 0
+	counts := map[string]int{}
+	for _, line := range strings.Split(res.Note, "\n") {
+		f, ok := Parse(o.repoRoot, line)
+		if !ok {
+			continue
+		}
+		f.Skill = res.Skill
+		// Annotations such as "(Fail on warning)" are not findings, and
+		// passing results only report warnings.
+		if strings.HasPrefix(line, "(") && strings.HasSuffix(line, ")") ||
+			res.Status == runner.StatusPass && !isWarningRule(f) {
+			kept = append(kept, line)
+			continue
+		}
+		sev, overridden := o.severity(f)
+		if overridden {
+			counts[sev]++
+		}
+		switch sev {
+		case config.RuleOff:
+			continue
+		case config.RuleWarning:
+			warnings++
+		default:
+			errs++
+		}
+		kept = append(kept, line)
+	}
+	if len(counts) == 0 {
+		return res
+	}
+
+	var parts []string
+	for _, sev := range []string{config.RuleOff, config.RuleWarning, config.RuleError} {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	summary := "rule overrides: " + strings.Join(parts, ", ")
+
+	switch {
+	case errs > 0 && counts[config.RuleError] > 0:
+		if res.Status == runner.StatusPass || res.ExitCode == runner.ExitWarning {
+			res.Status, res.ExitCode = runner.StatusFail, runner.ExitValidation
+		}
+	case errs > 0:
+	case warnings > 0 && o.failOnWarning:
+		res.Status, res.ExitCode = runner.StatusFail, runner.ExitWarning
+	default:
+		res.Status, res.ExitCode = runner.StatusPass, runner.ExitOK
+	}
+	if len(kept) == 0 {
+		res.Note = summary
+		return res
+	}
+	res.Note = strings.Join(kept, "\n") + "\n(" + summary + ")"
+	return res
+}
+
+// isWarningRule reports whether the rule of f has severity warning in the
+// registry.
+func isWarningRule(f Finding) bool {
+	r, ok := rules.Lookup(f.RuleID())
+	return ok && r.Severity == rules.SeverityWarning
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package findings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: CLI_COMMAND_CONFIG
+// Spec: spec/cli/config.md
+
+func TestOverridesApply(t *testing.T) {
+	nested := []config.DirConfig{
+		{Dir: "services", Rules: map[string]string{"compose:env-consistency/unused": config.RuleError}},
+		{Dir: "tools", Rules: map[string]string{"purity/os-exec": config.RuleOff, "compose:env-consistency": config.RuleWarning}},
+	}
+	ov := NewOverrides(t.TempDir(), map[string]string{"lint:golangci": config.RuleWarning}, nested, false)
+
+	res := ov.Apply(runner.SkillResult{Skill: "purity", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "tools/gen.go: banned import \"os/exec\"\ncmd/x.go: banned import \"os/exec\""})
+	assert.Equal(t, runner.StatusFail, res.Status)
+	assert.Equal(t, "cmd/x.go: banned import \"os/exec\"\n(rule overrides: 1 off)", res.Note)
+
+	res = ov.Apply(runner.SkillResult{Skill: "purity", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "tools/gen.go: banned import \"os/exec\""})
+	assert.Equal(t, runner.SkillResult{Skill: "purity", Status: runner.StatusPass, ExitCode: runner.ExitOK, Note: "rule overrides: 1 off"}, res)
+
+	res = ov.Apply(runner.SkillResult{Skill: "lint:golangci@api", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "api/a.go:3:1: unused x"})
+	assert.Equal(t, runner.StatusPass, res.Status, "findings lowered to warnings pass")
+	assert.Equal(t, "api/a.go:3:1: unused x\n(rule overrides: 1 warning)", res.Note)
+
+	res = NewOverrides(t.TempDir(), map[string]string{"lint:golangci": config.RuleWarning}, nil, true).Apply(
+		runner.SkillResult{Skill: "lint:golangci", Status: runner.StatusFail, ExitCode: runner.ExitValidation, Note: "a.go:3: unused x"})
+	assert.Equal(t, runner.StatusFail, res.Status)
+	assert.Equal(t, runner.ExitWarning, res.ExitCode, "--fail-on-warning fails on lowered findings")
+
+	warn := "services/.env.example:3: WARNING: FOO is documented but not used"
+	res = ov.Apply(runner.SkillResult{Skill: "compose:env-consistency", Status: runner.StatusPass, ExitCode: runner.ExitOK, Note: warn})
+	assert.Equal(t, runner.StatusFail, res.Status, "warnings raised to errors fail")
+	assert.Equal(t, runner.ExitValidation, res.ExitCode)
+	assert.Equal(t, warn+"\n(rule overrides: 1 error)", res.Note)
+
+	res = ov.Apply(runner.SkillResult{Skill: "compose:env-consistency", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "tools/main.go:9: BAR is not documented in .env.example\n.env.example:4: WARNING: BAZ is documented but not used"})
+	assert.Equal(t, runner.StatusPass, res.Status, "only warnings are left")
+
+	for _, unchanged := range []runner.SkillResult{
+		{Skill: "purity", Status: runner.StatusPass, Note: "No banned imports found."},
+		{Skill: "purity", Status: runner.StatusFail, ExitCode: runner.ExitValidation, Note: "cmd/x.go: banned import \"os/exec\""},
+		{Skill: "purity", Status: runner.StatusTimeout, Note: "tools/gen.go: banned import \"os/exec\""},
+		{Skill: "docs:orphan-docs", Status: runner.StatusFail, ExitCode: runner.ExitWarning, Note: "WARNING: x\n(Fail on warning)"},
+	} {
+		assert.Equal(t, unchanged, ov.Apply(unchanged))
+	}
+}
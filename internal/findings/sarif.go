@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package findings
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+import (
+	"sort"
+
+	"github.com/bartekus/cortex/internal/rules"
+)
+
+// SARIFVersion is the SARIF version of SARIF documents.
+const SARIFVersion = "2.1.0"
+
+// SARIFLog is a SARIF document with a single run.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun holds the tool and the results of one run.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes cortex and the rules its results reference.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component reporting the results.
+type SARIFDriver struct {
+	Name  string      `json:"name"`
+	Rules []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is the reporting descriptor of a rule.
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     *SARIFMessage      `json:"shortDescription,omitempty"`
+	FullDescription      *SARIFMessage      `json:"fullDescription,omitempty"`
+	Help                 *SARIFMessage      `json:"help,omitempty"`
+	DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]any     `json:"properties,omitempty"`
+}
+
+// SARIFConfiguration holds the default level of a rule.
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is one finding.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+	// PartialFingerprints holds the finding ID under "cortex/v1".
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+// SARIFLocation points at the file and line of a finding.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a location in a repo-relative file.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation holds a repo-relative, slash separated URI.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion holds the line of a finding.
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIF converts findings into a SARIF document for code scanning, keeping
+// their order. The driver lists the rules the results reference, sorted by
+// ID, with their description, rationale, fix and default severity; a rule
+// missing from the registry (a custom skill) gets a bare descriptor at level
+// error. Findings without a path have no location and findings without a
+// line no region. The finding ID is the partial fingerprint.
+func SARIF(fs []Finding) SARIFLog {
+	ids := map[string]bool{}
+	for _, f := range fs {
+		ids[f.RuleID()] = true
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	driver := SARIFDriver{Name: "cortex", Rules: make([]SARIFRule, 0, len(sorted))}
+	index := map[string]int{}
+	for i, id := range sorted {
+		index[id] = i
+		driver.Rules = append(driver.Rules, sarifRule(id))
+	}
+
+	results := make([]SARIFResult, 0, len(fs))
+	for _, f := range fs {
+		id := f.RuleID()
+		r := SARIFResult{
+			RuleID:              id,
+			RuleIndex:           index[id],
+			Level:               driver.Rules[index[id]].DefaultConfiguration.Level,
+			Message:             SARIFMessage{Text: f.Message},
+			PartialFingerprints: map[string]string{"cortex/v1": f.ID()},
+		}
+		if f.Path != "" {
+			loc := SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: f.Path}}
+			if f.Line > 0 {
+				loc.Region = &SARIFRegion{StartLine: f.Line}
+			}
+			r.Locations = []SARIFLocation{{PhysicalLocation: loc}}
+		}
+		results = append(results, r)
+	}
+
+	return SARIFLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: SARIFVersion,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
+
+func sarifRule(id string) SARIFRule {
+	r, ok := rules.Lookup(id)
+	if !ok {
+		return SARIFRule{ID: id, DefaultConfiguration: SARIFConfiguration{Level: "error"}}
+	}
+	out := SARIFRule{
+		ID:                   id,
+		ShortDescription:     &SARIFMessage{Text: r.Description},
+		FullDescription:      &SARIFMessage{Text: r.Rationale},
+		DefaultConfiguration: SARIFConfiguration{Level: r.Severity},
+		Properties: map[string]any{
+			"skill":   r.Skill,
+			"fixable": r.Fixable,
+			"spec":    r.Spec,
+		},
+	}
+	if r.Fix != "" {
+		out.Help = &SARIFMessage{Text: r.Fix}
+	}
+	return out
+}
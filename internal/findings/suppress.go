@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package findings
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Directive starts an inline suppression: a line comment of the file's
+// language holding the directive, a skill or rule ID, a required
+// reason="..." and an optional expires=YYYY-MM-DD. It suppresses the
+// findings of the skill, or of the rule only, on its own line and on the
+// next line; a finding without a line is suppressed by any suppression of
+// its skill or rule in the file.
+const Directive = "cortex:ignore"
+
+// DateLayout is the layout of the expires attribute.
+const DateLayout = "2006-01-02"
+
+// Suppression statuses.
+const (
+	SuppressionActive  = "active"
+	SuppressionExpired = "expired"
+	SuppressionInvalid = "invalid"
+)
+
+// Suppression is one inline suppression comment. Path is repo-relative and
+// slash separated; Line is the line of the comment.
+type Suppression struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	// Skill is the skill or rule ID the suppression names.
+	Skill   string `json:"skill"`
+	Reason  string `json:"reason"`
+	Expires string `json:"expires,omitempty"`
+	// Error is why the comment is malformed; a malformed suppression never
+	// applies.
+	Error string `json:"error,omitempty"`
+}
+
+// Status returns whether s applies on day today.
+func (s Suppression) Status(today time.Time) string {
+	switch {
+	case s.Error != "":
+		return SuppressionInvalid
+	case s.Expires != "" && today.Format(DateLayout) > s.Expires:
+		return SuppressionExpired
+	default:
+		return SuppressionActive
+	}
+}
+
+// Matches reports whether s covers finding f, regardless of its status.
+func (s Suppression) Matches(f Finding) bool {
+	if f.Path != s.Path || (runner.BaseSkillID(f.Skill) != s.Skill && f.RuleID() != s.Skill) {
+		return false
+	}
+	return f.Line == 0 || f.Line == s.Line || f.Line == s.Line+1
+}
+
+// lineComments maps file extensions (or base names) to their line comment
+// marker. Files of other types carry no suppressions.
+var lineComments = map[string]string{
+	".c": "//", ".cc": "//", ".cpp": "//", ".cs": "//", ".go": "//", ".h": "//", ".java": "//",
+	".js": "//", ".jsx": "//", ".kt": "//", ".proto": "//", ".rs": "//", ".swift": "//",
+	".ts": "//", ".tsx": "//",
+	".bash": "#", ".py": "#", ".rb": "#", ".sh": "#", ".tf": "#", ".toml": "#", ".yaml": "#", ".yml": "#",
+	"Dockerfile": "#", "Makefile": "#",
+	".lua": "--", ".sql": "--",
+}
+
+// commentMarker returns the line comment marker of the file at rel.
+func commentMarker(rel string) (string, bool) {
+	if m, ok := lineComments[path.Base(rel)]; ok {
+		return m, true
+	}
+	m, ok := lineComments[path.Ext(rel)]
+	return m, ok
+}
+
+var (
+	directivePatterns = map[string]*regexp.Regexp{}
+	attribute         = regexp.MustCompile(`^(\w+)=("(?:[^"\\]|\\.)*"|[^\s"]+)(?:\s+|$)`)
+)
+
+func init() {
+	for _, m := range lineComments {
+		if _, ok := directivePatterns[m]; !ok {
+			directivePatterns[m] = regexp.MustCompile(`(?:^|\s)` + regexp.QuoteMeta(m) + `\s*` + regexp.QuoteMeta(Directive) + `(?:\s+(.*))?$`)
+		}
+	}
+}
+
+// ParseSuppressions returns the suppressions in src, the content of the file
+// at rel. Malformed comments are returned with Error set.
+func ParseSuppressions(rel string, src []byte) []Suppression {
+	marker, ok := commentMarker(rel)
+	if !ok || !strings.Contains(string(src), Directive) {
+		return nil
+	}
+	re := directivePatterns[marker]
+
+	var out []Suppression
+	for i, line := range strings.Split(string(src), "\n") {
+		m := re.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		s := Suppression{Path: rel, Line: i + 1}
+		s.Error = parseDirective(&s, strings.TrimSpace(m[1]))
+		out = append(out, s)
+	}
+	return out
+}
+
+// parseDirective fills s from the text after the directive and returns why
+// it is malformed, or "".
+func parseDirective(s *Suppression, text string) string {
+	skill, rest, _ := strings.Cut(text, " ")
+	if skill == "" || strings.Contains(skill, "=") {
+		return "missing skill"
+	}
+	s.Skill = skill
+
+	rest = strings.TrimSpace(rest)
+	for rest != "" {
+		m := attribute.FindStringSubmatch(rest)
+		if m == nil {
+			return fmt.Sprintf("malformed attribute %q", rest)
+		}
+		rest = rest[len(m[0]):]
+		val := m[2]
+		if strings.HasPrefix(val, `"`) {
+			var err error
+			if val, err = strconv.Unquote(val); err != nil {
+				return fmt.Sprintf("malformed attribute %q", m[0])
+			}
+		}
+		switch m[1] {
+		case "reason":
+			s.Reason = strings.TrimSpace(val)
+		case "expires":
+			s.Expires = val
+			if _, err := time.Parse(DateLayout, val); err != nil {
+				return fmt.Sprintf("invalid expires %q: want YYYY-MM-DD", val)
+			}
+		default:
+			return fmt.Sprintf("unknown attribute %q", m[1])
+		}
+	}
+	if s.Reason == "" {
+		return "reason is required"
+	}
+	return ""
+}
+
+// ScanSuppressions returns the suppressions of the repo-relative files,
+// sorted by path and line. Unreadable files are skipped.
+func ScanSuppressions(repoRoot string, files []string) []Suppression {
+	var out []Suppression
+	for _, rel := range files {
+		if _, ok := commentMarker(rel); !ok {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(rel))) //nolint:gosec // G304: rel is a tracked file
+		if err != nil {
+			continue
+		}
+		out = append(out, ParseSuppressions(rel, src)...)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Line < out[j].Line
+	})
+	return out
+}
+
+// Suppressor drops the findings of failed skill results that active
+// suppressions cover. Files are read on first use.
+type Suppressor struct {
+	repoRoot string
+	today    time.Time
+	files    map[string][]Suppression
+}
+
+// NewSuppressor returns a suppressor for the repository at repoRoot that
+// applies the suppressions active on day today.
+func NewSuppressor(repoRoot string, today time.Time) *Suppressor {
+	return &Suppressor{repoRoot: repoRoot, today: today, files: map[string][]Suppression{}}
+}
+
+// Suppressed reports whether an active suppression covers f.
+func (s *Suppressor) Suppressed(f Finding) bool {
+	if f.Path == "" {
+		return false
+	}
+	sups, ok := s.files[f.Path]
+	if !ok {
+		sups = ScanSuppressions(s.repoRoot, []string{f.Path})
+		s.files[f.Path] = sups
+	}
+	for _, sup := range sups {
+		if sup.Status(s.today) == SuppressionActive && sup.Matches(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply removes the suppressed findings from the note of a failed result.
+// A result whose every note line is a suppressed finding passes. Results
+// that did not fail, including timeouts, are returned unchanged.
+func (s *Suppressor) Apply(res runner.SkillResult) runner.SkillResult {
+	if res.Status != runner.StatusFail {
+		return res
+	}
+	var kept []string
+	suppressed := 0
+	for _, line := range strings.Split(res.Note, "\n") {
+		f, ok := Parse(s.repoRoot, line)
+		if !ok {
+			continue
+		}
+		f.Skill = res.Skill
+		if s.Suppressed(f) {
+			suppressed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if suppressed == 0 {
+		return res
+	}
+
+	summary := fmt.Sprintf("%d finding(s) suppressed by %s comments", suppressed, Directive)
+	if len(kept) == 0 {
+		res.Status, res.ExitCode, res.Note = runner.StatusPass, runner.ExitOK, summary
+		return res
+	}
+	res.Note = strings.Join(kept, "\n") + "\n(" + summary + ")"
+	return res
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package findings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// ignore is the suppression directive of Go sources; it is split so this
+// file carries no suppression itself.
+const ignore = "//" + "cortex" + ":ignore"
+
+func TestParseSuppressions(t *testing.T) {
+	src := "package a\n\n" +
+		ignore + " purity reason=\"legacy exec\"\n" +
+		"import \"os/exec\"\n" +
+		"x := 1 " + ignore + " lint:golangci reason=\"generated\" expires=2026-12-31\n" +
+		ignore + " purity\n" +
+		ignore + " purity reason=\"x\" expires=soon\n" +
+		ignore + " purity reason=\"x\" owner=me\n" +
+		ignore + "\n" +
+		"s := \"" + ignore + " purity reason=\\\"not a comment\\\"\"\n"
+
+	got := ParseSuppressions("a/a.go", []byte(src))
+	assert.Equal(t, []Suppression{
+		{Path: "a/a.go", Line: 3, Skill: "purity", Reason: "legacy exec"},
+		{Path: "a/a.go", Line: 5, Skill: "lint:golangci", Reason: "generated", Expires: "2026-12-31"},
+		{Path: "a/a.go", Line: 6, Skill: "purity", Error: "reason is required"},
+		{Path: "a/a.go", Line: 7, Skill: "purity", Reason: "x", Expires: "soon", Error: `invalid expires "soon": want YYYY-MM-DD`},
+		{Path: "a/a.go", Line: 8, Skill: "purity", Reason: "x", Error: `unknown attribute "owner"`},
+		{Path: "a/a.go", Line: 9, Error: "missing skill"},
+	}, got)
+
+	yaml := "# cortex:ignore docs:yaml reason=\"vendored\"\nkey: value\n"
+	assert.Len(t, ParseSuppressions("x.yaml", []byte(yaml)), 1)
+	assert.Empty(t, ParseSuppressions("README.md", []byte(yaml)))
+}
+
+func TestSuppressionStatus(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, SuppressionActive, Suppression{Reason: "r"}.Status(day))
+	assert.Equal(t, SuppressionActive, Suppression{Reason: "r", Expires: "2026-10-16"}.Status(day))
+	assert.Equal(t, SuppressionExpired, Suppression{Reason: "r", Expires: "2026-10-15"}.Status(day))
+	assert.Equal(t, SuppressionInvalid, Suppression{Error: "reason is required"}.Status(day))
+}
+
+func TestSuppressionMatches(t *testing.T) {
+	s := Suppression{Path: "a.go", Line: 3, Skill: "test:go", Reason: "r"}
+	assert.True(t, s.Matches(Finding{Skill: "test:go", Path: "a.go", Line: 3}))
+	assert.True(t, s.Matches(Finding{Skill: "test:go@api", Path: "a.go", Line: 4}))
+	assert.True(t, s.Matches(Finding{Skill: "test:go", Path: "a.go"}))
+	assert.False(t, s.Matches(Finding{Skill: "test:go", Path: "a.go", Line: 5}))
+	assert.False(t, s.Matches(Finding{Skill: "purity", Path: "a.go", Line: 3}))
+	assert.False(t, s.Matches(Finding{Skill: "test:go", Path: "b.go", Line: 3}))
+
+	rule := Suppression{Path: "a b.md", Skill: "docs:doc-patterns/spaces", Reason: "r"}
+	assert.True(t, rule.Matches(Finding{Skill: "docs:doc-patterns", Path: "a b.md", Message: "path contains spaces"}))
+	assert.False(t, rule.Matches(Finding{Skill: "docs:doc-patterns", Path: "a b.md", Message: "document has no title"}))
+}
+
+func TestSuppressorApply(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a"), 0o750))
+	src := "package a\n" +
+		ignore + " purity reason=\"legacy\"\n" +
+		"import \"os/exec\"\n" +
+		ignore + " lint:golangci reason=\"old\" expires=2020-01-01\n" +
+		"var x int\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "a.go"), []byte(src), 0o600))
+
+	sup := NewSuppressor(root, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+
+	res := sup.Apply(runner.SkillResult{Skill: "purity", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "a/a.go: banned import \"os/exec\""})
+	assert.Equal(t, runner.StatusPass, res.Status)
+	assert.Equal(t, runner.ExitOK, res.ExitCode)
+	assert.Equal(t, "1 finding(s) suppressed by cortex:ignore comments", res.Note)
+
+	res = sup.Apply(runner.SkillResult{Skill: "lint:golangci", Status: runner.StatusFail, ExitCode: runner.ExitValidation,
+		Note: "a/a.go:5:5: unused x\nb.go:1: other"})
+	assert.Equal(t, runner.StatusFail, res.Status, "expired suppressions do not apply")
+	assert.Equal(t, "a/a.go:5:5: unused x\nb.go:1: other", res.Note)
+
+	timeout := runner.SkillResult{Skill: "purity", Status: runner.StatusTimeout, Note: "a/a.go: banned import"}
+	assert.Equal(t, timeout, sup.Apply(timeout))
+}
+
+func TestSuppressorApplyPartial(t *testing.T) {
+	root := t.TempDir()
+	src := ignore + " purity reason=\"legacy\"\nimport \"os/exec\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.go"), []byte(src), 0o600))
+
+	res := NewSuppressor(root, time.Now()).Apply(runner.SkillResult{Skill: "purity", Status: runner.StatusFail,
+		ExitCode: runner.ExitValidation, Note: "a.go:2: banned import\nb.go: banned import"})
+	assert.Equal(t, runner.StatusFail, res.Status)
+	assert.Equal(t, "b.go: banned import\n(1 finding(s) suppressed by cortex:ignore comments)", res.Note)
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package fingerprint computes the repository fingerprint: the state of
+// HEAD, the index and the working tree, as defined by the MCP snapshot
+// contract and computed identically by cortex-mcp. Snapshot IDs, lease
+// validation, worktree cache keys and run history IDs are derived from it.
+package fingerprint
+
+// Feature: CLI_COMMAND_FINGERPRINT
+// Spec: spec/cli/fingerprint.md
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/canonjson"
+	"github.com/bartekus/cortex/internal/git"
+)
+
+// Fingerprint identifies the state of a repository's HEAD, index and
+// working tree (§2.2).
+type Fingerprint struct {
+	// HeadOID is the commit HEAD points to; empty on an unborn branch.
+	HeadOID string `json:"head_oid"`
+	// IndexOID is the hex SHA-256 of the raw output of
+	// `git ls-files --stage -z`: the mode, blob and stage of every index
+	// entry. Reading the listing writes no objects, and a conflicted index
+	// lists its unmerged stages, so it has an IndexOID of its own.
+	IndexOID string `json:"index_oid"`
+	// StatusHash is the hex SHA-256 of the raw output of
+	// `git status --porcelain=v1 -z --untracked-files=all`.
+	StatusHash string `json:"status_hash"`
+}
+
+// StatusArgs are the git arguments whose output StatusHash covers.
+var StatusArgs = []string{"status", "--porcelain=v1", "-z", "--untracked-files=all"}
+
+// IndexArgs are the git arguments whose output IndexOID covers.
+var IndexArgs = []string{"ls-files", "--stage", "-z"}
+
+// Compute fingerprints the repository at repoRoot without writing to its
+// object database. It fails outside a git work tree or when git is not
+// installed.
+func Compute(ctx context.Context, repoRoot string) (Fingerprint, error) {
+	// status first: it reports a missing repository clearly.
+	status, err := git.Run(ctx, repoRoot, StatusArgs...)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	sum := sha256.Sum256(status)
+
+	var fp Fingerprint
+	fp.StatusHash = hex.EncodeToString(sum[:])
+	if out, err := git.Run(ctx, repoRoot, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		fp.HeadOID = strings.TrimSpace(string(out))
+	}
+	index, err := git.Run(ctx, repoRoot, IndexArgs...)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	sum = sha256.Sum256(index)
+	fp.IndexOID = hex.EncodeToString(sum[:])
+	return fp, nil
+}
+
+// JSON returns the canonical JSON encoding of f, the form snapshot IDs are
+// derived from.
+func (f Fingerprint) JSON() ([]byte, error) {
+	return canonjson.Marshal(f)
+}
+
+// Digest returns "sha256:<hex>" of the canonical JSON of f: a compact key
+// for caches and run history.
+func (f Fingerprint) Digest() string {
+	// Marshal cannot fail for three strings.
+	data, _ := f.JSON()
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
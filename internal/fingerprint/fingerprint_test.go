@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package fingerprint
+
+// Feature: CLI_COMMAND_FINGERPRINT
+// Spec: spec/cli/fingerprint.md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/git"
+)
+
+// emptySHA256 is the hex SHA-256 of no bytes: the status and index hashes
+// of a repository with nothing in it.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestCompute(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+
+	unborn, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, Fingerprint{IndexOID: emptySHA256, StatusHash: emptySHA256}, unborn)
+
+	data, err := unborn.JSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"head_oid":"","index_oid":"`+emptySHA256+`","status_hash":"`+emptySHA256+`"}`, string(data))
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, unborn.Digest())
+
+	write(t, dir, "a.txt", "a\n")
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-m", "init")
+	clean, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	assert.Len(t, clean.HeadOID, 40)
+	assert.NotEqual(t, emptySHA256, clean.IndexOID)
+	assert.Equal(t, unborn.StatusHash, clean.StatusHash, "a clean tree has empty status")
+
+	// A file in an untracked directory changes the fingerprint even when
+	// the directory already was untracked.
+	write(t, dir, "new/one.txt", "1\n")
+	one, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	write(t, dir, "new/two.txt", "2\n")
+	two, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, clean.Digest(), one.Digest())
+	assert.NotEqual(t, one.Digest(), two.Digest())
+	assert.Equal(t, clean.IndexOID, two.IndexOID)
+
+	again, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, two, again, "deterministic")
+}
+
+func TestCompute_ConflictedIndexWritesNoObjects(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+	write(t, dir, "a.txt", "base\n")
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-m", "base")
+	runGit(t, dir, "checkout", "-b", "other")
+	write(t, dir, "a.txt", "other\n")
+	runGit(t, dir, "commit", "-am", "other")
+	runGit(t, dir, "checkout", "main")
+	write(t, dir, "a.txt", "main\n")
+	runGit(t, dir, "commit", "-am", "main")
+	resolved, err := Compute(ctx, dir)
+	require.NoError(t, err)
+
+	_, err = git.Run(ctx, dir, "merge", "other")
+	require.Error(t, err, "the merge conflicts")
+	// A staged file in a new directory would make `git write-tree` write
+	// two trees.
+	write(t, dir, "sub/b.txt", "b\n")
+	runGit(t, dir, "add", "sub/b.txt")
+
+	objects := countObjects(t, dir)
+	conflicted, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, objects, countObjects(t, dir), "nothing is written to the object database")
+	assert.Len(t, conflicted.IndexOID, 64)
+	assert.NotEqual(t, resolved.IndexOID, conflicted.IndexOID)
+
+	again, err := Compute(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, conflicted, again, "deterministic")
+}
+
+func countObjects(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := git.Run(context.Background(), dir, "count-objects", "-v")
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestCompute_NotARepository(t *testing.T) {
+	_, err := Compute(context.Background(), t.TempDir())
+	assert.Error(t, err)
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := git.Run(context.Background(), dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
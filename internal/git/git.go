@@ -0,0 +1,551 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package git wraps the git CLI: read-only history queries, and Run for
+// callers that manage clones and worktrees.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Run runs git with args in dir and returns its standard output. The error
+// carries git's standard error.
+func Run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// LastCommitTimes returns the last commit time (Unix seconds) of every file
+// in the history of repoRoot, keyed by path relative to repoRoot. It depends
+// only on the checked-out history, so results are reproducible.
+func LastCommitTimes(ctx context.Context, repoRoot string) (map[string]int64, error) {
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotePath=false", "log", "--relative", "--name-only", "--format=%x00%ct")
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	recency := map[string]int64{}
+	var current int64
+	for _, line := range strings.Split(string(out), "\n") {
+		if ts, ok := strings.CutPrefix(line, "\x00"); ok {
+			current, err = strconv.ParseInt(strings.TrimSpace(ts), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parsing git log timestamp %q: %w", ts, err)
+			}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		// git log is newest first; keep the first time a path is seen.
+		if _, seen := recency[line]; !seen {
+			recency[line] = current
+		}
+	}
+	return recency, nil
+}
+
+// Commit is a single commit in a history range.
+type Commit struct {
+	Hash    string
+	Message string
+}
+
+// RefExists reports whether ref resolves to a commit in repoRoot.
+func RefExists(ctx context.Context, repoRoot, ref string) bool {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = repoRoot
+	return cmd.Run() == nil
+}
+
+// MergeBase returns the best common ancestor of a and b.
+func MergeBase(ctx context.Context, repoRoot, a, b string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "merge-base", a, b)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s failed: %w: %s", a, b, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Commits returns the non-merge commits reachable from head but not from
+// base, oldest first.
+func Commits(ctx context.Context, repoRoot, base, head string) ([]Commit, error) {
+	cmd := exec.CommandContext(ctx, "git", "log", "--reverse", "--no-merges", "--format=%H%x00%B%x1e", base+".."+head)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		hash, message, ok := strings.Cut(record, "\x00")
+		if !ok {
+			continue
+		}
+		commits = append(commits, Commit{Hash: hash, Message: strings.TrimRight(message, "\n")})
+	}
+	return commits, nil
+}
+
+// HeadCommit returns the commit checked out in repoRoot.
+func HeadCommit(ctx context.Context, repoRoot string) (Commit, error) {
+	cmd := exec.CommandContext(ctx, "git", "log", "-1", "--format=%H%x00%B", "HEAD")
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Commit{}, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	hash, message, _ := strings.Cut(string(out), "\x00")
+	return Commit{Hash: hash, Message: strings.TrimRight(message, "\n")}, nil
+}
+
+// Change is a file in the staged diff. Status is the single-letter
+// git name-status code (A, M, D, R, ...); renames report the new path.
+type Change struct {
+	Status string
+	Path   string
+}
+
+// StagedChanges returns the files staged in the index of repoRoot, sorted by
+// path.
+func StagedChanges(ctx context.Context, repoRoot string) ([]Change, error) {
+	return diffChanges(ctx, repoRoot, "--cached")
+}
+
+// DiffChanges returns the files that differ between base and head, sorted by
+// path.
+func DiffChanges(ctx context.Context, repoRoot, base, head string) ([]Change, error) {
+	return diffChanges(ctx, repoRoot, base, head)
+}
+
+func diffChanges(ctx context.Context, repoRoot string, args ...string) ([]Change, error) {
+	args = append([]string{"diff", "--name-status", "-z", "--relative"}, args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	var changes []Change
+	for i := 0; i < len(fields) && fields[i] != ""; i++ {
+		status := fields[i][:1]
+		// Renames and copies are followed by the old and the new path.
+		if status == "R" || status == "C" {
+			i++
+		}
+		i++
+		if i >= len(fields) {
+			return nil, fmt.Errorf("unexpected git diff output: %q", out)
+		}
+		changes = append(changes, Change{Status: status, Path: fields[i]})
+	}
+	sort.Slice(changes, func(a, b int) bool { return changes[a].Path < changes[b].Path })
+	return changes, nil
+}
+
+// LineStat counts the lines added and deleted in a file. Binary files have
+// Binary set and zero counts.
+type LineStat struct {
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// NumStat returns per-file line counts between base and head, keyed by path
+// (the new path for renames).
+func NumStat(ctx context.Context, repoRoot, base, head string) (map[string]LineStat, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--numstat", "-z", "--relative", base, head)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --numstat failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	stats := map[string]LineStat{}
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i < len(fields) && fields[i] != ""; i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected git numstat output: %q", fields[i])
+		}
+		path := parts[2]
+		// Renames leave the path empty and are followed by the old and new path.
+		if path == "" {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("unexpected git numstat output: %q", out)
+			}
+			path = fields[i+2]
+			i += 2
+		}
+		var st LineStat
+		if parts[0] == "-" {
+			st.Binary = true
+		} else {
+			if st.Added, err = strconv.Atoi(parts[0]); err != nil {
+				return nil, fmt.Errorf("parsing numstat %q: %w", fields[i], err)
+			}
+			if st.Deleted, err = strconv.Atoi(parts[1]); err != nil {
+				return nil, fmt.Errorf("parsing numstat %q: %w", fields[i], err)
+			}
+		}
+		stats[path] = st
+	}
+	return stats, nil
+}
+
+// CurrentBranch returns the short name of the checked-out branch, or "" when
+// HEAD is detached.
+func CurrentBranch(ctx context.Context, repoRoot string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("git symbolic-ref failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CountCommits returns the number of commits reachable from head but not
+// from base.
+func CountCommits(ctx context.Context, repoRoot, base, head string) (int, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--count", base+".."+head)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("git rev-list failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// ChangedFiles returns the sorted paths that differ between base and head.
+func ChangedFiles(ctx context.Context, repoRoot, base, head string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", "-z", "--relative", base, head)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	files := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	sort.Strings(files)
+	return files, nil
+}
+
+// RevParse resolves ref to a full commit hash.
+func RevParse(ctx context.Context, repoRoot, ref string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", ref+"^{commit}")
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse %s failed: %w: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Author is a commit author.
+type Author struct {
+	Name  string
+	Email string
+}
+
+// FileAuthors returns the authors of the non-merge commits since the git
+// date since ("" for the whole history) that touched each file, keyed by
+// path relative to repoRoot. Each file's authors are unique and sorted by
+// email, then name.
+func FileAuthors(ctx context.Context, repoRoot, since string) (map[string][]Author, error) {
+	args := []string{"-c", "core.quotePath=false", "log", "--no-merges", "--relative", "--name-only", "--format=%x00%an%x09%ae"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	seen := map[string]map[Author]bool{}
+	var current Author
+	for _, line := range strings.Split(string(out), "\n") {
+		if header, ok := strings.CutPrefix(line, "\x00"); ok {
+			name, email, _ := strings.Cut(header, "\t")
+			current = Author{Name: name, Email: email}
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if seen[line] == nil {
+			seen[line] = map[Author]bool{}
+		}
+		seen[line][current] = true
+	}
+
+	authors := make(map[string][]Author, len(seen))
+	for path, set := range seen {
+		list := make([]Author, 0, len(set))
+		for a := range set {
+			list = append(list, a)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Email != list[j].Email {
+				return list[i].Email < list[j].Email
+			}
+			return list[i].Name < list[j].Name
+		})
+		authors[path] = list
+	}
+	return authors, nil
+}
+
+// CommitFiles returns the files changed by each non-merge commit reachable
+// from head but not from base, keyed by commit hash, with paths relative to
+// repoRoot and sorted.
+func CommitFiles(ctx context.Context, repoRoot, base, head string) (map[string][]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotePath=false", "log", "--no-merges", "--relative", "--name-only", "--format=%x00%H", base+".."+head)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	files := map[string][]string{}
+	var current string
+	for _, line := range strings.Split(string(out), "\n") {
+		if hash, ok := strings.CutPrefix(line, "\x00"); ok {
+			current = hash
+			files[current] = []string{}
+			continue
+		}
+		if line != "" && current != "" {
+			files[current] = append(files[current], line)
+		}
+	}
+	for _, list := range files {
+		sort.Strings(list)
+	}
+	return files, nil
+}
+
+// PathCommits returns the non-merge commits reachable from head but not
+// from base that changed path, oldest first.
+func PathCommits(ctx context.Context, repoRoot, base, head, path string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "log", "--reverse", "--no-merges", "--format=%H", base+".."+head, "--", path)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// ShowFile returns the content of path, relative to repoRoot, at rev. It
+// returns nil without an error when path does not exist at rev.
+func ShowFile(ctx context.Context, repoRoot, rev, path string) ([]byte, error) {
+	spec := rev + ":./" + path
+	exists := exec.CommandContext(ctx, "git", "cat-file", "-e", spec)
+	exists.Dir = repoRoot
+	// An unknown rev falls through to git show, which reports it.
+	if exists.Run() != nil && RefExists(ctx, repoRoot, rev) {
+		return nil, nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "show", spec)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show %s failed: %w: %s", spec, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// TreeDigests returns the "sha256:<hex>" digest of the content of every
+// regular file at rev, keyed by path relative to repoRoot. Symlinks and
+// submodules are left out.
+func TreeDigests(ctx context.Context, repoRoot, rev string) (map[string]string, error) {
+	out, err := Run(ctx, repoRoot, "ls-tree", "-r", "-z", rev)
+	if err != nil {
+		return nil, err
+	}
+	var paths, oids []string
+	for _, rec := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		// <mode> SP <type> SP <object> TAB <path>
+		meta, path, ok := strings.Cut(rec, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		paths = append(paths, path)
+		oids = append(oids, fields[2])
+	}
+	if len(oids) == 0 {
+		return map[string]string{}, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	cmd.Dir = repoRoot
+	cmd.Stdin = strings.NewReader(strings.Join(oids, "\n") + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git cat-file failed: %w", err)
+	}
+	// On a read error git may be blocked writing; stop it.
+	abort := func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+	digests := make(map[string]string, len(paths))
+	r := bufio.NewReader(stdout)
+	for _, path := range paths {
+		// <object> SP blob SP <size> LF <content> LF
+		header, err := r.ReadString('\n')
+		if err != nil {
+			abort()
+			return nil, fmt.Errorf("git cat-file failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		fields := strings.Fields(header)
+		size, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+		if len(fields) != 3 || err != nil {
+			abort()
+			return nil, fmt.Errorf("git cat-file: unexpected header %q", strings.TrimSpace(header))
+		}
+		h := sha256.New()
+		if _, err := io.CopyN(h, r, size); err != nil {
+			abort()
+			return nil, fmt.Errorf("git cat-file failed: %w", err)
+		}
+		if _, err := r.Discard(1); err != nil {
+			abort()
+			return nil, fmt.Errorf("git cat-file failed: %w", err)
+		}
+		digests[path] = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("git cat-file failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return digests, nil
+}
+
+// AddedLine is a line a diff adds: its path relative to the repository
+// root, its 1-based number in the new file and its text.
+type AddedLine struct {
+	Path string
+	Line int
+	Text string
+}
+
+// AddedLines returns the lines added since base in the working tree of
+// repoRoot (committed or not) to the files matching pathspecs, in diff
+// order. Untracked files are not part of the diff.
+func AddedLines(ctx context.Context, repoRoot, base string, pathspecs ...string) ([]AddedLine, error) {
+	args := append([]string{"-c", "core.quotePath=false", "diff", "--unified=0", "--no-color", "--no-ext-diff", "--no-prefix", "--relative", base, "--"}, pathspecs...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var added []AddedLine
+	var path string
+	// oldLeft and newLeft count the lines of the current hunk still to
+	// come; outside a hunk both are 0 and lines are file headers.
+	var line, oldLeft, newLeft int
+	for _, l := range strings.Split(string(out), "\n") {
+		switch {
+		case newLeft > 0 && strings.HasPrefix(l, "+"):
+			added = append(added, AddedLine{Path: path, Line: line, Text: l[1:]})
+			line++
+			newLeft--
+		case oldLeft > 0 && strings.HasPrefix(l, "-"):
+			oldLeft--
+		case strings.HasPrefix(l, "+++ "):
+			path = strings.TrimPrefix(l, "+++ ")
+		case strings.HasPrefix(l, "@@ "):
+			// "@@ -a[,b] +c[,d] @@": a missing count is 1.
+			fields := strings.Fields(l)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("unexpected git diff hunk: %q", l)
+			}
+			_, oldLeft = hunkRange(fields[1])
+			line, newLeft = hunkRange(fields[2])
+		}
+	}
+	return added, nil
+}
+
+// hunkRange parses a "-a,b" or "+c,d" range of a hunk header into its start
+// and line count.
+func hunkRange(r string) (start, count int) {
+	from, n, ok := strings.Cut(r[1:], ",")
+	start, _ = strconv.Atoi(from)
+	count = 1
+	if ok {
+		count, _ = strconv.Atoi(n)
+	}
+	return start, count
+}
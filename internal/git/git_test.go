@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := Run(context.Background(), dir, args...)
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+func write(t *testing.T, dir, name string, lines ...string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+}
+
+func TestAddedLines(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+
+	write(t, dir, "a.go", "package a", "var x = 1", "var y = 2", "func f() {}", "-- old")
+	write(t, dir, "notes.txt", "one")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "base")
+	base := runGit(t, dir, "rev-parse", "HEAD")
+
+	// A changed line, an added line that reads like a file header in the
+	// diff ("+++ weird") and a removed one that does too ("--- old").
+	write(t, dir, "a.go", "package a", "var x = 10", "var y = 2", "func f() {}", "++ weird")
+	write(t, dir, "sub/c.go", "package c", "var z = 3")
+	write(t, dir, "notes.txt", "one", "two")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "change")
+	// Uncommitted changes count; untracked files do not.
+	write(t, dir, "sub/c.go", "package c", "var z = 3", "var w = 4")
+	write(t, dir, "untracked.go", "package u")
+
+	lines, err := AddedLines(context.Background(), dir, base, "*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []AddedLine{
+		{Path: "a.go", Line: 2, Text: "var x = 10"},
+		{Path: "a.go", Line: 5, Text: "++ weird"},
+		{Path: "sub/c.go", Line: 1, Text: "package c"},
+		{Path: "sub/c.go", Line: 2, Text: "var z = 3"},
+		{Path: "sub/c.go", Line: 3, Text: "var w = 4"},
+	}, lines)
+
+	// Paths are relative to the directory the diff runs in.
+	lines, err = AddedLines(context.Background(), filepath.Join(dir, "sub"), base, "*.go")
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, AddedLine{Path: "c.go", Line: 3, Text: "var w = 4"}, lines[2])
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package github publishes run results to the GitHub Checks API so skill
+// failures appear as inline annotations on pull request diffs.
+package github
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// DefaultAPIURL is the public GitHub REST endpoint.
+const DefaultAPIURL = "https://api.github.com"
+
+// MaxAnnotations is the number of annotations GitHub accepts per request.
+const MaxAnnotations = 50
+
+// Annotation is a check run annotation.
+type Annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// Output is the check run output shown on the Checks tab.
+type Output struct {
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Text        string       `json:"text,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// CheckRun is the request body for creating or updating a check run.
+type CheckRun struct {
+	Name       string `json:"name,omitempty"`
+	HeadSHA    string `json:"head_sha,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	Output     Output `json:"output"`
+}
+
+// maxTextLength is GitHub's limit for the summary and text fields.
+const maxTextLength = 65535
+
+// NewOutput summarises results: a status table in the summary and, in the
+// text, the findings that have no file location.
+func NewOutput(results []runner.SkillResult, fs []findings.Finding) Output {
+	counts := map[runner.SkillStatus]int{}
+	var sb strings.Builder
+	sb.WriteString("| Skill | Status |\n| --- | --- |\n")
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(&sb, "| `%s` | %s |\n", r.Skill, r.Status)
+	}
+
+	var text strings.Builder
+	for _, f := range fs {
+		if f.Path == "" {
+			fmt.Fprintf(&text, "- `%s`: %s\n", f.Skill, f.Message)
+		}
+	}
+
+	return Output{
+		Title: fmt.Sprintf("%d failed, %d passed, %d skipped",
+			counts[runner.StatusFail]+counts[runner.StatusTimeout], counts[runner.StatusPass], counts[runner.StatusSkip]),
+		Summary: truncate(sb.String()),
+		Text:    truncate(text.String()),
+	}
+}
+
+func truncate(s string) string {
+	const marker = "\n…(truncated)\n"
+	if len(s) <= maxTextLength {
+		return s
+	}
+	cut := maxTextLength - len(marker)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + marker
+}
+
+// Annotations merges findings that share a file and line into one failure
+// annotation each, in path/line order. Findings without a path are skipped;
+// findings without a line are anchored to line 1.
+func Annotations(fs []findings.Finding) []Annotation {
+	type key struct {
+		path string
+		line int
+	}
+	grouped := map[key][]findings.Finding{}
+	var keys []key
+	for _, f := range fs {
+		if f.Path == "" {
+			continue
+		}
+		k := key{f.Path, max(f.Line, 1)}
+		if _, seen := grouped[k]; !seen {
+			keys = append(keys, k)
+		}
+		grouped[k] = append(grouped[k], f)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].line < keys[j].line
+	})
+
+	out := make([]Annotation, 0, len(keys))
+	for _, k := range keys {
+		var skills, messages []string
+		for _, f := range grouped[k] {
+			if len(skills) == 0 || skills[len(skills)-1] != f.Skill {
+				skills = append(skills, f.Skill)
+			}
+			messages = append(messages, fmt.Sprintf("[%s] %s", f.Skill, f.Message))
+		}
+		out = append(out, Annotation{
+			Path:            k.path,
+			StartLine:       k.line,
+			EndLine:         k.line,
+			AnnotationLevel: "failure",
+			Title:           strings.Join(skills, ", "),
+			Message:         strings.Join(messages, "\n"),
+		})
+	}
+	return out
+}
+
+// Batches splits annotations into request-sized chunks. It always returns
+// at least one (possibly empty) batch.
+func Batches(annotations []Annotation) [][]Annotation {
+	batches := [][]Annotation{nil}
+	for i := 0; i < len(annotations); i += MaxAnnotations {
+		end := min(i+MaxAnnotations, len(annotations))
+		if i == 0 {
+			batches[0] = annotations[:end]
+			continue
+		}
+		batches = append(batches, annotations[i:end])
+	}
+	return batches
+}
+
+// Client calls the Checks API of one repository.
+type Client struct {
+	BaseURL string
+	Token   string
+	// Repo is "owner/name".
+	Repo string
+	// HTTP defaults to an http.Client with a 30s timeout.
+	HTTP *http.Client
+}
+
+// Publish creates a completed check run for run, uploading annotations in
+// batches of MaxAnnotations. It returns the check run's HTML URL.
+func (c *Client) Publish(ctx context.Context, run CheckRun, annotations []Annotation) (string, error) {
+	batches := Batches(annotations)
+	conclusion := run.Conclusion
+
+	create := run
+	create.Output.Annotations = batches[0]
+	if len(batches) > 1 {
+		create.Status, create.Conclusion = "in_progress", ""
+	} else {
+		create.Status = "completed"
+	}
+
+	var created struct {
+		ID      int64  `json:"id"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/repos/"+c.Repo+"/check-runs", create, &created); err != nil {
+		return "", err
+	}
+
+	for i, batch := range batches[1:] {
+		update := CheckRun{Output: run.Output}
+		update.Output.Annotations = batch
+		if i == len(batches)-2 {
+			update.Status, update.Conclusion = "completed", conclusion
+		}
+		path := fmt.Sprintf("/repos/%s/check-runs/%d", c.Repo, created.ID)
+		if err := c.do(ctx, http.MethodPatch, path, update, nil); err != nil {
+			return "", err
+		}
+	}
+	return created.HTMLURL, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	url := strings.TrimRight(c.BaseURL, "/") + path
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response from %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("%s %s returned %s: %s", method, url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return nil
+}
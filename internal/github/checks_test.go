@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+func TestAnnotationsGroupsByLine(t *testing.T) {
+	fs := []findings.Finding{
+		{Skill: "docs:yaml", Path: "a.go", Line: 3, Message: "one"},
+		{Skill: "lint:golangci", Path: "a.go", Line: 3, Message: "two"},
+		{Skill: "purity", Path: "a.go", Message: "file level"},
+		{Skill: "test:coverage", Message: "no location"},
+	}
+
+	got := Annotations(fs)
+	require.Len(t, got, 2)
+	assert.Equal(t, Annotation{
+		Path: "a.go", StartLine: 1, EndLine: 1, AnnotationLevel: "failure",
+		Title: "purity", Message: "[purity] file level",
+	}, got[0])
+	assert.Equal(t, "docs:yaml, lint:golangci", got[1].Title)
+	assert.Equal(t, "[docs:yaml] one\n[lint:golangci] two", got[1].Message)
+}
+
+func TestNewOutput(t *testing.T) {
+	results := []runner.SkillResult{
+		{Skill: "purity", Status: runner.StatusPass},
+		{Skill: "test:coverage", Status: runner.StatusFail},
+	}
+	out := NewOutput(results, []findings.Finding{{Skill: "test:coverage", Message: "41% < 60%"}})
+
+	assert.Equal(t, "1 failed, 1 passed, 0 skipped", out.Title)
+	assert.Contains(t, out.Summary, "| `test:coverage` | fail |")
+	assert.Equal(t, "- `test:coverage`: 41% < 60%\n", out.Text)
+}
+
+func TestBatches(t *testing.T) {
+	assert.Equal(t, [][]Annotation{nil}, Batches(nil))
+
+	anns := make([]Annotation, 2*MaxAnnotations+1)
+	batches := Batches(anns)
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], MaxAnnotations)
+	assert.Len(t, batches[2], 1)
+}
+
+func TestPublishBatches(t *testing.T) {
+	type request struct {
+		method, path string
+		body         CheckRun
+	}
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body CheckRun
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, request{r.Method, r.URL.Path, body})
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"id": 7, "html_url": "https://example.test/runs/7"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	anns := make([]Annotation, MaxAnnotations+1)
+	c := &Client{BaseURL: srv.URL, Token: "secret", Repo: "o/r"}
+	url, err := c.Publish(context.Background(), CheckRun{Name: "cortex", HeadSHA: "abc", Conclusion: "failure"}, anns)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.test/runs/7", url)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, http.MethodPost, requests[0].method)
+	assert.Equal(t, "/repos/o/r/check-runs", requests[0].path)
+	assert.Equal(t, "in_progress", requests[0].body.Status)
+	assert.Empty(t, requests[0].body.Conclusion)
+	assert.Len(t, requests[0].body.Output.Annotations, MaxAnnotations)
+
+	assert.Equal(t, http.MethodPatch, requests[1].method)
+	assert.Equal(t, "/repos/o/r/check-runs/7", requests[1].path)
+	assert.Equal(t, "completed", requests[1].body.Status)
+	assert.Equal(t, "failure", requests[1].body.Conclusion)
+	assert.Len(t, requests[1].body.Output.Annotations, 1)
+}
+
+func TestPublishError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Bad credentials", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, Repo: "o/r"}
+	_, err := c.Publish(context.Background(), CheckRun{Conclusion: "success"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401 Unauthorized: Bad credentials")
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package gomod reads the subset of go.mod needed by the context pipeline:
+// module path, go version and require directives.
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// File is a parsed go.mod.
+type File struct {
+	Module  string
+	Go      string
+	Require []Require
+}
+
+// Require is a single require directive.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool
+	Line     int // 1-based line in go.mod
+}
+
+// Parse extracts module, go and require directives (single-line and block form).
+func Parse(data []byte) *File {
+	f := &File{}
+	inRequire := false
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+
+		comment := ""
+		if i := strings.Index(line, "//"); i >= 0 {
+			comment = strings.TrimSpace(line[i+2:])
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inRequire {
+			if fields[0] == ")" {
+				inRequire = false
+				continue
+			}
+			f.addRequire(fields, comment, n)
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				f.Module = strings.Trim(fields[1], `"`)
+			}
+		case "go":
+			if len(fields) >= 2 {
+				f.Go = fields[1]
+			}
+		case "require":
+			if len(fields) == 2 && fields[1] == "(" {
+				inRequire = true
+				continue
+			}
+			f.addRequire(fields[1:], comment, n)
+		}
+	}
+	return f
+}
+
+func (f *File) addRequire(fields []string, comment string, line int) {
+	if len(fields) < 2 {
+		return
+	}
+	f.Require = append(f.Require, Require{
+		Path:     strings.Trim(fields[0], `"`),
+		Version:  fields[1],
+		Indirect: comment == "indirect",
+		Line:     line,
+	})
+}
+
+// Find parses every go.mod among files and returns them keyed by the
+// repo-relative directory containing them ("." for the root).
+func Find(repoRoot string, files []xray.FileNode) (map[string]*File, error) {
+	modules := make(map[string]*File)
+	for _, f := range files {
+		if path.Base(f.Path) != "go.mod" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(f.Path))) //nolint:gosec // paths come from the XRAY index
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f.Path, err)
+		}
+		if mod := Parse(data); mod.Module != "" {
+			modules[path.Dir(f.Path)] = mod
+		}
+	}
+	return modules, nil
+}
+
+// ImportPath resolves a repo-relative package directory against the nearest
+// enclosing module. Directories outside any module map to themselves.
+func ImportPath(modules map[string]*File, dir string) string {
+	for d := dir; ; d = path.Dir(d) {
+		if mod, ok := modules[d]; ok {
+			if d == dir {
+				return mod.Module
+			}
+			rel := strings.TrimPrefix(dir, d+"/")
+			if d == "." {
+				rel = dir
+			}
+			return mod.Module + "/" + rel
+		}
+		if d == "." || d == "/" {
+			return dir
+		}
+	}
+}
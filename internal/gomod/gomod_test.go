@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	f := Parse([]byte(`module example.com/demo // main module
+
+go 1.24
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace example.com/x => ../x
+`))
+	assert.Equal(t, &File{
+		Module: "example.com/demo",
+		Go:     "1.24",
+		Require: []Require{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0", Line: 5},
+			{Path: "github.com/stretchr/testify", Version: "v1.9.0", Line: 8},
+			{Path: "gopkg.in/yaml.v3", Version: "v3.0.1", Indirect: true, Line: 9},
+		},
+	}, f)
+}
+
+func TestImportPath(t *testing.T) {
+	modules := map[string]*File{".": {Module: "example.com/root"}, "tools": {Module: "example.com/tools"}}
+	assert.Equal(t, "example.com/root", ImportPath(modules, "."))
+	assert.Equal(t, "example.com/root/pkg/a", ImportPath(modules, "pkg/a"))
+	assert.Equal(t, "example.com/tools", ImportPath(modules, "tools"))
+	assert.Equal(t, "example.com/tools/gen", ImportPath(modules, "tools/gen"))
+	assert.Equal(t, "loose/dir", ImportPath(map[string]*File{}, "loose/dir"))
+}
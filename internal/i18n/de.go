@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package i18n
+
+// de is the German catalog.
+var de = map[string]string{
+	// cortex run progress
+	"SKIP: %s":                       "ÜBERSPRUNGEN: %s",
+	"PASS: %s":                       "BESTANDEN: %s",
+	"FAIL: %s (exit %d)":             "FEHLGESCHLAGEN: %s (Exit-Code %d)",
+	"TIMEOUT: %s (exit %d)":          "ZEITÜBERSCHREITUNG: %s (Exit-Code %d)",
+	"FLAKY: %s passed on attempt %d": "INSTABIL: %s bestanden im %d. Versuch",
+	"run failed: %v":                 "Lauf fehlgeschlagen: %v",
+	"FAIL-FAST: %d skill(s) not run": "FAIL-FAST: %d Skill(s) nicht ausgeführt",
+
+	// Errors
+	"finding repo root":     "Repository-Wurzel nicht gefunden",
+	"loading config":        "Konfiguration konnte nicht geladen werden",
+	"%s is not a directory": "%s ist kein Verzeichnis",
+
+	"Cortex version %s": "Cortex-Version %s",
+}
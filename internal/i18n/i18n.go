@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package i18n translates the human-readable output of the CLI: progress
+// labels and error messages. Messages are keyed by their English format
+// string, so call sites read as before and a message without a translation
+// prints in English.
+//
+// Only text meant for people goes through this package. JSON, exit codes,
+// skill statuses, log records and the strings errors return from Error()
+// stay English whatever the locale, so scripts never depend on it.
+package i18n
+
+// Feature: CLI_CONTRACT
+// Spec: spec/cli/contract.md
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvVar selects the locale, e.g. "de" or "de_DE.UTF-8".
+const EnvVar = "CORTEX_LANG"
+
+// Default is the locale of the message keys.
+const Default = "en"
+
+// catalogs maps a locale to its translations, keyed by English format.
+var catalogs = map[string]map[string]string{
+	"de": de,
+}
+
+// Locales returns the supported locales, sorted.
+func Locales() []string {
+	out := []string{Default}
+	for l := range catalogs {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lang returns the locale CORTEX_LANG selects: its language part
+// ("de_DE.UTF-8" selects "de"), or Default when it is unset or has no
+// catalog.
+func Lang() string {
+	return parse(os.Getenv(EnvVar))
+}
+
+func parse(v string) string {
+	v, _, _ = strings.Cut(v, ".")
+	lang, _, _ := strings.Cut(strings.ReplaceAll(v, "-", "_"), "_")
+	lang = strings.ToLower(lang)
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return Default
+}
+
+// T returns the translation of msg in the current locale, or msg.
+func T(msg string) string {
+	if t, ok := catalogs[Lang()][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Localizer is an error that can render its message in the current locale.
+type Localizer interface {
+	error
+	Localize() string
+}
+
+// Error returns the message of err for people: Localize() when err is a
+// Localizer, else Error().
+func Error(err error) string {
+	if l, ok := err.(Localizer); ok {
+		return l.Localize()
+	}
+	return err.Error()
+}
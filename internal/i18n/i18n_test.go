@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLang(t *testing.T) {
+	for v, want := range map[string]string{
+		"":            "en",
+		"C":           "en",
+		"de":          "de",
+		"DE":          "de",
+		"de_DE.UTF-8": "de",
+		"de-AT":       "de",
+		"fr_FR":       "en",
+	} {
+		t.Setenv(EnvVar, v)
+		assert.Equal(t, want, Lang(), v)
+	}
+	assert.Equal(t, []string{"de", "en"}, Locales())
+}
+
+func TestSprintf(t *testing.T) {
+	t.Setenv(EnvVar, "de")
+	assert.Equal(t, "BESTANDEN: purity", Sprintf("PASS: %s", "purity"))
+	assert.Equal(t, "no translation: 1", Sprintf("no translation: %d", 1), "untranslated messages print in English")
+
+	t.Setenv(EnvVar, "")
+	assert.Equal(t, "PASS: purity", Sprintf("PASS: %s", "purity"))
+}
+
+type localized struct{ error }
+
+func (localized) Localize() string { return "lokal" }
+
+func TestError(t *testing.T) {
+	assert.Equal(t, "lokal", Error(localized{errors.New("local")}))
+	assert.Equal(t, "plain", Error(errors.New("plain")))
+}
+
+// verbs matches the formatting directives of a message.
+var verbs = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsKeepVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for msg, translation := range catalog {
+			assert.Equal(t, verbs.FindAllString(msg, -1), verbs.FindAllString(translation, -1), "%s: %q", lang, msg)
+		}
+	}
+}
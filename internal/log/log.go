@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package log configures the structured logger (log/slog) shared by the
+// commands, the runner and skills. Logs are diagnostics for operators and
+// go to stderr; command results stay on stdout.
+//
+// Output is deterministic so it can be compared against goldens: records
+// carry no timestamp unless Options.Time is set, and attributes keep the
+// order they were passed in.
+package log
+
+// Feature: CLI_CONTRACT
+// Spec: spec/cli/contract.md
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configures New.
+type Options struct {
+	// Level is the minimum level logged.
+	Level slog.Level
+	// Format is FormatText (logfmt-style key=value) or FormatJSON (one
+	// object per line). Empty means FormatText.
+	Format string
+	// Time adds a "time" attribute to every record.
+	Time bool
+}
+
+// New returns a logger writing to w.
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	hopts := &slog.HandlerOptions{Level: opts.Level}
+	if !opts.Time {
+		hopts.ReplaceAttr = dropTime
+	}
+	switch opts.Format {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, hopts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, hopts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want %s or %s)", opts.Format, FormatText, FormatJSON)
+	}
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+// ParseLevel parses debug, info, warn or error, case-insensitively.
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	switch strings.ToLower(s) {
+	case "debug", "info", "warn", "error":
+		err := l.UnmarshalText([]byte(s))
+		return l, err
+	default:
+		return l, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+	}
+}
+
+// Discard returns a logger that drops every record.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// OrDiscard returns l, or Discard when l is nil, so optional loggers need
+// no nil checks.
+func OrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return Discard()
+	}
+	return l
+}
+
+type ctxKey struct{}
+
+// WithContext returns ctx carrying l.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or Discard.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return Discard()
+}
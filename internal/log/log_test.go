@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: CLI_CONTRACT
+// Spec: spec/cli/contract.md
+
+func TestNewIsDeterministic(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, Options{Level: slog.LevelInfo})
+	require.NoError(t, err)
+	l.Debug("hidden")
+	l.With("skill", "test:go").Info("skill done", "status", "pass", "exit_code", 0)
+	assert.Equal(t, "level=INFO msg=\"skill done\" skill=test:go status=pass exit_code=0\n", buf.String())
+
+	buf.Reset()
+	l, err = New(&buf, Options{Level: slog.LevelDebug, Format: FormatJSON})
+	require.NoError(t, err)
+	l.Debug("exec", "name", "go", "dir", ".")
+	assert.Equal(t, `{"level":"DEBUG","msg":"exec","name":"go","dir":"."}`+"\n", buf.String())
+
+	buf.Reset()
+	l, err = New(&buf, Options{Time: true})
+	require.NoError(t, err)
+	l.Info("x")
+	assert.Contains(t, buf.String(), "time=")
+
+	_, err = New(&buf, Options{Format: "xml"})
+	assert.ErrorContains(t, err, `unknown log format "xml"`)
+}
+
+func TestParseLevel(t *testing.T) {
+	l, err := ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, l)
+
+	_, err = ParseLevel("warn+2")
+	assert.Error(t, err)
+}
+
+func TestContext(t *testing.T) {
+	assert.False(t, FromContext(context.Background()).Enabled(context.Background(), slog.LevelError))
+
+	var buf bytes.Buffer
+	l, err := New(&buf, Options{})
+	require.NoError(t, err)
+	FromContext(WithContext(context.Background(), l)).Info("hello")
+	assert.Equal(t, "level=INFO msg=hello\n", buf.String())
+}
@@ -256,21 +256,10 @@ func analyzeWithFeatures(root string, metas []featureMeta) (Report, error) {
 		}
 
 		// Only care about file types we can extract Feature/Spec headers from.
-		//
-		// Supported:
-		//   - Go: .go
-		//   - Rust: .rs
-		//   - TypeScript/JavaScript: .ts, .tsx, .js, .jsx
-		//   - YAML: .yml, .yaml
-		//   - Makefile: Makefile (no extension)
-		baseName := filepath.Base(rel)
-		ext := strings.ToLower(filepath.Ext(rel))
-		isMakefile := baseName == "Makefile"
-		isTSJS := ext == ".ts" || ext == ".tsx" || ext == ".js" || ext == ".jsx"
-		isSupported := ext == ".go" || ext == ".rs" || isTSJS || ext == ".yml" || ext == ".yaml" || isMakefile
-		if !isSupported {
+		if !SupportsHeaders(rel) {
 			return nil
 		}
+		ext := strings.ToLower(filepath.Ext(rel))
 
 		base := strings.ToLower(filepath.Base(rel))
 		norm := pathClean(rel)
@@ -303,7 +292,7 @@ func analyzeWithFeatures(root string, metas []featureMeta) (Report, error) {
 			}
 		}
 
-		fileFeature, fileSpec, err := parseHeaders(path)
+		fileFeature, fileSpec, err := ParseHeaders(path)
 		if err != nil {
 			return fmt.Errorf("parse headers in %s: %w", rel, err)
 		}
@@ -509,7 +498,23 @@ func pathClean(p string) string {
 	return p
 }
 
-// parseHeaders reads a supported source/config file and extracts the first Feature and Spec headers
+// SupportsHeaders reports whether Feature/Spec headers are extracted from rel.
+//
+// Supported:
+//   - Go: .go
+//   - Rust: .rs
+//   - TypeScript/JavaScript: .ts, .tsx, .js, .jsx
+//   - YAML: .yml, .yaml
+//   - Makefile: Makefile (no extension)
+func SupportsHeaders(rel string) bool {
+	switch strings.ToLower(filepath.Ext(rel)) {
+	case ".go", ".rs", ".ts", ".tsx", ".js", ".jsx", ".yml", ".yaml":
+		return true
+	}
+	return filepath.Base(rel) == "Makefile"
+}
+
+// ParseHeaders reads a supported source/config file and extracts the first Feature and Spec headers
 // from line comments of the form:
 //
 //	// Feature: FEATURE_ID
@@ -521,7 +526,7 @@ func pathClean(p string) string {
 //	# Spec: spec/path/to/file.md
 //
 // The match is case-insensitive on the keys.
-func parseHeaders(path string) (featureID, specPath string, err error) {
+func ParseHeaders(path string) (featureID, specPath string, err error) {
 	f, err := os.Open(path) //nolint:gosec // path is from filepath.WalkDir, safe
 	if err != nil {
 		return "", "", err
@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package mcp is a minimal client for the cortex-mcp server (rust/mcp): it
+// starts the binary and calls its tools over stdio, as Content-Length framed
+// JSON-RPC 2.0 messages.
+package mcp
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BinEnv overrides the cortex-mcp binary location.
+const BinEnv = "CORTEX_MCP_BIN"
+
+// ResolveBin locates the cortex-mcp binary.
+// Resolution order: explicit path (e.g. --mcp-bin), $CORTEX_MCP_BIN,
+// then the release and debug cargo builds under repoRoot/rust/target.
+func ResolveBin(explicit, repoRoot string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if bin := os.Getenv(BinEnv); bin != "" {
+		return bin, nil
+	}
+	for _, profile := range []string{"release", "debug"} {
+		path := filepath.Join(repoRoot, "rust", "target", profile, "cortex-mcp")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("cortex-mcp binary not found. Build it with `cargo build` in rust/mcp/ or specify --mcp-bin")
+}
+
+// Error is a JSON-RPC error returned by the server. Code is the server's
+// code, which cortex-mcp sends as a string ("NOT_FOUND") for tool errors
+// and as a number for protocol errors.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Client calls tools of one server. It is safe for concurrent use; calls
+// are serialized.
+type Client struct {
+	mu     sync.Mutex
+	r      *bufio.Reader
+	w      io.Writer
+	nextID int
+	close  func() error
+}
+
+// NewClient returns a client speaking to a server through r and w.
+func NewClient(r io.Reader, w io.Writer) *Client {
+	return &Client{r: bufio.NewReader(r), w: w}
+}
+
+// Start runs bin in dir, where cortex-mcp keeps its store (.cortex/data
+// unless $CORTEX_DATA_DIR is set), and initializes the session. The
+// server's logs go to stderr.
+func Start(ctx context.Context, bin, dir string, stderr io.Writer) (*Client, error) {
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Dir = dir
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", bin, err)
+	}
+
+	c := NewClient(stdout, stdin)
+	c.close = func() error {
+		_ = stdin.Close()
+		return cmd.Wait()
+	}
+	if err := c.Initialize(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close ends the session and waits for a started server to exit.
+func (c *Client) Close() error {
+	if c.close == nil {
+		return nil
+	}
+	return c.close()
+}
+
+// Initialize performs the MCP handshake.
+func (c *Client) Initialize(ctx context.Context) error {
+	return c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": "2024-11-05",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": "cortex", "version": "1"},
+	}, nil)
+}
+
+// Tool is a tool the server registers, as listed by tools/list.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// ListTools returns the tools the server registers, in its order.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var res struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := c.call(ctx, "tools/list", map[string]any{}, &res); err != nil {
+		return nil, fmt.Errorf("tools/list: %w", err)
+	}
+	return res.Tools, nil
+}
+
+// CallTool calls a tool and decodes its JSON content into out. args is a
+// map or an argument struct encoding to a JSON object.
+func (c *Client) CallTool(ctx context.Context, name string, args any, out any) error {
+	var res struct {
+		Content []struct {
+			Type string          `json:"type"`
+			JSON json.RawMessage `json:"json"`
+			Text string          `json:"text"`
+		} `json:"content"`
+	}
+	if err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": args}, &res); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, item := range res.Content {
+		switch {
+		case item.Type == "json":
+			return json.Unmarshal(item.JSON, out)
+		case item.Type == "text" && strings.HasPrefix(strings.TrimSpace(item.Text), "{"):
+			return json.Unmarshal([]byte(item.Text), out)
+		}
+	}
+	return fmt.Errorf("%s: response has no JSON content", name)
+}
+
+func (c *Client) call(ctx context.Context, method string, params, out any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	payload, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(payload), payload); err != nil {
+		return fmt.Errorf("writing request: %w", err)
+	}
+
+	data, err := c.read()
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    json.RawMessage `json:"code"`
+			Message string          `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if string(resp.ID) != strconv.Itoa(c.nextID) {
+		return fmt.Errorf("response id %s does not match request id %d", resp.ID, c.nextID)
+	}
+	if resp.Error != nil {
+		code := strings.Trim(string(resp.Error.Code), `"`)
+		return &Error{Code: code, Message: resp.Error.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, out)
+}
+
+// read returns the body of the next framed message.
+func (c *Client) read() ([]byte, error) {
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("server closed the connection")
+			}
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if length < 0 {
+				continue // blank lines before the headers
+			}
+			break
+		}
+		if v, ok := cutHeader(line, "content-length"); ok {
+			if length, err = strconv.Atoi(v); err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid Content-Length %q", v)
+			}
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	return bytes.TrimSpace(body), nil
+}
+
+func cutHeader(line, name string) (string, bool) {
+	k, v, ok := strings.Cut(line, ":")
+	if !ok || !strings.EqualFold(strings.TrimSpace(k), name) {
+		return "", false
+	}
+	return strings.TrimSpace(v), true
+}
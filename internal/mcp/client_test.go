@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package mcp
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer answers framed requests on r/w with handle until r closes.
+func fakeServer(t *testing.T, r io.Reader, w io.Writer, handle func(method string, params json.RawMessage) (any, map[string]any)) {
+	t.Helper()
+	br := bufio.NewReader(r)
+	for {
+		length := 0
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				break
+			}
+			if v, ok := strings.CutPrefix(line, "Content-Length: "); ok {
+				length, _ = strconv.Atoi(v)
+			}
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(br, body); err != nil {
+			return
+		}
+		var req struct {
+			ID     int             `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("bad request: %v", err)
+			return
+		}
+		result, rpcErr := handle(req.Method, req.Params)
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+		data, _ := json.Marshal(resp)
+		_, _ = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+}
+
+func newTestClient(t *testing.T, handle func(string, json.RawMessage) (any, map[string]any)) *Client {
+	t.Helper()
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	go func() {
+		fakeServer(t, reqR, respW, handle)
+		_ = respW.Close()
+	}()
+	t.Cleanup(func() { _ = reqW.Close() })
+	return NewClient(respR, reqW)
+}
+
+func TestCallTool(t *testing.T) {
+	var calls []string
+	c := newTestClient(t, func(method string, params json.RawMessage) (any, map[string]any) {
+		calls = append(calls, method)
+		if method == "initialize" {
+			return map[string]any{"protocolVersion": "2024-11-05"}, nil
+		}
+		var p struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		require.NoError(t, json.Unmarshal(params, &p))
+		assert.Equal(t, "snapshot.list", p.Name)
+		assert.Equal(t, "/repo", p.Arguments["repo_root"])
+		return map[string]any{"content": []any{map[string]any{"type": "json", "json": map[string]any{"total": 2}}}}, nil
+	})
+
+	require.NoError(t, c.Initialize(context.Background()))
+	var out struct {
+		Total int `json:"total"`
+	}
+	require.NoError(t, c.CallTool(context.Background(), "snapshot.list", map[string]any{"repo_root": "/repo"}, &out))
+	assert.Equal(t, 2, out.Total)
+	assert.Equal(t, []string{"initialize", "tools/call"}, calls)
+}
+
+func TestCallTool_Error(t *testing.T) {
+	c := newTestClient(t, func(string, json.RawMessage) (any, map[string]any) {
+		return nil, map[string]any{"code": "NOT_FOUND", "message": "snapshot not found"}
+	})
+
+	err := c.CallTool(context.Background(), "snapshot.list", nil, &struct{}{})
+	var rpcErr *Error
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.Equal(t, "NOT_FOUND", rpcErr.Code)
+	assert.Equal(t, "snapshot.list: NOT_FOUND: snapshot not found", err.Error())
+}
+
+func TestListTools(t *testing.T) {
+	c := newTestClient(t, func(method string, _ json.RawMessage) (any, map[string]any) {
+		assert.Equal(t, "tools/list", method)
+		return map[string]any{"tools": []any{
+			map[string]any{"name": "snapshot.info", "description": "Get info", "inputSchema": map[string]any{"type": "object"}},
+		}}, nil
+	})
+
+	tools, err := c.ListTools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "snapshot.info", tools[0].Name)
+	assert.JSONEq(t, `{"type":"object"}`, string(tools[0].InputSchema))
+}
+
+func TestResolveBin(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(BinEnv, "")
+
+	_, err := ResolveBin("", root)
+	assert.Error(t, err)
+
+	debug := filepath.Join(root, "rust", "target", "debug", "cortex-mcp")
+	require.NoError(t, os.MkdirAll(filepath.Dir(debug), 0o755))
+	require.NoError(t, os.WriteFile(debug, nil, 0o755))
+	got, err := ResolveBin("", root)
+	require.NoError(t, err)
+	assert.Equal(t, debug, got)
+
+	t.Setenv(BinEnv, "/env/cortex-mcp")
+	got, _ = ResolveBin("", root)
+	assert.Equal(t, "/env/cortex-mcp", got)
+
+	got, _ = ResolveBin("/flag/cortex-mcp", root)
+	assert.Equal(t, "/flag/cortex-mcp", got)
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mcpschema
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/snapshot"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// DefaultFixture is the golden copy of the tools cortex-mcp registers,
+// relative to the repository root.
+const DefaultFixture = "spec/fixtures/mcp/tools.json"
+
+// Bindings are the Go argument structs of the tools Cortex calls, by tool
+// name.
+var Bindings = snapshot.ToolArgs
+
+// Report is the outcome of checking the tools of a server.
+type Report struct {
+	Tools    int       `json:"tools"`
+	Bound    int       `json:"bound"`
+	Problems []Problem `json:"problems"`
+	// Diff goes from the fixture to the server's tools; nil when they match.
+	Diff *gov.Diff `json:"diff"`
+}
+
+// Failed reports whether a problem or drift was found.
+func (r *Report) Failed() bool {
+	return len(r.Problems) > 0 || r.Diff != nil
+}
+
+// Check lints tools against Bindings and compares them with fixture, a
+// path relative to repoRoot unless absolute.
+func Check(tools []mcp.Tool, repoRoot, fixture string) (*Report, error) {
+	r := &Report{Tools: len(tools), Bound: len(Bindings), Problems: Lint(tools, Bindings)}
+	if r.Problems == nil {
+		r.Problems = []Problem{}
+	}
+	err := Compare(tools, repoRoot, fixture)
+	var drift *gov.DriftError
+	switch {
+	case errors.As(err, &drift):
+		r.Diff = &drift.Diff
+	case err != nil:
+		return nil, err
+	}
+	return r, nil
+}
+
+// Canonical renders tools as the golden fixture: sorted by name, object
+// keys sorted, indented by two spaces.
+func Canonical(tools []mcp.Tool) ([]byte, error) {
+	type entry struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		InputSchema any    `json:"inputSchema"`
+	}
+	entries := make([]entry, 0, len(tools))
+	for _, t := range tools {
+		var s any
+		if err := json.Unmarshal(t.InputSchema, &s); err != nil {
+			return nil, fmt.Errorf("tool %s: invalid input schema: %w", t.Name, err)
+		}
+		entries = append(entries, entry{Name: t.Name, Description: t.Description, InputSchema: s})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]any{"tools": entries}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Compare diffs the canonical form of tools against fixture, a path
+// relative to repoRoot unless absolute. On mismatch it returns a
+// *gov.DriftError whose diff goes from the fixture to the server's tools.
+func Compare(tools []mcp.Tool, repoRoot, fixture string) error {
+	got, err := Canonical(tools)
+	if err != nil {
+		return err
+	}
+	path := fixture
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+	if bytes.Equal(got, want) {
+		return nil
+	}
+	return &gov.DriftError{
+		Message: fmt.Sprintf("MCP tool schema drift detected (fixture %s)", fixture),
+		Diff:    gov.UnifiedDiff(fixture, "cortex-mcp", string(want), string(got), gov.DefaultDiffOptions()),
+	}
+}
+
+// ListTools starts the cortex-mcp binary bin and returns the tools it
+// registers. The server runs in a temporary directory so that the store it
+// opens on start does not land in the repository.
+func ListTools(ctx context.Context, bin string, stderr io.Writer) ([]mcp.Tool, error) {
+	dir, err := os.MkdirTemp("", "cortex-mcp-schema-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	client, err := mcp.Start(ctx, bin, dir, stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+	return client.ListTools(ctx)
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package mcpschema checks the tools registered by cortex-mcp: their input
+// schemas are linted against the Go argument structs that call them, and
+// compared with a golden fixture so schema changes are reviewed before
+// clients break.
+package mcpschema
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/mcp"
+)
+
+// Problem is a schema or binding defect of one tool.
+type Problem struct {
+	Tool    string `json:"tool"`
+	Message string `json:"message"`
+}
+
+// schema is the subset of JSON Schema used by tool input schemas.
+type schema struct {
+	Type       typeList           `json:"type"`
+	Properties map[string]*schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *schema            `json:"items"`
+}
+
+// typeList is a schema "type": one name or a list of names.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = many
+	return nil
+}
+
+func (t typeList) has(name string) bool {
+	for _, v := range t {
+		if v == name || (v == "number" && name == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks every tool's input schema and the argument struct bound to
+// it in args (tool name to a struct value). Problems are sorted by tool.
+func Lint(tools []mcp.Tool, args map[string]any) []Problem {
+	var problems []Problem
+	add := func(tool, format string, a ...any) {
+		problems = append(problems, Problem{Tool: tool, Message: fmt.Sprintf(format, a...)})
+	}
+
+	registered := map[string]*schema{}
+	for _, tool := range tools {
+		if _, dup := registered[tool.Name]; dup {
+			add(tool.Name, "registered more than once")
+			continue
+		}
+		var s schema
+		if err := json.Unmarshal(tool.InputSchema, &s); err != nil {
+			add(tool.Name, "invalid input schema: %v", err)
+			registered[tool.Name] = nil
+			continue
+		}
+		registered[tool.Name] = &s
+		for _, msg := range checkSchema(&s) {
+			add(tool.Name, "%s", msg)
+		}
+	}
+
+	for _, name := range sortedKeys(args) {
+		s, ok := registered[name]
+		switch {
+		case !ok:
+			add(name, "not registered by the server")
+		case s != nil:
+			for _, msg := range checkArgs(s, reflect.TypeOf(args[name])) {
+				add(name, "%s", msg)
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Tool < problems[j].Tool })
+	return problems
+}
+
+// checkSchema checks that s describes an object whose properties are typed
+// and whose required properties exist.
+func checkSchema(s *schema) []string {
+	var msgs []string
+	if !s.Type.has("object") {
+		msgs = append(msgs, `input schema type must be "object"`)
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		if p := s.Properties[name]; p == nil || len(p.Type) == 0 {
+			msgs = append(msgs, fmt.Sprintf("property %q has no type", name))
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			msgs = append(msgs, fmt.Sprintf("required property %q is not defined", name))
+		}
+	}
+	return msgs
+}
+
+// checkArgs compares the JSON fields of struct type t with schema s: every
+// field must be a property of a matching type, and every required property
+// must be a field the client always sends.
+func checkArgs(s *schema, t reflect.Type) []string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return []string{fmt.Sprintf("argument type %v is not a struct", t)}
+	}
+
+	var msgs []string
+	fields := map[string]bool{} // JSON name to omitempty
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitempty, ok := jsonField(f)
+		if !ok {
+			continue
+		}
+		fields[name] = omitempty
+		p, defined := s.Properties[name]
+		if !defined {
+			msgs = append(msgs, fmt.Sprintf("field %s.%s (%q) is not in the input schema", t.Name(), f.Name, name))
+			continue
+		}
+		if want := jsonType(f.Type); p != nil && len(p.Type) > 0 && !p.Type.has(want) {
+			msgs = append(msgs, fmt.Sprintf("field %s.%s (%q) is %s, schema says %s", t.Name(), f.Name, name, want, strings.Join(p.Type, "|")))
+		}
+	}
+	for _, name := range s.Required {
+		omitempty, ok := fields[name]
+		switch {
+		case !ok:
+			msgs = append(msgs, fmt.Sprintf("required property %q has no field in %s", name, t.Name()))
+		case omitempty:
+			msgs = append(msgs, fmt.Sprintf("required property %q is omitempty in %s", name, t.Name()))
+		}
+	}
+	return msgs
+}
+
+// jsonField returns the JSON name of an exported field, as encoding/json
+// would encode it.
+func jsonField(f reflect.StructField) (name string, omitempty, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), true
+}
+
+// jsonType is the JSON Schema type encoding/json produces for t.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64
+		}
+		return "array"
+	case reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package mcpschema
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+type listArgs struct {
+	RepoRoot string   `json:"repo_root"`
+	Path     string   `json:"path,omitempty"`
+	Limit    int      `json:"limit,omitempty"`
+	Paths    []string `json:"paths,omitempty"`
+	Ignored  string   `json:"-"`
+}
+
+func tool(name, schema string) mcp.Tool {
+	return mcp.Tool{Name: name, Description: name, InputSchema: json.RawMessage(schema)}
+}
+
+func TestLint_Clean(t *testing.T) {
+	tools := []mcp.Tool{
+		tool("list", `{"type":"object","properties":{"repo_root":{"type":"string"},"path":{"type":"string"},"limit":{"type":"integer"},"paths":{"type":"array","items":{"type":"string"}}},"required":["repo_root"]}`),
+		tool("other", `{"type":"object","properties":{}}`),
+	}
+	assert.Empty(t, Lint(tools, map[string]any{"list": listArgs{}}))
+}
+
+func TestLint_Problems(t *testing.T) {
+	tools := []mcp.Tool{
+		tool("list", `{"type":"object","properties":{"repo_root":{"type":"string"},"path":{"type":"string"},"limit":{"type":"string"},"mode":{"type":"string"}},"required":["repo_root","path","mode"]}`),
+		tool("broken", `{"type":"array","properties":{"x":{}},"required":["y"]}`),
+		tool("broken", `{"type":"object"}`),
+	}
+	problems := Lint(tools, map[string]any{"list": listArgs{}, "gone": listArgs{}})
+
+	assert.Equal(t, []Problem{
+		{Tool: "broken", Message: `input schema type must be "object"`},
+		{Tool: "broken", Message: `property "x" has no type`},
+		{Tool: "broken", Message: `required property "y" is not defined`},
+		{Tool: "broken", Message: "registered more than once"},
+		{Tool: "gone", Message: "not registered by the server"},
+		{Tool: "list", Message: `field listArgs.Limit ("limit") is integer, schema says string`},
+		{Tool: "list", Message: `field listArgs.Paths ("paths") is not in the input schema`},
+		{Tool: "list", Message: `required property "path" is omitempty in listArgs`},
+		{Tool: "list", Message: `required property "mode" has no field in listArgs`},
+	}, problems)
+}
+
+func TestCanonical_SortsToolsAndKeys(t *testing.T) {
+	data, err := Canonical([]mcp.Tool{
+		tool("b", `{"type":"object","properties":{}}`),
+		tool("a", `{"required":[],"type":"object"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "tools": [
+    {
+      "name": "a",
+      "description": "a",
+      "inputSchema": {
+        "required": [],
+        "type": "object"
+      }
+    },
+    {
+      "name": "b",
+      "description": "b",
+      "inputSchema": {
+        "properties": {},
+        "type": "object"
+      }
+    }
+  ]
+}
+`, string(data))
+}
+
+func TestCompare_Drift(t *testing.T) {
+	dir := t.TempDir()
+	tools := []mcp.Tool{tool("a", `{"type":"object"}`)}
+	data, err := Canonical(tools)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools.json"), data, 0o644))
+
+	require.NoError(t, Compare(tools, dir, "tools.json"))
+
+	err = Compare([]mcp.Tool{tool("a", `{"type":"object","properties":{}}`)}, dir, "tools.json")
+	var drift *gov.DriftError
+	require.True(t, errors.As(err, &drift), "got %v", err)
+	assert.Equal(t, "tools.json", drift.Diff.OldName)
+	assert.Contains(t, drift.Diff.String(), `+        "properties": {},`)
+}
+
+// TestBindings_MatchFixture lints the committed fixture against the Go
+// argument structs, so a struct change that the server would reject fails
+// without a cortex-mcp build.
+func TestBindings_MatchFixture(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", DefaultFixture))
+	require.NoError(t, err)
+	var fixture struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(data, &fixture))
+
+	report, err := Check(fixture.Tools, filepath.Join("..", ".."), DefaultFixture)
+	require.NoError(t, err)
+	assert.Empty(t, report.Problems)
+	assert.Nil(t, report.Diff, "fixture is not in canonical form")
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package meta governs a workspace of repositories listed in a manifest:
+// it clones the remote ones, runs skills across all of them and aggregates
+// their features and run state into one dashboard.
+package meta
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the default manifest, relative to the working directory.
+const ManifestFile = "cortex-meta.yaml"
+
+// CacheDir is where repositories listed by URL are cloned, relative to the
+// manifest directory.
+const CacheDir = ".cortex/meta/repos"
+
+// Manifest lists the repositories of a workspace and the run profiles.
+type Manifest struct {
+	// Dir is the directory of the manifest; relative repository paths are
+	// resolved against it.
+	Dir      string             `yaml:"-"`
+	Repos    []Repo             `yaml:"repos"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Repo is a repository of the workspace, either a local Path or a git URL
+// cloned into CacheDir and checked out at Ref (the remote HEAD when empty).
+type Repo struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+	URL  string `yaml:"url"`
+	Ref  string `yaml:"ref"`
+}
+
+// Remote reports whether the repository is cloned from a URL.
+func (r Repo) Remote() bool { return r.URL != "" }
+
+// Profile selects what `meta run` runs in each repository.
+type Profile struct {
+	// Skills lists the skill IDs to run; empty runs every skill.
+	Skills []string `yaml:"skills"`
+	// FailOnWarning fails skills that only found warnings.
+	FailOnWarning bool `yaml:"fail_on_warning"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Load reads and validates the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from a CLI flag
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	m.Dir = dir
+	return m, nil
+}
+
+// Parse decodes and validates manifest content. A repository without a
+// name is named after the last element of its path or URL.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if len(m.Repos) == 0 {
+		return nil, errors.New("no repos listed")
+	}
+
+	seen := map[string]bool{}
+	for i := range m.Repos {
+		r := &m.Repos[i]
+		switch {
+		case r.Path != "" && r.URL != "":
+			return nil, fmt.Errorf("repo %d: set either path or url, not both", i+1)
+		case r.Path == "" && r.URL == "":
+			return nil, fmt.Errorf("repo %d: path or url is required", i+1)
+		case r.Ref != "" && r.URL == "":
+			return nil, fmt.Errorf("repo %d: ref is only valid with url", i+1)
+		}
+		if r.Name == "" {
+			r.Name = defaultName(*r)
+		}
+		if !namePattern.MatchString(r.Name) {
+			return nil, fmt.Errorf("repo %d: invalid name %q", i+1, r.Name)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("repo %q is listed twice", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return &m, nil
+}
+
+// defaultName is the last element of the path or URL, without ".git".
+func defaultName(r Repo) string {
+	s := r.Path
+	if r.Remote() {
+		s = r.URL
+		// scp-like URLs: git@host:org/repo.git
+		if i := strings.LastIndex(s, ":"); i >= 0 && !strings.Contains(s, "://") {
+			s = s[i+1:]
+		}
+	}
+	s = strings.TrimSuffix(strings.TrimRight(filepath.ToSlash(s), "/"), ".git")
+	return path.Base(s)
+}
+
+// Root returns the directory of r.
+func (m *Manifest) Root(r Repo) string {
+	if r.Remote() {
+		return filepath.Join(m.Dir, filepath.FromSlash(CacheDir), r.Name)
+	}
+	if filepath.IsAbs(r.Path) {
+		return r.Path
+	}
+	return filepath.Join(m.Dir, filepath.FromSlash(r.Path))
+}
+
+// Profile returns the named profile; the empty name runs every skill.
+func (m *Manifest) Profile(name string) (Profile, error) {
+	if name == "" {
+		return Profile{}, nil
+	}
+	p, ok := m.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(m.Profiles))
+		for n := range m.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Profile{}, fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
+// Select returns the repositories named in names, in manifest order; all of
+// them when names is empty.
+func (m *Manifest) Select(names []string) ([]Repo, error) {
+	if len(names) == 0 {
+		return m.Repos, nil
+	}
+	want := map[string]bool{}
+	for _, n := range names {
+		want[n] = true
+	}
+	var out []Repo
+	for _, r := range m.Repos {
+		if want[r.Name] {
+			out = append(out, r)
+			delete(want, r.Name)
+		}
+	}
+	if len(want) > 0 {
+		var unknown []string
+		for n := range want {
+			unknown = append(unknown, n)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown repo(s): %s", strings.Join(unknown, ", "))
+	}
+	return out, nil
+}
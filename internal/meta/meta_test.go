@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package meta
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/git"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := git.Run(context.Background(), dir, append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+// writeRepo creates a git repository at dir with features.yaml content.
+func writeRepo(t *testing.T, dir, features string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "spec"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec", "features.yaml"), []byte(features), 0o644))
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-qm", "init")
+}
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(`repos:
+  - path: ../services/api/
+  - url: git@github.com:org/web.git
+    ref: main
+  - name: docs
+    url: https://example.com/org/handbook
+profiles:
+  ci:
+    skills: [test:go]
+    fail_on_warning: true
+`))
+	require.NoError(t, err)
+	var names []string
+	for _, r := range m.Repos {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"api", "web", "docs"}, names)
+
+	p, err := m.Profile("ci")
+	require.NoError(t, err)
+	assert.Equal(t, Profile{Skills: []string{"test:go"}, FailOnWarning: true}, p)
+	_, err = m.Profile("nightly")
+	assert.EqualError(t, err, `unknown profile "nightly" (defined: ci)`)
+
+	repos, err := m.Select([]string{"docs", "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "api", repos[0].Name)
+	_, err = m.Select([]string{"api", "x", "b"})
+	assert.EqualError(t, err, "unknown repo(s): b, x")
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		manifest string
+		want     string
+	}{
+		{"repos: []\n", "no repos listed"},
+		{"repos:\n  - name: a\n", "repo 1: path or url is required"},
+		{"repos:\n  - path: a\n    url: b\n", "repo 1: set either path or url, not both"},
+		{"repos:\n  - path: a\n    ref: main\n", "repo 1: ref is only valid with url"},
+		{"repos:\n  - path: a\n  - path: x/a\n", `repo "a" is listed twice`},
+		{"repos:\n  - name: a b\n    path: a\n", `repo 1: invalid name "a b"`},
+		{"repos:\n  - pth: a\n", "field pth not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			_, err := Parse([]byte(tt.manifest))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestSyncAndStatus(t *testing.T) {
+	ws := t.TempDir()
+	writeRepo(t, filepath.Join(ws, "api"), `features:
+  - id: AUTH
+    title: Auth
+    governance: approved
+    implementation: done
+    owner: core
+  - id: SHARED
+    title: Shared
+    governance: draft
+    implementation: todo
+`)
+	origin := filepath.Join(t.TempDir(), "web")
+	writeRepo(t, origin, `features:
+  - id: SHARED
+    title: Shared
+    governance: approved
+    implementation: wip
+`)
+	manifest := filepath.Join(ws, ManifestFile)
+	require.NoError(t, os.WriteFile(manifest, []byte("repos:\n  - path: api\n  - url: "+origin+"\n  - path: missing\n"), 0o644))
+	m, err := Load(manifest)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	d := Status(ctx, m, m.Repos)
+	assert.Equal(t, "not cloned; run cortex meta sync", d.Repos[1].Error)
+
+	results := Sync(ctx, m, m.Repos)
+	require.Len(t, results, 3)
+	assert.Equal(t, ActionLocal, results[0].Action)
+	assert.Equal(t, ActionCloned, results[1].Action)
+	assert.Equal(t, runGit(t, origin, "rev-parse", "HEAD"), results[1].Commit)
+	assert.Contains(t, results[2].Error, "no such file or directory")
+
+	require.NoError(t, os.WriteFile(filepath.Join(origin, "README.md"), []byte("web\n"), 0o644))
+	runGit(t, origin, "add", "-A")
+	runGit(t, origin, "commit", "-qm", "readme")
+	results = Sync(ctx, m, m.Repos[1:2])
+	assert.Equal(t, ActionUpdated, results[0].Action)
+	assert.Equal(t, runGit(t, origin, "rev-parse", "HEAD"), results[0].Commit)
+	assert.FileExists(t, filepath.Join(ws, CacheDir, "web", "README.md"))
+
+	d = Status(ctx, m, m.Repos)
+	require.Len(t, d.Repos, 3)
+	assert.Equal(t, 2, d.Repos[0].Features)
+	assert.Equal(t, 50.0, d.Repos[0].Completion)
+	assert.Equal(t, 1, d.Repos[1].WIP)
+	assert.NotEmpty(t, d.Repos[2].Error)
+	assert.Equal(t, []string{"SHARED"}, d.Shared)
+	var rows []string
+	for _, f := range d.Features {
+		rows = append(rows, f.ID+"@"+f.Repo)
+	}
+	assert.Equal(t, []string{"AUTH@api", "SHARED@api", "SHARED@web"}, rows)
+
+	md := d.Markdown()
+	assert.Contains(t, md, "3 repositories, 3 features, 33.3% done.")
+	assert.Contains(t, md, "| api | 2 | 1 | 0 | 1 | 50.0% | - |")
+	assert.Contains(t, md, "## Shared Feature IDs\n\n- `SHARED`\n")
+}
+
+func TestRun_NotCloned(t *testing.T) {
+	m, err := Parse([]byte("repos:\n  - url: https://example.com/org/web.git\n"))
+	require.NoError(t, err)
+	m.Dir = t.TempDir()
+
+	var out strings.Builder
+	sum := Run(context.Background(), m, m.Repos, "", Profile{}, &out)
+	assert.Equal(t, 2, sum.ExitCode)
+	assert.Equal(t, "not cloned; run cortex meta sync", sum.Repos[0].Error)
+	assert.Equal(t, "── web\n", out.String())
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package meta
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/pkg/cortexrun"
+)
+
+// RepoRun is the outcome of a run in one repository. Error is set when the
+// run could not start; failing skills are in Report.
+type RepoRun struct {
+	Repo     string            `json:"repo"`
+	ExitCode int               `json:"exit_code"`
+	Error    string            `json:"error,omitempty"`
+	Report   *cortexrun.Report `json:"report,omitempty"`
+}
+
+// RunSummary aggregates the runs of a workspace.
+type RunSummary struct {
+	Profile string    `json:"profile,omitempty"`
+	Repos   []RepoRun `json:"repos"`
+	// ExitCode is the highest exit code of the repositories.
+	ExitCode int `json:"exit_code"`
+}
+
+// Run runs the profile in each repository in turn, recording state in the
+// repository as `cortex run` does. The progress log of each run is written
+// to out.
+func Run(ctx context.Context, m *Manifest, repos []Repo, profileName string, profile Profile, out io.Writer) RunSummary {
+	sum := RunSummary{Profile: profileName, Repos: make([]RepoRun, 0, len(repos))}
+	for _, r := range repos {
+		_, _ = fmt.Fprintf(out, "── %s\n", r.Name)
+		rr := runRepo(ctx, m.Root(r), r, profile, out)
+		if rr.ExitCode > sum.ExitCode {
+			sum.ExitCode = rr.ExitCode
+		}
+		sum.Repos = append(sum.Repos, rr)
+	}
+	return sum
+}
+
+func runRepo(ctx context.Context, root string, r Repo, profile Profile, out io.Writer) RepoRun {
+	rr := RepoRun{Repo: r.Name}
+	if _, err := os.Stat(root); err != nil {
+		rr.ExitCode = runner.ExitConfig
+		rr.Error = err.Error()
+		if r.Remote() {
+			rr.Error = "not cloned; run cortex meta sync"
+		}
+		return rr
+	}
+
+	opts := []cortexrun.Option{cortexrun.WithOutput(out), cortexrun.WithFailOnWarning(profile.FailOnWarning)}
+	if len(profile.Skills) > 0 {
+		opts = append(opts, cortexrun.WithSkills(profile.Skills...))
+	}
+	report, err := cortexrun.Run(ctx, root, opts...)
+	if err != nil {
+		rr.ExitCode = runner.ExitExecution
+		if errors.Is(err, cortexrun.ErrConfig) || errors.Is(err, cortexrun.ErrUnknownSkill) {
+			rr.ExitCode = runner.ExitConfig
+		}
+		rr.Error = err.Error()
+		return rr
+	}
+	rr.Report = report
+	rr.ExitCode = report.ExitCode
+	return rr
+}
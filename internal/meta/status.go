@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package meta
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// RepoStatus is the state of one repository of the dashboard.
+type RepoStatus struct {
+	Repo   string `json:"repo"`
+	Commit string `json:"commit,omitempty"`
+	// Error is set when the repository or its registry cannot be read.
+	Error      string  `json:"error,omitempty"`
+	Features   int     `json:"features"`
+	Done       int     `json:"done"`
+	WIP        int     `json:"wip"`
+	Todo       int     `json:"todo"`
+	Completion float64 `json:"completion"`
+	// LastRun is "pass", "fail", or empty when nothing ran yet.
+	LastRun string   `json:"last_run,omitempty"`
+	Failed  []string `json:"failed"`
+}
+
+// FeatureRow is a feature of one repository.
+type FeatureRow struct {
+	ID             string `json:"id"`
+	Repo           string `json:"repo"`
+	Title          string `json:"title"`
+	Governance     string `json:"governance"`
+	Implementation string `json:"implementation"`
+	Owner          string `json:"owner"`
+}
+
+// Dashboard is the cross-repository status of a workspace.
+type Dashboard struct {
+	Repos    []RepoStatus `json:"repos"`
+	Features []FeatureRow `json:"features"`
+	// Shared lists the feature IDs defined in more than one repository.
+	Shared []string `json:"shared"`
+}
+
+// Status reads the feature registry (spec/features.yaml) and last run
+// (.cortex/run) of each repository. Features are sorted by ID, then repo.
+func Status(ctx context.Context, m *Manifest, repos []Repo) Dashboard {
+	d := Dashboard{Repos: []RepoStatus{}, Features: []FeatureRow{}, Shared: []string{}}
+	for _, r := range repos {
+		root := m.Root(r)
+		rs := RepoStatus{Repo: r.Name, Failed: []string{}}
+		feats, err := repoStatus(ctx, root, &rs)
+		if err != nil {
+			rs.Error = err.Error()
+			if r.Remote() && errors.Is(err, os.ErrNotExist) {
+				rs.Error = "not cloned; run cortex meta sync"
+			}
+		}
+		for _, f := range feats {
+			d.Features = append(d.Features, FeatureRow{
+				ID:             f.ID,
+				Repo:           r.Name,
+				Title:          f.Title,
+				Governance:     string(f.Governance),
+				Implementation: string(f.Implementation),
+				Owner:          f.Owner,
+			})
+		}
+		d.Repos = append(d.Repos, rs)
+	}
+
+	sort.SliceStable(d.Features, func(i, j int) bool {
+		if d.Features[i].ID != d.Features[j].ID {
+			return d.Features[i].ID < d.Features[j].ID
+		}
+		return d.Features[i].Repo < d.Features[j].Repo
+	})
+	for i := 1; i < len(d.Features); i++ {
+		id := d.Features[i].ID
+		if id == d.Features[i-1].ID && (len(d.Shared) == 0 || d.Shared[len(d.Shared)-1] != id) {
+			d.Shared = append(d.Shared, id)
+		}
+	}
+	return d
+}
+
+// repoStatus fills rs and returns the features of the repository at root.
+func repoStatus(ctx context.Context, root string, rs *RepoStatus) ([]gov.Feature, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+	rs.Commit, _ = git.RevParse(ctx, root, "HEAD")
+
+	last, err := runner.NewStateStore(filepath.Join(root, ".cortex", "run")).ReadLastRun()
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		rs.LastRun = last.Status
+		rs.Failed = append(rs.Failed, last.Failed...)
+	}
+
+	featuresPath := filepath.Join(root, "spec", "features.yaml")
+	if _, err := os.Stat(featuresPath); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	reg, err := gov.LoadRegistry(featuresPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range reg.Features {
+		switch f.Implementation {
+		case gov.ImplDone:
+			rs.Done++
+		case gov.ImplWip:
+			rs.WIP++
+		case gov.ImplTodo:
+			rs.Todo++
+		}
+	}
+	rs.Features = len(reg.Features)
+	if rs.Features > 0 {
+		rs.Completion = float64(rs.Done) / float64(rs.Features) * 100
+	}
+	return reg.Features, nil
+}
+
+// Markdown renders the dashboard.
+func (d Dashboard) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(projection.RenderHeader(1, "Workspace Status"))
+
+	var features, done int
+	rows := make([][]string, 0, len(d.Repos))
+	for _, r := range d.Repos {
+		features += r.Features
+		done += r.Done
+		run := r.LastRun
+		switch {
+		case r.Error != "":
+			run = "error: " + r.Error
+		case run == "":
+			run = "-"
+		case len(r.Failed) > 0:
+			run += " (" + strings.Join(r.Failed, ", ") + ")"
+		}
+		rows = append(rows, []string{
+			r.Repo,
+			fmt.Sprint(r.Features),
+			fmt.Sprint(r.Done),
+			fmt.Sprint(r.WIP),
+			fmt.Sprint(r.Todo),
+			fmt.Sprintf("%.1f%%", r.Completion),
+			strings.ReplaceAll(run, "|", `\|`),
+		})
+	}
+	completion := 0.0
+	if features > 0 {
+		completion = float64(done) / float64(features) * 100
+	}
+	fmt.Fprintf(&sb, "%d repositories, %d features, %.1f%% done.\n\n", len(d.Repos), features, completion)
+	sb.WriteString(projection.RenderTable([]string{"Repository", "Features", "Done", "WIP", "Todo", "Completion", "Last Run"}, rows))
+
+	if len(d.Shared) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(projection.RenderHeader(2, "Shared Feature IDs"))
+		items := make([]string, len(d.Shared))
+		for i, id := range d.Shared {
+			items[i] = "`" + id + "`"
+		}
+		sb.WriteString(projection.RenderList(items))
+	}
+
+	if len(d.Features) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(projection.RenderHeader(2, "Features"))
+		rows = rows[:0]
+		for _, f := range d.Features {
+			rows = append(rows, []string{"`" + f.ID + "`", f.Repo, strings.ReplaceAll(f.Title, "|", `\|`), f.Implementation, f.Governance, f.Owner})
+		}
+		sb.WriteString(projection.RenderTable([]string{"Feature", "Repository", "Title", "Implementation", "Governance", "Owner"}, rows))
+	}
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package meta
+
+// Feature: CLI_COMMAND_META
+// Spec: spec/cli/meta.md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/git"
+)
+
+// Sync actions.
+const (
+	ActionCloned  = "cloned"
+	ActionUpdated = "updated"
+	ActionLocal   = "local"
+)
+
+// SyncResult is what Sync did to a repository.
+type SyncResult struct {
+	Repo   string `json:"repo"`
+	Action string `json:"action,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Sync clones the remote repositories that are not cloned yet, fetches
+// the others, and checks out their ref detached. Local repositories are
+// only checked to exist. A failing repository does not stop the others.
+func Sync(ctx context.Context, m *Manifest, repos []Repo) []SyncResult {
+	out := make([]SyncResult, 0, len(repos))
+	for _, r := range repos {
+		res := SyncResult{Repo: r.Name}
+		action, err := syncRepo(ctx, m.Root(r), r)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Action = action
+			res.Commit, _ = git.RevParse(ctx, m.Root(r), "HEAD")
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+func syncRepo(ctx context.Context, root string, r Repo) (string, error) {
+	_, statErr := os.Stat(root)
+	if !r.Remote() {
+		if statErr != nil {
+			return "", fmt.Errorf("%s: %w", root, statErr)
+		}
+		return ActionLocal, nil
+	}
+
+	action := ActionUpdated
+	if errors.Is(statErr, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(root), 0o755); err != nil {
+			return "", err
+		}
+		if _, err := git.Run(ctx, "", "clone", "--quiet", "--no-checkout", r.URL, root); err != nil {
+			return "", err
+		}
+		action = ActionCloned
+	} else if statErr != nil {
+		return "", statErr
+	}
+
+	ref := r.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := git.Run(ctx, root, "fetch", "--quiet", "origin", ref); err != nil {
+		return "", err
+	}
+	if _, err := git.Run(ctx, root, "checkout", "--quiet", "--detach", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	return action, nil
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package owners
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// CodeOwnersPaths are the locations GitHub reads CODEOWNERS from, in the
+// order it looks; the first that exists is used.
+var CodeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is one line of a CODEOWNERS file.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+}
+
+// CodeOwners is a parsed CODEOWNERS file.
+type CodeOwners struct {
+	// Path is the repo-relative path of the file.
+	Path  string
+	Rules []Rule
+}
+
+// FindCodeOwners parses the first of CodeOwnersPaths under repoRoot. It
+// returns nil when there is none.
+func FindCodeOwners(repoRoot string) (*CodeOwners, error) {
+	for _, rel := range CodeOwnersPaths {
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(rel))) //nolint:gosec // fixed locations under the repo root
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		co, err := ParseCodeOwners(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		co.Path = rel
+		return co, nil
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses CODEOWNERS content: blank lines, "#" comments,
+// and lines of a pattern followed by owners. A pattern without owners
+// leaves the files it matches unowned.
+func ParseCodeOwners(data []byte) (*CodeOwners, error) {
+	co := &CodeOwners{}
+	for i, line := range strings.Split(string(data), "\n") {
+		if hash := strings.Index(line, "#"); hash >= 0 {
+			line = line[:hash]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := scanner.ValidateGlob(strings.TrimSuffix(fields[0], "/")); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		co.Rules = append(co.Rules, Rule{Pattern: fields[0], Owners: fields[1:], Line: i + 1})
+	}
+	return co, nil
+}
+
+// Match returns the last rule matching the slash-separated repo-relative
+// path p, as GitHub applies them, or nil. Patterns are matched as
+// scanner.MatchGlob globs once a trailing "/" is dropped: a pattern
+// without "/" matches any path element, one with "/" is anchored at the
+// root and matches the directories above a file too.
+func (co *CodeOwners) Match(p string) *Rule {
+	for i := len(co.Rules) - 1; i >= 0; i-- {
+		pattern := strings.TrimSuffix(co.Rules[i].Pattern, "/")
+		if pattern == "" || pattern == "*" || scanner.MatchGlob(pattern, p) {
+			return &co.Rules[i]
+		}
+	}
+	return nil
+}
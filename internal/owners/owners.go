@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package owners checks the owner declared by each feature in
+// spec/features.yaml against CODEOWNERS and against who commits to the
+// feature's files.
+package owners
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/git"
+)
+
+// Problem kinds.
+const (
+	// KindNoOwner is a feature with files but no owner.
+	KindNoOwner = "no-owner"
+	// KindUncovered is a feature file no CODEOWNERS rule assigns an owner.
+	KindUncovered = "uncovered"
+	// KindNotCodeOwner is a feature file whose code owners do not include
+	// the feature's owner.
+	KindNotCodeOwner = "not-code-owner"
+	// KindInactive is a feature whose owner committed to none of its files.
+	KindInactive = "inactive-owner"
+)
+
+// Feature is a feature with an owner and the repo-relative files that
+// belong to it: its spec and the files carrying its Feature header.
+type Feature struct {
+	ID    string   `json:"id"`
+	Owner string   `json:"owner"`
+	Files []string `json:"files"`
+}
+
+// Problem is one finding. Path is set for the kinds about a single file.
+type Problem struct {
+	Feature string `json:"feature"`
+	Owner   string `json:"owner"`
+	Kind    string `json:"kind"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s (owner %q): %s", p.Feature, p.Owner, p.Message)
+}
+
+// Input is what Check cross-references.
+type Input struct {
+	Features   []Feature
+	CodeOwners *CodeOwners
+	// Authors are the authors of the files in the history window, by path.
+	Authors map[string][]git.Author
+	// Since describes the history window in messages.
+	Since string
+	// Aliases maps an owner to the other identities it goes by.
+	Aliases map[string][]string
+}
+
+// Report is the outcome of Check.
+type Report struct {
+	CodeOwners string    `json:"codeowners"`
+	Since      string    `json:"since"`
+	Features   int       `json:"features"`
+	Files      int       `json:"files"`
+	Problems   []Problem `json:"problems"`
+}
+
+// LoadFeatures reads the features of featuresPath, relative to repoRoot,
+// and collects their files: the spec, and every Go file whose header
+// names the feature.
+func LoadFeatures(ctx context.Context, repoRoot, featuresPath string) ([]Feature, error) {
+	graph, err := features.LoadGraph(filepath.Join(repoRoot, featuresPath))
+	if err != nil {
+		return nil, err
+	}
+	specs, err := features.LoadFeaturesYAML(repoRoot, featuresPath)
+	if err != nil {
+		return nil, err
+	}
+	index, err := features.ScanSourceTree(ctx, repoRoot, specs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Feature, 0, len(graph.Nodes))
+	for id, node := range graph.Nodes {
+		files := map[string]bool{}
+		if node.Spec != "" {
+			files[node.Spec] = true
+		}
+		for _, refs := range [][]features.FileReference{index.Impls[id], index.Tests[id]} {
+			for _, ref := range refs {
+				rel, err := filepath.Rel(repoRoot, ref.File)
+				if err != nil {
+					return nil, err
+				}
+				files[filepath.ToSlash(rel)] = true
+			}
+		}
+		f := Feature{ID: id, Owner: node.Owner, Files: make([]string, 0, len(files))}
+		for path := range files {
+			f.Files = append(f.Files, path)
+		}
+		sort.Strings(f.Files)
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// Check reports, for every feature with files:
+//   - a missing owner;
+//   - each file no CODEOWNERS rule assigns an owner to;
+//   - each file whose code owners do not include the feature's owner;
+//   - an owner who authored none of the commits to its files in the window.
+func Check(in Input) *Report {
+	r := &Report{CodeOwners: in.CodeOwners.Path, Since: in.Since, Problems: []Problem{}}
+	for _, f := range in.Features {
+		if len(f.Files) == 0 {
+			continue
+		}
+		r.Features++
+		r.Files += len(f.Files)
+		add := func(kind, path, format string, args ...any) {
+			r.Problems = append(r.Problems, Problem{Feature: f.ID, Owner: f.Owner, Kind: kind, Path: path, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if f.Owner == "" {
+			add(KindNoOwner, "", "no owner declared in spec/features.yaml")
+		}
+		ids := identities(f.Owner, in.Aliases)
+
+		for _, path := range f.Files {
+			rule := in.CodeOwners.Match(path)
+			switch {
+			case rule == nil:
+				add(KindUncovered, path, "%s matches no rule in %s", path, in.CodeOwners.Path)
+			case len(rule.Owners) == 0:
+				add(KindUncovered, path, "%s is left unowned by %s:%d (%s)", path, in.CodeOwners.Path, rule.Line, rule.Pattern)
+			case f.Owner != "" && !anyIdentity(ids, rule.Owners...):
+				add(KindNotCodeOwner, path, "%s is owned by %s (%s:%d)", path, strings.Join(rule.Owners, " "), in.CodeOwners.Path, rule.Line)
+			}
+		}
+
+		if f.Owner == "" {
+			continue
+		}
+		touched, active := 0, false
+		for _, path := range f.Files {
+			authors := in.Authors[path]
+			if len(authors) > 0 {
+				touched++
+			}
+			for _, a := range authors {
+				local, _, _ := strings.Cut(a.Email, "@")
+				if anyIdentity(ids, a.Name, a.Email, local) {
+					active = true
+				}
+			}
+		}
+		if touched > 0 && !active {
+			window := "in the history"
+			if in.Since != "" {
+				window = "since " + in.Since
+			}
+			add(KindInactive, "", "authored none of the commits to its %d files changed %s", touched, window)
+		}
+	}
+	return r
+}
+
+// identities returns the normalized names owner goes by.
+func identities(owner string, aliases map[string][]string) map[string]bool {
+	ids := map[string]bool{}
+	if owner == "" {
+		return ids
+	}
+	ids[normalize(owner)] = true
+	for _, alias := range aliases[owner] {
+		ids[normalize(alias)] = true
+	}
+	return ids
+}
+
+// anyIdentity reports whether one of names is in ids.
+func anyIdentity(ids map[string]bool, names ...string) bool {
+	for _, n := range names {
+		if n != "" && ids[normalize(n)] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize makes "@Bart" and "bart" the same identity.
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "@"))
+}
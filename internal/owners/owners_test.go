@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package owners
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/git"
+)
+
+func TestParseCodeOwners_Match(t *testing.T) {
+	co, err := ParseCodeOwners([]byte(`# default
+*            @core
+
+/internal/   @platform @core  # trailing comment
+*.md         @docs
+/internal/generated/
+`))
+	require.NoError(t, err)
+	require.Len(t, co.Rules, 4)
+
+	tests := []struct {
+		path string
+		line int
+	}{
+		{"main.go", 2},
+		{"internal/owners/owners.go", 4},
+		{"internal/owners/README.md", 5},
+		{"internal/generated/api.go", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rule := co.Match(tt.path)
+			require.NotNil(t, rule)
+			assert.Equal(t, tt.line, rule.Line)
+		})
+	}
+	assert.Equal(t, []string{"@platform", "@core"}, co.Match("internal/x.go").Owners)
+	assert.Empty(t, co.Match("internal/generated/api.go").Owners)
+}
+
+func TestParseCodeOwners_InvalidPattern(t *testing.T) {
+	_, err := ParseCodeOwners([]byte("*   @core\n[oops  @core\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestFindCodeOwners(t *testing.T) {
+	root := t.TempDir()
+	co, err := FindCodeOwners(root)
+	require.NoError(t, err)
+	assert.Nil(t, co)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o644))
+
+	co, err = FindCodeOwners(root)
+	require.NoError(t, err)
+	require.NotNil(t, co)
+	assert.Equal(t, ".github/CODEOWNERS", co.Path)
+	assert.Equal(t, []string{"@github"}, co.Rules[0].Owners)
+}
+
+func TestCheck(t *testing.T) {
+	co, err := ParseCodeOwners([]byte("* @core\n/rust/ @rust\n/vendor/\n"))
+	require.NoError(t, err)
+	co.Path = "CODEOWNERS"
+
+	in := Input{
+		Features: []Feature{
+			{ID: "CORE", Owner: "core", Files: []string{"spec/core.md", "internal/core/core.go"}},
+			{ID: "ALIASED", Owner: "platform", Files: []string{"internal/platform/platform.go"}},
+			{ID: "RUST", Owner: "core", Files: []string{"rust/src/lib.rs"}},
+			{ID: "ORPHAN", Files: []string{"vendor/x.go"}},
+			{ID: "STALE", Owner: "core", Files: []string{"internal/stale/stale.go"}},
+			{ID: "EMPTY", Owner: "nobody"},
+		},
+		Authors: map[string][]git.Author{
+			"internal/core/core.go":         {{Name: "Someone", Email: "someone@example.com"}, {Name: "Core Dev", Email: "core@example.com"}},
+			"internal/platform/platform.go": {{Name: "Pat", Email: "pat@example.com"}},
+			"internal/stale/stale.go":       {{Name: "Drive By", Email: "drive@example.com"}},
+		},
+		Since:      "1 year ago",
+		Aliases:    map[string][]string{"platform": {"@Core", "Pat"}},
+		CodeOwners: co,
+	}
+	r := Check(in)
+
+	assert.Equal(t, 5, r.Features)
+	assert.Equal(t, 6, r.Files)
+
+	type finding struct{ feature, kind, path string }
+	var got []finding
+	for _, p := range r.Problems {
+		got = append(got, finding{p.Feature, p.Kind, p.Path})
+	}
+	assert.Equal(t, []finding{
+		{"RUST", KindNotCodeOwner, "rust/src/lib.rs"},
+		{"ORPHAN", KindNoOwner, ""},
+		{"ORPHAN", KindUncovered, "vendor/x.go"},
+		{"STALE", KindInactive, ""},
+	}, got)
+	assert.Equal(t, `STALE (owner "core"): authored none of the commits to its 1 files changed since 1 year ago`, r.Problems[3].String())
+}
+
+func TestCheck_NoRule(t *testing.T) {
+	co, err := ParseCodeOwners([]byte("/docs/ @docs\n"))
+	require.NoError(t, err)
+	co.Path = ".github/CODEOWNERS"
+
+	r := Check(Input{
+		Features:   []Feature{{ID: "CORE", Owner: "docs", Files: []string{"docs/a.md", "main.go"}}},
+		CodeOwners: co,
+	})
+	require.Len(t, r.Problems, 1)
+	assert.Equal(t, KindUncovered, r.Problems[0].Kind)
+	assert.Equal(t, "main.go matches no rule in .github/CODEOWNERS", r.Problems[0].Message)
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package policy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/conventional"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/gomod"
+	"github.com/bartekus/cortex/internal/mapping"
+	"github.com/bartekus/cortex/internal/reports/prsummary"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Commit is the HEAD commit rules see.
+type Commit struct {
+	Hash    string
+	Message string
+	Branch  string
+}
+
+// Sources are the inputs rules are evaluated against. Any of them may be
+// missing; the corresponding variable is then empty or null.
+type Sources struct {
+	RepoRoot string
+	Registry *gov.Registry
+	// Results are the skill results of the last run.
+	Results  []runner.SkillResult
+	Coverage prsummary.Coverage
+	Commit   *Commit
+	// Mapping is the feature mapping report (spec, implementation and
+	// test files of each feature).
+	Mapping *mapping.Report
+}
+
+// Collect gathers the sources of the repository at repoRoot: the feature
+// registry, the results and coverage profile of the last run in stateDir
+// (none when it is empty), the feature mapping and the HEAD commit. Missing
+// inputs are left empty.
+func Collect(ctx context.Context, repoRoot, stateDir string) (Sources, error) {
+	src := Sources{RepoRoot: repoRoot}
+
+	featuresPath := filepath.Join(repoRoot, "spec", "features.yaml")
+	if _, err := os.Stat(featuresPath); err == nil {
+		reg, err := gov.LoadRegistry(featuresPath)
+		if err != nil {
+			return src, err
+		}
+		src.Registry = reg
+
+		report, err := mapping.Analyze(mapping.Options{RootDir: repoRoot})
+		if err != nil {
+			return src, fmt.Errorf("mapping features: %w", err)
+		}
+		src.Mapping = &report
+	}
+
+	if stateDir != "" {
+		store := runner.NewStateStore(stateDir)
+		last, err := store.ReadLastRun()
+		if err != nil {
+			return src, err
+		}
+		if last != nil {
+			for _, id := range last.Skills {
+				res, err := store.ReadSkill(id)
+				if err != nil {
+					return src, err
+				}
+				if res != nil {
+					src.Results = append(src.Results, *res)
+				}
+			}
+		}
+
+		cov, err := readCoverage(filepath.Join(stateDir, "coverage.out"), repoRoot)
+		if err != nil {
+			return src, err
+		}
+		src.Coverage = cov
+	}
+
+	// Outside a git work tree, or before the first commit, commit is null.
+	if head, err := git.HeadCommit(ctx, repoRoot); err == nil {
+		branch, _ := git.CurrentBranch(ctx, repoRoot)
+		src.Commit = &Commit{Hash: head.Hash, Message: head.Message, Branch: branch}
+	}
+	return src, nil
+}
+
+// readCoverage parses the profile at path, with file names relative to the
+// module at repoRoot; nil when there is no profile.
+func readCoverage(path, repoRoot string) (prsummary.Coverage, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: fixed file under the state directory
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var module string
+	if data, err := os.ReadFile(filepath.Join(repoRoot, "go.mod")); err == nil { //nolint:gosec // G304: fixed file under the repo root
+		module = gomod.Parse(data).Module
+	}
+	cov, err := prsummary.ParseCoverage(f, module)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cov, nil
+}
+
+// Data returns the variables rules are evaluated against:
+//
+//	features      list of features.yaml entries (id, title, governance, ...)
+//	skills        list of {skill, status, exit_code} of the last run
+//	findings      list of {skill, path, line, message} of the last run
+//	coverage      {total, files: {path: percent}}, or null without a profile
+//	commit        {hash, subject, message, type, scope, breaking, branch}, or null
+//	traceability  the feature mapping, {features: [{id, spec_path, impl_files,
+//	              test_files, status}], violations: [{code, feature, path,
+//	              detail}]}, or null without features.yaml
+//
+// Map keys are sorted when the data is encoded as JSON, so the same sources
+// always encode to the same document.
+func Data(src Sources) map[string]any {
+	data := map[string]any{
+		"features":     []any{},
+		"skills":       []any{},
+		"findings":     []any{},
+		"coverage":     nil,
+		"commit":       nil,
+		"traceability": nil,
+	}
+	if src.Mapping != nil {
+		data["traceability"] = normalize(src.Mapping)
+	}
+	if src.Registry != nil {
+		data["features"] = normalize(src.Registry.Features)
+	}
+
+	skills := []map[string]any{}
+	for _, r := range src.Results {
+		skills = append(skills, map[string]any{"skill": r.Skill, "status": string(r.Status), "exit_code": r.ExitCode})
+	}
+	data["skills"] = normalize(skills)
+
+	fs := []map[string]any{}
+	for _, f := range findings.FromResults(src.RepoRoot, src.Results) {
+		fs = append(fs, map[string]any{"skill": f.Skill, "path": f.Path, "line": f.Line, "message": f.Message})
+	}
+	data["findings"] = normalize(fs)
+
+	if len(src.Coverage) > 0 {
+		total, _ := src.Coverage.Percent(nil)
+		files := map[string]any{}
+		for p := range src.Coverage {
+			files[p], _ = src.Coverage.Percent([]string{p})
+		}
+		data["coverage"] = map[string]any{"total": total, "files": files}
+	}
+
+	if c := src.Commit; c != nil {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		commit := map[string]any{
+			"hash":     c.Hash,
+			"subject":  subject,
+			"message":  c.Message,
+			"type":     "",
+			"scope":    "",
+			"breaking": strings.Contains(c.Message, "\nBREAKING CHANGE:") || strings.Contains(c.Message, "\nBREAKING-CHANGE:"),
+			"branch":   c.Branch,
+		}
+		if h, err := conventional.ParseHeader(subject); err == nil {
+			commit["type"], commit["scope"] = h.Type, h.Scope
+			commit["breaking"] = h.Breaking || commit["breaking"].(bool)
+		}
+		data["commit"] = commit
+	}
+	return data
+}
+
+// normalize converts v to the values of decoded JSON, the only ones
+// expressions operate on.
+func normalize(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil
+	}
+	return out
+}
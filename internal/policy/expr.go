@@ -0,0 +1,804 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package policy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expr is a compiled policy expression. Values are those of decoded JSON:
+// nil, bool, float64, string, []any and map[string]any.
+//
+// The language has literals (numbers, "strings" or 'strings', true, false,
+// null, [lists]), variables, field access (a.b, null-safe), indexing
+// (a[0], m["k"]), the operators ! - * / % + < <= > >= in == != && || by
+// increasing looseness, the functions in funcs, and the list macros
+// l.all(x, pred), l.exists(x, pred), l.filter(x, pred) and l.map(x, expr).
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses src.
+func Compile(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tkEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of e.
+func (e *Expr) String() string { return e.src }
+
+// Eval evaluates e with vars in scope.
+func (e *Expr) Eval(vars map[string]any) (any, error) {
+	return e.root.eval(&env{vars: vars})
+}
+
+// env is a chain of variable scopes; macros push one per element.
+type env struct {
+	vars  map[string]any
+	outer *env
+}
+
+func (e *env) lookup(name string) (any, bool) {
+	for ; e != nil; e = e.outer {
+		if v, ok := e.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Lexer.
+
+type tokenKind int
+
+const (
+	tkEOF tokenKind = iota
+	tkNum
+	tkStr
+	tkIdent
+	tkOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ",", "."}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at offset %d", src[i:j], i)
+			}
+			toks = append(toks, token{kind: tkNum, text: src[i:j], num: n, pos: i})
+			i = j
+		case c == '"' || c == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(src[j])
+					}
+					continue
+				}
+				sb.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			toks = append(toks, token{kind: tkStr, text: sb.String(), pos: i})
+			i = j + 1
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9') {
+				j++
+			}
+			toks = append(toks, token{kind: tkIdent, text: src[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			toks = append(toks, token{kind: tkOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tkEOF, pos: len(src)}), nil
+}
+
+// Parser.
+
+// precedence of the binary operators; "in" is an identifier token.
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4, "in": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// macros are the list methods binding a variable, by name.
+var macros = map[string]bool{"all": true, "exists": true, "filter": true, "map": true}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tkEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(op string) error {
+	if t := p.next(); t.kind != tkOp || t.text != op {
+		return fmt.Errorf("expected %q at offset %d", op, t.pos)
+	}
+	return nil
+}
+
+// binaryOp returns the operator at the current token, if any.
+func (p *parser) binaryOp() (string, int) {
+	t := p.peek()
+	if t.kind == tkOp || t.kind == tkIdent && t.text == "in" {
+		if prec, ok := precedence[t.text]; ok {
+			return t.text, prec
+		}
+	}
+	return "", 0
+}
+
+func (p *parser) expr(minPrec int) (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, prec := p.binaryOp()
+		if op == "" || prec <= minPrec {
+			return left, nil
+		}
+		p.next()
+		right, err := p.expr(prec)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, l: left, r: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if t := p.peek(); t.kind == tkOp && (t.text == "!" || t.text == "-") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: t.text, x: x}, nil
+	}
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	return p.postfix(x)
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tkNum:
+		return &litNode{v: t.num}, nil
+	case tkStr:
+		return &litNode{v: t.text}, nil
+	case tkIdent:
+		switch t.text {
+		case "true":
+			return &litNode{v: true}, nil
+		case "false":
+			return &litNode{v: false}, nil
+		case "null":
+			return &litNode{v: nil}, nil
+		}
+		if n := p.peek(); n.kind == tkOp && n.text == "(" {
+			fn, ok := funcs[t.text]
+			if !ok {
+				return nil, fmt.Errorf("unknown function %q at offset %d", t.text, t.pos)
+			}
+			p.next()
+			args, err := p.args()
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != fn.arity {
+				return nil, fmt.Errorf("%s takes %d argument(s), got %d", t.text, fn.arity, len(args))
+			}
+			return &callNode{name: t.text, fn: fn.fn, args: args}, nil
+		}
+		return &identNode{name: t.text}, nil
+	case tkOp:
+		switch t.text {
+		case "(":
+			x, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			elems, err := p.list("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{elems: elems}, nil
+		}
+	case tkEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+}
+
+func (p *parser) postfix(x node) (node, error) {
+	for {
+		t := p.peek()
+		if t.kind != tkOp {
+			return x, nil
+		}
+		switch t.text {
+		case ".":
+			p.next()
+			name := p.next()
+			if name.kind != tkIdent {
+				return nil, fmt.Errorf("expected a field name at offset %d", name.pos)
+			}
+			if n := p.peek(); n.kind == tkOp && n.text == "(" {
+				if !macros[name.text] {
+					return nil, fmt.Errorf("unknown method %q at offset %d", name.text, name.pos)
+				}
+				p.next()
+				args, err := p.args()
+				if err != nil {
+					return nil, err
+				}
+				v, ok := args[0].(*identNode)
+				if len(args) != 2 || !ok {
+					return nil, fmt.Errorf("%s takes a variable name and an expression, as in l.%s(x, x > 0)", name.text, name.text)
+				}
+				x = &macroNode{name: name.text, x: x, v: v.name, body: args[1]}
+				continue
+			}
+			x = &memberNode{x: x, name: name.text}
+		case "[":
+			p.next()
+			i, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexNode{x: x, i: i}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// args parses call arguments after "(".
+func (p *parser) args() ([]node, error) {
+	args, err := p.list(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expected arguments at offset %d", p.peek().pos)
+	}
+	return args, nil
+}
+
+// list parses comma-separated expressions up to the closing token.
+func (p *parser) list(closing string) ([]node, error) {
+	var elems []node
+	if t := p.peek(); t.kind == tkOp && t.text == closing {
+		p.next()
+		return elems, nil
+	}
+	for {
+		x, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, x)
+		t := p.next()
+		if t.kind == tkOp && t.text == closing {
+			return elems, nil
+		}
+		if t.kind != tkOp || t.text != "," {
+			return nil, fmt.Errorf("expected \",\" or %q at offset %d", closing, t.pos)
+		}
+	}
+}
+
+// Evaluation.
+
+type node interface {
+	eval(e *env) (any, error)
+}
+
+type litNode struct{ v any }
+
+func (n *litNode) eval(*env) (any, error) { return n.v, nil }
+
+type identNode struct{ name string }
+
+func (n *identNode) eval(e *env) (any, error) {
+	v, ok := e.lookup(n.name)
+	if !ok {
+		return nil, fmt.Errorf("unknown identifier %q", n.name)
+	}
+	return v, nil
+}
+
+type listNode struct{ elems []node }
+
+func (n *listNode) eval(e *env) (any, error) {
+	out := make([]any, 0, len(n.elems))
+	for _, x := range n.elems {
+		v, err := x.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+type memberNode struct {
+	x    node
+	name string
+}
+
+func (n *memberNode) eval(e *env) (any, error) {
+	x, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch m := x.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return m[n.name], nil
+	}
+	return nil, fmt.Errorf("cannot access .%s of %s", n.name, typeName(x))
+}
+
+type indexNode struct{ x, i node }
+
+func (n *indexNode) eval(e *env) (any, error) {
+	x, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	i, err := n.i.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	switch c := x.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		f, ok := i.(float64)
+		if !ok || f != float64(int(f)) {
+			return nil, fmt.Errorf("list index must be an integer, got %s", typeName(i))
+		}
+		if int(f) < 0 || int(f) >= len(c) {
+			return nil, fmt.Errorf("list index %d out of range [0, %d)", int(f), len(c))
+		}
+		return c[int(f)], nil
+	case map[string]any:
+		k, ok := i.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be a string, got %s", typeName(i))
+		}
+		return c[k], nil
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(x))
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n *unaryNode) eval(e *env) (any, error) {
+	x, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("! needs a bool, got %s", typeName(x))
+		}
+		return !b, nil
+	}
+	f, ok := x.(float64)
+	if !ok {
+		return nil, fmt.Errorf("- needs a number, got %s", typeName(x))
+	}
+	return -f, nil
+}
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+func (n *binaryNode) eval(e *env) (any, error) {
+	l, err := n.l.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs bools, got %s", n.op, typeName(l))
+		}
+		// Short-circuit: the right side is not evaluated.
+		if lb == (n.op == "||") {
+			return lb, nil
+		}
+		r, err := n.r.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs bools, got %s", n.op, typeName(r))
+		}
+		return rb, nil
+	}
+	r, err := n.r.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(l, r), nil
+	case "!=":
+		return !reflect.DeepEqual(l, r), nil
+	case "in":
+		return contains(r, l)
+	case "+":
+		switch lv := l.(type) {
+		case string:
+			if rv, ok := r.(string); ok {
+				return lv + rv, nil
+			}
+		case []any:
+			if rv, ok := r.([]any); ok {
+				return append(append([]any{}, lv...), rv...), nil
+			}
+		}
+	case "<", "<=", ">", ">=":
+		c, ok := compare(l, r)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %s %s %s", typeName(l), n.op, typeName(r))
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	}
+
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", n.op, typeName(l), typeName(r))
+	}
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	default: // %
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return float64(int64(lf) % int64(rf)), nil
+	}
+}
+
+type callNode struct {
+	name string
+	fn   func(args []any) (any, error)
+	args []node
+}
+
+func (n *callNode) eval(e *env) (any, error) {
+	args := make([]any, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+type macroNode struct {
+	name string
+	x    node
+	v    string
+	body node
+}
+
+func (n *macroNode) eval(e *env) (any, error) {
+	x, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	var list []any
+	switch l := x.(type) {
+	case nil:
+	case []any:
+		list = l
+	default:
+		return nil, fmt.Errorf("%s needs a list, got %s", n.name, typeName(x))
+	}
+
+	out := []any{}
+	for _, item := range list {
+		v, err := n.body.eval(&env{vars: map[string]any{n.v: item}, outer: e})
+		if err != nil {
+			return nil, err
+		}
+		if n.name == "map" {
+			out = append(out, v)
+			continue
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs a bool predicate, got %s", n.name, typeName(v))
+		}
+		switch {
+		case n.name == "all" && !b:
+			return false, nil
+		case n.name == "exists" && b:
+			return true, nil
+		case n.name == "filter" && b:
+			out = append(out, item)
+		}
+	}
+	switch n.name {
+	case "all":
+		return true, nil
+	case "exists":
+		return false, nil
+	}
+	return out, nil
+}
+
+// Functions.
+
+type function struct {
+	arity int
+	fn    func(args []any) (any, error)
+}
+
+var funcs = map[string]function{
+	"len": {1, func(a []any) (any, error) {
+		switch v := a[0].(type) {
+		case nil:
+			return 0.0, nil
+		case string:
+			return float64(len([]rune(v))), nil
+		case []any:
+			return float64(len(v)), nil
+		case map[string]any:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("needs a string, list or map, got %s", typeName(a[0]))
+	}},
+	"contains":   {2, func(a []any) (any, error) { return contains(a[0], a[1]) }},
+	"startsWith": {2, stringFunc(func(s, t string) any { return strings.HasPrefix(s, t) })},
+	"endsWith":   {2, stringFunc(func(s, t string) any { return strings.HasSuffix(s, t) })},
+	"matches": {2, func(a []any) (any, error) {
+		s, ok1 := a[0].(string)
+		pattern, ok2 := a[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("needs strings, got %s and %s", typeName(a[0]), typeName(a[1]))
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	}},
+	"lower": {1, func(a []any) (any, error) {
+		s, ok := a[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("needs a string, got %s", typeName(a[0]))
+		}
+		return strings.ToLower(s), nil
+	}},
+	"upper": {1, func(a []any) (any, error) {
+		s, ok := a[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("needs a string, got %s", typeName(a[0]))
+		}
+		return strings.ToUpper(s), nil
+	}},
+	"string": {1, func(a []any) (any, error) { return Format(a[0]), nil }},
+}
+
+func stringFunc(f func(s, t string) any) func(a []any) (any, error) {
+	return func(a []any) (any, error) {
+		s, ok1 := a[0].(string)
+		t, ok2 := a[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("needs strings, got %s and %s", typeName(a[0]), typeName(a[1]))
+		}
+		return f(s, t), nil
+	}
+}
+
+// contains reports whether x is an element of a list, a substring of a
+// string or a key of a map.
+func contains(container, x any) (any, error) {
+	switch c := container.(type) {
+	case nil:
+		return false, nil
+	case []any:
+		for _, e := range c {
+			if reflect.DeepEqual(e, x) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string:
+		s, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot look for %s in a string", typeName(x))
+		}
+		return strings.Contains(c, s), nil
+	case map[string]any:
+		k, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be a string, got %s", typeName(x))
+		}
+		_, found := c[k]
+		return found, nil
+	}
+	return nil, fmt.Errorf("cannot look into %s", typeName(container))
+}
+
+// compare orders two numbers or two strings.
+func compare(l, r any) (int, bool) {
+	switch lv := l.(type) {
+	case float64:
+		if rv, ok := r.(float64); ok {
+			switch {
+			case lv < rv:
+				return -1, true
+			case lv > rv:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if rv, ok := r.(string); ok {
+			return strings.Compare(lv, rv), true
+		}
+	}
+	return 0, false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// Format renders a value for a message: strings as they are, whole numbers
+// without a fraction, and lists and maps as JSON.
+func Format(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package policy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]any{
+		"n":     3.0,
+		"s":     "feat/login",
+		"list":  []any{1.0, 2.0, 3.0},
+		"tags":  []any{"a", "b"},
+		"m":     map[string]any{"k": "v", "nested": map[string]any{"x": 1.0}},
+		"empty": nil,
+	}
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{`1 + 2 * 3`, 7.0},
+		{`(1 + 2) * 3`, 9.0},
+		{`-n + 10 % 4`, -1.0},
+		{`n >= 3 && n < 4`, true},
+		{`!(n == 3) || false`, false},
+		{`"a" + 'b'`, "ab"},
+		{`"b" > "a"`, true},
+		{`m.k == "v"`, true},
+		{`m.nested.x`, 1.0},
+		{`m.missing`, nil},
+		{`empty.field`, nil},
+		{`m["k"]`, "v"},
+		{`list[1]`, 2.0},
+		{`2 in list`, true},
+		{`"c" in tags`, false},
+		{`"login" in s`, true},
+		{`"k" in m`, true},
+		{`[1, "x"] == [1, "x"]`, true},
+		{`len(list) + len(s) + len(m) + len(empty)`, 15.0},
+		{`startsWith(s, "feat/") && endsWith(s, "login")`, true},
+		{`matches(s, "^feat/[a-z]+$")`, true},
+		{`upper(lower("MiXed"))`, "MIXED"},
+		{`contains(tags, "b")`, true},
+		{`string(list)`, "[1,2,3]"},
+		{`list.all(x, x > 0)`, true},
+		{`list.exists(x, x > 2)`, true},
+		{`list.filter(x, x != 2)`, []any{1.0, 3.0}},
+		{`list.map(x, x * n)`, []any{3.0, 6.0, 9.0}},
+		{`empty.all(x, false)`, true},
+		{`tags.map(t, list.filter(x, x > 1).map(y, t))`, []any{[]any{"a", "a"}, []any{"b", "b"}}},
+		// The right side is not evaluated once the result is known.
+		{`empty == null || empty.x > 1`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			x, err := Compile(tt.src)
+			require.NoError(t, err)
+			got, err := x.Eval(vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`1 +`, "unexpected end of expression"},
+		{`"open`, "unterminated string"},
+		{`a # b`, "unexpected character"},
+		{`nope(1)`, `unknown function "nope"`},
+		{`len(1, 2)`, "len takes 1 argument(s), got 2"},
+		{`l.sort(x, x)`, `unknown method "sort"`},
+		{`l.all(1, true)`, "all takes a variable name"},
+		{`(1`, `expected ")"`},
+		{`1 2`, `unexpected "2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Compile(tt.src)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	vars := map[string]any{"n": 1.0, "s": "x", "list": []any{1.0}}
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`missing`, `unknown identifier "missing"`},
+		{`n < "a"`, "cannot compare number < string"},
+		{`n && true`, "&& needs bools, got number"},
+		{`s - 1`, "cannot apply - to string and number"},
+		{`n / 0`, "division by zero"},
+		{`list[3]`, "list index 3 out of range"},
+		{`n.x`, "cannot access .x of number"},
+		{`s.all(x, true)`, "all needs a list, got string"},
+		{`list.all(x, x)`, "all needs a bool predicate, got number"},
+		{`matches(s, "(")`, "matches: error parsing regexp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			x, err := Compile(tt.src)
+			require.NoError(t, err)
+			_, err = x.Eval(vars)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
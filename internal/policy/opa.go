@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package policy
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// InputSchemaVersion is the schema_version of the exported input document.
+const InputSchemaVersion = "1.0"
+
+// DefaultQuery is the query `gov opa-eval` evaluates by default.
+const DefaultQuery = "data.cortex.deny"
+
+//go:embed opa/*.rego
+var bundled embed.FS
+
+// Input returns the canonical input document for OPA: the variables of
+// Data with a schema_version, as indented JSON with sorted keys.
+func Input(src Sources) ([]byte, error) {
+	doc := Data(src)
+	doc["schema_version"] = InputSchemaVersion
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// WriteBundled writes the bundled Rego policies into dir.
+func WriteBundled(dir string) error {
+	return fs.WalkDir(bundled, "opa", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := bundled.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, d.Name()), data, 0o600)
+	})
+}
+
+// ParseOPAResult extracts the messages from the JSON output of `opa eval`.
+func ParseOPAResult(out []byte) ([]string, error) {
+	var res struct {
+		Result []struct {
+			Expressions []struct {
+				Value any `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		return nil, fmt.Errorf("parsing opa output: %w", err)
+	}
+	var msgs []string
+	for _, r := range res.Result {
+		for _, e := range r.Expressions {
+			list, ok := e.Value.([]any)
+			if !ok {
+				return nil, fmt.Errorf("query must yield a set or array, got %s", typeName(e.Value))
+			}
+			for _, v := range list {
+				msgs = append(msgs, Format(v))
+			}
+		}
+	}
+	return msgs, nil
+}
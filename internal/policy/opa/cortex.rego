@@ -0,0 +1,32 @@
+# SPDX-License-Identifier: AGPL-3.0-or-later
+#
+# Default policies of `cortex gov opa-eval`, evaluated against the document
+# of `cortex gov export-input`. Each message of deny is a violation.
+package cortex
+
+import rego.v1
+
+# A feature marked done lists its tests.
+deny contains msg if {
+	some f in input.features
+	f.implementation == "done"
+	untested(f)
+	msg := sprintf("%s: feature %s is done but lists no tests", [f.spec, f.id])
+}
+
+untested(f) if f.tests == null
+
+untested(f) if count(f.tests) == 0
+
+# The feature mapping holds.
+deny contains msg if {
+	some v in input.traceability.violations
+	msg := sprintf("%s: [%s] %s", [v.path, v.code, v.detail])
+}
+
+# No skill of the last run failed.
+deny contains msg if {
+	some s in input.skills
+	s.status in {"fail", "timeout"}
+	msg := sprintf("skill %s failed with exit code %d", [s.skill, s.exit_code])
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package policy
+
+// Feature: CLI_COMMAND_GOV
+// Spec: spec/cli/gov.md
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInput(t *testing.T) {
+	src := Sources{}
+	a, err := Input(src)
+	require.NoError(t, err)
+	b, err := Input(src)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(a, &doc))
+	assert.Equal(t, InputSchemaVersion, doc["schema_version"])
+	for _, key := range []string{"features", "skills", "findings", "coverage", "commit", "traceability"} {
+		assert.Contains(t, doc, key)
+	}
+}
+
+func TestWriteBundled(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteBundled(dir))
+	data, err := os.ReadFile(filepath.Join(dir, "cortex.rego"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "package cortex")
+}
+
+func TestParseOPAResult(t *testing.T) {
+	msgs, err := ParseOPAResult([]byte(`{"result":[{"expressions":[{"value":["b","a"],"text":"data.cortex.deny"}]}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, msgs)
+
+	msgs, err = ParseOPAResult([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	_, err = ParseOPAResult([]byte(`{"result":[{"expressions":[{"value":true}]}]}`))
+	assert.EqualError(t, err, "query must yield a set or array, got bool")
+}
@@ -0,0 +1,383 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package policy evaluates user-defined rules, written in a small expression
+// language, against what Cortex knows about a repository: its features, the
+// findings and coverage of the last run, and the HEAD commit.
+//
+// Rules are read from YAML files under .cortex/policies:
+//
+//	rules:
+//	  - id: done-features-have-tests
+//	    for_each: features
+//	    when: implementation == "done"
+//	    assert: len(tests) > 0
+//	    path: spec
+//	    message: "${id} is done but lists no tests"
+//
+// A rule with for_each is checked once per element of the list, with the
+// element bound to it and, when it is a map, its fields in scope.
+package policy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is the directory policy files are read from, relative to the repo root.
+const Dir = ".cortex/policies"
+
+// Severities of a rule. A warning does not fail the check on its own.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rule is a rule as written in a policy file. ForEach, When, Assert and
+// Path are expressions; Message is text with ${expr} placeholders.
+type Rule struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+	Severity    string `yaml:"severity"`
+	ForEach     string `yaml:"for_each"`
+	When        string `yaml:"when"`
+	Assert      string `yaml:"assert"`
+	Path        string `yaml:"path"`
+	Message     string `yaml:"message"`
+}
+
+type file struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Policy is a set of compiled rules.
+type Policy struct {
+	// Files are the policy files the rules were read from, repo-relative.
+	Files []string
+	rules []*compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	file    string
+	forEach *Expr
+	when    *Expr
+	assert  *Expr
+	path    *Expr
+	message []segment
+}
+
+// segment is literal text, or an expression when expr is set.
+type segment struct {
+	text string
+	expr *Expr
+}
+
+// Violation is a rule that did not hold.
+type Violation struct {
+	Rule     string
+	Severity string
+	Path     string
+	Message  string
+}
+
+// Warning reports whether the violation is only a warning.
+func (v Violation) Warning() bool { return v.Severity == SeverityWarning }
+
+func (v Violation) String() string {
+	s := fmt.Sprintf("[%s] %s", v.Rule, v.Message)
+	if v.Warning() {
+		s = "WARNING: " + s
+	}
+	if v.Path != "" {
+		s = v.Path + ": " + s
+	}
+	return s
+}
+
+// Load reads and compiles the policy files (*.yaml and *.yml) under
+// repoRoot/Dir in name order. It returns nil when there are none.
+func Load(repoRoot string) (*Policy, error) {
+	entries, err := os.ReadDir(filepath.Join(repoRoot, filepath.FromSlash(Dir)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", Dir, err)
+	}
+	var names []string
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	p := &Policy{}
+	for _, name := range names {
+		rel := Dir + "/" + name
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(rel))) //nolint:gosec // G304: file under the policy directory
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		rules, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		for _, r := range rules {
+			if err := p.add(r, rel); err != nil {
+				return nil, fmt.Errorf("%s: %w", rel, err)
+			}
+		}
+		p.Files = append(p.Files, rel)
+	}
+	return p, nil
+}
+
+// Parse decodes the rules of a policy file. Unknown keys are an error, so a
+// misspelled key cannot silently disable a check.
+func Parse(data []byte) ([]Rule, error) {
+	var f file
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	return f.Rules, nil
+}
+
+// New compiles rules into a policy.
+func New(rules []Rule) (*Policy, error) {
+	p := &Policy{}
+	for _, r := range rules {
+		if err := p.add(r, ""); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// add compiles r, read from file, and appends it to p.
+func (p *Policy) add(r Rule, file string) error {
+	for _, prev := range p.rules {
+		if prev.ID == r.ID && r.ID != "" {
+			if prev.file != "" && prev.file != file {
+				return fmt.Errorf("rule %q is already defined in %s", r.ID, prev.file)
+			}
+			return fmt.Errorf("rule %q is defined twice", r.ID)
+		}
+	}
+	cr, err := compile(r)
+	if err != nil {
+		return err
+	}
+	cr.file = file
+	p.rules = append(p.rules, cr)
+	return nil
+}
+
+func compile(r Rule) (*compiledRule, error) {
+	if r.ID == "" {
+		return nil, fmt.Errorf("a rule has no id")
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityError
+	case SeverityError, SeverityWarning:
+	default:
+		return nil, fmt.Errorf("rule %s: severity must be %q or %q, got %q", r.ID, SeverityError, SeverityWarning, r.Severity)
+	}
+	if strings.TrimSpace(r.Assert) == "" {
+		return nil, fmt.Errorf("rule %s: assert is required", r.ID)
+	}
+
+	cr := &compiledRule{Rule: r}
+	for _, e := range []struct {
+		key string
+		src string
+		dst **Expr
+	}{
+		{"for_each", r.ForEach, &cr.forEach},
+		{"when", r.When, &cr.when},
+		{"assert", r.Assert, &cr.assert},
+		{"path", r.Path, &cr.path},
+	} {
+		if strings.TrimSpace(e.src) == "" {
+			continue
+		}
+		x, err := Compile(e.src)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %s: %w", r.ID, e.key, err)
+		}
+		*e.dst = x
+	}
+
+	msg := r.Message
+	if msg == "" {
+		msg = "assertion failed: " + strings.ReplaceAll(r.Assert, "${", "$ {")
+	}
+	segments, err := compileMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: message: %w", r.ID, err)
+	}
+	cr.message = segments
+	return cr, nil
+}
+
+// compileMessage splits text into literal text and ${expr} placeholders.
+func compileMessage(text string) ([]segment, error) {
+	var out []segment
+	for {
+		i := strings.Index(text, "${")
+		if i < 0 {
+			if text != "" {
+				out = append(out, segment{text: text})
+			}
+			return out, nil
+		}
+		if i > 0 {
+			out = append(out, segment{text: text[:i]})
+		}
+		end := strings.Index(text[i:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated ${ at offset %d", i)
+		}
+		x, err := Compile(text[i+2 : i+end])
+		if err != nil {
+			return nil, fmt.Errorf("${%s}: %w", text[i+2:i+end], err)
+		}
+		out = append(out, segment{expr: x})
+		text = text[i+end+1:]
+	}
+}
+
+// Rules returns the number of rules in p.
+func (p *Policy) Rules() int { return len(p.rules) }
+
+// Evaluate checks every rule against data and returns the violations in
+// rule order. An error means a rule could not be evaluated, such as a type
+// mismatch or an assert that is not a bool.
+func (p *Policy) Evaluate(data map[string]any) ([]Violation, error) {
+	var out []Violation
+	for _, r := range p.rules {
+		vs, err := r.evaluate(data)
+		if err != nil {
+			if r.file != "" {
+				return nil, fmt.Errorf("%s: rule %s: %w", r.file, r.ID, err)
+			}
+			return nil, fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		out = append(out, vs...)
+	}
+	return out, nil
+}
+
+func (r *compiledRule) evaluate(data map[string]any) ([]Violation, error) {
+	if r.forEach == nil {
+		v, err := r.check(data)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		return []Violation{*v}, nil
+	}
+
+	items, err := r.forEach.Eval(data)
+	if err != nil {
+		return nil, fmt.Errorf("for_each: %w", err)
+	}
+	list, ok := items.([]any)
+	if !ok && items != nil {
+		return nil, fmt.Errorf("for_each: needs a list, got %s", typeName(items))
+	}
+	var out []Violation
+	for i, item := range list {
+		scope := make(map[string]any, len(data)+1)
+		for k, v := range data {
+			scope[k] = v
+		}
+		if m, ok := item.(map[string]any); ok {
+			for k, v := range m {
+				scope[k] = v
+			}
+		}
+		scope["it"] = item
+		v, err := r.check(scope)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		if v != nil {
+			out = append(out, *v)
+		}
+	}
+	return out, nil
+}
+
+// check evaluates the rule in scope; nil when it holds or does not apply.
+func (r *compiledRule) check(scope map[string]any) (*Violation, error) {
+	if r.when != nil {
+		ok, err := evalBool(r.when, scope)
+		if err != nil {
+			return nil, fmt.Errorf("when: %w", err)
+		}
+		if !ok {
+			return nil, nil
+		}
+	}
+	ok, err := evalBool(r.assert, scope)
+	if err != nil {
+		return nil, fmt.Errorf("assert: %w", err)
+	}
+	if ok {
+		return nil, nil
+	}
+
+	v := &Violation{Rule: r.ID, Severity: r.Severity}
+	if r.path != nil {
+		p, err := r.path.Eval(scope)
+		if err != nil {
+			return nil, fmt.Errorf("path: %w", err)
+		}
+		if p != nil {
+			v.Path = Format(p)
+		}
+	}
+	var sb strings.Builder
+	for _, s := range r.message {
+		if s.expr == nil {
+			sb.WriteString(s.text)
+			continue
+		}
+		x, err := s.expr.Eval(scope)
+		if err != nil {
+			return nil, fmt.Errorf("message: ${%s}: %w", s.expr, err)
+		}
+		sb.WriteString(Format(x))
+	}
+	v.Message = sb.String()
+	return v, nil
+}
+
+func evalBool(x *Expr, scope map[string]any) (bool, error) {
+	v, err := x.Eval(scope)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("needs a bool, got %s", typeName(v))
+	}
+	return b, nil
+}
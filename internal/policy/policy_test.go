@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package policy
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/reports/prsummary"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+func testData() map[string]any {
+	return Data(Sources{
+		RepoRoot: "/repo",
+		Registry: &gov.Registry{Features: []gov.Feature{
+			{ID: "AUTH", Implementation: gov.ImplementationState("done"), Spec: "spec/auth.md", Tests: []string{"auth_test.go"}},
+			{ID: "BILLING", Implementation: gov.ImplementationState("done"), Spec: "spec/billing.md"},
+			{ID: "SEARCH", Implementation: gov.ImplementationState("todo"), Spec: "spec/search.md"},
+		}},
+		Results: []runner.SkillResult{
+			{Skill: "lint:golangci", Status: runner.StatusFail, ExitCode: 1, Note: "internal/auth/auth.go:12:3: unused variable"},
+			{Skill: "test:go", Status: runner.StatusPass},
+		},
+		Coverage: prsummary.Coverage{
+			"internal/auth/auth.go":       {Statements: 10, Covered: 9},
+			"internal/billing/billing.go": {Statements: 10, Covered: 3},
+		},
+		Commit: &Commit{Hash: "abc123", Message: "feat(auth)!: drop sessions\n\nFeature: AUTH", Branch: "main"},
+	})
+}
+
+func TestData(t *testing.T) {
+	data := testData()
+
+	features := data["features"].([]any)
+	require.Len(t, features, 3)
+	assert.Equal(t, "AUTH", features[0].(map[string]any)["id"])
+	assert.Equal(t, []any{
+		map[string]any{"skill": "lint:golangci", "path": "internal/auth/auth.go", "line": 12.0, "message": "unused variable"},
+	}, data["findings"])
+	assert.Equal(t, []any{
+		map[string]any{"skill": "lint:golangci", "status": "fail", "exit_code": 1.0},
+		map[string]any{"skill": "test:go", "status": "pass", "exit_code": 0.0},
+	}, data["skills"])
+	assert.Equal(t, map[string]any{
+		"total": 60.0,
+		"files": map[string]any{"internal/auth/auth.go": 90.0, "internal/billing/billing.go": 30.0},
+	}, data["coverage"])
+	assert.Equal(t, map[string]any{
+		"hash": "abc123", "subject": "feat(auth)!: drop sessions", "message": "feat(auth)!: drop sessions\n\nFeature: AUTH",
+		"type": "feat", "scope": "auth", "breaking": true, "branch": "main",
+	}, data["commit"])
+
+	empty := Data(Sources{})
+	assert.Equal(t, []any{}, empty["features"])
+	assert.Nil(t, empty["coverage"])
+	assert.Nil(t, empty["commit"])
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := New([]Rule{
+		{
+			ID:      "done-has-tests",
+			ForEach: "features",
+			When:    `implementation == "done"`,
+			Assert:  "len(tests) > 0",
+			Path:    "spec",
+			Message: "${id} is done but lists no tests",
+		},
+		{
+			ID:       "coverage",
+			Severity: SeverityWarning,
+			Assert:   "coverage == null || coverage.total >= 80",
+			Message:  "coverage ${coverage.total}% is below 80%",
+		},
+		{
+			ID:      "no-lint-findings",
+			ForEach: `findings.filter(f, startsWith(f.skill, "lint:"))`,
+			Assert:  "false",
+			Path:    "path",
+		},
+		{ID: "breaking-needs-trailer", Assert: `!commit.breaking || "Feature:" in commit.message`},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, p.Rules())
+
+	vs, err := p.Evaluate(testData())
+	require.NoError(t, err)
+	var got []string
+	for _, v := range vs {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, []string{
+		"spec/billing.md: [done-has-tests] BILLING is done but lists no tests",
+		"WARNING: [coverage] coverage 60% is below 80%",
+		"internal/auth/auth.go: [no-lint-findings] assertion failed: false",
+	}, got)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{ID: "r", Assert: "1"}, "rule r: assert: needs a bool, got number"},
+		{Rule{ID: "r", ForEach: "commit", Assert: "true"}, "rule r: for_each: needs a list, got map"},
+		{Rule{ID: "r", ForEach: "features", Assert: "nope"}, `rule r: item 0: assert: unknown identifier "nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			p, err := New([]Rule{tt.rule})
+			require.NoError(t, err)
+			_, err = p.Evaluate(testData())
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		rules []Rule
+		want  string
+	}{
+		{[]Rule{{Assert: "true"}}, "a rule has no id"},
+		{[]Rule{{ID: "r"}}, "rule r: assert is required"},
+		{[]Rule{{ID: "r", Assert: "true", Severity: "fatal"}}, `rule r: severity must be "error" or "warning", got "fatal"`},
+		{[]Rule{{ID: "r", Assert: "true", When: "(("}}, "rule r: when: unexpected"},
+		{[]Rule{{ID: "r", Assert: "true", Message: "${x"}}, "rule r: message: unterminated ${"},
+		{[]Rule{{ID: "r", Assert: "true"}, {ID: "r", Assert: "false"}}, `rule "r" is defined twice`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			_, err := New(tt.rules)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	p, err := Load(root)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	dir := filepath.Join(root, ".cortex", "policies")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte("rules:\n  - id: second\n    assert: 'true'\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("rules:\n  - id: first\n    assert: 'false'\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	p, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{".cortex/policies/a.yaml", ".cortex/policies/b.yml"}, p.Files)
+	assert.Equal(t, 2, p.Rules())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.yaml"), []byte("rules:\n  - id: first\n    assert: 'true'\n"), 0o644))
+	_, err = Load(root)
+	assert.EqualError(t, err, `.cortex/policies/c.yaml: rule "first" is already defined in .cortex/policies/a.yaml`)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.yaml"), []byte("rules:\n  - id: third\n    asert: 'true'\n"), 0o644))
+	_, err = Load(root)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field asert not found")
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// GCS is a remote in a Google Cloud Storage bucket, accessed through the
+// JSON API with an OAuth 2.0 access token (`gcloud auth print-access-token`).
+type GCS struct {
+	Bucket string
+	Prefix string
+	// Endpoint is the base URL of the API; default
+	// https://storage.googleapis.com.
+	Endpoint string
+	// Token is the access token; it may be empty for an emulator.
+	Token  string
+	Client *http.Client
+}
+
+func newGCS(bucket, prefix, endpoint string, getenv func(string) string, client *http.Client) (*GCS, error) {
+	g := &GCS{Bucket: bucket, Prefix: prefix, Token: getenv("GOOGLE_OAUTH_ACCESS_TOKEN"), Client: client}
+	switch endpoint = firstNonEmpty(endpoint, getenv("STORAGE_EMULATOR_HOST")); {
+	case endpoint == "":
+		if g.Token == "" {
+			return nil, fmt.Errorf("gcs: GOOGLE_OAUTH_ACCESS_TOKEN must be set")
+		}
+		g.Endpoint = "https://storage.googleapis.com"
+	case !strings.Contains(endpoint, "://"):
+		// STORAGE_EMULATOR_HOST is conventionally a bare host:port.
+		g.Endpoint = "http://" + endpoint
+	default:
+		g.Endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+	return g, nil
+}
+
+// Put implements Backend with a simple media upload.
+func (g *GCS) Put(ctx context.Context, key string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	query := url.Values{"uploadType": {"media"}, "name": {join(g.Prefix, key)}}
+	resp, err := g.do(ctx, http.MethodPost, "/upload/storage/v1/b/"+url.PathEscape(g.Bucket)+"/o?"+query.Encode(), data)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Get implements Backend.
+func (g *GCS) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	resp, err := g.do(ctx, http.MethodGet, g.objectPath(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return io.ReadAll(resp.Body)
+}
+
+// List implements Backend.
+func (g *GCS) List(ctx context.Context, prefix string) ([]string, error) {
+	query := url.Values{"prefix": {join(g.Prefix, prefix)}, "fields": {"items(name),nextPageToken"}}
+	var keys []string
+	for {
+		resp, err := g.do(ctx, http.MethodGet, "/storage/v1/b/"+url.PathEscape(g.Bucket)+"/o?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("gcs: decoding object list: %w", err)
+		}
+		for _, item := range page.Items {
+			keys = append(keys, strings.TrimPrefix(item.Name, join(g.Prefix, "")))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (g *GCS) objectPath(key string) string {
+	return "/storage/v1/b/" + url.PathEscape(g.Bucket) + "/o/" + url.PathEscape(join(g.Prefix, key))
+}
+
+// do sends a request to path (with its query) and returns the response of a
+// successful request.
+func (g *GCS) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, g.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: %w", err)
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("gcs: %s: %w", req.URL.Path, ErrNotFound)
+	}
+	var e struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&e)
+	return nil, fmt.Errorf("gcs: %s %s: %s %s", method, req.URL.Path, resp.Status, e.Error.Message)
+}
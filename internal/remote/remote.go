@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package remote stores Cortex artifacts (snapshot blobs, run history and
+// reports) in a shared bucket, so CI machines and developer laptops can
+// exchange them. Backends implement a small object-store interface; Amazon
+// S3 and S3-compatible services, Google Cloud Storage and a local directory
+// are supported.
+package remote
+
+// Feature: CLI_COMMAND_SYNC
+// Spec: spec/cli/sync.md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// Backend is an object store. Keys are slash-separated paths relative to
+// the remote's prefix.
+type Backend interface {
+	// Put stores data under key, replacing any existing object.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the object under key, or an error wrapping ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys starting with prefix, sorted.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ErrNotFound is returned by Get for a missing object.
+var ErrNotFound = errors.New("object not found")
+
+// Options configures Open. Zero fields take their defaults.
+type Options struct {
+	// Region is the S3 region; default $AWS_REGION, $AWS_DEFAULT_REGION,
+	// then us-east-1.
+	Region string
+	// Endpoint is the base URL of an S3-compatible service or of a GCS
+	// emulator; default $AWS_ENDPOINT_URL_S3 or $AWS_ENDPOINT_URL for S3 and
+	// $STORAGE_EMULATOR_HOST for GCS.
+	Endpoint string
+	// Getenv reads credentials and defaults; nil means os.Getenv.
+	Getenv func(string) string
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// Open returns the backend of a remote URL: s3://bucket/prefix,
+// gs://bucket/prefix or file:///path. Credentials come from the
+// environment: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN for S3, GOOGLE_OAUTH_ACCESS_TOKEN for GCS.
+func Open(rawURL string, opts Options) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote %q: %w", rawURL, err)
+	}
+	getenv := opts.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid remote %q: no bucket", rawURL)
+		}
+		return newS3(u.Host, prefix, opts.Region, opts.Endpoint, getenv, client)
+	case "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid remote %q: no bucket", rawURL)
+		}
+		return newGCS(u.Host, prefix, opts.Endpoint, getenv, client)
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("invalid remote %q: file URLs must be local", rawURL)
+		}
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid remote %q: no path", rawURL)
+		}
+		return Dir{Root: u.Path}, nil
+	}
+	return nil, fmt.Errorf("unsupported remote %q: use s3://, gs:// or file://", rawURL)
+}
+
+// checkKey rejects keys that are not clean relative paths.
+func checkKey(key string) error {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || key == ".." ||
+		strings.HasPrefix(key, "../") || strings.Contains(key, `\`) {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	return nil
+}
+
+// join prefixes key with the remote prefix.
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
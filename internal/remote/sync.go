@@ -0,0 +1,372 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package remote
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+// Key prefixes of the artifacts in a remote.
+const (
+	// BlobsPrefix holds cortex-mcp snapshot blobs as stored in the local
+	// store, at blobs/sha256/<first two hex digits>/<hex>.
+	BlobsPrefix = "blobs/"
+	// RunsPrefix holds run history records at runs/<commit>/<id>.json.
+	RunsPrefix = "runs/"
+	// ReportsPrefix holds the files under .cortex/reports at
+	// reports/<commit>/<path>.
+	ReportsPrefix = "reports/"
+)
+
+// ErrInvalid marks a remote artifact that fails verification.
+var ErrInvalid = errors.New("invalid artifact")
+
+// ErrConflict marks a pull that would overwrite local reports.
+var ErrConflict = errors.New("remote reports differ from local files")
+
+// Local locates the artifacts of a checkout.
+type Local struct {
+	// DataDir is the cortex-mcp store; blobs live under DataDir/blobs.
+	DataDir string
+	// StateDir is the run state directory; records live under
+	// StateDir/history.
+	StateDir string
+	// ReportsDir is .cortex/reports.
+	ReportsDir string
+	// Commit is HEAD. Runs and reports are pulled for it only and reports
+	// are pushed under it; when empty neither is pulled and reports are not
+	// pushed.
+	Commit string
+}
+
+// Summary counts the artifacts a push or pull transferred, or would
+// transfer in a dry run, and those already on both sides.
+type Summary struct {
+	Blobs    int `json:"blobs"`
+	Runs     int `json:"runs"`
+	Reports  int `json:"reports"`
+	UpToDate int `json:"up_to_date"`
+}
+
+// Push uploads the blobs, run records and reports of l that the remote
+// lacks. Blobs and records never change once written, so existing objects
+// are skipped unread; reports are compared and replaced when they differ.
+// Records without a commit are not pushed.
+func Push(ctx context.Context, b Backend, l Local, dryRun bool) (Summary, error) {
+	var sum Summary
+	put := func(key, path string, count *int) error {
+		*count++
+		if dryRun {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return b.Put(ctx, key, data)
+	}
+
+	blobsDir := filepath.Join(l.DataDir, "blobs")
+	blobs, err := localFiles(blobsDir)
+	if err != nil {
+		return sum, err
+	}
+	remote, err := keySet(ctx, b, BlobsPrefix)
+	if err != nil {
+		return sum, err
+	}
+	for _, p := range blobs {
+		if _, ok := blobHash(p); !ok {
+			continue // not a blob, e.g. a write in progress
+		}
+		if remote[BlobsPrefix+p] {
+			sum.UpToDate++
+		} else if err := put(BlobsPrefix+p, filepath.Join(blobsDir, p), &sum.Blobs); err != nil {
+			return sum, err
+		}
+	}
+
+	runs, err := runner.NewStateStore(l.StateDir).ListRuns()
+	if err != nil {
+		return sum, err
+	}
+	if remote, err = keySet(ctx, b, RunsPrefix); err != nil {
+		return sum, err
+	}
+	for _, rec := range runs {
+		if rec.Commit == "" {
+			continue
+		}
+		key := RunsPrefix + rec.Commit + "/" + rec.ID + ".json"
+		if remote[key] {
+			sum.UpToDate++
+		} else if err := put(key, filepath.Join(l.StateDir, "history", rec.ID+".json"), &sum.Runs); err != nil {
+			return sum, err
+		}
+	}
+
+	if l.Commit == "" {
+		return sum, nil
+	}
+	prefix := ReportsPrefix + l.Commit + "/"
+	reports, err := localFiles(l.ReportsDir)
+	if err != nil {
+		return sum, err
+	}
+	if remote, err = keySet(ctx, b, prefix); err != nil {
+		return sum, err
+	}
+	for _, p := range reports {
+		path := filepath.Join(l.ReportsDir, filepath.FromSlash(p))
+		if remote[prefix+p] {
+			same, err := sameContent(ctx, b, prefix+p, path)
+			if err != nil {
+				return sum, err
+			}
+			if same {
+				sum.UpToDate++
+				continue
+			}
+		}
+		if err := put(prefix+p, path, &sum.Reports); err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
+
+// Pull downloads the blobs the local store lacks, and the run records and
+// reports of l.Commit. Blobs are checked against their hash and records
+// against the run-record schema; a failure wraps ErrInvalid. Local reports
+// that differ from the remote ones are only replaced with force; otherwise
+// nothing is written and the error, wrapping ErrConflict, lists them.
+func Pull(ctx context.Context, b Backend, l Local, force, dryRun bool) (Summary, error) {
+	var sum Summary
+
+	// Reports are fetched first so a conflict stops the pull before
+	// anything is written.
+	pending := map[string][]byte{}
+	if l.Commit != "" {
+		prefix := ReportsPrefix + l.Commit + "/"
+		keys, err := b.List(ctx, prefix)
+		if err != nil {
+			return sum, err
+		}
+		var conflicts []string
+		for _, key := range keys {
+			data, err := b.Get(ctx, key)
+			if err != nil {
+				return sum, err
+			}
+			p := strings.TrimPrefix(key, prefix)
+			existing, err := os.ReadFile(filepath.Join(l.ReportsDir, filepath.FromSlash(p)))
+			switch {
+			case err == nil && bytes.Equal(existing, data):
+				sum.UpToDate++
+				continue
+			case err == nil && !force:
+				conflicts = append(conflicts, p)
+			case err != nil && !errors.Is(err, fs.ErrNotExist):
+				return sum, err
+			}
+			pending[p] = data
+		}
+		if len(conflicts) > 0 {
+			return sum, fmt.Errorf("%w (use --force to replace): %s", ErrConflict, strings.Join(conflicts, ", "))
+		}
+	}
+
+	blobsDir := filepath.Join(l.DataDir, "blobs")
+	keys, err := b.List(ctx, BlobsPrefix)
+	if err != nil {
+		return sum, err
+	}
+	for _, key := range keys {
+		p := strings.TrimPrefix(key, BlobsPrefix)
+		hash, ok := blobHash(p)
+		if !ok {
+			continue
+		}
+		path := filepath.Join(blobsDir, filepath.FromSlash(p))
+		if _, err := os.Stat(path); err == nil {
+			sum.UpToDate++
+			continue
+		}
+		sum.Blobs++
+		if dryRun {
+			continue
+		}
+		data, err := b.Get(ctx, key)
+		if err != nil {
+			return sum, err
+		}
+		if !verifyBlob(hash, data) {
+			return sum, fmt.Errorf("%w: blob %s does not match its hash", ErrInvalid, key)
+		}
+		if err := projection.AtomicWrite(path, data); err != nil {
+			return sum, err
+		}
+	}
+
+	if l.Commit != "" {
+		store := runner.NewStateStore(l.StateDir)
+		prefix := RunsPrefix + l.Commit + "/"
+		if keys, err = b.List(ctx, prefix); err != nil {
+			return sum, err
+		}
+		for _, key := range keys {
+			id, ok := strings.CutSuffix(strings.TrimPrefix(key, prefix), ".json")
+			if !ok || strings.Contains(id, "/") {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(l.StateDir, "history", id+".json")); err == nil {
+				sum.UpToDate++
+				continue
+			}
+			sum.Runs++
+			if dryRun {
+				continue
+			}
+			data, err := b.Get(ctx, key)
+			if err != nil {
+				return sum, err
+			}
+			var rec runner.RunRecord
+			if err := schemas.Decode(schemas.RunRecord, data, &rec); err != nil {
+				return sum, fmt.Errorf("%w: %s: %v", ErrInvalid, key, err)
+			}
+			if rec.ID != id || rec.Commit != l.Commit {
+				return sum, fmt.Errorf("%w: %s holds run %s at %q", ErrInvalid, key, rec.ID, rec.Commit)
+			}
+			if err := store.WriteRun(rec); err != nil {
+				return sum, err
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(pending))
+	for p := range pending {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		sum.Reports++
+		if dryRun {
+			continue
+		}
+		if err := projection.AtomicWrite(filepath.Join(l.ReportsDir, filepath.FromSlash(p)), pending[p]); err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
+
+// localFiles returns the slash-separated paths of the regular files under
+// dir, sorted; a missing dir has none.
+func localFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == dir {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return paths, err
+}
+
+func keySet(ctx context.Context, b Backend, prefix string) (map[string]bool, error) {
+	keys, err := b.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set, nil
+}
+
+func sameContent(ctx context.Context, b Backend, key, path string) (bool, error) {
+	remote, err := b.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	local, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(remote, local), nil
+}
+
+// blobHash returns the hex hash of a blob path of the cortex-mcp store,
+// sha256/<first two hex digits>/<hex>.
+func blobHash(p string) (string, bool) {
+	parts := strings.Split(p, "/")
+	if len(parts) != 3 || parts[0] != "sha256" || len(parts[2]) != 64 || parts[1] != parts[2][:2] {
+		return "", false
+	}
+	if _, err := hex.DecodeString(parts[2]); err != nil || strings.ToLower(parts[2]) != parts[2] {
+		return "", false
+	}
+	return parts[2], true
+}
+
+// verifyBlob checks a blob as cortex-mcp stores it against its hash. Blobs
+// are named by the hash of their raw content but may be stored zstd or gzip
+// encoded ($CORTEX_BLOB_COMPRESSION), and blobs of older stores are named
+// by the hash of their stored bytes, so either matches.
+func verifyBlob(hash string, data []byte) bool {
+	matches := func(b []byte) bool {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:]) == hash
+	}
+	if matches(data) {
+		return true
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		d, err := zstd.NewReader(nil)
+		if err != nil {
+			return false
+		}
+		defer d.Close()
+		raw, err := d.DecodeAll(data, nil)
+		return err == nil && matches(raw)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return false
+		}
+		raw, err := io.ReadAll(r)
+		return err == nil && matches(raw)
+	}
+	return false
+}
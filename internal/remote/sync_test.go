@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+func newLocal(t *testing.T, commit string) Local {
+	root := t.TempDir()
+	return Local{
+		DataDir:    filepath.Join(root, "data"),
+		StateDir:   filepath.Join(root, "run"),
+		ReportsDir: filepath.Join(root, "reports"),
+		Commit:     commit,
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// writeBlob stores content as cortex-mcp does and returns its blob path.
+func writeBlob(t *testing.T, l Local, content string, compress bool) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(content))
+	h := hex.EncodeToString(sum[:])
+	p := "sha256/" + h[:2] + "/" + h
+	stored := []byte(content)
+	if compress {
+		enc, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		stored = enc.EncodeAll(stored, nil)
+	}
+	writeFile(t, filepath.Join(l.DataDir, "blobs", p), string(stored))
+	return p
+}
+
+func TestPushPull(t *testing.T) {
+	ctx := context.Background()
+	b := Dir{Root: t.TempDir()}
+
+	ci := newLocal(t, "c1")
+	raw := writeBlob(t, ci, "package main\n", false)
+	compressed := writeBlob(t, ci, "package remote\n", true)
+	writeFile(t, filepath.Join(ci.DataDir, "blobs", "sha256", "ab", ".tmp123"), "partial")
+	store := runner.NewStateStore(ci.StateDir)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ciRun := runner.NewRunRecord("c1", "", []runner.SkillResult{{Skill: "test:go", Status: runner.StatusPass}}, at)
+	require.NoError(t, store.WriteRun(ciRun))
+	require.NoError(t, store.WriteRun(runner.NewRunRecord("", "", nil, at)))
+	writeFile(t, filepath.Join(ci.ReportsDir, "gov", "drift.json"), `{"ok":true}`)
+
+	sum, err := Push(ctx, b, ci, true)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Blobs: 2, Runs: 1, Reports: 1}, sum)
+	keys, err := b.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, keys, "dry run")
+
+	sum, err = Push(ctx, b, ci, false)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Blobs: 2, Runs: 1, Reports: 1}, sum)
+	keys, err = b.List(ctx, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"blobs/" + raw,
+		"blobs/" + compressed,
+		"reports/c1/gov/drift.json",
+		"runs/c1/" + ciRun.ID + ".json",
+	}, keys)
+
+	sum, err = Push(ctx, b, ci, false)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{UpToDate: 4}, sum)
+
+	laptop := newLocal(t, "c1")
+	sum, err = Pull(ctx, b, laptop, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Blobs: 2, Runs: 1, Reports: 1}, sum)
+	for _, p := range []string{raw, compressed} {
+		want, err := os.ReadFile(filepath.Join(ci.DataDir, "blobs", p))
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(laptop.DataDir, "blobs", p))
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	runs, err := runner.NewStateStore(laptop.StateDir).ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, ciRun.ID, runs[0].ID)
+	report, err := os.ReadFile(filepath.Join(laptop.ReportsDir, "gov", "drift.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(report))
+
+	sum, err = Pull(ctx, b, laptop, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{UpToDate: 4}, sum)
+
+	// Runs and reports are pulled for HEAD only.
+	other := newLocal(t, "c2")
+	sum, err = Pull(ctx, b, other, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Blobs: 2}, sum)
+
+	writeFile(t, filepath.Join(laptop.ReportsDir, "gov", "drift.json"), `{"ok":false}`)
+	_, err = Pull(ctx, b, laptop, false, false)
+	require.ErrorIs(t, err, ErrConflict)
+	assert.ErrorContains(t, err, "gov/drift.json")
+	sum, err = Pull(ctx, b, laptop, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sum.Reports)
+}
+
+func TestPull_RejectsTampering(t *testing.T) {
+	ctx := context.Background()
+	sum := sha256.Sum256([]byte("x"))
+	h := hex.EncodeToString(sum[:])
+
+	b := Dir{Root: t.TempDir()}
+	require.NoError(t, b.Put(ctx, "blobs/sha256/"+h[:2]+"/"+h, []byte("y")))
+	_, err := Pull(ctx, b, newLocal(t, ""), false, false)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	b = Dir{Root: t.TempDir()}
+	require.NoError(t, b.Put(ctx, "runs/c1/abc.json", []byte(`{"id":"abc"}`)))
+	_, err = Pull(ctx, b, newLocal(t, "c1"), false, false)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestBlobHash(t *testing.T) {
+	h := "ab0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcd"
+	got, ok := blobHash("sha256/ab/" + h)
+	assert.True(t, ok)
+	assert.Equal(t, h, got)
+	for _, p := range []string{"sha256/cd/" + h, "md5/ab/" + h, "sha256/ab/.tmp123", "sha256/ab/" + h + "/x"} {
+		_, ok := blobHash(p)
+		assert.False(t, ok, p)
+	}
+}
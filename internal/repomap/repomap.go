@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package repomap renders .cortex/repo-map.md: a compact, deterministic
+// per-directory summary of the repository (file counts, languages, entry
+// points and the features implemented there) for inclusion in agent prompts.
+package repomap
+
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/mapping"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// FileName is the map's name under .cortex/.
+const FileName = "repo-map.md"
+
+// specLinkPrefix makes repo-relative spec paths resolve from .cortex/.
+const specLinkPrefix = "../"
+
+// entryPoints are file names that mark where to start reading a directory.
+var entryPoints = map[string]bool{
+	"Cargo.toml":   true,
+	"Dockerfile":   true,
+	"Makefile":     true,
+	"README.md":    true,
+	"doc.go":       true,
+	"go.mod":       true,
+	"index.js":     true,
+	"index.ts":     true,
+	"lib.rs":       true,
+	"main.go":      true,
+	"main.rs":      true,
+	"package.json": true,
+}
+
+// Dir summarizes the files directly inside one directory.
+type Dir struct {
+	Path        string
+	Files       int
+	Bytes       int64
+	Languages   map[string]int
+	EntryPoints []string // sorted
+	Features    []string // sorted feature IDs from Feature: headers
+}
+
+// Map is the input to Render.
+type Map struct {
+	Dirs     []Dir                           // sorted by path
+	Registry map[string]features.FeatureNode // by ID; may be empty
+}
+
+// Build groups files by directory and reads Feature headers from files that
+// support them. The feature registry (spec/features.yaml) is optional.
+func Build(repoRoot string, files []xray.FileNode) (*Map, error) {
+	byDir := map[string]*Dir{}
+	featureSets := map[string]map[string]bool{}
+	for _, f := range files {
+		dir := path.Dir(f.Path)
+		d := byDir[dir]
+		if d == nil {
+			d = &Dir{Path: dir, Languages: map[string]int{}}
+			byDir[dir] = d
+			featureSets[dir] = map[string]bool{}
+		}
+		d.Files++
+		d.Bytes += f.Size
+		d.Languages[f.Lang]++
+		if entryPoints[path.Base(f.Path)] {
+			d.EntryPoints = append(d.EntryPoints, path.Base(f.Path))
+		}
+		if mapping.SupportsHeaders(f.Path) {
+			id, _, err := mapping.ParseHeaders(filepath.Join(repoRoot, filepath.FromSlash(f.Path)))
+			if err != nil {
+				return nil, fmt.Errorf("reading headers of %s: %w", f.Path, err)
+			}
+			if id != "" {
+				featureSets[dir][id] = true
+			}
+		}
+	}
+
+	m := &Map{Registry: map[string]features.FeatureNode{}}
+	for dir, d := range byDir {
+		sort.Strings(d.EntryPoints)
+		for id := range featureSets[dir] {
+			d.Features = append(d.Features, id)
+		}
+		sort.Strings(d.Features)
+		m.Dirs = append(m.Dirs, *d)
+	}
+	sort.Slice(m.Dirs, func(i, j int) bool { return m.Dirs[i].Path < m.Dirs[j].Path })
+
+	data, err := os.ReadFile(filepath.Join(repoRoot, "spec", "features.yaml"))
+	if err == nil {
+		var registry features.YAML
+		if err := yaml.Unmarshal(data, &registry); err != nil {
+			return nil, fmt.Errorf("failed to parse features.yaml: %w", err)
+		}
+		for _, f := range registry.Features {
+			m.Registry[f.ID] = f
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read features.yaml: %w", err)
+	}
+	return m, nil
+}
+
+// Render returns the Markdown map.
+func Render(m *Map) string {
+	var b strings.Builder
+	b.WriteString(projection.RenderHeader(1, "Repository Map"))
+
+	files, bytes := 0, int64(0)
+	for _, d := range m.Dirs {
+		files += d.Files
+		bytes += d.Bytes
+	}
+	fmt.Fprintf(&b, "%d files in %d directories, %d bytes. Counts cover files directly in each directory.\n\n", files, len(m.Dirs), bytes)
+
+	b.WriteString(projection.RenderHeader(2, "Directories"))
+	rows := make([][]string, 0, len(m.Dirs))
+	for _, d := range m.Dirs {
+		rows = append(rows, []string{
+			"`" + d.Path + "`",
+			fmt.Sprintf("%d", d.Files),
+			languages(d.Languages),
+			codeList(d.EntryPoints),
+			strings.Join(featureLinks(m, d.Features), ", "),
+		})
+	}
+	b.WriteString(projection.RenderTable([]string{"Directory", "Files", "Languages", "Entry Points", "Features"}, rows))
+
+	dirsByFeature := map[string][]string{}
+	for _, d := range m.Dirs {
+		for _, id := range d.Features {
+			dirsByFeature[id] = append(dirsByFeature[id], d.Path)
+		}
+	}
+	if len(dirsByFeature) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n" + projection.RenderHeader(2, "Features"))
+	ids := make([]string, 0, len(dirsByFeature))
+	for id := range dirsByFeature {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rows = make([][]string, 0, len(ids))
+	for _, id := range ids {
+		title, spec := "(not in registry)", "-"
+		if f, ok := m.Registry[id]; ok {
+			title = strings.ReplaceAll(f.Title, "|", "\\|")
+			spec = fmt.Sprintf("[%s](%s%s)", f.Spec, specLinkPrefix, f.Spec)
+		}
+		rows = append(rows, []string{"`" + id + "`", title, spec, codeList(dirsByFeature[id])})
+	}
+	b.WriteString(projection.RenderTable([]string{"Feature", "Title", "Spec", "Directories"}, rows))
+	return b.String()
+}
+
+// languages renders language counts, most common first.
+func languages(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func featureLinks(m *Map, ids []string) []string {
+	links := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if f, ok := m.Registry[id]; ok && f.Spec != "" {
+			links = append(links, fmt.Sprintf("[%s](%s%s)", id, specLinkPrefix, f.Spec))
+		} else {
+			links = append(links, id)
+		}
+	}
+	if len(links) == 0 {
+		return []string{"-"}
+	}
+	return links
+}
+
+func codeList(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = "`" + s + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
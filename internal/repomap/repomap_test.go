@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package repomap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func TestBuildAndRender(t *testing.T) {
+	root := t.TempDir()
+	contents := map[string]string{
+		"spec/features.yaml": "features:\n  - id: CLI_X\n    title: \"X | Y\"\n    spec: spec/x.md\n",
+		"README.md":          "# Repo\n",
+		"cmd/x/main.go":      "// Feature: CLI_X\n// Spec: spec/x.md\npackage main\n",
+		"cmd/x/util.go":      "// Feature: UNKNOWN\npackage main\n",
+		"docs/guide.md":      "# Feature: not a header\n",
+	}
+	var files []xray.FileNode
+	for rel, content := range contents {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		files = append(files, xray.FileNode{Path: rel, Size: int64(len(content)), Lang: xray.DetectLanguage(rel)})
+	}
+
+	m, err := Build(root, files)
+	require.NoError(t, err)
+	require.Len(t, m.Dirs, 4)
+	assert.Equal(t, []string{".", "cmd/x", "docs", "spec"}, []string{m.Dirs[0].Path, m.Dirs[1].Path, m.Dirs[2].Path, m.Dirs[3].Path})
+	assert.Equal(t, []string{"main.go"}, m.Dirs[1].EntryPoints)
+	assert.Equal(t, []string{"CLI_X", "UNKNOWN"}, m.Dirs[1].Features)
+	assert.Empty(t, m.Dirs[2].Features)
+
+	out := Render(m)
+	assert.Contains(t, out, "| `.` | 1 | Markdown (1) | `README.md` | - |\n")
+	assert.Contains(t, out, "| `cmd/x` | 2 | Go (2) | `main.go` | [CLI_X](../spec/x.md), UNKNOWN |\n")
+	assert.Contains(t, out, "| `CLI_X` | X \\| Y | [spec/x.md](../spec/x.md) | `cmd/x` |\n")
+	assert.Contains(t, out, "| `UNKNOWN` | (not in registry) | - | `cmd/x` |\n")
+
+	again, err := Build(root, files)
+	require.NoError(t, err)
+	assert.Equal(t, out, Render(again))
+}
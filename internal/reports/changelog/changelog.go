@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package changelog renders a deterministic CHANGELOG section for a commit
+// range: the status transitions of each feature in the registry, and the
+// Conventional Commits that touched it, grouped by type.
+//
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/internal/conventional"
+	"github.com/bartekus/cortex/internal/templates"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Types are the commit types in the order their groups are rendered, with
+// their group headings. Commits of any other type, and commits that are not
+// Conventional Commits, are grouped under "Other".
+var Types = []struct{ Type, Heading string }{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build"},
+	{"ci", "CI"},
+	{"style", "Style"},
+	{"chore", "Chores"},
+}
+
+// TypeOther is the type of commits without a known Conventional Commit type.
+const TypeOther = "other"
+
+// Commit is a commit in the range with the files it changed.
+type Commit struct {
+	Hash    string
+	Message string
+	Files   []string
+}
+
+// Change is a commit as it appears in the changelog.
+type Change struct {
+	Hash        string
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+	Features    []string
+}
+
+// Transition is how a feature changed in the registry over the range.
+// Governance and Implementation list the successive states, and are empty
+// when the state did not change.
+type Transition struct {
+	Feature        string
+	Title          string
+	Added          bool
+	Removed        bool
+	Governance     []string
+	Implementation []string
+}
+
+// Input is everything the changelog is rendered from.
+type Input struct {
+	// Title heads the section, such as a version or "Unreleased".
+	Title string
+	// Since is the ref the range starts from.
+	Since string
+	// Registries are features.yaml at Since and after each commit of the
+	// range that changed it, oldest first. A nil entry is a revision where
+	// the file does not exist.
+	Registries []*gov.Registry
+	// Commits are the commits of the range, oldest first.
+	Commits []Commit
+	// Index maps repo-relative paths to the feature they belong to.
+	Index map[string]string
+}
+
+// ParseRegistry parses features.yaml content.
+func ParseRegistry(data []byte) (*gov.Registry, error) {
+	var reg gov.Registry
+	if err := yaml.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("failed to parse registry YAML: %w", err)
+	}
+	return &reg, nil
+}
+
+// Transitions returns the features whose registry entry was added, removed
+// or changed state across registries, sorted by ID.
+func Transitions(registries []*gov.Registry) []Transition {
+	type history struct {
+		title          string
+		present        []bool
+		governance     []string
+		implementation []string
+	}
+	byID := map[string]*history{}
+	for i, reg := range registries {
+		if reg == nil {
+			continue
+		}
+		for _, f := range reg.Features {
+			h := byID[f.ID]
+			if h == nil {
+				h = &history{present: make([]bool, len(registries))}
+				byID[f.ID] = h
+			}
+			h.title = f.Title
+			h.present[i] = true
+			h.governance = appendState(h.governance, string(f.Governance))
+			h.implementation = appendState(h.implementation, string(f.Implementation))
+		}
+	}
+
+	var out []Transition
+	last := len(registries) - 1
+	for id, h := range byID {
+		t := Transition{
+			Feature:        id,
+			Title:          h.title,
+			Added:          !h.present[0] && h.present[last],
+			Removed:        !h.present[last],
+			Governance:     []string{},
+			Implementation: []string{},
+		}
+		if len(h.governance) > 1 {
+			t.Governance = h.governance
+		}
+		if len(h.implementation) > 1 {
+			t.Implementation = h.implementation
+		}
+		if t.Added || t.Removed || len(t.Governance) > 0 || len(t.Implementation) > 0 {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Feature < out[j].Feature })
+	return out
+}
+
+// appendState appends state unless it repeats the last one.
+func appendState(states []string, state string) []string {
+	if len(states) > 0 && states[len(states)-1] == state {
+		return states
+	}
+	return append(states, state)
+}
+
+var trailerPattern = regexp.MustCompile(`(?m)^Feature:\s*(\S+)\s*$`)
+
+// Changes returns the commits as changelog entries, oldest first. A
+// commit belongs to the features named by its "Feature: <ID>" trailers;
+// without trailers, to the features of the files it changed.
+func Changes(commits []Commit, index map[string]string) []Change {
+	known := map[string]bool{}
+	for _, t := range Types {
+		known[t.Type] = true
+	}
+
+	out := make([]Change, 0, len(commits))
+	for _, c := range commits {
+		header, _, _ := strings.Cut(c.Message, "\n")
+		ch := Change{Hash: c.Hash, Type: TypeOther, Description: strings.TrimSpace(header)}
+		if h, err := conventional.ParseHeader(header); err == nil {
+			ch.Scope, ch.Breaking, ch.Description = h.Scope, h.Breaking, h.Description
+			if known[h.Type] {
+				ch.Type = h.Type
+			}
+		}
+		if strings.Contains(c.Message, "\nBREAKING CHANGE:") || strings.Contains(c.Message, "\nBREAKING-CHANGE:") {
+			ch.Breaking = true
+		}
+
+		ids := map[string]bool{}
+		for _, m := range trailerPattern.FindAllStringSubmatch(c.Message, -1) {
+			ids[m[1]] = true
+		}
+		if len(ids) == 0 {
+			for _, p := range c.Files {
+				if id := index[p]; id != "" {
+					ids[id] = true
+				}
+			}
+		}
+		ch.Features = make([]string, 0, len(ids))
+		for id := range ids {
+			ch.Features = append(ch.Features, id)
+		}
+		sort.Strings(ch.Features)
+		out = append(out, ch)
+	}
+	return out
+}
+
+// Render returns the changelog section rendered with the embedded default
+// template: a subsection per feature with a transition or a commit, sorted
+// by ID, then the commits without a feature. A commit of several features
+// is listed under each.
+func Render(in Input) string {
+	return strings.TrimRight(templates.MustRender(templates.Changelog, newView(in)), "\n") + "\n"
+}
+
+// RenderWith returns the changelog section rendered with the changelog
+// template of set, which may be overridden by the repository. Trailing
+// blank lines are trimmed.
+func RenderWith(set *templates.Set, in Input) (string, error) {
+	out, err := set.Render(templates.Changelog, newView(in))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n") + "\n", nil
+}
+
+// view is the data of the changelog template.
+type view struct {
+	Title, Since string
+	Commits      int
+	Features     []featureSection
+	// Other groups the commits without a feature.
+	Other []group
+}
+
+type featureSection struct {
+	ID, Title string
+	// Transition is nil when the registry entry did not change.
+	Transition *Transition
+	Groups     []group
+}
+
+// group is the changes of one commit type.
+type group struct {
+	Heading string
+	Changes []Change
+}
+
+func newView(in Input) view {
+	transitions := Transitions(in.Registries)
+	changes := Changes(in.Commits, in.Index)
+
+	titles := map[string]string{}
+	for _, reg := range in.Registries {
+		if reg == nil {
+			continue
+		}
+		for _, f := range reg.Features {
+			titles[f.ID] = f.Title
+		}
+	}
+	byTransition := map[string]*Transition{}
+	ids := map[string]bool{}
+	for i := range transitions {
+		byTransition[transitions[i].Feature] = &transitions[i]
+		ids[transitions[i].Feature] = true
+	}
+	var other []Change
+	for _, ch := range changes {
+		if len(ch.Features) == 0 {
+			other = append(other, ch)
+		}
+		for _, id := range ch.Features {
+			ids[id] = true
+		}
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	v := view{Title: in.Title, Since: in.Since, Commits: len(changes), Other: groups(other)}
+	for _, id := range sorted {
+		var own []Change
+		for _, ch := range changes {
+			for _, f := range ch.Features {
+				if f == id {
+					own = append(own, ch)
+				}
+			}
+		}
+		v.Features = append(v.Features, featureSection{ID: id, Title: titles[id], Transition: byTransition[id], Groups: groups(own)})
+	}
+	return v
+}
+
+// groups groups changes by type, in the order of Types.
+func groups(changes []Change) []group {
+	byType := map[string][]Change{}
+	for _, ch := range changes {
+		byType[ch.Type] = append(byType[ch.Type], ch)
+	}
+	var out []group
+	headings := append(Types[:len(Types):len(Types)], struct{ Type, Heading string }{TypeOther, "Other"})
+	for _, t := range headings {
+		if len(byType[t.Type]) > 0 {
+			out = append(out, group{Heading: t.Heading, Changes: byType[t.Type]})
+		}
+	}
+	return out
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
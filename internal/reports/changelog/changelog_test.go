@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+
+package changelog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+func registry(t *testing.T, yaml string) *gov.Registry {
+	t.Helper()
+	reg, err := ParseRegistry([]byte(yaml))
+	if err != nil {
+		t.Fatalf("ParseRegistry() error = %v", err)
+	}
+	return reg
+}
+
+func TestTransitions(t *testing.T) {
+	regs := []*gov.Registry{
+		registry(t, `features:
+  - {id: A, title: Alpha, governance: draft, implementation: todo}
+  - {id: B, title: Beta, governance: approved, implementation: done}
+  - {id: C, title: Gamma, governance: approved, implementation: done}
+`),
+		registry(t, `features:
+  - {id: A, title: Alpha, governance: approved, implementation: wip}
+  - {id: B, title: Beta, governance: approved, implementation: done}
+  - {id: C, title: Gamma, governance: approved, implementation: done}
+`),
+		registry(t, `features:
+  - {id: A, title: Alpha, governance: approved, implementation: done}
+  - {id: B, title: Beta, governance: approved, implementation: done}
+  - {id: D, title: Delta, governance: draft, implementation: todo}
+`),
+	}
+
+	got := Transitions(regs)
+	want := []Transition{
+		{Feature: "A", Title: "Alpha", Governance: []string{"draft", "approved"}, Implementation: []string{"todo", "wip", "done"}},
+		{Feature: "C", Title: "Gamma", Removed: true, Governance: []string{}, Implementation: []string{}},
+		{Feature: "D", Title: "Delta", Added: true, Governance: []string{}, Implementation: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Transitions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTransitions_MissingRegistry(t *testing.T) {
+	got := Transitions([]*gov.Registry{nil, registry(t, "features:\n  - {id: A, title: Alpha, governance: draft, implementation: todo}\n")})
+	if len(got) != 1 || !got[0].Added {
+		t.Errorf("Transitions() = %+v, want A added", got)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	index := map[string]string{"cmd/a.go": "A", "cmd/b.go": "B"}
+	got := Changes([]Commit{
+		{Hash: "1111111111", Message: "feat(A)!: add alpha", Files: []string{"cmd/a.go", "README.md"}},
+		{Hash: "2222222222", Message: "fix: repair beta\n\nBREAKING CHANGE: flag removed\n\nFeature: A\nFeature: B", Files: []string{"cmd/c.go"}},
+		{Hash: "3333333333", Message: "Update docs", Files: []string{"docs/x.md"}},
+		{Hash: "4444444444", Message: "wip: try things", Files: []string{"cmd/b.go"}},
+	}, index)
+
+	want := []Change{
+		{Hash: "1111111111", Type: "feat", Scope: "A", Breaking: true, Description: "add alpha", Features: []string{"A"}},
+		{Hash: "2222222222", Type: "fix", Breaking: true, Description: "repair beta", Features: []string{"A", "B"}},
+		{Hash: "3333333333", Type: TypeOther, Description: "Update docs", Features: []string{}},
+		{Hash: "4444444444", Type: TypeOther, Description: "try things", Features: []string{"B"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	in := Input{
+		Title: "v1.2.0",
+		Since: "v1.1.0",
+		Registries: []*gov.Registry{
+			registry(t, "features:\n  - {id: A, title: Alpha, governance: approved, implementation: wip}\n"),
+			registry(t, "features:\n  - {id: A, title: Alpha, governance: approved, implementation: done}\n  - {id: B, title: Beta, governance: draft, implementation: todo}\n"),
+		},
+		Commits: []Commit{
+			{Hash: "1111111111", Message: "fix(A): handle empty input", Files: []string{"a.go"}},
+			{Hash: "2222222222", Message: "feat(A): finish alpha", Files: []string{"a.go"}},
+			{Hash: "3333333333", Message: "chore: bump deps", Files: []string{"go.mod"}},
+		},
+		Index: map[string]string{"a.go": "A"},
+	}
+
+	want := "## v1.2.0\n\n" +
+		"Changes since `v1.1.0`: 3 commit(s), 2 feature(s).\n\n" +
+		"### `A` Alpha\n\n" +
+		"- Implementation: `wip` → `done`\n\n" +
+		"#### Features\n\n" +
+		"- finish alpha (`2222222`)\n\n" +
+		"#### Bug Fixes\n\n" +
+		"- handle empty input (`1111111`)\n\n" +
+		"### `B` Beta\n\n" +
+		"- Added to the registry.\n\n" +
+		"### Other Changes\n\n" +
+		"#### Chores\n\n" +
+		"- bump deps (`3333333`)\n"
+	if got := Render(in); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+	if Render(in) != Render(in) {
+		t.Error("Render() is not deterministic")
+	}
+}
+
+func TestRender_NoChanges(t *testing.T) {
+	got := Render(Input{Title: "Unreleased", Since: "v1", Registries: []*gov.Registry{nil}})
+	want := "## Unreleased\n\nChanges since `v1`: 0 commit(s), 0 feature(s).\n\nNo changes.\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
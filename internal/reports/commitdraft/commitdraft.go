@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package commitdraft drafts a Conventional Commit message for a staged diff.
+//
+// Touched files are attributed to features through the feature mapping
+// (Feature: headers and canonical spec paths). The most-touched feature
+// becomes the scope, matching the <type>(<FEATURE_ID>): <summary> format
+// checked by the commit health report.
+//
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+package commitdraft
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/mapping"
+)
+
+// maxHeaderLength matches the summary limit enforced by the commit health report.
+const maxHeaderLength = 72
+
+// File is a staged file and the feature it belongs to, if any.
+type File struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Feature string `json:"feature,omitempty"`
+}
+
+// Draft is a proposed commit message and the data it was derived from.
+type Draft struct {
+	Type        string   `json:"type"`
+	Scope       string   `json:"scope,omitempty"`
+	Description string   `json:"description"`
+	Features    []string `json:"features"`
+	Files       []File   `json:"files"`
+	Message     string   `json:"message"`
+	// Polished is set when Description was rewritten by Polish.
+	Polished bool `json:"polished,omitempty"`
+}
+
+// Options overrides the inferred parts of the draft.
+type Options struct {
+	Type  string
+	Scope string
+	// Trailer appends a "Feature: <ID>" trailer for every touched feature.
+	Trailer bool
+}
+
+// FeatureIndex maps repository-relative paths to their feature ID using the
+// implementation, test and spec files of a feature mapping report.
+func FeatureIndex(report mapping.Report) map[string]string {
+	index := map[string]string{}
+	for _, f := range report.Features {
+		if f.SpecPath != "" {
+			index[path.Clean(f.SpecPath)] = f.ID
+		}
+		for _, p := range f.ImplFiles {
+			index[p] = f.ID
+		}
+		for _, p := range f.TestFiles {
+			index[p] = f.ID
+		}
+	}
+	return index
+}
+
+// Build drafts a commit message for changes. It fails when nothing is staged.
+func Build(changes []git.Change, index map[string]string, opts Options) (Draft, error) {
+	if len(changes) == 0 {
+		return Draft{}, errors.New("no staged changes")
+	}
+
+	d := Draft{Features: []string{}}
+	counts := map[string]int{}
+	for _, c := range changes {
+		f := File{Path: c.Path, Status: statusName(c.Status), Feature: index[c.Path]}
+		if f.Feature != "" {
+			if counts[f.Feature] == 0 {
+				d.Features = append(d.Features, f.Feature)
+			}
+			counts[f.Feature]++
+		}
+		d.Files = append(d.Files, f)
+	}
+	sort.Strings(d.Features)
+
+	d.Type = opts.Type
+	if d.Type == "" {
+		d.Type = inferType(d.Files)
+	}
+	d.Scope = opts.Scope
+	if d.Scope == "" {
+		d.Scope = primaryFeature(d.Features, counts)
+	}
+	d.Description = describe(d.Files, len(prefix(d.Type, d.Scope)))
+	d.Message = render(d, opts.Trailer)
+	return d, nil
+}
+
+// PolishPrompt returns the system prompt and the request asking a language
+// model to rewrite the description of d. The model sees the draft and its
+// files only; it cannot change the type, scope or body.
+func PolishPrompt(d Draft) (system, prompt string) {
+	system = "You improve Conventional Commit summaries. Reply with the summary only: one line, " +
+		"imperative mood, lowercase first word, no trailing period, no type or scope prefix."
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Header: %s%s\n", prefix(d.Type, d.Scope), d.Description)
+	fmt.Fprintf(&sb, "The summary must fit in %d characters.\n\nStaged files:\n", maxHeaderLength-len(prefix(d.Type, d.Scope)))
+	for _, f := range d.Files {
+		fmt.Fprintf(&sb, "- %s (%s)\n", f.Path, f.Status)
+	}
+	return system, sb.String()
+}
+
+// Polish replaces the description of d with a model's reply to
+// PolishPrompt and renders the message again. The reply is reduced to its
+// first non-empty line without a repeated prefix or trailing period; a
+// reply that is empty or does not fit the header is rejected.
+func Polish(d Draft, reply string, opts Options) (Draft, error) {
+	desc := ""
+	for _, line := range strings.Split(reply, "\n") {
+		if desc = strings.TrimSpace(line); desc != "" {
+			break
+		}
+	}
+	desc = strings.Trim(desc, "`\"")
+	desc = strings.TrimPrefix(desc, prefix(d.Type, d.Scope))
+	desc = strings.TrimSpace(strings.TrimRight(desc, "."))
+	if desc == "" {
+		return d, errors.New("the model returned an empty summary")
+	}
+	if n := len(prefix(d.Type, d.Scope)) + len(desc); n > maxHeaderLength {
+		return d, fmt.Errorf("the model returned a %d-character header (max %d)", n, maxHeaderLength)
+	}
+	d.Description = desc
+	d.Polished = true
+	d.Message = render(d, opts.Trailer)
+	return d, nil
+}
+
+func statusName(code string) string {
+	switch code {
+	case "A":
+		return "added"
+	case "C":
+		return "copied"
+	case "D":
+		return "deleted"
+	case "R":
+		return "renamed"
+	case "T":
+		return "type-changed"
+	default:
+		return "modified"
+	}
+}
+
+// inferType guesses the commit type from the kinds of files touched. Only
+// types accepted by the commit health report are returned.
+func inferType(files []File) string {
+	all := func(pred func(string) bool) bool {
+		for _, f := range files {
+			if !pred(f.Path) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case all(isDoc):
+		return "docs"
+	case all(isTest):
+		return "test"
+	case all(isCI):
+		return "ci"
+	}
+	for _, f := range files {
+		if f.Status == "added" && isSource(f.Path) && !isTest(f.Path) {
+			return "feat"
+		}
+	}
+	for _, f := range files {
+		if isSource(f.Path) && !isTest(f.Path) {
+			return "fix"
+		}
+	}
+	return "chore"
+}
+
+func isDoc(p string) bool {
+	return strings.HasSuffix(p, ".md") || strings.HasPrefix(p, "docs/")
+}
+
+func isTest(p string) bool {
+	return strings.HasSuffix(p, "_test.go") || strings.Contains("/"+p, "/testdata/")
+}
+
+func isCI(p string) bool {
+	return strings.HasPrefix(p, ".github/")
+}
+
+func isSource(p string) bool {
+	switch path.Ext(p) {
+	case ".go", ".rs", ".ts", ".tsx", ".js", ".jsx":
+		return true
+	}
+	return false
+}
+
+// primaryFeature returns the feature with the most touched files, breaking
+// ties lexicographically. features must be sorted.
+func primaryFeature(features []string, counts map[string]int) string {
+	best := ""
+	for _, id := range features {
+		if best == "" || counts[id] > counts[best] {
+			best = id
+		}
+	}
+	return best
+}
+
+func prefix(typ, scope string) string {
+	if scope == "" {
+		return typ + ": "
+	}
+	return typ + "(" + scope + "): "
+}
+
+// describe summarises the files in a lowercase, period-free phrase that fits
+// in the header after a prefix of prefixLen characters.
+func describe(files []File, prefixLen int) string {
+	verb := "update"
+	if s := files[0].Status; s == "added" || s == "deleted" || s == "renamed" {
+		same := true
+		for _, f := range files {
+			same = same && f.Status == s
+		}
+		if same {
+			verb = map[string]string{"added": "add", "deleted": "remove", "renamed": "rename"}[s]
+		}
+	}
+
+	if len(files) == 1 {
+		desc := verb + " " + files[0].Path
+		if prefixLen+len(desc) > maxHeaderLength {
+			desc = verb + " " + path.Base(files[0].Path)
+		}
+		return desc
+	}
+
+	dir := path.Dir(files[0].Path)
+	for _, f := range files[1:] {
+		for dir != "." && !strings.HasPrefix(f.Path, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	desc := fmt.Sprintf("%s %d files", verb, len(files))
+	if dir != "." && prefixLen+len(desc)+len(" in ")+len(dir) <= maxHeaderLength {
+		desc += " in " + dir
+	}
+	return desc
+}
+
+func render(d Draft, trailer bool) string {
+	var sb strings.Builder
+	sb.WriteString(prefix(d.Type, d.Scope) + d.Description + "\n")
+
+	if len(d.Files) > 1 {
+		sb.WriteString("\n")
+		for _, f := range d.Files {
+			fmt.Fprintf(&sb, "- %s (%s)\n", f.Path, f.Status)
+		}
+	}
+
+	if trailer && len(d.Features) > 0 {
+		sb.WriteString("\n")
+		for _, id := range d.Features {
+			fmt.Fprintf(&sb, "Feature: %s\n", id)
+		}
+	}
+	return sb.String()
+}
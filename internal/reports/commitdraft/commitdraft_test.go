@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commitdraft
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/mapping"
+)
+
+func TestFeatureIndex(t *testing.T) {
+	report := mapping.Report{Features: []mapping.FeatureMapping{
+		{ID: "CLI_A", SpecPath: "spec/cli/a.md", ImplFiles: []string{"cmd/a.go"}, TestFiles: []string{"cmd/a_test.go"}},
+	}}
+
+	want := map[string]string{"spec/cli/a.md": "CLI_A", "cmd/a.go": "CLI_A", "cmd/a_test.go": "CLI_A"}
+	if got := FeatureIndex(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("FeatureIndex() = %v, want %v", got, want)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	index := map[string]string{
+		"cmd/a.go":      "CLI_A",
+		"cmd/a_test.go": "CLI_A",
+		"cmd/b.go":      "CLI_B",
+		"spec/cli/a.md": "CLI_A",
+	}
+
+	tests := []struct {
+		name    string
+		changes []git.Change
+		opts    Options
+		want    string
+	}{
+		{
+			name:    "single doc",
+			changes: []git.Change{{Status: "M", Path: "spec/cli/a.md"}},
+			want:    "docs(CLI_A): update spec/cli/a.md\n",
+		},
+		{
+			name:    "tests only",
+			changes: []git.Change{{Status: "A", Path: "cmd/a_test.go"}},
+			want:    "test(CLI_A): add cmd/a_test.go\n",
+		},
+		{
+			name: "new source picks most touched feature",
+			changes: []git.Change{
+				{Status: "A", Path: "cmd/a.go"},
+				{Status: "M", Path: "cmd/a_test.go"},
+				{Status: "M", Path: "cmd/b.go"},
+			},
+			opts: Options{Trailer: true},
+			want: "feat(CLI_A): update 3 files in cmd\n\n" +
+				"- cmd/a.go (added)\n- cmd/a_test.go (modified)\n- cmd/b.go (modified)\n\n" +
+				"Feature: CLI_A\nFeature: CLI_B\n",
+		},
+		{
+			name:    "modified source without feature",
+			changes: []git.Change{{Status: "D", Path: "internal/x/x.go"}, {Status: "D", Path: "internal/y.go"}},
+			want:    "fix: remove 2 files in internal\n\n- internal/x/x.go (deleted)\n- internal/y.go (deleted)\n",
+		},
+		{
+			name:    "overrides",
+			changes: []git.Change{{Status: "M", Path: "cmd/b.go"}},
+			opts:    Options{Type: "refactor", Scope: "CLI_C"},
+			want:    "refactor(CLI_C): update cmd/b.go\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Build(tt.changes, index, tt.opts)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if d.Message != tt.want {
+				t.Errorf("Build() message = %q, want %q", d.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild_NoChanges(t *testing.T) {
+	if _, err := Build(nil, nil, Options{}); err == nil {
+		t.Fatal("expected error for empty change set")
+	}
+}
+
+func TestDescribe_LongPathUsesBaseName(t *testing.T) {
+	files := []File{{Path: "internal/some/very/deeply/nested/directory/structure/file_name.go", Status: "modified"}}
+	if got, want := describe(files, len("fix(CLI_COMMAND_COMMIT): ")), "update file_name.go"; got != want {
+		t.Errorf("describe() = %q, want %q", got, want)
+	}
+}
+
+func TestPolish(t *testing.T) {
+	changes := []git.Change{{Status: "M", Path: "cmd/a.go"}}
+	d, err := Build(changes, map[string]string{"cmd/a.go": "CLI_A"}, Options{Trailer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, prompt := PolishPrompt(d)
+	if !strings.Contains(prompt, "- cmd/a.go (modified)") {
+		t.Errorf("prompt does not list the files:\n%s", prompt)
+	}
+
+	got, err := Polish(d, "\nfix(CLI_A): handle empty flags.\nmore text", Options{Trailer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "fix(CLI_A): handle empty flags\n\nFeature: CLI_A\n"; got.Message != want || !got.Polished {
+		t.Errorf("Polish message = %q, want %q", got.Message, want)
+	}
+
+	for _, reply := range []string{"  \n", strings.Repeat("x", 80)} {
+		if _, err := Polish(d, reply, Options{}); err == nil {
+			t.Errorf("Polish(%q) succeeded", reply)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package prsummary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/gomod"
+)
+
+// FileCoverage counts the statements of a file and how many were executed.
+type FileCoverage struct {
+	Statements int
+	Covered    int
+}
+
+// Coverage is a Go cover profile aggregated per repo-relative file.
+type Coverage map[string]FileCoverage
+
+// ParseCoverage reads a `go test -coverprofile` profile. File names are made
+// repo-relative by stripping the module path. Blocks reported by several
+// test binaries are counted once, covered if any binary executed them.
+func ParseCoverage(r io.Reader, module string) (Coverage, error) {
+	type block struct {
+		file    string
+		stmts   int
+		covered bool
+	}
+	blocks := map[string]*block{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "mode:") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("coverage profile line %d: malformed %q", line, text)
+		}
+		file, _, ok := strings.Cut(fields[0], ":")
+		if !ok {
+			return nil, fmt.Errorf("coverage profile line %d: malformed %q", line, text)
+		}
+		stmts, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("coverage profile line %d: %w", line, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("coverage profile line %d: %w", line, err)
+		}
+
+		if module != "" {
+			file = strings.TrimPrefix(file, module+"/")
+		}
+		b, seen := blocks[fields[0]]
+		if !seen {
+			b = &block{file: file, stmts: stmts}
+			blocks[fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	cov := Coverage{}
+	for _, b := range blocks {
+		fc := cov[b.file]
+		fc.Statements += b.stmts
+		if b.covered {
+			fc.Covered += b.stmts
+		}
+		cov[b.file] = fc
+	}
+	return cov, nil
+}
+
+// Percent returns the statement coverage of files, or of the whole profile
+// when files is nil. ok is false when no statements are counted.
+func (c Coverage) Percent(files []string) (pct float64, ok bool) {
+	var total FileCoverage
+	add := func(fc FileCoverage) {
+		total.Statements += fc.Statements
+		total.Covered += fc.Covered
+	}
+	if files == nil {
+		for _, fc := range c {
+			add(fc)
+		}
+	} else {
+		for _, f := range files {
+			add(c[f])
+		}
+	}
+	if total.Statements == 0 {
+		return 0, false
+	}
+	return 100 * float64(total.Covered) / float64(total.Statements), true
+}
+
+// ReadCoverage parses the profile at path with ParseCoverage, stripping the
+// module path declared in repoRoot/go.mod. An empty path means no profile.
+func ReadCoverage(path, repoRoot string) (Coverage, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path) //nolint:gosec // G304: path is from a CLI flag
+	if err != nil {
+		return nil, fmt.Errorf("reading coverage profile: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var module string
+	if data, err := os.ReadFile(filepath.Join(repoRoot, "go.mod")); err == nil { //nolint:gosec // G304: fixed file under the repo root
+		module = gomod.Parse(data).Module
+	}
+	cov, err := ParseCoverage(f, module)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cov, nil
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package prsummary renders a deterministic Markdown pull request description
+// from a branch diff: impacted features and their specs, the last skill run,
+// coverage against the base, and a change inventory.
+//
+// Feature: CLI_COMMAND_COMMIT
+// Spec: spec/cli/commit.md
+package prsummary
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+// Input is everything the summary is rendered from.
+type Input struct {
+	Base      string
+	MergeBase string
+	Changes   []git.Change
+	Lines     map[string]git.LineStat
+	// Index maps repo-relative paths to the feature they belong to.
+	Index map[string]string
+	Graph *features.Graph
+	// Skills are the results of the last run, in run order.
+	Skills []runner.SkillResult
+	// Coverage and BaseCoverage are nil when no profile is available.
+	Coverage     Coverage
+	BaseCoverage Coverage
+}
+
+// Render returns the PR description rendered with the embedded default
+// template.
+func Render(in Input) string {
+	return templates.MustRender(templates.PRSummary, newView(in))
+}
+
+// RenderWith returns the PR description rendered with the pr-summary
+// template of set, which may be overridden by the repository.
+func RenderWith(set *templates.Set, in Input) (string, error) {
+	return set.Render(templates.PRSummary, newView(in))
+}
+
+// view is the data of the pr-summary template.
+type view struct {
+	Base string
+	// MergeBase is abbreviated to 12 characters.
+	MergeBase      string
+	Files          int
+	Added, Deleted int
+	// Features are the features of changed files, sorted by ID.
+	Features []featureRow
+	// Downstream are the features depending on an impacted feature that
+	// have no changed file themselves.
+	Downstream []string
+	Skills     []skillRow
+	// Coverage is nil when no coverage profile is available.
+	Coverage []coverageRow
+	// Changes are sorted by path.
+	Changes []changeRow
+}
+
+type featureRow struct {
+	ID, Title, Implementation, Spec string
+	Files                           int
+}
+
+type skillRow struct {
+	Skill, Status string
+	// Note is the first line of the skill note.
+	Note string
+}
+
+// coverageRow holds percentages formatted with one decimal, "n/a" when a
+// profile does not cover the scope.
+type coverageRow struct {
+	Scope, Base, Head, Delta string
+}
+
+type changeRow struct {
+	Path, Status, Feature string
+	// Added and Deleted are "-" for binary files.
+	Added, Deleted string
+}
+
+func newView(in Input) view {
+	v := view{Base: in.Base, MergeBase: shortHash(in.MergeBase), Files: len(in.Changes)}
+	for _, st := range in.Lines {
+		v.Added += st.Added
+		v.Deleted += st.Deleted
+	}
+	v.Features, v.Downstream = featureRows(in)
+	for _, r := range in.Skills {
+		note, _, _ := strings.Cut(r.Note, "\n")
+		v.Skills = append(v.Skills, skillRow{Skill: r.Skill, Status: string(r.Status), Note: note})
+	}
+	v.Coverage = coverageRows(in)
+	v.Changes = changeRows(in)
+	return v
+}
+
+func featureRows(in Input) ([]featureRow, []string) {
+	counts := map[string]int{}
+	for _, c := range in.Changes {
+		if id := in.Index[c.Path]; id != "" {
+			counts[id]++
+		}
+	}
+
+	var rows []featureRow
+	downstream := map[string]int{}
+	for _, id := range projection.SortedKeys(counts) {
+		row := featureRow{ID: id, Files: counts[id]}
+		if in.Graph != nil {
+			if n, ok := in.Graph.Nodes[id]; ok {
+				row.Title, row.Implementation, row.Spec = n.Title, n.Implementation, n.Spec
+			}
+			for _, dep := range features.ImpactWith(in.Graph, id, features.ImpactOptions{}) {
+				if counts[dep] == 0 {
+					downstream[dep]++
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, projection.SortedKeys(downstream)
+}
+
+func coverageRows(in Input) []coverageRow {
+	if in.Coverage == nil {
+		return nil
+	}
+
+	var changed []string
+	for _, c := range in.Changes {
+		if strings.HasSuffix(c.Path, ".go") && !strings.HasSuffix(c.Path, "_test.go") {
+			changed = append(changed, c.Path)
+		}
+	}
+
+	row := func(scope string, files []string) coverageRow {
+		head, headOK := in.Coverage.Percent(files)
+		base, baseOK := in.BaseCoverage.Percent(files)
+		r := coverageRow{Scope: scope, Base: "n/a", Head: "n/a", Delta: "n/a"}
+		if baseOK {
+			r.Base = fmt.Sprintf("%.1f%%", base)
+		}
+		if headOK {
+			r.Head = fmt.Sprintf("%.1f%%", head)
+		}
+		if baseOK && headOK {
+			r.Delta = fmt.Sprintf("%+.1f", head-base)
+		}
+		return r
+	}
+	rows := []coverageRow{row("Total", nil)}
+	if len(changed) > 0 {
+		rows = append(rows, row("Changed files", changed))
+	}
+	return rows
+}
+
+func changeRows(in Input) []changeRow {
+	changes := append([]git.Change(nil), in.Changes...)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	rows := make([]changeRow, 0, len(changes))
+	for _, c := range changes {
+		r := changeRow{Path: c.Path, Status: statusName(c.Status), Feature: in.Index[c.Path], Added: "-", Deleted: "-"}
+		if st, ok := in.Lines[c.Path]; ok && !st.Binary {
+			r.Added, r.Deleted = strconv.Itoa(st.Added), strconv.Itoa(st.Deleted)
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func statusName(code string) string {
+	switch code {
+	case "A":
+		return "added"
+	case "C":
+		return "copied"
+	case "D":
+		return "deleted"
+	case "R":
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package prsummary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/features"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+func TestParseCoverage(t *testing.T) {
+	profile := `mode: atomic
+example.com/m/a/a.go:1.1,2.2 3 0
+example.com/m/a/a.go:3.1,4.2 1 2
+example.com/m/a/a.go:1.1,2.2 3 1
+example.com/m/b.go:1.1,2.2 4 0
+`
+	cov, err := ParseCoverage(strings.NewReader(profile), "example.com/m")
+	if err != nil {
+		t.Fatalf("ParseCoverage() error = %v", err)
+	}
+
+	if got, want := cov["a/a.go"], (FileCoverage{Statements: 4, Covered: 4}); got != want {
+		t.Errorf("a/a.go = %+v, want %+v", got, want)
+	}
+	if pct, ok := cov.Percent(nil); !ok || pct != 50 {
+		t.Errorf("Percent(nil) = %v, %v; want 50, true", pct, ok)
+	}
+	if _, ok := cov.Percent([]string{"missing.go"}); ok {
+		t.Error("Percent(missing) should not be ok")
+	}
+
+	if _, err := ParseCoverage(strings.NewReader("mode: set\nbroken\n"), ""); err == nil {
+		t.Error("expected error for malformed profile")
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := features.NewGraph()
+	g.AddNode(&features.FeatureNode{ID: "CORE", Title: "Core | base", Implementation: "done", Spec: "spec/core.md"})
+	g.AddNode(&features.FeatureNode{ID: "CLI", Title: "CLI", Implementation: "wip", Spec: "spec/cli.md", DependsOn: []string{"CORE"}})
+	g.AddEdge("CLI", "CORE")
+
+	in := Input{
+		Base:      "main",
+		MergeBase: "0123456789abcdef",
+		Changes: []git.Change{
+			{Status: "M", Path: "core/core.go"},
+			{Status: "A", Path: "assets/logo.png"},
+		},
+		Lines: map[string]git.LineStat{
+			"core/core.go":    {Added: 10, Deleted: 2},
+			"assets/logo.png": {Binary: true},
+		},
+		Index:        map[string]string{"core/core.go": "CORE"},
+		Graph:        g,
+		Skills:       []runner.SkillResult{{Skill: "purity", Status: runner.StatusPass, Note: "No banned imports found.\nmore"}},
+		Coverage:     Coverage{"core/core.go": {Statements: 10, Covered: 8}, "x.go": {Statements: 10, Covered: 10}},
+		BaseCoverage: Coverage{"core/core.go": {Statements: 10, Covered: 5}, "x.go": {Statements: 10, Covered: 10}},
+	}
+
+	want := "## Summary\n\n" +
+		"Changes against `main` (merge-base `0123456789ab`): 2 file(s), +10/-2 lines.\n\n" +
+		"## Impacted Features\n\n" +
+		"| Feature | Title | Implementation | Spec | Files |\n| --- | --- | --- | --- | --- |\n" +
+		"| `CORE` | Core \\| base | done | [spec/core.md](spec/core.md) | 1 |\n\n" +
+		"Downstream dependents: `CLI`.\n\n" +
+		"## Skill Results\n\n" +
+		"| Skill | Status | Note |\n| --- | --- | --- |\n" +
+		"| `purity` | pass | No banned imports found. |\n\n" +
+		"## Coverage\n\n" +
+		"| Scope | Base | Head | Delta |\n| --- | --- | --- | --- |\n" +
+		"| Total | 75.0% | 90.0% | +15.0 |\n" +
+		"| Changed files | 50.0% | 80.0% | +30.0 |\n\n" +
+		"## Change Inventory\n\n" +
+		"| Path | Status | Feature | + | - |\n| --- | --- | --- | --- | --- |\n" +
+		"| `assets/logo.png` | added |  | - | - |\n" +
+		"| `core/core.go` | modified | `CORE` | 10 | 2 |\n"
+
+	if got := Render(in); got != want {
+		t.Errorf("Render() mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	got := Render(Input{Base: "main", MergeBase: "abc"})
+	for _, want := range []string{
+		"No changed file belongs to a feature.",
+		"No skill results recorded",
+		"No coverage profile available",
+		"No changes.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+}
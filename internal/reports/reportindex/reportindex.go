@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package reportindex maintains .cortex/reports/index.json, the manifest of
+// every report and context artifact Cortex generated in a repository: its
+// digest, the command and version that wrote it, the repository
+// fingerprint it was generated from and the inputs it was generated with.
+//
+// Generators call Begin before they read their sources and Record after
+// writing, so the index always describes the artifacts on disk. The index
+// has no timestamps; the same generations produce the same bytes.
+//
+// Feature: REPORTS_CORE
+// Spec: spec/reports/core.md
+package reportindex
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/bartekus/cortex/internal/fingerprint"
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/schemas"
+	"github.com/bartekus/cortex/internal/version"
+)
+
+// File is the repo-relative path of the index.
+const File = ".cortex/reports/index.json"
+
+// Record holds lockFile while it rewrites the index, which takes
+// milliseconds; waiters poll every lockPoll.
+const (
+	lockFile  = File + ".lock"
+	lockPoll  = 10 * time.Millisecond
+	staleLock = time.Minute
+)
+
+// SchemaVersion is the schema_version of the index.
+const SchemaVersion = "1.0"
+
+// Artifact kinds.
+const (
+	KindReport  = "report"
+	KindContext = "context"
+)
+
+// Artifact statuses reported by Status.
+const (
+	StatusOK       = "ok"
+	StatusModified = "modified"
+	StatusMissing  = "missing"
+)
+
+// Index is the content of File.
+type Index struct {
+	SchemaVersion string `json:"schema_version"`
+	// Artifacts are sorted by path.
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Artifact is one generated file.
+type Artifact struct {
+	// Path is repo-relative, with forward slashes.
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	// Digest is "sha256:<hex>" of the file as written.
+	Digest string `json:"digest"`
+	// Generator is the command that wrote the file, e.g.
+	// "cortex reports commit-report".
+	Generator        string `json:"generator"`
+	GeneratorVersion string `json:"generator_version"`
+	// SourceFingerprint is the fingerprint digest of the repository before
+	// the generator ran; empty outside git.
+	SourceFingerprint string `json:"source_fingerprint,omitempty"`
+	// Inputs are the flags the generator was given, by name without
+	// dashes, and its arguments under "args". Global flags are left out.
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// Generation describes one run of a generator.
+type Generation struct {
+	Command           string
+	Version           string
+	SourceFingerprint string
+	Inputs            map[string]string
+}
+
+// Begin describes a run of cmd with args in repoRoot. Call it before
+// reading the sources so the fingerprint covers what the artifacts were
+// generated from.
+func Begin(cmd *cobra.Command, args []string, repoRoot string) Generation {
+	g := Generation{Command: cmd.CommandPath(), Version: version.String()}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if fp, err := fingerprint.Compute(ctx, repoRoot); err == nil {
+		g.SourceFingerprint = fp.Digest()
+	}
+	// The global flags (logging, verbosity) do not change what is generated.
+	global := cmd.Root().PersistentFlags()
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if cmd != cmd.Root() && global.Lookup(f.Name) != nil {
+			return
+		}
+		if g.Inputs == nil {
+			g.Inputs = map[string]string{}
+		}
+		g.Inputs[f.Name] = f.Value.String()
+	})
+	if len(args) > 0 {
+		if g.Inputs == nil {
+			g.Inputs = map[string]string{}
+		}
+		g.Inputs["args"] = strings.Join(args, " ")
+	}
+	return g
+}
+
+// Record adds the artifacts at paths (absolute or relative to repoRoot) to
+// the index of repoRoot, replacing earlier entries for the same paths.
+// Paths outside the repository are not indexed. Concurrent generators take
+// turns, so none drops the entries of another.
+func (g Generation) Record(repoRoot, kind string, paths ...string) error {
+	unlock, err := lockIndex(repoRoot)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	idx, err := Read(repoRoot)
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]Artifact, len(idx.Artifacts))
+	for _, a := range idx.Artifacts {
+		byPath[a.Path] = a
+	}
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = scanner.OSPath(repoRoot, p)
+		}
+		rel, ok := scanner.RelPath(repoRoot, p)
+		if !ok {
+			continue
+		}
+		digest, err := fileDigest(p)
+		if err != nil {
+			return fmt.Errorf("indexing %s: %w", rel, err)
+		}
+		byPath[rel] = Artifact{
+			Path:              rel,
+			Kind:              kind,
+			Digest:            digest,
+			Generator:         g.Command,
+			GeneratorVersion:  g.Version,
+			SourceFingerprint: g.SourceFingerprint,
+			Inputs:            g.Inputs,
+		}
+	}
+
+	idx.Artifacts = idx.Artifacts[:0]
+	for _, a := range byPath {
+		idx.Artifacts = append(idx.Artifacts, a)
+	}
+	sort.Slice(idx.Artifacts, func(i, j int) bool { return idx.Artifacts[i].Path < idx.Artifacts[j].Path })
+	idx.SchemaVersion = SchemaVersion
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return projection.AtomicWrite(scanner.OSPath(repoRoot, File), append(data, '\n'))
+}
+
+// lockIndex waits for the lock file next to the index, created with O_EXCL,
+// and returns the func that removes it. A lock older than staleLock was
+// left by a crashed generator and is removed.
+func lockIndex(repoRoot string) (unlock func(), err error) {
+	path := scanner.OSPath(repoRoot, lockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", filepath.Dir(lockFile), err)
+	}
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("locking %s: %w", File, err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			_ = os.Remove(path)
+			continue
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// Read loads the index of repoRoot; an empty index when there is none.
+func Read(repoRoot string) (*Index, error) {
+	data, err := os.ReadFile(scanner.OSPath(repoRoot, File))
+	if os.IsNotExist(err) {
+		return &Index{SchemaVersion: SchemaVersion, Artifacts: []Artifact{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", File, err)
+	}
+	var idx Index
+	if err := schemas.Decode(schemas.ReportIndex, data, &idx); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", File, err)
+	}
+	return &idx, nil
+}
+
+// Status compares the artifact with the file on disk: StatusOK,
+// StatusModified when its digest changed or StatusMissing.
+func (a Artifact) Status(repoRoot string) string {
+	digest, err := fileDigest(scanner.OSPath(repoRoot, a.Path))
+	switch {
+	case err != nil:
+		return StatusMissing
+	case digest != a.Digest:
+		return StatusModified
+	}
+	return StatusOK
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
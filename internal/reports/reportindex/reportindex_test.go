@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package reportindex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+func TestRecord(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write(".cortex/reports/b.json", "{}")
+	write("docs/a.md", "# a\n")
+
+	gen := Generation{Command: "cortex test", Version: "1.2.3", Inputs: map[string]string{"format": "md"}}
+	outside := filepath.Join(t.TempDir(), "x.md")
+	require.NoError(t, os.WriteFile(outside, nil, 0o644))
+	require.NoError(t, gen.Record(root, KindReport, ".cortex/reports/b.json", filepath.Join(root, "docs", "a.md"), outside))
+
+	idx, err := Read(root)
+	require.NoError(t, err)
+	require.Len(t, idx.Artifacts, 2, "paths outside the repository are not indexed")
+	assert.Equal(t, Artifact{
+		Path:             ".cortex/reports/b.json",
+		Kind:             KindReport,
+		Digest:           "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		Generator:        "cortex test",
+		GeneratorVersion: "1.2.3",
+		Inputs:           map[string]string{"format": "md"},
+	}, idx.Artifacts[0])
+	assert.Equal(t, "docs/a.md", idx.Artifacts[1].Path, "sorted by path")
+	assert.Equal(t, StatusOK, idx.Artifacts[1].Status(root))
+
+	indexPath := filepath.Join(root, filepath.FromSlash(File))
+	first, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	require.NoError(t, schemas.Validate(schemas.ReportIndex, first))
+	require.NoError(t, gen.Record(root, KindReport, ".cortex/reports/b.json", "docs/a.md"))
+	again, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(again), "the same generation writes the same bytes")
+
+	write("docs/a.md", "# a2\n")
+	assert.Equal(t, StatusModified, idx.Artifacts[1].Status(root))
+	require.NoError(t, os.Remove(filepath.Join(root, "docs", "a.md")))
+	assert.Equal(t, StatusMissing, idx.Artifacts[1].Status(root))
+
+	// Regenerating replaces the entry; others are kept.
+	write("docs/a.md", "# a3\n")
+	require.NoError(t, Generation{Command: "cortex other", Version: "1.2.3"}.Record(root, KindContext, "docs/a.md"))
+	idx, err = Read(root)
+	require.NoError(t, err)
+	require.Len(t, idx.Artifacts, 2)
+	assert.Equal(t, "cortex test", idx.Artifacts[0].Generator)
+	assert.Equal(t, "cortex other", idx.Artifacts[1].Generator)
+	assert.Equal(t, KindContext, idx.Artifacts[1].Kind)
+	assert.Equal(t, StatusOK, idx.Artifacts[1].Status(root))
+}
+
+func TestRecord_Concurrent(t *testing.T) {
+	root := t.TempDir()
+	var paths []string
+	for i := 0; i < 8; i++ {
+		rel := fmt.Sprintf("docs/%d.md", i)
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, filepath.FromSlash(rel)), []byte(rel), 0o644))
+		paths = append(paths, rel)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(paths))
+	for i, rel := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = Generation{Command: "cortex test", Version: "1.2.3"}.Record(root, KindReport, rel)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	idx, err := Read(root)
+	require.NoError(t, err)
+	assert.Len(t, idx.Artifacts, len(paths), "no generator drops the entries of another")
+	_, err = os.Stat(filepath.Join(root, filepath.FromSlash(lockFile)))
+	assert.True(t, os.IsNotExist(err), "the lock is removed")
+
+	// A lock left by a crashed generator is taken over once stale.
+	lock := filepath.Join(root, filepath.FromSlash(lockFile))
+	require.NoError(t, os.WriteFile(lock, nil, 0o644))
+	old := time.Now().Add(-2 * staleLock)
+	require.NoError(t, os.Chtimes(lock, old, old))
+	require.NoError(t, Generation{Command: "cortex test", Version: "1.2.3"}.Record(root, KindReport, paths[0]))
+}
+
+func TestBegin(t *testing.T) {
+	t.Setenv("CORTEX_VERSION", "9.9.9")
+	var gen Generation
+	cmd := &cobra.Command{Use: "docs", RunE: func(cmd *cobra.Command, args []string) error {
+		gen = Begin(cmd, args, t.TempDir())
+		return nil
+	}}
+	cmd.Flags().String("format", "markdown", "")
+	cmd.Flags().Bool("check", false, "")
+	parent := &cobra.Command{Use: "context"}
+	parent.PersistentFlags().String("engine", "xray", "")
+	parent.AddCommand(cmd)
+	root := &cobra.Command{Use: "cortex"}
+	root.PersistentFlags().Bool("verbose", false, "")
+	root.AddCommand(parent)
+	root.SetArgs([]string{"context", "docs", "--format", "html", "--engine", "go", "--verbose", "target"})
+	require.NoError(t, root.Execute())
+
+	assert.Equal(t, Generation{
+		Command: "cortex context docs",
+		Version: "9.9.9",
+		Inputs:  map[string]string{"format": "html", "engine": "go", "args": "target"},
+	}, gen, "unset and global flags are not inputs; no fingerprint outside git")
+}
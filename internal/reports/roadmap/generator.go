@@ -23,145 +23,52 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/bartekus/cortex/internal/templates"
 )
 
-// GenerateMarkdown generates a deterministic markdown document from statistics and blockers.
+// GenerateMarkdown generates a deterministic markdown document from statistics
+// and blockers with the embedded default template.
 func GenerateMarkdown(stats *Stats, blockers []*Blocker) string {
-	var b strings.Builder
-
-	// Top-level heading
-	b.WriteString("# Feature Completion Analysis\n\n")
-	b.WriteString("> **Source**: Generated from `spec/features.yaml` by `cortex status roadmap`\n")
-	b.WriteString("> **Last Updated**: See `spec/features.yaml` for the source of truth\n>\n")
-	b.WriteString("> **Note**: This document is automatically generated. To regenerate, run `cortex status roadmap`.\n\n")
-	b.WriteString("⸻\n\n")
-
-	// Executive Summary
-	b.WriteString("## Executive Summary\n\n")
-	fmt.Fprintf(&b, "- **Total Features**: %d\n", stats.Total)
-	if stats.Total > 0 {
-		fmt.Fprintf(&b, "- **Completed**: %d (%.1f%%)\n", stats.Done, float64(stats.Done)/float64(stats.Total)*100.0)
-		fmt.Fprintf(&b, "- **In Progress**: %d (%.1f%%)\n", stats.WIP, float64(stats.WIP)/float64(stats.Total)*100.0)
-		fmt.Fprintf(&b, "- **Planned**: %d (%.1f%%)\n", stats.Todo, float64(stats.Todo)/float64(stats.Total)*100.0)
-	} else {
-		b.WriteString("- **Completed**: 0\n")
-		b.WriteString("- **In Progress**: 0\n")
-		b.WriteString("- **Planned**: 0\n")
-	}
-
-	b.WriteString("\n⸻\n\n")
-
-	// Phase-by-Phase Completion
-	b.WriteString("## Phase-by-Phase Completion\n\n")
-	b.WriteString("| Phase | Features | Done | WIP | Todo | Completion | Status |\n")
-	b.WriteString("|-------|----------|------|-----|------|------------|--------|\n")
-
-	phaseNames := sortedPhaseNames(stats.PhaseStats)
-	for _, name := range phaseNames {
-		ps := stats.PhaseStats[name]
-		status := phaseStatusLabel(ps)
-		fmt.Fprintf(
-			&b,
-			"| **%s** | %d | %d | %d | %d | %.0f%% | %s |\n",
-			name,
-			ps.Total,
-			ps.Done,
-			ps.WIP,
-			ps.Todo,
-			ps.CompletionPercentage,
-			status,
-		)
-	}
+	return templates.MustRender(templates.Roadmap, newMarkdownView(stats, blockers))
+}
 
-	b.WriteString("\n⸻\n\n")
+// GenerateMarkdownWith generates the markdown document with the roadmap template
+// of set, which may be overridden by the repository.
+func GenerateMarkdownWith(set *templates.Set, stats *Stats, blockers []*Blocker) (string, error) {
+	return set.Render(templates.Roadmap, newMarkdownView(stats, blockers))
+}
 
-	// Roadmap Alignment
-	b.WriteString("## Roadmap Alignment\n\n")
-	b.WriteString("### Strong Progress\n\n")
-	// Identify phases with good progress
-	for _, name := range phaseNames {
-		ps := stats.PhaseStats[name]
-		if ps.CompletionPercentage >= 100.0 {
-			fmt.Fprintf(&b, "- ✅ **%s Complete**: All features done (%d/%d)\n", name, ps.Done, ps.Total)
-		} else if ps.CompletionPercentage >= 50.0 && ps.Done > 0 {
-			fmt.Fprintf(&b, "- 🔄 **%s In Progress**: %.1f%% complete (%d/%d done", name, ps.CompletionPercentage, ps.Done, ps.Total)
-			if ps.WIP > 0 {
-				fmt.Fprintf(&b, ", %d wip", ps.WIP)
-			}
-			b.WriteString(")\n")
-		}
-	}
+// markdownView is the data of the roadmap template.
+type markdownView struct {
+	Stats *Stats
+	// Phases are in roadmap order.
+	Phases     []phaseView
+	Milestones []milestoneView
+	Blockers   []*Blocker
+}
 
-	b.WriteString("\n### Critical Gaps\n\n")
-	// Identify phases with no progress
-	for _, name := range phaseNames {
-		ps := stats.PhaseStats[name]
-		if ps.CompletionPercentage == 0.0 && ps.Total > 0 {
-			fmt.Fprintf(&b, "- ⚠️ **%s**: 0%% complete — not started\n", name)
-		}
-	}
+type phaseView struct {
+	*PhaseStats
+	Name   string
+	Status string
+}
 
-	b.WriteString("\n⸻\n\n")
-
-	// Priority Recommendations
-	b.WriteString("## Priority Recommendations\n\n")
-	if stats.Total == 0 {
-		b.WriteString("No features are defined in `spec/features.yaml`.\n\n")
-	} else {
-		b.WriteString("### 🔥 Immediate (Unblocks Other Work)\n\n")
-		// List blockers that block other features
-		if len(blockers) > 0 {
-			for _, blk := range blockers {
-				fmt.Fprintf(&b, "1. Complete `%s` to unblock dependent features\n", blk.FeatureID)
-			}
-		} else {
-			b.WriteString("No immediate blockers detected.\n")
-		}
-		b.WriteString("\n")
-	}
+type milestoneView struct {
+	*MilestoneStats
+	Burndown []BurndownPoint
+}
 
-	// Detailed Phase Analysis
-	b.WriteString("## Detailed Phase Analysis\n\n")
-	for _, name := range phaseNames {
+func newMarkdownView(stats *Stats, blockers []*Blocker) markdownView {
+	v := markdownView{Stats: stats, Blockers: blockers}
+	for _, name := range sortedPhaseNames(stats.PhaseStats) {
 		ps := stats.PhaseStats[name]
-		fmt.Fprintf(&b, "### %s\n\n", name)
-
-		if ps.Total == 0 {
-			b.WriteString("No features defined for this phase.\n\n")
-			continue
-		}
-
-		fmt.Fprintf(
-			&b,
-			"- Features: %d (Done: %d, WIP: %d, Todo: %d)\n",
-			ps.Total,
-			ps.Done,
-			ps.WIP,
-			ps.Todo,
-		)
-		fmt.Fprintf(&b, "- Completion: %.1f%%\n\n", ps.CompletionPercentage)
+		v.Phases = append(v.Phases, phaseView{PhaseStats: ps, Name: name, Status: phaseStatusLabel(ps)})
 	}
-
-	// Critical Path Analysis
-	b.WriteString("⸻\n\n")
-	b.WriteString("## Critical Path Analysis\n\n")
-	if len(blockers) == 0 {
-		b.WriteString("No blocked features detected. All dependencies for non-done features are satisfied.\n\n")
-	} else {
-		b.WriteString("The following features are blocked by incomplete dependencies:\n\n")
-		for _, blk := range blockers {
-			fmt.Fprintf(&b, "- `%s` blocked by: %s\n", blk.FeatureID, strings.Join(blk.BlockedBy, ", "))
-		}
-		b.WriteString("\n")
+	for _, ms := range stats.Milestones {
+		v.Milestones = append(v.Milestones, milestoneView{MilestoneStats: ms, Burndown: burndown(stats, ms)})
 	}
-
-	// Next Steps
-	b.WriteString("## Next Steps\n\n")
-	b.WriteString("1. Use `cortex status roadmap` to regenerate this document whenever `spec/features.yaml` changes.\n")
-	b.WriteString("2. Prioritize unblocking critical-path features.\n")
-	b.WriteString("3. Complete partially implemented phases before starting new ones.\n")
-
-	return b.String()
+	return v
 }
 
 // sortedPhaseNames returns phase names in deterministic, roadmap-aligned order:
@@ -234,3 +234,58 @@ func diffStrings(want, got string) string {
 
 	return diff.String()
 }
+
+func TestGenerateMarkdown_MilestoneBurndown(t *testing.T) {
+	phases, err := DetectPhases(writeFeatures(t, milestonesYAML))
+	if err != nil {
+		t.Fatalf("DetectPhases() failed: %v", err)
+	}
+	stats := CalculateStats(phases)
+
+	markdown := GenerateMarkdown(stats, IdentifyBlockers(phases))
+	want := `## Milestone Burndown
+
+### v0.1 (target 2026-03-31)
+
+- Features: 3 (Done: 1, WIP: 1, Todo: 1)
+- Completion: 33.3%
+
+| Phase | Features | Done | Open | Remaining |
+|-------|----------|------|------|-----------|
+| Phase 0: Foundation | 1 | 1 | 0 | 2 |
+| Phase 1: Interfaces | 2 | 0 | 2 | 2 |
+
+### v0.2 (target 2026-06-30)
+
+- Features: 0 (Done: 0, WIP: 0, Todo: 0)
+- Completion: 0.0%
+
+| Phase | Features | Done | Open | Remaining |
+|-------|----------|------|------|-----------|
+| Phase 2: Orchestration | 0 | 0 | 0 | 0 |
+`
+	if !strings.Contains(markdown, want) {
+		t.Errorf("GenerateMarkdown() missing milestone burndown:\n%s", markdown)
+	}
+
+	report := BuildReport(stats, nil)
+	if len(report.Milestones) != 2 || report.Milestones[0].Name != "v0.1" {
+		t.Fatalf("BuildReport() milestones = %+v, want v0.1 then v0.2", report.Milestones)
+	}
+	if got := report.Milestones[0].Burndown; len(got) != 2 || got[1] != (BurndownPoint{Phase: "Phase 1: Interfaces", Total: 2, Open: 2, Remaining: 2}) {
+		t.Errorf("BuildReport() burndown = %+v", got)
+	}
+	if len(report.Phases) != 4 || report.Phases[0].Name != "Phase 0: Foundation" || report.Phases[0].Milestone != "v0.1" {
+		t.Errorf("BuildReport() phases = %+v", report.Phases)
+	}
+}
+
+func TestGenerateMarkdown_NoMilestonesSection(t *testing.T) {
+	phases, err := DetectPhases(filepath.Join(testDataDir(t), "features.yaml"))
+	if err != nil {
+		t.Fatalf("DetectPhases() failed: %v", err)
+	}
+	if markdown := GenerateMarkdown(CalculateStats(phases), nil); strings.Contains(markdown, "Milestone Burndown") {
+		t.Error("GenerateMarkdown() without milestones should not render a burndown")
+	}
+}
@@ -25,19 +25,34 @@ type Feature struct {
 	Implementation string   `yaml:"implementation"`
 	Spec           string   `yaml:"spec"`
 	Owner          string   `yaml:"owner"`
+	Phase          string   `yaml:"phase"`
 	DependsOn      []string `yaml:"depends_on"`
 	Tests          []string `yaml:"tests"`
 }
 
 // featureDocument matches the top-level shape of spec/features.yaml for YAML decoding.
 type featureDocument struct {
-	Features []Feature `yaml:"features"`
+	Features   []Feature   `yaml:"features"`
+	Milestones []Milestone `yaml:"milestones"`
+}
+
+// Milestone groups phases under a release with an optional target date
+// (YYYY-MM-DD), as declared in the milestones section of spec/features.yaml.
+type Milestone struct {
+	Name   string   `yaml:"name"`
+	Target string   `yaml:"target"`
+	Phases []string `yaml:"phases"`
 }
 
 // Phase groups features under a human-readable phase name.
 type Phase struct {
 	Name     string
 	Features []Feature
+	// Milestone and Target are set when a milestone lists the phase; Order
+	// is the position of the phase in that list.
+	Milestone string
+	Target    string
+	Order     int
 }
 
 // Stats represents overall and per-phase statistics.
@@ -48,6 +63,7 @@ type Stats struct {
 	Todo                 int
 	CompletionPercentage float64
 	PhaseStats           map[string]*PhaseStats
+	Milestones           []*MilestoneStats
 }
 
 // PhaseStats represents statistics for a single phase.
@@ -57,6 +73,20 @@ type PhaseStats struct {
 	WIP                  int
 	Todo                 int
 	CompletionPercentage float64
+	Milestone            string
+}
+
+// MilestoneStats represents statistics for a milestone, with its phases in
+// the order the milestone lists them.
+type MilestoneStats struct {
+	Name                 string
+	Target               string
+	Phases               []string
+	Total                int
+	Done                 int
+	WIP                  int
+	Todo                 int
+	CompletionPercentage float64
 }
 
 // Blocker represents a feature blocked by incomplete dependencies.
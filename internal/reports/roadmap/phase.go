@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -37,6 +38,10 @@ import (
 //   - If a feature appears before any phase comment, it is assigned to the
 //     "Uncategorized" phase.
 //   - Multiple phase comments before a feature: the last one wins.
+//   - A feature's own "phase:" key takes precedence over comments.
+//   - Phases listed by an entry of the top-level "milestones" section get
+//     that milestone and its target date, and exist even without features.
+//     A phase may belong to one milestone only, and targets are YYYY-MM-DD.
 //   - Invalid YAML must return an error.
 //   - A missing file must return an error.
 func DetectPhases(featuresPath string) (map[string]*Phase, error) {
@@ -90,8 +95,11 @@ func DetectPhases(featuresPath string) (map[string]*Phase, error) {
 
 	for i := range doc.Features {
 		f := &doc.Features[i]
-		phaseName, ok := featurePhase[f.ID]
-		if !ok || phaseName == "" {
+		phaseName := f.Phase
+		if phaseName == "" {
+			phaseName = featurePhase[f.ID]
+		}
+		if phaseName == "" {
 			phaseName = "Uncategorized"
 		}
 
@@ -104,5 +112,45 @@ func DetectPhases(featuresPath string) (map[string]*Phase, error) {
 		p.Features = append(p.Features, *f)
 	}
 
+	if err := assignMilestones(phases, doc.Milestones); err != nil {
+		return nil, err
+	}
 	return phases, nil
 }
+
+// assignMilestones records on each phase the milestone listing it.
+func assignMilestones(phases map[string]*Phase, milestones []Milestone) error {
+	seen := make(map[string]bool, len(milestones))
+	for _, m := range milestones {
+		if m.Name == "" {
+			return fmt.Errorf("roadmap: milestone without a name")
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("roadmap: milestone %q is declared twice", m.Name)
+		}
+		seen[m.Name] = true
+		if m.Target != "" {
+			if _, err := time.Parse(time.DateOnly, m.Target); err != nil {
+				return fmt.Errorf("roadmap: milestone %q: target %q is not a YYYY-MM-DD date", m.Name, m.Target)
+			}
+		}
+		if len(m.Phases) == 0 {
+			return fmt.Errorf("roadmap: milestone %q lists no phases", m.Name)
+		}
+
+		for i, name := range m.Phases {
+			p, exists := phases[name]
+			if !exists {
+				p = &Phase{Name: name}
+				phases[name] = p
+			}
+			if p.Milestone != "" {
+				return fmt.Errorf("roadmap: phase %q is listed by milestones %q and %q", name, p.Milestone, m.Name)
+			}
+			p.Milestone = m.Name
+			p.Target = m.Target
+			p.Order = i
+		}
+	}
+	return nil
+}
@@ -258,3 +258,109 @@ func TestDetectPhases_ReturnsErrorForMissingFile(t *testing.T) {
 		t.Error("DetectPhases() expected error for missing file, got nil")
 	}
 }
+
+const milestonesYAML = `features:
+  # Phase 0: Foundation
+  - id: CORE
+    title: "Core"
+    implementation: done
+  - id: CLI
+    title: "CLI"
+    implementation: wip
+    phase: "Phase 1: Interfaces"
+  - id: API
+    title: "API"
+    implementation: todo
+    phase: "Phase 1: Interfaces"
+  # Later
+  - id: DOCS
+    title: "Docs"
+    implementation: todo
+
+milestones:
+  - name: v0.2
+    target: 2026-06-30
+    phases: ["Phase 2: Orchestration"]
+  - name: v0.1
+    target: 2026-03-31
+    phases: ["Phase 0: Foundation", "Phase 1: Interfaces"]
+`
+
+func writeFeatures(t *testing.T, content string) string {
+	t.Helper()
+	featuresPath := filepath.Join(t.TempDir(), "features.yaml")
+	if err := os.WriteFile(featuresPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write test YAML: %v", err)
+	}
+	return featuresPath
+}
+
+func TestDetectPhases_PhaseKeyAndMilestones(t *testing.T) {
+	phases, err := DetectPhases(writeFeatures(t, milestonesYAML))
+	if err != nil {
+		t.Fatalf("DetectPhases() failed: %v", err)
+	}
+
+	tests := []struct {
+		phase     string
+		features  int
+		milestone string
+		target    string
+		order     int
+	}{
+		{"Phase 0: Foundation", 1, "v0.1", "2026-03-31", 0},
+		{"Phase 1: Interfaces", 2, "v0.1", "2026-03-31", 1},
+		{"Phase 2: Orchestration", 0, "v0.2", "2026-06-30", 0},
+		{"Later", 1, "", "", 0},
+	}
+	if len(phases) != len(tests) {
+		t.Errorf("DetectPhases() returned %d phases, want %d", len(phases), len(tests))
+	}
+	for _, tt := range tests {
+		p, ok := phases[tt.phase]
+		if !ok {
+			t.Errorf("phase %q not found", tt.phase)
+			continue
+		}
+		if len(p.Features) != tt.features || p.Milestone != tt.milestone || p.Target != tt.target || p.Order != tt.order {
+			t.Errorf("phase %q = %d features, milestone %q, target %q, order %d; want %d, %q, %q, %d",
+				tt.phase, len(p.Features), p.Milestone, p.Target, p.Order, tt.features, tt.milestone, tt.target, tt.order)
+		}
+	}
+}
+
+func TestDetectPhases_ReturnsErrorForInvalidMilestones(t *testing.T) {
+	tests := map[string]string{
+		"bad target": `
+milestones:
+  - name: v1
+    target: March
+    phases: [A]
+`,
+		"phase in two milestones": `
+milestones:
+  - name: v1
+    phases: [A]
+  - name: v2
+    phases: [A]
+`,
+		"duplicate name": `
+milestones:
+  - name: v1
+    phases: [A]
+  - name: v1
+    phases: [B]
+`,
+		"no phases": `
+milestones:
+  - name: v1
+`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DetectPhases(writeFeatures(t, "features: []\n"+content)); err == nil {
+				t.Error("DetectPhases() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: CLI_COMMAND_STATUS
+// Spec: spec/cli/status.md
+
+package roadmap
+
+// Report is the JSON form of the feature completion analysis.
+type Report struct {
+	Total      int               `json:"total"`
+	Done       int               `json:"done"`
+	WIP        int               `json:"wip"`
+	Todo       int               `json:"todo"`
+	Completion float64           `json:"completion"`
+	Phases     []PhaseReport     `json:"phases"`
+	Milestones []MilestoneReport `json:"milestones"`
+	Blockers   []BlockerReport   `json:"blockers"`
+}
+
+// PhaseReport is a phase in a Report. Milestone is empty when no milestone
+// lists the phase.
+type PhaseReport struct {
+	Name       string  `json:"name"`
+	Milestone  string  `json:"milestone,omitempty"`
+	Total      int     `json:"total"`
+	Done       int     `json:"done"`
+	WIP        int     `json:"wip"`
+	Todo       int     `json:"todo"`
+	Completion float64 `json:"completion"`
+}
+
+// MilestoneReport is a milestone in a Report, with its burndown: the
+// features still open from each of its phases on.
+type MilestoneReport struct {
+	Name       string          `json:"name"`
+	Target     string          `json:"target,omitempty"`
+	Total      int             `json:"total"`
+	Done       int             `json:"done"`
+	WIP        int             `json:"wip"`
+	Todo       int             `json:"todo"`
+	Completion float64         `json:"completion"`
+	Burndown   []BurndownPoint `json:"burndown"`
+}
+
+// BurndownPoint is a row of a milestone burndown table.
+type BurndownPoint struct {
+	Phase     string `json:"phase"`
+	Total     int    `json:"total"`
+	Done      int    `json:"done"`
+	Open      int    `json:"open"`
+	Remaining int    `json:"remaining"`
+}
+
+// BlockerReport is a blocked feature in a Report.
+type BlockerReport struct {
+	Feature   string   `json:"feature"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+// BuildReport returns the JSON form of the analysis GenerateMarkdown
+// renders, in the same order.
+func BuildReport(stats *Stats, blockers []*Blocker) *Report {
+	r := &Report{
+		Total:      stats.Total,
+		Done:       stats.Done,
+		WIP:        stats.WIP,
+		Todo:       stats.Todo,
+		Completion: stats.CompletionPercentage,
+		Phases:     []PhaseReport{},
+		Milestones: []MilestoneReport{},
+		Blockers:   []BlockerReport{},
+	}
+	for _, name := range sortedPhaseNames(stats.PhaseStats) {
+		ps := stats.PhaseStats[name]
+		r.Phases = append(r.Phases, PhaseReport{
+			Name:       name,
+			Milestone:  ps.Milestone,
+			Total:      ps.Total,
+			Done:       ps.Done,
+			WIP:        ps.WIP,
+			Todo:       ps.Todo,
+			Completion: ps.CompletionPercentage,
+		})
+	}
+	for _, ms := range stats.Milestones {
+		mr := MilestoneReport{
+			Name:       ms.Name,
+			Target:     ms.Target,
+			Total:      ms.Total,
+			Done:       ms.Done,
+			WIP:        ms.WIP,
+			Todo:       ms.Todo,
+			Completion: ms.CompletionPercentage,
+			Burndown:   burndown(stats, ms),
+		}
+		r.Milestones = append(r.Milestones, mr)
+	}
+	for _, blk := range blockers {
+		r.Blockers = append(r.Blockers, BlockerReport{Feature: blk.FeatureID, BlockedBy: blk.BlockedBy})
+	}
+	return r
+}
+
+// burndown returns, for each phase of ms in order, the features not done in
+// it and the features not done in it and the phases after it.
+func burndown(stats *Stats, ms *MilestoneStats) []BurndownPoint {
+	points := make([]BurndownPoint, 0, len(ms.Phases))
+	remaining := ms.Total - ms.Done
+	for _, name := range ms.Phases {
+		ps := stats.PhaseStats[name]
+		open := ps.Total - ps.Done
+		points = append(points, BurndownPoint{Phase: name, Total: ps.Total, Done: ps.Done, Open: open, Remaining: remaining})
+		remaining -= open
+	}
+	return points
+}
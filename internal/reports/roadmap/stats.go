@@ -25,8 +25,9 @@ func CalculateStats(phases map[string]*Phase) *Stats {
 		PhaseStats: make(map[string]*PhaseStats),
 	}
 
+	milestones := make(map[string]*MilestoneStats)
 	for phaseName, phase := range phases {
-		ps := &PhaseStats{}
+		ps := &PhaseStats{Milestone: phase.Milestone}
 
 		for i := range phase.Features {
 			f := &phase.Features[i]
@@ -52,12 +53,54 @@ func CalculateStats(phases map[string]*Phase) *Stats {
 		}
 
 		stats.PhaseStats[phaseName] = ps
+
+		if phase.Milestone == "" {
+			continue
+		}
+		ms, exists := milestones[phase.Milestone]
+		if !exists {
+			ms = &MilestoneStats{Name: phase.Milestone, Target: phase.Target}
+			milestones[phase.Milestone] = ms
+		}
+		ms.Total += ps.Total
+		ms.Done += ps.Done
+		ms.WIP += ps.WIP
+		ms.Todo += ps.Todo
 	}
 
 	if stats.Total > 0 {
 		stats.CompletionPercentage = float64(stats.Done) / float64(stats.Total) * 100.0
 	}
 
+	// Milestones are ordered by target date, undated ones last, with their
+	// phases in the order the milestone lists them.
+	for _, ms := range milestones {
+		if ms.Total > 0 {
+			ms.CompletionPercentage = float64(ms.Done) / float64(ms.Total) * 100.0
+		}
+		stats.Milestones = append(stats.Milestones, ms)
+	}
+	for name, phase := range phases {
+		if ms := milestones[phase.Milestone]; ms != nil {
+			ms.Phases = append(ms.Phases, name)
+		}
+	}
+	for _, ms := range stats.Milestones {
+		sort.Slice(ms.Phases, func(i, j int) bool {
+			return phases[ms.Phases[i]].Order < phases[ms.Phases[j]].Order
+		})
+	}
+	sort.Slice(stats.Milestones, func(i, j int) bool {
+		a, b := stats.Milestones[i], stats.Milestones[j]
+		if (a.Target == "") != (b.Target == "") {
+			return b.Target == ""
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Name < b.Name
+	})
+
 	return stats
 }
 
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package runsummary renders the last skill run as a compact Markdown pull
+// request comment: a status table, findings that are new against a baseline,
+// the coverage delta and links to the run artifacts. The comment starts with
+// Marker so CI bots can find and update it instead of adding one per push.
+//
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+package runsummary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/reports/prsummary"
+	"github.com/bartekus/cortex/internal/rules"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+// Marker is the first line of the summary, an HTML comment that identifies
+// the comment to update.
+const Marker = "<!-- cortex:run-summary -->"
+
+// MaxFindings bounds the findings listed; the rest are only counted.
+const MaxFindings = 50
+
+// Input is everything the summary is rendered from.
+type Input struct {
+	// Last is nil when there is no run state.
+	Last *runner.LastRun
+	// Skills are the results of the last run, in run order.
+	Skills   []runner.SkillResult
+	Findings []findings.Finding
+	// Baseline holds the code quality issues of the base, as printed by
+	// `run report --format codeclimate`; nil without a baseline.
+	Baseline []findings.CodeClimateIssue
+	// Coverage and BaseCoverage are nil when no profile is available.
+	Coverage     prsummary.Coverage
+	BaseCoverage prsummary.Coverage
+	// Artifacts are the repo-relative paths of the run artifacts.
+	Artifacts []string
+	// ArtifactsURL is prefixed to artifact paths to link them; empty
+	// lists them without links.
+	ArtifactsURL string
+}
+
+// Render returns the summary rendered with the embedded default template.
+func Render(in Input) string {
+	return templates.MustRender(templates.RunSummary, newView(in))
+}
+
+// RenderWith returns the summary rendered with the run-summary template of
+// set, which may be overridden by the repository.
+func RenderWith(set *templates.Set, in Input) (string, error) {
+	return set.Render(templates.RunSummary, newView(in))
+}
+
+// view is the data of the run-summary template.
+type view struct {
+	Marker string
+	// Status is empty when there is no run state.
+	Status                         string
+	Passed, Failed, Skipped, Flaky int
+	Skills                         []skillRow
+	// HasBaseline reports whether Findings are the new findings only.
+	HasBaseline bool
+	// Total counts the findings to report (the new ones with a baseline);
+	// Findings lists at most MaxFindings of them, Omitted the rest.
+	Total    int
+	Findings []findingRow
+	Omitted  int
+	// Fixed and Kept count the baseline findings gone and still present.
+	Fixed, Kept int
+	// Coverage is nil when no coverage profile is available.
+	Coverage  *coverageRow
+	Artifacts []artifactRow
+}
+
+type skillRow struct {
+	Skill, Status string
+	// Note is the first line of the note of a skill that did not pass.
+	Note string
+}
+
+// findingRow is a listed finding; Rule is its rule ID (cortex rules list)
+// and Skill the skill that reported it.
+type findingRow struct {
+	Rule, Skill, Location, Message string
+}
+
+// coverageRow holds percentages formatted with one decimal, "n/a" when a
+// profile is missing.
+type coverageRow struct {
+	Base, Head, Delta string
+}
+
+type artifactRow struct {
+	// URL is empty without an artifacts URL.
+	Path, URL string
+}
+
+func newView(in Input) view {
+	v := view{Marker: Marker}
+	if in.Last != nil {
+		v.Status = in.Last.Status
+		v.Flaky = len(in.Last.Flaky)
+	}
+	for _, r := range in.Skills {
+		row := skillRow{Skill: r.Skill, Status: string(r.Status)}
+		switch {
+		case r.Status == runner.StatusPass:
+			v.Passed++
+		case r.Status == runner.StatusSkip:
+			v.Skipped++
+		case r.Status.Failed():
+			v.Failed++
+		}
+		if r.Status != runner.StatusPass {
+			row.Note = firstLine(r.Note)
+		}
+		v.Skills = append(v.Skills, row)
+	}
+
+	issues := findings.CodeClimate(in.Findings)
+	listed := issues
+	if in.Baseline != nil {
+		v.HasBaseline = true
+		base := map[string]bool{}
+		for _, b := range in.Baseline {
+			base[b.Fingerprint] = true
+		}
+		current := map[string]bool{}
+		listed = nil
+		for _, is := range issues {
+			current[is.Fingerprint] = true
+			if base[is.Fingerprint] {
+				v.Kept++
+				continue
+			}
+			listed = append(listed, is)
+		}
+		for fp := range base {
+			if !current[fp] {
+				v.Fixed++
+			}
+		}
+	}
+	v.Total = len(listed)
+	for i, is := range listed {
+		if i == MaxFindings {
+			v.Omitted = v.Total - MaxFindings
+			break
+		}
+		loc := is.Location.Path
+		if loc != "." {
+			loc = fmt.Sprintf("%s:%d", loc, is.Location.Lines.Begin)
+		}
+		skill := is.CheckName
+		if r, ok := rules.Lookup(is.CheckName); ok {
+			skill = r.Skill
+		}
+		v.Findings = append(v.Findings, findingRow{Rule: is.CheckName, Skill: skill, Location: loc, Message: is.Description})
+	}
+
+	if in.Coverage != nil || in.BaseCoverage != nil {
+		v.Coverage = coverage(in.Coverage, in.BaseCoverage)
+	}
+	for _, p := range in.Artifacts {
+		row := artifactRow{Path: p}
+		if in.ArtifactsURL != "" {
+			row.URL = strings.TrimSuffix(in.ArtifactsURL, "/") + "/" + p
+		}
+		v.Artifacts = append(v.Artifacts, row)
+	}
+	return v
+}
+
+func coverage(head, base prsummary.Coverage) *coverageRow {
+	r := &coverageRow{Base: "n/a", Head: "n/a", Delta: "n/a"}
+	h, headOK := head.Percent(nil)
+	b, baseOK := base.Percent(nil)
+	if headOK {
+		r.Head = fmt.Sprintf("%.1f%%", h)
+	}
+	if baseOK {
+		r.Base = fmt.Sprintf("%.1f%%", b)
+	}
+	if headOK && baseOK {
+		r.Delta = fmt.Sprintf("%+.1f", h-b)
+	}
+	return r
+}
+
+// firstLine returns the first non-empty line of s.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
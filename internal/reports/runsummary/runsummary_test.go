@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package runsummary
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/reports/prsummary"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+func TestRender(t *testing.T) {
+	known := findings.Finding{Skill: "test:go", Path: "a.go", Line: 3, Message: "old"}
+	gone := findings.Finding{Skill: "test:go", Path: "b.go", Line: 1, Message: "fixed"}
+	in := Input{
+		Last: &runner.LastRun{Status: "fail", Skills: []string{"docs:yaml", "test:go", "lint:golangci"}, Failed: []string{"test:go"}, Flaky: []string{"docs:yaml"}},
+		Skills: []runner.SkillResult{
+			{Skill: "docs:yaml", Status: runner.StatusPass, Note: "ok"},
+			{Skill: "test:go", Status: runner.StatusFail, Note: "a.go:3: old\nc.go:7: new | pipe"},
+			{Skill: "lint:golangci", Status: runner.StatusSkip, Note: "golangci-lint not found"},
+		},
+		Findings: []findings.Finding{
+			known,
+			{Skill: "test:go", Path: "c.go", Line: 7, Message: "new | pipe"},
+		},
+		Baseline:     findings.CodeClimate([]findings.Finding{known, gone}),
+		Coverage:     prsummary.Coverage{"a.go": {Statements: 10, Covered: 8}},
+		BaseCoverage: prsummary.Coverage{"a.go": {Statements: 10, Covered: 7}},
+		Artifacts:    []string{".cortex/run/last-run.json"},
+		ArtifactsURL: "https://ci.example.com/artifacts/",
+	}
+
+	want := Marker + "\n" +
+		"## Cortex Run: fail\n\n" +
+		"1 passed, 1 failed, 1 skipped, 1 flaky.\n\n" +
+		"| Skill | Status | Note |\n| --- | --- | --- |\n" +
+		"| `docs:yaml` | pass |  |\n" +
+		"| `test:go` | fail | a.go:3: old |\n" +
+		"| `lint:golangci` | skip | golangci-lint not found |\n\n" +
+		"### New Findings\n\n" +
+		"1 new, 1 fixed, 1 unchanged against the baseline.\n\n" +
+		"| Rule | Location | Message |\n| --- | --- | --- |\n" +
+		"| `test:go` | `c.go:7` | new \\| pipe |\n\n" +
+		"### Coverage\n\n" +
+		"| Base | Head | Delta |\n| --- | --- | --- |\n" +
+		"| 70.0% | 80.0% | +10.0 |\n\n" +
+		"### Artifacts\n\n" +
+		"- [`.cortex/run/last-run.json`](https://ci.example.com/artifacts/.cortex/run/last-run.json)\n"
+	if got := Render(in); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRender_NoBaseline(t *testing.T) {
+	in := Input{Last: &runner.LastRun{Status: "fail"}}
+	for i := range MaxFindings + 2 {
+		in.Findings = append(in.Findings, findings.Finding{Skill: "s", Path: "f.go", Line: i + 1, Message: "m"})
+	}
+	got := Render(in)
+	for _, want := range []string{
+		"### Findings\n\n52 finding(s); no baseline given.\n",
+		"| `s` | `f.go:50` | m |\n\n…and 2 more.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "f.go:51") || strings.Contains(got, "### Coverage") || strings.Contains(got, "### Artifacts") {
+		t.Errorf("Render() lists too much:\n%s", got)
+	}
+}
+
+func TestRender_NoRunState(t *testing.T) {
+	want := fmt.Sprintf("%s\n## Cortex Run\n\nNo run state found; run `cortex run all` first.\n", Marker)
+	if got := Render(Input{}); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package signing signs generated reports and context bundles with Ed25519
+// and verifies them, so consumers such as release gates can detect changes
+// made after generation. Signatures are detached, cosign-style: the
+// base64-encoded signature of the file's bytes in a "<file>.sig" sidecar.
+// Keys are PEM: PKCS #8 private keys and PKIX public keys, as written by
+// `openssl genpkey -algorithm ed25519`.
+//
+// Feature: REPORTS_SIGNING
+// Spec: spec/reports/signing.md
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SigSuffix is appended to a file's path to name its signature.
+const SigSuffix = ".sig"
+
+// ErrBadSignature marks a file whose signature is missing or does not
+// verify.
+var ErrBadSignature = errors.New("signature does not verify")
+
+// ParsePrivateKey decodes a PEM-encoded PKCS #8 Ed25519 private key.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("no PEM \"PRIVATE KEY\" block (encrypted keys are not supported)")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, not Ed25519", key)
+	}
+	return priv, nil
+}
+
+// ParsePublicKey decodes a PEM-encoded PKIX Ed25519 public key. A private
+// key is accepted too and yields its public half.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	switch {
+	case block == nil:
+		return nil, fmt.Errorf("no PEM \"PUBLIC KEY\" block")
+	case block.Type == "PRIVATE KEY":
+		priv, err := ParsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return priv.Public().(ed25519.PublicKey), nil
+	case block.Type != "PUBLIC KEY":
+		return nil, fmt.Errorf("PEM block is %q, not \"PUBLIC KEY\"", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is %T, not Ed25519", key)
+	}
+	return pub, nil
+}
+
+// KeyID identifies a public key: "sha256:<hex>" of its PKIX DER encoding,
+// the digest of `openssl pkey -pubin -outform DER | sha256sum`.
+func KeyID(pub ed25519.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(pub) // fails for other key types only
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Sign returns the content of the signature file of data: its base64
+// Ed25519 signature and a newline.
+func Sign(key ed25519.PrivateKey, data []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n")
+}
+
+// Verify checks data against the content of its signature file. A
+// signature that does not verify wraps ErrBadSignature.
+func Verify(pub ed25519.PublicKey, data, sig []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed signature", ErrBadSignature)
+	}
+	if !ed25519.Verify(pub, data, raw) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// Files returns the files under dir that are signed, sorted: every regular
+// file but signatures.
+func Files(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && !strings.HasSuffix(p, SigSuffix) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package retrieve ranks the chunks of the context against a natural
+// language question without calling a model: Okapi BM25 over the terms of
+// each chunk's content, file path and symbol, optionally fused with the
+// similarity ranking of the vector index.
+//
+// Identifiers are split into their words ("ExitValidation" yields
+// "exitvalidation", "exit" and "validation"), so questions in prose match
+// code. Ranking is deterministic: the same chunks and question give the
+// same hits in the same order.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package retrieve
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bartekus/cortex/internal/chunker"
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+	// fieldBoost weighs the terms of the file path and symbol of a chunk
+	// against those of its content.
+	fieldBoost = 3
+	// rrfK damps the ranks of reciprocal rank fusion.
+	rrfK = 60
+)
+
+// Hit is a ranked chunk.
+type Hit struct {
+	ID        string `json:"id"`
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Symbol    string `json:"symbol,omitempty"`
+	// Score is the BM25 score, or the fused score with Fuse.
+	Score float64 `json:"score"`
+	// Line is the first line of the chunk with a query term, 0 without one;
+	// Preview is its text.
+	Line    int    `json:"line,omitempty"`
+	Preview string `json:"preview,omitempty"`
+	Content string `json:"content"`
+}
+
+// Index is a BM25 index of chunks.
+type Index struct {
+	chunks []chunker.Chunk
+	// freqs[i] holds the term frequencies of chunk i, lengths[i] its
+	// weighted length.
+	freqs   []map[string]int
+	lengths []int
+	df      map[string]int
+	avgLen  float64
+}
+
+// NewIndex indexes chunks.
+func NewIndex(chunks []chunker.Chunk) *Index {
+	ix := &Index{chunks: chunks, df: make(map[string]int)}
+	total := 0
+	for _, c := range chunks {
+		freq := make(map[string]int)
+		n := 0
+		for _, t := range Terms(c.Content) {
+			freq[t]++
+			n++
+		}
+		for _, t := range Terms(c.FilePath + " " + c.Symbol) {
+			freq[t] += fieldBoost
+			n += fieldBoost
+		}
+		for t := range freq {
+			ix.df[t]++
+		}
+		ix.freqs = append(ix.freqs, freq)
+		ix.lengths = append(ix.lengths, n)
+		total += n
+	}
+	if len(chunks) > 0 {
+		ix.avgLen = float64(total) / float64(len(chunks))
+	}
+	return ix
+}
+
+// Query returns the k chunks (0: all) that score highest for question, most
+// relevant first; ties are broken by file path, then start line. Chunks
+// without a query term are left out.
+func (ix *Index) Query(question string, k int) []Hit {
+	terms := unique(Terms(question))
+	n := float64(len(ix.chunks))
+	var hits []Hit
+	for i, c := range ix.chunks {
+		score := 0.0
+		for _, t := range terms {
+			f := float64(ix.freqs[i][t])
+			if f == 0 {
+				continue
+			}
+			df := float64(ix.df[t])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * f * (k1 + 1) / (f + k1*(1-b+b*float64(ix.lengths[i])/ix.avgLen))
+		}
+		if score > 0 {
+			hits = append(hits, ix.hit(c, score, terms))
+		}
+	}
+	sortHits(hits)
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// Fuse merges rankings by reciprocal rank fusion: a chunk scores the sum
+// of 1/(60+rank) over the rankings it appears in. Rankings hold chunk IDs,
+// best first; chunks the index does not hold are ignored. It returns the
+// top k (0: all).
+func (ix *Index) Fuse(question string, k int, rankings ...[]string) []Hit {
+	scores := make(map[string]float64)
+	for _, ranking := range rankings {
+		for rank, id := range ranking {
+			scores[id] += 1 / float64(rrfK+rank+1)
+		}
+	}
+	terms := unique(Terms(question))
+	var hits []Hit
+	for _, c := range ix.chunks {
+		if s, ok := scores[c.ID]; ok {
+			hits = append(hits, ix.hit(c, s, terms))
+		}
+	}
+	sortHits(hits)
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// IDs returns the chunk IDs of hits in order.
+func IDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func (ix *Index) hit(c chunker.Chunk, score float64, terms []string) Hit {
+	h := Hit{
+		ID:        c.ID,
+		FilePath:  c.FilePath,
+		StartLine: c.StartLine,
+		EndLine:   c.EndLine,
+		Symbol:    c.Symbol,
+		Score:     score,
+		Content:   c.Content,
+	}
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+	for i, line := range strings.Split(c.Content, "\n") {
+		for _, t := range Terms(line) {
+			if want[t] {
+				h.Line, h.Preview = c.StartLine+i, strings.TrimSpace(line)
+				return h
+			}
+		}
+	}
+	return h
+}
+
+func sortHits(hits []Hit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].FilePath != hits[j].FilePath {
+			return hits[i].FilePath < hits[j].FilePath
+		}
+		return hits[i].StartLine < hits[j].StartLine
+	})
+}
+
+// stopwords are English words too common to rank by.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "do": true, "does": true, "for": true, "from": true, "how": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true, "what": true, "when": true,
+	"where": true, "which": true, "who": true, "why": true, "with": true,
+}
+
+// Terms returns the lower-case terms of text in order: each word and, for
+// words that mix case, letters and digits or join parts with "_", their
+// parts ("parseHTTPHeader" yields "parsehttpheader", "parse", "http" and
+// "header"). Stop words and single characters are dropped.
+func Terms(text string) []string {
+	var out []string
+	add := func(t string) {
+		t = strings.ToLower(t)
+		if len(t) > 1 && !stopwords[t] {
+			out = append(out, t)
+		}
+	}
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		parts := split(word)
+		if len(parts) > 1 {
+			add(word)
+		}
+		for _, p := range parts {
+			add(p)
+		}
+	}
+	return out
+}
+
+// split cuts an identifier at "_" and at changes between lower case, upper
+// case and digits; an upper-case run before a lower-case letter ends one
+// letter early ("HTTPHeader" is "HTTP" and "Header").
+func split(word string) []string {
+	var parts []string
+	for _, seg := range strings.Split(word, "_") {
+		rs := []rune(seg)
+		start := 0
+		for i := 1; i < len(rs); i++ {
+			prev, cur := rs[i-1], rs[i]
+			boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+				unicode.IsDigit(prev) != unicode.IsDigit(cur) ||
+				unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if boundary {
+				parts = append(parts, string(rs[start:i]))
+				start = i
+			}
+		}
+		if start < len(rs) {
+			parts = append(parts, string(rs[start:]))
+		}
+	}
+	return parts
+}
+
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	var out []string
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,64 @@
+package retrieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/chunker"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"parsehttpheader", "parse", "http", "header"}, Terms("parseHTTPHeader"))
+	assert.Equal(t, []string{"exit_code", "exit", "code", "sha256", "sha", "256"}, Terms("exit_code sha256"))
+	assert.Equal(t, []string{"exit", "codes", "mapped"}, Terms("How are exit codes mapped?"))
+	assert.Empty(t, Terms("a b c"))
+}
+
+func chunks() []chunker.Chunk {
+	return []chunker.Chunk{
+		{ID: "1", FilePath: "clierr/codes.go", StartLine: 1, EndLine: 3, Symbol: "ExitCode",
+			Content: "// ExitCode maps errors to exit codes.\nfunc ExitCode(err error) int {\n}"},
+		{ID: "2", FilePath: "config/config.go", StartLine: 10, EndLine: 12, Symbol: "Load",
+			Content: "// Load reads the configuration.\nfunc Load() {}\n"},
+		{ID: "3", FilePath: "docs/exit.md", StartLine: 1, EndLine: 2,
+			Content: "# Exit\n\nThe exit code is 2 for configuration errors."},
+	}
+}
+
+func TestQuery(t *testing.T) {
+	ix := NewIndex(chunks())
+
+	hits := ix.Query("how are exit codes mapped?", 0)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "1", hits[0].ID)
+	assert.Equal(t, 1, hits[0].Line)
+	assert.Equal(t, "// ExitCode maps errors to exit codes.", hits[0].Preview)
+	assert.Equal(t, "3", hits[1].ID)
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+
+	assert.Len(t, ix.Query("exit", 1), 1)
+	assert.Empty(t, ix.Query("unrelated words", 0))
+	assert.Equal(t, hits, ix.Query("how are exit codes mapped?", 0))
+}
+
+func TestQuery_Ties(t *testing.T) {
+	ix := NewIndex([]chunker.Chunk{
+		{ID: "b", FilePath: "b.txt", StartLine: 1, EndLine: 1, Content: "alpha"},
+		{ID: "a", FilePath: "a.txt", StartLine: 5, EndLine: 5, Content: "alpha"},
+		{ID: "c", FilePath: "a.txt", StartLine: 1, EndLine: 1, Content: "alpha"},
+	})
+	assert.Equal(t, []string{"c", "a", "b"}, IDs(ix.Query("alpha", 0)))
+}
+
+func TestFuse(t *testing.T) {
+	ix := NewIndex(chunks())
+
+	hits := ix.Fuse("configuration", 0, []string{"3", "2"}, []string{"2", "1", "unknown"})
+	assert.Equal(t, []string{"2", "3", "1"}, IDs(hits))
+	assert.InDelta(t, 1.0/62+1.0/61, hits[0].Score, 1e-12)
+	assert.Equal(t, 10, hits[0].Line)
+
+	assert.Len(t, ix.Fuse("configuration", 1, []string{"3", "2"}), 1)
+}
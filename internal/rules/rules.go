@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package rules is the registry of the checks skills report findings for:
+// for each rule its ID, owning skill, description, default severity and
+// whether a command fixes it, with why it exists, the spec section it
+// enforces, compliant examples and how to fix a violation. The registry is
+// embedded in the binary, so findings can be explained without network
+// access or a checkout of Cortex.
+//
+// Every skill that can fail on findings has a rule with the skill's ID.
+// Rules "<skill>/<check>" narrow it to the findings whose message matches
+// their pattern; Classify picks the rule of a finding.
+//
+// Feature: CLI_COMMAND_RULES
+// Spec: spec/cli/rules.md
+package rules
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+//go:embed rules.yaml
+var rulesYAML []byte
+
+// Severities of a rule.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rule describes one check.
+type Rule struct {
+	ID string `yaml:"id" json:"id"`
+	// Skill is the skill reporting the rule's findings: the ID up to "/".
+	Skill       string `yaml:"-" json:"skill"`
+	Description string `yaml:"description" json:"description"`
+	// Severity is the default severity, SeverityError or SeverityWarning.
+	Severity string `yaml:"severity" json:"severity"`
+	// Fixable reports whether Fix is a command that resolves violations
+	// by itself.
+	Fixable   bool   `yaml:"fixable" json:"fixable"`
+	Rationale string `yaml:"rationale" json:"rationale"`
+	// Spec is the repo-relative spec the rule enforces; Section its heading.
+	Spec     string   `yaml:"spec" json:"spec"`
+	Section  string   `yaml:"section" json:"section"`
+	Examples []string `yaml:"examples,omitempty" json:"examples,omitempty"`
+	// Fix is a command or instruction that resolves a violation; empty when
+	// there is none.
+	Fix string `yaml:"fix,omitempty" json:"fix,omitempty"`
+	// Match is the pattern of the messages of the rule's findings; empty
+	// for the rule of a skill, which takes the findings no other rule of
+	// the skill matches.
+	Match string `yaml:"match,omitempty" json:"match,omitempty"`
+
+	match *regexp.Regexp
+}
+
+var all = mustParse(rulesYAML)
+
+func mustParse(data []byte) []Rule {
+	var doc struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		panic(fmt.Sprintf("rules: parsing embedded rules.yaml: %v", err))
+	}
+	rs := doc.Rules
+	sort.Slice(rs, func(i, j int) bool { return rs[i].ID < rs[j].ID })
+	skills := map[string]Rule{}
+	for i := range rs {
+		r := &rs[i]
+		r.Skill, _, _ = strings.Cut(r.ID, "/")
+		if r.Severity == "" {
+			r.Severity = SeverityError
+		}
+		if r.Match != "" {
+			r.match = regexp.MustCompile(r.Match)
+		}
+		// The rule of a skill sorts before the rules narrowing it.
+		if r.ID == r.Skill {
+			skills[r.Skill] = *r
+			continue
+		}
+		parent := skills[r.Skill]
+		if r.Rationale == "" {
+			r.Rationale = parent.Rationale
+		}
+		if r.Spec == "" {
+			r.Spec, r.Section = parent.Spec, parent.Section
+		}
+		if r.Fix == "" {
+			r.Fix = parent.Fix
+		}
+	}
+	return rs
+}
+
+// All returns every rule, sorted by ID.
+func All() []Rule {
+	return append([]Rule(nil), all...)
+}
+
+// Lookup returns the rule with the given ID. A module variant of a skill
+// ("test:go@services/api") resolves to the rule of the skill.
+func Lookup(id string) (Rule, bool) {
+	id = runner.BaseSkillID(id)
+	i := sort.Search(len(all), func(i int) bool { return all[i].ID >= id })
+	if i < len(all) && all[i].ID == id {
+		return all[i], true
+	}
+	return Rule{}, false
+}
+
+// Classify returns the ID of the rule a finding of skill with message
+// violates: the first rule of the skill, by ID, whose pattern matches the
+// message, else the rule of the skill. A skill without rules is its own
+// rule ID.
+func Classify(skill, message string) string {
+	skill = runner.BaseSkillID(skill)
+	for _, r := range all {
+		if r.Skill == skill && r.match != nil && r.match.MatchString(message) {
+			return r.ID
+		}
+	}
+	return skill
+}
@@ -0,0 +1,87 @@
+package rules_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/rules"
+	"github.com/bartekus/cortex/internal/skills"
+)
+
+// Feature: CLI_COMMAND_RULES
+// Spec: spec/cli/rules.md
+
+func TestEverySkillHasARule(t *testing.T) {
+	for _, s := range skills.Registry {
+		if _, ok := s.(*skills.PlaceholderSkill); ok {
+			continue
+		}
+		_, ok := rules.Lookup(s.ID())
+		assert.True(t, ok, "no rule for skill %s in rules.yaml", s.ID())
+	}
+}
+
+func TestRules(t *testing.T) {
+	all := rules.All()
+	require.NotEmpty(t, all)
+	for i, r := range all {
+		if i > 0 {
+			assert.Less(t, all[i-1].ID, r.ID, "rules are unique and sorted")
+		}
+		assert.NotEmpty(t, r.Description, r.ID)
+		assert.NotEmpty(t, r.Rationale, r.ID)
+		assert.Contains(t, []string{rules.SeverityError, rules.SeverityWarning}, r.Severity, r.ID)
+		if r.ID == r.Skill {
+			assert.Empty(t, r.Match, "%s: the rule of a skill takes every other finding", r.ID)
+		} else {
+			assert.NotEmpty(t, r.Match, r.ID)
+			_, ok := rules.Lookup(r.Skill)
+			assert.True(t, ok, "%s: no rule for its skill", r.ID)
+		}
+		if r.Fixable {
+			assert.NotEmpty(t, r.Fix, r.ID)
+		}
+		spec, err := os.ReadFile(filepath.Join("..", "..", filepath.FromSlash(r.Spec)))
+		if assert.NoError(t, err, r.ID) {
+			assert.True(t, strings.Contains(string(spec), "\n## "+r.Section+"\n"), "%s: no section %q in %s", r.ID, r.Section, r.Spec)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	r, ok := rules.Lookup("test:go@services/api")
+	require.True(t, ok)
+	assert.Equal(t, "test:go", r.ID)
+	assert.Equal(t, "test:go", r.Skill)
+	_, ok = rules.Lookup("nope")
+	assert.False(t, ok)
+
+	// Narrower rules inherit what they leave out.
+	r, ok = rules.Lookup("docs:doc-patterns/filename")
+	require.True(t, ok)
+	assert.Equal(t, "docs:doc-patterns", r.Skill)
+	parent, _ := rules.Lookup("docs:doc-patterns")
+	assert.Equal(t, parent.Fix, r.Fix)
+	assert.Equal(t, parent.Section, r.Section)
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct{ skill, message, want string }{
+		{"docs:doc-patterns", "invalid filename (must match [A-Za-z0-9-_]+\\.md)", "docs:doc-patterns/filename"},
+		{"docs:header-comments", "missing '// Package <name>' comment before 'package' declaration", "docs:header-comments/package-comment"},
+		{"docs:header-comments", "unexpected EOF before package declaration", "docs:header-comments"},
+		{"deps:policy", "github.com/x/y v1.0.0: license GPL-3.0 is not allowed (allowed: MIT)", "deps:policy/license"},
+		{"deps:policy", "github.com/x/y v1.0.0: license not detected", "deps:policy/license-unknown"},
+		{"env:windows-paths", "hard-coded /tmp; use os.TempDir or t.TempDir", "env:windows-paths/tmp"},
+		{"test:go@services/api", "FAIL x", "test:go"},
+		{"custom:skill", "anything", "custom:skill"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rules.Classify(tt.skill, tt.message), tt.message)
+	}
+}
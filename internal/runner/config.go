@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// ApplyConfig sets the retry, timeout, limit, sandbox and environment
+// policies of the skills section of .cortex/config.yaml. Skills without a
+// timeout entry get DefaultTimeout. Invalid values return an error naming
+// the offending key and leave r unchanged.
+func (r *Runner) ApplyConfig(cfg config.Skills) error {
+	retry := make(map[string]RetryPolicy, len(cfg.Retry))
+	for id, p := range cfg.Retry {
+		if p.Count < 0 || p.Backoff < 0 {
+			return fmt.Errorf("skills.retry.%s: count and backoff must not be negative", id)
+		}
+		retry[id] = RetryPolicy{Count: p.Count, Backoff: p.Backoff}
+	}
+
+	timeout := map[string]time.Duration{"*": DefaultTimeout}
+	for id, d := range cfg.Timeout {
+		if d < 0 {
+			return fmt.Errorf("skills.timeout.%s: must not be negative", id)
+		}
+		timeout[id] = d
+	}
+
+	limits := make(map[string]ExecLimits, len(cfg.Limits))
+	for id, l := range cfg.Limits {
+		if l.GOMAXPROCS < 0 || l.Parallel < 0 || l.MemoryMB < 0 || l.CPUSeconds < 0 || l.Nice < 0 || l.Nice > 19 {
+			return fmt.Errorf("skills.limits.%s: limits must not be negative and nice must be 0-19", id)
+		}
+		limits[id] = ExecLimits{
+			GOMAXPROCS: l.GOMAXPROCS,
+			GOFLAGS:    l.GOFLAGS,
+			Parallel:   l.Parallel,
+			Nice:       l.Nice,
+			MemoryMB:   l.MemoryMB,
+			CPUSeconds: l.CPUSeconds,
+		}
+	}
+
+	sandbox := make(map[string]ExecSandbox, len(cfg.Sandbox))
+	for id, sb := range cfg.Sandbox {
+		switch sb.Mode {
+		case "", SandboxOff, SandboxAuto, SandboxBwrap:
+		case SandboxDocker:
+			if sb.Image == "" {
+				return fmt.Errorf("skills.sandbox.%s: docker mode needs an image", id)
+			}
+		default:
+			return fmt.Errorf("skills.sandbox.%s: mode must be off, auto, bwrap or docker", id)
+		}
+		for _, w := range sb.Writable {
+			if scanner.Outside(w) {
+				return fmt.Errorf("skills.sandbox.%s: writable path %q must be inside the repository", id, w)
+			}
+		}
+		sandbox[id] = ExecSandbox{Mode: sb.Mode, Network: sb.Network, Env: sb.Env, Writable: sb.Writable, Image: sb.Image}
+	}
+
+	env := make(map[string]EnvPolicy, len(cfg.Env))
+	for id, e := range cfg.Env {
+		env[id] = EnvPolicy{Inherit: e.Inherit, Passthrough: e.Passthrough}
+	}
+
+	r.SetRetry(retry)
+	r.SetTimeout(timeout)
+	r.SetLimits(limits)
+	r.SetSandbox(sandbox)
+	r.SetEnv(env)
+	return nil
+}
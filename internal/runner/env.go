@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// baseEnv are the variables every child process keeps.
+var baseEnv = []string{"GOCACHE", "HOME", "PATH"}
+
+// windowsEnv are the variables programs need to start on Windows.
+var windowsEnv = []string{"APPDATA", "COMSPEC", "LOCALAPPDATA", "PATHEXT", "SYSTEMROOT", "TEMP", "TMP", "USERPROFILE"}
+
+// EnvPolicy decides the environment of the processes a skill starts. The
+// zero value keeps only the base variables (PATH, HOME, GOCACHE), so runs
+// do not depend on whatever else the caller's shell exports.
+type EnvPolicy struct {
+	// Inherit passes the whole environment unchanged.
+	Inherit bool
+	// Passthrough lists further variables to keep; a trailing "*" keeps
+	// every variable with that prefix ("GO*").
+	Passthrough []string
+}
+
+// String describes the policy for logs, e.g. "inherit" or
+// "base+CI,GOFLAGS".
+func (p EnvPolicy) String() string {
+	if p.Inherit {
+		return "inherit"
+	}
+	if len(p.Passthrough) == 0 {
+		return "base"
+	}
+	return "base+" + strings.Join(p.Passthrough, ",")
+}
+
+// Filter returns the entries of base the policy keeps, in base order.
+func (p EnvPolicy) Filter(base []string) []string {
+	if p.Inherit {
+		return append([]string(nil), base...)
+	}
+	names := append(append([]string(nil), baseEnv...), p.Passthrough...)
+	if runtime.GOOS == "windows" {
+		names = append(names, windowsEnv...)
+	}
+	var env []string
+	for _, kv := range base {
+		k, _, _ := strings.Cut(kv, "=")
+		if runtime.GOOS == "windows" {
+			k = strings.ToUpper(k)
+		}
+		for _, n := range names {
+			if prefix, ok := strings.CutSuffix(n, "*"); ok && strings.HasPrefix(k, prefix) || k == n {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	return env
+}
+
+// EnvHash returns the first 12 hex digits of a SHA-256 over the sorted
+// entries of env; equal environments hash equal whatever their order.
+func EnvHash(env []string) string {
+	sorted := append([]string(nil), env...)
+	sort.Strings(sorted)
+	h := sha256.New()
+	for _, kv := range sorted {
+		_, _ = h.Write([]byte(kv))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Environ returns the environment of the processes the skill starts: the
+// process environment filtered by d.Env, with d.Limits applied.
+func (d *Deps) Environ() []string {
+	return d.Limits.Env(d.Env.Filter(os.Environ()))
+}
+
+// SetEnv configures the environment policy per skill ID for the processes
+// skills start through Deps.Command. The "*" entry applies to skills
+// without their own entry.
+func (r *Runner) SetEnv(env map[string]EnvPolicy) {
+	r.env = env
+}
+
+// envFor returns the policy of skill id; the variables its sandbox passes
+// are passed through as well.
+func (r *Runner) envFor(id string) EnvPolicy {
+	p := lookup(r.env, id)
+	if extra := r.sandboxFor(id).Env; len(extra) > 0 {
+		p.Passthrough = append(append([]string(nil), p.Passthrough...), extra...)
+	}
+	return p
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/config"
+)
+
+func TestEnvPolicy_Filter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows keeps further variables")
+	}
+	base := []string{"PATH=/bin", "HOME=/home/u", "AWS_SECRET_ACCESS_KEY=x", "GOFLAGS=-mod=mod", "GOPROXY=off", "CI=true", "GOCACHE=/c"}
+
+	assert.Equal(t, []string{"PATH=/bin", "HOME=/home/u", "GOCACHE=/c"}, EnvPolicy{}.Filter(base))
+	assert.Equal(t, []string{"PATH=/bin", "HOME=/home/u", "GOFLAGS=-mod=mod", "GOPROXY=off", "CI=true", "GOCACHE=/c"},
+		EnvPolicy{Passthrough: []string{"GO*", "CI"}}.Filter(base))
+	assert.Equal(t, base, EnvPolicy{Inherit: true}.Filter(base))
+
+	assert.Equal(t, "base", EnvPolicy{}.String())
+	assert.Equal(t, "base+GO*,CI", EnvPolicy{Passthrough: []string{"GO*", "CI"}}.String())
+	assert.Equal(t, "inherit", EnvPolicy{Inherit: true}.String())
+}
+
+func TestEnvHash(t *testing.T) {
+	a := EnvHash([]string{"PATH=/bin", "HOME=/h"})
+	assert.Len(t, a, 12)
+	assert.Equal(t, a, EnvHash([]string{"HOME=/h", "PATH=/bin"}), "order does not matter")
+	assert.NotEqual(t, a, EnvHash([]string{"PATH=/usr/bin", "HOME=/h"}))
+}
+
+func TestDepsCommand_ScrubsEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("CORTEX_TEST_SECRET", "s3cret")
+	t.Setenv("CORTEX_TEST_PASS", "kept")
+
+	deps := &Deps{Env: EnvPolicy{Passthrough: []string{"CORTEX_TEST_PASS"}}, Limits: ExecLimits{GOMAXPROCS: 2}}
+	out, err := deps.Command(context.Background(), "sh", "-c", `echo "[$CORTEX_TEST_SECRET][$CORTEX_TEST_PASS][$GOMAXPROCS]"`).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "[][kept][2]\n", string(out), "limits apply on top of the scrubbed environment")
+}
+
+func TestRunner_EnvHash(t *testing.T) {
+	t.Setenv("CORTEX_TEST_PASS", "one")
+	store := NewStateStore(t.TempDir())
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusPass}}
+	r := NewRunner([]Skill{s1}, store, &Deps{Commit: "abc123"})
+	require.NoError(t, r.ApplyConfig(config.Skills{Env: map[string]config.SkillEnv{"s1": {Passthrough: []string{"CORTEX_TEST_PASS"}}}}))
+
+	require.NoError(t, r.RunAll(context.Background()))
+	t.Setenv("CORTEX_TEST_UNRELATED", "ignored")
+	require.NoError(t, r.RunAll(context.Background()))
+	runs, err := store.ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, 1, "variables outside the policy do not change the run")
+
+	t.Setenv("CORTEX_TEST_PASS", "two")
+	require.NoError(t, r.RunAll(context.Background()))
+	runs, err = store.ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, 2, "a passed-through variable is part of the run ID")
+	assert.NotEqual(t, runs[0].Results[0].EnvHash, runs[1].Results[0].EnvHash)
+}
@@ -0,0 +1,191 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ExecLimits bounds the resources of the processes a skill starts.
+// Zero fields impose no limit.
+type ExecLimits struct {
+	// GOMAXPROCS is exported to child processes.
+	GOMAXPROCS int
+	// GOFLAGS is appended to the inherited GOFLAGS.
+	GOFLAGS string
+	// Parallel adds -p=N to GOFLAGS, capping packages built or tested at once.
+	Parallel int
+	// Nice lowers the scheduling priority of the child (0-19, Unix only).
+	Nice int
+	// MemoryMB sets GOMEMLIMIT and, on Linux, caps the address space.
+	MemoryMB int
+	// CPUSeconds caps CPU time (Linux only); the child is killed beyond it.
+	CPUSeconds int
+}
+
+// IsZero reports whether no limit is set.
+func (l ExecLimits) IsZero() bool {
+	return l == ExecLimits{}
+}
+
+// String describes the limits for reports, e.g. "GOMAXPROCS=2 -p=2 nice=10".
+func (l ExecLimits) String() string {
+	var parts []string
+	if l.GOMAXPROCS > 0 {
+		parts = append(parts, fmt.Sprintf("GOMAXPROCS=%d", l.GOMAXPROCS))
+	}
+	if l.GOFLAGS != "" {
+		parts = append(parts, fmt.Sprintf("GOFLAGS=%q", l.GOFLAGS))
+	}
+	if l.Parallel > 0 {
+		parts = append(parts, fmt.Sprintf("-p=%d", l.Parallel))
+	}
+	if l.Nice > 0 {
+		parts = append(parts, fmt.Sprintf("nice=%d", l.Nice))
+	}
+	if l.MemoryMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory=%dMiB", l.MemoryMB))
+	}
+	if l.CPUSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("cpu=%ds", l.CPUSeconds))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Env returns base with the limit variables set.
+func (l ExecLimits) Env(base []string) []string {
+	env := append([]string(nil), base...)
+	set := func(key, value string) {
+		for i, kv := range env {
+			if strings.HasPrefix(kv, key+"=") {
+				env[i] = key + "=" + value
+				return
+			}
+		}
+		env = append(env, key+"="+value)
+	}
+	lookup := func(key string) string {
+		for _, kv := range env {
+			if v, ok := strings.CutPrefix(kv, key+"="); ok {
+				return v
+			}
+		}
+		return ""
+	}
+
+	if l.GOMAXPROCS > 0 {
+		set("GOMAXPROCS", strconv.Itoa(l.GOMAXPROCS))
+	}
+	flags := strings.Fields(lookup("GOFLAGS"))
+	flags = append(flags, strings.Fields(l.GOFLAGS)...)
+	if l.Parallel > 0 {
+		flags = append(flags, "-p="+strconv.Itoa(l.Parallel))
+	}
+	if len(flags) > 0 {
+		set("GOFLAGS", strings.Join(flags, " "))
+	}
+	if l.MemoryMB > 0 {
+		set("GOMEMLIMIT", strconv.Itoa(l.MemoryMB)+"MiB")
+	}
+	return env
+}
+
+// Cmd is a child process of a skill, started with the skill's ExecLimits
+// in its ExecSandbox.
+type Cmd struct {
+	*exec.Cmd
+	limits   ExecLimits
+	sandbox  ExecSandbox
+	repoRoot string
+	stateDir string
+	log      *slog.Logger
+}
+
+// Command prepares name to run with the running skill's environment
+// policy, limits and sandbox, like exec.CommandContext. Use its Output or CombinedOutput (not
+// the embedded exec.Cmd's) so the sandbox and OS-level limits are applied
+// when the process starts.
+func (d *Deps) Command(ctx context.Context, name string, args ...string) *Cmd {
+	d.Logger().Debug("exec", "name", name, "args", args, "env", d.Env.String(), "limits", d.Limits.String(), "sandbox", d.Sandbox.String())
+	c := exec.CommandContext(ctx, name, args...)
+	c.Env = d.Environ()
+	return &Cmd{Cmd: c, limits: d.Limits, sandbox: d.Sandbox, repoRoot: d.RepoRoot, stateDir: d.StateDir, log: d.Logger()}
+}
+
+// Output runs the command and returns its standard output. Standard error
+// is kept in the *exec.ExitError like exec.Cmd.Output does.
+func (c *Cmd) Output() ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+	err := c.run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its standard output and
+// standard error interleaved.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	var out bytes.Buffer
+	c.Stdout, c.Stderr = &out, &out
+	err := c.run()
+	return out.Bytes(), err
+}
+
+func (c *Cmd) run() error {
+	if err := c.sandboxed(); err != nil {
+		return err
+	}
+	if err := c.Start(); err != nil {
+		return err
+	}
+	if err := applyOSLimits(c.Process.Pid, c.limits); err != nil {
+		_ = c.Process.Kill()
+		_ = c.Wait()
+		return fmt.Errorf("applying resource limits to %s: %w", c.Path, err)
+	}
+	return c.Wait()
+}
+
+// SetLimits configures resource limits per skill ID for the processes
+// skills start through Deps.Command. The "*" entry applies to skills
+// without their own entry.
+func (r *Runner) SetLimits(limits map[string]ExecLimits) {
+	r.limits = limits
+}
+
+func (r *Runner) limitsFor(id string) ExecLimits {
+	return lookup(r.limits, id)
+}
+
+// SetSandbox configures the sandbox per skill ID for the processes skills
+// start through Deps.Command. The "*" entry applies to skills without
+// their own entry.
+func (r *Runner) SetSandbox(sandbox map[string]ExecSandbox) {
+	r.sandbox = sandbox
+}
+
+func (r *Runner) sandboxFor(id string) ExecSandbox {
+	return lookup(r.sandbox, id)
+}
+
+// skillDeps returns the dependencies for one skill: the shared Deps with
+// that skill's limits.
+func (r *Runner) skillDeps(id string) *Deps {
+	var deps Deps
+	if r.deps != nil {
+		deps = *r.deps
+	}
+	deps.Limits = r.limitsFor(id)
+	deps.Sandbox = r.sandboxFor(id)
+	deps.Env = r.envFor(id)
+	deps.Log = r.logger().With("skill", id)
+	return &deps
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package runner
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type rlimit64 struct {
+	Cur, Max uint64
+}
+
+// applyOSLimits lowers the priority of pid and sets its address space and
+// CPU time limits. Processes it starts afterwards inherit them.
+func applyOSLimits(pid int, l ExecLimits) error {
+	if l.Nice > 0 {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, pid, l.Nice); err != nil {
+			return err
+		}
+	}
+	if l.MemoryMB > 0 {
+		if err := prlimit(pid, syscall.RLIMIT_AS, uint64(l.MemoryMB)<<20); err != nil {
+			return err
+		}
+	}
+	if l.CPUSeconds > 0 {
+		if err := prlimit(pid, syscall.RLIMIT_CPU, uint64(l.CPUSeconds)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prlimit(pid, resource int, limit uint64) error {
+	lim := rlimit64{Cur: limit, Max: limit}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&lim)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package runner
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandAppliesOSLimits(t *testing.T) {
+	if _, err := os.Stat("/proc/self/limits"); err != nil {
+		t.Skip("no /proc")
+	}
+	deps := &Deps{Limits: ExecLimits{Nice: 7, CPUSeconds: 30}}
+
+	// The sleep lets the limits land before the shell execs into cat.
+	out, err := deps.Command(context.Background(), "sh", "-c", "sleep 0.2; exec cat /proc/self/stat /proc/self/limits").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	stat, limits, _ := strings.Cut(string(out), "\n")
+	// Field 19 of /proc/<pid>/stat is the nice value; the command name in
+	// field 2 cannot contain spaces here.
+	assert.Equal(t, "7", strings.Fields(stat)[18])
+	assert.Regexp(t, `Max cpu time\s+30\s+30\s+seconds`, limits)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package runner
+
+// applyOSLimits is a no-op where the OS offers no per-process hooks; only
+// the environment limits apply.
+func applyOSLimits(pid int, l ExecLimits) error {
+	return nil
+}
@@ -0,0 +1,33 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecLimitsEnv(t *testing.T) {
+	l := ExecLimits{GOMAXPROCS: 2, GOFLAGS: "-mod=mod", Parallel: 3, MemoryMB: 512}
+	env := l.Env([]string{"PATH=/bin", "GOFLAGS=-trimpath", "GOMAXPROCS=8"})
+
+	assert.Equal(t, []string{
+		"PATH=/bin",
+		"GOFLAGS=-trimpath -mod=mod -p=3",
+		"GOMAXPROCS=2",
+		"GOMEMLIMIT=512MiB",
+	}, env)
+	assert.Equal(t, `GOMAXPROCS=2 GOFLAGS="-mod=mod" -p=3 memory=512MiB`, l.String())
+
+	assert.Equal(t, []string{"PATH=/bin"}, ExecLimits{}.Env([]string{"PATH=/bin"}))
+	assert.True(t, ExecLimits{}.IsZero())
+	assert.Empty(t, ExecLimits{}.String())
+}
+
+func TestRunner_SkillDepsLimits(t *testing.T) {
+	r := NewRunner(nil, NewStateStore(t.TempDir()), &Deps{RepoRoot: "/repo"})
+	r.SetLimits(map[string]ExecLimits{"*": {Nice: 5}, "test:go": {Parallel: 1}})
+
+	assert.Equal(t, ExecLimits{Parallel: 1}, r.skillDeps("test:go").Limits)
+	assert.Equal(t, ExecLimits{Nice: 5}, r.skillDeps("purity").Limits)
+	assert.Equal(t, "/repo", r.skillDeps("purity").RepoRoot)
+}
@@ -0,0 +1,14 @@
+//go:build unix && !linux
+
+package runner
+
+import "syscall"
+
+// applyOSLimits lowers the priority of pid. Address space and CPU limits
+// cannot be set on another process here; GOMEMLIMIT still applies.
+func applyOSLimits(pid int, l ExecLimits) error {
+	if l.Nice > 0 {
+		return syscall.Setpriority(syscall.PRIO_PROCESS, pid, l.Nice)
+	}
+	return nil
+}
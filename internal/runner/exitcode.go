@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/bartekus/cortex/internal/i18n"
+)
+
+// Exit codes reported by skills in SkillResult.ExitCode and by the CLI.
+// The documented mapping lives in cmd/cortex/internal/clierr; these values
+// are defined here so skills can use them without depending on the CLI.
+const (
+	// ExitOK means the skill passed (or was skipped).
+	ExitOK = 0
+	// ExitValidation means the check ran and found violations.
+	ExitValidation = 1
+	// ExitConfig means invalid usage, configuration or a missing prerequisite
+	// tool prevented the check from running.
+	ExitConfig = 2
+	// ExitWarning means only warnings were found and --fail-on-warning
+	// promoted them to a failure.
+	ExitWarning = 3
+	// ExitExecution means the check could not complete: I/O, subprocess or
+	// environment errors.
+	ExitExecution = 4
+)
+
+// RunError is returned when one or more skills in a run failed.
+type RunError struct {
+	Failed []string
+	code   int
+}
+
+func (e *RunError) Error() string { return fmt.Sprintf("run failed: %v", e.Failed) }
+
+// Localize implements i18n.Localizer.
+func (e *RunError) Localize() string { return i18n.Sprintf("run failed: %v", e.Failed) }
+
+// ExitCode is the highest exit code among the failed skills, so execution
+// errors outrank warnings and warnings outrank violations.
+func (e *RunError) ExitCode() int { return e.code }
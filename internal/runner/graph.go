@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlowNode is a skill of the run flow with its outcome in the last run.
+type FlowNode struct {
+	ID string
+	// Status is empty when the skill was not part of the last run.
+	Status SkillStatus
+	// Reason is the first line of the note of a skipped or failed skill.
+	Reason string
+}
+
+// maxReason bounds the reason shown in graph labels.
+const maxReason = 60
+
+// Flow returns the skills in the order `run all` executes them, with the
+// status of each in the last run recorded in store. Skills declare no
+// dependencies, so the order is the only edge between them.
+func Flow(skillIDs []string, store *StateStore) ([]FlowNode, error) {
+	last, err := store.ReadLastRun()
+	if err != nil {
+		return nil, err
+	}
+	inLast := map[string]bool{}
+	if last != nil {
+		for _, id := range last.Skills {
+			inLast[id] = true
+		}
+	}
+
+	nodes := make([]FlowNode, 0, len(skillIDs))
+	for _, id := range skillIDs {
+		n := FlowNode{ID: id}
+		if inLast[id] {
+			res, err := store.ReadSkill(id)
+			if err != nil {
+				return nil, err
+			}
+			if res != nil {
+				n.Status = res.Status
+				if res.Status != StatusPass {
+					n.Reason = reason(res.Note)
+				}
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// reason returns the first non-empty line of note, truncated to maxReason
+// runes.
+func reason(note string) string {
+	for _, line := range strings.Split(note, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if r := []rune(line); len(r) > maxReason {
+			line = string(r[:maxReason-1]) + "…"
+		}
+		return line
+	}
+	return ""
+}
+
+// FlowDOT renders the run flow as a Graphviz digraph. Nodes are colored by
+// status and the module variants of a skill are clustered.
+func FlowDOT(nodes []FlowNode) string {
+	var sb strings.Builder
+	sb.WriteString("digraph skill_flow {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=filled];\n\n")
+
+	for _, group := range moduleGroups(nodes) {
+		indent := "  "
+		if len(group) > 1 {
+			base := BaseSkillID(group[0].ID)
+			sb.WriteString(fmt.Sprintf("  subgraph %q {\n    label=%q;\n", "cluster_"+base, base))
+			indent = "    "
+		}
+		for _, n := range group {
+			label := n.ID + "\\n[" + statusLabel(n.Status) + "]"
+			if n.Reason != "" {
+				label += "\\n" + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(n.Reason)
+			}
+			sb.WriteString(fmt.Sprintf("%s%q [label=\"%s\", fillcolor=%s];\n", indent, n.ID, label, flowColor(n.Status)))
+		}
+		if len(group) > 1 {
+			sb.WriteString("  }\n")
+		}
+	}
+
+	if len(nodes) > 1 {
+		sb.WriteString("\n")
+	}
+	for i := 1; i < len(nodes); i++ {
+		sb.WriteString(fmt.Sprintf("  %q -> %q;\n", nodes[i-1].ID, nodes[i].ID))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// FlowMermaid renders the run flow as a Mermaid flowchart with the colors
+// of FlowDOT, one class per status.
+func FlowMermaid(nodes []FlowNode) string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	ids := make(map[string]string, len(nodes))
+	for i, n := range nodes {
+		ids[n.ID] = fmt.Sprintf("s%d", i)
+	}
+	for _, group := range moduleGroups(nodes) {
+		indent := "  "
+		if len(group) > 1 {
+			base := BaseSkillID(group[0].ID)
+			sb.WriteString(fmt.Sprintf("  subgraph %s_modules [\"%s\"]\n", ids[group[0].ID], base))
+			indent = "    "
+		}
+		for _, n := range group {
+			label := n.ID + "<br/>" + statusLabel(n.Status)
+			if n.Reason != "" {
+				label += "<br/>" + n.Reason
+			}
+			sb.WriteString(fmt.Sprintf("%s%s[\"%s\"]\n", indent, ids[n.ID], strings.ReplaceAll(label, `"`, "#quot;")))
+		}
+		if len(group) > 1 {
+			sb.WriteString("  end\n")
+		}
+	}
+
+	for i := 1; i < len(nodes); i++ {
+		sb.WriteString(fmt.Sprintf("  %s --> %s\n", ids[nodes[i-1].ID], ids[nodes[i].ID]))
+	}
+
+	classes := map[string][]string{}
+	for _, n := range nodes {
+		s := statusLabel(n.Status)
+		classes[s] = append(classes[s], ids[n.ID])
+	}
+	for _, s := range flowStatuses {
+		if _, ok := classes[s]; !ok {
+			continue
+		}
+		class := "status_" + strings.ReplaceAll(s, " ", "_")
+		sb.WriteString(fmt.Sprintf("  classDef %s fill:%s\n", class, flowColor(SkillStatus(s))))
+		sb.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(classes[s], ","), class))
+	}
+	return sb.String()
+}
+
+// flowStatuses orders the status classes of FlowMermaid.
+var flowStatuses = []string{string(StatusPass), string(StatusFail), string(StatusTimeout), string(StatusSkip), notRun}
+
+const notRun = "not run"
+
+func statusLabel(s SkillStatus) string {
+	if s == "" {
+		return notRun
+	}
+	return string(s)
+}
+
+func flowColor(s SkillStatus) string {
+	switch s {
+	case StatusPass:
+		return "lightgreen"
+	case StatusFail:
+		return "lightcoral"
+	case StatusTimeout:
+		return "orange"
+	case StatusSkip:
+		return "lightyellow"
+	default:
+		return "white"
+	}
+}
+
+// moduleGroups splits nodes into runs of consecutive skills sharing a base
+// ID, i.e. the module variants of one skill.
+func moduleGroups(nodes []FlowNode) [][]FlowNode {
+	var groups [][]FlowNode
+	for i, n := range nodes {
+		if i > 0 && BaseSkillID(n.ID) == BaseSkillID(nodes[i-1].ID) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], n)
+			continue
+		}
+		groups = append(groups, []FlowNode{n})
+	}
+	return groups
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlow(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "docs:yaml", Status: StatusPass}))
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "test:go", Status: StatusFail, Note: "\nFAIL pkg/a\nmore"}))
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "lint:golangci", Status: StatusSkip, Note: "golangci-lint not found"}))
+	// Not part of the last run, so its stale result is ignored.
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "test:go@api", Status: StatusFail}))
+	require.NoError(t, store.WriteLastRun(LastRun{Status: "fail", Skills: []string{"docs:yaml", "test:go", "lint:golangci"}, Failed: []string{"test:go"}}))
+
+	nodes, err := Flow([]string{"docs:yaml", "test:go", "test:go@api", "lint:golangci"}, store)
+	require.NoError(t, err)
+	assert.Equal(t, []FlowNode{
+		{ID: "docs:yaml", Status: StatusPass},
+		{ID: "test:go", Status: StatusFail, Reason: "FAIL pkg/a"},
+		{ID: "test:go@api"},
+		{ID: "lint:golangci", Status: StatusSkip, Reason: "golangci-lint not found"},
+	}, nodes)
+
+	assert.Equal(t, `digraph skill_flow {
+  rankdir=LR;
+  node [shape=box, style=filled];
+
+  "docs:yaml" [label="docs:yaml\n[pass]", fillcolor=lightgreen];
+  subgraph "cluster_test:go" {
+    label="test:go";
+    "test:go" [label="test:go\n[fail]\nFAIL pkg/a", fillcolor=lightcoral];
+    "test:go@api" [label="test:go@api\n[not run]", fillcolor=white];
+  }
+  "lint:golangci" [label="lint:golangci\n[skip]\ngolangci-lint not found", fillcolor=lightyellow];
+
+  "docs:yaml" -> "test:go";
+  "test:go" -> "test:go@api";
+  "test:go@api" -> "lint:golangci";
+}
+`, FlowDOT(nodes))
+
+	assert.Equal(t, `graph LR
+  s0["docs:yaml<br/>pass"]
+  subgraph s1_modules ["test:go"]
+    s1["test:go<br/>fail<br/>FAIL pkg/a"]
+    s2["test:go@api<br/>not run"]
+  end
+  s3["lint:golangci<br/>skip<br/>golangci-lint not found"]
+  s0 --> s1
+  s1 --> s2
+  s2 --> s3
+  classDef status_pass fill:lightgreen
+  class s0 status_pass
+  classDef status_fail fill:lightcoral
+  class s1 status_fail
+  classDef status_skip fill:lightyellow
+  class s3 status_skip
+  classDef status_not_run fill:white
+  class s2 status_not_run
+`, FlowMermaid(nodes))
+}
+
+func TestFlow_NoRunState(t *testing.T) {
+	nodes, err := Flow([]string{"a"}, NewStateStore(t.TempDir()))
+	require.NoError(t, err)
+	assert.Equal(t, []FlowNode{{ID: "a"}}, nodes)
+	assert.Contains(t, FlowDOT(nodes), `"a" [label="a\n[not run]", fillcolor=white];`)
+}
+
+func TestReason_Truncates(t *testing.T) {
+	r := []rune(reason("  " + strings.Repeat("x", 80)))
+	assert.Len(t, r, maxReason)
+	assert.Equal(t, '…', r[maxReason-1])
+}
@@ -0,0 +1,282 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+// HistoryLimit is the number of runs kept under <state-dir>/history; older
+// runs are pruned when a new one is written.
+const HistoryLimit = 50
+
+// RunRecord is one retained run, stored as <state-dir>/history/<id>.json.
+type RunRecord struct {
+	SchemaVersion string `json:"schema_version"`
+
+	ID          string        `json:"id"`
+	Commit      string        `json:"commit,omitempty"`
+	Fingerprint string        `json:"fingerprint,omitempty"`
+	CreatedAt   string        `json:"created_at"` // RFC 3339 with nanoseconds, UTC
+	Status      string        `json:"status"`     // "pass" or "fail"
+	Results     []SkillResult `json:"results"`
+}
+
+// NewRunRecord builds the record of a run at commit, in the worktree state
+// with the given fingerprint digest. Its ID is derived from the commit, the
+// fingerprint and the results only, so re-running the same skills on the
+// same worktree with the same outcome yields the same ID.
+func NewRunRecord(commit, fingerprint string, results []SkillResult, at time.Time) RunRecord {
+	rec := RunRecord{
+		ID:          RunID(commit, fingerprint, results),
+		Commit:      commit,
+		Fingerprint: fingerprint,
+		CreatedAt:   at.UTC().Format(time.RFC3339Nano),
+		Status:      "pass",
+		Results:     make([]SkillResult, len(results)),
+	}
+	for i, res := range results {
+		res.SchemaVersion = StateSchemaVersion
+		rec.Results[i] = res
+		if res.Status.Failed() {
+			rec.Status = "fail"
+		}
+	}
+	return rec
+}
+
+// RunID is the content hash of commit, fingerprint and results: the first
+// 12 hex digits of a SHA-256 over the commit, the fingerprint (when set) and
+// each result's skill, status, exit code and note (plus a flaky marker and
+// the environment hash when set), in run order.
+func RunID(commit, fingerprint string, results []SkillResult) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n", commit)
+	if fingerprint != "" {
+		_, _ = fmt.Fprintf(h, "fingerprint %s\n", fingerprint)
+	}
+	for _, r := range results {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00%d\x00%s", r.Skill, r.Status, r.ExitCode, r.Note)
+		if r.Flaky {
+			_, _ = fmt.Fprint(h, "\x00flaky")
+		}
+		if r.EnvHash != "" {
+			_, _ = fmt.Fprint(h, "\x00env "+r.EnvHash)
+		}
+		_, _ = fmt.Fprintln(h)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func (s *StateStore) historyDir() string {
+	return filepath.Join(s.baseDir, "history")
+}
+
+// WriteRun saves rec in the history and prunes runs beyond HistoryLimit.
+func (s *StateStore) WriteRun(rec RunRecord) error {
+	rec.SchemaVersion = StateSchemaVersion
+	if err := writeJSON(filepath.Join(s.historyDir(), rec.ID+".json"), rec); err != nil {
+		return err
+	}
+	return s.pruneHistory()
+}
+
+func (s *StateStore) pruneHistory() error {
+	runs, err := s.ListRuns()
+	if err != nil {
+		return err
+	}
+	for _, rec := range runs[min(len(runs), HistoryLimit):] {
+		if err := os.Remove(filepath.Join(s.historyDir(), rec.ID+".json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRuns returns the retained runs, newest first (ties by ID).
+func (s *StateStore) ListRuns() ([]RunRecord, error) {
+	entries, err := os.ReadDir(s.historyDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading run history: %w", err)
+	}
+
+	var runs []RunRecord
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		rec, err := s.readRun(id)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *rec)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].CreatedAt != runs[j].CreatedAt {
+			return runs[i].CreatedAt > runs[j].CreatedAt
+		}
+		return runs[i].ID < runs[j].ID
+	})
+	return runs, nil
+}
+
+// ReadRun loads the run whose ID is id or starts with it. A prefix matching
+// several runs is an error.
+func (s *StateStore) ReadRun(id string) (*RunRecord, error) {
+	if id == "" {
+		return nil, fmt.Errorf("empty run ID")
+	}
+	entries, err := os.ReadDir(s.historyDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading run history: %w", err)
+	}
+
+	var matches []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !e.IsDir() && ok && strings.HasPrefix(name, id) {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("run %q not found in history", id)
+	case 1:
+		return s.readRun(matches[0])
+	default:
+		return nil, fmt.Errorf("run ID %q is ambiguous: %s", id, strings.Join(matches, ", "))
+	}
+}
+
+func (s *StateStore) readRun(id string) (*RunRecord, error) {
+	data, err := os.ReadFile(filepath.Join(s.historyDir(), id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading run %s: %w", id, err)
+	}
+	var rec RunRecord
+	if err := schemas.Decode(schemas.RunRecord, data, &rec); err != nil {
+		return nil, fmt.Errorf("decoding run %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// Kinds of SkillChange.
+const (
+	ChangeRegressed = "regressed" // now failing, was not (or did not run)
+	ChangeFixed     = "fixed"     // was failing, now passing or skipped
+	ChangeAdded     = "added"     // only in the newer run, not failing
+	ChangeRemoved   = "removed"   // only in the older run
+	ChangeChanged   = "changed"   // any other change, e.g. pass <-> skip or fail <-> timeout
+)
+
+// SkillChange is a skill whose status differs between two runs. From or To
+// is empty when the skill did not run on that side.
+type SkillChange struct {
+	Skill string      `json:"skill"`
+	Kind  string      `json:"kind"`
+	From  SkillStatus `json:"from,omitempty"`
+	To    SkillStatus `json:"to,omitempty"`
+}
+
+// DiffRuns compares two runs skill by skill and returns the changes sorted
+// by skill ID. Skills with the same status in both runs are omitted.
+func DiffRuns(from, to RunRecord) []SkillChange {
+	before := map[string]SkillStatus{}
+	for _, r := range from.Results {
+		before[r.Skill] = r.Status
+	}
+	after := map[string]SkillStatus{}
+	for _, r := range to.Results {
+		after[r.Skill] = r.Status
+	}
+
+	var changes []SkillChange
+	add := func(skill string) {
+		a, b := before[skill], after[skill]
+		if a == b {
+			return
+		}
+		c := SkillChange{Skill: skill, From: a, To: b}
+		switch {
+		case b.Failed():
+			c.Kind = ChangeRegressed
+		case b == "":
+			c.Kind = ChangeRemoved
+		case a == "":
+			c.Kind = ChangeAdded
+		case a.Failed():
+			c.Kind = ChangeFixed
+		default:
+			c.Kind = ChangeChanged
+		}
+		changes = append(changes, c)
+	}
+	for skill := range before {
+		add(skill)
+	}
+	for skill := range after {
+		if _, ok := before[skill]; !ok {
+			add(skill)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Skill < changes[j].Skill })
+	return changes
+}
+
+// FlakyScore summarises how often a skill needed a retry to pass.
+type FlakyScore struct {
+	Skill string `json:"skill"`
+	// Runs counts the runs in which the skill passed or failed.
+	Runs int `json:"runs"`
+	// Flaky counts the runs in which it passed only on retry.
+	Flaky int `json:"flaky"`
+	// Score is Flaky / Runs.
+	Score float64 `json:"score"`
+}
+
+// Flakiness scores every skill that ran in runs, most flaky first (ties by
+// skill ID). Skipped results are not counted.
+func Flakiness(runs []RunRecord) []FlakyScore {
+	bySkill := map[string]*FlakyScore{}
+	for _, rec := range runs {
+		for _, res := range rec.Results {
+			if res.Status == StatusSkip {
+				continue
+			}
+			sc := bySkill[res.Skill]
+			if sc == nil {
+				sc = &FlakyScore{Skill: res.Skill}
+				bySkill[res.Skill] = sc
+			}
+			sc.Runs++
+			if res.Flaky {
+				sc.Flaky++
+			}
+		}
+	}
+
+	out := make([]FlakyScore, 0, len(bySkill))
+	for _, sc := range bySkill {
+		sc.Score = float64(sc.Flaky) / float64(sc.Runs)
+		out = append(out, *sc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Skill < out[j].Skill
+	})
+	return out
+}
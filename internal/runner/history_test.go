@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRuns(t *testing.T) {
+	from := RunRecord{Results: []SkillResult{
+		{Skill: "a", Status: StatusPass},
+		{Skill: "b", Status: StatusFail},
+		{Skill: "c", Status: StatusPass},
+		{Skill: "d", Status: StatusSkip},
+		{Skill: "e", Status: StatusFail},
+		{Skill: "h", Status: StatusPass},
+		{Skill: "same", Status: StatusPass},
+	}}
+	to := RunRecord{Results: []SkillResult{
+		{Skill: "a", Status: StatusFail},
+		{Skill: "b", Status: StatusPass},
+		{Skill: "d", Status: StatusPass},
+		{Skill: "f", Status: StatusPass},
+		{Skill: "g", Status: StatusFail},
+		{Skill: "h", Status: StatusTimeout},
+		{Skill: "same", Status: StatusPass},
+	}}
+
+	assert.Equal(t, []SkillChange{
+		{Skill: "a", Kind: ChangeRegressed, From: StatusPass, To: StatusFail},
+		{Skill: "b", Kind: ChangeFixed, From: StatusFail, To: StatusPass},
+		{Skill: "c", Kind: ChangeRemoved, From: StatusPass},
+		{Skill: "d", Kind: ChangeChanged, From: StatusSkip, To: StatusPass},
+		{Skill: "e", Kind: ChangeRemoved, From: StatusFail},
+		{Skill: "f", Kind: ChangeAdded, To: StatusPass},
+		{Skill: "g", Kind: ChangeRegressed, To: StatusFail},
+		{Skill: "h", Kind: ChangeRegressed, From: StatusPass, To: StatusTimeout},
+	}, DiffRuns(from, to))
+}
+
+func TestHistoryPrune(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := range HistoryLimit + 2 {
+		res := []SkillResult{{Skill: "s", Status: StatusPass, Note: fmt.Sprint(i)}}
+		require.NoError(t, store.WriteRun(NewRunRecord("c", "", res, start.Add(time.Duration(i)*time.Minute))))
+	}
+
+	runs, err := store.ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, HistoryLimit)
+	assert.Equal(t, fmt.Sprint(HistoryLimit+1), runs[0].Results[0].Note, "newest first")
+	assert.Equal(t, "2", runs[len(runs)-1].Results[0].Note, "oldest runs pruned")
+}
+
+func TestFlakiness(t *testing.T) {
+	runs := []RunRecord{
+		{Results: []SkillResult{{Skill: "net", Status: StatusPass, Flaky: true}, {Skill: "lint", Status: StatusPass}}},
+		{Results: []SkillResult{{Skill: "net", Status: StatusPass}, {Skill: "lint", Status: StatusFail}}},
+		{Results: []SkillResult{{Skill: "net", Status: StatusSkip}, {Skill: "lint", Status: StatusPass}}},
+	}
+
+	assert.Equal(t, []FlakyScore{
+		{Skill: "net", Runs: 2, Flaky: 1, Score: 0.5},
+		{Skill: "lint", Runs: 3, Flaky: 0, Score: 0},
+	}, Flakiness(runs))
+}
@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/schemas"
+)
+
+// LastRunSchemaVersion is the schema_version of last-run.json. Version 1.1
+// added SkillsChecksum; older files are migrated when read.
+const LastRunSchemaVersion = "1.1"
+
+// ErrSkillsChanged is returned by ReadLastRun when the skill results no
+// longer match the checksum the last run recorded.
+var ErrSkillsChanged = errors.New("skill results changed since the last run was recorded")
+
+// VersionError is returned for a state file written by a newer cortex.
+type VersionError struct {
+	Path    string
+	Version string
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("%s has schema_version %s, newer than this cortex supports (%s); upgrade cortex or clear the state with `cortex run reset`",
+		e.Path, e.Version, LastRunSchemaVersion)
+}
+
+// ExitCode reports an incompatible state directory as a config error.
+func (e *VersionError) ExitCode() int { return ExitConfig }
+
+// lastRunMigrations upgrade a last-run document one version at a time, in
+// order. A file without schema_version predates versioning.
+var lastRunMigrations = []struct {
+	from, to string
+	migrate  func(doc map[string]any)
+}{
+	{"", "1.0", func(doc map[string]any) {
+		for _, key := range []string{"skills", "failed"} {
+			if _, ok := doc[key]; !ok {
+				doc[key] = []any{}
+			}
+		}
+	}},
+	{"1.0", "1.1", func(map[string]any) {}},
+}
+
+// decodeLastRun migrates data to LastRunSchemaVersion and decodes it.
+func decodeLastRun(path string, data []byte) (*LastRun, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding last run: %w", err)
+	}
+	version, _ := doc["schema_version"].(string)
+	if newerVersion(version, LastRunSchemaVersion) {
+		return nil, &VersionError{Path: path, Version: version}
+	}
+	if version != LastRunSchemaVersion {
+		for _, m := range lastRunMigrations {
+			if version == m.from {
+				m.migrate(doc)
+				version = m.to
+			}
+		}
+		if version != LastRunSchemaVersion {
+			return nil, fmt.Errorf("decoding last run: no migration from schema_version %q", doc["schema_version"])
+		}
+		doc["schema_version"] = version
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var last LastRun
+	if err := schemas.Decode(schemas.LastRun, data, &last); err != nil {
+		return nil, fmt.Errorf("decoding last run: %w", err)
+	}
+	return &last, nil
+}
+
+// newerVersion reports whether the "major.minor" version v is above cur.
+// Versions that do not parse are not newer; decoding rejects them.
+func newerVersion(v, cur string) bool {
+	parse := func(s string) (int, int, bool) {
+		major, minor, _ := strings.Cut(s, ".")
+		ma, err1 := strconv.Atoi(major)
+		mi, err2 := strconv.Atoi(minor)
+		return ma, mi, err1 == nil && err2 == nil
+	}
+	vMajor, vMinor, ok := parse(v)
+	cMajor, cMinor, _ := parse(cur)
+	return ok && (vMajor > cMajor || vMajor == cMajor && vMinor > cMinor)
+}
+
+// skillsChecksum returns the SHA-256 over the names and contents of the
+// skill result files, sorted by name.
+func (s *StateStore) skillsChecksum() (string, error) {
+	dir := filepath.Dir(s.skillPath("x"))
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		fh := sha256.New()
+		_, err = io.Copy(fh, f)
+		_ = f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%x\n", name, fh.Sum(nil))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLastRun_Migrates(t *testing.T) {
+	for name, doc := range map[string]string{
+		"unversioned": `{"status":"fail","skills":["a","b"],"failed":["b"]}`,
+		"1.0":         `{"schema_version":"1.0","status":"fail","skills":["a","b"],"failed":["b"]}`,
+	} {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "last-run.json"), []byte(doc), 0o644))
+
+		last, err := NewStateStore(dir).ReadLastRun()
+		require.NoError(t, err, name)
+		assert.Equal(t, &LastRun{
+			SchemaVersion: LastRunSchemaVersion,
+			Status:        "fail",
+			Skills:        []string{"a", "b"},
+			Failed:        []string{"b"},
+		}, last, name)
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "last-run.json"), []byte(`{"status":"pass"}`), 0o644))
+	last, err := NewStateStore(dir).ReadLastRun()
+	require.NoError(t, err)
+	assert.Empty(t, last.Skills, "lists missing before versioning default to empty")
+}
+
+func TestReadLastRun_RefusesNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "last-run.json"), []byte(`{"schema_version":"1.2","status":"pass","skills":[],"failed":[],"new_field":1}`), 0o644))
+
+	_, err := NewStateStore(dir).ReadLastRun()
+	var verr *VersionError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "1.2", verr.Version)
+	assert.Equal(t, ExitConfig, verr.ExitCode())
+	assert.ErrorContains(t, err, "upgrade cortex or clear the state with `cortex run reset`")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "last-run.json"), []byte(`{"schema_version":"0.9","status":"pass","skills":[],"failed":[]}`), 0o644))
+	_, err = NewStateStore(dir).ReadLastRun()
+	assert.ErrorContains(t, err, `no migration from schema_version "0.9"`)
+}
+
+func TestReadLastRun_SkillsChecksum(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "a", Status: StatusPass}))
+	require.NoError(t, store.WriteLastRun(LastRun{Status: "pass", Skills: []string{"a"}}))
+
+	last, err := store.ReadLastRun()
+	require.NoError(t, err)
+	assert.Len(t, last.SkillsChecksum, 64)
+
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "a", Status: StatusFail}))
+	_, err = store.ReadLastRun()
+	assert.ErrorIs(t, err, ErrSkillsChanged)
+	assert.ErrorContains(t, err, "cortex run reset")
+}
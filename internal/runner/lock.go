@@ -0,0 +1,175 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockFileName is the run-in-progress marker in the state directory.
+const LockFileName = "run.lock"
+
+// RunLock is the content of the marker while a run holds the state
+// directory; there is no marker while it is idle.
+type RunLock struct {
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+	// Commit and Fingerprint identify the worktree state the run started
+	// from, as in RunRecord.
+	Commit      string `json:"commit,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// LockedError is returned when another process holds the state directory.
+type LockedError struct {
+	Path   string
+	Holder RunLock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another cortex run (pid %d, started %s) holds %s; wait for it to finish or pass --force",
+		e.Holder.PID, e.Holder.Started.Format(time.RFC3339), e.Path)
+}
+
+// ExitCode reports the lock as an execution error.
+func (e *LockedError) ExitCode() int { return ExitExecution }
+
+func (s *StateStore) lockPath() string {
+	return filepath.Join(s.baseDir, LockFileName)
+}
+
+// Lock takes the advisory lock of the state directory for the process and
+// records info in the marker. unlock removes the marker and releases the
+// lock. When another process holds it, Lock returns a *LockedError, unless
+// force is set: then the marker is overwritten and the run proceeds
+// without the lock.
+func (s *StateStore) Lock(info RunLock, force bool) (unlock func() error, err error) {
+	if err := os.MkdirAll(s.baseDir, 0o755); err != nil {
+		return nil, err
+	}
+	f, locked, err := s.openLock()
+	if err != nil {
+		return nil, err
+	}
+	if !locked && !force {
+		holder, _ := readRunLock(f)
+		_ = f.Close()
+		return nil, &LockedError{Path: s.lockPath(), Holder: holder}
+	}
+
+	if err := writeRunLock(f, &info); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() error {
+		if !locked {
+			return f.Close()
+		}
+		return releaseLock(s.lockPath(), f)
+	}, nil
+}
+
+// openLock opens the marker and reports whether the process now holds the
+// lock. A missing marker is created with O_EXCL, so of two processes
+// starting together exactly one creates it. The marker of a holder that is
+// gone is removed and created again the same way.
+func (s *StateStore) openLock() (*os.File, bool, error) {
+	path := s.lockPath()
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		created := err == nil
+		if errors.Is(err, fs.ErrExist) {
+			f, err = os.OpenFile(path, os.O_RDWR, 0)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("opening run lock: %w", err)
+		}
+
+		locked, err := lockFile(f, created)
+		if err != nil {
+			_ = f.Close()
+			return nil, false, fmt.Errorf("locking %s: %w", path, err)
+		}
+		if !locked {
+			return f, false, nil
+		}
+		// A marker no longer at path was removed by its holder or taken
+		// over by another process; only the one still there is removed.
+		current := isMarker(path, f)
+		if created && current {
+			return f, true, nil
+		}
+		if current {
+			_ = releaseLock(path, f)
+		} else {
+			_ = f.Close()
+		}
+	}
+}
+
+// isMarker reports whether f is still the file at path.
+func isMarker(path string, f *os.File) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	own, err := f.Stat()
+	return err == nil && os.SameFile(info, own)
+}
+
+// ReadLock returns the marker of the run in progress, or nil when the
+// state directory is idle.
+func (s *StateStore) ReadLock() (*RunLock, error) {
+	f, err := os.Open(s.lockPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := readRunLock(f)
+	if err != nil || info.PID == 0 {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func readRunLock(f *os.File) (RunLock, error) {
+	var info RunLock
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return info, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil || len(data) == 0 {
+		return info, err
+	}
+	return info, json.Unmarshal(data, &info)
+}
+
+// writeRunLock replaces the content of the marker with info, or empties it.
+func writeRunLock(f *os.File, info *RunLock) error {
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("writing run lock: %w", err)
+	}
+	if info == nil {
+		return nil
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(append(data, '\n'), 0); err != nil {
+		return fmt.Errorf("writing run lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build !unix
+
+package runner
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile treats a marker the process created as its lock, and an
+// existing one as held while it names another live process, the closest
+// the standard library offers without flock. A marker left by a crashed
+// run is taken over; an empty or partial one is still being written.
+func lockFile(f *os.File, created bool) (bool, error) {
+	if created {
+		return true, nil
+	}
+	holder, err := readRunLock(f)
+	if err != nil || holder.PID == 0 {
+		return false, nil
+	}
+	if holder.PID == os.Getpid() {
+		return true, nil
+	}
+	if _, err := os.FindProcess(holder.PID); err != nil {
+		return true, nil
+	}
+	return false, nil
+}
+
+// releaseLock closes the marker f and removes it from path; an open file
+// cannot be removed on every platform.
+func releaseLock(path string, f *os.File) error {
+	return errors.Join(f.Close(), os.Remove(path))
+}
@@ -0,0 +1,137 @@
+//go:build unix
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_Lock(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	unlock, err := store.Lock(RunLock{PID: 42, Started: started, Commit: "abc"}, false)
+	require.NoError(t, err)
+	held, err := store.ReadLock()
+	require.NoError(t, err)
+	assert.Equal(t, &RunLock{PID: 42, Started: started, Commit: "abc"}, held)
+
+	// flock conflicts between open files even within one process.
+	_, err = store.Lock(RunLock{PID: 43}, false)
+	var locked *LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, 42, locked.Holder.PID)
+	assert.Equal(t, ExitExecution, locked.ExitCode())
+	assert.ErrorContains(t, err, "pass --force")
+
+	forced, err := store.Lock(RunLock{PID: 43}, true)
+	require.NoError(t, err)
+	held, err = store.ReadLock()
+	require.NoError(t, err)
+	assert.Equal(t, 43, held.PID, "--force overwrites the marker")
+	require.NoError(t, forced())
+
+	require.NoError(t, unlock())
+	held, err = store.ReadLock()
+	require.NoError(t, err)
+	assert.Nil(t, held)
+
+	unlock, err = store.Lock(RunLock{PID: 44}, false)
+	require.NoError(t, err, "the lock is free again")
+	require.NoError(t, unlock())
+}
+
+func TestStateStore_LockTakesOverStaleMarker(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStateStore(dir)
+	// A crashed run leaves its marker behind, but not its flock.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LockFileName), []byte(`{"pid":42}`+"\n"), 0o644))
+
+	unlock, err := store.Lock(RunLock{PID: 43}, false)
+	require.NoError(t, err)
+	held, err := store.ReadLock()
+	require.NoError(t, err)
+	assert.Equal(t, 43, held.PID)
+
+	require.NoError(t, unlock())
+	_, err = os.Stat(filepath.Join(dir, LockFileName))
+	assert.True(t, os.IsNotExist(err), "the marker is removed when the run ends")
+}
+
+func TestStateStore_LockIsExclusive(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var unlocks []func() error
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(pid int) {
+			defer wg.Done()
+			unlock, err := store.Lock(RunLock{PID: pid}, false)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			unlocks = append(unlocks, unlock)
+			mu.Unlock()
+		}(100 + i)
+	}
+	wg.Wait()
+	require.Len(t, unlocks, 1)
+	require.NoError(t, unlocks[0]())
+}
+
+func TestRunner_LockedStateDir(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStateStore(dir)
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusPass}}
+
+	unlock, err := store.Lock(RunLock{PID: 42, Started: time.Now()}, false)
+	require.NoError(t, err)
+	defer func() { _ = unlock() }()
+
+	r := NewRunner([]Skill{s1}, store, &Deps{})
+	r.SetOutput(&bytes.Buffer{})
+	var locked *LockedError
+	require.ErrorAs(t, r.RunAll(context.Background()), &locked)
+	assert.False(t, s1.called)
+
+	r.SetForce(true)
+	require.NoError(t, r.RunAll(context.Background()))
+	assert.True(t, s1.called)
+}
+
+func TestStateStore_AtomicWrites(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStateStore(dir)
+	require.NoError(t, store.WriteLastRun(LastRun{Status: "pass", Skills: []string{"s1"}}))
+	require.NoError(t, store.WriteSkillResult(SkillResult{Skill: "s1", Status: StatusPass}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"last-run.json", "skills"}, names, "no temp files are left behind")
+
+	unlock, err := store.Lock(RunLock{PID: 42}, false)
+	require.NoError(t, err)
+	defer func() { _ = unlock() }()
+	require.NoError(t, store.Reset())
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "reset keeps the lock")
+	assert.Equal(t, LockFileName, entries[0].Name())
+	_, err = os.Stat(filepath.Join(dir, "last-run.json"))
+	assert.True(t, os.IsNotExist(err))
+}
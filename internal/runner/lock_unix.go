@@ -0,0 +1,29 @@
+//go:build unix
+
+package runner
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive flock on f without waiting and reports
+// whether it got it. The kernel releases it when the process exits, so a
+// crashed run never leaves the directory locked.
+func lockFile(f *os.File, created bool) (bool, error) {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// releaseLock removes the marker f at path and closes it. It is removed
+// while still locked: a process that opened it earlier and locks it
+// afterwards finds it gone and starts over.
+func releaseLock(path string, f *os.File) error {
+	err := os.Remove(path)
+	err = errors.Join(err, syscall.Flock(int(f.Fd()), syscall.LOCK_UN))
+	return errors.Join(err, f.Close())
+}
@@ -7,21 +7,69 @@ const (
 	StatusPass SkillStatus = "pass"
 	StatusFail SkillStatus = "fail"
 	StatusSkip SkillStatus = "skip"
+	// StatusTimeout is a failure caused by the skill exceeding its time limit.
+	StatusTimeout SkillStatus = "timeout"
 )
 
+// Failed reports whether the status counts as a failure.
+func (s SkillStatus) Failed() bool {
+	return s == StatusFail || s == StatusTimeout
+}
+
+// StateSchemaVersion is the schema_version stamped on skill results and
+// run records; last-run.json has LastRunSchemaVersion.
+const StateSchemaVersion = "1.0"
+
 // SkillResult represents the result of a single skill execution.
 // Matches .cortex/run/skills/<skill>.json schema.
 type SkillResult struct {
+	SchemaVersion string `json:"schema_version"`
+
 	Skill    string      `json:"skill"`
 	Status   SkillStatus `json:"status"`
 	ExitCode int         `json:"exit_code"`
 	Note     string      `json:"note,omitempty"`
+	// Attempts is how many times the skill ran when it was retried.
+	Attempts int `json:"attempts,omitempty"`
+	// Flaky marks a skill that failed and then passed on retry.
+	Flaky bool `json:"flaky,omitempty"`
+	// DurationMS is the wall time of the skill, retries included.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+	// Limits describes the ExecLimits the skill ran with, if any.
+	Limits string `json:"limits,omitempty"`
+	// Sandbox describes the ExecSandbox the skill ran in, if any, with the
+	// mode auto resolved to.
+	Sandbox string `json:"sandbox,omitempty"`
+	// EnvHash is the EnvHash of the environment the skill's processes got.
+	EnvHash string `json:"env_hash,omitempty"`
 }
 
 // LastRun represents the summary of the last execution.
 // Matches .cortex/run/last-run.json schema.
 type LastRun struct {
-	Status string   `json:"status"` // "pass" or "fail"
-	Skills []string `json:"skills"` // Ordered list of skills run
-	Failed []string `json:"failed"` // List of failed skills
+	SchemaVersion string `json:"schema_version"`
+
+	Status string   `json:"status"`          // "pass" or "fail"
+	Skills []string `json:"skills"`          // Ordered list of skills run
+	Failed []string `json:"failed"`          // List of failed skills
+	Flaky  []string `json:"flaky,omitempty"` // Skills that passed only on retry
+	// NotRun are the skills a fail-fast run stopped before, in order.
+	NotRun []string `json:"not_run,omitempty"`
+	// Mode is how the run selected its skills; nil for a plain run.
+	Mode *RunMode `json:"mode,omitempty"`
+	// SkillsChecksum is the SHA-256 of the skill result files when the run
+	// was recorded; ReadLastRun rejects results that changed since.
+	SkillsChecksum string `json:"skills_checksum,omitempty"`
+}
+
+// RunMode records how a run selected and bounded its skills, so what it
+// left out is unambiguous.
+type RunMode struct {
+	// FailFast runs stop at their first failed skill.
+	FailFast bool `json:"fail_fast,omitempty"`
+	// ContinueFrom is the skill a run all started at.
+	ContinueFrom string `json:"continue_from,omitempty"`
+	// OnlyFailed runs re-ran the failed and not-run skills of the previous
+	// run (resume).
+	OnlyFailed bool `json:"only_failed,omitempty"`
 }
@@ -0,0 +1,81 @@
+package runner
+
+import "strings"
+
+// ModuleSkillID returns the ID of the per-module variant of skill base for
+// the module at dir (relative, slash separated), e.g. "test:go@services/api".
+// The root module keeps the plain ID.
+func ModuleSkillID(base, dir string) string {
+	if dir == "" || dir == "." {
+		return base
+	}
+	return base + "@" + dir
+}
+
+// BaseSkillID strips the module suffix from a skill ID.
+func BaseSkillID(id string) string {
+	base, _, _ := strings.Cut(id, "@")
+	return base
+}
+
+// lookup returns the per-skill setting for id: its own entry, else the entry
+// of its base skill, else the "*" fallback.
+func lookup[V any](m map[string]V, id string) V {
+	if v, ok := m[id]; ok {
+		return v
+	}
+	if v, ok := m[BaseSkillID(id)]; ok {
+		return v
+	}
+	return m["*"]
+}
+
+// ModuleAggregate is the combined outcome of the module variants of one
+// per-module skill in a run.
+type ModuleAggregate struct {
+	Skill   string   `json:"skill"`
+	Modules int      `json:"modules"`
+	Failed  []string `json:"failed,omitempty"` // module dirs; "." is the root
+}
+
+// AggregateModules combines the module variants of each per-module skill in
+// last, in run order. Skills that ran without module variants are left out.
+func AggregateModules(last *LastRun) []ModuleAggregate {
+	if last == nil {
+		return nil
+	}
+	failed := make(map[string]bool, len(last.Failed))
+	for _, id := range last.Failed {
+		failed[id] = true
+	}
+
+	var bases []string
+	dirs := map[string][]string{}
+	hasVariant := map[string]bool{}
+	for _, id := range last.Skills {
+		base, dir, ok := strings.Cut(id, "@")
+		if !ok {
+			dir = "."
+		}
+		hasVariant[base] = hasVariant[base] || ok
+		if _, seen := dirs[base]; !seen {
+			bases = append(bases, base)
+		}
+		dirs[base] = append(dirs[base], dir)
+	}
+
+	var out []ModuleAggregate
+	for _, base := range bases {
+		if !hasVariant[base] {
+			continue
+		}
+		agg := ModuleAggregate{Skill: base, Modules: len(dirs[base])}
+		for _, dir := range dirs[base] {
+			if failed[ModuleSkillID(base, dir)] {
+				agg.Failed = append(agg.Failed, dir)
+			}
+		}
+		out = append(out, agg)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleSkillID(t *testing.T) {
+	assert.Equal(t, "test:go", ModuleSkillID("test:go", "."))
+	assert.Equal(t, "test:go", ModuleSkillID("test:go", ""))
+	assert.Equal(t, "test:go@services/api", ModuleSkillID("test:go", "services/api"))
+	assert.Equal(t, "test:go", BaseSkillID("test:go@services/api"))
+	assert.Equal(t, "test:go", BaseSkillID("test:go"))
+}
+
+func TestLookup_FallsBackToBaseSkill(t *testing.T) {
+	m := map[string]int{"*": 1, "test:go": 2, "test:go@services/web": 3}
+	assert.Equal(t, 2, lookup(m, "test:go@services/api"))
+	assert.Equal(t, 3, lookup(m, "test:go@services/web"))
+	assert.Equal(t, 1, lookup(m, "lint:golangci@services/api"))
+}
+
+func TestRunner_RunListSelectsModuleVariants(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	root := &MockSkill{id: "test:go", result: SkillResult{Skill: "test:go", Status: StatusPass}}
+	api := &MockSkill{id: "test:go@services/api", result: SkillResult{Skill: "test:go@services/api", Status: StatusFail, ExitCode: ExitValidation}}
+	web := &MockSkill{id: "test:build@services/web", result: SkillResult{Skill: "test:build@services/web", Status: StatusPass}}
+	r := NewRunner([]Skill{root, api, web}, store, &Deps{})
+
+	require.Error(t, r.RunList(context.Background(), []string{"test:go"}))
+	assert.True(t, root.called)
+	assert.True(t, api.called)
+	assert.False(t, web.called)
+
+	root.called, api.called = false, false
+	require.NoError(t, r.RunList(context.Background(), []string{"test:go@.", "test:build"}))
+	assert.True(t, root.called)
+	assert.False(t, api.called)
+	assert.True(t, web.called)
+
+	// Module results are stored under an escaped file name.
+	res, err := store.ReadSkill("test:go@services/api")
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, StatusFail, res.Status)
+	assert.FileExists(t, store.skillPath("test:go@services/api"))
+
+	assert.ErrorContains(t, r.RunList(context.Background(), []string{"lint:golangci"}), "skill not found: lint:golangci")
+}
+
+func TestAggregateModules(t *testing.T) {
+	last := &LastRun{
+		Skills: []string{"test:build@services/api", "test:build@services/web", "test:go", "test:go@services/api", "docs:yaml"},
+		Failed: []string{"test:go@services/api", "docs:yaml"},
+	}
+	assert.Equal(t, []ModuleAggregate{
+		{Skill: "test:build", Modules: 2},
+		{Skill: "test:go", Modules: 2, Failed: []string{"services/api"}},
+	}, AggregateModules(last))
+	assert.Nil(t, AggregateModules(nil))
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy re-runs a failed skill to ride out transient failures.
+type RetryPolicy struct {
+	// Count is the number of extra attempts after the first failure.
+	Count int
+	// Backoff is the wait before the first retry; it doubles for each
+	// further retry.
+	Backoff time.Duration
+}
+
+// SetRetry configures retries per skill ID. The "*" entry applies to skills
+// without their own entry.
+func (r *Runner) SetRetry(policies map[string]RetryPolicy) {
+	r.retry = policies
+}
+
+func (r *Runner) retryPolicy(id string) RetryPolicy {
+	return lookup(r.retry, id)
+}
+
+// runWithRetry runs skill until it does not fail or its retries are used up.
+// A skill that passes after failing is marked flaky.
+func (r *Runner) runWithRetry(ctx context.Context, skill Skill) SkillResult {
+	policy := r.retryPolicy(skill.ID())
+	backoff := policy.Backoff
+	deps := r.skillDeps(skill.ID())
+
+	res := r.runOnce(ctx, skill, deps)
+	attempts := 1
+	for res.Status.Failed() && attempts <= policy.Count {
+		fmt.Fprintf(r.out, "RETRY: %s (attempt %d of %d, exit %d) after %s\n", skill.ID(), attempts+1, policy.Count+1, res.ExitCode, backoff)
+		r.logger().Warn("retrying skill", "skill", skill.ID(), "attempt", attempts+1, "of", policy.Count+1,
+			"exit_code", res.ExitCode, "backoff", backoff)
+		if err := sleepContext(ctx, backoff); err != nil {
+			break
+		}
+		backoff *= 2
+		res = r.runOnce(ctx, skill, deps)
+		attempts++
+	}
+
+	if attempts > 1 {
+		res.Attempts = attempts
+		res.Flaky = res.Status == StatusPass
+	}
+	return res
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -2,15 +2,37 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
+
+	"github.com/bartekus/cortex/internal/i18n"
 )
 
+// ErrSkillNotFound is returned by RunList for an ID that selects no skill.
+var ErrSkillNotFound = errors.New("skill not found")
+
 // Runner manages the execution of skills.
 type Runner struct {
-	skills []Skill
-	store  *StateStore
-	deps   *Deps
+	skills  []Skill
+	store   *StateStore
+	deps    *Deps
+	retry   map[string]RetryPolicy
+	timeout map[string]time.Duration
+	limits  map[string]ExecLimits
+	sandbox map[string]ExecSandbox
+	env     map[string]EnvPolicy
+	out     io.Writer
+	filter  func(SkillResult) SkillResult
+	results []SkillResult
+	// failFast stops a sequence at its first failed skill.
+	failFast bool
+	// force runs even when another process holds the state lock.
+	force bool
 }
 
 // NewRunner creates a new runner with the given skills and dependencies.
@@ -19,84 +41,108 @@ func NewRunner(skills []Skill, store *StateStore, deps *Deps) *Runner {
 		skills: skills,
 		store:  store,
 		deps:   deps,
+		out:    os.Stdout,
 	}
 }
 
+// logger returns the logger of the runner's Deps.
+func (r *Runner) logger() *slog.Logger {
+	return r.deps.Logger()
+}
+
+// SetOutput sets where progress is written (default os.Stdout).
+func (r *Runner) SetOutput(w io.Writer) {
+	r.out = w
+}
+
+// SetResultFilter sets a function applied to every skill result before it
+// is recorded, e.g. to drop suppressed findings.
+func (r *Runner) SetResultFilter(f func(SkillResult) SkillResult) {
+	r.filter = f
+}
+
+// SetFailFast stops runs at their first failed skill. The skills a run did
+// not reach are recorded in LastRun.NotRun, and Resume runs them.
+func (r *Runner) SetFailFast(on bool) {
+	r.failFast = on
+}
+
+// SetForce makes runs proceed when another process holds the state
+// directory, overwriting its run marker. Results of both runs may then
+// interleave.
+func (r *Runner) SetForce(on bool) {
+	r.force = on
+}
+
+// Results returns the skill results of the last RunAll, Resume or RunList
+// call, in execution order.
+func (r *Runner) Results() []SkillResult {
+	return r.results
+}
+
 // RunAll executes all skills in order.
-// It continues execution even if a skill fails, accumulating failures.
-// Returns an error if ANY skill failed.
+// It continues execution even if a skill fails, accumulating failures,
+// unless fail-fast is set. Returns an error if ANY skill failed.
 func (r *Runner) RunAll(ctx context.Context) error {
-	return r.executeSequence(ctx, r.skills)
+	return r.executeSequence(ctx, r.skills, RunMode{})
+}
+
+// RunAllFrom executes the skills of RunAll starting at id, which may be a
+// plain ID selecting its first module variant.
+func (r *Runner) RunAllFrom(ctx context.Context, id string) error {
+	selected := r.selectSkills(id)
+	if len(selected) == 0 {
+		return fmt.Errorf("%w: %s", ErrSkillNotFound, id)
+	}
+	for i, s := range r.skills {
+		if s.ID() == selected[0].ID() {
+			return r.executeSequence(ctx, r.skills[i:], RunMode{ContinueFrom: id})
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrSkillNotFound, id)
 }
 
-// Resume continues execution from the first failed skill in the last run.
+// Resume re-runs the skills that failed in the last run and those a
+// fail-fast run did not reach, in RunAll order. Without any it does
+// nothing.
 func (r *Runner) Resume(ctx context.Context) error {
-	failed, err := r.store.LoadFailedSkills()
+	last, err := r.store.ReadLastRun()
 	if err != nil {
 		return fmt.Errorf("loading failed skills: %w", err)
 	}
-
-	if len(failed) == 0 {
-		// Nothing failed, do nothing or run all?
-		// Scripts/run.sh says "No failed skills to resume" and exits 0.
-		// We'll mimic that by returning nil.
+	if last == nil {
 		return nil
 	}
 
-	// Logic from scripts/run.sh:
-	// It basically re-runs the failed skills (and arguably subsequent ones, but the script logic
-	// actually just re-runs the failed ones if I recall correctly, OR it re-runs from the failure point).
-	// Let's re-read the script logic in my head:
-	// "while IFS= read -r skill; do ... run_skill ... done << failed_list"
-	// It only re-runs the SPECIFIC failed skills.
-
-	// Wait, the Review said "Resume logic: find first failed skill from last run; re-run from there."
-	// But the script says: "failed_list=...; while read skill; do run_skill...".
-	// The script logic is "Retry the ones that failed".
-	// However, if a skill blocked subsequent skills from running, they wouldn't be in "failed", they just wouldn't be in the list?
-	// Actually scripts/run.sh stops on first fail. So "failed" contains the ONE skill that failed (and maybe others if it kept going, but it has `set -e` behavior sort of).
-	// Actually `run_skill || { overall_rc=1; failed+=("$skill"); }`
-	// And the loop continues?
-	// "run_skill ... || { ... }" implies it continues?
-	// Let's check run.sh content again...
-	// Line 218: "run_skill "$skill" || { overall_rc=1; failed+=("$skill"); }"
-	// It continues! `run.sh all` runs EVERYTHING and collects failures.
-	// BUT `run_skill` line 138 `return 2`? No.
-	// `run_skill` returns the rc.
-	// The loop in `all` continues.
-
-	// So `resume` runs ONLY the skills that failed previously.
-	// The script does NOT run subsequent skipped skills because `all` runs everything anyway (it doesn't abort early).
-	// WAIT. `run.sh` line 17 check: `set -euo pipefail`.
-	// Line 218 loop. `||` catches the error so `set -e` doesn't trigger.
-
-	// So `cortex run all` should run ALL skills and collect failures.
-	// And `cortex run resume` should run ONLY the failed skills.
-
-	// I will implement "Run specific list of skills".
-
+	pending := make(map[string]bool)
+	for _, id := range append(append([]string{}, last.Failed...), last.NotRun...) {
+		pending[id] = true
+	}
 	toRun := []Skill{}
-	for _, id := range failed {
-		skill := r.findSkill(id)
-		if skill != nil {
-			toRun = append(toRun, skill)
+	for _, s := range r.skills {
+		if pending[s.ID()] {
+			toRun = append(toRun, s)
 		}
 	}
-
-	return r.executeSequence(ctx, toRun)
+	if len(toRun) == 0 {
+		return nil
+	}
+	return r.executeSequence(ctx, toRun, RunMode{OnlyFailed: true})
 }
 
-// RunList executes a specific list of skill IDs.
+// RunList executes a specific list of skill IDs. The base ID of a skill
+// that runs per module (e.g. "test:go") selects all its module variants;
+// "test:go@." selects the root module's alone.
 func (r *Runner) RunList(ctx context.Context, skillIDs []string) error {
 	var toRun []Skill
 	for _, id := range skillIDs {
-		s := r.findSkill(id)
-		if s == nil {
-			return fmt.Errorf("skill not found: %s", id)
+		selected := r.selectSkills(id)
+		if len(selected) == 0 {
+			return fmt.Errorf("%w: %s", ErrSkillNotFound, id)
 		}
-		toRun = append(toRun, s)
+		toRun = append(toRun, selected...)
 	}
-	return r.executeSequence(ctx, toRun)
+	return r.executeSequence(ctx, toRun, RunMode{})
 }
 
 func (r *Runner) findSkill(id string) Skill {
@@ -108,29 +154,89 @@ func (r *Runner) findSkill(id string) Skill {
 	return nil
 }
 
+func (r *Runner) selectSkills(id string) []Skill {
+	if base, ok := strings.CutSuffix(id, "@."); ok {
+		if s := r.findSkill(base); s != nil {
+			return []Skill{s}
+		}
+		return nil
+	}
+
+	var out []Skill
+	for _, s := range r.skills {
+		if s.ID() == id || !strings.Contains(id, "@") && BaseSkillID(s.ID()) == id {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
 // executeSequence runs a sequence of skills, updating state.
 // It returns error if ANY skill failed.
-func (r *Runner) executeSequence(ctx context.Context, skills []Skill) error {
+func (r *Runner) executeSequence(ctx context.Context, skills []Skill, mode RunMode) (err error) {
+	var commit, fingerprint string
+	if r.deps != nil {
+		commit, fingerprint = r.deps.Commit, r.deps.Fingerprint
+	}
+	unlock, err := r.store.Lock(RunLock{
+		PID:         os.Getpid(),
+		Started:     time.Now().UTC(),
+		Commit:      commit,
+		Fingerprint: fingerprint,
+	}, r.force)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if uerr := unlock(); err == nil && uerr != nil {
+			err = fmt.Errorf("releasing run lock: %w", uerr)
+		}
+	}()
+
+	mode.FailFast = r.failFast
 	var failed []string
+	var notRun []string
+	var flaky []string
 	var skillNames []string
+	var results []SkillResult
+	exitCode := ExitOK
+	defer func() { r.results = results }()
 
 	overallSuccess := true
 
-	for _, skill := range skills {
+	for i, skill := range skills {
 		id := skill.ID()
+		if r.failFast && !overallSuccess {
+			for _, rest := range skills[i:] {
+				notRun = append(notRun, rest.ID())
+			}
+			fmt.Fprintln(r.out, "")
+			fmt.Fprintln(r.out, i18n.Sprintf("FAIL-FAST: %d skill(s) not run", len(notRun)))
+			break
+		}
 		skillNames = append(skillNames, id)
 
-		fmt.Println("")
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Printf("SKILL: %s\n", id)
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println("")
+		fmt.Fprintln(r.out, "")
+		fmt.Fprintln(r.out, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Fprintln(r.out, i18n.Sprintf("SKILL: %s", id))
+		fmt.Fprintln(r.out, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Fprintln(r.out, "")
 
-		// Measure duration?
+		r.logger().Debug("skill started", "skill", id)
 		start := time.Now()
-		_ = start
-
-		res := skill.Run(ctx, r.deps)
+		res := r.runWithRetry(ctx, skill)
+		if r.filter != nil {
+			res = r.filter(res)
+		}
+		res.DurationMS = time.Since(start).Milliseconds()
+		res.Limits = r.limitsFor(id).String()
+		// The mode processes ran in, e.g. env-only where auto found
+		// neither bubblewrap nor docker.
+		res.Sandbox = r.sandboxFor(id).Resolved().String()
+		res.EnvHash = EnvHash(r.skillDeps(id).Environ())
+		results = append(results, res)
+		r.logger().Info("skill finished", "skill", id, "status", res.Status, "exit_code", res.ExitCode,
+			"duration_ms", res.DurationMS, "attempts", max(res.Attempts, 1))
 
 		// Save individual result
 		if err := r.store.WriteSkillResult(res); err != nil {
@@ -138,9 +244,9 @@ func (r *Runner) executeSequence(ctx context.Context, skills []Skill) error {
 		}
 
 		if res.Status == StatusSkip {
-			fmt.Printf("SKIP: %s\n", id)
+			fmt.Fprintln(r.out, i18n.Sprintf("SKIP: %s", id))
 			if res.Note != "" {
-				fmt.Println(res.Note)
+				fmt.Fprintln(r.out, res.Note)
 			}
 			continue
 		}
@@ -148,15 +254,24 @@ func (r *Runner) executeSequence(ctx context.Context, skills []Skill) error {
 		if res.Status != StatusPass {
 			failed = append(failed, id)
 			overallSuccess = false
-			fmt.Printf("FAIL: %s (exit %d)\n", id, res.ExitCode)
+			exitCode = max(exitCode, res.ExitCode, ExitValidation)
+			if res.Status == StatusTimeout {
+				fmt.Fprintln(r.out, i18n.Sprintf("TIMEOUT: %s (exit %d)", id, res.ExitCode))
+			} else {
+				fmt.Fprintln(r.out, i18n.Sprintf("FAIL: %s (exit %d)", id, res.ExitCode))
+			}
 			if res.Note != "" {
-				fmt.Println(res.Note)
+				fmt.Fprintln(r.out, res.Note)
 			}
 		} else {
 			// passed = append(passed, id)
-			fmt.Printf("PASS: %s\n", id)
+			if res.Flaky {
+				flaky = append(flaky, id)
+				fmt.Fprintln(r.out, i18n.Sprintf("FLAKY: %s passed on attempt %d", id, res.Attempts))
+			}
+			fmt.Fprintln(r.out, i18n.Sprintf("PASS: %s", id))
 			if res.Note != "" {
-				fmt.Println(res.Note)
+				fmt.Fprintln(r.out, res.Note)
 			}
 		}
 	}
@@ -166,6 +281,11 @@ func (r *Runner) executeSequence(ctx context.Context, skills []Skill) error {
 		Status: "pass",
 		Skills: skillNames,
 		Failed: failed,
+		Flaky:  flaky,
+		NotRun: notRun,
+	}
+	if mode != (RunMode{}) {
+		lastRun.Mode = &mode
 	}
 	if !overallSuccess {
 		lastRun.Status = "fail"
@@ -175,8 +295,12 @@ func (r *Runner) executeSequence(ctx context.Context, skills []Skill) error {
 		return fmt.Errorf("writing last run: %w", err)
 	}
 
+	if err := r.store.WriteRun(NewRunRecord(commit, fingerprint, results, time.Now())); err != nil {
+		return fmt.Errorf("writing run history: %w", err)
+	}
+
 	if !overallSuccess {
-		return fmt.Errorf("run failed: %v", failed)
+		return &RunError{Failed: failed, code: exitCode}
 	}
 	return nil
 }
@@ -1,11 +1,18 @@
 package runner
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/i18n"
+	"github.com/bartekus/cortex/internal/log"
 )
 
 // MockSkill implements Skill for testing.
@@ -32,12 +39,20 @@ func TestRunner_RunAll(t *testing.T) {
 	s2 := &MockSkill{id: "s2", result: SkillResult{Skill: "s2", Status: StatusPass}}
 
 	r := NewRunner([]Skill{s1, s2}, store, &Deps{})
+	var out bytes.Buffer
+	r.SetOutput(&out)
 
 	err := r.RunAll(context.Background())
 	require.NoError(t, err)
 
 	assert.True(t, s1.called)
 	assert.True(t, s2.called)
+	assert.Contains(t, out.String(), "PASS: s2")
+	require.Len(t, r.Results(), 2)
+	assert.Equal(t, "s1", r.Results()[0].Skill)
+
+	err = r.RunList(context.Background(), []string{"s3"})
+	assert.ErrorIs(t, err, ErrSkillNotFound)
 
 	// Verify state
 	last, err := store.ReadLastRun()
@@ -47,6 +62,24 @@ func TestRunner_RunAll(t *testing.T) {
 	assert.Empty(t, last.Failed)
 }
 
+func TestRunner_Localized(t *testing.T) {
+	t.Setenv("CORTEX_LANG", "de")
+	store := NewStateStore(t.TempDir())
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusFail, ExitCode: 1}}
+	r := NewRunner([]Skill{s1}, store, &Deps{})
+	var out bytes.Buffer
+	r.SetOutput(&out)
+
+	err := r.RunAll(context.Background())
+	assert.Contains(t, out.String(), "FEHLGESCHLAGEN: s1 (Exit-Code 1)")
+	assert.EqualError(t, err, "run failed: [s1]", "Error() stays English")
+	assert.Equal(t, "Lauf fehlgeschlagen: [s1]", i18n.Error(err))
+
+	last, err := store.ReadLastRun()
+	require.NoError(t, err)
+	assert.Equal(t, "fail", last.Status, "recorded state is locale-invariant")
+}
+
 func TestRunner_RunAll_Failure(t *testing.T) {
 	dir := t.TempDir()
 	store := NewStateStore(dir)
@@ -69,6 +102,43 @@ func TestRunner_RunAll_Failure(t *testing.T) {
 	assert.Equal(t, []string{"s1"}, last.Failed)
 }
 
+func TestRunner_RunAll_ExitCode(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusFail, ExitCode: ExitValidation}}
+	s2 := &MockSkill{id: "s2", result: SkillResult{Skill: "s2", Status: StatusFail, ExitCode: ExitExecution}}
+	s3 := &MockSkill{id: "s3", result: SkillResult{Skill: "s3", Status: StatusFail}}
+
+	err := NewRunner([]Skill{s1, s2}, store, &Deps{}).RunAll(context.Background())
+	var runErr *RunError
+	require.ErrorAs(t, err, &runErr)
+	assert.Equal(t, ExitExecution, runErr.ExitCode())
+	assert.Equal(t, "run failed: [s1 s2]", err.Error())
+
+	// A failure without an exit code still fails the process.
+	err = NewRunner([]Skill{s3}, store, &Deps{}).RunAll(context.Background())
+	require.ErrorAs(t, err, &runErr)
+	assert.Equal(t, ExitValidation, runErr.ExitCode())
+}
+
+func TestRunner_ResultFilter(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusFail, ExitCode: 1, Note: "a.go: x"}}
+
+	r := NewRunner([]Skill{s1}, store, &Deps{})
+	r.SetOutput(&bytes.Buffer{})
+	r.SetResultFilter(func(res SkillResult) SkillResult {
+		res.Status, res.ExitCode, res.Note = StatusPass, ExitOK, "suppressed"
+		return res
+	})
+
+	require.NoError(t, r.RunAll(context.Background()))
+	res, err := store.ReadSkill("s1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, res.Status)
+	assert.Equal(t, "suppressed", res.Note)
+}
+
 func TestRunner_Resume(t *testing.T) {
 	dir := t.TempDir()
 	store := NewStateStore(dir)
@@ -103,3 +173,179 @@ func TestRunner_Resume(t *testing.T) {
 	assert.Equal(t, "pass", last.Status)
 	assert.Equal(t, []string{"s2"}, last.Skills)
 }
+
+func TestRunner_FailFastThenResume(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusFail, ExitCode: ExitValidation}}
+	s2 := &MockSkill{id: "s2", result: SkillResult{Skill: "s2", Status: StatusPass}}
+	s3 := &MockSkill{id: "s3", result: SkillResult{Skill: "s3", Status: StatusPass}}
+
+	var out bytes.Buffer
+	r := NewRunner([]Skill{s1, s2, s3}, store, &Deps{})
+	r.SetOutput(&out)
+	r.SetFailFast(true)
+	require.Error(t, r.RunAll(context.Background()))
+	assert.False(t, s2.called, "fail-fast stops at the first failure")
+	assert.Contains(t, out.String(), "FAIL-FAST: 2 skill(s) not run")
+
+	last, err := store.ReadLastRun()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, last.Skills)
+	assert.Equal(t, []string{"s2", "s3"}, last.NotRun)
+	assert.Equal(t, &RunMode{FailFast: true}, last.Mode)
+
+	// Resume runs the failed skill and the ones fail-fast left out.
+	s1.result = SkillResult{Skill: "s1", Status: StatusPass}
+	r = NewRunner([]Skill{s1, s2, s3}, store, &Deps{})
+	r.SetOutput(io.Discard)
+	require.NoError(t, r.Resume(context.Background()))
+	last, err = store.ReadLastRun()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2", "s3"}, last.Skills)
+	assert.Empty(t, last.NotRun)
+	assert.Equal(t, &RunMode{OnlyFailed: true}, last.Mode)
+}
+
+func TestRunner_RunAllFrom(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusPass}}
+	s2 := &MockSkill{id: "test:go@api", result: SkillResult{Skill: "test:go@api", Status: StatusPass}}
+	s3 := &MockSkill{id: "s3", result: SkillResult{Skill: "s3", Status: StatusPass}}
+
+	r := NewRunner([]Skill{s1, s2, s3}, store, &Deps{})
+	r.SetOutput(io.Discard)
+	require.NoError(t, r.RunAllFrom(context.Background(), "test:go"))
+	assert.False(t, s1.called)
+	assert.True(t, s2.called && s3.called)
+
+	last, err := store.ReadLastRun()
+	require.NoError(t, err)
+	assert.Equal(t, &RunMode{ContinueFrom: "test:go"}, last.Mode)
+
+	assert.ErrorIs(t, r.RunAllFrom(context.Background(), "nope"), ErrSkillNotFound)
+}
+
+func TestRunner_WritesHistory(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+
+	fp := "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusPass}}
+	r := NewRunner([]Skill{s1}, store, &Deps{Commit: "abc123", Fingerprint: fp})
+	require.NoError(t, r.RunAll(context.Background()))
+	require.NoError(t, r.RunAll(context.Background()))
+
+	runs, err := store.ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, 1, "same commit and results share an ID")
+	want := s1.result
+	want.EnvHash = EnvHash((&Deps{}).Environ())
+	assert.Equal(t, RunID("abc123", fp, []SkillResult{want}), runs[0].ID)
+	assert.NotEqual(t, RunID("abc123", "", []SkillResult{want}), runs[0].ID, "the worktree state is part of the ID")
+	assert.Equal(t, "abc123", runs[0].Commit)
+	assert.Equal(t, fp, runs[0].Fingerprint)
+	assert.Equal(t, "pass", runs[0].Status)
+
+	s1.result.Status = StatusFail
+	require.Error(t, r.RunAll(context.Background()))
+
+	runs, err = store.ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	rec, err := store.ReadRun(runs[0].ID[:6])
+	require.NoError(t, err)
+	assert.Equal(t, runs[0].ID, rec.ID)
+
+	_, err = store.ReadRun("ffffffffffff")
+	require.Error(t, err)
+}
+
+// SequenceSkill returns its results in order, repeating the last one.
+type SequenceSkill struct {
+	id      string
+	results []SkillResult
+	calls   int
+}
+
+func (s *SequenceSkill) ID() string { return s.id }
+
+func (s *SequenceSkill) Run(ctx context.Context, deps *Deps) SkillResult {
+	res := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+	return res
+}
+
+func TestRunner_RetryMarksFlaky(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+
+	flaky := &SequenceSkill{id: "net", results: []SkillResult{
+		{Skill: "net", Status: StatusFail, ExitCode: ExitExecution},
+		{Skill: "net", Status: StatusPass},
+	}}
+	broken := &SequenceSkill{id: "lint", results: []SkillResult{{Skill: "lint", Status: StatusFail, ExitCode: ExitValidation}}}
+	once := &SequenceSkill{id: "once", results: []SkillResult{{Skill: "once", Status: StatusFail, ExitCode: ExitValidation}}}
+
+	r := NewRunner([]Skill{flaky, broken, once}, store, &Deps{})
+	r.SetRetry(map[string]RetryPolicy{"*": {Count: 2}, "once": {}})
+	require.Error(t, r.RunAll(context.Background()))
+
+	assert.Equal(t, 2, flaky.calls)
+	assert.Equal(t, 3, broken.calls)
+	assert.Equal(t, 1, once.calls)
+
+	res, err := store.ReadSkill("net")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, res.Status)
+	assert.Equal(t, 2, res.Attempts)
+	assert.True(t, res.Flaky)
+
+	res, err = store.ReadSkill("lint")
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Attempts)
+	assert.False(t, res.Flaky)
+
+	last, err := store.ReadLastRun()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"net"}, last.Flaky)
+	assert.Equal(t, []string{"lint", "once"}, last.Failed)
+}
+
+// loggingSkill logs through its Deps.
+type loggingSkill struct{ id string }
+
+func (s loggingSkill) ID() string { return s.id }
+
+func (s loggingSkill) Run(ctx context.Context, deps *Deps) SkillResult {
+	deps.Logger().Info("checked", "files", 2)
+	return SkillResult{Skill: s.id, Status: StatusPass}
+}
+
+func TestRunner_Logs(t *testing.T) {
+	var logs bytes.Buffer
+	logger, err := log.New(&logs, log.Options{Level: slog.LevelDebug})
+	require.NoError(t, err)
+
+	r := NewRunner([]Skill{loggingSkill{id: "s1"}}, NewStateStore(t.TempDir()), &Deps{Log: logger})
+	r.SetOutput(&bytes.Buffer{})
+	require.NoError(t, r.RunAll(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "level=DEBUG msg=\"skill started\" skill=s1", lines[0])
+	assert.Equal(t, "level=INFO msg=checked skill=s1 files=2", lines[1])
+	assert.Regexp(t, `^level=INFO msg="skill finished" skill=s1 status=pass exit_code=0 duration_ms=\d+ attempts=1$`, lines[2])
+
+	// Without a logger diagnostics are discarded.
+	r = NewRunner([]Skill{loggingSkill{id: "s1"}}, NewStateStore(t.TempDir()), nil)
+	r.SetOutput(&bytes.Buffer{})
+	require.NoError(t, r.RunAll(context.Background()))
+}
+
+func TestDeps_Targets(t *testing.T) {
+	files := []string{"a.go", "docs/b.md", "spec/c.yaml"}
+	assert.Equal(t, files, (&Deps{}).Targets(files), "no subset selects every file")
+
+	deps := &Deps{TargetFiles: []string{"spec/c.yaml", "./a.go", "missing.go"}}
+	assert.Equal(t, []string{"a.go", "spec/c.yaml"}, deps.Targets(files))
+	assert.Empty(t, deps.Targets([]string{"docs/b.md"}))
+}
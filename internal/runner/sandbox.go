@@ -0,0 +1,302 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Sandbox modes.
+const (
+	SandboxOff    = "off"
+	SandboxAuto   = "auto"
+	SandboxBwrap  = "bwrap"
+	SandboxDocker = "docker"
+	// SandboxEnvOnly is what auto resolves to when neither bubblewrap nor
+	// docker with an image is available: only the environment is
+	// restricted.
+	SandboxEnvOnly = "env-only"
+)
+
+// sandboxEnv are the variables a sandboxed process keeps besides
+// ExecSandbox.Env: what the Go toolchain and a shell need, no credentials.
+var sandboxEnv = []string{
+	"CGO_ENABLED", "GOARCH", "GOCACHE", "GOFLAGS", "GOMAXPROCS", "GOMEMLIMIT", "GOMODCACHE",
+	"GONOSUMDB", "GOOS", "GOPATH", "GOPRIVATE", "GOPROXY", "GOROOT", "GOTOOLCHAIN",
+	"HOME", "LANG", "LC_ALL", "PATH", "TERM", "TMPDIR", "USER",
+}
+
+// ExecSandbox isolates the processes a skill starts through Deps.Command.
+// The zero value runs them unsandboxed.
+type ExecSandbox struct {
+	// Mode is SandboxOff (or empty), SandboxAuto, SandboxBwrap or
+	// SandboxDocker.
+	Mode string
+	// Network allows network access.
+	Network bool
+	// Env lists variables kept besides the toolchain ones.
+	Env []string
+	// Writable lists repository-relative paths the processes may modify.
+	// The state directory is always writable.
+	Writable []string
+	// Image is the container image of SandboxDocker.
+	Image string
+}
+
+// Enabled reports whether processes run sandboxed.
+func (s ExecSandbox) Enabled() bool {
+	return s.Mode != "" && s.Mode != SandboxOff
+}
+
+// String describes the sandbox for reports, e.g. "bwrap network=off
+// writable=bin"; empty when disabled. SandboxEnvOnly isolates neither the
+// network nor the filesystem, so it is described by its mode alone.
+func (s ExecSandbox) String() string {
+	if !s.Enabled() {
+		return ""
+	}
+	if s.Mode == SandboxEnvOnly {
+		return s.Mode
+	}
+	parts := []string{s.Mode, "network=off"}
+	if s.Network {
+		parts[1] = "network=on"
+	}
+	if len(s.Writable) > 0 {
+		parts = append(parts, "writable="+strings.Join(s.Writable, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FilterEnv returns the entries of base a sandboxed process keeps: the
+// toolchain variables and those of s.Env.
+func (s ExecSandbox) FilterEnv(base []string) []string {
+	keep := map[string]bool{}
+	for _, k := range sandboxEnv {
+		keep[k] = true
+	}
+	for _, k := range s.Env {
+		keep[k] = true
+	}
+	var env []string
+	for _, kv := range base {
+		if k, _, _ := strings.Cut(kv, "="); keep[k] {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// resolve returns the mode to run with: auto becomes bwrap, docker or
+// SandboxEnvOnly depending on what is installed.
+func (s ExecSandbox) resolve(lookPath func(string) (string, error)) string {
+	if s.Mode != SandboxAuto {
+		return s.Mode
+	}
+	if _, err := lookPath("bwrap"); err == nil {
+		return SandboxBwrap
+	}
+	if _, err := lookPath("docker"); err == nil && s.Image != "" {
+		return SandboxDocker
+	}
+	return SandboxEnvOnly
+}
+
+// Resolved returns s with the mode its processes run in on this machine:
+// auto is replaced as resolve does, other modes are kept.
+func (s ExecSandbox) Resolved() ExecSandbox {
+	s.Mode = s.resolve(exec.LookPath)
+	return s
+}
+
+// sandboxTarget is the process to run in a sandbox.
+type sandboxTarget struct {
+	repoRoot string
+	stateDir string
+	// dir is the working directory, absolute.
+	dir  string
+	argv []string
+	env  []string
+}
+
+// wrap returns the argv that runs t in mode; t.argv itself for
+// SandboxEnvOnly.
+func (s ExecSandbox) wrap(mode string, t sandboxTarget) ([]string, error) {
+	writable, err := s.writablePaths(t)
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case SandboxBwrap:
+		argv := []string{"bwrap", "--die-with-parent", "--unshare-pid", "--unshare-ipc"}
+		if !s.Network {
+			argv = append(argv, "--unshare-net")
+		}
+		argv = append(argv, "--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc", "--tmpfs", "/tmp",
+			"--ro-bind", t.repoRoot, t.repoRoot)
+		for _, p := range append(writable, cacheDirs(t.env, "GOCACHE")...) {
+			argv = append(argv, "--bind", p, p)
+		}
+		argv = append(argv, "--chdir", t.dir, "--")
+		return append(argv, t.argv...), nil
+	case SandboxDocker:
+		if s.Image == "" {
+			return nil, fmt.Errorf("sandbox: docker mode needs an image")
+		}
+		argv := []string{"docker", "run", "--rm", "--tmpfs", "/tmp"}
+		if !s.Network {
+			argv = append(argv, "--network", "none")
+		}
+		if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+			argv = append(argv, "--user", fmt.Sprintf("%d:%d", uid, gid))
+		}
+		argv = append(argv, "-v", t.repoRoot+":"+t.repoRoot+":ro")
+		for _, p := range writable {
+			argv = append(argv, "-v", p+":"+p)
+		}
+		for _, p := range cacheDirs(t.env, "GOMODCACHE") {
+			argv = append(argv, "-v", p+":"+p+":ro")
+		}
+		// The build cache of the host may not fit the image's toolchain.
+		argv = append(argv, "-e", "GOCACHE=/tmp/go-build")
+		var names []string
+		for _, kv := range t.env {
+			if k, _, _ := strings.Cut(kv, "="); k != "PATH" && k != "HOME" && k != "GOCACHE" && k != "TMPDIR" {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			argv = append(argv, "-e", k)
+		}
+		argv = append(argv, "-w", t.dir, s.Image)
+		return append(argv, t.argv...), nil
+	case SandboxEnvOnly:
+		return t.argv, nil
+	}
+	return nil, fmt.Errorf("sandbox: unknown mode %q", mode)
+}
+
+// writablePaths returns the absolute writable paths of t: the state
+// directory and the existing entries of s.Writable, which must stay inside
+// the repository.
+func (s ExecSandbox) writablePaths(t sandboxTarget) ([]string, error) {
+	var out []string
+	if t.stateDir != "" {
+		if err := os.MkdirAll(t.stateDir, 0o755); err != nil {
+			return nil, err
+		}
+		out = append(out, t.stateDir)
+	}
+	for _, w := range s.Writable {
+		p := scanner.OSPath(t.repoRoot, w)
+		if _, ok := scanner.RelPath(t.repoRoot, p); !ok {
+			return nil, fmt.Errorf("sandbox: writable path %q is outside the repository", w)
+		}
+		if _, err := os.Stat(p); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// cacheDirs returns the directory that variable key of env names, if it
+// exists, so the sandbox can mount it.
+func cacheDirs(env []string, key string) []string {
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, key+"="); ok && filepath.IsAbs(v) {
+			if fi, err := os.Stat(v); err == nil && fi.IsDir() {
+				return []string{v}
+			}
+		}
+	}
+	return nil
+}
+
+// sandboxed rewrites c to run in its sandbox. It is called right before
+// the process starts so the Dir the skill set applies.
+func (c *Cmd) sandboxed() error {
+	s := c.sandbox.Resolved()
+	if !s.Enabled() {
+		return nil
+	}
+	mode := s.Mode
+	if mode == SandboxEnvOnly {
+		c.log.Warn("neither bwrap nor docker with an image is available; only the environment is restricted", "name", c.Args[0])
+	}
+	dir := c.Dir
+	if dir == "" {
+		dir = c.repoRoot
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	root, err := filepath.Abs(c.repoRoot)
+	if err != nil {
+		return err
+	}
+	state := c.stateDir
+	if state != "" {
+		if state, err = filepath.Abs(state); err != nil {
+			return err
+		}
+	}
+
+	full := c.Env
+	if full == nil {
+		full = os.Environ()
+	}
+	env := s.FilterEnv(full)
+	argv := append([]string{c.Args[0]}, c.Args[1:]...)
+	if mode == SandboxBwrap {
+		// /tmp is private and the build cache must be a known directory
+		// to be mounted writable.
+		env = setEnv(env, "TMPDIR", "/tmp")
+		if len(cacheDirs(env, "GOCACHE")) == 0 {
+			if dir, err := os.UserCacheDir(); err == nil {
+				_ = os.MkdirAll(filepath.Join(dir, "go-build"), 0o755)
+				env = setEnv(env, "GOCACHE", filepath.Join(dir, "go-build"))
+			}
+		}
+		// The program is resolved outside, where PATH lookups are known
+		// to work; docker resolves it in the image instead.
+		if c.Err == nil && c.Path != "" {
+			argv[0] = c.Path
+		}
+	}
+	wrapped, err := s.wrap(mode, sandboxTarget{repoRoot: root, stateDir: state, dir: absDir, argv: argv, env: env})
+	if err != nil {
+		return err
+	}
+	if mode != SandboxEnvOnly {
+		path, err := exec.LookPath(wrapped[0])
+		if err != nil {
+			return fmt.Errorf("sandbox: %s not found: %w", wrapped[0], err)
+		}
+		c.Path, c.Args, c.Err = path, wrapped, nil
+	}
+	c.Env = env
+	if mode == SandboxDocker {
+		// The client may need DOCKER_HOST and the like; the container only
+		// gets the variables named with -e.
+		c.Env = full
+	}
+	return nil
+}
+
+// setEnv returns env with key set to value.
+func setEnv(env []string, key, value string) []string {
+	out := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, key+"=") {
+			out = append(out, kv)
+		}
+	}
+	return append(out, key+"="+value)
+}
@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/config"
+)
+
+func TestExecSandbox_FilterEnv(t *testing.T) {
+	s := ExecSandbox{Mode: SandboxAuto, Env: []string{"CI"}}
+	env := s.FilterEnv([]string{"PATH=/bin", "AWS_SECRET_ACCESS_KEY=x", "GOFLAGS=-mod=mod", "CI=true", "GITHUB_TOKEN=t"})
+	assert.Equal(t, []string{"PATH=/bin", "GOFLAGS=-mod=mod", "CI=true"}, env)
+
+	assert.Equal(t, "auto network=off", s.String())
+	assert.Equal(t, "bwrap network=on writable=bin,dist", ExecSandbox{Mode: SandboxBwrap, Network: true, Writable: []string{"bin", "dist"}}.String())
+	assert.Empty(t, ExecSandbox{Mode: SandboxOff}.String())
+	assert.Equal(t, "env-only", ExecSandbox{Mode: SandboxEnvOnly, Writable: []string{"bin"}}.String(), "nothing but the environment is isolated")
+	assert.False(t, ExecSandbox{}.Enabled())
+}
+
+func TestExecSandbox_Resolve(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	auto := ExecSandbox{Mode: SandboxAuto}
+	assert.Equal(t, SandboxBwrap, auto.resolve(installed("bwrap", "docker")))
+	assert.Equal(t, SandboxEnvOnly, auto.resolve(installed("docker")), "docker needs an image")
+	auto.Image = "golang:1.24"
+	assert.Equal(t, SandboxDocker, auto.resolve(installed("docker")))
+	assert.Equal(t, SandboxEnvOnly, auto.resolve(installed()))
+	assert.Equal(t, SandboxBwrap, ExecSandbox{Mode: SandboxBwrap}.resolve(installed()), "explicit modes are not resolved")
+}
+
+func TestExecSandbox_Wrap(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bin"), 0o755))
+	target := sandboxTarget{
+		repoRoot: root,
+		stateDir: filepath.Join(root, ".cortex", "run"),
+		dir:      filepath.Join(root, "sub"),
+		argv:     []string{"/usr/bin/go", "test", "./..."},
+		env:      []string{"PATH=/bin", "GOFLAGS=-mod=mod"},
+	}
+	s := ExecSandbox{Mode: SandboxBwrap, Writable: []string{"bin", "missing"}}
+
+	argv, err := s.wrap(SandboxBwrap, target)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"bwrap", "--die-with-parent", "--unshare-pid", "--unshare-ipc", "--unshare-net",
+		"--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc", "--tmpfs", "/tmp",
+		"--ro-bind", root, root,
+		"--bind", target.stateDir, target.stateDir,
+		"--bind", filepath.Join(root, "bin"), filepath.Join(root, "bin"),
+		"--chdir", target.dir, "--", "/usr/bin/go", "test", "./...",
+	}, argv)
+	assert.DirExists(t, target.stateDir, "the state directory is created to be mounted")
+
+	s.Image = "golang:1.24"
+	s.Network = true
+	target.argv[0] = "go"
+	argv, err = s.wrap(SandboxDocker, target)
+	require.NoError(t, err)
+	joined := strings.Join(argv, " ")
+	assert.True(t, strings.HasPrefix(joined, "docker run --rm --tmpfs /tmp "), joined)
+	assert.NotContains(t, joined, "--network none")
+	assert.Contains(t, joined, "-v "+root+":"+root+":ro")
+	assert.Contains(t, joined, "-v "+target.stateDir+":"+target.stateDir+" ")
+	assert.Contains(t, joined, "-e GOCACHE=/tmp/go-build -e GOFLAGS -w "+target.dir+" golang:1.24 go test ./...")
+
+	argv, err = s.wrap(SandboxEnvOnly, target)
+	require.NoError(t, err)
+	assert.Equal(t, target.argv, argv)
+
+	_, err = ExecSandbox{Writable: []string{"../outside"}}.wrap(SandboxBwrap, target)
+	assert.ErrorContains(t, err, "outside the repository")
+}
+
+func TestDepsCommand_SandboxEnvOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	// Neither bubblewrap nor docker is found on this PATH, so auto only
+	// restricts the environment.
+	t.Setenv("PATH", "/bin:/usr/bin")
+	if _, err := os.Stat("/usr/bin/bwrap"); err == nil {
+		t.Skip("bubblewrap is installed")
+	}
+	t.Setenv("CORTEX_TEST_SECRET", "s3cret")
+	t.Setenv("CORTEX_TEST_PASS", "kept")
+
+	root := t.TempDir()
+	deps := &Deps{RepoRoot: root, Env: EnvPolicy{Inherit: true}, Sandbox: ExecSandbox{Mode: SandboxAuto, Env: []string{"CORTEX_TEST_PASS"}}}
+	out, err := deps.Command(context.Background(), "sh", "-c", `echo "[$CORTEX_TEST_SECRET][$CORTEX_TEST_PASS]"`).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "[][kept]\n", string(out))
+}
+
+func TestApplyConfig_Sandbox(t *testing.T) {
+	r := NewRunner(nil, NewStateStore(t.TempDir()), &Deps{RepoRoot: "/repo"})
+	require.NoError(t, r.ApplyConfig(config.Skills{Sandbox: map[string]config.Sandbox{
+		"*":       {Mode: "auto", Writable: []string{".cortex/bin"}},
+		"test:go": {Mode: "off"},
+	}}))
+	assert.Equal(t, ExecSandbox{Mode: SandboxAuto, Writable: []string{".cortex/bin"}}, r.skillDeps("lint:gofumpt").Sandbox)
+	assert.False(t, r.skillDeps("test:go@services/api").Sandbox.Enabled())
+
+	for _, tc := range []struct {
+		sandbox config.Sandbox
+		err     string
+	}{
+		{config.Sandbox{Mode: "jail"}, "skills.sandbox.x: mode must be off, auto, bwrap or docker"},
+		{config.Sandbox{Mode: "docker"}, "skills.sandbox.x: docker mode needs an image"},
+		{config.Sandbox{Mode: "auto", Writable: []string{"../up"}}, `skills.sandbox.x: writable path "../up" must be inside the repository`},
+	} {
+		assert.EqualError(t, r.ApplyConfig(config.Skills{Sandbox: map[string]config.Sandbox{"x": tc.sandbox}}), tc.err)
+	}
+}
+
+func TestRunner_RecordsResolvedSandbox(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	s1 := &MockSkill{id: "s1", result: SkillResult{Skill: "s1", Status: StatusPass}}
+	r := NewRunner([]Skill{s1}, NewStateStore(t.TempDir()), &Deps{})
+	r.SetOutput(io.Discard)
+	r.SetSandbox(map[string]ExecSandbox{"*": {Mode: SandboxAuto}})
+	require.NoError(t, r.RunAll(context.Background()))
+	require.Len(t, r.Results(), 1)
+	assert.Equal(t, "env-only", r.Results()[0].Sandbox, "auto without bubblewrap or docker")
+}
@@ -2,7 +2,9 @@ package runner
 
 import (
 	"context"
+	"log/slog"
 
+	"github.com/bartekus/cortex/internal/log"
 	"github.com/bartekus/cortex/internal/scanner"
 )
 
@@ -12,10 +14,52 @@ type Deps struct {
 	StateDir      string
 	Scanner       *scanner.Scanner
 	FailOnWarning bool
-	TargetFiles   []string // Files to process (if empty, process all tracked files)
+	Commit        string       // HEAD commit SHA, recorded in run history (empty outside git)
+	Fingerprint   string       // Digest of the repo fingerprint before the run (empty outside git)
+	TargetFiles   []string     // Files to process (if empty, process all tracked files)
+	Limits        ExecLimits   // Resource limits of the running skill; see Command
+	Sandbox       ExecSandbox  // Sandbox of the running skill's processes; see Command
+	Env           EnvPolicy    // Environment of the running skill's processes; see Environ
+	Log           *slog.Logger // Diagnostics to stderr; nil discards them (see Logger)
 	// Add other deps like Registry later
 }
 
+// Logger returns d.Log, or a logger that discards when it is unset.
+func (d *Deps) Logger() *slog.Logger {
+	if d == nil {
+		return log.Discard()
+	}
+	return log.OrDiscard(d.Log)
+}
+
+// Path returns the OS path of the repository path rel, e.g. a file the
+// scanner listed. Skills join repository paths only through it.
+func (d *Deps) Path(rel string) string {
+	return scanner.OSPath(d.RepoRoot, rel)
+}
+
+// Targets returns the files of files the run checks: all of them, or only
+// those in TargetFiles when paths, --staged or --files0 select a subset.
+// File-scanning skills pass the files they report on through it; files
+// they only read for context, like the specs linking to a doc, are not
+// filtered.
+func (d *Deps) Targets(files []string) []string {
+	if len(d.TargetFiles) == 0 {
+		return files
+	}
+	selected := make(map[string]bool, len(d.TargetFiles))
+	for _, f := range d.TargetFiles {
+		selected[scanner.RepoPath(f)] = true
+	}
+	var out []string
+	for _, f := range files {
+		if selected[f] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
 // Skill defines a unit of work in the migration runner.
 type Skill interface {
 	// ID returns the unique identifier (e.g. "lint:gofumpt").
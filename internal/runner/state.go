@@ -3,8 +3,13 @@ package runner
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/schemas"
 )
 
 // StateStore handles reading and writing runner state.
@@ -21,27 +26,44 @@ func (s *StateStore) lastRunPath() string {
 	return filepath.Join(s.baseDir, "last-run.json")
 }
 
-// ReadLastRun loads the last execution summary.
+// skillPath returns the result file of a skill. The "/" of module skill IDs
+// is escaped so every result stays directly under skills/.
+func (s *StateStore) skillPath(skillID string) string {
+	return filepath.Join(s.baseDir, "skills", strings.ReplaceAll(skillID, "/", "%2F")+".json")
+}
+
+// ReadLastRun loads the last execution summary, migrating files of older
+// schema versions. It fails with a *VersionError for files of a newer
+// cortex and with ErrSkillsChanged when the skill results do not match the
+// recorded checksum.
 func (s *StateStore) ReadLastRun() (*LastRun, error) {
 	path := s.lastRunPath()
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
 		return nil, nil // Not found is clean state
 	}
 	if err != nil {
-		return nil, fmt.Errorf("opening last run file: %w", err)
+		return nil, fmt.Errorf("reading last run file: %w", err)
 	}
-	defer func() { _ = f.Close() }()
 
-	var last LastRun
-	if err := json.NewDecoder(f).Decode(&last); err != nil {
-		return nil, fmt.Errorf("decoding last run: %w", err)
+	last, err := decodeLastRun(path, data)
+	if err != nil {
+		return nil, err
+	}
+	if last.SkillsChecksum != "" {
+		sum, err := s.skillsChecksum()
+		if err != nil {
+			return nil, fmt.Errorf("checksumming skill results: %w", err)
+		}
+		if sum != last.SkillsChecksum {
+			return nil, fmt.Errorf("%w: a run was interrupted or overlapped another; re-run the skills or clear the state with `cortex run reset`", ErrSkillsChanged)
+		}
 	}
-	return &last, nil
+	return last, nil
 }
 
 func (s *StateStore) ReadSkill(skillID string) (*SkillResult, error) {
-	path := filepath.Join(s.baseDir, "skills", skillID+".json")
+	path := s.skillPath(skillID)
 	f, err := os.Open(path)
 	if os.IsNotExist(err) {
 		return nil, nil
@@ -51,62 +73,65 @@ func (s *StateStore) ReadSkill(skillID string) (*SkillResult, error) {
 	}
 	defer func() { _ = f.Close() }()
 
-	var res SkillResult
-	if err := json.NewDecoder(f).Decode(&res); err != nil {
+	data, err := io.ReadAll(f)
+	if err != nil {
 		return nil, err
 	}
-	return &res, nil
-}
 
-// WriteLastRun saves the execution summary.
-func (s *StateStore) WriteLastRun(last LastRun) (err error) {
-	path := s.lastRunPath()
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
+	var res SkillResult
+	if err := schemas.Decode(schemas.SkillResult, data, &res); err != nil {
+		return nil, fmt.Errorf("decoding skill result %s: %w", skillID, err)
 	}
+	return &res, nil
+}
 
-	f, err := os.Create(path)
+// WriteLastRun saves the execution summary with the checksum of the skill
+// results written so far.
+func (s *StateStore) WriteLastRun(last LastRun) error {
+	last.SchemaVersion = LastRunSchemaVersion
+	sum, err := s.skillsChecksum()
 	if err != nil {
-		return err
+		return fmt.Errorf("checksumming skill results: %w", err)
 	}
-	defer func() {
-		cerr := f.Close()
-		if err == nil {
-			err = cerr
-		}
-	}()
-
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(last)
+	last.SkillsChecksum = sum
+	return writeJSON(s.lastRunPath(), last)
 }
 
 // WriteSkillResult saves a skill's result.
-func (s *StateStore) WriteSkillResult(res SkillResult) (err error) {
-	path := filepath.Join(s.baseDir, "skills", res.Skill+".json")
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
+func (s *StateStore) WriteSkillResult(res SkillResult) error {
+	res.SchemaVersion = StateSchemaVersion
+	return writeJSON(s.skillPath(res.Skill), res)
+}
 
-	f, err := os.Create(path)
+// writeJSON replaces path with the indented JSON of v atomically, so a
+// reader or an interrupted run never sees a partial state file.
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
-	defer func() {
-		cerr := f.Close()
-		if err == nil {
-			err = cerr
-		}
-	}()
-
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(res)
+	return projection.AtomicWrite(path, append(data, '\n'))
 }
 
-// Reset clears the state directory.
+// Reset clears the state directory. The run lock is kept, so a caller
+// holding it keeps out other runs.
 func (s *StateStore) Reset() error {
-	return os.RemoveAll(s.baseDir)
+	entries, err := os.ReadDir(s.baseDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == LockFileName {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.baseDir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // LoadFailedSkills returns a list of skills that failed in the last run.
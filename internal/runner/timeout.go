@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout limits each skill attempt when no "*" timeout is configured.
+const DefaultTimeout = 30 * time.Minute
+
+// cancelGrace is how long a skill may take to return after its deadline
+// before the runner stops waiting for it.
+var cancelGrace = 5 * time.Second
+
+// SetTimeout configures a time limit per skill ID. The "*" entry applies to
+// skills without their own entry; zero means no limit.
+func (r *Runner) SetTimeout(limits map[string]time.Duration) {
+	r.timeout = limits
+}
+
+func (r *Runner) timeoutFor(id string) time.Duration {
+	return lookup(r.timeout, id)
+}
+
+// runOnce runs skill under its time limit. A skill that has not finished
+// when the limit expires is cancelled and reported as StatusTimeout, keeping
+// whatever output it returned on cancellation.
+func (r *Runner) runOnce(ctx context.Context, skill Skill, deps *Deps) SkillResult {
+	limit := r.timeoutFor(skill.ID())
+	if limit <= 0 {
+		return skill.Run(ctx, deps)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+
+	done := make(chan SkillResult, 1)
+	go func() { done <- skill.Run(runCtx, deps) }()
+
+	var res SkillResult
+	select {
+	case res = <-done:
+	case <-runCtx.Done():
+		select {
+		case res = <-done:
+		case <-time.After(cancelGrace):
+			res = SkillResult{Note: fmt.Sprintf("skill did not stop within %s of cancellation", cancelGrace)}
+		}
+	}
+
+	if res.Status != StatusPass && res.Status != StatusSkip &&
+		errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		note := fmt.Sprintf("TIMEOUT after %s", limit)
+		if partial := strings.TrimSpace(res.Note); partial != "" {
+			note += "\n" + partial
+		}
+		res = SkillResult{Skill: skill.ID(), Status: StatusTimeout, ExitCode: ExitExecution, Note: note}
+	}
+	return res
+}
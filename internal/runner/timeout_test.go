@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// BlockingSkill runs until its context is done, or until release is closed
+// when it ignores cancellation.
+type BlockingSkill struct {
+	id        string
+	ignoreCtx bool
+	release   chan struct{}
+}
+
+func (s *BlockingSkill) ID() string { return s.id }
+
+func (s *BlockingSkill) Run(ctx context.Context, deps *Deps) SkillResult {
+	if s.ignoreCtx {
+		<-s.release
+		return SkillResult{Skill: s.id, Status: StatusPass}
+	}
+	<-ctx.Done()
+	return SkillResult{Skill: s.id, Status: StatusFail, ExitCode: ExitValidation, Note: "partial output\n"}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	grace := cancelGrace
+	cancelGrace = 20 * time.Millisecond
+	defer func() { cancelGrace = grace }()
+
+	store := NewStateStore(t.TempDir())
+	hung := &BlockingSkill{id: "hung"}
+	stuck := &BlockingSkill{id: "stuck", ignoreCtx: true, release: make(chan struct{})}
+	defer close(stuck.release)
+	quick := &MockSkill{id: "quick", result: SkillResult{Skill: "quick", Status: StatusPass}}
+
+	r := NewRunner([]Skill{hung, stuck, quick}, store, &Deps{})
+	r.SetTimeout(map[string]time.Duration{"*": 10 * time.Millisecond, "quick": 0})
+	err := r.RunAll(context.Background())
+
+	var runErr *RunError
+	require.ErrorAs(t, err, &runErr)
+	assert.Equal(t, []string{"hung", "stuck"}, runErr.Failed)
+	assert.Equal(t, ExitExecution, runErr.ExitCode())
+
+	res, err := store.ReadSkill("hung")
+	require.NoError(t, err)
+	assert.Equal(t, StatusTimeout, res.Status)
+	assert.Equal(t, ExitExecution, res.ExitCode)
+	assert.Equal(t, "TIMEOUT after 10ms\npartial output", res.Note)
+
+	res, err = store.ReadSkill("stuck")
+	require.NoError(t, err)
+	assert.Equal(t, StatusTimeout, res.Status)
+	assert.Contains(t, res.Note, "did not stop within 20ms")
+
+	res, err = store.ReadSkill("quick")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, res.Status)
+}
+
+func TestRunner_TimeoutIsRetried(t *testing.T) {
+	store := NewStateStore(t.TempDir())
+	hung := &BlockingSkill{id: "hung"}
+
+	r := NewRunner([]Skill{hung}, store, &Deps{})
+	r.SetTimeout(map[string]time.Duration{"hung": time.Millisecond})
+	r.SetRetry(map[string]RetryPolicy{"hung": {Count: 1}})
+	require.Error(t, r.RunAll(context.Background()))
+
+	res, err := store.ReadSkill("hung")
+	require.NoError(t, err)
+	assert.Equal(t, StatusTimeout, res.Status)
+	assert.Equal(t, 2, res.Attempts)
+}
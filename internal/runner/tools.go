@@ -0,0 +1,30 @@
+package runner
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// ToolBinDir holds the tools `cortex tools install` installs, relative to
+// the repository root. Skills look there before PATH.
+const ToolBinDir = ".cortex/bin"
+
+// LookTool finds the program name: in ToolBinDir of repoRoot, then on PATH.
+// A tool of ToolBinDir is returned as an absolute path so it runs from any
+// directory.
+func LookTool(repoRoot, name string) (string, error) {
+	if repoRoot != "" {
+		if p, err := exec.LookPath(filepath.Join(scanner.OSPath(repoRoot, ToolBinDir), name)); err == nil {
+			return filepath.Abs(p)
+		}
+	}
+	return exec.LookPath(name)
+}
+
+// LookTool finds the program name for a skill, preferring the tools
+// installed in the repository's ToolBinDir; see LookTool.
+func (d *Deps) LookTool(name string) (string, error) {
+	return LookTool(d.RepoRoot, name)
+}
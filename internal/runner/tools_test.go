@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: CLI_COMMAND_TOOLS
+// Spec: spec/cli/tools.md
+
+func TestLookTool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the tool")
+	}
+	root := t.TempDir()
+	pathDir := t.TempDir()
+	t.Setenv("PATH", pathDir)
+	write := func(path string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755))
+	}
+
+	_, err := LookTool(root, "fmt-tool")
+	assert.Error(t, err)
+
+	write(filepath.Join(pathDir, "fmt-tool"))
+	got, err := LookTool(root, "fmt-tool")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(pathDir, "fmt-tool"), got)
+
+	write(filepath.Join(root, ".cortex", "bin", "fmt-tool"))
+	deps := &Deps{RepoRoot: root}
+	got, err = deps.LookTool("fmt-tool")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".cortex", "bin", "fmt-tool"), got, "the repository's tool wins over PATH")
+
+	// Without a repository only PATH is searched.
+	got, err = (&Deps{}).LookTool("fmt-tool")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(pathDir, "fmt-tool"), got)
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// ToolsLockFile records the versions and binary digests of the tools
+// `cortex tools install` installed, relative to the repository root. It is
+// meant to be committed so every machine runs the same tool builds.
+const ToolsLockFile = ".cortex/tools.lock"
+
+// ToolsLockSchemaVersion is the schema_version of ToolsLockFile.
+const ToolsLockSchemaVersion = "1"
+
+// CodeVersionMismatch starts the note of a tool that does not match
+// ToolsLockFile.
+const CodeVersionMismatch = "VERSION_MISMATCH"
+
+// ToolsLock is the content of ToolsLockFile.
+type ToolsLock struct {
+	SchemaVersion string       `json:"schema_version"`
+	Tools         []LockedTool `json:"tools"`
+}
+
+// LockedTool is the locked build of one tool.
+type LockedTool struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+	// Digests maps a platform ("linux/amd64", see Platform) to the
+	// FileDigest of the binary built for it.
+	Digests map[string]string `json:"digests"`
+}
+
+// Platform returns the key of the running platform in LockedTool.Digests.
+func Platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// ReadToolsLock reads the ToolsLockFile of repoRoot; nil when there is none.
+func ReadToolsLock(repoRoot string) (*ToolsLock, error) {
+	data, err := os.ReadFile(scanner.OSPath(repoRoot, ToolsLockFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ToolsLockFile, err)
+	}
+	var lock ToolsLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ToolsLockFile, err)
+	}
+	return &lock, nil
+}
+
+// WriteToolsLock writes lock to the ToolsLockFile of repoRoot, tools sorted
+// by name.
+func WriteToolsLock(repoRoot string, lock *ToolsLock) error {
+	lock.SchemaVersion = ToolsLockSchemaVersion
+	sort.Slice(lock.Tools, func(i, j int) bool { return lock.Tools[i].Name < lock.Tools[j].Name })
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := scanner.OSPath(repoRoot, ToolsLockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Tool returns the entry of name, or nil.
+func (l *ToolsLock) Tool(name string) *LockedTool {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Tools {
+		if l.Tools[i].Name == name {
+			return &l.Tools[i]
+		}
+	}
+	return nil
+}
+
+// Lock records digest as the build of the tool at version for the running
+// platform. A new version replaces the digests of every platform.
+func (l *ToolsLock) Lock(name, pkg, version, digest string) {
+	t := l.Tool(name)
+	if t == nil {
+		l.Tools = append(l.Tools, LockedTool{Name: name})
+		t = &l.Tools[len(l.Tools)-1]
+	}
+	if t.Version != version || t.Package != pkg || t.Digests == nil {
+		t.Package, t.Version, t.Digests = pkg, version, map[string]string{}
+	}
+	t.Digests[Platform()] = digest
+}
+
+// FileDigest returns the "sha256:<hex>" digest of the file at path.
+func FileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyTool checks the binary of name at path against the ToolsLockFile
+// of repoRoot. It passes when there is no lock, the tool is not locked or
+// the lock has no digest for the running platform. A mismatch is reported
+// as "<path>: VERSION_MISMATCH: ..." so it becomes a finding on the binary.
+func VerifyTool(repoRoot, name, path string) error {
+	lock, err := ReadToolsLock(repoRoot)
+	if err != nil {
+		return err
+	}
+	t := lock.Tool(name)
+	if t == nil || t.Digests[Platform()] == "" {
+		return nil
+	}
+	want := t.Digests[Platform()]
+	got, err := FileDigest(path)
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", path, CodeVersionMismatch, err)
+	}
+	if got != want {
+		return fmt.Errorf("%s: %s: %s is %s, %s locks %s@%s as %s for %s. Run: cortex tools install %s",
+			path, CodeVersionMismatch, name, shortDigest(got), ToolsLockFile, name, t.Version, shortDigest(want), Platform(), name)
+	}
+	return nil
+}
+
+// VerifyTool checks the binary of name at path against the repository's
+// ToolsLockFile before a skill runs it; see VerifyTool.
+func (d *Deps) VerifyTool(name, path string) error {
+	return VerifyTool(d.RepoRoot, name, path)
+}
+
+// shortDigest abbreviates a FileDigest for messages.
+func shortDigest(d string) string {
+	if len(d) > len("sha256:")+12 {
+		return d[:len("sha256:")+12]
+	}
+	return d
+}
@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: CLI_COMMAND_TOOLS
+// Spec: spec/cli/tools.md
+
+func TestToolsLock_Lock(t *testing.T) {
+	var lock ToolsLock
+	lock.Lock("gofumpt", "mvdan.cc/gofumpt", "v0.6.0", "sha256:aa")
+	lock.Tools[0].Digests["other/arch"] = "sha256:bb"
+
+	lock.Lock("gofumpt", "mvdan.cc/gofumpt", "v0.6.0", "sha256:cc")
+	assert.Equal(t, map[string]string{Platform(): "sha256:cc", "other/arch": "sha256:bb"}, lock.Tool("gofumpt").Digests,
+		"the same version keeps the digests of other platforms")
+
+	lock.Lock("gofumpt", "mvdan.cc/gofumpt", "v0.7.0", "sha256:dd")
+	assert.Equal(t, map[string]string{Platform(): "sha256:dd"}, lock.Tool("gofumpt").Digests,
+		"a new version drops the digests of the old one")
+	assert.Nil(t, lock.Tool("golangci-lint"))
+}
+
+func TestToolsLock_ReadWrite(t *testing.T) {
+	root := t.TempDir()
+	lock, err := ReadToolsLock(root)
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+
+	lock = &ToolsLock{}
+	lock.Lock("golangci-lint", "example.com/golangci-lint", "v2.6.2", "sha256:bb")
+	lock.Lock("gofumpt", "mvdan.cc/gofumpt", "v0.6.0", "sha256:aa")
+	require.NoError(t, WriteToolsLock(root, lock))
+
+	got, err := ReadToolsLock(root)
+	require.NoError(t, err)
+	assert.Equal(t, ToolsLockSchemaVersion, got.SchemaVersion)
+	require.Len(t, got.Tools, 2)
+	assert.Equal(t, "gofumpt", got.Tools[0].Name, "tools are sorted by name")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ToolsLockFile), []byte("{"), 0o644))
+	_, err = ReadToolsLock(root)
+	assert.ErrorContains(t, err, "parsing "+ToolsLockFile)
+}
+
+func TestVerifyTool(t *testing.T) {
+	root := t.TempDir()
+	bin := filepath.Join(root, ".cortex", "bin", "gofumpt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(bin), 0o755))
+	require.NoError(t, os.WriteFile(bin, []byte("build 1"), 0o755))
+	deps := &Deps{RepoRoot: root}
+
+	// Without a lock every binary passes.
+	require.NoError(t, deps.VerifyTool("gofumpt", bin))
+
+	digest, err := FileDigest(bin)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(digest, "sha256:"))
+	lock := &ToolsLock{}
+	lock.Lock("gofumpt", "mvdan.cc/gofumpt", "v0.6.0", digest)
+	require.NoError(t, WriteToolsLock(root, lock))
+	require.NoError(t, deps.VerifyTool("gofumpt", bin))
+	require.NoError(t, deps.VerifyTool("golangci-lint", bin), "unlocked tools pass")
+
+	require.NoError(t, os.WriteFile(bin, []byte("build 2"), 0o755))
+	err = deps.VerifyTool("gofumpt", bin)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), bin+": "+CodeVersionMismatch+": gofumpt is sha256:"), err.Error())
+	assert.Contains(t, err.Error(), "locks gofumpt@v0.6.0 as "+digest[:len("sha256:")+12]+" for "+Platform())
+	assert.Contains(t, err.Error(), "Run: cortex tools install gofumpt")
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package scaffold bootstraps the Cortex governance layout in a repository:
+// the spec skeleton and feature registry, .cortex/config.yaml, the docs index
+// and git hooks.
+//
+// Files that already exist are never overwritten, so running Init again only
+// adds what is missing.
+package scaffold
+
+// Feature: CLI_COMMAND_INIT
+// Spec: spec/cli/init.md
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed all:templates
+var templates embed.FS
+
+// Profiles.
+const (
+	ProfileMinimal = "minimal"
+	ProfileStrict  = "strict"
+)
+
+// Profiles returns the available profiles.
+func Profiles() []string {
+	return []string{ProfileMinimal, ProfileStrict}
+}
+
+// Action is what Init did with one file.
+type Action string
+
+// Actions.
+const (
+	ActionCreated Action = "created"
+	ActionExists  Action = "exists"
+	ActionSkipped Action = "skipped"
+)
+
+// Entry reports one scaffolded file. Path is repo-relative with forward
+// slashes; hooks are reported under .git/hooks.
+type Entry struct {
+	Path   string `json:"path"`
+	Action Action `json:"action"`
+	Note   string `json:"note,omitempty"`
+}
+
+// hooksPrefix marks templates that are installed as git hooks.
+const hooksPrefix = "hooks/"
+
+// Init writes the files of profile below root. The profile's templates
+// override the common ones with the same path. Hooks are installed into
+// .git/hooks and skipped when root has no .git directory. Entries are sorted
+// by path.
+func Init(root, profile string) ([]Entry, error) {
+	files, err := profileFiles(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filepath.Join(root, ".git"))
+	hasGit := err == nil && info.IsDir()
+
+	var entries []Entry
+	for _, rel := range sortedKeys(files) {
+		dest, mode := rel, fs.FileMode(0o644)
+		if strings.HasPrefix(rel, hooksPrefix) {
+			dest, mode = path.Join(".git", rel), 0o755
+			if !hasGit {
+				entries = append(entries, Entry{Path: dest, Action: ActionSkipped, Note: "not a git repository"})
+				continue
+			}
+		}
+
+		entry, err := writeFile(root, dest, files[rel], mode)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// profileFiles maps each template path of profile to its template file.
+func profileFiles(profile string) (map[string]string, error) {
+	known := false
+	for _, p := range Profiles() {
+		known = known || p == profile
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown profile %q (want one of: %s)", profile, strings.Join(Profiles(), ", "))
+	}
+
+	files := map[string]string{}
+	for _, dir := range []string{"common", profile} {
+		base := path.Join("templates", dir)
+		err := fs.WalkDir(templates, base, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			files[strings.TrimPrefix(p, base+"/")] = p
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading templates: %w", err)
+		}
+	}
+	return files, nil
+}
+
+// writeFile creates dest from the template unless it already exists.
+func writeFile(root, dest, tmpl string, mode fs.FileMode) (Entry, error) {
+	target := filepath.Join(root, filepath.FromSlash(dest))
+	if _, err := os.Lstat(target); err == nil {
+		return Entry{Path: dest, Action: ActionExists}, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, fmt.Errorf("checking %s: %w", dest, err)
+	}
+
+	data, err := templates.ReadFile(tmpl)
+	if err != nil {
+		return Entry{}, fmt.Errorf("reading template %s: %w", tmpl, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("creating directory for %s: %w", dest, err)
+	}
+	if err := os.WriteFile(target, data, mode); err != nil {
+		return Entry{}, fmt.Errorf("writing %s: %w", dest, err)
+	}
+	return Entry{Path: dest, Action: ActionCreated}, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Feature: CLI_COMMAND_INIT
+// Spec: spec/cli/init.md
+
+func actions(entries []Entry) map[string]Action {
+	out := map[string]Action{}
+	for _, e := range entries {
+		out[e.Path] = e.Action
+	}
+	return out
+}
+
+func TestInitMinimal(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+
+	entries, err := Init(root, ProfileMinimal)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Action{
+		".cortex/config.yaml":               ActionCreated,
+		".git/hooks/pre-commit":             ActionCreated,
+		"docs/README.md":                    ActionCreated,
+		"spec/features.yaml":                ActionCreated,
+		"spec/schemas/features.schema.json": ActionCreated,
+		"spec/system/contract.md":           ActionCreated,
+	}, actions(entries))
+
+	reg, err := gov.LoadRegistry(filepath.Join(root, "spec", "features.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, reg.Validate())
+	require.NoError(t, reg.ValidateTraceability(root))
+
+	_, err = config.Load(root)
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(root, ".git", "hooks", "pre-commit"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0o100, "hook must be executable")
+}
+
+func TestInitStrictIsIdempotent(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "README.md"), []byte("# Mine\n"), 0o644))
+
+	entries, err := Init(root, ProfileStrict)
+	require.NoError(t, err)
+	got := actions(entries)
+	assert.Equal(t, ActionExists, got["docs/README.md"])
+	assert.Equal(t, ActionCreated, got["docs/adr/README.md"])
+	assert.Equal(t, ActionSkipped, got[".git/hooks/pre-push"])
+
+	cfg, err := config.Load(root)
+	require.NoError(t, err)
+	assert.True(t, cfg.Commits.RequireScope)
+
+	data, err := os.ReadFile(filepath.Join(root, "docs", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Mine\n", string(data))
+
+	again, err := Init(root, ProfileStrict)
+	require.NoError(t, err)
+	for _, e := range again {
+		assert.NotEqual(t, ActionCreated, e.Action, e.Path)
+	}
+}
+
+func TestInitUnknownProfile(t *testing.T) {
+	_, err := Init(t.TempDir(), "lax")
+	assert.ErrorContains(t, err, `unknown profile "lax"`)
+}
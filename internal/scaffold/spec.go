@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package scaffold
+
+// Feature: CLI_COMMAND_SPEC
+// Spec: spec/cli/spec.md
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+var (
+	featureIDRE = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	domainRE    = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+// specsHeading is the index section new spec links are added to.
+const specsHeading = "## Specs"
+
+// SpecOptions describes a new spec. Paths are repo-relative with forward
+// slashes; empty fields take the defaults documented on each.
+type SpecOptions struct {
+	ID     string
+	Domain string
+	// Title defaults to the ID in words ("CLI_COMMAND_X" -> "Cli Command X").
+	Title string
+	// Owner defaults to "unassigned".
+	Owner string
+	// Group defaults to the domain.
+	Group     string
+	DependsOn []string
+	// Path defaults to spec/<domain>/<id in kebab case>.md.
+	Path string
+	// Features defaults to spec/features.yaml.
+	Features string
+	// Index is the doc the spec is linked from. Empty picks the first
+	// existing of docs/<domain>/README.md and docs/README.md.
+	Index string
+}
+
+// SpecResult reports what NewSpec wrote. Index is empty when no index doc
+// was found; Linked is false when the index already linked the spec.
+type SpecResult struct {
+	Path     string `json:"path"`
+	Features string `json:"features"`
+	Index    string `json:"index,omitempty"`
+	Linked   bool   `json:"linked"`
+}
+
+// ErrInvalidSpec marks NewSpec errors caused by the options or the
+// registry rather than by I/O.
+var ErrInvalidSpec = errors.New("invalid spec")
+
+// NewSpec creates a spec with valid frontmatter (status todo), registers
+// the feature as draft/todo and links the spec from the index doc. Nothing
+// is written when the options or the resulting registry are invalid.
+func NewSpec(root string, opts SpecOptions) (SpecResult, error) {
+	opts = specDefaults(opts)
+	if !featureIDRE.MatchString(opts.ID) {
+		return SpecResult{}, fmt.Errorf("%w: feature ID %q must match %s", ErrInvalidSpec, opts.ID, featureIDRE)
+	}
+	if !domainRE.MatchString(opts.Domain) {
+		return SpecResult{}, fmt.Errorf("%w: domain %q must match %s", ErrInvalidSpec, opts.Domain, domainRE)
+	}
+	if !strings.HasPrefix(opts.Path, "spec/"+opts.Domain+"/") || path.Ext(opts.Path) != ".md" || path.Clean(opts.Path) != opts.Path {
+		return SpecResult{}, fmt.Errorf("%w: spec path %q must be a .md file under spec/%s/", ErrInvalidSpec, opts.Path, opts.Domain)
+	}
+
+	specFile := filepath.Join(root, filepath.FromSlash(opts.Path))
+	if _, err := os.Stat(specFile); err == nil {
+		return SpecResult{}, fmt.Errorf("%w: %s already exists", ErrInvalidSpec, opts.Path)
+	}
+
+	featuresFile := filepath.Join(root, filepath.FromSlash(opts.Features))
+	doc, err := gov.LoadDocument(featuresFile)
+	if err != nil {
+		return SpecResult{}, err
+	}
+	err = doc.Add(gov.Feature{
+		ID:             opts.ID,
+		Title:          opts.Title,
+		Governance:     gov.GovDraft,
+		Implementation: gov.ImplTodo,
+		Spec:           opts.Path,
+		Owner:          opts.Owner,
+		Group:          opts.Group,
+		DependsOn:      opts.DependsOn,
+	})
+	if err == nil {
+		err = doc.Validate()
+	}
+	if err != nil {
+		return SpecResult{}, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(specFile), 0o755); err != nil {
+		return SpecResult{}, fmt.Errorf("creating spec directory: %w", err)
+	}
+	if err := os.WriteFile(specFile, []byte(renderSpec(opts)), 0o644); err != nil {
+		return SpecResult{}, fmt.Errorf("writing %s: %w", opts.Path, err)
+	}
+	if err := doc.Write(featuresFile); err != nil {
+		_ = os.Remove(specFile)
+		return SpecResult{}, err
+	}
+
+	res := SpecResult{Path: opts.Path, Features: opts.Features}
+	index, err := resolveIndex(root, opts)
+	if err != nil || index == "" {
+		return res, err
+	}
+	res.Index = index
+	res.Linked, err = linkSpec(root, index, opts)
+	return res, err
+}
+
+func specDefaults(opts SpecOptions) SpecOptions {
+	if opts.Title == "" {
+		words := strings.Split(strings.ToLower(opts.ID), "_")
+		for i, w := range words {
+			if w != "" {
+				words[i] = strings.ToUpper(w[:1]) + w[1:]
+			}
+		}
+		opts.Title = strings.Join(words, " ")
+	}
+	if opts.Owner == "" {
+		opts.Owner = "unassigned"
+	}
+	if opts.Group == "" {
+		opts.Group = opts.Domain
+	}
+	if opts.Path == "" {
+		opts.Path = "spec/" + opts.Domain + "/" + strings.ReplaceAll(strings.ToLower(opts.ID), "_", "-") + ".md"
+	}
+	if opts.Features == "" {
+		opts.Features = "spec/features.yaml"
+	}
+	return opts
+}
+
+func renderSpec(opts SpecOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\nfeature: %s\nversion: v1\nstatus: todo\ndomain: %s\n---\n", opts.ID, opts.Domain)
+	fmt.Fprintf(&b, "# %s\n\n**Feature**: `%s`\n\n", opts.Title, opts.ID)
+	b.WriteString("## Summary\nWhat the feature provides and why.\n\n")
+	b.WriteString("## Behavior\nThe observable contract: inputs, outputs, exit codes and failure modes.\n\n")
+	b.WriteString("## References\n")
+	return b.String()
+}
+
+// resolveIndex returns the index doc to link from, or "" when there is
+// none. An explicit index must exist.
+func resolveIndex(root string, opts SpecOptions) (string, error) {
+	if opts.Index != "" {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(opts.Index))); err != nil {
+			return "", fmt.Errorf("index doc: %w", err)
+		}
+		return opts.Index, nil
+	}
+	for _, candidate := range []string{"docs/" + opts.Domain + "/README.md", "docs/README.md"} {
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(candidate)))
+		if err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("index doc: %w", err)
+		}
+	}
+	return "", nil
+}
+
+// linkSpec adds a list item linking the spec to the index's Specs section,
+// creating the section at the end when missing. It reports false when the
+// index already links the spec.
+func linkSpec(root, index string, opts SpecOptions) (bool, error) {
+	file := filepath.Join(root, filepath.FromSlash(index))
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false, fmt.Errorf("reading index doc: %w", err)
+	}
+	target, err := filepath.Rel(filepath.Dir(file), filepath.Join(root, filepath.FromSlash(opts.Path)))
+	if err != nil {
+		return false, fmt.Errorf("resolving spec link: %w", err)
+	}
+	target = filepath.ToSlash(target)
+	if strings.Contains(string(data), "]("+target+")") {
+		return false, nil
+	}
+	item := fmt.Sprintf("- [%s](%s): `%s`", opts.Title, target, opts.ID)
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	section := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == specsHeading {
+			section = i
+			break
+		}
+	}
+	if section < 0 {
+		lines = append(lines, "", specsHeading, "", item)
+	} else {
+		// Insert after the last non-blank line of the section.
+		at := section + 1
+		for i := section + 1; i < len(lines) && !strings.HasPrefix(lines[i], "#"); i++ {
+			if strings.TrimSpace(lines[i]) != "" {
+				at = i + 1
+			}
+		}
+		insert := []string{item}
+		if at == section+1 {
+			insert = append([]string{""}, insert...)
+		}
+		if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+			insert = append(insert, "")
+		}
+		lines = append(lines[:at], append(insert, lines[at:]...)...)
+	}
+	if err := projection.AtomicWrite(file, []byte(strings.Join(lines, "\n")+"\n")); err != nil {
+		return false, fmt.Errorf("writing index doc: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/specschema"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Feature: CLI_COMMAND_SPEC
+// Spec: spec/cli/spec.md
+
+func TestNewSpec(t *testing.T) {
+	root := t.TempDir()
+	_, err := Init(root, ProfileMinimal)
+	require.NoError(t, err)
+
+	res, err := NewSpec(root, SpecOptions{ID: "CLI_COMMAND_DEPLOY", Domain: "cli", DependsOn: []string{"CORE_REPO_CONTRACT"}})
+	require.NoError(t, err)
+	assert.Equal(t, SpecResult{
+		Path:     "spec/cli/cli-command-deploy.md",
+		Features: "spec/features.yaml",
+		Index:    "docs/README.md",
+		Linked:   true,
+	}, res)
+
+	spec, err := specschema.LoadSpec(filepath.Join(root, "spec", "cli", "cli-command-deploy.md"))
+	require.NoError(t, err)
+	spec.Path = res.Path
+	require.NoError(t, specschema.ValidateSpec(spec))
+
+	reg, err := gov.LoadRegistry(filepath.Join(root, "spec", "features.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, reg.ValidateTraceability(root))
+	last := reg.Features[len(reg.Features)-1]
+	assert.Equal(t, gov.Feature{
+		ID:             "CLI_COMMAND_DEPLOY",
+		Title:          "Cli Command Deploy",
+		Governance:     gov.GovDraft,
+		Implementation: gov.ImplTodo,
+		Spec:           "spec/cli/cli-command-deploy.md",
+		Owner:          "unassigned",
+		Group:          "cli",
+		Tests:          []string{},
+		DependsOn:      []string{"CORE_REPO_CONTRACT"},
+	}, last)
+
+	_, err = NewSpec(root, SpecOptions{ID: "CLI_COMMAND_SHIP", Domain: "cli", Title: "Ship"})
+	require.NoError(t, err)
+	index, err := os.ReadFile(filepath.Join(root, "docs", "README.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "## Specs\n\n"+
+		"- [Cli Command Deploy](../spec/cli/cli-command-deploy.md): `CLI_COMMAND_DEPLOY`\n"+
+		"- [Ship](../spec/cli/cli-command-ship.md): `CLI_COMMAND_SHIP`\n")
+}
+
+func TestNewSpecLinksIntoExistingSection(t *testing.T) {
+	root := t.TempDir()
+	_, err := Init(root, ProfileMinimal)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs", "cli"), 0o755))
+	index := filepath.Join(root, "docs", "cli", "README.md")
+	require.NoError(t, os.WriteFile(index, []byte("# CLI\n\n## Specs\n- [Old](old.md)\n## Guides\n"), 0o644))
+
+	res, err := NewSpec(root, SpecOptions{ID: "CLI_X", Domain: "cli", Path: "spec/cli/x.md"})
+	require.NoError(t, err)
+	assert.Equal(t, "docs/cli/README.md", res.Index)
+
+	data, err := os.ReadFile(index)
+	require.NoError(t, err)
+	assert.Equal(t, "# CLI\n\n## Specs\n- [Old](old.md)\n- [Cli X](../../spec/cli/x.md): `CLI_X`\n\n## Guides\n", string(data))
+}
+
+func TestNewSpecRejectsInvalidRegistry(t *testing.T) {
+	root := t.TempDir()
+	_, err := Init(root, ProfileMinimal)
+	require.NoError(t, err)
+	before, err := os.ReadFile(filepath.Join(root, "spec", "features.yaml"))
+	require.NoError(t, err)
+
+	for _, opts := range []SpecOptions{
+		{ID: "CORE_REPO_CONTRACT", Domain: "core"},
+		{ID: "CLI_Y", Domain: "cli", DependsOn: []string{"MISSING"}},
+		{ID: "cli_y", Domain: "cli"},
+		{ID: "CLI_Y", Domain: "cli", Path: "spec/other/y.md"},
+	} {
+		_, err := NewSpec(root, opts)
+		assert.ErrorIs(t, err, ErrInvalidSpec, opts.ID)
+	}
+
+	after, err := os.ReadFile(filepath.Join(root, "spec", "features.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	assert.NoDirExists(t, filepath.Join(root, "spec", "cli"))
+}
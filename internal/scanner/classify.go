@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultMaxFileBytes is the size limit of the zero Policy; it matches the
+// XRAY LOC cap.
+const DefaultMaxFileBytes int64 = 2 * 1024 * 1024
+
+// SniffBytes is how much of a file is searched for a NUL byte to tell
+// binary files from text, as git does.
+const SniffBytes = 8000
+
+// Class is the content classification of a file.
+type Class string
+
+// Classes of files. Only text files have their content read; binary and
+// large files are recorded by metadata (path, size, hash) only.
+const (
+	ClassText   Class = "text"
+	ClassBinary Class = "binary"
+	ClassLarge  Class = "too_large"
+)
+
+// Policy is the binary and large-file policy shared by context chunking,
+// cortex grep and snapshots.
+type Policy struct {
+	// MaxFileBytes is the size above which a file is large. Zero means
+	// DefaultMaxFileBytes; a negative value disables the limit.
+	MaxFileBytes int64
+}
+
+// Limit returns the effective size limit, or 0 when there is none.
+func (p Policy) Limit() int64 {
+	switch {
+	case p.MaxFileBytes == 0:
+		return DefaultMaxFileBytes
+	case p.MaxFileBytes < 0:
+		return 0
+	}
+	return p.MaxFileBytes
+}
+
+// TooLarge reports whether a file of size bytes is over the limit.
+func (p Policy) TooLarge(size int64) bool {
+	limit := p.Limit()
+	return limit > 0 && size > limit
+}
+
+// Classify classifies a file of size bytes whose content starts with head;
+// head needs to hold no more than the first SniffBytes bytes.
+func (p Policy) Classify(size int64, head []byte) Class {
+	switch {
+	case p.TooLarge(size):
+		return ClassLarge
+	case IsBinary(head):
+		return ClassBinary
+	}
+	return ClassText
+}
+
+// ClassifyFile classifies the file at path, reading at most SniffBytes of
+// it. It returns the class and the size of the file.
+func (p Policy) ClassifyFile(path string) (Class, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // callers pass repository files
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	if p.TooLarge(info.Size()) {
+		return ClassLarge, info.Size(), nil
+	}
+	head, err := io.ReadAll(io.LimitReader(f, SniffBytes))
+	if err != nil {
+		return "", 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return p.Classify(info.Size(), head), info.Size(), nil
+}
+
+// IsBinary reports whether data looks binary: a NUL byte in its first
+// SniffBytes bytes.
+func IsBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), SniffBytes)], 0) >= 0
+}
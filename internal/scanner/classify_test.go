@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Limit(t *testing.T) {
+	assert.Equal(t, DefaultMaxFileBytes, Policy{}.Limit())
+	assert.Equal(t, int64(10), Policy{MaxFileBytes: 10}.Limit())
+	assert.Equal(t, int64(0), Policy{MaxFileBytes: -1}.Limit())
+
+	assert.True(t, Policy{MaxFileBytes: 10}.TooLarge(11))
+	assert.False(t, Policy{MaxFileBytes: 10}.TooLarge(10))
+	assert.False(t, Policy{MaxFileBytes: -1}.TooLarge(1<<40))
+}
+
+func TestPolicy_ClassifyFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	p := Policy{MaxFileBytes: 16 * 1024}
+
+	tests := []struct {
+		path string
+		want Class
+	}{
+		{write("a.txt", "hello\n"), ClassText},
+		{write("b.bin", "PNG\x00\x01"), ClassBinary},
+		{write("late.bin", strings.Repeat("a", SniffBytes)+"\x00"), ClassText},
+		{write("big.txt", strings.Repeat("a", 16*1024+1)), ClassLarge},
+	}
+	for _, tt := range tests {
+		class, size, err := p.ClassifyFile(tt.path)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, class, filepath.Base(tt.path))
+		info, _ := os.Stat(tt.path)
+		assert.Equal(t, info.Size(), size)
+	}
+
+	_, _, err := p.ClassifyFile(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -15,6 +15,18 @@ type FilterOptions struct {
 	// IncludeExtensions is a list of extensions to include (e.g., ".go").
 	// If empty, all extensions are included.
 	IncludeExtensions []string
+
+	// IncludeDirs limits the result to files under these directories,
+	// given as slash-separated paths relative to the repo root: "spec"
+	// includes "spec/a.md" and "spec/cli/b.md", but not "specs/c.md".
+	// If empty, all directories are included.
+	IncludeDirs []string
+
+	// IncludePatterns limits the result to files matching one of these
+	// globs; ExcludePatterns drops files matching any of them. See
+	// MatchGlob.
+	IncludePatterns []string
+	ExcludePatterns []string
 }
 
 // DefaultExcludeDirs returns the standard list of directories to exclude in Cortex.
@@ -53,6 +65,15 @@ func FilterFiles(paths []string, opts FilterOptions) []string {
 		if !shouldIncludeExtension(path, opts.IncludeExtensions) {
 			continue
 		}
+		if len(opts.IncludeDirs) > 0 && !inAnyDir(path, opts.IncludeDirs) {
+			continue
+		}
+		if len(opts.IncludePatterns) > 0 && !matchAnyGlob(opts.IncludePatterns, path) {
+			continue
+		}
+		if matchAnyGlob(opts.ExcludePatterns, path) {
+			continue
+		}
 		filtered = append(filtered, path)
 	}
 
@@ -88,3 +109,27 @@ func shouldIncludeExtension(path string, extensions []string) bool {
 	}
 	return false
 }
+
+// inAnyDir returns true if path is under one of dirs.
+func inAnyDir(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(path, dirPrefix(dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirPrefix returns the prefix shared by the paths under dir.
+func dirPrefix(dir string) string {
+	return strings.Trim(dir, "/") + "/"
+}
+
+func matchAnyGlob(globs []string, path string) bool {
+	for _, g := range globs {
+		if MatchGlob(g, path) {
+			return true
+		}
+	}
+	return false
+}
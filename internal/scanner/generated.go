@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// generatedMarker matches the standard header of generated files
+// (https://go.dev/s/generatedcode) once the comment marker is stripped.
+var generatedMarker = regexp.MustCompile(`^Code generated .* DO NOT EDIT\.?`)
+
+// commentPrefixes are the comment markers a generated header may start
+// with, longest first.
+var commentPrefixes = []string{"<!--", "//", "/*", "--", "#", ";", "*"}
+
+// markerExtensions are the extensions (or base names) of files whose
+// comment syntax can carry the generated header.
+var markerExtensions = map[string]bool{
+	".c": true, ".cc": true, ".cpp": true, ".cs": true, ".go": true, ".h": true, ".java": true,
+	".js": true, ".jsx": true, ".kt": true, ".proto": true, ".rs": true, ".swift": true,
+	".ts": true, ".tsx": true, ".css": true, ".scss": true,
+	".bash": true, ".py": true, ".rb": true, ".sh": true, ".tf": true, ".toml": true, ".yaml": true, ".yml": true,
+	".lua": true, ".sql": true, ".md": true, ".html": true, ".xml": true,
+	"Dockerfile": true, "Makefile": true,
+}
+
+// CanCarryMarker reports whether the file at rel has a comment syntax the
+// generated header can be written in.
+func CanCarryMarker(rel string) bool {
+	return markerExtensions[path.Base(rel)] || markerExtensions[path.Ext(rel)]
+}
+
+// HasGeneratedMarker reports whether src starts with a "Code generated ...
+// DO NOT EDIT." comment: the line must come before the first line that is
+// neither blank nor a comment, within the first SniffBytes bytes.
+func HasGeneratedMarker(src []byte) bool {
+	if len(src) > SniffBytes {
+		src = src[:SniffBytes]
+	}
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		text, ok := stripComment(line)
+		if !ok {
+			return false
+		}
+		if generatedMarker.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileHasGeneratedMarker reports whether the file at the OS path p starts
+// with the generated header, reading at most SniffBytes of it.
+func FileHasGeneratedMarker(p string) (bool, error) {
+	f, err := os.Open(p) //nolint:gosec // callers pass repository files
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+	head, err := io.ReadAll(io.LimitReader(f, SniffBytes))
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", p, err)
+	}
+	return HasGeneratedMarker(head), nil
+}
+
+func stripComment(line string) (string, bool) {
+	for _, p := range commentPrefixes {
+		if strings.HasPrefix(line, p) {
+			return strings.TrimSpace(strings.TrimPrefix(line, p)), true
+		}
+	}
+	return "", false
+}
+
+// Generated tells generated files from authored ones: a file is generated
+// when it matches one of the configured globs (files.generated, with the
+// semantics of MatchGlob) or carries the generated header. Headers are read
+// once per file; a Generated is safe for concurrent use.
+type Generated struct {
+	repoRoot string
+	globs    []string
+
+	mu    sync.Mutex
+	cache map[string]bool
+}
+
+// NewGenerated returns the detector for the repository at repoRoot.
+func NewGenerated(repoRoot string, globs []string) *Generated {
+	return &Generated{repoRoot: repoRoot, globs: globs, cache: map[string]bool{}}
+}
+
+// Globs returns the configured globs.
+func (g *Generated) Globs() []string { return g.globs }
+
+// Declared reports whether the repository path rel matches a configured
+// glob.
+func (g *Generated) Declared(rel string) bool {
+	for _, glob := range g.globs {
+		if MatchGlob(glob, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGenerated reports whether the file at the repository path rel is
+// generated. Unreadable files are not.
+func (g *Generated) IsGenerated(rel string) bool {
+	if g == nil || rel == "" {
+		return false
+	}
+	if g.Declared(rel) {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if v, ok := g.cache[rel]; ok {
+		return v
+	}
+	v := g.hasMarker(rel)
+	g.cache[rel] = v
+	return v
+}
+
+func (g *Generated) hasMarker(rel string) bool {
+	if !CanCarryMarker(rel) {
+		return false
+	}
+	ok, err := FileHasGeneratedMarker(OSPath(g.repoRoot, rel))
+	return err == nil && ok
+}
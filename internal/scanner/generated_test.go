@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasGeneratedMarker(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{"go", "// Code generated by mockgen. DO NOT EDIT.\n\npackage mocks\n", true},
+		{"after license", "// SPDX-License-Identifier: MIT\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: a.proto\n\npackage pb\n", true},
+		{"python", "#!/usr/bin/env python\n# Code generated by gen.py. DO NOT EDIT.\nimport os\n", true},
+		{"html comment", "<!-- Code generated by cortex context agents. DO NOT EDIT. -->\n# Agents\n", true},
+		{"block comment", "/*\n * Code generated by x. DO NOT EDIT.\n */\n", true},
+		{"after code", "package a\n\n// Code generated by x. DO NOT EDIT.\n", false},
+		{"in a string", "package a\n\nconst h = \"// Code generated by x. DO NOT EDIT.\"\n", false},
+		{"not the marker", "// Code written by hand. Feel free to edit.\npackage a\n", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, HasGeneratedMarker([]byte(tt.src)), tt.name)
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		p := OSPath(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	write("api/api.pb.go", "package api\n")
+	write("mocks/store.go", "// Code generated by mockgen. DO NOT EDIT.\npackage mocks\n")
+	write("main.go", "package main\n")
+	write("data.json", "{}\n")
+
+	g := NewGenerated(root, []string{"**/*.pb.go"})
+	assert.True(t, g.Declared("api/api.pb.go"))
+	assert.False(t, g.Declared("mocks/store.go"))
+	assert.True(t, g.IsGenerated("api/api.pb.go"), "declared by glob, header or not")
+	assert.True(t, g.IsGenerated("mocks/store.go"), "detected by header")
+	assert.False(t, g.IsGenerated("main.go"))
+	assert.False(t, g.IsGenerated("data.json"))
+	assert.False(t, g.IsGenerated("missing.go"))
+	assert.False(t, g.IsGenerated(""))
+
+	var none *Generated
+	assert.False(t, none.IsGenerated("mocks/store.go"))
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// MatchGlob reports whether the slash-separated path p matches glob, with
+// gitignore-like anchoring:
+//
+//   - A glob without "/" matches any path element, so "vendor" matches
+//     "vendor/a.go" and "x/vendor/b.go", and "*.md" matches "docs/a.md".
+//   - A glob with "/" is anchored at the root and matches the path or any
+//     of its parent directories; a leading "/" is ignored.
+//   - "*", "?" and "[...]" match within one element as in path.Match, and
+//     a "**" element matches zero or more elements.
+func MatchGlob(glob, p string) bool {
+	segs := strings.Split(p, "/")
+	if !strings.Contains(glob, "/") {
+		for _, s := range segs {
+			if ok, _ := path.Match(glob, s); ok {
+				return true
+			}
+		}
+		return false
+	}
+	pats := strings.Split(strings.TrimPrefix(glob, "/"), "/")
+	for n := len(segs); n > 0; n-- {
+		if matchElements(pats, segs[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGlob reports a malformed glob.
+func ValidateGlob(glob string) error {
+	if glob == "" {
+		return fmt.Errorf("glob must not be empty")
+	}
+	for _, pat := range strings.Split(glob, "/") {
+		if _, err := path.Match(pat, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+func matchElements(pats, segs []string) bool {
+	for len(pats) > 0 {
+		if pats[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchElements(pats[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pats[0], segs[0]); !ok {
+			return false
+		}
+		pats, segs = pats[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// SelectFiles returns the files matching any of patterns, sorted and
+// without duplicates, and the patterns that match none. A pattern is a
+// repo-relative file, a directory, which selects the files under it, or a
+// glob as in MatchGlob but always anchored at the root ("*.go" selects the
+// Go files of the root, "**/*.go" all of them); "." selects every file.
+func SelectFiles(files, patterns []string) (selected, unmatched []string) {
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		p := RepoPath(pattern)
+		matched := false
+		for _, f := range files {
+			if p == "." || MatchGlob("/"+p, f) {
+				matched = true
+				if !seen[f] {
+					seen[f] = true
+					selected = append(selected, f)
+				}
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	sort.Strings(selected)
+	return selected, unmatched
+}
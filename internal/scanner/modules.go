@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Module is a Go module inside the repository.
+type Module struct {
+	// Dir is the module directory relative to the repository root, slash
+	// separated; "." is the root module.
+	Dir string
+	// Path is the module path declared in go.mod.
+	Path string
+}
+
+// Modules returns the Go modules of the repository, root module first and
+// the rest sorted by directory. When the root has a go.work file its use
+// directives list the modules; otherwise every tracked go.mod outside the
+// default excluded directories and testdata is one.
+func (s *Scanner) Modules(ctx context.Context) ([]Module, error) {
+	dirs, err := s.moduleDirs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mods := make([]Module, 0, len(dirs))
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(s.repoRoot, filepath.FromSlash(dir), "go.mod"))
+		if err != nil {
+			return nil, fmt.Errorf("reading module %s: %w", dir, err)
+		}
+		mods = append(mods, Module{Dir: dir, Path: modulePath(data)})
+	}
+
+	sort.Slice(mods, func(i, j int) bool {
+		if (mods[i].Dir == ".") != (mods[j].Dir == ".") {
+			return mods[i].Dir == "."
+		}
+		return mods[i].Dir < mods[j].Dir
+	})
+	return mods, nil
+}
+
+// discoveryExcludeDirs are the directories skipped when looking for modules
+// and project manifests. Toolchains ignore testdata, so fixtures there are
+// not projects.
+func discoveryExcludeDirs() []string {
+	return append(DefaultExcludeDirs(), "testdata")
+}
+
+func (s *Scanner) moduleDirs(ctx context.Context) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(s.repoRoot, "go.work"))
+	if err == nil {
+		return parseGoWork(data)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading go.work: %w", err)
+	}
+
+	files, err := s.TrackedFilesFiltered(ctx, FilterOptions{ExcludeDirs: discoveryExcludeDirs()})
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, f := range files {
+		if path.Base(f) == "go.mod" {
+			dirs = append(dirs, path.Dir(f))
+		}
+	}
+	return dirs, nil
+}
+
+// parseGoWork returns the directories of the use directives in a go.work
+// file, both the single-line and the block form.
+func parseGoWork(data []byte) ([]string, error) {
+	var dirs []string
+	seen := map[string]bool{}
+	inBlock := false
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		var dir string
+		switch {
+		case line == "":
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+			dir = line
+		case line == "use (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "use "):
+			dir = strings.TrimSpace(strings.TrimPrefix(line, "use "))
+		default:
+			continue
+		}
+
+		if unquoted, err := strconv.Unquote(dir); err == nil {
+			dir = unquoted
+		}
+		dir = RepoPath(dir)
+		if Outside(dir) {
+			return nil, fmt.Errorf("go.work:%d: module %s is outside the repository", n, dir)
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading go.work: %w", err)
+	}
+	return dirs, nil
+}
+
+// modulePath returns the path of the module directive in a go.mod file.
+func modulePath(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			rest = strings.TrimSpace(rest)
+			if i := strings.Index(rest, "//"); i >= 0 {
+				rest = strings.TrimSpace(rest[:i])
+			}
+			if unquoted, err := strconv.Unquote(rest); err == nil {
+				return unquoted
+			}
+			return rest
+		}
+	}
+	return ""
+}
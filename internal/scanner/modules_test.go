@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoWork(t *testing.T) {
+	dirs, err := parseGoWork([]byte(`go 1.24
+
+use ./tools/gen // generator
+
+use (
+	.
+	./services/api
+	"./services/web"
+	./services/api
+)
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tools/gen", ".", "services/api", "services/web"}, dirs)
+
+	_, err = parseGoWork([]byte("use ../other\n"))
+	assert.ErrorContains(t, err, "go.work:1: module ../other is outside the repository")
+}
+
+func TestModulePath(t *testing.T) {
+	assert.Equal(t, "example.com/api", modulePath([]byte("// comment\nmodule example.com/api // api\n\ngo 1.24\n")))
+	assert.Equal(t, "example.com/q", modulePath([]byte(`module "example.com/q"`)))
+	assert.Equal(t, "", modulePath([]byte("go 1.24\n")))
+}
+
+func TestScanner_Modules(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	runGit(t, dir, "init")
+	createFile(t, dir, "go.mod", "module example.com/root\n")
+	createFile(t, dir, "services/web/go.mod", "module example.com/web\n")
+	createFile(t, dir, "services/api/go.mod", "module example.com/api\n")
+	createFile(t, dir, "vendor/x/go.mod", "module example.com/x\n")
+	createFile(t, dir, "pkg/testdata/fixture/go.mod", "module example.com/fixture\n")
+	runGit(t, dir, "add", ".")
+
+	mods, err := New(dir).Modules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Dir: ".", Path: "example.com/root"},
+		{Dir: "services/api", Path: "example.com/api"},
+		{Dir: "services/web", Path: "example.com/web"},
+	}, mods)
+
+	// go.work takes precedence over discovery.
+	createFile(t, dir, "go.work", "go 1.24\n\nuse ./services/api\n")
+	mods, err = New(dir).Modules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Module{{Dir: "services/api", Path: "example.com/api"}}, mods)
+
+	createFile(t, dir, "go.work", "use ./missing\n")
+	_, err = New(dir).Modules(ctx)
+	assert.ErrorContains(t, err, "reading module missing")
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Repository paths are relative to the repository root and slash
+// separated on every OS, as git prints them. OS paths are what the os and
+// path/filepath packages take. Convert between the two only through these
+// helpers, so backslashes never leak into findings or comparisons and
+// slashes never reach a Windows API unconverted.
+
+// RepoPath returns p, a relative path in either separator style (as git,
+// the go command or a tool printed it), as a clean repository path: "./"
+// and ".\" prefixes are dropped and "" becomes ".".
+func RepoPath(p string) string {
+	return path.Clean(strings.ReplaceAll(p, "\\", "/"))
+}
+
+// OSPath returns the OS path of the repository path rel under root.
+func OSPath(root, rel string) string {
+	return filepath.Join(root, filepath.FromSlash(rel))
+}
+
+// RelPath returns the OS path p, absolute or relative to the working
+// directory like root, as a repository path under root. ok is false when p
+// is outside root.
+func RelPath(root, p string) (rel string, ok bool) {
+	//cortex:ignore env:windows-paths reason="converted right below"
+	r, err := filepath.Rel(root, p)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(r)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+// Outside reports whether the repository path rel escapes the repository:
+// it is absolute in either style ("/x", `C:\x`) or starts with "..".
+func Outside(rel string) bool {
+	rel = RepoPath(rel)
+	return path.IsAbs(rel) || len(rel) >= 2 && rel[1] == ':' || rel == ".." || strings.HasPrefix(rel, "../")
+}
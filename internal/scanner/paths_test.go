@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"./internal/x.go":   "internal/x.go",
+		`.\internal\x.go`:   "internal/x.go",
+		"internal//a/../b":  "internal/b",
+		"":                  ".",
+		`services\api\`:     "services/api",
+		"spec/features.yml": "spec/features.yml",
+	} {
+		assert.Equal(t, want, RepoPath(in), in)
+	}
+}
+
+func TestOSPathAndRelPath(t *testing.T) {
+	root := t.TempDir()
+	p := OSPath(root, "internal/scanner/paths.go")
+	assert.Equal(t, filepath.Join(root, "internal", "scanner", "paths.go"), p)
+
+	rel, ok := RelPath(root, p)
+	assert.True(t, ok)
+	assert.Equal(t, "internal/scanner/paths.go", rel)
+
+	_, ok = RelPath(root, filepath.Dir(root))
+	assert.False(t, ok, "the parent is outside the repository")
+}
+
+func TestOutside(t *testing.T) {
+	for _, rel := range []string{"..", "../x", `..\x`, "/etc", `C:\x`, "a/../../b"} {
+		assert.True(t, Outside(rel), rel)
+	}
+	for _, rel := range []string{".", "a", "a/..", ".cortex/bin", "..x"} {
+		assert.False(t, Outside(rel), rel)
+	}
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// ProcessFiles calls fn on every file with a bounded pool of workers and
+// returns the results in the order of files, so callers aggregate them as
+// deterministically as a serial loop would. workers <= 0 means one per
+// CPU (GOMAXPROCS).
+//
+// Files are handed out until ctx is done; then the remaining files are
+// skipped and ctx.Err() is returned once the running calls finish.
+// Otherwise the error of the first file in order whose call failed is
+// returned, after every file was processed.
+func ProcessFiles[T any](ctx context.Context, files []string, workers int, fn func(rel string) (T, error)) ([]T, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(files))
+
+	results := make([]T, len(files))
+	errs := make([]error, len(files))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = fn(files[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case <-ctx.Done():
+			break feed
+		case next <- i:
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
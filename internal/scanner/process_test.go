@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessFiles(t *testing.T) {
+	files := make([]string, 100)
+	for i := range files {
+		files[i] = fmt.Sprintf("f%03d.go", i)
+	}
+	var running, peak atomic.Int32
+	got, err := ProcessFiles(context.Background(), files, 4, func(rel string) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return "x/" + rel, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, len(files))
+	for i, rel := range files {
+		assert.Equal(t, "x/"+rel, got[i], "results keep the order of files")
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(4), "at most 4 workers")
+
+	none, err := ProcessFiles(context.Background(), nil, 0, func(string) (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestProcessFiles_Error(t *testing.T) {
+	files := []string{"a", "b", "c", "d", "e"}
+	var calls atomic.Int32
+	_, err := ProcessFiles(context.Background(), files, 3, func(rel string) (int, error) {
+		calls.Add(1)
+		if rel == "b" || rel == "d" {
+			return 0, errors.New("bad " + rel)
+		}
+		return 0, nil
+	})
+	assert.EqualError(t, err, "bad b", "the error of the first failing file in order")
+	assert.Equal(t, int32(len(files)), calls.Load())
+}
+
+func TestProcessFiles_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	_, err := ProcessFiles(ctx, []string{"a", "b", "c", "d"}, 1, func(string) (int, error) {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		return 0, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, calls.Load(), int32(4), "files after the cancellation are skipped")
+}
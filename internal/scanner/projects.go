@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ProjectDirs returns the directories of tracked files named manifest (e.g.
+// "package.json"), relative to the repository root and sorted. A directory
+// nested in another project directory is left out, so workspace members are
+// covered by their workspace root.
+func (s *Scanner) ProjectDirs(ctx context.Context, manifest string) ([]string, error) {
+	files, err := s.TrackedFilesFiltered(ctx, FilterOptions{ExcludeDirs: discoveryExcludeDirs()})
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, f := range files {
+		if path.Base(f) == manifest {
+			dirs = append(dirs, path.Dir(f))
+		}
+	}
+	sort.Strings(dirs)
+
+	var out []string
+	for _, dir := range dirs {
+		if !nestedIn(dir, out) {
+			out = append(out, dir)
+		}
+	}
+	return out, nil
+}
+
+// nestedIn reports whether dir is one of parents or below one of them.
+func nestedIn(dir string, parents []string) bool {
+	for _, p := range parents {
+		if p == "." || dir == p || strings.HasPrefix(dir, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_ProjectDirs(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	runGit(t, dir, "init")
+	createFile(t, dir, "web/package.json", "{}")
+	createFile(t, dir, "web/packages/ui/package.json", "{}")
+	createFile(t, dir, "web-admin/package.json", "{}")
+	createFile(t, dir, "web/node_modules/left-pad/package.json", "{}")
+	createFile(t, dir, "py/testdata/pyproject.toml")
+	createFile(t, dir, "rust/Cargo.toml")
+	runGit(t, dir, "add", "-f", ".")
+
+	s := New(dir)
+
+	dirs, err := s.ProjectDirs(ctx, "package.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "web-admin"}, dirs)
+
+	dirs, err = s.ProjectDirs(ctx, "pyproject.toml")
+	require.NoError(t, err)
+	assert.Empty(t, dirs)
+
+	dirs, err = s.ProjectDirs(ctx, "Cargo.toml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rust"}, dirs)
+
+	createFile(t, dir, "package.json", "{}")
+	runGit(t, dir, "add", "package.json")
+	dirs, err = New(dir).ProjectDirs(ctx, "package.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"."}, dirs)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -11,20 +13,31 @@ import (
 // Scanner provides access to the repository's tracked files.
 type Scanner struct {
 	repoRoot string
+	git      bool
 
 	mu           sync.Mutex
 	trackedCache []string
 }
 
-// New creates a new Scanner for the given repository root.
+// New creates a new Scanner for the given repository root. Files are listed
+// by git when it is installed and the root is inside a work tree; otherwise
+// the scanner walks the filesystem and honors .gitignore files itself.
 func New(repoRoot string) *Scanner {
 	return &Scanner{
 		repoRoot: repoRoot,
+		git:      useGit(repoRoot),
 	}
 }
 
+// UsesGit reports whether files are listed by git rather than by walking
+// the filesystem.
+func (s *Scanner) UsesGit() bool {
+	return s.git
+}
+
 // TrackedFiles returns all files tracked by git, caching the result for the instance lifetime.
-// It respects .gitignore implicitly by asking git.
+// It respects .gitignore implicitly by asking git. Without git, the files a
+// filesystem walk finds outside .gitignore patterns count as tracked.
 func (s *Scanner) TrackedFiles(ctx context.Context) ([]string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -33,6 +46,15 @@ func (s *Scanner) TrackedFiles(ctx context.Context) ([]string, error) {
 		return s.trackedCache, nil
 	}
 
+	if !s.git {
+		files, err := walkFiles(ctx, s.repoRoot)
+		if err != nil {
+			return nil, err
+		}
+		s.trackedCache = files
+		return s.trackedCache, nil
+	}
+
 	// git ls-files -z to avoid escaping issues
 	cmd := exec.CommandContext(ctx, "git", "ls-files", "-z")
 	cmd.Dir = s.repoRoot
@@ -51,19 +73,54 @@ func (s *Scanner) TrackedFiles(ctx context.Context) ([]string, error) {
 	sOut := strings.TrimSuffix(string(out), "\x00")
 
 	files := strings.Split(sOut, "\x00")
+	// git lists the index in byte order already; sorting guarantees it
+	// for TrackedFilesIn.
+	sort.Strings(files)
 	s.trackedCache = files
 	return s.trackedCache, nil
 }
 
+// TrackedFilesIn returns the tracked files under dir (a slash-separated
+// path relative to the repo root), sorted. It looks the directory up in the
+// cached list instead of filtering all of it.
+func (s *Scanner) TrackedFilesIn(ctx context.Context, dir string) ([]string, error) {
+	all, err := s.TrackedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filesIn(all, dir), nil
+}
+
 // TrackedFilesFiltered returns tracked files matching the filter options.
+// With IncludeDirs only the files under those directories are filtered.
 func (s *Scanner) TrackedFilesFiltered(ctx context.Context, opts FilterOptions) ([]string, error) {
 	all, err := s.TrackedFiles(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(opts.IncludeDirs) > 0 {
+		var scoped []string
+		for _, dir := range opts.IncludeDirs {
+			scoped = append(scoped, filesIn(all, dir)...)
+		}
+		// Nested directories list a file twice; FilterFiles sorts, so
+		// duplicates end up adjacent.
+		return slices.Compact(FilterFiles(scoped, opts)), nil
+	}
 	return FilterFiles(all, opts), nil
 }
 
+// filesIn returns the range of the sorted paths that lies under dir.
+func filesIn(sorted []string, dir string) []string {
+	prefix := dirPrefix(dir)
+	lo := sort.SearchStrings(sorted, prefix)
+	hi := lo
+	for hi < len(sorted) && strings.HasPrefix(sorted[hi], prefix) {
+		hi++
+	}
+	return sorted[lo:hi:hi]
+}
+
 // TrackedGoFiles returns only tracked .go files, applying default excludes.
 func (s *Scanner) TrackedGoFiles(ctx context.Context) ([]string, error) {
 	return s.TrackedFilesFiltered(ctx, FilterOptions{
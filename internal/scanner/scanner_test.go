@@ -59,6 +59,23 @@ func TestFilterFiles(t *testing.T) {
 			},
 			expected: []string{"b.go"},
 		},
+		{
+			name:  "include dirs",
+			paths: []string{"spec/a.md", "specs/b.md", "spec/cli/c.md", "docs/d.md"},
+			opts: FilterOptions{
+				IncludeDirs: []string{"spec", "docs/"},
+			},
+			expected: []string{"docs/d.md", "spec/a.md", "spec/cli/c.md"},
+		},
+		{
+			name:  "include and exclude patterns",
+			paths: []string{"docs/a.md", "docs/archive/b.md", "docs/c.txt", "x/docs/d.md"},
+			opts: FilterOptions{
+				IncludePatterns: []string{"docs/**/*.md"},
+				ExcludePatterns: []string{"docs/archive"},
+			},
+			expected: []string{"docs/a.md"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -69,6 +86,41 @@ func TestFilterFiles(t *testing.T) {
 	}
 }
 
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob, path string
+		want       bool
+	}{
+		{"*.go", "a/b/c.go", true},
+		{"vendor", "x/vendor/y.go", true},
+		{"internal/search", "internal/search/search.go", true},
+		{"/internal", "internal/x.go", true},
+		{"internal/*.go", "internal/a/b.go", false},
+		{"internal/**/*.go", "internal/a/b.go", true},
+		{"internal/**/*.go", "internal/b.go", true},
+		{"**/testdata", "a/b/testdata/f.txt", true},
+		{"docs/*.md", "spec/docs/a.md", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchGlob(tt.glob, tt.path), "%s ~ %s", tt.glob, tt.path)
+	}
+}
+
+func TestSelectFiles(t *testing.T) {
+	files := []string{"a.go", "docs/x.md", "internal/a/b.go", "internal/c.go", "main.go"}
+
+	selected, unmatched := SelectFiles(files, []string{"*.go", "./internal/a", "internal/c.go", "missing/", "**/*.md"})
+	assert.Equal(t, []string{"a.go", "docs/x.md", "internal/a/b.go", "internal/c.go", "main.go"}, selected)
+	assert.Equal(t, []string{"missing/"}, unmatched)
+
+	selected, _ = SelectFiles(files, []string{"*.go"})
+	assert.Equal(t, []string{"a.go", "main.go"}, selected, "globs are anchored at the root")
+
+	selected, unmatched = SelectFiles(files, []string{"."})
+	assert.Equal(t, files, selected)
+	assert.Empty(t, unmatched)
+}
+
 func TestScanner(t *testing.T) {
 	// Create a temp directory for the git repo
 	dir := t.TempDir()
@@ -118,6 +170,22 @@ func TestScanner(t *testing.T) {
 	assert.Contains(t, goFiles, "pkg/util.go")
 	assert.NotContains(t, goFiles, "vendor/foo.go")
 	assert.NotContains(t, goFiles, ".gitignore")
+
+	// Test TrackedFilesIn
+	in, err := s.TrackedFilesIn(ctx, "pkg")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pkg/util.go"}, in)
+	in, err = s.TrackedFilesIn(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, in)
+
+	// IncludeDirs narrows before filtering; nested dirs list files once
+	scoped, err := s.TrackedFilesFiltered(ctx, FilterOptions{
+		IncludeDirs:       []string{"vendor", "pkg", "pkg"},
+		IncludeExtensions: []string{".go"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pkg/util.go", "vendor/foo.go"}, scoped)
 }
 
 func runGit(t *testing.T, dir string, args ...string) {
@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// FileStatus is a path that differs from HEAD or is untracked, as reported
+// by `git status --porcelain`.
+type FileStatus struct {
+	Path string
+	// Index and Worktree are the porcelain XY letters: ' ' (unchanged), 'M',
+	// 'T', 'A', 'D', 'U', or '?' for untracked files.
+	Index    byte
+	Worktree byte
+}
+
+// Untracked reports whether the file is untracked and not ignored.
+func (f FileStatus) Untracked() bool {
+	return f.Index == '?'
+}
+
+// Status returns the files that differ from HEAD plus the untracked files
+// that are not ignored, sorted by path. Unlike TrackedFiles it is not
+// cached, since skills may change the worktree between calls. Untracked
+// directories are listed file by file and renames as a deletion and an
+// addition. Without git (see New) there is no HEAD to compare with, and
+// every file is tracked: the status is empty.
+func (s *Scanner) Status(ctx context.Context) ([]FileStatus, error) {
+	if !s.git {
+		return []FileStatus{}, nil
+	}
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain=v1", "-z", "--untracked-files=all", "--no-renames")
+	cmd.Dir = s.repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	return parseStatus(string(out))
+}
+
+// UntrackedFiles returns the files git does not track and does not ignore,
+// sorted by path.
+func (s *Scanner) UntrackedFiles(ctx context.Context) ([]string, error) {
+	return s.statusPaths(ctx, FileStatus.Untracked)
+}
+
+// ModifiedFiles returns the tracked files whose index or worktree state
+// differs from HEAD, including staged additions and deletions, sorted by
+// path.
+func (s *Scanner) ModifiedFiles(ctx context.Context) ([]string, error) {
+	return s.statusPaths(ctx, func(f FileStatus) bool { return !f.Untracked() })
+}
+
+func (s *Scanner) statusPaths(ctx context.Context, keep func(FileStatus) bool) ([]string, error) {
+	all, err := s.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, f := range all {
+		if keep(f) {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths, nil
+}
+
+// parseStatus parses `git status --porcelain=v1 -z --no-renames` output:
+// NUL-terminated "XY path" records.
+func parseStatus(out string) ([]FileStatus, error) {
+	files := []FileStatus{}
+	for _, rec := range strings.Split(strings.TrimSuffix(out, "\x00"), "\x00") {
+		if rec == "" {
+			continue
+		}
+		if len(rec) < 4 || rec[2] != ' ' {
+			return nil, fmt.Errorf("git status: malformed entry %q", rec)
+		}
+		files = append(files, FileStatus{Path: rec[3:], Index: rec[0], Worktree: rec[1]})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
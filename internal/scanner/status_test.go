@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_Status(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+	createFile(t, dir, ".gitignore", "ignored.txt\n")
+	createFile(t, dir, "kept.go")
+	createFile(t, dir, "edited.go")
+	createFile(t, dir, "removed.go")
+	createFile(t, dir, "staged.go")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "Initial commit")
+
+	createFile(t, dir, "edited.go", "package x\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "removed.go")))
+	createFile(t, dir, "staged.go", "package y\n")
+	createFile(t, dir, "added.go")
+	runGit(t, dir, "add", "staged.go", "added.go")
+	createFile(t, dir, "ignored.txt")
+	createFile(t, dir, "docs/new.md")
+	createFile(t, dir, "docs/sub/other.md")
+	createFile(t, dir, "with space.md")
+
+	s := New(dir)
+
+	status, err := s.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []FileStatus{
+		{Path: "added.go", Index: 'A', Worktree: ' '},
+		{Path: "docs/new.md", Index: '?', Worktree: '?'},
+		{Path: "docs/sub/other.md", Index: '?', Worktree: '?'},
+		{Path: "edited.go", Index: ' ', Worktree: 'M'},
+		{Path: "removed.go", Index: ' ', Worktree: 'D'},
+		{Path: "staged.go", Index: 'M', Worktree: ' '},
+		{Path: "with space.md", Index: '?', Worktree: '?'},
+	}, status)
+
+	untracked, err := s.UntrackedFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/new.md", "docs/sub/other.md", "with space.md"}, untracked)
+
+	modified, err := s.ModifiedFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"added.go", "edited.go", "removed.go", "staged.go"}, modified)
+}
+
+func TestParseStatus_Malformed(t *testing.T) {
+	_, err := parseStatus("M\x00")
+	assert.Error(t, err)
+
+	files, err := parseStatus("")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// useGit reports whether the files of root can be listed by git: git is
+// installed and root is inside a work tree. Exported source tarballs and
+// minimal containers have neither and fall back to walkFiles.
+func useGit(root string) bool {
+	if _, err := exec.LookPath("git"); err != nil {
+		return false
+	}
+	dir, err := filepath.Abs(root)
+	if err != nil {
+		return false
+	}
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// walkFiles lists the files under root that git would track after
+// `git add -A`: every file not excluded by a .gitignore file (or by
+// .git/info/exclude), outside .git directories, sorted by path.
+func walkFiles(ctx context.Context, root string) ([]string, error) {
+	rules := map[string][]ignorePattern{}
+	if data, err := os.ReadFile(filepath.Join(root, ".git", "info", "exclude")); err == nil {
+		rules[""] = parseIgnore(string(data))
+	}
+
+	files := []string{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("reading directory entry: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, ok := RelPath(root, p)
+		if !ok {
+			return fmt.Errorf("%s is outside %s", p, root)
+		}
+
+		if d.IsDir() {
+			if rel != "." {
+				if d.Name() == ".git" || ignored(rules, rel, true) {
+					return filepath.SkipDir
+				}
+			} else {
+				rel = ""
+			}
+			data, err := os.ReadFile(filepath.Join(p, ".gitignore"))
+			switch {
+			case err == nil:
+				rules[rel] = append(rules[rel], parseIgnore(string(data))...)
+			case !errors.Is(err, fs.ErrNotExist):
+				return err
+			}
+			return nil
+		}
+		if !ignored(rules, rel, false) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ignorePattern is one line of a .gitignore file.
+type ignorePattern struct {
+	elems    []string // the pattern split on "/"
+	anchored bool     // matches relative to its .gitignore, not any element
+	dirOnly  bool     // trailing "/": matches directories only
+	negate   bool     // leading "!": re-includes what an earlier line excluded
+}
+
+// parseIgnore parses a .gitignore file. Lines are blank, "#" comments, or
+// patterns; "\#" and "\!" escape a leading "#" or "!", and trailing spaces
+// are dropped unless escaped with "\".
+func parseIgnore(data string) []ignorePattern {
+	var patterns []ignorePattern
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+			line = strings.TrimSuffix(line, " ")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate, line = true, line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly, line = true, strings.TrimSuffix(line, "/")
+		}
+		// A "/" at the start or in the middle anchors the pattern.
+		p.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+		p.elems = strings.Split(line, "/")
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// match reports whether the pattern matches rel, a path relative to the
+// directory of its .gitignore.
+func (p ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if !p.anchored {
+		ok, _ := path.Match(p.elems[0], path.Base(rel))
+		return ok
+	}
+	return matchElements(p.elems, strings.Split(rel, "/"))
+}
+
+// ignored reports whether the .gitignore rules collected so far exclude rel.
+// Rules of deeper directories take precedence, and within a file the last
+// matching line wins. Entries of an excluded directory are never reached,
+// so a negation cannot re-include them, as in git.
+func ignored(rules map[string][]ignorePattern, rel string, isDir bool) bool {
+	var dirs []string
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, "")
+
+	result := false
+	for i := len(dirs) - 1; i >= 0; i-- {
+		sub := rel
+		if dirs[i] != "" {
+			sub = strings.TrimPrefix(rel, dirs[i]+"/")
+		}
+		for _, p := range rules[dirs[i]] {
+			if p.match(sub, isDir) {
+				result = !p.negate
+			}
+		}
+	}
+	return result
+}
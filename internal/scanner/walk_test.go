@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ignoreTree exercises the .gitignore rules walkFiles implements.
+func ignoreTree(t *testing.T, dir string) {
+	createFile(t, dir, ".gitignore", strings.Join([]string{
+		"# comment",
+		"*.log",
+		"!keep.log",
+		"/root-only.txt",
+		"build/",
+		"docs/**/draft.md",
+		"\\#hash",
+		"trailing.txt   ",
+		"",
+	}, "\n"))
+	for _, f := range []string{
+		"main.go", "a.log", "keep.log", "sub/b.log",
+		"root-only.txt", "sub/root-only.txt",
+		"build/out.bin", "sub/build/x", "buildfile",
+		"docs/draft.md", "docs/a/b/draft.md", "docs/ok.md",
+		"#hash", "trailing.txt",
+		"pkg/.gitignore", "pkg/gen.go", "pkg/keep.go", "pkg/sub/gen.go",
+	} {
+		createFile(t, dir, f)
+	}
+	createFile(t, dir, "pkg/.gitignore", "gen.go\n!sub/gen.go\n")
+}
+
+func TestWalkFiles(t *testing.T) {
+	dir := t.TempDir()
+	ignoreTree(t, dir)
+
+	s := New(dir)
+	require.False(t, s.UsesGit(), "a temp dir is not a work tree")
+
+	files, err := s.TrackedFiles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		".gitignore",
+		"buildfile",
+		"docs/ok.md",
+		"keep.log",
+		"main.go",
+		"pkg/.gitignore",
+		"pkg/keep.go",
+		"pkg/sub/gen.go",
+		"sub/root-only.txt",
+	}, files)
+
+	status, err := s.Status(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, status)
+}
+
+func TestWalkFiles_MatchesGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	ignoreTree(t, dir)
+	walked, err := walkFiles(context.Background(), dir)
+	require.NoError(t, err)
+
+	runGit(t, dir, "init")
+	runGit(t, dir, "add", "-A")
+	s := New(dir)
+	require.True(t, s.UsesGit())
+	tracked, err := s.TrackedFiles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, tracked, walked)
+}
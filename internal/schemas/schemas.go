@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package schemas embeds the JSON Schemas of every JSON artifact Cortex emits
+// and validates documents against them.
+//
+// Feature: REPORTS_CORE
+// Spec: spec/reports/core.md
+package schemas
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//go:embed json/*.schema.json
+var files embed.FS
+
+// Artifact schema names.
+const (
+	CommitHealth        = "commit-health"
+	FeatureTraceability = "feature-traceability"
+	CommitSuggestions   = "commit-suggestions"
+	CLI                 = "cli"
+	LastRun             = "last-run"
+	SkillResult         = "skill-result"
+	RunRecord           = "run-record"
+	BundleManifest      = "bundle-manifest"
+	ReportIndex         = "report-index"
+	Provenance          = "provenance"
+	Config              = "config"
+)
+
+// Schema describes one versioned JSON artifact.
+type Schema struct {
+	// Name identifies the artifact (e.g. "commit-health").
+	Name string `json:"name"`
+	// Version is the schema_version value written by the current binary.
+	Version string `json:"version"`
+	// FileName is the conventional artifact file name used for detection.
+	FileName string `json:"file_name,omitempty"`
+
+	file string
+}
+
+// registry lists schemas in a stable order.
+var registry = []Schema{
+	{Name: CommitHealth, Version: "1.0", FileName: "commit-health.json", file: "json/commit-health.v1.schema.json"},
+	{Name: FeatureTraceability, Version: "1.0", FileName: "feature-traceability.json", file: "json/feature-traceability.v1.schema.json"},
+	{Name: CommitSuggestions, Version: "1.0", FileName: "commit-suggestions.json", file: "json/commit-suggestions.v1.schema.json"},
+	{Name: CLI, Version: "1.0", FileName: "cli.json", file: "json/cli.v1.schema.json"},
+	{Name: LastRun, Version: "1.1", FileName: "last-run.json", file: "json/last-run.v1.schema.json"},
+	{Name: SkillResult, Version: "1.0", file: "json/skill-result.v1.schema.json"},
+	{Name: RunRecord, Version: "1.0", file: "json/run-record.v1.schema.json"},
+	{Name: BundleManifest, Version: "1.0", FileName: "cortex-bundle.json", file: "json/bundle-manifest.v1.schema.json"},
+	{Name: ReportIndex, Version: "1.0", file: "json/report-index.v1.schema.json"},
+	{Name: Provenance, Version: "1.0", FileName: "provenance.json", file: "json/provenance.v1.schema.json"},
+	{Name: Config, Version: "1.0", file: "json/config.v1.schema.json"},
+}
+
+// All returns every registered schema.
+func All() []Schema {
+	out := make([]Schema, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// Lookup returns the schema registered under name.
+func Lookup(name string) (Schema, bool) {
+	for _, s := range registry {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Schema{}, false
+}
+
+// Detect infers the schema of an artifact from its path.
+// Skill results live under a "skills" directory and are named after the skill;
+// run records live under a "history" directory and are named after the run ID;
+// the report index is index.json under a "reports" directory.
+func Detect(path string) (Schema, bool) {
+	base := filepath.Base(path)
+	for _, s := range registry {
+		if s.FileName != "" && s.FileName == base {
+			return s, true
+		}
+	}
+	if filepath.Base(filepath.Dir(path)) == "skills" && filepath.Ext(base) == ".json" {
+		return Lookup(SkillResult)
+	}
+	if filepath.Base(filepath.Dir(path)) == "history" && filepath.Ext(base) == ".json" {
+		return Lookup(RunRecord)
+	}
+	if filepath.Base(filepath.Dir(path)) == "reports" && base == "index.json" {
+		return Lookup(ReportIndex)
+	}
+	return Schema{}, false
+}
+
+// Raw returns the embedded JSON Schema document.
+func (s Schema) Raw() ([]byte, error) {
+	data, err := files.ReadFile(s.file)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s not embedded: %w", s.Name, err)
+	}
+	return data, nil
+}
+
+// Validate checks data against the schema.
+// It returns a *ValidationError listing every violation on mismatch.
+func (s Schema) Validate(data []byte) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	var root node
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return fmt.Errorf("parsing schema %s: %w", s.Name, err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s document: %w", s.Name, err)
+	}
+
+	v := &validator{root: &root}
+	v.validate(&root, doc, "$")
+	if len(v.violations) > 0 {
+		return &ValidationError{Schema: s.Name + "@" + s.Version, Violations: v.violations}
+	}
+	return nil
+}
+
+// Validate checks data against the schema registered under name.
+func Validate(name string, data []byte) error {
+	s, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown schema %q", name)
+	}
+	return s.Validate(data)
+}
+
+// Decode validates data against the named schema and then unmarshals it into v.
+// Readers of Cortex artifacts use it so stale or hand-edited files fail loudly.
+func Decode(name string, data []byte, v any) error {
+	if err := Validate(name, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ValidateFile validates the artifact at path.
+// When name is empty the schema is detected from the file name.
+func ValidateFile(path, name string) (Schema, error) {
+	var (
+		s  Schema
+		ok bool
+	)
+	if name != "" {
+		s, ok = Lookup(name)
+		if !ok {
+			return Schema{}, fmt.Errorf("unknown schema %q", name)
+		}
+	} else {
+		s, ok = Detect(path)
+		if !ok {
+			return Schema{}, fmt.Errorf("cannot detect schema for %s; pass --schema", path)
+		}
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is supplied by the user on purpose
+	if err != nil {
+		return s, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return s, s.Validate(data)
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package schemas
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllSchemasEmbedded(t *testing.T) {
+	for _, s := range All() {
+		raw, err := s.Raw()
+		require.NoError(t, err, s.Name)
+
+		var root node
+		require.NoError(t, json.Unmarshal(raw, &root), s.Name)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		".cortex/reports/commit-health.json":        CommitHealth,
+		".cortex/reports/feature-traceability.json": FeatureTraceability,
+		".cortex/data/cli.json":                     CLI,
+		".cortex/run/last-run.json":                 LastRun,
+		".cortex/run/skills/test:go.json":           SkillResult,
+		".cortex/run/history/0123456789ab.json":     RunRecord,
+		".cortex/reports/index.json":                ReportIndex,
+		".cortex/provenance.json":                   Provenance,
+	}
+	for path, want := range cases {
+		s, ok := Detect(path)
+		require.True(t, ok, path)
+		assert.Equal(t, want, s.Name, path)
+	}
+
+	_, ok := Detect("random.json")
+	assert.False(t, ok)
+}
+
+func TestValidate_LastRun(t *testing.T) {
+	require.NoError(t, Validate(LastRun, []byte(`{"schema_version":"1.1","status":"pass","skills":["a"],"failed":null}`)))
+
+	err := Validate(LastRun, []byte(`{"schema_version":"2.0","status":"maybe","skills":[1],"failed":[]}`))
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []Violation{
+		{Path: "$.schema_version", Message: `expected "1.1", got "2.0"`},
+		{Path: "$.skills[0]", Message: "expected string, got number"},
+		{Path: "$.status", Message: `value "maybe" is not one of ["pass", "fail"]`},
+	}, verr.Violations)
+}
+
+func TestValidate_RejectsMalformedJSON(t *testing.T) {
+	assert.Error(t, Validate(CLI, []byte(`{`)))
+	assert.Error(t, Validate("nope", []byte(`{}`)))
+}
+
+func TestDecode_ValidatesBeforeUnmarshal(t *testing.T) {
+	var out struct {
+		Status string `json:"status"`
+	}
+	err := Decode(SkillResult, []byte(`{"skill":"x","status":"pass","exit_code":0}`), &out)
+	require.Error(t, err)
+	assert.Empty(t, out.Status)
+
+	require.NoError(t, Decode(SkillResult, []byte(`{"schema_version":"1.0","skill":"x","status":"pass","exit_code":0}`), &out))
+	assert.Equal(t, "pass", out.Status)
+}
+
+// The committed report goldens are real emitter output and must stay valid.
+func TestReportGoldensMatchSchemas(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	require.True(t, ok)
+	testdata := filepath.Join(filepath.Dir(filename), "..", "..", "cmd", "cortex", "commands", "reports", "testdata")
+
+	cases := map[string]string{
+		"reports_commit_report.golden":               CommitHealth,
+		"reports_feature_traceability_report.golden": FeatureTraceability,
+		"reports_commit_suggest_json.golden":         CommitSuggestions,
+	}
+	for file, name := range cases {
+		data, err := os.ReadFile(filepath.Join(testdata, file)) //nolint:gosec // testdata path
+		require.NoError(t, err)
+		assert.NoError(t, Validate(name, data), file)
+	}
+}
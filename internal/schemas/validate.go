@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// node is the subset of JSON Schema (draft 2020-12) understood by the validator.
+// Cortex artifacts only need structural checks, so keywords outside this set
+// are ignored rather than rejected.
+type node struct {
+	Ref                  string           `json:"$ref"`
+	Defs                 map[string]*node `json:"$defs"`
+	Type                 typeList         `json:"type"`
+	Properties           map[string]*node `json:"properties"`
+	Required             []string         `json:"required"`
+	AdditionalProperties *additional      `json:"additionalProperties"`
+	Items                *node            `json:"items"`
+	Enum                 []any            `json:"enum"`
+	Const                any              `json:"const"`
+	MinLength            *int             `json:"minLength"`
+	Minimum              *float64         `json:"minimum"`
+	Pattern              string           `json:"pattern"`
+}
+
+// typeList accepts both "type": "string" and "type": ["string", "null"].
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = typeList{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or array of strings")
+	}
+	*t = many
+	return nil
+}
+
+// additional is either a boolean or a schema.
+type additional struct {
+	allowed bool
+	schema  *node
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		a.allowed = b
+		return nil
+	}
+	a.allowed = true
+	a.schema = &node{}
+	return json.Unmarshal(data, a.schema)
+}
+
+// Violation is a single schema mismatch at a JSON path.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError lists every violation found while validating a document.
+type ValidationError struct {
+	Schema     string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, "  - "+v.String())
+	}
+	return fmt.Sprintf("document does not match schema %s:\n%s", e.Schema, strings.Join(lines, "\n"))
+}
+
+type validator struct {
+	root       *node
+	violations []Violation
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) resolve(n *node) *node {
+	for n.Ref != "" {
+		name, ok := strings.CutPrefix(n.Ref, "#/$defs/")
+		if !ok || v.root.Defs[name] == nil {
+			v.fail("$", "unresolvable $ref %q", n.Ref)
+			return &node{}
+		}
+		n = v.root.Defs[name]
+	}
+	return n
+}
+
+func (v *validator) validate(n *node, value any, path string) {
+	n = v.resolve(n)
+
+	if len(n.Type) > 0 && !matchesType(n.Type, value) {
+		v.fail(path, "expected %s, got %s", strings.Join(n.Type, " or "), typeName(value))
+		return
+	}
+
+	if n.Const != nil && !jsonEqual(n.Const, value) {
+		v.fail(path, "expected %s, got %s", render(n.Const), render(value))
+	}
+	if len(n.Enum) > 0 {
+		found := false
+		for _, e := range n.Enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allowed := make([]string, 0, len(n.Enum))
+			for _, e := range n.Enum {
+				allowed = append(allowed, render(e))
+			}
+			v.fail(path, "value %s is not one of [%s]", render(value), strings.Join(allowed, ", "))
+		}
+	}
+
+	switch val := value.(type) {
+	case string:
+		if n.MinLength != nil && len([]rune(val)) < *n.MinLength {
+			v.fail(path, "string shorter than %d", *n.MinLength)
+		}
+		if n.Pattern != "" {
+			re, err := regexp.Compile(n.Pattern)
+			if err != nil {
+				v.fail(path, "invalid pattern %q in schema: %v", n.Pattern, err)
+			} else if !re.MatchString(val) {
+				v.fail(path, "value %q does not match pattern %q", val, n.Pattern)
+			}
+		}
+	case float64:
+		if n.Minimum != nil && val < *n.Minimum {
+			v.fail(path, "value %v is less than minimum %v", val, *n.Minimum)
+		}
+	case []any:
+		if n.Items != nil {
+			for i, item := range val {
+				v.validate(n.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case map[string]any:
+		for _, key := range n.Required {
+			if _, ok := val[key]; !ok {
+				v.fail(path, "missing required property %q", key)
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := path + "." + key
+			if prop, ok := n.Properties[key]; ok {
+				v.validate(prop, val[key], child)
+				continue
+			}
+			if n.AdditionalProperties == nil {
+				continue
+			}
+			if !n.AdditionalProperties.allowed {
+				v.fail(child, "unexpected property")
+				continue
+			}
+			if n.AdditionalProperties.schema != nil {
+				v.validate(n.AdditionalProperties.schema, val[key], child)
+			}
+		}
+	}
+}
+
+func matchesType(types []string, value any) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	return render(a) == render(b)
+}
+
+func render(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
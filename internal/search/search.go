@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package search is the grep engine behind `cortex grep`. It matches RE2
+// regular expressions or fixed strings line by line, with case modes,
+// include and exclude globs, a match cap with deterministic truncation and
+// context lines. The snapshot.grep tool of cortex-mcp implements the same
+// semantics, so a search gives the same matches through either surface.
+package search
+
+// Feature: CLI_COMMAND_GREP
+// Spec: spec/cli/grep.md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// CaseMode selects how letter case is matched.
+type CaseMode string
+
+// Case modes. Smart matches case-insensitively unless the pattern contains
+// an upper-case letter.
+const (
+	CaseSensitive   CaseMode = "sensitive"
+	CaseInsensitive CaseMode = "insensitive"
+	CaseSmart       CaseMode = "smart"
+)
+
+// Options configures a search.
+type Options struct {
+	// Pattern is an RE2 regular expression, or a literal with FixedStrings.
+	Pattern      string
+	FixedStrings bool
+	// Case defaults to CaseSensitive.
+	Case CaseMode
+	// Include keeps only files matching one of the globs; Exclude drops
+	// files matching any of them. See scanner.MatchGlob.
+	Include []string
+	Exclude []string
+	// MaxMatches caps the matches returned; 0 means no cap.
+	MaxMatches int
+	// Context is the number of lines reported before and after each match.
+	Context int
+	// Files decides which files are too large to read.
+	Files scanner.Policy
+}
+
+// Match is a matching line. Column is the 1-based byte offset of the first
+// match on the line.
+type Match struct {
+	Path   string   `json:"path"`
+	Line   int      `json:"line"`
+	Column int      `json:"column"`
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// Skipped is an admitted file whose content was not searched: a binary
+// file (including text that is not valid UTF-8) or one over the size limit.
+type Skipped struct {
+	Path   string        `json:"path"`
+	Size   int64         `json:"size"`
+	Reason scanner.Class `json:"reason"`
+}
+
+// Result is the outcome of a search. Matches are ordered by path, then
+// line, and Skipped by path. Truncated is set when MaxMatches cut off
+// further matches.
+type Result struct {
+	Matches       []Match   `json:"matches"`
+	FilesSearched int       `json:"files_searched"`
+	Skipped       []Skipped `json:"skipped,omitempty"`
+	Truncated     bool      `json:"truncated"`
+}
+
+// File is a file of a Source. Omitted marks a file whose content the source
+// does not hold, such as a snapshot entry captured as metadata only.
+type File struct {
+	Path    string
+	Size    int64
+	Omitted bool
+}
+
+// Source is a set of files to search.
+type Source interface {
+	// Files lists the files of the source with slash-separated paths.
+	Files(ctx context.Context) ([]File, error)
+	// Read returns the content of path. Files listed but gone are
+	// reported with an error wrapping fs.ErrNotExist and skipped.
+	Read(ctx context.Context, path string) ([]byte, error)
+}
+
+// Searcher is a compiled search.
+type Searcher struct {
+	opts Options
+	re   *regexp.Regexp
+}
+
+// Compile validates opts and compiles its pattern.
+func Compile(opts Options) (*Searcher, error) {
+	if opts.Pattern == "" {
+		return nil, errors.New("pattern must not be empty")
+	}
+	if opts.MaxMatches < 0 {
+		return nil, fmt.Errorf("max matches must not be negative, got %d", opts.MaxMatches)
+	}
+	if opts.Context < 0 {
+		return nil, fmt.Errorf("context must not be negative, got %d", opts.Context)
+	}
+	for _, g := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if err := scanner.ValidateGlob(g); err != nil {
+			return nil, err
+		}
+	}
+
+	expr := opts.Pattern
+	if opts.FixedStrings {
+		expr = regexp.QuoteMeta(expr)
+	}
+	switch opts.Case {
+	case "", CaseSensitive:
+	case CaseInsensitive:
+		expr = "(?i)" + expr
+	case CaseSmart:
+		if !strings.ContainsFunc(opts.Pattern, unicode.IsUpper) {
+			expr = "(?i)" + expr
+		}
+	default:
+		return nil, fmt.Errorf("unknown case mode %q (want sensitive, insensitive or smart)", opts.Case)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return &Searcher{opts: opts, re: re}, nil
+}
+
+// Admits reports whether path passes the include and exclude globs.
+func (s *Searcher) Admits(path string) bool {
+	if len(s.opts.Include) > 0 && !matchAny(s.opts.Include, path) {
+		return false
+	}
+	return !matchAny(s.opts.Exclude, path)
+}
+
+// Search searches the admitted files of src in path order. Binary files,
+// files that are not valid UTF-8 and files over the size limit are skipped
+// and listed in Result.Skipped; large files are never read.
+func (s *Searcher) Search(ctx context.Context, src Source) (*Result, error) {
+	files, err := src.Files(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files = append([]File(nil), files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	res := &Result{Matches: []Match{}}
+	for _, f := range files {
+		if !s.Admits(f.Path) {
+			continue
+		}
+		if f.Omitted || s.opts.Files.TooLarge(f.Size) {
+			res.Skipped = append(res.Skipped, Skipped{Path: f.Path, Size: f.Size, Reason: scanner.ClassLarge})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := src.Read(ctx, f.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if scanner.IsBinary(data) || !utf8.Valid(data) {
+			res.Skipped = append(res.Skipped, Skipped{Path: f.Path, Size: int64(len(data)), Reason: scanner.ClassBinary})
+			continue
+		}
+		res.FilesSearched++
+		if s.searchFile(res, f.Path, splitLines(string(data))) {
+			res.Truncated = true
+			break
+		}
+	}
+	return res, nil
+}
+
+// searchFile appends the matches of one file and reports whether the cap
+// was hit with matches left over.
+func (s *Searcher) searchFile(res *Result, path string, lines []string) bool {
+	for i, line := range lines {
+		loc := s.re.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		if s.opts.MaxMatches > 0 && len(res.Matches) == s.opts.MaxMatches {
+			return true
+		}
+		m := Match{Path: path, Line: i + 1, Column: loc[0] + 1, Text: line}
+		if n := s.opts.Context; n > 0 {
+			m.Before = lines[max(0, i-n):i]
+			m.After = lines[i+1 : min(len(lines), i+1+n)]
+		}
+		res.Matches = append(res.Matches, m)
+	}
+	return false
+}
+
+// splitLines splits text into lines without their terminators, treating
+// "\r\n" as one.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines
+}
+
+func matchAny(globs []string, path string) bool {
+	for _, g := range globs {
+		if scanner.MatchGlob(g, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package search
+
+// Feature: CLI_COMMAND_GREP
+// Spec: spec/cli/grep.md
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// memSource holds files as path -> content.
+type memSource map[string]string
+
+func (m memSource) Files(context.Context) ([]File, error) {
+	var files []File
+	for p, content := range m {
+		files = append(files, File{Path: p, Size: int64(len(content))})
+	}
+	return files, nil
+}
+
+func (m memSource) Read(_ context.Context, path string) ([]byte, error) {
+	content, ok := m[path]
+	if !ok {
+		return nil, fmt.Errorf("read %s: %w", path, fs.ErrNotExist)
+	}
+	return []byte(content), nil
+}
+
+func search(t *testing.T, opts Options, src Source) *Result {
+	t.Helper()
+	s, err := Compile(opts)
+	require.NoError(t, err)
+	res, err := s.Search(context.Background(), src)
+	require.NoError(t, err)
+	return res
+}
+
+func TestSearch_OrderAndColumns(t *testing.T) {
+	src := memSource{
+		"b.go":     "package b\nfunc Foo() {}\r\n",
+		"a/a.go":   "// Foo\nvar foo = 1\n",
+		"bin.dat":  "Foo\x00",
+		"latin.md": "Foo \xff\n",
+	}
+	res := search(t, Options{Pattern: `Foo\(?`}, src)
+
+	assert.Equal(t, []Match{
+		{Path: "a/a.go", Line: 1, Column: 4, Text: "// Foo"},
+		{Path: "b.go", Line: 2, Column: 6, Text: "func Foo() {}"},
+	}, res.Matches)
+	assert.Equal(t, 2, res.FilesSearched, "binary and non-UTF-8 files are skipped")
+	assert.Equal(t, []Skipped{
+		{Path: "bin.dat", Size: 4, Reason: scanner.ClassBinary},
+		{Path: "latin.md", Size: 6, Reason: scanner.ClassBinary},
+	}, res.Skipped)
+	assert.False(t, res.Truncated)
+}
+
+func TestSearch_LargeFilesAreNotRead(t *testing.T) {
+	src := memSource{"big.txt": "x 0123456789\n", "small.txt": "x\n"}
+	res := search(t, Options{Pattern: "x", Files: scanner.Policy{MaxFileBytes: 8}}, src)
+
+	require.Len(t, res.Matches, 1)
+	assert.Equal(t, "small.txt", res.Matches[0].Path)
+	assert.Equal(t, []Skipped{{Path: "big.txt", Size: 13, Reason: scanner.ClassLarge}}, res.Skipped)
+}
+
+func TestSearch_CaseModes(t *testing.T) {
+	src := memSource{"f.txt": "Foo\nfoo\nFOO\n"}
+	count := func(opts Options) int { return len(search(t, opts, src).Matches) }
+
+	assert.Equal(t, 1, count(Options{Pattern: "foo"}))
+	assert.Equal(t, 3, count(Options{Pattern: "foo", Case: CaseInsensitive}))
+	assert.Equal(t, 3, count(Options{Pattern: "foo", Case: CaseSmart}))
+	assert.Equal(t, 1, count(Options{Pattern: "Foo", Case: CaseSmart}))
+}
+
+func TestSearch_FixedStrings(t *testing.T) {
+	src := memSource{"f.txt": "a.b\naxb\n"}
+	assert.Len(t, search(t, Options{Pattern: "a.b"}, src).Matches, 2)
+	assert.Len(t, search(t, Options{Pattern: "a.b", FixedStrings: true}, src).Matches, 1)
+}
+
+func TestSearch_MaxMatchesTruncates(t *testing.T) {
+	src := memSource{"a.txt": "x\nx\n", "b.txt": "x\n"}
+
+	res := search(t, Options{Pattern: "x", MaxMatches: 2}, src)
+	assert.Len(t, res.Matches, 2)
+	assert.Equal(t, "a.txt", res.Matches[1].Path)
+	assert.True(t, res.Truncated)
+
+	res = search(t, Options{Pattern: "x", MaxMatches: 3}, src)
+	assert.Len(t, res.Matches, 3)
+	assert.False(t, res.Truncated, "reaching the cap exactly is not truncation")
+}
+
+func TestSearch_Context(t *testing.T) {
+	src := memSource{"f.txt": "1\n2\nhit\n4\n"}
+	res := search(t, Options{Pattern: "hit", Context: 2}, src)
+	require.Len(t, res.Matches, 1)
+	assert.Equal(t, []string{"1", "2"}, res.Matches[0].Before)
+	assert.Equal(t, []string{"4"}, res.Matches[0].After)
+}
+
+func TestSearch_Globs(t *testing.T) {
+	src := memSource{
+		"cmd/main.go":          "x\n",
+		"internal/a/a.go":      "x\n",
+		"internal/a/a_test.go": "x\n",
+		"vendor/v/v.go":        "x\n",
+		"README.md":            "x\n",
+	}
+	paths := func(opts Options) []string {
+		opts.Pattern = "x"
+		var out []string
+		for _, m := range search(t, opts, src).Matches {
+			out = append(out, m.Path)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"cmd/main.go", "internal/a/a.go", "internal/a/a_test.go", "vendor/v/v.go"},
+		paths(Options{Include: []string{"*.go"}}))
+	assert.Equal(t, []string{"internal/a/a.go"},
+		paths(Options{Include: []string{"internal"}, Exclude: []string{"*_test.go"}}))
+	assert.Equal(t, []string{"README.md", "cmd/main.go", "internal/a/a.go", "internal/a/a_test.go"},
+		paths(Options{Exclude: []string{"vendor"}}))
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, opts := range []Options{
+		{},
+		{Pattern: "("},
+		{Pattern: "x", Case: "upper"},
+		{Pattern: "x", Include: []string{"[a"}},
+		{Pattern: "x", MaxMatches: -1},
+		{Pattern: "x", Context: -1},
+	} {
+		_, err := Compile(opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
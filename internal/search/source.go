@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package search
+
+// Feature: CLI_COMMAND_GREP
+// Spec: spec/cli/grep.md
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/snapshot"
+)
+
+// Worktree is the working tree of the repository at Root: the files git
+// tracks, as they are on disk.
+type Worktree struct {
+	Root string
+}
+
+// Files lists the tracked files that exist on disk.
+func (w Worktree) Files(ctx context.Context) ([]File, error) {
+	paths, err := scanner.New(w.Root).TrackedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]File, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(filepath.Join(w.Root, filepath.FromSlash(p)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: p, Size: info.Size()})
+	}
+	return files, nil
+}
+
+// Read reads a file from disk.
+func (w Worktree) Read(_ context.Context, path string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(w.Root, filepath.FromSlash(path))) //nolint:gosec // G304: path is a tracked file of the repository
+}
+
+// Snapshot is snapshot ID of Store.
+type Snapshot struct {
+	Store snapshot.Store
+	ID    string
+}
+
+// Files lists the files of the snapshot.
+func (s Snapshot) Files(ctx context.Context) ([]File, error) {
+	entries, err := s.Store.Entries(ctx, s.ID)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]File, len(entries))
+	for i, e := range entries {
+		files[i] = File{Path: e.Path, Size: e.Size, Omitted: e.Omitted != ""}
+	}
+	return files, nil
+}
+
+// Read reads a file from the snapshot.
+func (s Snapshot) Read(ctx context.Context, path string) ([]byte, error) {
+	return s.Store.Blob(ctx, s.ID, path)
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+// Feature: CLI_COMMAND_SERVE
+// Spec: spec/cli/serve.md
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bartekus/cortex/pkg/cortexrun"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the skill duration
+// histogram: from quick doc checks to test suites near the default timeout.
+var durationBuckets = []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 1800}
+
+// metrics collects the counters served under /metrics in the Prometheus
+// text exposition format. Families and series are written in sorted order.
+type metrics struct {
+	mu        sync.Mutex
+	runs      map[string]float64 // by final run status
+	skillRuns map[[2]string]float64
+	durations map[string]*histogram // by skill
+	retries   map[string]float64    // by skill
+	requests  map[string]float64    // by HTTP status code
+}
+
+type histogram struct {
+	counts []float64 // per bucket, not cumulative
+	sum    float64
+	count  float64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		runs:      map[string]float64{},
+		skillRuns: map[[2]string]float64{},
+		durations: map[string]*histogram{},
+		retries:   map[string]float64{},
+		requests:  map[string]float64{},
+	}
+}
+
+// observeRun records a finished run and, when it has one, its report.
+func (m *metrics) observeRun(status string, report *cortexrun.Report) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[status]++
+	if report == nil {
+		return
+	}
+	for _, r := range report.Results {
+		m.skillRuns[[2]string{r.Skill, r.Status}]++
+		if r.Attempts > 1 {
+			m.retries[r.Skill] += float64(r.Attempts - 1)
+		}
+		if r.Status == cortexrun.StatusSkip {
+			continue
+		}
+		h := m.durations[r.Skill]
+		if h == nil {
+			h = &histogram{counts: make([]float64, len(durationBuckets))}
+			m.durations[r.Skill] = h
+		}
+		secs := r.Duration.Seconds()
+		for i, le := range durationBuckets {
+			if secs <= le {
+				h.counts[i]++
+				break
+			}
+		}
+		h.sum += secs
+		h.count++
+	}
+}
+
+func (m *metrics) observeRequest(code int) {
+	m.mu.Lock()
+	m.requests[strconv.Itoa(code)]++
+	m.mu.Unlock()
+}
+
+// statusRecorder captures the status code a handler writes.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	states := map[string]float64{RunQueued: 0, RunRunning: 0}
+	for _, run := range s.runs {
+		if _, ok := states[run.Status]; ok {
+			states[run.Status]++
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.write(w, states)
+}
+
+func (m *metrics) write(w io.Writer, states map[string]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	family(w, "cortex_http_requests_total", "counter", "HTTP requests served, by status code.")
+	for _, code := range sortedKeys(m.requests) {
+		sample(w, "cortex_http_requests_total", m.requests[code], "code", code)
+	}
+
+	family(w, "cortex_runs", "gauge", "Runs currently queued or running, by state.")
+	for _, st := range sortedKeys(states) {
+		sample(w, "cortex_runs", states[st], "state", st)
+	}
+
+	family(w, "cortex_runs_total", "counter", "Finished runs, by status (done or error).")
+	for _, st := range sortedKeys(m.runs) {
+		sample(w, "cortex_runs_total", m.runs[st], "status", st)
+	}
+
+	family(w, "cortex_skill_runs_total", "counter", "Skill results, by skill and status (pass, fail, skip or timeout).")
+	keys := make([][2]string, 0, len(m.skillRuns))
+	for k := range m.skillRuns {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, k := range keys {
+		sample(w, "cortex_skill_runs_total", m.skillRuns[k], "skill", k[0], "status", k[1])
+	}
+
+	family(w, "cortex_skill_retries_total", "counter", "Skill retries after a failed attempt, by skill.")
+	for _, skill := range sortedKeys(m.retries) {
+		sample(w, "cortex_skill_retries_total", m.retries[skill], "skill", skill)
+	}
+
+	family(w, "cortex_skill_duration_seconds", "histogram", "Wall time of skills that ran, retries included, by skill.")
+	for _, skill := range sortedKeys(m.durations) {
+		h := m.durations[skill]
+		cumulative := 0.0
+		for i, le := range durationBuckets {
+			cumulative += h.counts[i]
+			sample(w, "cortex_skill_duration_seconds_bucket", cumulative, "skill", skill, "le", formatFloat(le))
+		}
+		sample(w, "cortex_skill_duration_seconds_bucket", h.count, "skill", skill, "le", "+Inf")
+		sample(w, "cortex_skill_duration_seconds_sum", h.sum, "skill", skill)
+		sample(w, "cortex_skill_duration_seconds_count", h.count, "skill", skill)
+	}
+}
+
+func family(w io.Writer, name, typ, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one series; labels are name/value pairs.
+func sample(w io.Writer, name string, value float64, labels ...string) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+`="`+escapeLabel(labels[i+1])+`"`)
+	}
+	_, _ = fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,410 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package server exposes Cortex over HTTP for dashboards and bots that
+// cannot read the repository: skill runs, the feature registry and the
+// generated reports.
+//
+// Runs are executed in-process through pkg/cortexrun, one at a time in the
+// order they were requested, and kept in memory.
+package server
+
+// Feature: CLI_COMMAND_SERVE
+// Spec: spec/cli/serve.md
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bartekus/cortex/internal/github"
+	"github.com/bartekus/cortex/internal/log"
+	"github.com/bartekus/cortex/pkg/cortexrun"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Run states.
+const (
+	RunQueued  = "queued"
+	RunRunning = "running"
+	RunDone    = "done"
+	RunError   = "error"
+)
+
+// MaxRuns is how many runs are kept; the oldest finished runs are dropped
+// beyond it.
+const MaxRuns = 100
+
+// MaxQueued is how many runs may wait; further requests are rejected with
+// 503 Service Unavailable.
+const MaxQueued = 32
+
+// ReportsDir is the repo-relative directory served under /reports.
+const ReportsDir = "docs/__generated__"
+
+// Options configures a Server.
+type Options struct {
+	RepoRoot string
+	// FeaturesPath is the registry served under /features, relative to
+	// RepoRoot unless absolute.
+	FeaturesPath string
+	// Token, when set, is required as "Authorization: Bearer <token>".
+	Token string
+	// RunOptions are applied to every run before the request's own.
+	RunOptions []cortexrun.Option
+	// WebhookSecret enables POST /webhooks/github; deliveries must be signed
+	// with it.
+	WebhookSecret string
+	// GitHub, when set, receives webhook run results as check runs; its Repo
+	// is taken from each event.
+	GitHub *github.Client
+	// CheckName names the check runs; empty means "cortex".
+	CheckName string
+	// Remote is the git remote webhook refs are fetched from; empty means
+	// "origin".
+	Remote string
+	// Log receives a record per finished run; nil discards them.
+	Log *slog.Logger
+}
+
+// Run is one requested skill run.
+type Run struct {
+	ID            string            `json:"id"`
+	Status        string            `json:"status"`
+	Skills        []string          `json:"skills,omitempty"`
+	FailOnWarning bool              `json:"fail_on_warning,omitempty"`
+	Created       time.Time         `json:"created"`
+	Finished      *time.Time        `json:"finished,omitempty"`
+	Report        *cortexrun.Report `json:"report,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	// Source is the commit a webhook run checked out.
+	Source *Source `json:"source,omitempty"`
+	// CheckURL is the check run the results were published to, CheckError
+	// why publishing failed.
+	CheckURL   string `json:"check_url,omitempty"`
+	CheckError string `json:"check_error,omitempty"`
+}
+
+// RunRequest is the body of POST /runs. No skills runs every skill.
+type RunRequest struct {
+	Skills        []string `json:"skills"`
+	FailOnWarning bool     `json:"fail_on_warning"`
+}
+
+// Server serves the HTTP API.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+	// runFunc executes a run; tests replace it.
+	runFunc func(ctx context.Context, repoRoot string, opts ...cortexrun.Option) (*cortexrun.Report, error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	queue  chan job
+	done   chan struct{}
+
+	metrics *metrics
+
+	mu     sync.Mutex
+	runs   map[string]*Run
+	order  []string // run IDs, oldest first
+	nextID int
+}
+
+type job struct {
+	id  string
+	req RunRequest
+	src *Source
+}
+
+// New returns a server for opts.
+func New(opts Options) *Server {
+	if opts.FeaturesPath == "" {
+		opts.FeaturesPath = filepath.Join("spec", "features.yaml")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		runFunc: cortexrun.Run,
+		ctx:     ctx,
+		cancel:  cancel,
+		queue:   make(chan job, MaxQueued),
+		done:    make(chan struct{}),
+		runs:    map[string]*Run{},
+		metrics: newMetrics(),
+	}
+	go s.worker()
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	s.mux.HandleFunc("POST /runs", s.handleCreateRun)
+	s.mux.HandleFunc("GET /runs", s.handleListRuns)
+	s.mux.HandleFunc("GET /runs/{id}", s.handleGetRun)
+	s.mux.HandleFunc("GET /features", s.handleFeatures)
+	s.mux.HandleFunc("GET /reports", s.handleListReports)
+	s.mux.HandleFunc("GET /reports/{path...}", s.handleGetReport)
+	if opts.WebhookSecret != "" {
+		s.mux.HandleFunc("POST /webhooks/github", s.handleGitHubWebhook)
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+	defer func() { s.metrics.observeRequest(rec.code) }()
+	w = rec
+
+	// Webhooks authenticate with their signature instead.
+	if s.opts.Token != "" && r.URL.Path != "/healthz" && r.URL.Path != webhookPath {
+		want := "Bearer " + s.opts.Token
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close cancels the running run, waits for it to stop and drops the queue.
+func (s *Server) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
+	var req RunRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid run request: "+err.Error())
+		return
+	}
+
+	s.enqueue(w, req, nil)
+}
+
+// enqueue records a run and queues it, answering 202 with the run.
+func (s *Server) enqueue(w http.ResponseWriter, req RunRequest, src *Source) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == cap(s.queue) {
+		writeError(w, http.StatusServiceUnavailable, "too many queued runs")
+		return
+	}
+	s.nextID++
+	run := &Run{
+		ID:            strconv.Itoa(s.nextID),
+		Status:        RunQueued,
+		Skills:        req.Skills,
+		FailOnWarning: req.FailOnWarning,
+		Created:       time.Now().UTC(),
+		Source:        src,
+	}
+	s.runs[run.ID] = run
+	s.order = append(s.order, run.ID)
+	s.pruneLocked()
+	s.queue <- job{id: run.ID, req: req, src: src}
+
+	w.Header().Set("Location", "/runs/"+run.ID)
+	writeJSON(w, http.StatusAccepted, *run)
+}
+
+// worker executes queued runs one at a time until the server is closed.
+func (s *Server) worker() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case j := <-s.queue:
+			s.execute(j)
+		}
+	}
+}
+
+func (s *Server) execute(j job) {
+	id, req := j.id, j.req
+	s.update(id, func(run *Run) { run.Status = RunRunning })
+	opts := append([]cortexrun.Option(nil), s.opts.RunOptions...)
+	if len(req.Skills) > 0 {
+		opts = append(opts, cortexrun.WithSkills(req.Skills...))
+	}
+	opts = append(opts, cortexrun.WithFailOnWarning(req.FailOnWarning))
+
+	report, err := s.runSource(id, j.src, opts)
+	if err != nil {
+		s.fail(id, err)
+		return
+	}
+	s.update(id, func(run *Run) {
+		now := time.Now().UTC()
+		run.Finished = &now
+		run.Status, run.Report = RunDone, report
+	})
+	s.metrics.observeRun(RunDone, report)
+	log.OrDiscard(s.opts.Log).Info("run finished", "run", id, "status", RunDone, "exit_code", report.ExitCode)
+}
+
+// runSource runs in the repository, or in a checkout of src for webhook
+// runs, publishing the results of the latter. The checkout is removed
+// before it returns.
+func (s *Server) runSource(id string, src *Source, opts []cortexrun.Option) (*cortexrun.Report, error) {
+	if src == nil {
+		return s.runFunc(s.ctx, s.opts.RepoRoot, opts...)
+	}
+	dir, cleanup, err := s.checkout(src)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	report, err := s.runFunc(s.ctx, dir, opts...)
+	if err == nil && s.opts.GitHub != nil {
+		url, perr := s.publish(dir, src, report)
+		s.update(id, func(run *Run) {
+			run.CheckURL = url
+			if perr != nil {
+				run.CheckError = perr.Error()
+			}
+		})
+		if perr != nil {
+			log.OrDiscard(s.opts.Log).Warn("publishing check run failed", "run", id, "repo", src.Repo, "sha", src.SHA, "err", perr)
+		}
+	}
+	return report, err
+}
+
+// fail finishes a run that could not start.
+func (s *Server) fail(id string, err error) {
+	s.update(id, func(run *Run) {
+		now := time.Now().UTC()
+		run.Finished = &now
+		run.Status, run.Error = RunError, err.Error()
+	})
+	s.metrics.observeRun(RunError, nil)
+	log.OrDiscard(s.opts.Log).Error("run failed to start", "run", id, "err", err)
+}
+
+func (s *Server) update(id string, fn func(*Run)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if run, ok := s.runs[id]; ok {
+		fn(run)
+	}
+}
+
+// pruneLocked drops the oldest finished runs beyond MaxRuns.
+func (s *Server) pruneLocked() {
+	for i := 0; len(s.order) > MaxRuns && i < len(s.order); {
+		id := s.order[i]
+		if st := s.runs[id].Status; st == RunDone || st == RunError {
+			delete(s.runs, id)
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	runs := make([]Run, 0, len(s.order))
+	for i := len(s.order) - 1; i >= 0; i-- {
+		runs = append(runs, *s.runs[s.order[i]])
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string][]Run{"runs": runs})
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	run, ok := s.runs[r.PathValue("id")]
+	var snapshot Run
+	if ok {
+		snapshot = *run
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "run not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+func (s *Server) handleFeatures(w http.ResponseWriter, _ *http.Request) {
+	path := s.opts.FeaturesPath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.opts.RepoRoot, path)
+	}
+	reg, err := gov.LoadRegistry(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeError(w, http.StatusNotFound, "feature registry not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if reg.Features == nil {
+		reg.Features = []gov.Feature{}
+	}
+	writeJSON(w, http.StatusOK, reg)
+}
+
+func (s *Server) reportsFS() fs.FS {
+	return os.DirFS(filepath.Join(s.opts.RepoRoot, filepath.FromSlash(ReportsDir)))
+}
+
+func (s *Server) handleListReports(w http.ResponseWriter, _ *http.Request) {
+	reports := []string{}
+	err := fs.WalkDir(s.reportsFS(), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			reports = append(reports, p)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sort.Strings(reports)
+	writeJSON(w, http.StatusOK, map[string][]string{"reports": reports})
+}
+
+func (s *Server) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("path")
+	info, err := fs.Stat(s.reportsFS(), name)
+	if err != nil || info.IsDir() {
+		writeError(w, http.StatusNotFound, "report not found")
+		return
+	}
+	http.ServeFileFS(w, r, s.reportsFS(), name)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/pkg/cortexrun"
+)
+
+// Feature: CLI_COMMAND_SERVE
+// Spec: spec/cli/serve.md
+
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server) {
+	t.Helper()
+	if opts.RepoRoot == "" {
+		opts.RepoRoot = t.TempDir()
+	}
+	s := New(opts)
+	s.runFunc = func(ctx context.Context, _ string, _ ...cortexrun.Option) (*cortexrun.Report, error) {
+		return &cortexrun.Report{Results: []cortexrun.SkillResult{{Skill: "test:go", Status: cortexrun.StatusPass}}}, nil
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+	return s, ts
+}
+
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	if v != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+	return resp.StatusCode
+}
+
+func TestCreateAndGetRun(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	resp, err := http.Post(ts.URL+"/runs", "application/json", strings.NewReader(`{"skills":["test:go"]}`))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "/runs/1", resp.Header.Get("Location"))
+
+	var run Run
+	require.Eventually(t, func() bool {
+		require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/runs/1", &run))
+		return run.Status == RunDone
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"test:go"}, run.Skills)
+	require.NotNil(t, run.Report)
+	assert.True(t, run.Report.Passed())
+
+	var list struct{ Runs []Run }
+	require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/runs", &list))
+	assert.Len(t, list.Runs, 1)
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, ts.URL+"/runs/42", nil))
+}
+
+func TestCreateRunRejectsInvalidBody(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	resp, err := http.Post(ts.URL+"/runs", "application/json", strings.NewReader(`{"skills":`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestFeaturesAndReports(t *testing.T) {
+	root := t.TempDir()
+	_, ts := newTestServer(t, Options{RepoRoot: root})
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, ts.URL+"/features", nil))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "spec"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "spec", "features.yaml"),
+		[]byte("features:\n  - id: CORE\n    title: Core\n"), 0o644))
+	var reg struct {
+		Features []struct{ ID, Title string }
+	}
+	require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/features", &reg))
+	require.Len(t, reg.Features, 1)
+	assert.Equal(t, "CORE", reg.Features[0].ID)
+
+	reports := filepath.Join(root, filepath.FromSlash(ReportsDir), "x")
+	require.NoError(t, os.MkdirAll(reports, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(reports, "a.json"), []byte(`{"ok":true}`), 0o644))
+
+	var list struct{ Reports []string }
+	require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/reports", &list))
+	assert.Equal(t, []string{"x/a.json"}, list.Reports)
+
+	var report map[string]bool
+	require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/reports/x/a.json", &report))
+	assert.True(t, report["ok"])
+	assert.Equal(t, http.StatusNotFound, getJSON(t, ts.URL+"/reports/x", nil))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, ts.URL+"/reports/missing.json", nil))
+}
+
+func TestToken(t *testing.T) {
+	_, ts := newTestServer(t, Options{Token: "s3cret"})
+
+	assert.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/healthz", nil))
+	assert.Equal(t, http.StatusUnauthorized, getJSON(t, ts.URL+"/runs", nil))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/runs", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestMetrics(t *testing.T) {
+	s, ts := newTestServer(t, Options{})
+	s.runFunc = func(context.Context, string, ...cortexrun.Option) (*cortexrun.Report, error) {
+		return &cortexrun.Report{ExitCode: 1, Results: []cortexrun.SkillResult{
+			{Skill: "test:go", Status: cortexrun.StatusFail, ExitCode: 1, Attempts: 3, Duration: 2 * time.Second},
+			{Skill: "docs:validate-spec", Status: cortexrun.StatusSkip},
+		}}, nil
+	}
+
+	resp, err := http.Post(ts.URL+"/runs", "application/json", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Eventually(t, func() bool {
+		var run Run
+		getJSON(t, ts.URL+"/runs/1", &run)
+		return run.Status == RunDone
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp, err = http.Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := string(data)
+
+	for _, line := range []string{
+		`cortex_http_requests_total{code="202"} 1`,
+		`cortex_runs{state="queued"} 0`,
+		`cortex_runs_total{status="done"} 1`,
+		`cortex_skill_runs_total{skill="docs:validate-spec",status="skip"} 1`,
+		`cortex_skill_runs_total{skill="test:go",status="fail"} 1`,
+		`cortex_skill_retries_total{skill="test:go"} 2`,
+		`cortex_skill_duration_seconds_bucket{skill="test:go",le="1"} 0`,
+		`cortex_skill_duration_seconds_bucket{skill="test:go",le="5"} 1`,
+		`cortex_skill_duration_seconds_bucket{skill="test:go",le="+Inf"} 1`,
+		`cortex_skill_duration_seconds_sum{skill="test:go"} 2`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, `cortex_skill_duration_seconds_count{skill="docs:validate-spec"}`)
+}
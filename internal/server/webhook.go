@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+// Feature: CLI_COMMAND_SERVE
+// Spec: spec/cli/serve.md
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/github"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/pkg/cortexrun"
+)
+
+const webhookPath = "/webhooks/github"
+
+// maxWebhookBody is GitHub's payload cap.
+const maxWebhookBody = 25 << 20
+
+// zeroSHA is the "after" of a push that deleted its ref.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// Source is the commit a webhook event asks to check.
+type Source struct {
+	// Event is the X-GitHub-Event header: "push" or "pull_request".
+	Event string `json:"event"`
+	// Repo is "owner/name".
+	Repo string `json:"repo"`
+	// Ref is fetched from the remote: the pushed ref, or refs/pull/<n>/head.
+	Ref         string `json:"ref"`
+	SHA         string `json:"sha"`
+	PullRequest int    `json:"pull_request,omitempty"`
+}
+
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// handleGitHubWebhook queues a run of the configured webhook profile for
+// push and pull request events. Other events are acknowledged and ignored.
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading payload: "+err.Error())
+		return
+	}
+	if !validSignature(s.opts.WebhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid X-Hub-Signature-256")
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	src, reason, err := parseEvent(event, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid "+event+" payload: "+err.Error())
+		return
+	}
+	if src == nil {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored", "reason": reason})
+		return
+	}
+
+	cfg, err := config.Load(s.opts.RepoRoot)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.enqueue(w, RunRequest{
+		Skills:        cfg.Server.Webhook.Skills,
+		FailOnWarning: cfg.Server.Webhook.FailOnWarning,
+	}, src)
+}
+
+// validSignature checks header against the HMAC-SHA256 of body.
+func validSignature(secret string, body []byte, header string) bool {
+	got, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	sig, err := hex.DecodeString(got)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// parseEvent returns the commit to check, or nil and why the event is
+// ignored.
+func parseEvent(event string, body []byte) (*Source, string, error) {
+	switch event {
+	case "push":
+		var e pushEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			return nil, "", err
+		}
+		if e.Deleted || e.After == zeroSHA {
+			return nil, "ref deleted", nil
+		}
+		if e.Ref == "" || e.After == "" {
+			return nil, "", fmt.Errorf("ref and after are required")
+		}
+		return &Source{Event: event, Repo: e.Repository.FullName, Ref: e.Ref, SHA: e.After}, "", nil
+	case "pull_request":
+		var e pullRequestEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			return nil, "", err
+		}
+		switch e.Action {
+		case "opened", "reopened", "synchronize":
+		default:
+			return nil, "action " + e.Action, nil
+		}
+		if e.Number == 0 || e.PullRequest.Head.SHA == "" {
+			return nil, "", fmt.Errorf("number and pull_request.head.sha are required")
+		}
+		return &Source{
+			Event:       event,
+			Repo:        e.Repository.FullName,
+			Ref:         fmt.Sprintf("refs/pull/%d/head", e.Number),
+			SHA:         e.PullRequest.Head.SHA,
+			PullRequest: e.Number,
+		}, "", nil
+	default:
+		return nil, "event " + event, nil
+	}
+}
+
+// checkout fetches src from the remote and checks its commit out in a
+// temporary worktree, which cleanup removes.
+func (s *Server) checkout(src *Source) (dir string, cleanup func(), err error) {
+	remote := s.opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	if err := s.git("fetch", "--quiet", remote, src.Ref); err != nil {
+		return "", nil, err
+	}
+	if !git.RefExists(s.ctx, s.opts.RepoRoot, src.SHA) {
+		return "", nil, fmt.Errorf("commit %s not found after fetching %s", src.SHA, src.Ref)
+	}
+
+	dir, err = os.MkdirTemp("", "cortex-webhook-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating worktree directory: %w", err)
+	}
+	if err := s.git("worktree", "add", "--quiet", "--detach", dir, src.SHA); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, err
+	}
+	return dir, func() {
+		// The server context may be cancelled; cleanup must still run.
+		_, _ = git.Run(context.Background(), s.opts.RepoRoot, "worktree", "remove", "--force", dir)
+		_ = os.RemoveAll(dir)
+	}, nil
+}
+
+func (s *Server) git(args ...string) error {
+	_, err := git.Run(s.ctx, s.opts.RepoRoot, args...)
+	return err
+}
+
+// publish creates a check run on src.SHA from report, annotating the
+// findings of failed skills.
+func (s *Server) publish(root string, src *Source, report *cortexrun.Report) (string, error) {
+	results := make([]runner.SkillResult, 0, len(report.Results))
+	for _, r := range report.Results {
+		results = append(results, runner.SkillResult{
+			Skill:    r.Skill,
+			Status:   runner.SkillStatus(r.Status),
+			ExitCode: r.ExitCode,
+			Note:     r.Note,
+		})
+	}
+	fs := findings.FromResults(root, results)
+
+	run := github.CheckRun{
+		Name:       s.opts.CheckName,
+		HeadSHA:    src.SHA,
+		Conclusion: "success",
+		Output:     github.NewOutput(results, fs),
+	}
+	if run.Name == "" {
+		run.Name = "cortex"
+	}
+	if !report.Passed() {
+		run.Conclusion = "failure"
+	}
+
+	client := *s.opts.GitHub
+	client.Repo = src.Repo
+	return client.Publish(s.ctx, run, github.Annotations(fs))
+}
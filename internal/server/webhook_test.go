@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/github"
+	"github.com/bartekus/cortex/pkg/cortexrun"
+)
+
+// Feature: CLI_COMMAND_SERVE
+// Spec: spec/cli/serve.md
+
+const testSecret = "hook-secret"
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := git.Run(context.Background(), dir, append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func deliver(t *testing.T, url, event, body, signature string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+"/webhooks/github", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", signature)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestWebhookPushRunsAndPublishes(t *testing.T) {
+	origin := t.TempDir()
+	runGit(t, origin, "init", "-q", "-b", "main")
+	runGit(t, origin, "commit", "-q", "--allow-empty", "-m", "first")
+
+	root := filepath.Join(t.TempDir(), "clone")
+	runGit(t, filepath.Dir(root), "clone", "-q", origin, root)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".cortex"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".cortex", "config.yaml"),
+		[]byte("server:\n  webhook:\n    skills: [docs:validate-spec]\n"), 0o644))
+
+	require.NoError(t, os.WriteFile(filepath.Join(origin, "new.txt"), []byte("pushed\n"), 0o644))
+	runGit(t, origin, "add", ".")
+	runGit(t, origin, "commit", "-q", "-m", "second")
+	sha := runGit(t, origin, "rev-parse", "HEAD")
+
+	var (
+		mu    sync.Mutex
+		check github.CheckRun
+		path  string
+	)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&check)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "html_url": "https://github.test/check/1"}`))
+	}))
+	t.Cleanup(api.Close)
+
+	s := New(Options{RepoRoot: root, WebhookSecret: testSecret, GitHub: &github.Client{BaseURL: api.URL}})
+	var ranIn string
+	s.runFunc = func(_ context.Context, repoRoot string, opts ...cortexrun.Option) (*cortexrun.Report, error) {
+		data, err := os.ReadFile(filepath.Join(repoRoot, "new.txt"))
+		require.NoError(t, err)
+		ranIn = string(data)
+		return &cortexrun.Report{ExitCode: 1, Results: []cortexrun.SkillResult{
+			{Skill: "docs:validate-spec", Status: cortexrun.StatusFail, ExitCode: 1, Note: "spec/a.md:3: missing feature"},
+		}}, nil
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+
+	body := `{"ref": "refs/heads/main", "after": "` + sha + `", "repository": {"full_name": "o/r"}}`
+	resp := deliver(t, ts.URL, "push", body, sign(body))
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var run Run
+	require.Eventually(t, func() bool {
+		require.Equal(t, http.StatusOK, getJSON(t, ts.URL+"/runs/1", &run))
+		return run.Status != RunQueued && run.Status != RunRunning
+	}, 10*time.Second, 20*time.Millisecond)
+	require.Equal(t, RunDone, run.Status, run.Error)
+	assert.Equal(t, "pushed\n", ranIn)
+	assert.Equal(t, []string{"docs:validate-spec"}, run.Skills)
+	assert.Equal(t, &Source{Event: "push", Repo: "o/r", Ref: "refs/heads/main", SHA: sha}, run.Source)
+	assert.Equal(t, "https://github.test/check/1", run.CheckURL)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "/repos/o/r/check-runs", path)
+	assert.Equal(t, sha, check.HeadSHA)
+	assert.Equal(t, "failure", check.Conclusion)
+	require.Len(t, check.Output.Annotations, 1)
+	assert.Equal(t, "spec/a.md", check.Output.Annotations[0].Path)
+
+	worktrees := runGit(t, root, "worktree", "list")
+	assert.Len(t, strings.Split(worktrees, "\n"), 1, "the run's worktree must be removed")
+}
+
+func TestWebhookRejectsBadSignature(t *testing.T) {
+	_, ts := newTestServer(t, Options{WebhookSecret: testSecret, Token: "api-token"})
+
+	body := `{"zen": "hi"}`
+	assert.Equal(t, http.StatusUnauthorized, deliver(t, ts.URL, "ping", body, "sha256=00").StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, deliver(t, ts.URL, "ping", body, "").StatusCode)
+	assert.Equal(t, http.StatusOK, deliver(t, ts.URL, "ping", body, sign(body)).StatusCode)
+}
+
+func TestWebhookDisabledWithoutSecret(t *testing.T) {
+	_, ts := newTestServer(t, Options{})
+
+	body := `{}`
+	assert.Equal(t, http.StatusNotFound, deliver(t, ts.URL, "ping", body, sign(body)).StatusCode)
+}
+
+func TestParseEvent(t *testing.T) {
+	src, _, err := parseEvent("pull_request", []byte(`{"action": "synchronize", "number": 7,
+		"pull_request": {"head": {"sha": "abc"}}, "repository": {"full_name": "o/r"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Source{Event: "pull_request", Repo: "o/r", Ref: "refs/pull/7/head", SHA: "abc", PullRequest: 7}, src)
+
+	src, reason, err := parseEvent("pull_request", []byte(`{"action": "closed", "number": 7}`))
+	require.NoError(t, err)
+	assert.Nil(t, src)
+	assert.Equal(t, "action closed", reason)
+
+	src, reason, err = parseEvent("push", []byte(`{"ref": "refs/heads/x", "after": "`+zeroSHA+`"}`))
+	require.NoError(t, err)
+	assert.Nil(t, src)
+	assert.Equal(t, "ref deleted", reason)
+
+	_, _, err = parseEvent("push", []byte(`{"ref": "refs/heads/x"}`))
+	assert.Error(t, err)
+}
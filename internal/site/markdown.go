@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package site
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6}) (.+)$`)
+	orderedPattern = regexp.MustCompile(`^\d+\. (.*)$`)
+	linkPattern    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern    = regexp.MustCompile(`\*\*(.+?)\*\*`)
+)
+
+// HTML converts the Markdown subset Cortex generates to HTML: ATX
+// headings, tables, unordered and ordered lists, blockquotes, "⸻" rules
+// and paragraphs, with inline code, bold and links. Relative links to .md
+// files are rewritten to their .html page.
+func HTML(md string) string {
+	var b strings.Builder
+	lines := strings.Split(strings.TrimRight(md, "\n"), "\n")
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case strings.TrimSpace(line) == "⸻":
+			b.WriteString("<hr>\n")
+			i++
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			b.WriteString("<h" + level + ` id="` + templates.Anchor(stripInline(m[2])) + `">` + inline(m[2]) + "</h" + level + ">\n")
+			i++
+		case strings.HasPrefix(line, ">"):
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(lines[i], ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(lines[i], ">"), " "))
+			}
+			b.WriteString("<blockquote>\n" + HTML(strings.Join(quote, "\n")) + "</blockquote>\n")
+		case strings.HasPrefix(line, "|"):
+			var rows []string
+			for ; i < len(lines) && strings.HasPrefix(lines[i], "|"); i++ {
+				rows = append(rows, lines[i])
+			}
+			writeTable(&b, rows)
+		case strings.HasPrefix(line, "- "):
+			b.WriteString("<ul>\n")
+			for ; i < len(lines) && strings.HasPrefix(lines[i], "- "); i++ {
+				b.WriteString("<li>" + inline(strings.TrimPrefix(lines[i], "- ")) + "</li>\n")
+			}
+			b.WriteString("</ul>\n")
+		case orderedPattern.MatchString(line):
+			b.WriteString("<ol>\n")
+			for ; i < len(lines) && orderedPattern.MatchString(lines[i]); i++ {
+				b.WriteString("<li>" + inline(orderedPattern.FindStringSubmatch(lines[i])[1]) + "</li>\n")
+			}
+			b.WriteString("</ol>\n")
+		default:
+			var para []string
+			for ; i < len(lines) && isParagraph(lines[i]); i++ {
+				para = append(para, inline(lines[i]))
+			}
+			b.WriteString("<p>" + strings.Join(para, "\n") + "</p>\n")
+		}
+	}
+	return b.String()
+}
+
+// isParagraph reports whether line continues a paragraph.
+func isParagraph(line string) bool {
+	t := strings.TrimSpace(line)
+	return t != "" && t != "⸻" && !headingPattern.MatchString(line) &&
+		!strings.HasPrefix(line, ">") && !strings.HasPrefix(line, "|") &&
+		!strings.HasPrefix(line, "- ") && !orderedPattern.MatchString(line)
+}
+
+// writeTable writes a table whose second row is the header separator.
+func writeTable(b *strings.Builder, rows []string) {
+	body := rows[1:]
+	if len(body) > 0 && strings.Trim(body[0], "|-: ") == "" {
+		body = body[1:]
+	}
+	b.WriteString("<table>\n<thead>\n")
+	writeRow(b, "th", rows[0])
+	b.WriteString("</thead>\n<tbody>\n")
+	for _, row := range body {
+		writeRow(b, "td", row)
+	}
+	b.WriteString("</tbody>\n</table>\n")
+}
+
+func writeRow(b *strings.Builder, tag, row string) {
+	b.WriteString("<tr>")
+	for _, c := range splitRow(row) {
+		b.WriteString("<" + tag + ">" + inline(c) + "</" + tag + ">")
+	}
+	b.WriteString("</tr>\n")
+}
+
+// splitRow splits a table row on the pipes that are not escaped.
+func splitRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cur.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+// inline renders code spans, bold text and links of one line. Text inside
+// code spans is only escaped.
+func inline(s string) string {
+	var b strings.Builder
+	parts := strings.Split(s, "`")
+	for i, p := range parts {
+		switch {
+		case i%2 == 1 && i < len(parts)-1:
+			b.WriteString("<code>" + html.EscapeString(p) + "</code>")
+		case i%2 == 1:
+			// Unterminated code span.
+			b.WriteString("`" + format(p))
+		default:
+			b.WriteString(format(p))
+		}
+	}
+	return b.String()
+}
+
+func format(s string) string {
+	s = html.EscapeString(s)
+	s = boldPattern.ReplaceAllString(s, "<strong>$1</strong>")
+	return linkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkPattern.FindStringSubmatch(m)
+		return `<a href="` + pageLink(sub[2]) + `">` + sub[1] + "</a>"
+	})
+}
+
+// pageLink rewrites a relative link to a Markdown file to its page.
+func pageLink(href string) string {
+	if strings.Contains(href, "://") || strings.HasPrefix(href, "/") {
+		return href
+	}
+	path, frag, hasFrag := strings.Cut(href, "#")
+	if !strings.HasSuffix(path, ".md") {
+		return href
+	}
+	path = strings.TrimSuffix(path, ".md") + ".html"
+	if hasFrag {
+		return path + "#" + frag
+	}
+	return path
+}
+
+// stripInline removes inline Markdown markers, for heading anchors.
+func stripInline(s string) string {
+	return strings.NewReplacer("`", "", "**", "").Replace(s)
+}
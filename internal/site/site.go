@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package site renders generated Markdown documents as a self-contained
+// static site: one HTML page per document with the CSS embedded, a shared
+// navigation bar and client-side filtering of table rows, ready to publish
+// on GitHub Pages.
+//
+// Feature: REPORTS_CORE
+// Spec: spec/reports/core.md
+package site
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/projection"
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+// Dir is where the site is written, relative to the repository root.
+const Dir = "docs/__generated__/site"
+
+// Nav lists the pages of the site in navigation order. Pages are written by
+// different commands, so a page that was not generated yet is still linked.
+var Nav = []struct{ File, Title string }{
+	{"index.html", "Context"},
+	{"files.html", "Files"},
+	{"modules.html", "Modules"},
+	{"dependencies.html", "Dependencies"},
+	{"module-dependencies.html", "Module Dependencies"},
+	{"roadmap.html", "Roadmap"},
+}
+
+// Page is a page of the site.
+type Page struct {
+	// File is the page file name, such as "files.html".
+	File string
+	// Title defaults to the first heading of Markdown.
+	Title    string
+	Markdown string
+	// Search adds a box filtering the table rows of the page.
+	Search bool
+}
+
+// layout is the data of the site template.
+type layout struct {
+	Title  string
+	Nav    []navLink
+	Body   string
+	Search bool
+}
+
+type navLink struct {
+	Title, Href string
+	Current     bool
+}
+
+// Render returns the HTML document of p, laid out by the site template of set.
+func Render(set *templates.Set, p Page) (string, error) {
+	l := layout{Title: p.Title, Body: HTML(p.Markdown), Search: p.Search}
+	if l.Title == "" {
+		l.Title = title(p.Markdown)
+	}
+	for _, n := range Nav {
+		l.Nav = append(l.Nav, navLink{Title: n.Title, Href: n.File, Current: n.File == p.File})
+	}
+	return set.Render(templates.Site, l)
+}
+
+// Write renders pages into dir atomically.
+func Write(set *templates.Set, dir string, pages []Page) error {
+	for _, p := range pages {
+		out, err := Render(set, p)
+		if err != nil {
+			return err
+		}
+		if err := projection.AtomicWrite(filepath.Join(dir, p.File), []byte(out)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// title returns the text of the first heading of md.
+func title(md string) string {
+	for _, line := range strings.Split(md, "\n") {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			return stripInline(m[2])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/templates"
+)
+
+func TestHTML(t *testing.T) {
+	md := "# Module `Files`\n\n" +
+		"> **Source**: a <b>\n>\n> Note\n\n" +
+		"⸻\n\n" +
+		"Intro line\nsecond line.\n\n" +
+		"| Path | Note |\n| --- | --- |\n| `a\\|b` | [details](dependencies.md#gomod) |\n\n" +
+		"- [Files](files.md)\n- [Site](https://example.com/x.md)\n\n" +
+		"1. one\n2. two `x\n"
+	want := `<h1 id="module-files">Module <code>Files</code></h1>
+<blockquote>
+<p><strong>Source</strong>: a &lt;b&gt;</p>
+<p>Note</p>
+</blockquote>
+<hr>
+<p>Intro line
+second line.</p>
+<table>
+<thead>
+<tr><th>Path</th><th>Note</th></tr>
+</thead>
+<tbody>
+<tr><td><code>a|b</code></td><td><a href="dependencies.html#gomod">details</a></td></tr>
+</tbody>
+</table>
+<ul>
+<li><a href="files.html">Files</a></li>
+<li><a href="https://example.com/x.md">Site</a></li>
+</ul>
+<ol>
+<li>one</li>
+<li>two ` + "`x" + `</li>
+</ol>
+`
+	assert.Equal(t, want, HTML(md))
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	pages := []Page{
+		{File: "files.html", Markdown: "# File <Inventory>\n\n| Path |\n| --- |\n| a |\n", Search: true},
+		{File: "roadmap.html", Title: "Roadmap", Markdown: "text\n"},
+	}
+	require.NoError(t, Write(templates.New(""), dir, pages))
+
+	files, err := os.ReadFile(filepath.Join(dir, "files.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(files), "<title>File &lt;Inventory&gt; · Cortex</title>")
+	assert.Contains(t, string(files), `<a href="files.html" aria-current="page">Files</a>`)
+	assert.Contains(t, string(files), `<input id="search"`)
+	assert.Contains(t, string(files), "<style>")
+
+	roadmap, err := os.ReadFile(filepath.Join(dir, "roadmap.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(roadmap), "<title>Roadmap · Cortex</title>")
+	assert.NotContains(t, string(roadmap), "<script>")
+
+	again, err := Render(templates.New(""), pages[0])
+	require.NoError(t, err)
+	assert.Equal(t, string(files), again)
+	assert.True(t, strings.HasSuffix(again, "</html>\n"))
+}
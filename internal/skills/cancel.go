@@ -0,0 +1,21 @@
+package skills
+
+import (
+	"fmt"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// cancelled is the result of a skill that stopped early because its context
+// was cancelled or timed out; the runner reports a timeout as such.
+func cancelled(id string, err error) runner.SkillResult {
+	return runner.SkillResult{
+		Skill:    id,
+		Status:   runner.StatusFail,
+		ExitCode: runner.ExitExecution,
+		Note:     fmt.Sprintf("cancelled: %v", err),
+	}
+}
@@ -0,0 +1,129 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/envcheck"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+type ComposeEnvConsistency struct {
+	id string
+}
+
+func NewComposeEnvConsistency() runner.Skill {
+	return &ComposeEnvConsistency{id: "compose:env-consistency"}
+}
+
+func (s *ComposeEnvConsistency) ID() string { return s.id }
+
+func (s *ComposeEnvConsistency) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	cfg, err := config.Load(deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitConfig, Note: err.Error()}
+	}
+
+	files, err := deps.Scanner.TrackedFilesFiltered(ctx, scanner.FilterOptions{ExcludeDirs: scanner.DefaultExcludeDirs()})
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Failed to list files: %v", err),
+		}
+	}
+
+	var used, documented []envcheck.Ref
+	examples := 0
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
+		}
+
+		var parse func(string, []byte) []envcheck.Ref
+		switch {
+		case envcheck.IsExampleFile(f):
+			examples++
+		case envcheck.IsComposeFile(f):
+			parse = envcheck.ParseCompose
+		case strings.HasSuffix(f, ".go") && !strings.HasSuffix(f, "_test.go"):
+			parse = envcheck.ParseGo
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(deps.Path(f))
+		if err != nil {
+			return runner.SkillResult{
+				Skill:    s.id,
+				Status:   runner.StatusFail,
+				ExitCode: runner.ExitExecution,
+				Note:     fmt.Sprintf("Failed to read %s: %v", f, err),
+			}
+		}
+		if parse == nil {
+			documented = append(documented, envcheck.ParseExample(f, data)...)
+			continue
+		}
+		used = append(used, parse(f, data)...)
+	}
+
+	if examples == 0 {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   "No .env.example found",
+		}
+	}
+
+	ignore := append(append([]string(nil), envcheck.DefaultIgnore...), cfg.Env.Ignore...)
+	rep := envcheck.Check(used, documented, ignore)
+	if len(deps.TargetFiles) > 0 {
+		// Every file takes part in the check; only findings in the targeted
+		// files are reported.
+		targeted := make(map[string]bool)
+		for _, f := range deps.Targets(files) {
+			targeted[f] = true
+		}
+		untargeted := func(r envcheck.Ref) bool { return !targeted[r.Path] }
+		rep.Undocumented = envcheck.Check(slices.DeleteFunc(used, untargeted), documented, ignore).Undocumented
+		rep.Unused = slices.DeleteFunc(rep.Unused, untargeted)
+	}
+
+	status := runner.StatusPass
+	exitCode := runner.ExitOK
+	var notes []string
+	for _, r := range rep.Undocumented {
+		notes = append(notes, fmt.Sprintf("%s:%d: %s is not documented in .env.example", r.Path, r.Line, r.Name))
+	}
+	if len(rep.Undocumented) > 0 {
+		status = runner.StatusFail
+		exitCode = runner.ExitValidation
+	}
+
+	// Unused variables are warnings: stale but harmless.
+	for _, r := range rep.Unused {
+		notes = append(notes, fmt.Sprintf("%s:%d: WARNING: %s is documented but not used", r.Path, r.Line, r.Name))
+	}
+	if len(rep.Unused) > 0 && deps.FailOnWarning && status == runner.StatusPass {
+		status = runner.StatusFail
+		exitCode = runner.ExitWarning
+		notes = append(notes, "(Fail on warning)")
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   status,
+		ExitCode: exitCode,
+		Note:     strings.Join(notes, "\n"),
+	}
+}
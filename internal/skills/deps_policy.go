@@ -0,0 +1,154 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/depspolicy"
+	"github.com/bartekus/cortex/internal/gomod"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+type DepsPolicy struct {
+	id string
+}
+
+func NewDepsPolicy() runner.Skill {
+	return &DepsPolicy{id: "deps:policy"}
+}
+
+func (s *DepsPolicy) ID() string { return s.id }
+
+func (s *DepsPolicy) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	cfg, err := config.Load(deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitConfig, Note: err.Error()}
+	}
+	policy, err := depspolicy.Compile(cfg.Deps)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitConfig, Note: err.Error()}
+	}
+
+	files, err := deps.Scanner.TrackedFilesFiltered(ctx, scanner.FilterOptions{ExcludeDirs: scanner.DefaultExcludeDirs()})
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Failed to list files: %v", err),
+		}
+	}
+
+	var licenses depspolicy.LicenseFunc
+	if policy.ChecksLicenses() {
+		licenses = depspolicy.CacheLicenses(modCache(ctx))
+	}
+
+	var violations []depspolicy.Violation
+	modules, requirements := 0, 0
+	for _, f := range deps.Targets(files) {
+		if path.Base(f) != "go.mod" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
+		}
+		data, err := os.ReadFile(deps.Path(f))
+		if err != nil {
+			return runner.SkillResult{
+				Skill:    s.id,
+				Status:   runner.StatusFail,
+				ExitCode: runner.ExitExecution,
+				Note:     fmt.Sprintf("Failed to read %s: %v", f, err),
+			}
+		}
+		mod := gomod.Parse(data)
+		vs, err := policy.Check(f, mod, licenses)
+		if err != nil {
+			return runner.SkillResult{
+				Skill:    s.id,
+				Status:   runner.StatusFail,
+				ExitCode: runner.ExitExecution,
+				Note:     fmt.Sprintf("Failed to check %s: %v", f, err),
+			}
+		}
+		violations = append(violations, vs...)
+		modules++
+		requirements += len(mod.Require)
+	}
+	if modules == 0 {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusSkip, Note: "No go.mod found"}
+	}
+
+	var errs, warnings []string
+	waived := 0
+	for _, v := range violations {
+		switch {
+		case v.Waiver != "":
+			waived++
+		case v.Warning:
+			warnings = append(warnings, v.String())
+		default:
+			errs = append(errs, v.String())
+		}
+	}
+	// A stale exception would silently waive a future violation.
+	for _, e := range policy.Unused() {
+		warnings = append(warnings, fmt.Sprintf("WARNING: exception for %s waives nothing; remove it from deps.exceptions", e.Module))
+	}
+
+	status, exitCode := runner.StatusPass, runner.ExitOK
+	notes := errs
+	if len(errs) > 0 {
+		status, exitCode = runner.StatusFail, runner.ExitValidation
+	} else {
+		summary := fmt.Sprintf("%d requirements in %d go.mod files follow the dependency policy", requirements, modules)
+		if waived > 0 {
+			summary += fmt.Sprintf(" (%d violations waived)", waived)
+		}
+		notes = append(notes, summary+".")
+	}
+	notes = append(notes, warnings...)
+	if len(warnings) > 0 && deps.FailOnWarning && status == runner.StatusPass {
+		status = runner.StatusFail
+		exitCode = runner.ExitWarning
+		notes = append(notes, "(Fail on warning)")
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   status,
+		ExitCode: exitCode,
+		Note:     strings.Join(notes, "\n"),
+	}
+}
+
+// modCache returns the module cache directory: `go env GOMODCACHE`, or
+// without a go command $GOMODCACHE, then $GOPATH/pkg/mod, then
+// ~/go/pkg/mod.
+func modCache(ctx context.Context) string {
+	if out, err := exec.CommandContext(ctx, "go", "env", "GOMODCACHE").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			return dir
+		}
+	}
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, _ := os.UserHomeDir()
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
@@ -26,20 +26,24 @@ func NewDocsDocPatterns() runner.Skill {
 func (s *DocsDocPatterns) ID() string { return s.id }
 
 func (s *DocsDocPatterns) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
-	// Scan docs/
+	// Scan docs/, skipping hidden directories and generated or archived docs
 	opts := scanner.FilterOptions{
 		IncludeExtensions: []string{".md"},
+		IncludeDirs:       []string{"docs"},
+		ExcludePatterns:   []string{".*", "__generated__", "archive"},
 	}
 	files, err := deps.Scanner.TrackedFilesFiltered(ctx, opts)
 	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed: %v", err),
 		}
 	}
 
+	files = deps.Targets(files)
+
 	// Also check if docs/ exists? Or do we care?
 	// If files is empty, maybe docs doesn't exist?
 	// Just processing files found is safe.
@@ -50,30 +54,9 @@ func (s *DocsDocPatterns) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 	fileNameRegex := regexp.MustCompile(`^[A-Za-z0-9\-_]+\.md$`)
 
 	for _, p := range files {
-		// Only check docs/
-		if !strings.HasPrefix(p, "docs/") {
-			continue
-		}
-
-		// Check exclusions
-		// 1. Hidden directories (segment starts with .)
-		parts := strings.Split(p, string(filepath.Separator))
-		isHidden := false
-		for _, part := range parts {
-			if strings.HasPrefix(part, ".") && part != "." && part != ".." {
-				isHidden = true
-				break
-			}
-		}
-		if isHidden {
-			continue
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
 		}
-
-		// 2. Specific ignores
-		if strings.Contains(p, "/__generated__/") || strings.Contains(p, "/archive/") {
-			continue
-		}
-
 		// 1. Filename naming
 		base := filepath.Base(p)
 		if !fileNameRegex.MatchString(base) {
@@ -101,7 +84,7 @@ func (s *DocsDocPatterns) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(failures, "\n"),
 		}
 	}
@@ -109,7 +92,7 @@ func (s *DocsDocPatterns) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "No doc pattern violations found.",
 	}
 }
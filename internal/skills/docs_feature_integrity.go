@@ -43,7 +43,7 @@ func (s *DocsFeatureIntegrity) Run(ctx context.Context, deps *runner.Deps) runne
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Analysis failed: %v", err),
 		}
 	}
@@ -54,11 +54,11 @@ func (s *DocsFeatureIntegrity) Run(ctx context.Context, deps *runner.Deps) runne
 	lines = append(lines, fmt.Sprintf("Violations found: %d", len(report.Violations)))
 
 	status := runner.StatusPass
-	exitCode := 0
+	exitCode := runner.ExitOK
 
 	if len(report.Violations) > 0 {
 		status = runner.StatusFail
-		exitCode = 1
+		exitCode = runner.ExitValidation
 
 		lines = append(lines, "")
 		lines = append(lines, "Violations:")
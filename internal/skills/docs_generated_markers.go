@@ -0,0 +1,111 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// DocsGeneratedMarkers checks that the files matching files.generated carry
+// the "Code generated ... DO NOT EDIT." header, so editors, linters and
+// reviewers outside cortex recognize them as generated too.
+type DocsGeneratedMarkers struct {
+	id string
+}
+
+func NewDocsGeneratedMarkers() runner.Skill {
+	return &DocsGeneratedMarkers{id: "docs:generated-markers"}
+}
+
+func (s *DocsGeneratedMarkers) ID() string { return s.id }
+
+func (s *DocsGeneratedMarkers) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	cfg, err := config.Load(deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitConfig,
+			Note:     err.Error(),
+		}
+	}
+	if len(cfg.Files.Generated) == 0 {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   "No files.generated globs configured",
+		}
+	}
+	for _, g := range cfg.Files.Generated {
+		if err := scanner.ValidateGlob(g); err != nil {
+			return runner.SkillResult{
+				Skill:    s.id,
+				Status:   runner.StatusFail,
+				ExitCode: runner.ExitConfig,
+				Note:     "files.generated: " + err.Error(),
+			}
+		}
+	}
+
+	files, err := deps.Scanner.TrackedFilesFiltered(ctx, scanner.FilterOptions{IncludePatterns: cfg.Files.Generated})
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Scanner failed: %v", err),
+		}
+	}
+
+	// Files without comments (JSON, binaries) cannot carry the header.
+	var candidates []string
+	for _, p := range deps.Targets(files) {
+		if scanner.CanCarryMarker(p) {
+			candidates = append(candidates, p)
+		}
+	}
+	checked, err := scanner.ProcessFiles(ctx, candidates, 0, func(p string) (string, error) {
+		ok, err := scanner.FileHasGeneratedMarker(deps.Path(p))
+		switch {
+		case err != nil:
+			return fmt.Sprintf("%s: %v", p, err), nil
+		case !ok:
+			return fmt.Sprintf("%s: missing \"Code generated ... DO NOT EDIT.\" header", p), nil
+		}
+		return "", nil
+	})
+	if err != nil {
+		return cancelled(s.id, err)
+	}
+
+	var failures []string
+	for _, failure := range checked {
+		if failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitValidation,
+			Note:     strings.Join(failures, "\n"),
+		}
+	}
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   runner.StatusPass,
+		ExitCode: runner.ExitOK,
+		Note:     fmt.Sprintf("%d generated file(s) carry the header.", len(candidates)),
+	}
+}
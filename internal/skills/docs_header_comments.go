@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -47,52 +46,69 @@ func (s *DocsHeaderComments) Run(ctx context.Context, deps *runner.Deps) runner.
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed (go): %v", err),
 		}
 	}
 
 	specOpts := scanner.FilterOptions{
 		IncludeExtensions: []string{".md"},
+		IncludeDirs:       []string{"spec"},
 	}
 	mdFiles, err := deps.Scanner.TrackedFilesFiltered(ctx, specOpts)
 	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed (md): %v", err),
 		}
 	}
 
+	goFiles, mdFiles = deps.Targets(goFiles), deps.Targets(mdFiles)
+
 	var failures []string
 	var warnings []string
 
 	// 2. Check Go SPDX headers
-	for _, p := range goFiles {
+	goChecks, err := scanner.ProcessFiles(ctx, goFiles, 0, func(p string) (headerCheck, error) {
 		// "Required a line containing SPDX-License-Identifier: (exact prefix recommended)"
 		// First ~5 lines.
-		fullPath := filepath.Join(deps.RepoRoot, p)
+		fullPath := deps.Path(p)
 		warn, err := checkSPDX(fullPath)
 		if err != nil {
-			failures = append(failures, fmt.Sprintf("%s: %v", p, err))
+			return headerCheck{failure: fmt.Sprintf("%s: %v", p, err)}, nil
 		} else if warn != "" {
-			warnings = append(warnings, fmt.Sprintf("%s: %s", p, warn))
+			return headerCheck{warning: fmt.Sprintf("%s: %s", p, warn)}, nil
 		}
+		return headerCheck{}, nil
+	})
+	if err != nil {
+		return cancelled(s.id, err)
 	}
 
 	// 3. Check Spec Frontmatter
 	// spec/**/*.md excluding README
-	for _, p := range mdFiles {
-		if !strings.HasPrefix(p, "spec/") {
-			continue
-		}
+	mdChecks, err := scanner.ProcessFiles(ctx, mdFiles, 0, func(p string) (headerCheck, error) {
 		if strings.HasSuffix(strings.ToLower(p), "readme.md") {
-			continue
+			return headerCheck{}, nil
 		}
-		fullPath := filepath.Join(deps.RepoRoot, p)
+		fullPath := deps.Path(p)
 		if err := checkFrontmatter(fullPath); err != nil {
-			failures = append(failures, fmt.Sprintf("%s: %v", p, err))
+			return headerCheck{failure: fmt.Sprintf("%s: %v", p, err)}, nil
+		}
+		return headerCheck{}, nil
+	})
+	if err != nil {
+		return cancelled(s.id, err)
+	}
+
+	for _, c := range append(goChecks, mdChecks...) {
+		if c.failure != "" {
+			failures = append(failures, c.failure)
+		}
+		if c.warning != "" {
+			warnings = append(warnings, c.warning)
 		}
 	}
 
@@ -103,7 +119,7 @@ func (s *DocsHeaderComments) Run(ctx context.Context, deps *runner.Deps) runner.
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(failures, "\n"),
 		}
 	}
@@ -113,7 +129,7 @@ func (s *DocsHeaderComments) Run(ctx context.Context, deps *runner.Deps) runner.
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusPass,
-			ExitCode: 0,
+			ExitCode: runner.ExitOK,
 			Note:     "Warnings:\n" + strings.Join(warnings, "\n"),
 		}
 	}
@@ -121,11 +137,18 @@ func (s *DocsHeaderComments) Run(ctx context.Context, deps *runner.Deps) runner.
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "All checked files have correct headers.",
 	}
 }
 
+// headerCheck is the outcome of checking one file: a failure, a warning or
+// neither.
+type headerCheck struct {
+	failure string
+	warning string
+}
+
 func checkSPDX(path string) (string, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -34,6 +34,15 @@ func (s *DocsOrphanDocs) ID() string { return s.id }
 // We use the index-based API so we can ignore image links like ![alt](img.png).
 var linkRegex = regexp.MustCompile(`\[([^\]]*)\]\(([^)]*)\)`)
 
+// docCandidates selects the docs that should be linked from another doc
+// or spec. Hidden directories, docs/archive/ and docs/__generated__/ are
+// intentional dead zones.
+var docCandidates = scanner.FilterOptions{
+	IncludeExtensions: []string{".md"},
+	IncludeDirs:       []string{"docs"},
+	ExcludePatterns:   []string{".*", "docs/archive", "docs/__generated__"},
+}
+
 func (s *DocsOrphanDocs) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
 	// 1. Repo Sensitivity: If docs/ directory is missing, SKIP.
 	docsDir := filepath.Join(deps.RepoRoot, "docs")
@@ -46,163 +55,77 @@ func (s *DocsOrphanDocs) Run(ctx context.Context, deps *runner.Deps) runner.Skil
 	}
 
 	// 2. Identify Candidates: tracked docs/**/*.md
-	// Exclude archive, generated?
-	// User said "exclude docs/archive/ or other intentional dead zones".
-	candidateOpts := scanner.FilterOptions{
-		IncludeExtensions: []string{".md"},
+	docs, err := deps.Scanner.TrackedFilesFiltered(ctx, docCandidates)
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Scanner failed: %v", err),
+		}
 	}
-	allFiles, err := deps.Scanner.TrackedFilesFiltered(ctx, candidateOpts)
+	// Specs are sources of references too
+	specs, err := deps.Scanner.TrackedFilesFiltered(ctx, scanner.FilterOptions{
+		IncludeExtensions: []string{".md"},
+		IncludeDirs:       []string{"spec"},
+	})
 	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed: %v", err),
 		}
 	}
 
+	// Every doc and spec is a source of links; only the targeted docs are
+	// checked for being linked.
 	candidates := make(map[string]bool)
-	docSources := []string{}
-
-	// Filter candidates (only docs/**/*.md, not ignored)
-	for _, p := range allFiles {
-		if strings.HasPrefix(p, "docs/") {
-			// Check for hidden directories (e.g. docs/.hidden/...)
-			parts := strings.Split(p, "/")
-			hidden := false
-			for _, part := range parts {
-				if strings.HasPrefix(part, ".") && part != "." && part != ".." {
-					hidden = true
-					break
-				}
-			}
-			if hidden {
-				continue
-			}
+	for _, p := range deps.Targets(docs) {
+		candidates[p] = true
+	}
+	docSources := append(docs, specs...)
 
-			// Candidates must be in docs/
-			// Exclude docs/archive/ and docs/__generated__/
-			if strings.HasPrefix(p, "docs/archive/") || strings.HasPrefix(p, "docs/__generated__/") {
-				continue
-			}
-			candidates[p] = true
-			docSources = append(docSources, p)
-		} else if strings.HasPrefix(p, "spec/") {
-			// Specs are sources of references too
-			docSources = append(docSources, p)
+	// Docs created but never added to git are outside governance: nothing
+	// checks them and links to them break on checkout. They are warnings.
+	untracked, err := deps.Scanner.UntrackedFiles(ctx)
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Scanner failed: %v", err),
 		}
 	}
+	var warnings []string
+	for _, p := range scanner.FilterFiles(deps.Targets(untracked), docCandidates) {
+		warnings = append(warnings, fmt.Sprintf("WARNING: %s is not tracked by git", p))
+	}
 
 	if len(candidates) == 0 {
-		return runner.SkillResult{
-			Skill:  s.id,
-			Status: runner.StatusPass, // No docs to be orphans
-			Note:   "No docs candidates found",
-		}
+		return s.result(deps, runner.StatusPass, runner.ExitOK, []string{"No docs candidates found"}, warnings)
 	}
 
 	// 3. Scan references
-	referencedDocs := make(map[string]bool)
-
-	for _, src := range docSources {
-		srcPath := filepath.Join(deps.RepoRoot, src)
-		// We need relative resolution.
-		// If src is "docs/guide.md" and links to "setup.md", it means "docs/setup.md".
-		srcDir := path.Dir(src) // use path (forward slash) as we cleaned paths from scanner?
-		// Scanner returns result of git ls-files, mostly forward slash on Mac/Linux, but let's be safe.
-		// Actually scanner.FilterFiles sorts strings.
-		// Let's ensure forward slashes for math.
-		srcDir = filepath.ToSlash(srcDir)
-
-		f, err := os.Open(srcPath)
-		if err != nil {
-			// Warn or skip?
-			// Fail for now as it's unexpected for a tracked file
-			return runner.SkillResult{
-				Skill:    s.id,
-				Status:   runner.StatusFail,
-				ExitCode: 4,
-				Note:     fmt.Sprintf("Failed to read %s: %v", src, err),
-			}
+	perSource, err := scanner.ProcessFiles(ctx, docSources, 0, func(src string) ([]string, error) {
+		return docLinks(deps.Path(src), src)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return cancelled(s.id, err)
 		}
-
-		scn := bufio.NewScanner(f)
-		// Increase buffer to handle long markdown lines (default 64K can be too small).
-		scn.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-
-		inCodeFence := false
-
-		for scn.Scan() {
-			line := scn.Text()
-
-			// Skip fenced code blocks to avoid counting example links.
-			// We treat any line starting with ``` as a fence toggle.
-			if strings.HasPrefix(strings.TrimSpace(line), "```") {
-				inCodeFence = !inCodeFence
-				continue
-			}
-			if inCodeFence {
-				continue
-			}
-
-			// Use index-based matching so we can ignore image links.
-			matches := linkRegex.FindAllStringSubmatchIndex(line, -1)
-			for _, mi := range matches {
-				// mi contains pairs: full match, group1, group2.
-				// Ignore image links: ![alt](...)
-				if mi[0] > 0 && line[mi[0]-1] == '!' {
-					continue
-				}
-
-				// Extract group 2 (target)
-				if len(mi) < 6 {
-					continue
-				}
-				target := line[mi[4]:mi[5]]
-				// Ignore external
-				if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "/") {
-					continue
-				}
-
-				// Strip anchors/queries
-				if idx := strings.Index(target, "#"); idx != -1 {
-					target = target[:idx]
-				}
-				if idx := strings.Index(target, "?"); idx != -1 {
-					target = target[:idx]
-				}
-
-				target = strings.TrimSpace(target)
-				if target == "" {
-					continue
-				}
-
-				// Ignore non-markdown / non-file targets quickly.
-				// (We only consider .md links for orphan-doc detection.)
-				if !strings.HasSuffix(target, ".md") {
-					continue
-				}
-
-				// Resolve path.
-				// path.Join cleans and is slash-stable.
-				resolved := path.Clean(path.Join(srcDir, target))
-
-				// Only record links into docs/.
-				if strings.HasPrefix(resolved, "docs/") {
-					referencedDocs[resolved] = true
-				}
-			}
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Failed to %v", err),
 		}
-		if err := scn.Err(); err != nil {
-			_ = f.Close()
-			return runner.SkillResult{
-				Skill:    s.id,
-				Status:   runner.StatusFail,
-				ExitCode: 4,
-				Note:     fmt.Sprintf("Failed to scan %s: %v", src, err),
-			}
+	}
+	referencedDocs := make(map[string]bool)
+	for _, links := range perSource {
+		for _, l := range links {
+			referencedDocs[l] = true
 		}
-		_ = f.Close()
 	}
 
 	// 4. Calculate Orphans
@@ -229,18 +152,114 @@ func (s *DocsOrphanDocs) Run(ctx context.Context, deps *runner.Deps) runner.Skil
 		for _, o := range orphans {
 			lines = append(lines, fmt.Sprintf("- %s", o))
 		}
-		return runner.SkillResult{
-			Skill:    s.id,
-			Status:   runner.StatusFail,
-			ExitCode: 1,
-			Note:     strings.Join(lines, "\n"),
-		}
+		return s.result(deps, runner.StatusFail, runner.ExitValidation, lines, warnings)
 	}
 
+	return s.result(deps, runner.StatusPass, runner.ExitOK, []string{"No orphan docs found."}, warnings)
+}
+
+// result appends the warnings to the note; with --fail-on-warning they
+// fail an otherwise passing run.
+func (s *DocsOrphanDocs) result(deps *runner.Deps, status runner.SkillStatus, exitCode int, lines, warnings []string) runner.SkillResult {
+	lines = append(lines, warnings...)
+	if len(warnings) > 0 && deps.FailOnWarning && status == runner.StatusPass {
+		status = runner.StatusFail
+		exitCode = runner.ExitWarning
+		lines = append(lines, "(Fail on warning)")
+	}
 	return runner.SkillResult{
 		Skill:    s.id,
-		Status:   runner.StatusPass,
-		ExitCode: 0,
-		Note:     "No orphan docs found.",
+		Status:   status,
+		ExitCode: exitCode,
+		Note:     strings.Join(lines, "\n"),
+	}
+}
+
+// docLinks returns the docs/ files the markdown file src (at srcPath)
+// links to, resolved against its directory. Errors name the operation and
+// src: "read docs/a.md: ...".
+func docLinks(srcPath, src string) ([]string, error) {
+	// We need relative resolution.
+	// If src is "docs/guide.md" and links to "setup.md", it means "docs/setup.md".
+	srcDir := path.Dir(src)
+
+	f, err := os.Open(srcPath)
+	if err != nil {
+		// Fail for now as it's unexpected for a tracked file
+		return nil, fmt.Errorf("read %s: %w", src, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scn := bufio.NewScanner(f)
+	// Increase buffer to handle long markdown lines (default 64K can be too small).
+	scn.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	inCodeFence := false
+	var links []string
+
+	for scn.Scan() {
+		line := scn.Text()
+
+		// Skip fenced code blocks to avoid counting example links.
+		// We treat any line starting with ``` as a fence toggle.
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeFence = !inCodeFence
+			continue
+		}
+		if inCodeFence {
+			continue
+		}
+
+		// Use index-based matching so we can ignore image links.
+		matches := linkRegex.FindAllStringSubmatchIndex(line, -1)
+		for _, mi := range matches {
+			// mi contains pairs: full match, group1, group2.
+			// Ignore image links: ![alt](...)
+			if mi[0] > 0 && line[mi[0]-1] == '!' {
+				continue
+			}
+
+			// Extract group 2 (target)
+			if len(mi) < 6 {
+				continue
+			}
+			target := line[mi[4]:mi[5]]
+			// Ignore external
+			if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "/") {
+				continue
+			}
+
+			// Strip anchors/queries
+			if idx := strings.Index(target, "#"); idx != -1 {
+				target = target[:idx]
+			}
+			if idx := strings.Index(target, "?"); idx != -1 {
+				target = target[:idx]
+			}
+
+			target = strings.TrimSpace(target)
+			if target == "" {
+				continue
+			}
+
+			// Ignore non-markdown / non-file targets quickly.
+			// (We only consider .md links for orphan-doc detection.)
+			if !strings.HasSuffix(target, ".md") {
+				continue
+			}
+
+			// Resolve path.
+			// path.Join cleans and is slash-stable.
+			resolved := path.Clean(path.Join(srcDir, target))
+
+			// Only record links into docs/.
+			if strings.HasPrefix(resolved, "docs/") {
+				links = append(links, resolved)
+			}
+		}
+	}
+	if err := scn.Err(); err != nil {
+		return nil, fmt.Errorf("scan %s: %w", src, err)
 	}
+	return links, nil
 }
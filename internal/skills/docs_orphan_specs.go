@@ -61,7 +61,7 @@ func (s *DocsOrphanSpecs) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Failed to load features graph: %v", err),
 		}
 	}
@@ -79,8 +79,7 @@ func (s *DocsOrphanSpecs) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 	// 3. Scan tracked files in spec/
 	opts := scanner.FilterOptions{
 		IncludeExtensions: []string{".md"},
-		// We can't filter by dir in options yet (ExcludeDirs only).
-		// So we get all .md and filter for "spec/".
+		IncludeDirs:       []string{"spec"},
 	}
 
 	allMdFiles, err := deps.Scanner.TrackedFilesFiltered(ctx, opts)
@@ -88,7 +87,7 @@ func (s *DocsOrphanSpecs) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed: %v", err),
 		}
 	}
@@ -101,13 +100,10 @@ func (s *DocsOrphanSpecs) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 		return strings.HasSuffix(strings.ToLower(path), "readme.md")
 	}
 
-	for _, path := range allMdFiles {
-		// path is relative to RepoRoot
-		// Check if it's in spec/
-		if !strings.HasPrefix(path, "spec/") {
-			continue
+	for _, path := range deps.Targets(allMdFiles) {
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
 		}
-
 		// Clean path for comparison
 		clean := filepath.ToSlash(filepath.Clean(path))
 
@@ -131,7 +127,7 @@ func (s *DocsOrphanSpecs) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(lines, "\n"),
 		}
 	}
@@ -139,7 +135,7 @@ func (s *DocsOrphanSpecs) Run(ctx context.Context, deps *runner.Deps) runner.Ski
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "No orphan specs found.",
 	}
 }
@@ -35,10 +35,13 @@ func (s *DocsPolicy) Run(ctx context.Context, deps *runner.Deps) runner.SkillRes
 	skipped := false
 
 	for _, check := range s.checks {
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
+		}
 		res := check.Run(ctx, deps)
 
 		switch res.Status {
-		case runner.StatusFail:
+		case runner.StatusFail, runner.StatusTimeout:
 			failed = true
 			notes = append(notes, fmt.Sprintf("FAIL [%s]: %s", check.ID(), oneLine(res.Note)))
 		case runner.StatusSkip:
@@ -53,7 +56,7 @@ func (s *DocsPolicy) Run(ctx context.Context, deps *runner.Deps) runner.SkillRes
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(notes, "\n"),
 		}
 	}
@@ -62,7 +65,7 @@ func (s *DocsPolicy) Run(ctx context.Context, deps *runner.Deps) runner.SkillRes
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusPass,
-			ExitCode: 0,
+			ExitCode: runner.ExitOK,
 			Note:     fmt.Sprintf("Policy passed (with skips):\n%s", strings.Join(notes, "\n")),
 		}
 	}
@@ -70,7 +73,7 @@ func (s *DocsPolicy) Run(ctx context.Context, deps *runner.Deps) runner.SkillRes
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "All policy checks passed.",
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -40,25 +41,26 @@ func (s *DocsProviderGovernance) Run(ctx context.Context, deps *runner.Deps) run
 	// 2. Scan for provider specs
 	opts := scanner.FilterOptions{
 		IncludeExtensions: []string{".md"},
+		IncludeDirs:       []string{"spec/providers"},
 	}
 	allFiles, err := deps.Scanner.TrackedFilesFiltered(ctx, opts)
 	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed: %v", err),
 		}
 	}
 
+	allFiles = deps.Targets(allFiles)
+
 	var missingDocs []string
 
 	for _, p := range allFiles {
-		// Only check spec/providers/*.md
-		if !strings.HasPrefix(p, "spec/providers/") {
-			continue
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
 		}
-
 		// Exclude README.md
 		if strings.HasSuffix(strings.ToLower(p), "readme.md") {
 			continue
@@ -83,37 +85,37 @@ func (s *DocsProviderGovernance) Run(ctx context.Context, deps *runner.Deps) run
 		candidates := []string{}
 
 		// 1. Direct mirror
-		candidates = append(candidates, filepath.Join("docs", "providers", relPath))
+		candidates = append(candidates, path.Join("docs", "providers", relPath))
 
 		// Logic to extract category and name
 		// relPath = backend/encore-ts.md
-		dir := filepath.Dir(relPath)               // backend
-		nameExt := filepath.Base(relPath)          // encore-ts.md
+		dir := path.Dir(relPath)                   // backend
+		nameExt := path.Base(relPath)              // encore-ts.md
 		name := strings.TrimSuffix(nameExt, ".md") // encore-ts
 
 		// 2. Category Index? Only if it's a "provider" inside a "category".
 		// If spec is spec/providers/aws.md, dir=".", name="aws". Category index docs/providers.md? Maybe.
 		// User example: spec/providers/backend/encore-ts.md -> docs/providers/backend.md
 		if dir != "." {
-			categoryDoc := filepath.Join("docs", "providers", dir+".md")
+			categoryDoc := path.Join("docs", "providers", dir+".md")
 			candidates = append(candidates, categoryDoc)
 		}
 
 		// 3. Folder README
 		// docs/providers/backend/encore-ts/README.md
-		candidates = append(candidates, filepath.Join("docs", "providers", dir, name, "README.md"))
+		candidates = append(candidates, path.Join("docs", "providers", dir, name, "README.md"))
 
 		// 4. Folder Index
 		// docs/providers/backend/encore-ts/index.md
-		candidates = append(candidates, filepath.Join("docs", "providers", dir, name, "index.md"))
+		candidates = append(candidates, path.Join("docs", "providers", dir, name, "index.md"))
 
 		// 5. Root Name Match
 		// spec/providers/integration/terraform.md -> docs/providers/terraform.md
-		candidates = append(candidates, filepath.Join("docs", "providers", name+".md"))
+		candidates = append(candidates, path.Join("docs", "providers", name+".md"))
 
 		found := false
 		for _, c := range candidates {
-			if _, err := os.Stat(filepath.Join(deps.RepoRoot, c)); err == nil {
+			if _, err := os.Stat(deps.Path(c)); err == nil {
 				found = true
 				break
 			}
@@ -129,7 +131,7 @@ func (s *DocsProviderGovernance) Run(ctx context.Context, deps *runner.Deps) run
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(missingDocs, "\n"),
 		}
 	}
@@ -137,7 +139,7 @@ func (s *DocsProviderGovernance) Run(ctx context.Context, deps *runner.Deps) run
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "All provider specs have matching docs.",
 	}
 }
@@ -53,7 +53,7 @@ func (s *DocsValidateSpec) Run(ctx context.Context, deps *runner.Deps) runner.Sk
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4, // Execution error
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Failed to load specs: %v", err),
 		}
 	}
@@ -68,11 +68,11 @@ func (s *DocsValidateSpec) Run(ctx context.Context, deps *runner.Deps) runner.Sk
 
 	var notes []string
 	status := runner.StatusPass
-	exitCode := 0
+	exitCode := runner.ExitOK
 
 	if err := specschema.ValidateAll(specs); err != nil {
 		status = runner.StatusFail
-		exitCode = 1
+		exitCode = runner.ExitValidation
 		notes = append(notes, fmt.Sprintf("Spec validation failed: %v", err))
 	} else {
 		notes = append(notes, fmt.Sprintf("Validated %d spec file(s)", len(specs)))
@@ -48,61 +48,53 @@ func (s *DocsYaml) Run(ctx context.Context, deps *runner.Deps) runner.SkillResul
 
 	opts := scanner.FilterOptions{
 		IncludeExtensions: []string{".yaml", ".yml"},
-		// We only want files inside "spec/" for now?
-		// Or maybe everything?
 		// "Implement docs:yaml as a thin 'fast parse' check... catches invalid YAML or missing registry files"
-		// This implies it checks the registry files specifically.
-		// Let's check ALL tracked yaml files in the repo to be helpful?
-		// No, might be too broad. Let's start with `spec/`.
+		// This implies it checks the registry files specifically, so only `spec/`.
+		IncludeDirs: []string{"spec"},
 	}
 
-	// We need to filter scanner results by directory manually or add Dir option to scanner?
-	// Scanner returns all tracked files.
-	// We'll filter in loop.
-
 	files, err := deps.Scanner.TrackedFilesFiltered(ctx, opts)
 	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanning failed: %v", err),
 		}
 	}
 
-	var failedFiles []string
-	var checkedCount int
-
-	for _, path := range files {
-		// Only check spec/ directory?
-		// And maybe root features.yaml?
-		// path is relative to repo root (TrackedFilesFiltered returns relative paths).
-		if !strings.HasPrefix(path, "spec/") && path != "spec/features.yaml" { // redundant check but clear
-			continue
-		}
-
-		checkedCount++
-		fullPath := filepath.Join(deps.RepoRoot, path)
-
-		f, err := os.Open(fullPath)
+	files = deps.Targets(files)
+	checked, err := scanner.ProcessFiles(ctx, files, 0, func(path string) (string, error) {
+		f, err := os.Open(deps.Path(path))
 		if err != nil {
-			failedFiles = append(failedFiles, fmt.Sprintf("%s: open error: %v", path, err))
-			continue
+			return fmt.Sprintf("%s: open error: %v", path, err), nil
 		}
+		defer func() { _ = f.Close() }()
 
 		var node yaml.Node
 		dec := yaml.NewDecoder(f)
 		if err := dec.Decode(&node); err != nil {
-			failedFiles = append(failedFiles, fmt.Sprintf("%s: invalid YAML: %v", path, err))
+			return fmt.Sprintf("%s: invalid YAML: %v", path, err), nil
+		}
+		return "", nil
+	})
+	if err != nil {
+		return cancelled(s.id, err)
+	}
+
+	var failedFiles []string
+	checkedCount := len(files)
+	for _, failure := range checked {
+		if failure != "" {
+			failedFiles = append(failedFiles, failure)
 		}
-		_ = f.Close()
 	}
 
 	if len(failedFiles) > 0 {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(failedFiles, "\n"),
 		}
 	}
@@ -110,7 +102,7 @@ func (s *DocsYaml) Run(ctx context.Context, deps *runner.Deps) runner.SkillResul
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     fmt.Sprintf("Validated %d YAML files in spec/", checkedCount),
 	}
 }
@@ -0,0 +1,161 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// portabilityRule flags a non-portable construct on a line of Go code.
+type portabilityRule struct {
+	re *regexp.Regexp
+	// unless exempts lines containing it.
+	unless  string
+	message string
+}
+
+// portabilityRules are checked in order; a line is reported for the first
+// rule it matches.
+var portabilityRules = []portabilityRule{
+	{
+		re:      regexp.MustCompile(`exec\.Command(Context)?\((ctx, )?"(/bin/)?(ba)?sh"`),
+		message: "runs a POSIX shell, which Windows lacks; run the program directly",
+	},
+	{
+		re:      regexp.MustCompile(`"/" [+]|[+] "/"|[+] '/'`),
+		message: `path built by concatenating "/"; use filepath.Join for OS paths or path.Join for repository paths`,
+	},
+	{
+		re:      regexp.MustCompile(`filepath\.(Join|Dir|Base|Clean)\([^)]*"[^"]*/[^"]*"`),
+		message: "slash-separated literal passed to filepath; pass the elements separately or use filepath.FromSlash",
+	},
+	{
+		re:      regexp.MustCompile(`filepath\.Rel\(`),
+		unless:  "ToSlash",
+		message: "filepath.Rel returns backslashes on Windows; use scanner.RelPath or convert with filepath.ToSlash",
+	},
+	{
+		re:      regexp.MustCompile(`"/tmp(/[^"]*)?"`),
+		message: "hard-coded /tmp; use os.TempDir or t.TempDir",
+	},
+	{
+		re:      regexp.MustCompile(`"/dev/null"`),
+		message: `hard-coded /dev/null; use os.DevNull`,
+	},
+	{
+		re:      regexp.MustCompile(`Getenv\("PATH"\)[^;]*":"|":"[^;]*Getenv\("PATH"\)`),
+		message: `PATH split on ":"; use filepath.SplitList or os.PathListSeparator`,
+	},
+}
+
+// EnvWindowsPaths flags non-portable path and shell handling added to Go
+// files since the merge-base, so it cannot creep back in once removed.
+type EnvWindowsPaths struct {
+	id string
+}
+
+func NewEnvWindowsPaths() runner.Skill {
+	return &EnvWindowsPaths{id: "env:windows-paths"}
+}
+
+func (s *EnvWindowsPaths) ID() string { return s.id }
+
+func (s *EnvWindowsPaths) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	cfg, err := config.Load(deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+
+	baseRef, candidates := findBaseRef(ctx, deps.RepoRoot, cfg.Commits.Base)
+	if baseRef == "" {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   fmt.Sprintf("No base ref found (tried %s)", strings.Join(candidates, ", ")),
+		}
+	}
+	mergeBase, err := git.MergeBase(ctx, deps.RepoRoot, baseRef, "HEAD")
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+
+	lines, err := git.AddedLines(ctx, deps.RepoRoot, mergeBase, "*.go")
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+
+	paths := make([]string, 0, len(lines))
+	for _, l := range lines {
+		paths = append(paths, l.Path)
+	}
+	targeted := make(map[string]bool)
+	for _, p := range deps.Targets(paths) {
+		targeted[p] = true
+	}
+
+	var findings []string
+	for _, l := range lines {
+		if !targeted[l.Path] || strings.HasPrefix(l.Path, "vendor/") || strings.Contains("/"+l.Path, "/testdata/") {
+			continue
+		}
+		if msg := checkPortability(l.Text); msg != "" {
+			findings = append(findings, fmt.Sprintf("%s:%d: %s", l.Path, l.Line, msg))
+		}
+	}
+	if len(findings) > 0 {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitValidation,
+			Note:     strings.Join(findings, "\n"),
+		}
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   runner.StatusPass,
+		ExitCode: runner.ExitOK,
+		Note:     fmt.Sprintf("%d Go line(s) added since %s use portable paths.", len(lines), baseRef),
+	}
+}
+
+// checkPortability returns the message of the first rule a line of Go code
+// matches, or "". Comments are not checked.
+func checkPortability(line string) string {
+	code := strings.TrimSpace(line)
+	if strings.HasPrefix(code, "//") {
+		return ""
+	}
+	if i := strings.Index(code, " //"); i >= 0 {
+		code = code[:i]
+	}
+	for _, r := range portabilityRules {
+		if r.re.MatchString(code) && (r.unless == "" || !strings.Contains(code, r.unless)) {
+			return r.message
+		}
+	}
+	return ""
+}
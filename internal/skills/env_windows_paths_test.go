@@ -0,0 +1,48 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPortability(t *testing.T) {
+	var (
+		shell   = portabilityRules[0].message
+		concat  = portabilityRules[1].message
+		literal = portabilityRules[2].message
+		rel     = portabilityRules[3].message
+		tmp     = portabilityRules[4].message
+		devNull = portabilityRules[5].message
+		pathSep = portabilityRules[6].message
+	)
+	tests := []struct {
+		name, line, want string
+	}{
+		{"sh", `out, err := exec.Command("sh", "-c", script).Output()`, shell},
+		{"bash with context", `cmd := exec.CommandContext(ctx, "/bin/bash", "-c", script)`, shell},
+		{"slash concatenation", `p := dir + "/" + name`, concat},
+		{"slash rune concatenation", `p := dir + '/' + name`, concat},
+		{"slash literal in filepath", `p := filepath.Join(root, "spec/features.yaml")`, literal},
+		{"filepath.Rel", `rel, err := filepath.Rel(root, p)`, rel},
+		{"/tmp", `dir := "/tmp/cortex"`, tmp},
+		{"/dev/null", `f, err := os.OpenFile("/dev/null", os.O_WRONLY, 0)`, devNull},
+		{"PATH split", `dirs := strings.Split(os.Getenv("PATH"), ":")`, pathSep},
+		{"first rule wins", `exec.Command("sh", "/tmp/x")`, shell},
+
+		{"filepath.Rel with ToSlash", `rel, err := filepath.Rel(root, p); rel = filepath.ToSlash(rel)`, ""},
+		{"comment line", `// exec.Command("sh", "-c", script)`, ""},
+		{"trailing comment", `dir := os.TempDir() // not "/tmp"`, ""},
+
+		{"path.Join takes slashes", `p := path.Join(dir, "spec/features.yaml")`, ""},
+		{"filepath elements", `p := filepath.Join(root, "spec", "features.yaml")`, ""},
+		{"URL path", `url := base + "/api/v1"`, ""},
+		{"program starting with sh", `exec.Command("shellcheck", file)`, ""},
+		{"/tmpl", `tmpl := "/tmpl/index.html"`, ""},
+		{"PATHEXT", `exts := strings.Split(os.Getenv("PATHEXT"), ";")`, ""},
+		{"os.DevNull", `f, err := os.Open(os.DevNull)`, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, checkPortability("\t"+tt.line), tt.name)
+	}
+}
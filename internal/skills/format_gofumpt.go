@@ -3,7 +3,6 @@ package skills
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/bartekus/cortex/internal/runner"
@@ -35,7 +34,7 @@ func (s *FormatGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.Skill
 			return runner.SkillResult{
 				Skill:    s.ID(),
 				Status:   runner.StatusFail,
-				ExitCode: 4,
+				ExitCode: runner.ExitExecution,
 				Note:     fmt.Sprintf("Failed to list files: %v", err),
 			}
 		}
@@ -45,18 +44,27 @@ func (s *FormatGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.Skill
 		return runner.SkillResult{
 			Skill:    s.ID(),
 			Status:   runner.StatusPass,
-			ExitCode: 0,
+			ExitCode: runner.ExitOK,
 			Note:     "No Go files to format",
 		}
 	}
 
-	// 2. Check if gofumpt is installed
-	if _, err := exec.LookPath("gofumpt"); err != nil {
+	// 2. Check that gofumpt is installed and matches tools.lock
+	bin, err := deps.LookTool("gofumpt")
+	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.ID(),
 			Status:   runner.StatusFail,
-			ExitCode: 2,
-			Note:     "gofumpt not found. Run: go install mvdan.cc/gofumpt@v0.6.0",
+			ExitCode: runner.ExitConfig,
+			Note:     missingTool("gofumpt"),
+		}
+	}
+	if err := deps.VerifyTool("gofumpt", bin); err != nil {
+		return runner.SkillResult{
+			Skill:    s.ID(),
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitConfig,
+			Note:     err.Error(),
 		}
 	}
 
@@ -69,13 +77,13 @@ func (s *FormatGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.Skill
 		}
 
 		batch := files[i:end]
-		cmd := exec.CommandContext(ctx, "gofumpt", append([]string{"-w"}, batch...)...)
+		cmd := deps.Command(ctx, bin, append([]string{"-w"}, batch...)...)
 		out, err := cmd.CombinedOutput()
 		if err != nil {
 			return runner.SkillResult{
 				Skill:    s.ID(),
 				Status:   runner.StatusFail,
-				ExitCode: 4,
+				ExitCode: runner.ExitExecution,
 				Note:     fmt.Sprintf("gofumpt failed: %v\n%s", err, string(out)),
 			}
 		}
@@ -84,6 +92,6 @@ func (s *FormatGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.Skill
 	return runner.SkillResult{
 		Skill:    s.ID(),
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 	}
 }
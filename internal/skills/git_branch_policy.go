@@ -0,0 +1,138 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/branchpolicy"
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+type GitBranchPolicy struct {
+	id string
+}
+
+func NewGitBranchPolicy() runner.Skill {
+	return &GitBranchPolicy{id: "git:branch-policy"}
+}
+
+func (s *GitBranchPolicy) ID() string { return s.id }
+
+func (s *GitBranchPolicy) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	fail := func(err error) runner.SkillResult {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+
+	cfg, err := config.Load(deps.RepoRoot)
+	if err != nil {
+		return fail(err)
+	}
+	patterns, err := branchpolicy.Compile(cfg.Branches.Patterns)
+	if err != nil {
+		return fail(err)
+	}
+
+	branch, err := git.CurrentBranch(ctx, deps.RepoRoot)
+	if err != nil {
+		return fail(err)
+	}
+	if branch == "" {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusSkip, Note: "HEAD is detached"}
+	}
+
+	baseRef, candidates := findBaseRef(ctx, deps.RepoRoot, cfg.Commits.Base)
+	if baseRef == "" {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   fmt.Sprintf("No base ref found (tried %s)", strings.Join(candidates, ", ")),
+		}
+	}
+	if branch == baseRef || "origin/"+branch == baseRef {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   fmt.Sprintf("On base branch %s", branch),
+		}
+	}
+
+	var findings []string
+
+	// 1. Branch name
+	feature, ok := branchpolicy.Match(patterns, branch)
+	if !ok {
+		raw := make([]string, len(patterns))
+		for i, p := range patterns {
+			raw[i] = p.Raw
+		}
+		findings = append(findings, fmt.Sprintf("branch %q does not match any of %s", branch, strings.Join(raw, ", ")))
+	}
+	if feature != "" {
+		reg, err := gov.LoadRegistry(filepath.Join(deps.RepoRoot, "spec", "features.yaml"))
+		if err != nil {
+			return fail(err)
+		}
+		known := false
+		for _, f := range reg.Features {
+			known = known || f.ID == feature
+		}
+		if !known {
+			findings = append(findings, fmt.Sprintf("branch feature %s is not defined in spec/features.yaml", feature))
+		}
+	}
+
+	// 2. Up to date with base
+	behind, err := git.CountCommits(ctx, deps.RepoRoot, "HEAD", baseRef)
+	if err != nil {
+		return fail(err)
+	}
+	if behind > cfg.Branches.MaxBehind {
+		findings = append(findings, fmt.Sprintf("branch is %d commit(s) behind %s (max %d); rebase onto %s", behind, baseRef, cfg.Branches.MaxBehind, baseRef))
+	}
+
+	// 3. Feature path scope
+	if feature != "" {
+		mergeBase, err := git.MergeBase(ctx, deps.RepoRoot, baseRef, "HEAD")
+		if err != nil {
+			return fail(err)
+		}
+		files, err := git.ChangedFiles(ctx, deps.RepoRoot, mergeBase, "HEAD")
+		if err != nil {
+			return fail(err)
+		}
+		for _, f := range files {
+			if owner := branchpolicy.Owner(deps.RepoRoot, f, cfg.Features.Annotate); owner != "" && owner != feature {
+				findings = append(findings, fmt.Sprintf("%s: outside %s scope (belongs to %s)", f, feature, owner))
+			}
+		}
+	}
+
+	if len(findings) > 0 {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitValidation,
+			Note:     strings.Join(findings, "\n"),
+		}
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   runner.StatusPass,
+		ExitCode: runner.ExitOK,
+		Note:     fmt.Sprintf("Branch %s follows the branch policy against %s.", branch, baseRef),
+	}
+}
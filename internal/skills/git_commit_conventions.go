@@ -0,0 +1,134 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/conventional"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// defaultBaseRefs are tried in order when commits.base is not configured.
+var defaultBaseRefs = []string{"origin/main", "main", "origin/master", "master"}
+
+type GitCommitConventions struct {
+	id string
+}
+
+func NewGitCommitConventions() runner.Skill {
+	return &GitCommitConventions{id: "git:commit-conventions"}
+}
+
+func (s *GitCommitConventions) ID() string { return s.id }
+
+func (s *GitCommitConventions) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	cfg, err := config.Load(deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+
+	baseRef, candidates := findBaseRef(ctx, deps.RepoRoot, cfg.Commits.Base)
+	if baseRef == "" {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   fmt.Sprintf("No base ref found (tried %s)", strings.Join(candidates, ", ")),
+		}
+	}
+
+	mergeBase, err := git.MergeBase(ctx, deps.RepoRoot, baseRef, "HEAD")
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+
+	commits, err := git.Commits(ctx, deps.RepoRoot, mergeBase, "HEAD")
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     err.Error(),
+		}
+	}
+	if len(commits) == 0 {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   fmt.Sprintf("No commits since merge-base with %s", baseRef),
+		}
+	}
+
+	policy := conventional.Policy{
+		Types:           cfg.Commits.Types,
+		Scopes:          cfg.Commits.Scopes,
+		RequireScope:    cfg.Commits.RequireScope,
+		MaxHeaderLength: cfg.Commits.MaxHeaderLength,
+	}
+
+	var findings []string
+	for _, c := range commits {
+		problems := conventional.Check(c.Message, policy)
+		if len(problems) == 0 {
+			continue
+		}
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		findings = append(findings, fmt.Sprintf("%s %s", shortHash(c.Hash), subject))
+		for _, p := range problems {
+			findings = append(findings, "  - "+p)
+		}
+	}
+
+	if len(findings) > 0 {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitValidation,
+			Note:     strings.Join(findings, "\n"),
+		}
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   runner.StatusPass,
+		ExitCode: runner.ExitOK,
+		Note:     fmt.Sprintf("%d commit(s) since %s follow Conventional Commits.", len(commits), baseRef),
+	}
+}
+
+// findBaseRef returns the configured base ref, or the first existing default,
+// along with the candidates that were tried. The ref is "" if none exists.
+func findBaseRef(ctx context.Context, repoRoot, configured string) (string, []string) {
+	candidates := defaultBaseRefs
+	if configured != "" {
+		candidates = []string{configured}
+	}
+	for _, ref := range candidates {
+		if git.RefExists(ctx, repoRoot, ref) {
+			return ref, candidates
+		}
+	}
+	return "", candidates
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
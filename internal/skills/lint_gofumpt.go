@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
 	"sort"
 	"strings"
 
@@ -37,7 +36,7 @@ func (s *LintGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.SkillRe
 			return runner.SkillResult{
 				Skill:    s.ID(),
 				Status:   runner.StatusFail,
-				ExitCode: 4,
+				ExitCode: runner.ExitExecution,
 				Note:     fmt.Sprintf("Failed to list files: %v", err),
 			}
 		}
@@ -47,18 +46,27 @@ func (s *LintGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.SkillRe
 		return runner.SkillResult{
 			Skill:    s.ID(),
 			Status:   runner.StatusPass,
-			ExitCode: 0,
+			ExitCode: runner.ExitOK,
 			Note:     "No Go files to check",
 		}
 	}
 
-	// 2. Check if gofumpt is installed
-	if _, err := exec.LookPath("gofumpt"); err != nil {
+	// 2. Check that gofumpt is installed and matches tools.lock
+	bin, err := deps.LookTool("gofumpt")
+	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.ID(),
 			Status:   runner.StatusFail,
-			ExitCode: 2,
-			Note:     "gofumpt not found. Run: go install mvdan.cc/gofumpt@v0.6.0",
+			ExitCode: runner.ExitConfig,
+			Note:     missingTool("gofumpt"),
+		}
+	}
+	if err := deps.VerifyTool("gofumpt", bin); err != nil {
+		return runner.SkillResult{
+			Skill:    s.ID(),
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitConfig,
+			Note:     err.Error(),
 		}
 	}
 
@@ -74,7 +82,7 @@ func (s *LintGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.SkillRe
 		}
 
 		batch := files[i:end]
-		cmd := exec.CommandContext(ctx, "gofumpt", append([]string{"-l"}, batch...)...)
+		cmd := deps.Command(ctx, bin, append([]string{"-l"}, batch...)...)
 		out, err := cmd.Output()
 		// Exit status 0 means success (found/not found doesn't change exit code for -l usually,
 		// but gofumpt -l prints names of unformatted files).
@@ -83,7 +91,7 @@ func (s *LintGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.SkillRe
 			return runner.SkillResult{
 				Skill:    s.ID(),
 				Status:   runner.StatusFail,
-				ExitCode: 4,
+				ExitCode: runner.ExitExecution,
 				Note:     fmt.Sprintf("gofumpt execution failed: %v", err),
 			}
 		}
@@ -110,7 +118,7 @@ func (s *LintGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.SkillRe
 		return runner.SkillResult{
 			Skill:    s.ID(),
 			Status:   runner.StatusFail,
-			ExitCode: 3,
+			ExitCode: runner.ExitValidation,
 			Note:     msg.String(),
 		}
 	}
@@ -118,7 +126,7 @@ func (s *LintGofumpt) Run(ctx context.Context, deps *runner.Deps) runner.SkillRe
 	return runner.SkillResult{
 		Skill:    s.ID(),
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 	}
 }
 
@@ -11,26 +11,48 @@ import (
 // Feature: SKILLS_REGISTRY
 // Spec: spec/skills/registry.md
 
-type LintGolangCI struct{}
+type LintGolangCI struct {
+	// dir is the module directory relative to the repo root; empty for
+	// the root.
+	dir string
+}
 
 func (s *LintGolangCI) ID() string {
-	return "lint:golangci"
+	return runner.ModuleSkillID("lint:golangci", s.dir)
+}
+
+func (s *LintGolangCI) forModule(dir string) runner.Skill {
+	return &LintGolangCI{dir: dir}
 }
 
 func (s *LintGolangCI) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
-	// 1. Check if golangci-lint is installed
-	if _, err := exec.LookPath("golangci-lint"); err != nil {
+	// 1. Check that golangci-lint is installed and matches tools.lock
+	bin, err := deps.LookTool("golangci-lint")
+	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.ID(),
 			Status:   runner.StatusFail,
-			ExitCode: 2,
-			Note:     "golangci-lint not found. Run: go install github.com/golangci/golangci-lint/v2/cmd/golangci-lint@v2.6.2",
+			ExitCode: runner.ExitConfig,
+			Note:     missingTool("golangci-lint"),
+		}
+	}
+	if err := deps.VerifyTool("golangci-lint", bin); err != nil {
+		return runner.SkillResult{
+			Skill:    s.ID(),
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitConfig,
+			Note:     err.Error(),
 		}
 	}
 
 	// 2. Run golangci-lint run ./...
-	cmd := exec.CommandContext(ctx, "golangci-lint", "run", "./...")
-	cmd.Dir = deps.RepoRoot
+	args := []string{"run", "./..."}
+	if s.dir != "" && s.dir != "." {
+		// Report paths relative to the repo root, not the module.
+		args = append(args, "--path-prefix", s.dir)
+	}
+	cmd := deps.Command(ctx, bin, args...)
+	cmd.Dir = moduleDir(deps, s.dir)
 	// Capture output to return in Note if failed, or just let it print to stdout?
 	// The runner handles printing "Note", but for a linter, the output IS the note.
 	// But golangci-lint output can be huge.
@@ -44,23 +66,11 @@ func (s *LintGolangCI) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		// Distinguish execution error (4) vs lint failure (3)?
-		// golangci-lint returns 1 by default for issues.
-		// User requested: "4 execution error", "3 lint failures (if you want mapping)".
-		// But golangci-lint exit codes:
-		// 1: issues found
-		// 3: generic error
-		// 4: timeout
-		// Let's stick to simple: if err, fail.
-		// If exit code is 1, maybe map to 3? Or just keep 1?
-		// Existing run.sh: just returns exit code.
-		// I will pass through the exit code if possible, or mapping.
-
-		var exitCode int
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		} else {
-			exitCode = 4 // unable to run
+		// golangci-lint exits 1 when it found issues; any other failure
+		// (config error, timeout, crash) means the lint did not complete.
+		exitCode := runner.ExitExecution
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			exitCode = runner.ExitValidation
 		}
 
 		// If it's a lint failure, we want to show the output.
@@ -77,6 +87,6 @@ func (s *LintGolangCI) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 	return runner.SkillResult{
 		Skill:    s.ID(),
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 	}
 }
@@ -0,0 +1,78 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/mcp"
+	"github.com/bartekus/cortex/internal/mcpschema"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// MCPSchema runs the checks of `cortex gov mcp-schema` against the built
+// cortex-mcp binary.
+type MCPSchema struct {
+	id string
+}
+
+func NewMCPSchema() runner.Skill {
+	return &MCPSchema{id: "mcp:schema"}
+}
+
+func (s *MCPSchema) ID() string { return s.id }
+
+func (s *MCPSchema) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	if _, err := os.Stat(filepath.Join(deps.RepoRoot, mcpschema.DefaultFixture)); err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusSkip, Note: "No MCP tool fixture at " + mcpschema.DefaultFixture}
+	}
+	bin, err := mcp.ResolveBin("", deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusSkip, Note: err.Error()}
+	}
+
+	tools, err := mcpschema.ListTools(ctx, bin, io.Discard)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return cancelled(s.id, ctxErr)
+		}
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Failed to list MCP tools: %v", err),
+		}
+	}
+
+	report, err := mcpschema.Check(tools, deps.RepoRoot, mcpschema.DefaultFixture)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitExecution, Note: err.Error()}
+	}
+	if !report.Failed() {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusPass,
+			Note:   fmt.Sprintf("%d MCP tools match %s", report.Tools, mcpschema.DefaultFixture),
+		}
+	}
+
+	var notes []string
+	for _, p := range report.Problems {
+		notes = append(notes, fmt.Sprintf("%s: %s", p.Tool, p.Message))
+	}
+	if report.Diff != nil {
+		notes = append(notes, "Tool schemas drifted from the fixture (accept with `cortex gov mcp-schema --update`):\n"+report.Diff.String())
+	}
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   runner.StatusFail,
+		ExitCode: runner.ExitValidation,
+		Note:     strings.Join(notes, "\n"),
+	}
+}
@@ -0,0 +1,43 @@
+package skills
+
+import (
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// moduleSkill is implemented by skills that run once per Go module.
+type moduleSkill interface {
+	forModule(dir string) runner.Skill
+}
+
+// ForModules expands the per-module skills of registry (test:build, test:go
+// and lint:golangci) into one skill per module, keeping registry order. The
+// root module keeps the plain ID and the others are suffixed with their
+// directory, e.g. "test:go@services/api". A repository with at most a root
+// module gets registry unchanged.
+func ForModules(registry []runner.Skill, mods []scanner.Module) []runner.Skill {
+	if len(mods) == 0 || len(mods) == 1 && mods[0].Dir == "." {
+		return registry
+	}
+
+	out := make([]runner.Skill, 0, len(registry))
+	for _, s := range registry {
+		ms, ok := s.(moduleSkill)
+		if !ok {
+			out = append(out, s)
+			continue
+		}
+		for _, m := range mods {
+			out = append(out, ms.forModule(m.Dir))
+		}
+	}
+	return out
+}
+
+// moduleDir returns the absolute directory of the module at dir.
+func moduleDir(deps *runner.Deps, dir string) string {
+	return deps.Path(dir)
+}
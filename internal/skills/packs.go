@@ -0,0 +1,156 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+// PackSkill runs a non-Go toolchain in every project directory that has its
+// manifest. It skips when the repository has no such project or the tool is
+// not installed, so polyglot checks never fail a Go-only setup.
+type PackSkill struct {
+	id       string
+	manifest string
+	tool     string
+	args     []string
+	// local is a project-relative path to the tool checked before PATH,
+	// e.g. node_modules/.bin/eslint.
+	local string
+	// noTests is the tool's exit status for "nothing to check"; 0 if none.
+	noTests int
+	install string
+}
+
+func NewLintESLint() runner.Skill {
+	return &PackSkill{
+		id:       "lint:eslint",
+		manifest: "package.json",
+		tool:     "eslint",
+		args:     []string{".", "--format", "unix"},
+		local:    "node_modules/.bin/eslint",
+		install:  "npm install --save-dev eslint",
+	}
+}
+
+func NewTestPytest() runner.Skill {
+	return &PackSkill{
+		id:       "test:pytest",
+		manifest: "pyproject.toml",
+		tool:     "pytest",
+		args:     []string{"-q"},
+		noTests:  5,
+		install:  "pip install pytest",
+	}
+}
+
+func NewTestCargo() runner.Skill {
+	return &PackSkill{
+		id:       "test:cargo",
+		manifest: "Cargo.toml",
+		tool:     "cargo",
+		args:     []string{"test", "--quiet"},
+		install:  "https://rustup.rs",
+	}
+}
+
+func (s *PackSkill) ID() string { return s.id }
+
+func (s *PackSkill) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	dirs, err := deps.Scanner.ProjectDirs(ctx, s.manifest)
+	if err != nil {
+		return runner.SkillResult{
+			Skill:    s.id,
+			Status:   runner.StatusFail,
+			ExitCode: runner.ExitExecution,
+			Note:     fmt.Sprintf("Failed to list files: %v", err),
+		}
+	}
+	if len(dirs) == 0 {
+		return runner.SkillResult{
+			Skill:  s.id,
+			Status: runner.StatusSkip,
+			Note:   "No " + s.manifest + " found",
+		}
+	}
+
+	status := runner.StatusSkip
+	exitCode := runner.ExitOK
+	var notes []string
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return cancelled(s.id, err)
+		}
+
+		abs := moduleDir(deps, dir)
+		tool := s.lookTool(deps.RepoRoot, abs)
+		if tool == "" {
+			notes = append(notes, fmt.Sprintf("%s: %s not found. Install: %s", dir, s.tool, s.install))
+			continue
+		}
+
+		cmd := deps.Command(ctx, tool, s.args...)
+		cmd.Dir = abs
+		out, err := cmd.CombinedOutput()
+
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			if status == runner.StatusSkip {
+				status = runner.StatusPass
+			}
+		case s.noTests != 0 && errors.As(err, &exitErr) && exitErr.ExitCode() == s.noTests:
+			notes = append(notes, dir+": no tests collected")
+			if status == runner.StatusSkip {
+				status = runner.StatusPass
+			}
+		default:
+			status = runner.StatusFail
+			exitCode = max(exitCode, commandExitCode(err))
+			notes = append(notes, fmt.Sprintf("%s: %s failed", dir, s.tool), lastLines(string(out), 20))
+		}
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   status,
+		ExitCode: exitCode,
+		Note:     strings.Join(notes, "\n"),
+	}
+}
+
+// lookTool returns the tool to run for the project at dir: its local copy
+// (in the project, then the repo root), else the one on PATH, else "".
+// The local copy is looked up like a command, so on Windows the .cmd shim
+// npm installs next to the shell script is found instead.
+func (s *PackSkill) lookTool(repoRoot, dir string) string {
+	if s.local != "" {
+		for _, base := range []string{dir, repoRoot} {
+			if p, err := exec.LookPath(scanner.OSPath(base, s.local)); err == nil {
+				return p
+			}
+		}
+	}
+	if p, err := exec.LookPath(s.tool); err == nil {
+		return p
+	}
+	return ""
+}
+
+// lastLines trims output to its last n lines.
+func lastLines(output string, n int) string {
+	output = strings.TrimSpace(output)
+	lines := strings.Split(output, "\n")
+	if len(lines) <= n {
+		return output
+	}
+	return "...(truncated)...\n" + strings.Join(lines[len(lines)-n:], "\n")
+}
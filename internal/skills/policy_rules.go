@@ -0,0 +1,75 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/policy"
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+// Feature: SKILLS_REGISTRY
+// Spec: spec/skills/registry.md
+
+type PolicyRules struct {
+	id string
+}
+
+func NewPolicyRules() runner.Skill {
+	return &PolicyRules{id: "policy:rules"}
+}
+
+func (s *PolicyRules) ID() string { return s.id }
+
+func (s *PolicyRules) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
+	p, err := policy.Load(deps.RepoRoot)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitConfig, Note: err.Error()}
+	}
+	if p == nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusSkip, Note: "No policy files in " + policy.Dir}
+	}
+	if err := ctx.Err(); err != nil {
+		return cancelled(s.id, err)
+	}
+
+	src, err := policy.Collect(ctx, deps.RepoRoot, deps.StateDir)
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitExecution, Note: err.Error()}
+	}
+	violations, err := p.Evaluate(policy.Data(src))
+	if err != nil {
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitConfig, Note: err.Error()}
+	}
+
+	var errs, warnings []string
+	for _, v := range violations {
+		if v.Warning() {
+			warnings = append(warnings, v.String())
+		} else {
+			errs = append(errs, v.String())
+		}
+	}
+
+	status, exitCode := runner.StatusPass, runner.ExitOK
+	notes := errs
+	if len(errs) > 0 {
+		status, exitCode = runner.StatusFail, runner.ExitValidation
+	} else {
+		notes = append(notes, fmt.Sprintf("%d rules in %d policy files hold.", p.Rules(), len(p.Files)))
+	}
+	notes = append(notes, warnings...)
+	if len(warnings) > 0 && deps.FailOnWarning && status == runner.StatusPass {
+		status = runner.StatusFail
+		exitCode = runner.ExitWarning
+		notes = append(notes, "(Fail on warning)")
+	}
+
+	return runner.SkillResult{
+		Skill:    s.id,
+		Status:   status,
+		ExitCode: exitCode,
+		Note:     strings.Join(notes, "\n"),
+	}
+}
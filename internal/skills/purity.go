@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -35,15 +34,33 @@ var bannedImports = map[string][]string{
 		"internal/scanner/",   // Allowed in scanner (git ls-files)
 		"internal/runner/",    // Allowed if runner needs to exec (maybe?)
 		"internal/git/",       // Allowed for git operations
+		"internal/mcp/",       // Allowed: starts the cortex-mcp server
 		"pkg/executil/",       // Allowed: core exec utility
 		"test/e2e/",           // Allowed: e2e tests
 		"internal/dev/",       // Allowed: dev tooling
 		"internal/providers/", // Allowed: local providers
 		// "tools/", // If we had tools
 	},
+	// Model output only reaches features through commands that opt in.
+	"github.com/bartekus/cortex/pkg/llm": {
+		"cmd/",
+		"pkg/llm/",
+	},
 	// Add others if needed: "syscall", "unsafe"
 }
 
+// BannedImports returns a copy of the banned imports and, for each, the
+// sorted directory prefixes where it is allowed.
+func BannedImports() map[string][]string {
+	out := make(map[string][]string, len(bannedImports))
+	for pkg, dirs := range bannedImports {
+		allowed := append([]string(nil), dirs...)
+		sort.Strings(allowed)
+		out[pkg] = allowed
+	}
+	return out
+}
+
 func (s *Purity) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
 	// 1. Scan tracked Go files
 	goOpts := scanner.FilterOptions{
@@ -56,10 +73,11 @@ func (s *Purity) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Scanner failed: %v", err),
 		}
 	}
+	files = deps.Targets(files)
 
 	if len(files) == 0 {
 		return runner.SkillResult{
@@ -69,18 +87,15 @@ func (s *Purity) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult
 		}
 	}
 
-	var violations []string
-
-	for _, p := range files {
-		// Clean path
-		p = filepath.ToSlash(p) // normalized
+	perFile, err := scanner.ProcessFiles(ctx, files, 0, func(p string) ([]string, error) {
+		p = scanner.RepoPath(p)
 
-		imports, err := scanImports(filepath.Join(deps.RepoRoot, p))
+		imports, err := scanImports(deps.Path(p))
 		if err != nil {
-			violations = append(violations, fmt.Sprintf("%s: failed to scan imports: %v", p, err))
-			continue
+			return []string{fmt.Sprintf("%s: failed to scan imports: %v", p, err)}, nil
 		}
 
+		var out []string
 		for imp := range imports {
 			if allowedDirs, banned := bannedImports[imp]; banned {
 				// Check if P is in allowedDirs
@@ -92,10 +107,19 @@ func (s *Purity) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult
 					}
 				}
 				if !allowed {
-					violations = append(violations, fmt.Sprintf("%s: banned import %q", p, imp))
+					out = append(out, fmt.Sprintf("%s: banned import %q", p, imp))
 				}
 			}
 		}
+		return out, nil
+	})
+	if err != nil {
+		return cancelled(s.id, err)
+	}
+
+	var violations []string
+	for _, v := range perFile {
+		violations = append(violations, v...)
 	}
 
 	if len(violations) > 0 {
@@ -103,7 +127,7 @@ func (s *Purity) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 1,
+			ExitCode: runner.ExitValidation,
 			Note:     strings.Join(violations, "\n"),
 		}
 	}
@@ -111,7 +135,7 @@ func (s *Purity) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "No banned imports found.",
 	}
 }
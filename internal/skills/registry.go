@@ -45,16 +45,27 @@ var Registry = []runner.Skill{
 	NewTestBinary(),
 	NewTestGo(),
 	NewTestCoverage(),
+	NewLintESLint(),
+	NewTestPytest(),
+	NewTestCargo(),
 	NewDocsYaml(),
 	NewDocsValidateSpec(),
 	newPlaceholder("docs:spec-reference-check"),
 	NewDocsOrphanSpecs(),
 	NewDocsOrphanDocs(),
 	NewDocsDocPatterns(),
+	NewDocsGeneratedMarkers(),
 
 	newPlaceholder("docs:required-tests"),
 	NewDocsHeaderComments(),
 	NewPurity(),
+	NewGitBranchPolicy(),
+	NewGitCommitConventions(),
 	NewDocsPolicy(),
 	NewDocsProviderGovernance(),
+	NewComposeEnvConsistency(),
+	NewDepsPolicy(),
+	NewMCPSchema(),
+	NewPolicyRules(),
+	NewEnvWindowsPaths(),
 }
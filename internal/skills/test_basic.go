@@ -2,11 +2,15 @@ package skills
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
+	"path"
+	"runtime"
 	"strings"
 
 	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
 )
 
 // Feature: SKILLS_REGISTRY
@@ -16,27 +20,31 @@ import (
 type ExecSkill struct {
 	id   string
 	args []string
+	// dir is the module directory relative to the repo root; empty for
+	// the root.
+	dir string
 	// Env etc
 }
 
 func (s *ExecSkill) ID() string { return s.id }
 
+func (s *ExecSkill) forModule(dir string) runner.Skill {
+	return &ExecSkill{id: runner.ModuleSkillID(s.id, dir), args: s.args, dir: dir}
+}
+
 func (s *ExecSkill) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
-	cmd := exec.CommandContext(ctx, s.args[0], s.args[1:]...)
-	cmd.Dir = deps.RepoRoot
+	cmd := deps.Command(ctx, s.args[0], s.args[1:]...)
+	cmd.Dir = moduleDir(deps, s.dir)
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		exitCode := 1
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
+		exitCode := commandExitCode(err)
 
 		// Capture last N lines of output for note?
 		// Or all of it?
 		// User said: "failures become StatusFail with a useful note (include last N lines of stderr)"
 
-		output := string(out)
+		output := s.repoRelative(string(out))
 		lines := strings.Split(output, "\n")
 		// Keep last 20 lines
 		if len(lines) > 20 {
@@ -55,8 +63,28 @@ func (s *ExecSkill) Run(ctx context.Context, deps *runner.Deps) runner.SkillResu
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
+	}
+}
+
+// repoRelative rewrites the "./file.go" (".\\file.go" on Windows) paths
+// the go command prints for a nested module to be relative to the repo root.
+func (s *ExecSkill) repoRelative(output string) string {
+	if s.dir == "" || s.dir == "." {
+		return output
 	}
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		file, rest, found := strings.Cut(line, ":")
+		if !strings.HasPrefix(file, "./") && !strings.HasPrefix(file, ".\\") {
+			continue
+		}
+		lines[i] = path.Join(s.dir, scanner.RepoPath(file))
+		if found {
+			lines[i] += ":" + rest
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 func NewTestBuild() runner.Skill {
@@ -94,14 +122,20 @@ func (s *SmartBinarySkill) Run(ctx context.Context, deps *runner.Deps) runner.Sk
 	// We can use os.Stat
 
 	hasCortex := false
-	if info, err := os.Stat(deps.RepoRoot + "/" + cortexPath); err == nil && info.IsDir() {
+	if info, err := os.Stat(deps.Path(cortexPath)); err == nil && info.IsDir() {
 		hasCortex = true
 	}
 
 	var args []string
 
 	if hasCortex {
-		args = []string{"go", "build", "-o", "bin/cortex", "./cmd/cortex"}
+		// go build -o takes the name as given; Windows needs the suffix to
+		// run the binary.
+		out := "bin/cortex"
+		if runtime.GOOS == "windows" {
+			out += ".exe"
+		}
+		args = []string{"go", "build", "-o", out, "./cmd/cortex"}
 	} else {
 		return runner.SkillResult{
 			Skill:  s.id,
@@ -110,15 +144,12 @@ func (s *SmartBinarySkill) Run(ctx context.Context, deps *runner.Deps) runner.Sk
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd := deps.Command(ctx, args[0], args[1:]...)
 	cmd.Dir = deps.RepoRoot
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		exitCode := 1
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
+		exitCode := commandExitCode(err)
 
 		output := string(out)
 		lines := strings.Split(output, "\n")
@@ -139,7 +170,7 @@ func (s *SmartBinarySkill) Run(ctx context.Context, deps *runner.Deps) runner.Sk
 	return runner.SkillResult{
 		Skill:    s.id,
 		Status:   runner.StatusPass,
-		ExitCode: 0,
+		ExitCode: runner.ExitOK,
 		Note:     "Built " + args[3],
 	}
 }
@@ -150,3 +181,13 @@ func NewTestGo() runner.Skill {
 		args: []string{"go", "test", "./..."},
 	}
 }
+
+// commandExitCode maps the error of a finished command: a non-zero exit means
+// the tool ran and reported failures, anything else means it could not run.
+func commandExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return runner.ExitValidation
+	}
+	return runner.ExitExecution
+}
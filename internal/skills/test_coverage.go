@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -29,19 +28,16 @@ func (s *TestCoverage) ID() string { return s.id }
 func (s *TestCoverage) Run(ctx context.Context, deps *runner.Deps) runner.SkillResult {
 	// 1. Prepare coverage file path
 	if deps.StateDir == "" {
-		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: 4, Note: "StateDir not set"}
+		return runner.SkillResult{Skill: s.id, Status: runner.StatusFail, ExitCode: runner.ExitExecution, Note: "StateDir not set"}
 	}
 	coverProfile := filepath.Join(deps.StateDir, "coverage.out")
 
 	// 2. Run go test
-	cmd := exec.CommandContext(ctx, "go", "test", "./...", "-coverprofile="+coverProfile, "-covermode=atomic")
+	cmd := deps.Command(ctx, "go", "test", "./...", "-coverprofile="+coverProfile, "-covermode=atomic")
 	cmd.Dir = deps.RepoRoot
 
 	if out, err := cmd.CombinedOutput(); err != nil {
-		exitCode := 1
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
+		exitCode := commandExitCode(err)
 		// Capture output for diagnosis
 		return runner.SkillResult{
 			Skill:    s.id,
@@ -53,12 +49,12 @@ func (s *TestCoverage) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 
 	// 3. Parse Coverage
 	//   a) Overall via go tool cover -func
-	totalCov, err := getOverallCoverage(ctx, deps.RepoRoot, coverProfile)
+	totalCov, err := getOverallCoverage(ctx, deps, coverProfile)
 	if err != nil {
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Failed to parse coverage: %v", err),
 		}
 	}
@@ -70,7 +66,7 @@ func (s *TestCoverage) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 		return runner.SkillResult{
 			Skill:    s.id,
 			Status:   runner.StatusFail,
-			ExitCode: 4,
+			ExitCode: runner.ExitExecution,
 			Note:     fmt.Sprintf("Failed to parse core coverage: %v", err),
 		}
 	}
@@ -80,21 +76,21 @@ func (s *TestCoverage) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 	// Core: < 80 FAIL
 
 	status := runner.StatusPass
-	exitCode := 0
+	exitCode := runner.ExitOK
 	var notes []string
 
 	notes = append(notes, fmt.Sprintf("Overall: %.1f%%", totalCov))
 
 	if totalCov < 50.0 {
 		status = runner.StatusFail
-		exitCode = 3
+		exitCode = runner.ExitValidation
 		notes = append(notes, "FAIL: Overall coverage < 50%")
 	} else if totalCov < 60.0 {
 		msg := "WARNING: Overall coverage < 60%"
 		notes = append(notes, msg)
 		if deps.FailOnWarning {
 			status = runner.StatusFail
-			exitCode = 3
+			exitCode = runner.ExitWarning
 			notes = append(notes, "(Fail on warning)")
 		}
 	}
@@ -110,7 +106,7 @@ func (s *TestCoverage) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 		statusStr := "OK"
 		if cov < 80.0 {
 			status = runner.StatusFail
-			exitCode = 3
+			exitCode = runner.ExitValidation
 			statusStr = "FAIL (< 80%)"
 		}
 		notes = append(notes, fmt.Sprintf("  %s: %.1f%% %s", pkg, cov, statusStr))
@@ -126,9 +122,9 @@ func (s *TestCoverage) Run(ctx context.Context, deps *runner.Deps) runner.SkillR
 	}
 }
 
-func getOverallCoverage(ctx context.Context, dir, profile string) (float64, error) {
-	cmd := exec.CommandContext(ctx, "go", "tool", "cover", "-func="+profile)
-	cmd.Dir = dir
+func getOverallCoverage(ctx context.Context, deps *runner.Deps, profile string) (float64, error) {
+	cmd := deps.Command(ctx, "go", "tool", "cover", "-func="+profile)
+	cmd.Dir = deps.RepoRoot
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return 0, err
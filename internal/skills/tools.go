@@ -0,0 +1,72 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+)
+
+// Feature: CLI_COMMAND_TOOLS
+// Spec: spec/cli/tools.md
+
+// GoTool is a Go program skills run, installed with `go install`.
+type GoTool struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	// Version is the pinned version, e.g. "v0.6.0".
+	Version string `json:"version"`
+}
+
+// GoTools are the Go programs skills run, at their default pins.
+var GoTools = []GoTool{
+	{Name: "gofumpt", Package: "mvdan.cc/gofumpt", Version: "v0.6.0"},
+	{Name: "golangci-lint", Package: "github.com/golangci/golangci-lint/v2/cmd/golangci-lint", Version: "v2.6.2"},
+}
+
+// PinnedGoTools returns GoTools with the versions of tools.versions in
+// .cortex/config.yaml applied; a leading "v" is added when missing.
+func PinnedGoTools(versions map[string]string) []GoTool {
+	out := append([]GoTool(nil), GoTools...)
+	for i, t := range out {
+		if v := versions[t.Name]; v != "" {
+			out[i].Version = "v" + strings.TrimPrefix(v, "v")
+		}
+	}
+	return out
+}
+
+// Install runs `go install Package@Version` with GOBIN set to the
+// ToolBinDir of repoRoot; go's output goes to w.
+func (t GoTool) Install(ctx context.Context, repoRoot string, w io.Writer) error {
+	bin, err := filepath.Abs(scanner.OSPath(repoRoot, runner.ToolBinDir))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", runner.ToolBinDir, err)
+	}
+	cmd := exec.CommandContext(ctx, "go", "install", t.Package+"@"+t.Version) //nolint:gosec // G204: package from the tool catalog
+	cmd.Env = append(os.Environ(), "GOBIN="+bin)
+	cmd.Stdout, cmd.Stderr = w, w
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go install %s@%s: %w", t.Package, t.Version, err)
+	}
+	return nil
+}
+
+// missingTool is the note of a skill whose Go tool is not installed.
+func missingTool(name string) string {
+	for _, t := range GoTools {
+		if t.Name == name {
+			return fmt.Sprintf("%s not found. Run: cortex tools install (or go install %s@%s)", name, t.Package, t.Version)
+		}
+	}
+	return name + " not found"
+}
@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package snapshot compares snapshots of the cortex-mcp workspace store,
+// producing a change manifest and a unified diff of what changed between
+// them.
+package snapshot
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Change types, in the manifest's "type" field.
+const (
+	Added    = "added"
+	Modified = "modified"
+	Deleted  = "deleted"
+)
+
+// Entry is a file in a snapshot. Blob is the content hash of the file as
+// recorded by the store. Omitted is set ("too_large") when the store holds
+// only the metadata of the file, not its content.
+type Entry struct {
+	Path    string `json:"path"`
+	Blob    string `json:"blob"`
+	Size    int64  `json:"size"`
+	Omitted string `json:"omitted,omitempty"`
+}
+
+// Change is a file that differs between two snapshots. OldBlob is empty for
+// added files and NewBlob for deleted ones.
+type Change struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	OldBlob string `json:"old_blob,omitempty"`
+	NewBlob string `json:"new_blob,omitempty"`
+}
+
+// Store reads snapshots.
+type Store interface {
+	// Entries lists every file of snapshot id.
+	Entries(ctx context.Context, id string) ([]Entry, error)
+	// Blob returns the content of path in snapshot id.
+	Blob(ctx context.Context, id, path string) ([]byte, error)
+}
+
+// Result is the comparison of two snapshots.
+type Result struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Changes []Change `json:"changes"`
+	// Patch is the git-style unified diff of all changes, in path order.
+	// It applies with git apply.
+	Patch string `json:"patch"`
+}
+
+// Changes compares two file lists by blob hash. The result is sorted by
+// path.
+func Changes(from, to []Entry) []Change {
+	old := make(map[string]string, len(from))
+	for _, e := range from {
+		old[e.Path] = e.Blob
+	}
+
+	changes := []Change{}
+	seen := make(map[string]bool, len(to))
+	for _, e := range to {
+		seen[e.Path] = true
+		blob, ok := old[e.Path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: e.Path, Type: Added, NewBlob: e.Blob})
+		case blob != e.Blob:
+			changes = append(changes, Change{Path: e.Path, Type: Modified, OldBlob: blob, NewBlob: e.Blob})
+		}
+	}
+	for _, e := range from {
+		if !seen[e.Path] {
+			changes = append(changes, Change{Path: e.Path, Type: Deleted, OldBlob: e.Blob})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Diff compares snapshot from to snapshot to. Only the contents of changed
+// files are read from the store; files captured as metadata only are
+// reported as differing without their content.
+func Diff(ctx context.Context, store Store, from, to string) (*Result, error) {
+	oldEntries, err := store.Entries(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	newEntries, err := store.Entries(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{From: from, To: to, Changes: Changes(oldEntries, newEntries)}
+	oldOmitted, newOmitted := omitted(oldEntries), omitted(newEntries)
+	var patch strings.Builder
+	for _, c := range res.Changes {
+		if oldOmitted[c.Path] || newOmitted[c.Path] {
+			writeHeader(&patch, c)
+			fmt.Fprintf(&patch, "Large files a/%s and b/%s differ\n", c.Path, c.Path)
+			continue
+		}
+		var oldData, newData []byte
+		if c.Type != Added {
+			if oldData, err = store.Blob(ctx, from, c.Path); err != nil {
+				return nil, err
+			}
+		}
+		if c.Type != Deleted {
+			if newData, err = store.Blob(ctx, to, c.Path); err != nil {
+				return nil, err
+			}
+		}
+		writePatch(&patch, c, oldData, newData)
+	}
+	res.Patch = patch.String()
+	return res, nil
+}
+
+// omitted returns the paths of entries captured as metadata only.
+func omitted(entries []Entry) map[string]bool {
+	paths := make(map[string]bool)
+	for _, e := range entries {
+		if e.Omitted != "" {
+			paths[e.Path] = true
+		}
+	}
+	return paths
+}
+
+// writePatch renders one change in the format of git diff, so the patch
+// can be applied with git apply.
+func writePatch(b *strings.Builder, c Change, oldData, newData []byte) {
+	oldName, newName := writeHeader(b, c)
+	if scanner.IsBinary(oldData) || scanner.IsBinary(newData) {
+		fmt.Fprintf(b, "Binary files %s and %s differ\n", oldName, newName)
+		return
+	}
+	d := gov.UnifiedDiff(oldName, newName, markEOF(oldData), markEOF(newData), gov.DiffOptions{Context: gov.DefaultDiffContext})
+	b.WriteString(strings.ReplaceAll(d.String(), noEOL+"\n", "\n\\ No newline at end of file\n"))
+}
+
+// writeHeader writes the git diff header lines of a change and returns the
+// names of its two sides.
+func writeHeader(b *strings.Builder, c Change) (oldName, newName string) {
+	fmt.Fprintf(b, "diff --git a/%s b/%s\n", c.Path, c.Path)
+	oldName, newName = "a/"+c.Path, "b/"+c.Path
+	switch c.Type {
+	case Added:
+		b.WriteString("new file mode 100644\n")
+		oldName = "/dev/null"
+	case Deleted:
+		b.WriteString("deleted file mode 100644\n")
+		newName = "/dev/null"
+	}
+	fmt.Fprintf(b, "index %s..%s\n", abbrev(c.OldBlob), abbrev(c.NewBlob))
+	return oldName, newName
+}
+
+// noEOL tags a last line without a newline. Text files hold no NUL, so the
+// tag cannot clash with content, and a tagged line never equals its
+// newline-terminated counterpart.
+const noEOL = "\x00"
+
+func markEOF(data []byte) string {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		return string(data) + noEOL
+	}
+	return string(data)
+}
+
+// abbrev shortens a blob hash for the index line; absent blobs are zeros.
+func abbrev(blob string) string {
+	blob = strings.TrimPrefix(blob, "sha256:")
+	if blob == "" {
+		return "000000000000"
+	}
+	if len(blob) > 12 {
+		return blob[:12]
+	}
+	return blob
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package snapshot
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore holds snapshots as path -> content maps.
+type memStore map[string]map[string]string
+
+func (m memStore) Entries(_ context.Context, id string) ([]Entry, error) {
+	files, ok := m[id]
+	if !ok {
+		return nil, fmt.Errorf("snapshot %s not found", id)
+	}
+	var entries []Entry
+	for path, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		entries = append(entries, Entry{Path: path, Blob: hex.EncodeToString(sum[:]), Size: int64(len(content))})
+	}
+	return entries, nil
+}
+
+func (m memStore) Blob(_ context.Context, id, path string) ([]byte, error) {
+	return []byte(m[id][path]), nil
+}
+
+func blob(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestChanges(t *testing.T) {
+	from := []Entry{{Path: "b", Blob: "1"}, {Path: "a", Blob: "2"}, {Path: "c", Blob: "3"}}
+	to := []Entry{{Path: "d", Blob: "4"}, {Path: "a", Blob: "2"}, {Path: "b", Blob: "5"}}
+
+	assert.Equal(t, []Change{
+		{Path: "b", Type: Modified, OldBlob: "1", NewBlob: "5"},
+		{Path: "c", Type: Deleted, OldBlob: "3"},
+		{Path: "d", Type: Added, NewBlob: "4"},
+	}, Changes(from, to))
+	assert.Empty(t, Changes(from, from))
+}
+
+func TestDiff(t *testing.T) {
+	store := memStore{
+		"s1": {
+			"README.md":    "# cortex\n",
+			"src/main.go":  "package main\n\nfunc main() {}\n",
+			"old.txt":      "gone\n",
+			"logo.png":     "\x89PNG\x00\x01",
+			"same/keep.go": "package same\n",
+		},
+		"s2": {
+			"README.md":    "# cortex\n",
+			"src/main.go":  "package main\n\nfunc main() {\n\trun()\n}\n",
+			"new.txt":      "hello",
+			"logo.png":     "\x89PNG\x00\x02",
+			"same/keep.go": "package same\n",
+		},
+	}
+
+	res, err := Diff(context.Background(), store, "s1", "s2")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Path: "logo.png", Type: Modified, OldBlob: blob("\x89PNG\x00\x01"), NewBlob: blob("\x89PNG\x00\x02")},
+		{Path: "new.txt", Type: Added, NewBlob: blob("hello")},
+		{Path: "old.txt", Type: Deleted, OldBlob: blob("gone\n")},
+		{Path: "src/main.go", Type: Modified, OldBlob: blob("package main\n\nfunc main() {}\n"), NewBlob: blob("package main\n\nfunc main() {\n\trun()\n}\n")},
+	}, res.Changes)
+
+	want := "diff --git a/logo.png b/logo.png\n" +
+		"index " + blob("\x89PNG\x00\x01")[:12] + ".." + blob("\x89PNG\x00\x02")[:12] + "\n" +
+		"Binary files a/logo.png and b/logo.png differ\n" +
+		"diff --git a/new.txt b/new.txt\n" +
+		"new file mode 100644\n" +
+		"index 000000000000.." + blob("hello")[:12] + "\n" +
+		"--- /dev/null\n" +
+		"+++ b/new.txt\n" +
+		"@@ -0,0 +1 @@\n" +
+		"+hello\n" +
+		"\\ No newline at end of file\n" +
+		"diff --git a/old.txt b/old.txt\n" +
+		"deleted file mode 100644\n" +
+		"index " + blob("gone\n")[:12] + "..000000000000\n" +
+		"--- a/old.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-gone\n" +
+		"diff --git a/src/main.go b/src/main.go\n" +
+		"index " + blob("package main\n\nfunc main() {}\n")[:12] + ".." + blob("package main\n\nfunc main() {\n\trun()\n}\n")[:12] + "\n" +
+		"--- a/src/main.go\n" +
+		"+++ b/src/main.go\n" +
+		"@@ -1,3 +1,5 @@\n" +
+		" package main\n" +
+		" \n" +
+		"-func main() {}\n" +
+		"+func main() {\n" +
+		"+\trun()\n" +
+		"+}\n"
+	assert.Equal(t, want, res.Patch)
+
+	again, err := Diff(context.Background(), store, "s1", "s2")
+	require.NoError(t, err)
+	assert.Equal(t, res, again, "diff must be deterministic")
+}
+
+func TestDiff_MissingNewline(t *testing.T) {
+	store := memStore{"s1": {"f": "a\nb"}, "s2": {"f": "a\nb\n"}}
+
+	res, err := Diff(context.Background(), store, "s1", "s2")
+	require.NoError(t, err)
+	assert.Contains(t, res.Patch, "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n")
+}
+
+func TestDiff_UnknownSnapshot(t *testing.T) {
+	_, err := Diff(context.Background(), memStore{}, "nope", "s2")
+	assert.EqualError(t, err, "snapshot nope not found")
+}
+
+// omittingStore captures files over limit bytes as metadata only, as
+// cortex-mcp does for files over its size limit.
+type omittingStore struct {
+	memStore
+	limit int64
+}
+
+func (s omittingStore) Entries(ctx context.Context, id string) ([]Entry, error) {
+	entries, err := s.memStore.Entries(ctx, id)
+	for i := range entries {
+		if entries[i].Size > s.limit {
+			entries[i].Omitted = "too_large"
+		}
+	}
+	return entries, err
+}
+
+func (s omittingStore) Blob(ctx context.Context, id, path string) ([]byte, error) {
+	if int64(len(s.memStore[id][path])) > s.limit {
+		return nil, fmt.Errorf("%s: content not captured", path)
+	}
+	return s.memStore.Blob(ctx, id, path)
+}
+
+func TestDiff_OmittedContent(t *testing.T) {
+	store := omittingStore{memStore: memStore{
+		"s1": {"big.bin": "0123456789", "a.txt": "a\n"},
+		"s2": {"big.bin": "9876543210", "a.txt": "b\n"},
+	}, limit: 4}
+
+	res, err := Diff(context.Background(), store, "s1", "s2")
+	require.NoError(t, err)
+	assert.Len(t, res.Changes, 2)
+	assert.Contains(t, res.Patch, "index "+blob("0123456789")[:12]+".."+blob("9876543210")[:12]+"\nLarge files a/big.bin and b/big.bin differ\n")
+	assert.Contains(t, res.Patch, "-a\n+b\n")
+}
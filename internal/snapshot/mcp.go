@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package snapshot
+
+// Feature: CLI_COMMAND_SNAPSHOT
+// Spec: spec/cli/snapshot.md
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/mcp"
+)
+
+// MCPStore reads snapshots through the snapshot tools of cortex-mcp, which
+// keeps manifests in its SQLite store.
+type MCPStore struct {
+	Client   *mcp.Client
+	RepoRoot string
+}
+
+// ToolArgs maps each cortex-mcp tool this package calls to its argument
+// struct. `cortex gov mcp-schema` checks them against the tools' input
+// schemas.
+var ToolArgs = map[string]any{
+	"snapshot.file":    FileArgs{},
+	"snapshot.history": HistoryArgs{},
+	"snapshot.list":    ListArgs{},
+	"snapshot.verify":  VerifyArgs{},
+}
+
+// ListArgs are the arguments of snapshot.list in snapshot mode.
+type ListArgs struct {
+	RepoRoot   string `json:"repo_root"`
+	Path       string `json:"path"`
+	Mode       string `json:"mode"`
+	SnapshotID string `json:"snapshot_id"`
+	Cursor     string `json:"cursor,omitempty"`
+}
+
+// FileArgs are the arguments of snapshot.file in snapshot mode.
+type FileArgs struct {
+	RepoRoot   string `json:"repo_root"`
+	Path       string `json:"path"`
+	Mode       string `json:"mode"`
+	SnapshotID string `json:"snapshot_id"`
+}
+
+// VerifyArgs are the arguments of snapshot.verify.
+type VerifyArgs struct {
+	RepoRoot string `json:"repo_root,omitempty"`
+}
+
+// HistoryArgs are the arguments of snapshot.history.
+type HistoryArgs struct {
+	RepoRoot   string `json:"repo_root,omitempty"`
+	SnapshotID string `json:"snapshot_id"`
+}
+
+type listResult struct {
+	Entries []struct {
+		Path    string `json:"path"`
+		Type    string `json:"type"`
+		Size    int64  `json:"size"`
+		SHA     string `json:"sha"`
+		Omitted string `json:"omitted"`
+	} `json:"entries"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// Entries walks the snapshot tree; snapshot.list returns one directory
+// level per call, paged by next_cursor.
+func (s *MCPStore) Entries(ctx context.Context, id string) ([]Entry, error) {
+	var entries []Entry
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+		cursor := ""
+		for {
+			args := ListArgs{RepoRoot: s.RepoRoot, Path: dir, Mode: "snapshot", SnapshotID: id, Cursor: cursor}
+			var res listResult
+			if err := s.Client.CallTool(ctx, "snapshot.list", args, &res); err != nil {
+				return nil, err
+			}
+			for _, e := range res.Entries {
+				switch e.Type {
+				case "dir":
+					dirs = append(dirs, e.Path)
+				case "file":
+					entries = append(entries, Entry{Path: e.Path, Blob: e.SHA, Size: e.Size, Omitted: e.Omitted})
+				}
+			}
+			if res.NextCursor == "" {
+				break
+			}
+			cursor = res.NextCursor
+		}
+	}
+	return entries, nil
+}
+
+// Blob reads a file through snapshot.file.
+func (s *MCPStore) Blob(ctx context.Context, id, path string) ([]byte, error) {
+	var res struct {
+		Content string `json:"content"`
+	}
+	err := s.Client.CallTool(ctx, "snapshot.file", FileArgs{RepoRoot: s.RepoRoot, Path: path, Mode: "snapshot", SnapshotID: id}, &res)
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := strings.CutPrefix(res.Content, "base64:")
+	if !ok {
+		return nil, fmt.Errorf("snapshot.file %s: unexpected content encoding", path)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot.file %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// VerifyReport is the result of snapshot.verify.
+type VerifyReport struct {
+	OK          bool          `json:"ok"`
+	Blobs       int           `json:"blobs"`
+	StoredBytes int64         `json:"stored_bytes"`
+	Problems    []BlobProblem `json:"problems"`
+}
+
+// BlobProblem is a blob that failed verification.
+type BlobProblem struct {
+	Hash    string `json:"hash"`
+	Problem string `json:"problem"`
+}
+
+// Verify revalidates every blob of the store through snapshot.verify.
+func (s *MCPStore) Verify(ctx context.Context) (*VerifyReport, error) {
+	var res VerifyReport
+	if err := s.Client.CallTool(ctx, "snapshot.verify", VerifyArgs{RepoRoot: s.RepoRoot}, &res); err != nil {
+		return nil, err
+	}
+	if res.Problems == nil {
+		res.Problems = []BlobProblem{}
+	}
+	return &res, nil
+}
+
+// History is a snapshot's provenance chain, as returned by snapshot.history.
+type History struct {
+	SnapshotID string `json:"snapshot_id"`
+	// Chain holds the snapshot and its ancestors, newest first.
+	Chain []Provenance `json:"chain"`
+	// Complete is false when an ancestor is no longer stored; Missing
+	// names it.
+	Complete bool   `json:"complete"`
+	Missing  string `json:"missing,omitempty"`
+}
+
+// Provenance records where a snapshot came from. Parent, CreatedBy and
+// PatchDigest are empty when unknown or not applicable.
+type Provenance struct {
+	SnapshotID  string `json:"snapshot_id"`
+	Parent      string `json:"parent,omitempty"`
+	CreatedBy   string `json:"created_by,omitempty"`
+	PatchDigest string `json:"patch_digest,omitempty"`
+	BaseHeadSHA string `json:"base_head_sha"`
+	Label       string `json:"label,omitempty"`
+	CreatedAt   int64  `json:"created_at,omitempty"`
+}
+
+// History walks the provenance chain of snapshot id through
+// snapshot.history.
+func (s *MCPStore) History(ctx context.Context, id string) (*History, error) {
+	var res History
+	if err := s.Client.CallTool(ctx, "snapshot.history", HistoryArgs{RepoRoot: s.RepoRoot, SnapshotID: id}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Cortex - Cortex is a standalone governance and intelligence tool for AI-assisted software development.
+It analyzes repositories, enforces structural contracts, detects drift, and generates deterministic context artifacts that enable safe, auditable collaboration between humans and AI agents.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package specschema
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FlagKind is the kind of value a CLI flag takes.
+type FlagKind string
+
+// Flag kinds a spec may declare. Their names match the pflag types,
+// lowercased, except FlagEnum, which is a string restricted to a set of
+// values.
+const (
+	FlagString      FlagKind = "string"
+	FlagBool        FlagKind = "bool"
+	FlagInt         FlagKind = "int"
+	FlagDuration    FlagKind = "duration"
+	FlagStringSlice FlagKind = "stringslice"
+	FlagIntSlice    FlagKind = "intslice"
+	FlagEnum        FlagKind = "enum"
+)
+
+// flagKindAliases maps the accepted spellings of a type to its kind.
+var flagKindAliases = map[string]FlagKind{
+	"string":      FlagString,
+	"str":         FlagString,
+	"bool":        FlagBool,
+	"boolean":     FlagBool,
+	"int":         FlagInt,
+	"integer":     FlagInt,
+	"duration":    FlagDuration,
+	"stringslice": FlagStringSlice,
+	"[]string":    FlagStringSlice,
+	"intslice":    FlagIntSlice,
+	"[]int":       FlagIntSlice,
+}
+
+// FlagType is a parsed CliFlag.Type.
+type FlagType struct {
+	Kind FlagKind
+	// Values are the allowed values of an enum, in declaration order.
+	Values []string
+}
+
+// ParseFlagType parses a flag type declaration: one of the kinds or their
+// aliases ("str", "boolean", "integer", "[]string", "[]int"), or
+// "enum[a,b,...]" with at least one value. Matching is case-insensitive
+// except for enum values.
+func ParseFlagType(s string) (FlagType, error) {
+	s = strings.TrimSpace(s)
+	if rest, ok := cutPrefixFold(s, "enum["); ok {
+		inner, ok := strings.CutSuffix(rest, "]")
+		if !ok {
+			return FlagType{}, fmt.Errorf("invalid type %q: enum values must end with \"]\"", s)
+		}
+		var values []string
+		for _, v := range strings.Split(inner, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				return FlagType{}, fmt.Errorf("invalid type %q: empty enum value", s)
+			}
+			if slices.Contains(values, v) {
+				return FlagType{}, fmt.Errorf("invalid type %q: duplicate enum value %q", s, v)
+			}
+			values = append(values, v)
+		}
+		return FlagType{Kind: FlagEnum, Values: values}, nil
+	}
+	if kind, ok := flagKindAliases[strings.ToLower(s)]; ok {
+		return FlagType{Kind: kind}, nil
+	}
+	return FlagType{}, fmt.Errorf("unknown type %q (must be one of: string, bool, int, duration, stringSlice, intSlice, enum[...])", s)
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// String returns the canonical declaration of t.
+func (t FlagType) String() string {
+	if t.Kind == FlagEnum {
+		return "enum[" + strings.Join(t.Values, ",") + "]"
+	}
+	return string(t.Kind)
+}
+
+// Matches reports whether a flag of t can be implemented by a cobra flag
+// of the pflag type cliType. An enum is implemented by a string flag.
+func (t FlagType) Matches(cliType string) bool {
+	cli := FlagKind(strings.ToLower(cliType))
+	switch t.Kind {
+	case FlagEnum:
+		return cli == FlagString
+	case FlagInt:
+		return cli == FlagInt || cli == "int32" || cli == "int64" || cli == "count"
+	case FlagStringSlice:
+		return cli == FlagStringSlice || cli == "stringarray"
+	default:
+		return cli == t.Kind
+	}
+}
+
+// ParseDefault checks that v is a valid value of t and returns it in a
+// canonical form, so that equal values compare equal: durations as
+// time.Duration.String, integers and booleans as strconv formats them, and
+// slices without brackets or spaces. pflag's "[]" for an empty slice
+// parses as "".
+func (t FlagType) ParseDefault(v string) (string, error) {
+	switch t.Kind {
+	case FlagBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a bool", v)
+		}
+		return strconv.FormatBool(b), nil
+	case FlagInt:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an int", v)
+		}
+		return strconv.FormatInt(n, 10), nil
+	case FlagDuration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a duration", v)
+		}
+		return d.String(), nil
+	case FlagEnum:
+		if !slices.Contains(t.Values, v) {
+			return "", fmt.Errorf("%q is not one of %s", v, strings.Join(t.Values, ", "))
+		}
+		return v, nil
+	case FlagStringSlice, FlagIntSlice:
+		items := splitSlice(v)
+		if t.Kind == FlagIntSlice {
+			for i, item := range items {
+				n, err := strconv.ParseInt(item, 10, 64)
+				if err != nil {
+					return "", fmt.Errorf("%q is not an int slice", v)
+				}
+				items[i] = strconv.FormatInt(n, 10)
+			}
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return v, nil
+	}
+}
+
+// splitSlice splits a slice default written as "a,b" or, as pflag prints
+// it, "[a,b]".
+func splitSlice(v string) []string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+	if strings.TrimSpace(v) == "" {
+		return []string{}
+	}
+	items := strings.Split(v, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
@@ -33,6 +33,17 @@ type SpecFrontmatter struct {
 // SpecInputs represents the inputs section of spec frontmatter.
 type SpecInputs struct {
 	Flags []CliFlag `yaml:"flags"`
+	// Arity is the number of positional arguments the command accepts, or
+	// nil when the spec does not say. (The args list some specs carry
+	// names the arguments for readers and is not checked.)
+	Arity *CliArity `yaml:"arity"`
+}
+
+// CliArity bounds the positional arguments of a command.
+type CliArity struct {
+	Min int `yaml:"min"`
+	// Max is -1 when the number is unbounded.
+	Max int `yaml:"max"`
 }
 
 // SpecOutputs represents the outputs section of spec frontmatter.
@@ -450,3 +450,154 @@ func TestValidateAll_WithErrors(t *testing.T) {
 		t.Fatal("expected error for invalid spec")
 	}
 }
+
+func TestParseFlagType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "string", want: "string"},
+		{input: "str", want: "string"},
+		{input: "Boolean", want: "bool"},
+		{input: "integer", want: "int"},
+		{input: "duration", want: "duration"},
+		{input: "stringSlice", want: "stringslice"},
+		{input: "[]int", want: "intslice"},
+		{input: "enum[text, json]", want: "enum[text,json]"},
+		{input: "ENUM[a]", want: "enum[a]"},
+		{input: "float", wantErr: true},
+		{input: "enum[]", wantErr: true},
+		{input: "enum[a,a]", wantErr: true},
+		{input: "enum[a,b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFlagType(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseFlagType(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagType_ParseDefault(t *testing.T) {
+	tests := []struct {
+		typ     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{typ: "bool", value: "true", want: "true"},
+		{typ: "bool", value: "yes", wantErr: true},
+		{typ: "int", value: "007", want: "7"},
+		{typ: "int", value: "1.5", wantErr: true},
+		{typ: "duration", value: "90s", want: "1m30s"},
+		{typ: "duration", value: "90", wantErr: true},
+		{typ: "enum[text,json]", value: "json", want: "json"},
+		{typ: "enum[text,json]", value: "yaml", wantErr: true},
+		{typ: "stringSlice", value: "[a, b]", want: "a,b"},
+		{typ: "stringSlice", value: "[]", want: ""},
+		{typ: "intSlice", value: "1,x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+"/"+tt.value, func(t *testing.T) {
+			ft, err := ParseFlagType(tt.typ)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := ft.ParseDefault(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDefault(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSpec_FlagTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    CliFlag
+		wantErr bool
+	}{
+		{name: "untyped", flag: CliFlag{Name: "--out", Default: "anything"}},
+		{name: "typed default", flag: CliFlag{Name: "--timeout", Type: "duration", Default: "5m"}},
+		{name: "enum", flag: CliFlag{Name: "--format", Type: "enum[text,json]", Default: "text"}},
+		{name: "unknown type", flag: CliFlag{Name: "--ratio", Type: "float"}, wantErr: true},
+		{name: "invalid default", flag: CliFlag{Name: "--retries", Type: "int", Default: "many"}, wantErr: true},
+		{name: "default outside enum", flag: CliFlag{Name: "--format", Type: "enum[text,json]", Default: "yaml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := Spec{
+				Path: "spec/test/FEATURE.md",
+				Frontmatter: SpecFrontmatter{
+					Feature: "FEATURE",
+					Version: "v1",
+					Status:  "done",
+					Domain:  "test",
+					Inputs:  SpecInputs{Flags: []CliFlag{tt.flag}},
+				},
+			}
+			err := ValidateSpec(&spec)
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSpec_Arity(t *testing.T) {
+	tests := []struct {
+		arity   CliArity
+		wantErr bool
+	}{
+		{arity: CliArity{Min: 0, Max: 0}},
+		{arity: CliArity{Min: 1, Max: -1}},
+		{arity: CliArity{Min: 2, Max: 1}, wantErr: true},
+		{arity: CliArity{Min: -1, Max: 1}, wantErr: true},
+		{arity: CliArity{Min: 0, Max: -2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		arity := tt.arity
+		spec := Spec{
+			Path: "spec/test/FEATURE.md",
+			Frontmatter: SpecFrontmatter{
+				Feature: "FEATURE",
+				Version: "v1",
+				Status:  "done",
+				Domain:  "test",
+				Inputs:  SpecInputs{Arity: &arity},
+			},
+		}
+		err := ValidateSpec(&spec)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("arity %+v: wantErr %v, got %v", tt.arity, tt.wantErr, err)
+		}
+	}
+}
@@ -97,6 +97,13 @@ func ValidateSpec(spec *Spec) error {
 		if normalizedName == "" {
 			return fmt.Errorf("flag[%d]: name cannot be empty after normalization", i)
 		}
+		if err := validateFlagType(flag); err != nil {
+			return fmt.Errorf("flag %s: %w", flag.Name, err)
+		}
+	}
+
+	if a := fm.Inputs.Arity; a != nil && (a.Min < 0 || a.Max < -1 || (a.Max >= 0 && a.Max < a.Min)) {
+		return fmt.Errorf("invalid arity: min %d, max %d (need 0 <= min <= max, or max -1 for unbounded)", a.Min, a.Max)
 	}
 
 	// Validate exit codes if present
@@ -109,6 +116,26 @@ func ValidateSpec(spec *Spec) error {
 	return nil
 }
 
+// validateFlagType checks that a flag's type, if declared, is one of the
+// flag types, and that its default is a value of that type. A default
+// without a type is not checked.
+func validateFlagType(flag CliFlag) error {
+	if flag.Type == "" {
+		return nil
+	}
+	t, err := ParseFlagType(flag.Type)
+	if err != nil {
+		return err
+	}
+	if flag.Default == "" {
+		return nil
+	}
+	if _, err := t.ParseDefault(flag.Default); err != nil {
+		return fmt.Errorf("invalid default: %w", err)
+	}
+	return nil
+}
+
 // inferDomainFromPath extracts the domain from a spec file path.
 // For example, "spec/commands/build.md" -> "commands"
 func inferDomainFromPath(path string) string {
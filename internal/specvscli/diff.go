@@ -17,6 +17,8 @@ package specvscli
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/bartekus/cortex/internal/specschema"
@@ -24,18 +26,43 @@ import (
 )
 
 // DiffResult represents the result of comparing specs to CLI implementation.
+// Errors and Warnings are the messages of the findings; the other slices
+// hold the same findings by kind, for reports. All are sorted by flag name.
 type DiffResult struct {
-	CommandName string
-	Errors      []string
-	Warnings    []string
+	CommandName string `json:"command"`
+	// Feature and Spec identify the spec the command was compared with.
+	Feature string `json:"feature,omitempty"`
+	Spec    string `json:"spec,omitempty"`
+	// MissingFlags are declared by the spec but do not exist in the CLI
+	// (errors).
+	MissingFlags []string `json:"missing_flags"`
+	// ExtraFlags exist in the CLI but are not declared by the spec
+	// (warnings).
+	ExtraFlags []string `json:"extra_flags"`
+	// TypeMismatches are errors, DefaultMismatches warnings.
+	TypeMismatches    []Mismatch `json:"type_mismatches"`
+	DefaultMismatches []Mismatch `json:"default_mismatches"`
+	Errors            []string   `json:"errors"`
+	Warnings          []string   `json:"warnings"`
+}
+
+// Mismatch is a flag whose spec and CLI values differ.
+type Mismatch struct {
+	Flag string `json:"flag"`
+	Spec string `json:"spec"`
+	CLI  string `json:"cli"`
 }
 
 // CompareFlags compares flags from a spec to flags from CLI introspection.
 func CompareFlags(specFlags []specschema.CliFlag, cliFlags []introspect.FlagInfo, commandName string) DiffResult {
 	result := DiffResult{
-		CommandName: commandName,
-		Errors:      []string{},
-		Warnings:    []string{},
+		CommandName:       commandName,
+		MissingFlags:      []string{},
+		ExtraFlags:        []string{},
+		TypeMismatches:    []Mismatch{},
+		DefaultMismatches: []Mismatch{},
+		Errors:            []string{},
+		Warnings:          []string{},
 	}
 
 	// Build maps for easier lookup
@@ -53,29 +80,44 @@ func CompareFlags(specFlags []specschema.CliFlag, cliFlags []introspect.FlagInfo
 	}
 
 	// Check: spec declares flag that doesn't exist in CLI
-	for name, specFlag := range specFlagMap {
+	for _, name := range sortedKeys(specFlagMap) {
+		specFlag := specFlagMap[name]
 		cliFlag, exists := cliFlagMap[name]
 		if !exists {
+			result.MissingFlags = append(result.MissingFlags, specFlag.Name)
 			result.Errors = append(result.Errors, fmt.Sprintf("spec declares flag %q that does not exist in CLI", specFlag.Name))
 			continue
 		}
 
 		// Check type alignment (if spec specifies type)
-		if specFlag.Type != "" && cliFlag.Type != "" {
-			normalizedSpecType := normalizeType(specFlag.Type)
-			normalizedCLIType := normalizeType(cliFlag.Type)
-			if normalizedSpecType != normalizedCLIType {
-				result.Errors = append(result.Errors, fmt.Sprintf("flag %q type mismatch: spec has %q but CLI has %q", name, specFlag.Type, cliFlag.Type))
+		var specType *specschema.FlagType
+		if specFlag.Type != "" {
+			t, err := specschema.ParseFlagType(specFlag.Type)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("flag %q: %v", name, err))
+			} else {
+				specType = &t
 			}
 		}
-
-		// Check default value alignment (if spec specifies default)
-		if specFlag.Default != "" && cliFlag.Default != "" {
-			if specFlag.Default != cliFlag.Default {
-				result.Warnings = append(result.Warnings, fmt.Sprintf("flag %q default mismatch: spec has %q but CLI has %q", name, specFlag.Default, cliFlag.Default))
+		if specType != nil && cliFlag.Type != "" && !specType.Matches(normalizeType(cliFlag.Type)) {
+			result.TypeMismatches = append(result.TypeMismatches, Mismatch{Flag: name, Spec: specFlag.Type, CLI: cliFlag.Type})
+			result.Errors = append(result.Errors, fmt.Sprintf("flag %q type mismatch: spec has %q but CLI has %q", name, specFlag.Type, cliFlag.Type))
+			specType = nil
+		}
+		if specType != nil && specType.Kind == specschema.FlagEnum && cliFlag.Default != "" {
+			if _, err := specType.ParseDefault(cliFlag.Default); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("flag %q CLI default: %v", name, err))
 			}
 		}
 
+		// Check default value alignment (if spec specifies default). With a
+		// declared type, defaults are compared as values of that type, so
+		// "90s" matches the CLI's "1m30s".
+		if specFlag.Default != "" && cliFlag.Default != "" && !equalDefaults(specType, specFlag.Default, cliFlag.Default) {
+			result.DefaultMismatches = append(result.DefaultMismatches, Mismatch{Flag: name, Spec: specFlag.Default, CLI: cliFlag.Default})
+			result.Warnings = append(result.Warnings, fmt.Sprintf("flag %q default mismatch: spec has %q but CLI has %q", name, specFlag.Default, cliFlag.Default))
+		}
+
 		// Check description alignment (if spec specifies description)
 		// Description comparison is lenient - just check if both are non-empty
 		// Full text matching would be too strict
@@ -83,13 +125,14 @@ func CompareFlags(specFlags []specschema.CliFlag, cliFlags []introspect.FlagInfo
 	}
 
 	// Check: CLI has flag that's not in spec
-	// Note: We skip global/persistent flags that are inherited from root
-	for name, cliFlag := range cliFlagMap {
-		if cliFlag.Persistent {
-			// Skip persistent flags - they're inherited from root
+	// Note: We skip global/persistent flags that are inherited from root,
+	// and hidden flags, which are not part of the documented surface
+	for _, name := range sortedKeys(cliFlagMap) {
+		if f := cliFlagMap[name]; f.Persistent || f.Hidden {
 			continue
 		}
 		if _, exists := specFlagMap[name]; !exists {
+			result.ExtraFlags = append(result.ExtraFlags, name)
 			result.Warnings = append(result.Warnings, fmt.Sprintf("CLI has flag %q that is not documented in spec", name))
 		}
 	}
@@ -97,6 +140,30 @@ func CompareFlags(specFlags []specschema.CliFlag, cliFlags []introspect.FlagInfo
 	return result
 }
 
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// equalDefaults reports whether two defaults are the same value of t, or
+// the same string when t is nil or either does not parse.
+func equalDefaults(t *specschema.FlagType, spec, cli string) bool {
+	if t == nil {
+		return spec == cli
+	}
+	a, errA := t.ParseDefault(spec)
+	b, errB := t.ParseDefault(cli)
+	if errA != nil || errB != nil {
+		return spec == cli
+	}
+	return a == b
+}
+
 // normalizeType normalizes type strings for comparison.
 func normalizeType(typ string) string {
 	typ = strings.ToLower(typ)
@@ -135,15 +202,13 @@ func CompareAllCommands(specs []specschema.Spec, cliCommands []introspect.Comman
 		featureID := inferFeatureID(cmd.Use)
 		spec, hasSpec := specMap[featureID]
 
-		if !hasSpec {
-			// No spec for this command - skip
-			continue
-		}
-
-		// Compare flags
-		diff := CompareFlags(spec.Frontmatter.Inputs.Flags, cmd.Flags, cmd.Use)
-		if len(diff.Errors) > 0 || len(diff.Warnings) > 0 {
-			results = append(results, diff)
+		// A command without a spec (such as the root) is not compared, but
+		// its subcommands may have one.
+		if hasSpec {
+			diff := compareSpec(spec, &cmd)
+			if len(diff.Errors) > 0 || len(diff.Warnings) > 0 {
+				results = append(results, diff)
+			}
 		}
 
 		// Recursively check subcommands
@@ -162,7 +227,7 @@ func compareSubcommands(cmd *introspect.CommandInfo, specMap map[string]specsche
 
 	featureID := inferFeatureID(cmd.Use)
 	if spec, hasSpec := specMap[featureID]; hasSpec {
-		diff := CompareFlags(spec.Frontmatter.Inputs.Flags, cmd.Flags, cmd.Use)
+		diff := compareSpec(spec, cmd)
 		if len(diff.Errors) > 0 || len(diff.Warnings) > 0 {
 			results = append(results, diff)
 		}
@@ -176,6 +241,59 @@ func compareSubcommands(cmd *introspect.CommandInfo, specMap map[string]specsche
 	return results
 }
 
+// compareSpec compares the flags and the rest of the contract of cmd with
+// spec and records which spec it was.
+func compareSpec(spec specschema.Spec, cmd *introspect.CommandInfo) DiffResult {
+	diff := CompareFlags(spec.Frontmatter.Inputs.Flags, cmd.Flags, cmd.Use)
+	diff.Feature, diff.Spec = spec.Frontmatter.Feature, spec.Path
+	compareContract(spec.Frontmatter, cmd, &diff)
+	return diff
+}
+
+// compareContract compares the arity, exit codes and deprecation of cmd
+// with the spec's. The arity is compared when the spec declares
+// inputs.arity, the exit codes when both declare them; a spec may list
+// fewer codes than the command, whose codes are often the whole taxonomy
+// inherited from the root.
+func compareContract(fm specschema.SpecFrontmatter, cmd *introspect.CommandInfo, diff *DiffResult) {
+	if a := fm.Inputs.Arity; a != nil && (a.Min != cmd.Args.Min || a.Max != cmd.Args.Max) {
+		diff.Errors = append(diff.Errors, fmt.Sprintf("arity mismatch: spec accepts %s but CLI accepts %s", arity(a.Min, a.Max), arity(cmd.Args.Min, cmd.Args.Max)))
+	}
+
+	if len(fm.Outputs.ExitCodes) > 0 && cmd.ExitCodes != nil {
+		var missing []int
+		for _, code := range fm.Outputs.ExitCodes {
+			if !slices.Contains(cmd.ExitCodes, code) && !slices.Contains(missing, code) {
+				missing = append(missing, code)
+			}
+		}
+		sort.Ints(missing)
+		for _, code := range missing {
+			diff.Errors = append(diff.Errors, fmt.Sprintf("spec declares exit code %d that CLI does not declare", code))
+		}
+	}
+
+	switch deprecated := fm.Status == "deprecated"; {
+	case deprecated && cmd.Deprecated == "":
+		diff.Warnings = append(diff.Warnings, "spec is deprecated but CLI command is not")
+	case !deprecated && cmd.Deprecated != "":
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("CLI command is deprecated (%s) but spec status is %q", cmd.Deprecated, fm.Status))
+	}
+}
+
+// arity describes a number of positional arguments: "1 args", "0-2 args"
+// or "1+ args".
+func arity(minArgs, maxArgs int) string {
+	switch {
+	case maxArgs < 0:
+		return fmt.Sprintf("%d+ args", minArgs)
+	case minArgs == maxArgs:
+		return fmt.Sprintf("%d args", minArgs)
+	default:
+		return fmt.Sprintf("%d-%d args", minArgs, maxArgs)
+	}
+}
+
 // inferFeatureID attempts to infer a feature ID from a command name.
 // For example: "build" -> "CLI_BUILD", "deploy" -> "CLI_DEPLOY"
 func inferFeatureID(commandUse string) string {
@@ -183,3 +301,28 @@ func inferFeatureID(commandUse string) string {
 	upper := strings.ToUpper(commandUse)
 	return "CLI_" + upper
 }
+
+// Report is the structured result of a spec-vs-cli comparison.
+type Report struct {
+	// Strict counts warnings as errors.
+	Strict bool `json:"strict"`
+	// OK is false when there are errors, or warnings under Strict.
+	OK       bool         `json:"ok"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+	Commands []DiffResult `json:"commands"`
+}
+
+// NewReport summarizes the results of CompareAllCommands.
+func NewReport(results []DiffResult, strict bool) Report {
+	r := Report{Strict: strict, Commands: results}
+	if r.Commands == nil {
+		r.Commands = []DiffResult{}
+	}
+	for _, res := range results {
+		r.Errors += len(res.Errors)
+		r.Warnings += len(res.Warnings)
+	}
+	r.OK = r.Errors == 0 && (!strict || r.Warnings == 0)
+	return r
+}
@@ -471,3 +471,168 @@ func TestCompareFlags_EmptyCLIFlags(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
+
+func TestCompareFlags_StructuredFindings(t *testing.T) {
+	specFlags := []specschema.CliFlag{
+		{Name: "--env", Type: "string", Default: "dev"},
+		{Name: "--count", Type: "int"},
+		{Name: "--version"},
+	}
+	cliFlags := []introspect.FlagInfo{
+		{Name: "env", Type: "string", Default: "prod"},
+		{Name: "count", Type: "string"},
+		{Name: "zeta", Type: "bool"},
+		{Name: "alpha", Type: "bool"},
+	}
+
+	result := CompareFlags(specFlags, cliFlags, "test")
+
+	if got := strings.Join(result.MissingFlags, ","); got != "--version" {
+		t.Errorf("MissingFlags = %q", got)
+	}
+	if got := strings.Join(result.ExtraFlags, ","); got != "alpha,zeta" {
+		t.Errorf("ExtraFlags = %q, want sorted", got)
+	}
+	if len(result.TypeMismatches) != 1 || result.TypeMismatches[0] != (Mismatch{Flag: "count", Spec: "int", CLI: "string"}) {
+		t.Errorf("TypeMismatches = %v", result.TypeMismatches)
+	}
+	if len(result.DefaultMismatches) != 1 || result.DefaultMismatches[0] != (Mismatch{Flag: "env", Spec: "dev", CLI: "prod"}) {
+		t.Errorf("DefaultMismatches = %v", result.DefaultMismatches)
+	}
+	if len(result.Errors) != 2 || len(result.Warnings) != 3 {
+		t.Errorf("expected 2 errors and 3 warnings, got %v and %v", result.Errors, result.Warnings)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	warnOnly := []DiffResult{{CommandName: "build", Warnings: []string{"w"}}}
+
+	if r := NewReport(warnOnly, false); !r.OK || r.Warnings != 1 {
+		t.Errorf("warnings pass without strict: %+v", r)
+	}
+	if r := NewReport(warnOnly, true); r.OK {
+		t.Errorf("warnings fail with strict: %+v", r)
+	}
+	if r := NewReport([]DiffResult{{Errors: []string{"e"}}}, false); r.OK || r.Errors != 1 {
+		t.Errorf("errors fail: %+v", r)
+	}
+	if r := NewReport(nil, true); !r.OK || r.Commands == nil {
+		t.Errorf("empty report: %+v", r)
+	}
+}
+
+func TestCompareAllCommands_RootWithoutSpec(t *testing.T) {
+	specs := []specschema.Spec{{
+		Path: "spec/cli/build.md",
+		Frontmatter: specschema.SpecFrontmatter{
+			Feature: "CLI_BUILD",
+			Inputs:  specschema.SpecInputs{Flags: []specschema.CliFlag{{Name: "--env"}}},
+		},
+	}}
+	cliCommands := []introspect.CommandInfo{{
+		Use:         "cortex",
+		Subcommands: []introspect.CommandInfo{{Use: "build"}},
+	}}
+
+	results := CompareAllCommands(specs, cliCommands)
+	if len(results) != 1 || results[0].CommandName != "build" || results[0].Spec != "spec/cli/build.md" {
+		t.Fatalf("expected the build subcommand to be compared, got %+v", results)
+	}
+}
+
+func TestCompareFlags_TypedDefaults(t *testing.T) {
+	specFlags := []specschema.CliFlag{
+		{Name: "--timeout", Type: "duration", Default: "90s"},
+		{Name: "--format", Type: "enum[text,json]", Default: "text"},
+		{Name: "--only", Type: "stringSlice", Default: "[]"},
+	}
+
+	cliFlags := []introspect.FlagInfo{
+		{Name: "timeout", Type: "duration", Default: "1m30s"},
+		{Name: "format", Type: "string", Default: "text"},
+		{Name: "only", Type: "stringSlice", Default: "[]"},
+	}
+
+	result := CompareFlags(specFlags, cliFlags, "test")
+
+	if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+		t.Errorf("expected typed flags to match, got errors %v, warnings %v", result.Errors, result.Warnings)
+	}
+}
+
+func TestCompareFlags_TypeSystemErrors(t *testing.T) {
+	specFlags := []specschema.CliFlag{
+		{Name: "--format", Type: "enum[text,json]"},
+		{Name: "--ratio", Type: "float"},
+		{Name: "--retries", Type: "int", Default: "3"},
+		{Name: "--verbose", Type: "enum[on,off]"},
+	}
+
+	cliFlags := []introspect.FlagInfo{
+		{Name: "format", Type: "string", Default: "yaml"},
+		{Name: "ratio", Type: "float64", Default: "0"},
+		{Name: "retries", Type: "int", Default: "5"},
+		{Name: "verbose", Type: "bool", Default: "false"},
+	}
+
+	result := CompareFlags(specFlags, cliFlags, "test")
+
+	for _, want := range []string{
+		`flag "format" CLI default: "yaml" is not one of text, json`,
+		`flag "ratio": unknown type "float"`,
+		`flag "verbose" type mismatch`,
+	} {
+		found := false
+		for _, err := range result.Errors {
+			if contains(err, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected error containing %q, got: %v", want, result.Errors)
+		}
+	}
+	if len(result.DefaultMismatches) != 1 || result.DefaultMismatches[0].Flag != "retries" {
+		t.Errorf("expected a default mismatch for retries, got: %v", result.DefaultMismatches)
+	}
+}
+
+func TestCompareAllCommands_Contract(t *testing.T) {
+	specs := []specschema.Spec{{
+		Path: "spec/cli/old.md",
+		Frontmatter: specschema.SpecFrontmatter{
+			Feature: "CLI_OLD",
+			Status:  "approved",
+			Inputs:  specschema.SpecInputs{Arity: &specschema.CliArity{Min: 1, Max: 1}},
+			Outputs: specschema.SpecOutputs{ExitCodes: map[string]int{"0": 0, "1": 1, "5": 5}},
+		},
+	}}
+	commands := []introspect.CommandInfo{{
+		Use: "cortex",
+		Subcommands: []introspect.CommandInfo{{
+			Use:        "old",
+			Deprecated: "use new",
+			Args:       introspect.ArgsInfo{Min: 0, Max: -1},
+			ExitCodes:  []int{0, 1, 2, 3, 4},
+			Flags:      []introspect.FlagInfo{{Name: "internal", Type: "string", Hidden: true}},
+		}},
+	}}
+
+	results := CompareAllCommands(specs, commands)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	got := results[0]
+	wantErrors := []string{
+		"arity mismatch: spec accepts 1 args but CLI accepts 0+ args",
+		"spec declares exit code 5 that CLI does not declare",
+	}
+	if strings.Join(got.Errors, "\n") != strings.Join(wantErrors, "\n") {
+		t.Errorf("errors = %v, want %v", got.Errors, wantErrors)
+	}
+	wantWarnings := []string{`CLI command is deprecated (use new) but spec status is "approved"`}
+	if strings.Join(got.Warnings, "\n") != strings.Join(wantWarnings, "\n") {
+		t.Errorf("warnings = %v, want %v (hidden flags are not extra)", got.Warnings, wantWarnings)
+	}
+}
@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package status collects the repository health snapshot shown by
+// `cortex status`: roadmap progress, the last `cortex run`, drift checks and
+// the freshness of generated artifacts.
+package status
+
+// Feature: CLI_COMMAND_STATUS
+// Spec: spec/cli/status.md
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bartekus/cortex/internal/reports/roadmap"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/templates"
+	"github.com/bartekus/cortex/pkg/gov"
+)
+
+// Check and artifact states.
+const (
+	StatePass    = "pass"
+	StateFail    = "fail"
+	StateSkip    = "skip"
+	StateFresh   = "fresh"
+	StateStale   = "stale"
+	StateMissing = "missing"
+)
+
+// Options configures Collect. Relative paths are resolved against RepoRoot.
+type Options struct {
+	RepoRoot     string
+	FeaturesPath string
+	StateDir     string
+	// Help renders the current CLI help for the help drift check; nil skips it.
+	Help func() (string, error)
+}
+
+// Report is the status snapshot.
+type Report struct {
+	Healthy   bool       `json:"healthy"`
+	Roadmap   Roadmap    `json:"roadmap"`
+	LastRun   LastRun    `json:"last_run"`
+	Drift     []Check    `json:"drift"`
+	Freshness []Artifact `json:"freshness"`
+}
+
+// Roadmap summarizes feature implementation progress.
+type Roadmap struct {
+	Total      int      `json:"total"`
+	Done       int      `json:"done"`
+	WIP        int      `json:"wip"`
+	Todo       int      `json:"todo"`
+	Completion float64  `json:"completion"`
+	Blocked    []string `json:"blocked"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// LastRun summarizes the last `cortex run`. Status is empty if there was none.
+type LastRun struct {
+	Status string   `json:"status"`
+	Skills int      `json:"skills"`
+	Failed []string `json:"failed"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Check is a drift check outcome.
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Artifact is the freshness of a generated artifact: stale when one of its
+// inputs was modified after it.
+type Artifact struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	ModTime string `json:"mtime,omitempty"` // RFC 3339, UTC
+	Detail  string `json:"detail,omitempty"`
+}
+
+// artifact declares a generated output and what it is derived from.
+// inputs selects from git-tracked files unless usesTracked is false.
+type artifact struct {
+	name        string
+	path        string
+	usesTracked bool
+	inputs      func(tracked []string) []string
+}
+
+var artifacts = []artifact{
+	{"context build", ".cortex/digest.txt", true, allTracked},
+	{"embeddings", ".cortex/index/meta.json", false, fixed(".cortex/data/chunks.ndjson")},
+	{"context docs", "docs/__generated__/context/index.md", false, fixed(".cortex/data/index.json")},
+	{"feature completion analysis", "docs/__generated__/feature-completion-analysis.md", false, fixed("spec/features.yaml")},
+	{"features overview", "docs/__generated__/features-overview.md", true, underPrefix("spec/")},
+	{"last run", ".cortex/run/last-run.json", true, allTracked},
+}
+
+// Collect gathers the snapshot. Problems in individual sections are
+// reported inside the snapshot rather than as errors.
+func Collect(ctx context.Context, opts Options) *Report {
+	r := &Report{
+		Roadmap: collectRoadmap(resolve(opts.RepoRoot, opts.FeaturesPath)),
+		LastRun: collectLastRun(resolve(opts.RepoRoot, opts.StateDir)),
+		Drift:   collectDrift(opts),
+	}
+	r.Freshness = collectFreshness(ctx, opts.RepoRoot)
+
+	r.Healthy = r.Roadmap.Error == "" && r.LastRun.Error == "" && r.LastRun.Status != StateFail
+	for _, c := range r.Drift {
+		r.Healthy = r.Healthy && c.Status != StateFail
+	}
+	return r
+}
+
+func collectRoadmap(featuresPath string) Roadmap {
+	phases, err := roadmap.DetectPhases(featuresPath)
+	if err != nil {
+		return Roadmap{Blocked: []string{}, Error: err.Error()}
+	}
+	stats := roadmap.CalculateStats(phases)
+	out := Roadmap{
+		Total:      stats.Total,
+		Done:       stats.Done,
+		WIP:        stats.WIP,
+		Todo:       stats.Todo,
+		Completion: stats.CompletionPercentage,
+		Blocked:    []string{},
+	}
+	for _, b := range roadmap.IdentifyBlockers(phases) {
+		out.Blocked = append(out.Blocked, b.FeatureID)
+	}
+	sort.Strings(out.Blocked)
+	return out
+}
+
+func collectLastRun(stateDir string) LastRun {
+	last, err := runner.NewStateStore(stateDir).ReadLastRun()
+	if err != nil {
+		return LastRun{Failed: []string{}, Error: err.Error()}
+	}
+	if last == nil {
+		return LastRun{Failed: []string{}}
+	}
+	failed := append([]string{}, last.Failed...)
+	return LastRun{Status: last.Status, Skills: len(last.Skills), Failed: failed}
+}
+
+func collectDrift(opts Options) []Check {
+	var checks []Check
+
+	if opts.Help == nil {
+		checks = append(checks, Check{Name: "help", Status: StateSkip, Detail: "no help renderer"})
+	} else if help, err := opts.Help(); err != nil {
+		checks = append(checks, checkOf("help", err))
+	} else {
+		checks = append(checks, checkOf("help", gov.CompareHelp(help, resolve(opts.RepoRoot, "spec/fixtures/cli/help.sample.txt"))))
+	}
+
+	checks = append(checks, checkOf("xray", gov.CheckXrayDrift(resolve(opts.RepoRoot, "spec/fixtures/xray/index.sample.json"))))
+
+	featuresPath := resolve(opts.RepoRoot, opts.FeaturesPath)
+	generated := []gov.GeneratedArtifact{{
+		Path: "docs/__generated__/feature-completion-analysis.md",
+		Render: func(dst string) error {
+			phases, err := roadmap.DetectPhases(featuresPath)
+			if err != nil {
+				return err
+			}
+			md, err := roadmap.GenerateMarkdownWith(templates.New(opts.RepoRoot), roadmap.CalculateStats(phases), roadmap.IdentifyBlockers(phases))
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(dst, []byte(md), 0o600)
+		},
+	}}
+	skipped, err := gov.CheckGeneratedDrift(opts.RepoRoot, generated, gov.DefaultDiffOptions())
+	switch {
+	case len(skipped) > 0:
+		checks = append(checks, Check{Name: "generated", Status: StateSkip, Detail: "not committed: " + strings.Join(skipped, ", ")})
+	default:
+		checks = append(checks, checkOf("generated", err))
+	}
+	return checks
+}
+
+func checkOf(name string, err error) Check {
+	if err != nil {
+		// Only the summary line; `cortex gov drift` shows the full diff.
+		detail, _, _ := strings.Cut(err.Error(), "\n")
+		return Check{Name: name, Status: StateFail, Detail: detail}
+	}
+	return Check{Name: name, Status: StatePass}
+}
+
+func collectFreshness(ctx context.Context, repoRoot string) []Artifact {
+	tracked, trackedErr := scanner.New(repoRoot).TrackedFiles(ctx)
+
+	out := make([]Artifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		entry := Artifact{Name: a.name, Path: a.path}
+		info, err := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(a.path)))
+		if err != nil {
+			entry.Status = StateMissing
+			out = append(out, entry)
+			continue
+		}
+		entry.ModTime = info.ModTime().UTC().Format(time.RFC3339)
+		entry.Status = StateFresh
+
+		if a.usesTracked && trackedErr != nil {
+			entry.Detail = fmt.Sprintf("inputs unknown: %v", trackedErr)
+			out = append(out, entry)
+			continue
+		}
+		if newer := newestAfter(repoRoot, a.inputs(tracked), info.ModTime()); newer != "" {
+			entry.Status = StateStale
+			entry.Detail = newer + " changed since"
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// newestAfter returns the first input (in sorted order) modified after t.
+func newestAfter(repoRoot string, inputs []string, t time.Time) string {
+	sort.Strings(inputs)
+	for _, in := range inputs {
+		info, err := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(in)))
+		if err == nil && info.ModTime().After(t) {
+			return in
+		}
+	}
+	return ""
+}
+
+func allTracked(tracked []string) []string {
+	return append([]string(nil), tracked...)
+}
+
+func fixed(paths ...string) func([]string) []string {
+	return func([]string) []string { return append([]string(nil), paths...) }
+}
+
+func underPrefix(prefix string) func([]string) []string {
+	return func(tracked []string) []string {
+		var out []string
+		for _, p := range tracked {
+			if strings.HasPrefix(p, prefix) {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+}
+
+func resolve(repoRoot, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(repoRoot, path)
+}
+
+// RenderText renders the terminal dashboard.
+func RenderText(r *Report) string {
+	var b strings.Builder
+	overall := "healthy"
+	if !r.Healthy {
+		overall = "attention needed"
+	}
+	fmt.Fprintf(&b, "Cortex status: %s\n\n", overall)
+
+	b.WriteString("Roadmap\n")
+	if r.Roadmap.Error != "" {
+		fmt.Fprintf(&b, "  ✗ %s\n", r.Roadmap.Error)
+	} else {
+		fmt.Fprintf(&b, "  %d features: %d done, %d wip, %d todo (%.1f%% complete)\n",
+			r.Roadmap.Total, r.Roadmap.Done, r.Roadmap.WIP, r.Roadmap.Todo, r.Roadmap.Completion)
+		if len(r.Roadmap.Blocked) > 0 {
+			fmt.Fprintf(&b, "  blocked: %s\n", strings.Join(r.Roadmap.Blocked, ", "))
+		}
+	}
+
+	b.WriteString("\nLast run\n")
+	switch {
+	case r.LastRun.Error != "":
+		fmt.Fprintf(&b, "  ✗ %s\n", r.LastRun.Error)
+	case r.LastRun.Status == "":
+		b.WriteString("  - no run recorded (cortex run all)\n")
+	default:
+		fmt.Fprintf(&b, "  %s %s, %d skills", symbol(r.LastRun.Status), r.LastRun.Status, r.LastRun.Skills)
+		if len(r.LastRun.Failed) > 0 {
+			fmt.Fprintf(&b, ", failed: %s", strings.Join(r.LastRun.Failed, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nDrift\n")
+	for _, c := range r.Drift {
+		b.WriteString(strings.TrimRight(fmt.Sprintf("  %s %-10s %s", symbol(c.Status), c.Name, c.Detail), " ") + "\n")
+	}
+
+	b.WriteString("\nFreshness\n")
+	for _, a := range r.Freshness {
+		line := fmt.Sprintf("  %s %-28s %-8s", symbol(a.Status), a.Name, a.Status)
+		if a.ModTime != "" {
+			line += " " + a.ModTime
+		}
+		if a.Detail != "" {
+			line += " (" + a.Detail + ")"
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return b.String()
+}
+
+func symbol(state string) string {
+	switch state {
+	case StatePass, StateFresh:
+		return "✓"
+	case StateFail:
+		return "✗"
+	case StateStale:
+		return "!"
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package status
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/runner"
+)
+
+const statusFeatures = `features:
+  - id: A
+    title: "A"
+    governance: approved
+    implementation: done
+    spec: "spec/a.md"
+  - id: B
+    title: "B"
+    governance: approved
+    implementation: todo
+    spec: "spec/b.md"
+    depends_on: [C]
+  - id: C
+    title: "C"
+    governance: draft
+    implementation: wip
+    spec: "spec/c.md"
+`
+
+func TestCollect(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string, mtime time.Time) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+	old, recent := time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)
+	write("spec/features.yaml", statusFeatures, recent)
+	write(".cortex/data/chunks.ndjson", "", old)
+	write(".cortex/index/meta.json", "{}", recent)
+	write(".cortex/data/index.json", "{}", recent)
+	write("docs/__generated__/context/index.md", "", old)
+
+	stateDir := filepath.Join(root, ".cortex", "run")
+	require.NoError(t, runner.NewStateStore(stateDir).WriteLastRun(runner.LastRun{
+		SchemaVersion: runner.StateSchemaVersion,
+		Status:        "fail",
+		Skills:        []string{"lint:x", "test:y"},
+		Failed:        []string{"test:y"},
+	}))
+
+	r := Collect(context.Background(), Options{
+		RepoRoot:     root,
+		FeaturesPath: "spec/features.yaml",
+		StateDir:     ".cortex/run",
+		Help:         func() (string, error) { return "", errors.New("boom\nmore") },
+	})
+
+	assert.False(t, r.Healthy)
+	assert.Equal(t, Roadmap{Total: 3, Done: 1, WIP: 1, Todo: 1, Completion: r.Roadmap.Completion, Blocked: []string{"B"}}, r.Roadmap)
+	assert.Equal(t, LastRun{Status: "fail", Skills: 2, Failed: []string{"test:y"}}, r.LastRun)
+
+	byName := map[string]Check{}
+	for _, c := range r.Drift {
+		byName[c.Name] = c
+	}
+	assert.Equal(t, Check{Name: "help", Status: StateFail, Detail: "boom"}, byName["help"])
+	assert.Equal(t, StateFail, byName["xray"].Status) // fixture missing
+	assert.Equal(t, StateSkip, byName["generated"].Status)
+
+	fresh := map[string]Artifact{}
+	for _, a := range r.Freshness {
+		fresh[a.Name] = a
+	}
+	assert.Equal(t, StateMissing, fresh["context build"].Status)
+	assert.Equal(t, StateFresh, fresh["embeddings"].Status)
+	assert.Equal(t, StateStale, fresh["context docs"].Status)
+	assert.Equal(t, ".cortex/data/index.json changed since", fresh["context docs"].Detail)
+
+	text := RenderText(r)
+	assert.Contains(t, text, "Cortex status: attention needed\n")
+	assert.Contains(t, text, "  3 features: 1 done, 1 wip, 1 todo")
+	assert.Contains(t, text, "  ✗ fail, 2 skills, failed: test:y\n")
+}
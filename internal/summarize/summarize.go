@@ -0,0 +1,318 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package summarize writes natural-language summaries of the files and
+// directories of the context with a language model.
+//
+// Summaries are not deterministic, so they live in their own area,
+// .cortex/llm/, apart from every deterministic artifact, and nothing
+// deterministic reads them. They are cached by content: a file is only
+// summarized again when its content changes, and a directory when the
+// content of a file below it does.
+//
+// The package does not call a model itself; commands pass a Completer
+// (see spec/system/llm.md).
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package summarize
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/projection"
+)
+
+// SchemaVersion is the schema_version of summaries.json.
+const SchemaVersion = "1.0"
+
+// Locations of the summaries, relative to the repository root.
+const (
+	Dir          = ".cortex/llm"
+	JSONFile     = Dir + "/summaries.json"
+	MarkdownFile = Dir + "/summaries.md"
+)
+
+// maxContent caps the bytes of a file shown to the model.
+const maxContent = 24 << 10
+
+// Completer returns a model's reply to a prompt.
+type Completer func(ctx context.Context, system, prompt string) (string, error)
+
+// Summaries are the summaries of a context, keyed by repo-relative path;
+// the root directory is ".".
+type Summaries struct {
+	SchemaVersion string           `json:"schema_version"`
+	Files         map[string]Entry `json:"files"`
+	Dirs          map[string]Entry `json:"dirs"`
+}
+
+// Entry is a summary and what it was made from.
+type Entry struct {
+	// Hash is "sha256:<hex>" of the file content, or for a directory of
+	// the names and hashes of its files and subdirectories.
+	Hash string `json:"hash"`
+	// Model is the provider and model that wrote Summary.
+	Model   string `json:"model"`
+	Summary string `json:"summary"`
+}
+
+// Options tunes Run.
+type Options struct {
+	// Model names the provider and model behind the Completer; summaries
+	// of another model are not reused. Empty reuses those of any model.
+	Model string
+	// Force summarizes everything again.
+	Force bool
+	// DryRun counts what would be summarized without calling the model.
+	DryRun bool
+}
+
+// Stats counts what Run did. With DryRun, Summarized counts what would be.
+type Stats struct {
+	Files      int
+	Dirs       int
+	Summarized int
+	Reused     int
+}
+
+// Read reads summaries.json under repoRoot.
+func Read(repoRoot string) (*Summaries, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(JSONFile)))
+	if err != nil {
+		return nil, err
+	}
+	var s Summaries
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", JSONFile, err)
+	}
+	if s.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("%s: unsupported schema_version %q", JSONFile, s.SchemaVersion)
+	}
+	return &s, nil
+}
+
+// ReadOrNil reads summaries.json, or returns nil when there is none.
+func ReadOrNil(repoRoot string) (*Summaries, error) {
+	s, err := Read(repoRoot)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// Write writes summaries.json and summaries.md under repoRoot.
+func Write(repoRoot string, s *Summaries) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := projection.AtomicWrite(filepath.Join(repoRoot, filepath.FromSlash(JSONFile)), append(data, '\n')); err != nil {
+		return err
+	}
+	return projection.AtomicWrite(filepath.Join(repoRoot, filepath.FromSlash(MarkdownFile)), []byte(Render(s)))
+}
+
+// Run summarizes the files at paths (repo-relative, slash-separated) and
+// their directories up to the root, reusing the entries of previous (nil
+// for none) whose hash and model match. Files are summarized first, then
+// directories from the deepest up, each from the summaries of its
+// children. On error it returns the summaries made so far, with the
+// previous entries of what it did not get to, so they can be saved.
+func Run(ctx context.Context, repoRoot string, paths []string, previous *Summaries, complete Completer, opts Options) (*Summaries, Stats, error) {
+	if previous == nil {
+		previous = &Summaries{}
+	}
+	s := &Summaries{SchemaVersion: SchemaVersion, Files: map[string]Entry{}, Dirs: map[string]Entry{}}
+	var stats Stats
+	reuse := func(prev Entry, ok bool, hash string) bool {
+		return ok && !opts.Force && prev.Hash == hash && (opts.Model == "" || prev.Model == opts.Model)
+	}
+
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	contents := make(map[string][]byte, len(sorted))
+	hashes := make(map[string]string, len(sorted))
+	children := map[string][]string{}
+	for _, p := range sorted {
+		content, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(p))) //nolint:gosec // G304: path from the context manifest
+		if err != nil {
+			return nil, stats, err
+		}
+		contents[p] = content
+		hashes[p] = digest(content)
+		for child, dir := p, path.Dir(p); ; child, dir = dir, path.Dir(dir) {
+			known := len(children[dir]) > 0
+			children[dir] = appendUnique(children[dir], child)
+			if dir == "." || known {
+				break
+			}
+		}
+	}
+	carry := func(err error) (*Summaries, Stats, error) {
+		for _, p := range sorted {
+			if _, done := s.Files[p]; !done {
+				if prev, ok := previous.Files[p]; ok {
+					s.Files[p] = prev
+				}
+			}
+		}
+		for dir := range children {
+			if _, done := s.Dirs[dir]; !done {
+				if prev, ok := previous.Dirs[dir]; ok {
+					s.Dirs[dir] = prev
+				}
+			}
+		}
+		return s, stats, err
+	}
+
+	for _, p := range sorted {
+		hash := hashes[p]
+		stats.Files++
+		if prev, ok := previous.Files[p]; reuse(prev, ok, hash) {
+			s.Files[p] = prev
+			stats.Reused++
+			continue
+		}
+		stats.Summarized++
+		if opts.DryRun {
+			continue
+		}
+		summary, err := complete(ctx, fileSystem, filePrompt(p, contents[p]))
+		if err != nil {
+			return carry(fmt.Errorf("summarizing %s: %w", p, err))
+		}
+		s.Files[p] = Entry{Hash: hash, Model: opts.Model, Summary: strings.TrimSpace(summary)}
+	}
+
+	dirs := make([]string, 0, len(children))
+	for dir := range children {
+		dirs = append(dirs, dir)
+	}
+	// Deepest first, so subdirectories are summarized before their parent.
+	sort.Slice(dirs, func(i, j int) bool {
+		if di, dj := depth(dirs[i]), depth(dirs[j]); di != dj {
+			return di > dj
+		}
+		return dirs[i] < dirs[j]
+	})
+	for _, dir := range dirs {
+		kids := children[dir]
+		sort.Strings(kids)
+		var h strings.Builder
+		for _, k := range kids {
+			if hash, ok := hashes[k]; ok {
+				fmt.Fprintf(&h, "f %s %s\n", k, hash)
+			} else {
+				fmt.Fprintf(&h, "d %s %s\n", k, hashes[k+"/"])
+			}
+		}
+		hash := digest([]byte(h.String()))
+		hashes[dir+"/"] = hash
+		stats.Dirs++
+
+		if prev, ok := previous.Dirs[dir]; reuse(prev, ok, hash) {
+			s.Dirs[dir] = prev
+			stats.Reused++
+			continue
+		}
+		stats.Summarized++
+		if opts.DryRun {
+			continue
+		}
+		var prompt strings.Builder
+		fmt.Fprintf(&prompt, "Directory: %s\n\n", dir)
+		for _, k := range kids {
+			e, ok := s.Files[k]
+			if !ok {
+				e = s.Dirs[k]
+			}
+			fmt.Fprintf(&prompt, "- %s: %s\n", k, e.Summary)
+		}
+		summary, err := complete(ctx, dirSystem, prompt.String())
+		if err != nil {
+			return carry(fmt.Errorf("summarizing %s: %w", dir, err))
+		}
+		s.Dirs[dir] = Entry{Hash: hash, Model: opts.Model, Summary: strings.TrimSpace(summary)}
+	}
+	return s, stats, nil
+}
+
+const (
+	fileSystem = "You summarize source files for engineers new to a repository. " +
+		"Reply with two or three plain sentences on what the file is for and its main parts. No preamble, no Markdown headings."
+	dirSystem = "You summarize directories of a repository from the summaries of their files and subdirectories. " +
+		"Reply with two or three plain sentences on the purpose of the directory. No preamble, no Markdown headings."
+)
+
+func filePrompt(p string, content []byte) string {
+	text := string(content)
+	if len(text) > maxContent {
+		text = text[:maxContent] + "\n[truncated]"
+	}
+	return fmt.Sprintf("File: %s\n\n%s\n", p, text)
+}
+
+// Render returns the summaries as Markdown: each directory, then its files.
+func Render(s *Summaries) string {
+	var b strings.Builder
+	b.WriteString("<!-- Written by cortex context summarize with a language model. Not deterministic; not a contract. -->\n\n")
+	b.WriteString("# Summaries\n")
+	files := map[string][]string{}
+	dirs := make([]string, 0, len(s.Dirs))
+	for p := range s.Files {
+		files[path.Dir(p)] = append(files[path.Dir(p)], p)
+	}
+	for dir := range s.Dirs {
+		dirs = append(dirs, dir)
+	}
+	for dir := range files {
+		if _, ok := s.Dirs[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		fmt.Fprintf(&b, "\n## `%s`\n\n", dir)
+		if e, ok := s.Dirs[dir]; ok {
+			b.WriteString(e.Summary + "\n\n")
+		}
+		sort.Strings(files[dir])
+		for _, p := range files[dir] {
+			fmt.Fprintf(&b, "- `%s`: %s\n", path.Base(p), strings.ReplaceAll(s.Files[p].Summary, "\n", " "))
+		}
+	}
+	return b.String()
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func depth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, x := range list {
+		if x == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
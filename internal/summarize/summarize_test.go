@@ -0,0 +1,112 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fake answers with the first line of the prompt and records every prompt.
+type fake struct {
+	prompts []string
+	failOn  string
+}
+
+func (f *fake) complete(_ context.Context, _, prompt string) (string, error) {
+	first, _, _ := strings.Cut(prompt, "\n")
+	if f.failOn != "" && first == f.failOn {
+		return "", errors.New("boom")
+	}
+	f.prompts = append(f.prompts, first)
+	return "About " + first + ".\n", nil
+}
+
+func write(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for p, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+}
+
+var paths = []string{"main.go", "a/b/c.go", "a/d.go"}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	write(t, root, map[string]string{"main.go": "package main\n", "a/b/c.go": "package b\n", "a/d.go": "package a\n"})
+
+	f := &fake{}
+	s, stats, err := Run(context.Background(), root, paths, nil, f.complete, Options{Model: "fake/v1"})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Files: 3, Dirs: 3, Summarized: 6}, stats)
+	// Files first, then directories deepest first.
+	assert.Equal(t, []string{
+		"File: a/b/c.go", "File: a/d.go", "File: main.go",
+		"Directory: a/b", "Directory: a", "Directory: .",
+	}, f.prompts)
+	assert.Equal(t, Entry{Hash: digest([]byte("package a\n")), Model: "fake/v1", Summary: "About File: a/d.go."}, s.Files["a/d.go"])
+	assert.Equal(t, "About Directory: ..", s.Dirs["."].Summary)
+
+	// Unchanged content is never summarized again.
+	f = &fake{}
+	again, stats, err := Run(context.Background(), root, paths, s, f.complete, Options{Model: "fake/v1"})
+	require.NoError(t, err)
+	assert.Empty(t, f.prompts)
+	assert.Equal(t, 6, stats.Reused)
+	assert.Equal(t, s, again)
+
+	// A change summarizes the file and the directories above it.
+	write(t, root, map[string]string{"a/b/c.go": "package b // changed\n"})
+	_, stats, err = Run(context.Background(), root, paths, s, f.complete, Options{Model: "fake/v1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"File: a/b/c.go", "Directory: a/b", "Directory: a", "Directory: ."}, f.prompts)
+	assert.Equal(t, 2, stats.Reused)
+
+	// Another model, or --force, summarizes everything again.
+	_, stats, err = Run(context.Background(), root, paths, s, nil, Options{Model: "fake/v2", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 6, stats.Summarized)
+	_, stats, err = Run(context.Background(), root, paths, s, nil, Options{Force: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 6, stats.Summarized)
+}
+
+func TestRun_ErrorKeepsPrevious(t *testing.T) {
+	root := t.TempDir()
+	write(t, root, map[string]string{"main.go": "package main\n", "a/b/c.go": "package b\n", "a/d.go": "package a\n"})
+	s, _, err := Run(context.Background(), root, paths, nil, (&fake{}).complete, Options{Model: "fake/v1"})
+	require.NoError(t, err)
+
+	write(t, root, map[string]string{"a/b/c.go": "package b // changed\n", "main.go": "package main // changed\n"})
+	partial, _, err := Run(context.Background(), root, paths, s, (&fake{failOn: "File: main.go"}).complete, Options{Model: "fake/v1"})
+	require.Error(t, err)
+	assert.NotEqual(t, s.Files["a/b/c.go"], partial.Files["a/b/c.go"])
+	assert.Equal(t, s.Files["main.go"], partial.Files["main.go"])
+	assert.Equal(t, s.Dirs, partial.Dirs)
+}
+
+func TestWriteRead(t *testing.T) {
+	root := t.TempDir()
+	write(t, root, map[string]string{"main.go": "package main\n", "a/b/c.go": "package b\n", "a/d.go": "package a\n"})
+	s, _, err := Run(context.Background(), root, paths, nil, (&fake{}).complete, Options{Model: "fake/v1"})
+	require.NoError(t, err)
+
+	none, err := ReadOrNil(root)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+	require.NoError(t, Write(root, s))
+	got, err := Read(root)
+	require.NoError(t, err)
+	assert.Equal(t, s, got)
+
+	md, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(MarkdownFile)))
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## `a`\n\nAbout Directory: a.\n\n- `d.go`: About File: a/d.go.\n")
+}
@@ -0,0 +1,439 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package symbols extracts a navigable symbol graph from Go sources.
+//
+// Extraction is purely syntactic (go/parser, no type checking) so it works on
+// any checkout without building it. References are resolved to symbols
+// declared in the repository: identifiers naming a package-level declaration
+// of the same package, and selectors on imports of in-repo packages.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package symbols
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/canonjson"
+	"github.com/bartekus/cortex/internal/gomod"
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+// SchemaVersion is the version of symbols.json.
+const SchemaVersion = "1.0"
+
+// Symbol kinds.
+const (
+	KindStruct    = "struct"
+	KindInterface = "interface"
+	KindType      = "type" // any other named type
+	KindFunc      = "func"
+	KindMethod    = "method"
+	KindConst     = "const"
+	KindVar       = "var"
+)
+
+// Graph is the content of symbols.json.
+type Graph struct {
+	SchemaVersion string    `json:"schema_version"`
+	Packages      []Package `json:"packages"`
+	Symbols       []Symbol  `json:"symbols"`
+	// Digest is the SHA-256 of the canonical JSON of the graph with an empty digest.
+	Digest string `json:"digest"`
+}
+
+// Package is a Go package (one directory).
+type Package struct {
+	Path  string   `json:"path"` // import path
+	Dir   string   `json:"dir"`  // repo-relative directory
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+}
+
+// Symbol is a package-level declaration.
+type Symbol struct {
+	// ID is "<import path>.<Name>" or "<import path>.<Receiver>.<Name>" for methods.
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Package   string `json:"package"`
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Exported  bool   `json:"exported"`
+	Receiver  string `json:"receiver,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	// Methods lists method IDs for named types and method names for interfaces.
+	Methods []string `json:"methods,omitempty"`
+	// References are IDs of in-repo symbols used by this declaration.
+	References []string `json:"references,omitempty"`
+	// Digest is the SHA-256 of the declaration source (doc comment excluded).
+	Digest string `json:"digest"`
+}
+
+// Extract builds the symbol graph for the non-test Go files among files.
+func Extract(repoRoot string, files []xray.FileNode) (*Graph, error) {
+	modules, err := gomod.Find(repoRoot, files)
+	if err != nil {
+		return nil, err
+	}
+
+	byDir := make(map[string][]string)
+	for _, f := range files {
+		if strings.HasSuffix(f.Path, ".go") && !strings.HasSuffix(f.Path, "_test.go") {
+			dir := path.Dir(f.Path)
+			byDir[dir] = append(byDir[dir], f.Path)
+		}
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	// First pass: parse everything so cross-package references can be resolved.
+	var pkgs []*parsedPackage
+	byImport := make(map[string]*parsedPackage)
+	for _, dir := range dirs {
+		pkg, err := parsePackage(repoRoot, dir, byDir[dir], gomod.ImportPath(modules, dir))
+		if err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+		byImport[pkg.info.Path] = pkg
+	}
+
+	g := &Graph{SchemaVersion: SchemaVersion, Packages: []Package{}, Symbols: []Symbol{}}
+	for _, pkg := range pkgs {
+		g.Packages = append(g.Packages, pkg.info)
+		g.Symbols = append(g.Symbols, pkg.symbols(byImport)...)
+	}
+	linkMethods(g.Symbols)
+
+	sort.Slice(g.Packages, func(i, j int) bool { return g.Packages[i].Path < g.Packages[j].Path })
+	sort.Slice(g.Symbols, func(i, j int) bool {
+		a, b := g.Symbols[i], g.Symbols[j]
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+
+	digest, err := canonjson.Sum256(g)
+	if err != nil {
+		return nil, fmt.Errorf("computing symbols digest: %w", err)
+	}
+	g.Digest = digest
+	return g, nil
+}
+
+type parsedFile struct {
+	path    string
+	src     []byte
+	ast     *ast.File
+	imports map[string]string // local name -> import path
+}
+
+type parsedPackage struct {
+	info  Package
+	fset  *token.FileSet
+	files []parsedFile
+	decls map[string]bool // package-level names
+}
+
+func parsePackage(repoRoot, dir string, paths []string, importPath string) (*parsedPackage, error) {
+	sort.Strings(paths)
+	pkg := &parsedPackage{
+		info:  Package{Path: importPath, Dir: dir, Files: []string{}},
+		fset:  token.NewFileSet(),
+		decls: make(map[string]bool),
+	}
+
+	for _, p := range paths {
+		src, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(p))) //nolint:gosec // paths come from the XRAY index
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", p, err)
+		}
+		file, err := parser.ParseFile(pkg.fset, p, src, parser.SkipObjectResolution)
+		if err != nil {
+			// Unparseable files (e.g. templates with a .go suffix) are skipped.
+			continue
+		}
+		if pkg.info.Name == "" {
+			pkg.info.Name = file.Name.Name
+		}
+		if file.Name.Name != pkg.info.Name {
+			// Stray files of another package (e.g. `//go:build ignore` tools).
+			continue
+		}
+
+		pf := parsedFile{path: p, src: src, ast: file, imports: make(map[string]string)}
+		for _, imp := range file.Imports {
+			ip, _ := strconv.Unquote(imp.Path.Value)
+			name := path.Base(ip)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			pf.imports[name] = ip
+		}
+		pkg.files = append(pkg.files, pf)
+		pkg.info.Files = append(pkg.info.Files, p)
+
+		for _, d := range file.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					pkg.decls[d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						pkg.decls[s.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							pkg.decls[n.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(pkg.files) == 0 {
+		return nil, nil
+	}
+	return pkg, nil
+}
+
+func (pkg *parsedPackage) symbols(byImport map[string]*parsedPackage) []Symbol {
+	var out []Symbol
+	for _, f := range pkg.files {
+		for _, d := range f.ast.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				out = append(out, pkg.funcSymbol(f, d, byImport))
+			case *ast.GenDecl:
+				out = append(out, pkg.genSymbols(f, d, byImport)...)
+			}
+		}
+	}
+	return out
+}
+
+func (pkg *parsedPackage) funcSymbol(f parsedFile, d *ast.FuncDecl, byImport map[string]*parsedPackage) Symbol {
+	s := pkg.newSymbol(f, d.Name.Name, d, d.Pos())
+	s.Kind = KindFunc
+	if d.Recv != nil && len(d.Recv.List) > 0 {
+		s.Kind = KindMethod
+		s.Receiver = receiverName(d.Recv.List[0].Type)
+		s.ID = pkg.info.Path + "." + s.Receiver + "." + s.Name
+	}
+	s.Signature = pkg.source(f, d.Pos(), d.Type.End())
+	if d.Body == nil {
+		s.Signature = pkg.source(f, d.Pos(), d.End())
+	}
+	s.References = pkg.references(f, d, s.ID, byImport)
+	return s
+}
+
+func (pkg *parsedPackage) genSymbols(f parsedFile, d *ast.GenDecl, byImport map[string]*parsedPackage) []Symbol {
+	var out []Symbol
+	for _, spec := range d.Specs {
+		switch sp := spec.(type) {
+		case *ast.TypeSpec:
+			s := pkg.newSymbol(f, sp.Name.Name, sp, sp.Pos())
+			s.Kind = KindType
+			switch t := sp.Type.(type) {
+			case *ast.StructType:
+				s.Kind = KindStruct
+			case *ast.InterfaceType:
+				s.Kind = KindInterface
+				s.Methods = interfaceMethods(t)
+			}
+			if len(d.Specs) == 1 {
+				s.StartLine = pkg.fset.Position(d.Pos()).Line
+			}
+			s.Signature = "type " + pkg.source(f, sp.Pos(), sp.End())
+			if i := strings.IndexByte(s.Signature, '\n'); i >= 0 {
+				s.Signature = s.Signature[:i]
+			}
+			s.References = pkg.references(f, sp.Type, s.ID, byImport)
+			out = append(out, s)
+		case *ast.ValueSpec:
+			kind := KindVar
+			if d.Tok == token.CONST {
+				kind = KindConst
+			}
+			var refs []string
+			if sp.Type != nil {
+				refs = append(refs, pkg.references(f, sp.Type, "", byImport)...)
+			}
+			for _, v := range sp.Values {
+				refs = append(refs, pkg.references(f, v, "", byImport)...)
+			}
+			for _, n := range sp.Names {
+				if n.Name == "_" {
+					continue
+				}
+				s := pkg.newSymbol(f, n.Name, sp, sp.Pos())
+				s.Kind = kind
+				s.References = dedupe(refs, s.ID)
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func (pkg *parsedPackage) newSymbol(f parsedFile, name string, node ast.Node, start token.Pos) Symbol {
+	return Symbol{
+		ID:        pkg.info.Path + "." + name,
+		Name:      name,
+		Package:   pkg.info.Path,
+		File:      f.path,
+		StartLine: pkg.fset.Position(start).Line,
+		EndLine:   pkg.fset.Position(node.End()).Line,
+		Exported:  ast.IsExported(name),
+		Digest:    "sha256:" + hashHex(pkg.source(f, node.Pos(), node.End())),
+	}
+}
+
+func (pkg *parsedPackage) source(f parsedFile, from, to token.Pos) string {
+	start := pkg.fset.Position(from).Offset
+	end := pkg.fset.Position(to).Offset
+	if start < 0 || end > len(f.src) || start > end {
+		return ""
+	}
+	return string(f.src[start:end])
+}
+
+// references collects in-repo symbols used inside node.
+func (pkg *parsedPackage) references(f parsedFile, node ast.Node, self string, byImport map[string]*parsedPackage) []string {
+	var refs []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncDecl:
+			// Skip the declared name and receiver; inspect signature and body.
+			if x.Type != nil {
+				refs = append(refs, pkg.references(f, x.Type, self, byImport)...)
+			}
+			if x.Body != nil {
+				refs = append(refs, pkg.references(f, x.Body, self, byImport)...)
+			}
+			return false
+		case *ast.SelectorExpr:
+			if id, ok := x.X.(*ast.Ident); ok {
+				if ip, ok := f.imports[id.Name]; ok {
+					if target, ok := byImport[ip]; ok && target.decls[x.Sel.Name] {
+						refs = append(refs, ip+"."+x.Sel.Name)
+					}
+					return false
+				}
+			}
+			// Field or method selectors are not package-level references.
+			refs = append(refs, pkg.references(f, x.X, self, byImport)...)
+			return false
+		case *ast.KeyValueExpr:
+			// Composite literal keys name fields, not declarations.
+			if _, ok := x.Key.(*ast.Ident); ok {
+				refs = append(refs, pkg.references(f, x.Value, self, byImport)...)
+				return false
+			}
+		case *ast.Field:
+			// Field and parameter names are local.
+			refs = append(refs, pkg.references(f, x.Type, self, byImport)...)
+			return false
+		case *ast.Ident:
+			if pkg.decls[x.Name] {
+				refs = append(refs, pkg.info.Path+"."+x.Name)
+			}
+		}
+		return true
+	})
+	return dedupe(refs, self)
+}
+
+// linkMethods attaches method IDs to their receiver types.
+func linkMethods(syms []Symbol) {
+	methods := make(map[string][]string)
+	for _, s := range syms {
+		if s.Kind == KindMethod {
+			typeID := s.Package + "." + s.Receiver
+			methods[typeID] = append(methods[typeID], s.ID)
+		}
+	}
+	for i := range syms {
+		if syms[i].Kind == KindMethod || syms[i].Kind == KindInterface {
+			continue
+		}
+		if m, ok := methods[syms[i].ID]; ok {
+			sort.Strings(m)
+			syms[i].Methods = m
+		}
+	}
+}
+
+func interfaceMethods(t *ast.InterfaceType) []string {
+	var names []string
+	for _, m := range t.Methods.List {
+		for _, n := range m.Names {
+			names = append(names, n.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+func dedupe(refs []string, self string) []string {
+	if len(refs) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(refs))
+	var out []string
+	for _, r := range refs {
+		if r == self || seen[r] {
+			continue
+		}
+		seen[r] = true
+		out = append(out, r)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func hashHex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
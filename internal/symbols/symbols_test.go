@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package symbols
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/xray"
+)
+
+func writeRepo(t *testing.T, files map[string]string) (string, []xray.FileNode) {
+	t.Helper()
+	root := t.TempDir()
+	var nodes []xray.FileNode
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+		nodes = append(nodes, xray.FileNode{Path: rel})
+	}
+	return root, nodes
+}
+
+func TestExtract(t *testing.T) {
+	root, files := writeRepo(t, map[string]string{
+		"go.mod": "module example.com/demo\n\ngo 1.24\n",
+		"store/store.go": `package store
+
+// Store persists things.
+type Store interface {
+	Put(key string) error
+	Get(key string) (string, error)
+}
+
+// Mem is an in-memory Store.
+type Mem struct {
+	Store Store
+	items map[string]string
+}
+
+// Get implements Store.
+func (m *Mem) Get(key string) (string, error) { return m.items[key], nil }
+
+func (m *Mem) Put(key string) error { m.items[key] = key; return nil }
+
+const Limit = 10
+`,
+		"cmd/main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/demo/store"
+)
+
+var defaultLimit = store.Limit
+
+func main() {
+	var s store.Store = &store.Mem{}
+	fmt.Println(s, defaultLimit)
+}
+`,
+		"store/store_test.go": "package store\n\nfunc TestX() {}\n",
+		"bad/bad.go":          "package bad\nfunc {",
+	})
+
+	g, err := Extract(root, files)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Package{
+		{Path: "example.com/demo/cmd", Dir: "cmd", Name: "main", Files: []string{"cmd/main.go"}},
+		{Path: "example.com/demo/store", Dir: "store", Name: "store", Files: []string{"store/store.go"}},
+	}, g.Packages)
+
+	ids := make([]string, 0, len(g.Symbols))
+	byID := make(map[string]Symbol)
+	for _, s := range g.Symbols {
+		ids = append(ids, s.ID)
+		byID[s.ID] = s
+	}
+	assert.Equal(t, []string{
+		"example.com/demo/cmd.defaultLimit",
+		"example.com/demo/cmd.main",
+		"example.com/demo/store.Limit",
+		"example.com/demo/store.Mem",
+		"example.com/demo/store.Mem.Get",
+		"example.com/demo/store.Mem.Put",
+		"example.com/demo/store.Store",
+	}, ids)
+
+	iface := byID["example.com/demo/store.Store"]
+	assert.Equal(t, KindInterface, iface.Kind)
+	assert.Equal(t, []string{"Get", "Put"}, iface.Methods)
+	assert.Equal(t, 4, iface.StartLine)
+
+	mem := byID["example.com/demo/store.Mem"]
+	assert.Equal(t, KindStruct, mem.Kind)
+	assert.Equal(t, []string{"example.com/demo/store.Mem.Get", "example.com/demo/store.Mem.Put"}, mem.Methods)
+	assert.Equal(t, []string{"example.com/demo/store.Store"}, mem.References)
+
+	get := byID["example.com/demo/store.Mem.Get"]
+	assert.Equal(t, KindMethod, get.Kind)
+	assert.Equal(t, "Mem", get.Receiver)
+	assert.Equal(t, "func (m *Mem) Get(key string) (string, error)", get.Signature)
+	assert.Empty(t, get.References)
+
+	assert.Equal(t, []string{
+		"example.com/demo/cmd.defaultLimit",
+		"example.com/demo/store.Mem",
+		"example.com/demo/store.Store",
+	}, byID["example.com/demo/cmd.main"].References)
+	assert.Equal(t, []string{"example.com/demo/store.Limit"}, byID["example.com/demo/cmd.defaultLimit"].References)
+
+	again, err := Extract(root, files)
+	require.NoError(t, err)
+	assert.Equal(t, g.Digest, again.Digest)
+	assert.Len(t, g.Digest, 64)
+}
@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package templates renders the documents Cortex generates (roadmap
+// analysis, context docs, reports, the static site layout) from
+// text/template sources.
+// Every template has an embedded default; a repository overrides one by
+// placing a file of the same name under .cortex/templates.
+//
+// Templates must render deterministically: the function map only holds pure
+// functions, references to clock or environment functions are rejected, and
+// every render is executed twice and compared.
+//
+// Feature: REPORTS_CORE
+// Spec: spec/reports/core.md
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Dir holds the template overrides, relative to the repository root.
+const Dir = ".cortex/templates"
+
+// Template names.
+const (
+	Roadmap                   = "roadmap.md.tmpl"
+	ContextIndex              = "context-index.md.tmpl"
+	ContextFiles              = "context-files.md.tmpl"
+	ContextModules            = "context-modules.md.tmpl"
+	ContextDependencies       = "context-dependencies.md.tmpl"
+	ContextModuleDependencies = "context-module-dependencies.md.tmpl"
+	PRSummary                 = "pr-summary.md.tmpl"
+	RunSummary                = "run-summary.md.tmpl"
+	Changelog                 = "changelog.md.tmpl"
+	Site                      = "site.html.tmpl"
+)
+
+//go:embed defaults/*.tmpl
+var defaults embed.FS
+
+// funcs are the functions available to templates. They must stay pure:
+// output may only depend on their arguments.
+var funcs = template.FuncMap{
+	"anchor":  Anchor,
+	"cell":    Cell,
+	"code":    func(s string) string { return "`" + s + "`" },
+	"join":    func(items []string, sep string) string { return strings.Join(items, sep) },
+	"lower":   strings.ToLower,
+	"percent": percent,
+	"repeat":  func(s string, n int) string { return strings.Repeat(s, n) },
+	"replace": func(s, old, repl string) string { return strings.ReplaceAll(s, old, repl) },
+	"trim":    strings.TrimSpace,
+	"trunc":   trunc,
+	"upper":   strings.ToUpper,
+}
+
+// denied are function names other template libraries offer that read the
+// clock, the environment or a random source. They parse, so the error names
+// the rule instead of reporting an undefined function, and are rejected
+// before execution.
+var denied = []string{
+	"date", "dateInZone", "env", "expandenv", "getenv", "htmlDate", "now",
+	"randAlpha", "randAlphaNum", "randAscii", "randInt", "randNumeric", "unixEpoch", "uuidv4",
+}
+
+// Set resolves templates for a repository: an override under
+// <repoRoot>/.cortex/templates wins over the embedded default.
+type Set struct {
+	root string
+}
+
+// New returns the templates of the repository at repoRoot. An empty
+// repoRoot only resolves the embedded defaults.
+func New(repoRoot string) *Set {
+	return &Set{root: repoRoot}
+}
+
+// Names returns the names of the embedded default templates, sorted.
+func Names() []string {
+	entries, _ := fs.ReadDir(defaults, "defaults")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the source of the embedded default template name.
+func Default(name string) (string, error) {
+	data, err := defaults.ReadFile(path.Join("defaults", name))
+	if err != nil {
+		return "", fmt.Errorf("unknown template %q", name)
+	}
+	return string(data), nil
+}
+
+// Source returns the source of template name and where it was read from:
+// the override path, or "default".
+func (s *Set) Source(name string) (src, origin string, err error) {
+	if s.root != "" {
+		p := filepath.Join(s.root, filepath.FromSlash(Dir), name)
+		data, err := os.ReadFile(p) //nolint:gosec // G304: name is one of the template constants
+		switch {
+		case err == nil:
+			return string(data), p, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return "", "", err
+		}
+	}
+	src, err = Default(name)
+	return src, "default", err
+}
+
+// Render executes template name with data.
+func (s *Set) Render(name string, data any) (string, error) {
+	src, origin, err := s.Source(name)
+	if err != nil {
+		return "", err
+	}
+	out, err := render(name, src, data)
+	if err != nil {
+		return "", fmt.Errorf("template %s (%s): %w", name, origin, err)
+	}
+	return out, nil
+}
+
+// MustRender executes the embedded default template name with data. The
+// defaults are covered by tests, so an error is a programming error.
+func MustRender(name string, data any) string {
+	out, err := New("").Render(name, data)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func render(name, src string, data any) (string, error) {
+	fm := template.FuncMap{}
+	for _, n := range denied {
+		fm[n] = func(...any) (string, error) { return "", errors.New("not allowed") }
+	}
+	for n, f := range funcs {
+		fm[n] = f
+	}
+	t, err := template.New(name).Option("missingkey=error").Funcs(fm).Parse(src)
+	if err != nil {
+		return "", err
+	}
+	for _, tt := range t.Templates() {
+		if tt.Tree == nil {
+			continue
+		}
+		if n := deniedCall(tt.Tree.Root); n != nil {
+			loc, _ := tt.ErrorContext(n)
+			return "", fmt.Errorf("%s: %s is not allowed: templates must not read the clock, the environment or a random source", loc, n.Ident)
+		}
+	}
+
+	var first, second bytes.Buffer
+	if err := t.Execute(&first, data); err != nil {
+		return "", err
+	}
+	if err := t.Execute(&second, data); err != nil {
+		return "", err
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		return "", errors.New("output is not deterministic")
+	}
+	return first.String(), nil
+}
+
+// deniedCall returns the first call to a denied function in the tree.
+func deniedCall(n parse.Node) *parse.IdentifierNode {
+	var children []parse.Node
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			children = n.Nodes
+		}
+	case *parse.ActionNode:
+		children = []parse.Node{n.Pipe}
+	case *parse.PipeNode:
+		if n != nil {
+			for _, c := range n.Cmds {
+				children = append(children, c)
+			}
+		}
+	case *parse.CommandNode:
+		children = n.Args
+	case *parse.ChainNode:
+		children = []parse.Node{n.Node}
+	case *parse.IdentifierNode:
+		for _, d := range denied {
+			if n.Ident == d {
+				return n
+			}
+		}
+	case *parse.IfNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.RangeNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.WithNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.TemplateNode:
+		children = []parse.Node{n.Pipe}
+	}
+	for _, c := range children {
+		if id := deniedCall(c); id != nil {
+			return id
+		}
+	}
+	return nil
+}
+
+// Cell escapes text for a Markdown table cell.
+func Cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// Anchor returns the GitHub heading slug of heading.
+func Anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// trunc returns the first n bytes of s.
+func trunc(n int, s string) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// percent returns part as a percentage of total, 0 when total is 0.
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
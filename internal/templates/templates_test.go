@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOverride(t *testing.T, root, name, src string) {
+	t.Helper()
+	dir := filepath.Join(root, filepath.FromSlash(Dir))
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644))
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, []string{
+		Changelog,
+		ContextDependencies,
+		ContextFiles,
+		ContextIndex,
+		ContextModuleDependencies,
+		ContextModules,
+		PRSummary,
+		Roadmap,
+		RunSummary,
+		Site,
+	}, Names())
+}
+
+func TestRender_Override(t *testing.T) {
+	root := t.TempDir()
+	data := map[string]any{"Title": "a|b", "Hash": "0123456789abcdef"}
+
+	_, origin, err := New(root).Source(Changelog)
+	require.NoError(t, err)
+	assert.Equal(t, "default", origin)
+
+	writeOverride(t, root, Changelog, `{{ cell .Title }} {{ trunc 7 .Hash }} {{ anchor "Go Mod" }} {{ printf "%.1f" (percent 1 3) }}`)
+	out, err := New(root).Render(Changelog, data)
+	require.NoError(t, err)
+	assert.Equal(t, `a\|b 0123456 go-mod 33.3`, out)
+
+	// Defaults ignore the repository.
+	_, err = New("").Render("missing.md.tmpl", data)
+	assert.EqualError(t, err, `unknown template "missing.md.tmpl"`)
+}
+
+func TestRender_Determinism(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"clock", `{{ if false }}{{ now }}{{ end }}`, "now is not allowed"},
+		{"env in define", `{{ define "x" }}{{ env "HOME" }}{{ end }}ok`, "env is not allowed"},
+		{"undefined", `{{ hostname }}`, `function "hostname" not defined`},
+		{"missing key", `{{ .Nope }}`, `map has no entry for key "Nope"`},
+		{"stateful data", `{{ .Counter.Next }}`, "output is not deterministic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeOverride(t, root, Roadmap, tt.src)
+			_, err := New(root).Render(Roadmap, map[string]any{"Counter": &counter{}})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "template "+Roadmap+" ("+filepath.Join(root, filepath.FromSlash(Dir), Roadmap)+")")
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+type counter struct{ n int }
+
+func (c *counter) Next() int {
+	c.n++
+	return c.n
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package tokens
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/bartekus/cortex/internal/chunker"
+)
+
+// Levels a Report breaks the counts down by.
+const (
+	ByDir   = "dir"
+	ByFile  = "file"
+	ByChunk = "chunk"
+)
+
+// Report is the token count of a built context.
+type Report struct {
+	Tokenizer string `json:"tokenizer"`
+	Files     int    `json:"files"`
+	Chunks    int    `json:"chunks"`
+	Tokens    int    `json:"tokens"`
+	// By is the level of Usage: ByDir, ByFile or ByChunk.
+	By string `json:"by"`
+	// Usage is sorted by tokens, most first, then by path.
+	Usage []Usage `json:"usage"`
+	// Budgets are sorted by model.
+	Budgets []Fit `json:"budgets"`
+}
+
+// Usage is the token count of a directory, a file or a chunk.
+type Usage struct {
+	// Path is the directory ("." for the root), the file, or the file and
+	// line range of a chunk ("a.go:3-10").
+	Path   string `json:"path"`
+	ID     string `json:"id,omitempty"`
+	Files  int    `json:"files,omitempty"`
+	Chunks int    `json:"chunks"`
+	Tokens int    `json:"tokens"`
+}
+
+// Fit tells whether the context fits the context window of a model.
+type Fit struct {
+	Model  string `json:"model"`
+	Window int    `json:"window"`
+	Fits   bool   `json:"fits"`
+}
+
+// Measure counts the tokens of the chunks of res with c, broken down by
+// level, and checks the total against the context windows of models.
+func Measure(c Counter, res *chunker.Result, by string, models map[string]int) (*Report, error) {
+	if by != ByDir && by != ByFile && by != ByChunk {
+		return nil, fmt.Errorf("invalid level %q (must be %s, %s or %s)", by, ByDir, ByFile, ByChunk)
+	}
+	r := &Report{Tokenizer: c.Name(), Files: len(res.Manifest), Chunks: len(res.Chunks), By: by, Usage: []Usage{}, Budgets: []Fit{}}
+	byPath := make(map[string]*Usage)
+	add := func(key string, u Usage) {
+		if prev, ok := byPath[key]; ok {
+			prev.Files += u.Files
+			prev.Chunks += u.Chunks
+			prev.Tokens += u.Tokens
+			return
+		}
+		byPath[key] = &u
+	}
+
+	for _, e := range res.Manifest {
+		switch by {
+		case ByDir:
+			add(path.Dir(e.Path), Usage{Path: path.Dir(e.Path), Files: 1})
+		case ByFile:
+			add(e.Path, Usage{Path: e.Path, Files: 1})
+		}
+	}
+	for _, ch := range res.Chunks {
+		n := c.Count(ch.Content)
+		r.Tokens += n
+		switch by {
+		case ByDir:
+			add(path.Dir(ch.FilePath), Usage{Path: path.Dir(ch.FilePath), Chunks: 1, Tokens: n})
+		case ByFile:
+			add(ch.FilePath, Usage{Path: ch.FilePath, Chunks: 1, Tokens: n})
+		case ByChunk:
+			add(ch.ID, Usage{Path: fmt.Sprintf("%s:%d-%d", ch.FilePath, ch.StartLine, ch.EndLine), ID: ch.ID, Chunks: 1, Tokens: n})
+		}
+	}
+
+	for _, u := range byPath {
+		r.Usage = append(r.Usage, *u)
+	}
+	sort.Slice(r.Usage, func(i, j int) bool {
+		a, b := r.Usage[i], r.Usage[j]
+		if a.Tokens != b.Tokens {
+			return a.Tokens > b.Tokens
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.ID < b.ID
+	})
+
+	for model, window := range models {
+		r.Budgets = append(r.Budgets, Fit{Model: model, Window: window, Fits: r.Tokens <= window})
+	}
+	sort.Slice(r.Budgets, func(i, j int) bool { return r.Budgets[i].Model < r.Budgets[j].Model })
+	return r, nil
+}
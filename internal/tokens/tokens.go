@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package tokens counts the tokens of text as language models see it, so
+// the context can be checked against model context windows.
+//
+// Text is first split into pieces with the cl100k pre-tokenization rules.
+// With an encoding file in the tiktoken format (one "<base64 token> <rank>"
+// per line, such as cl100k_base.tiktoken) each piece is encoded by
+// byte-pair merging, which gives the exact counts of that encoding.
+// Without one, the tokens of a piece are estimated from its length.
+//
+// Feature: CLI_COMMAND_CONTEXT
+// Spec: spec/cli/context.md
+package tokens
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bartekus/cortex/internal/config"
+)
+
+// Counter counts tokens.
+type Counter interface {
+	// Name identifies the tokenizer: EstimateName or the encoding name.
+	Name() string
+	Count(text string) int
+}
+
+// New returns the counter context.tokens of repoRoot configures: BPE over
+// the encoding file, relative to repoRoot, or Estimate.
+func New(repoRoot string, cfg config.Tokens) (Counter, error) {
+	if cfg.Encoding == "" {
+		return Estimate{}, nil
+	}
+	path := cfg.Encoding
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, filepath.FromSlash(path))
+	}
+	return LoadBPE(path)
+}
+
+// EstimateName is the Name of Estimate.
+const EstimateName = "estimate"
+
+// Estimate approximates cl100k counts without an encoding file: a piece of
+// ASCII text takes a token per four bytes, any other character a token of
+// its own.
+type Estimate struct{}
+
+// Name returns EstimateName.
+func (Estimate) Name() string { return EstimateName }
+
+// Count estimates the tokens of text.
+func (Estimate) Count(text string) int {
+	n := 0
+	for _, piece := range Split(text) {
+		ascii, other := 0, 0
+		for _, r := range piece {
+			if r < utf8.RuneSelf {
+				ascii++
+			} else {
+				other++
+			}
+		}
+		n += (ascii+3)/4 + other
+	}
+	return n
+}
+
+// BPE is a byte-pair encoding read from a tiktoken file.
+type BPE struct {
+	name  string
+	ranks map[string]int
+}
+
+// LoadBPE reads the tiktoken encoding file at path. The encoding is named
+// after the file, without extension.
+func LoadBPE(path string) (*BPE, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: the encoding file is configured by the repository
+	if err != nil {
+		return nil, fmt.Errorf("reading encoding: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	b := &BPE{name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), ranks: map[string]int{}}
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		token, rank, ok := strings.Cut(strings.TrimSpace(sc.Text()), " ")
+		if !ok {
+			if token == "" {
+				continue
+			}
+			return nil, fmt.Errorf("%s:%d: want \"<base64 token> <rank>\"", path, line)
+		}
+		raw, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		r, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		b.ranks[string(raw)] = r
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading encoding: %w", err)
+	}
+	if len(b.ranks) == 0 {
+		return nil, fmt.Errorf("%s: no tokens", path)
+	}
+	return b, nil
+}
+
+// Name returns the encoding name.
+func (b *BPE) Name() string { return b.name }
+
+// Count encodes text and returns the number of tokens.
+func (b *BPE) Count(text string) int {
+	n := 0
+	for _, piece := range Split(text) {
+		n += b.encode(piece)
+	}
+	return n
+}
+
+// encode merges the bytes of piece, lowest rank first, and returns the
+// number of parts left. Bytes without a rank stay single tokens.
+func (b *BPE) encode(piece string) int {
+	if _, ok := b.ranks[piece]; ok {
+		return 1
+	}
+	// bounds[i] is the start of part i; the last entry ends the piece.
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+	for len(bounds) > 2 {
+		best, at := -1, -1
+		for i := 0; i+2 < len(bounds); i++ {
+			if r, ok := b.ranks[piece[bounds[i]:bounds[i+2]]]; ok && (best < 0 || r < best) {
+				best, at = r, i
+			}
+		}
+		if at < 0 {
+			break
+		}
+		bounds = append(bounds[:at+1], bounds[at+2:]...)
+	}
+	return len(bounds) - 1
+}
+
+// Split cuts text into the pieces of the cl100k pre-tokenization pattern:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}|
+//	 ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// The pieces concatenate to text.
+func Split(text string) []string {
+	var pieces []string
+	for text != "" {
+		n := contraction(text)
+		for _, match := range []func(string) int{letters, numbers, symbols, spaces} {
+			if n > 0 {
+				break
+			}
+			n = match(text)
+		}
+		if n == 0 {
+			_, n = utf8.DecodeRuneInString(text)
+		}
+		pieces = append(pieces, text[:n])
+		text = text[n:]
+	}
+	return pieces
+}
+
+// contraction matches (?i:'s|'t|'re|'ve|'m|'ll|'d).
+func contraction(s string) int {
+	if s[0] != '\'' {
+		return 0
+	}
+	rest := strings.ToLower(s[1:min(len(s), 3)])
+	for _, suffix := range []string{"s", "t", "re", "ve", "m", "ll", "d"} {
+		if strings.HasPrefix(rest, suffix) {
+			return 1 + len(suffix)
+		}
+	}
+	return 0
+}
+
+// letters matches [^\r\n\p{L}\p{N}]?\p{L}+.
+func letters(s string) int {
+	i := 0
+	if r, size := utf8.DecodeRuneInString(s); r != '\r' && r != '\n' && !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+		i = size
+	}
+	n := run(s[i:], unicode.IsLetter, -1)
+	if n == 0 {
+		return 0
+	}
+	return i + n
+}
+
+// numbers matches \p{N}{1,3}.
+func numbers(s string) int {
+	return run(s, unicode.IsNumber, 3)
+}
+
+// symbols matches  ?[^\s\p{L}\p{N}]+[\r\n]*.
+func symbols(s string) int {
+	i := 0
+	if s[0] == ' ' {
+		i = 1
+	}
+	n := run(s[i:], func(r rune) bool { return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r) }, -1)
+	if n == 0 {
+		return 0
+	}
+	i += n
+	return i + run(s[i:], func(r rune) bool { return r == '\r' || r == '\n' }, -1)
+}
+
+// spaces matches \s*[\r\n]+|\s+(?!\S)|\s+.
+func spaces(s string) int {
+	end := run(s, unicode.IsSpace, -1)
+	if end == 0 {
+		return 0
+	}
+	// \s*[\r\n]+ backtracks to the last line break of the run.
+	if last := strings.LastIndexAny(s[:end], "\r\n"); last >= 0 {
+		return last + 1
+	}
+	// \s+(?!\S) leaves the last space to the piece that follows.
+	if end < len(s) && end > 1 {
+		_, size := utf8.DecodeLastRuneInString(s[:end])
+		return end - size
+	}
+	return end
+}
+
+// run returns the byte length of the longest prefix of s of at most limit
+// runes (-1: no limit) that all satisfy f.
+func run(s string, f func(rune) bool, limit int) int {
+	n := 0
+	for count := 0; n < len(s) && count != limit; count++ {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !f(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
@@ -0,0 +1,143 @@
+package tokens
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/chunker"
+	"github.com/bartekus/cortex/internal/config"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello world", []string{"Hello", " world"}},
+		{"don't stop", []string{"don", "'t", " stop"}},
+		{"x := 12345", []string{"x", " :=", " ", "123", "45"}},
+		{"a  b", []string{"a", " ", " b"}},
+		{"func() {\n\treturn\n}\n", []string{"func", "()", " {\n", "\treturn", "\n", "}\n"}},
+		{"héllo wörld", []string{"héllo", " wörld"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := Split(tt.text)
+		assert.Equal(t, tt.want, got, "Split(%q)", tt.text)
+		assert.Equal(t, tt.text, strings.Join(got, ""))
+	}
+}
+
+func TestSplit_InvalidUTF8(t *testing.T) {
+	text := "a\xffb\xfe"
+	assert.Equal(t, text, strings.Join(Split(text), ""))
+}
+
+func TestEstimate(t *testing.T) {
+	e := Estimate{}
+	assert.Equal(t, EstimateName, e.Name())
+	assert.Equal(t, 0, e.Count(""))
+	// "Hello" 2, " world" 2
+	assert.Equal(t, 4, e.Count("Hello world"))
+	// "é" and "ö" count a token each
+	assert.Equal(t, 5, e.Count("héllo wörld"))
+}
+
+// writeEncoding writes a tiktoken file of the byte tokens and merges, in
+// rank order.
+func writeEncoding(t *testing.T, tokens ...string) string {
+	t.Helper()
+	var b strings.Builder
+	for rank, tok := range tokens {
+		_, _ = fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(tok)), rank)
+	}
+	path := filepath.Join(t.TempDir(), "tiny.tiktoken")
+	require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o600))
+	return path
+}
+
+func TestBPE(t *testing.T) {
+	path := writeEncoding(t, "a", "b", "c", " ", "ab", "abc", " a")
+	b, err := LoadBPE(path)
+	require.NoError(t, err)
+	assert.Equal(t, "tiny", b.Name())
+
+	assert.Equal(t, 1, b.Count("abc"))
+	// "ab" merges first, then "abc"; "d" has no rank.
+	assert.Equal(t, 2, b.Count("abcd"))
+	// "ab" outranks " a", so " abc" ends as " " and "abc".
+	assert.Equal(t, 2, b.Count(" abc"))
+	assert.Equal(t, 2, b.Count("cab"))
+}
+
+func TestLoadBPE_Errors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.tiktoken")
+	require.NoError(t, os.WriteFile(bad, []byte("YQ== x\n"), 0o600))
+	_, err := LoadBPE(bad)
+	assert.Error(t, err)
+
+	empty := filepath.Join(dir, "empty.tiktoken")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o600))
+	_, err = LoadBPE(empty)
+	assert.Error(t, err)
+
+	_, err = LoadBPE(filepath.Join(dir, "missing.tiktoken"))
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(t.TempDir(), config.Tokens{})
+	require.NoError(t, err)
+	assert.Equal(t, EstimateName, c.Name())
+
+	path := writeEncoding(t, "a")
+	c, err = New(filepath.Dir(path), config.Tokens{Encoding: filepath.Base(path)})
+	require.NoError(t, err)
+	assert.Equal(t, "tiny", c.Name())
+}
+
+func TestMeasure(t *testing.T) {
+	res := &chunker.Result{
+		Manifest: []chunker.ManifestEntry{{Path: "README.md"}, {Path: "cmd/main.go"}, {Path: "cmd/util.go"}},
+		Chunks: []chunker.Chunk{
+			{ID: "r1", FilePath: "README.md", StartLine: 1, EndLine: 2, Content: "Hello world"},
+			{ID: "m1", FilePath: "cmd/main.go", StartLine: 1, EndLine: 3, Content: "package main"},
+			{ID: "m2", FilePath: "cmd/main.go", StartLine: 5, EndLine: 9, Content: "func main() {}"},
+			{ID: "u1", FilePath: "cmd/util.go", StartLine: 1, EndLine: 1, Content: "x"},
+		},
+	}
+	models := map[string]int{"small": 10, "large": 1000}
+
+	r, err := Measure(Estimate{}, res, ByDir, models)
+	require.NoError(t, err)
+	assert.Equal(t, 3, r.Files)
+	assert.Equal(t, 4, r.Chunks)
+	assert.Equal(t, 14, r.Tokens)
+	assert.Equal(t, []Usage{
+		{Path: "cmd", Files: 2, Chunks: 3, Tokens: 10},
+		{Path: ".", Files: 1, Chunks: 1, Tokens: 4},
+	}, r.Usage)
+	assert.Equal(t, []Fit{{Model: "large", Window: 1000, Fits: true}, {Model: "small", Window: 10, Fits: false}}, r.Budgets)
+
+	r, err = Measure(Estimate{}, res, ByFile, nil)
+	require.NoError(t, err)
+	require.Len(t, r.Usage, 3)
+	assert.Equal(t, Usage{Path: "cmd/main.go", Files: 1, Chunks: 2, Tokens: 9}, r.Usage[0])
+	assert.Empty(t, r.Budgets)
+
+	r, err = Measure(Estimate{}, res, ByChunk, nil)
+	require.NoError(t, err)
+	require.Len(t, r.Usage, 4)
+	assert.Equal(t, Usage{Path: "cmd/main.go:5-9", ID: "m2", Chunks: 1, Tokens: 5}, r.Usage[0])
+
+	_, err = Measure(Estimate{}, res, "package", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package version reports the version of the running cortex binary.
+//
+// Feature: CLI_CONTRACT
+// Spec: spec/cli/contract.md
+package version
+
+import "os"
+
+// EnvVar overrides the version, e.g. in release builds and tests.
+const EnvVar = "CORTEX_VERSION"
+
+// Dev is the version of builds without EnvVar.
+const Dev = "0.0.0-dev"
+
+// String returns the version of cortex.
+func String() string {
+	if v := os.Getenv(EnvVar); v != "" {
+		return v
+	}
+	return Dev
+}
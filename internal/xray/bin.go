@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package xray
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ResolveBin locates the xray binary.
+// Resolution order: explicit path (e.g. --xray-bin), $XRAY_BIN,
+// then the release and debug cargo builds under repoRoot/rust/target.
+func ResolveBin(explicit, repoRoot string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+
+	if bin := os.Getenv("XRAY_BIN"); bin != "" {
+		return bin, nil
+	}
+
+	// Try release first, then debug
+	releasePath := filepath.Join(repoRoot, "rust/target/release/xray")
+	if _, err := os.Stat(releasePath); err == nil {
+		return releasePath, nil
+	}
+
+	debugPath := filepath.Join(repoRoot, "rust/target/debug/xray")
+	if _, err := os.Stat(debugPath); err == nil {
+		return debugPath, nil
+	}
+
+	return "", fmt.Errorf("xray binary not found. Build it with `cargo build` in rust/xray/ or specify --xray-bin")
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package xray
+
+import (
+	"io/fs"
+	"time"
+)
+
+// CachedFile is the per-file scan result kept between incremental scans.
+type CachedFile struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"` // Unix nanoseconds
+	Hash    string `json:"hash"`
+	LOC     int    `json:"loc"`
+}
+
+// ScanOptions configures ScanWith.
+type ScanOptions struct {
+	// Cache holds entries from a previous scan, keyed by repo-relative path.
+	Cache map[string]CachedFile
+	// TrustBefore guards against files modified within the timestamp
+	// granularity of the previous scan: entries whose modification time is not
+	// strictly before TrustBefore are always re-read.
+	TrustBefore time.Time
+}
+
+// lookup returns the cached node for rel if its size and mtime still match.
+func (o ScanOptions) lookup(rel string, info fs.FileInfo) (FileNode, bool) {
+	c, ok := o.Cache[rel]
+	if !ok || c.Hash == "" {
+		return FileNode{}, false
+	}
+	mtime := info.ModTime()
+	if c.Size != info.Size() || c.ModTime != mtime.UnixNano() || !mtime.Before(o.TrustBefore) {
+		return FileNode{}, false
+	}
+	return FileNode{
+		Path: rel,
+		Size: c.Size,
+		Hash: c.Hash,
+		Lang: DetectLanguage(rel),
+		LOC:  c.LOC,
+	}, true
+}
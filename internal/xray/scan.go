@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package xray
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bartekus/cortex/internal/canonjson"
+	"github.com/bartekus/cortex/internal/projection"
+)
+
+// Feature: XRAY_SCAN_POLICY
+// Spec: spec/xray/scan-policy.md
+
+// Scan engines selectable from the CLI.
+const (
+	EngineXray = "xray"
+	EngineGo   = "go"
+)
+
+// SchemaVersion is the index schema version written by the Rust XRAY binary.
+const SchemaVersion = "1.0.0"
+
+// LOCBigFileCapBytes is the size above which LOC is not computed (loc = 0).
+const LOCBigFileCapBytes = 2 * 1024 * 1024
+
+// ignoredDirs mirrors IGNORED_DIRS in rust/xray/src/traversal.rs.
+// Any entry with one of these names is skipped, file or directory.
+var ignoredDirs = map[string]bool{
+	".git":         true,
+	".bin":         true,
+	"node_modules": true,
+	"dist":         true,
+	"build":        true,
+	"out":          true,
+	"vendor":       true,
+	"target":       true,
+	".cache":       true,
+	".tmp":         true,
+	"coverage":     true,
+	".cortex":      true,
+}
+
+// Ignored reports whether the scan skips the repo-relative path rel: when
+// any of its elements is an ignored name.
+func Ignored(rel string) bool {
+	for _, name := range strings.Split(rel, "/") {
+		if ignoredDirs[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleFiles mirrors MODULE_FILES_LOOKUP; only matched at the scan root.
+var moduleFiles = map[string]bool{
+	"go.mod":       true,
+	"Cargo.toml":   true,
+	"package.json": true,
+	".git":         true,
+	"Makefile":     true,
+	"Dockerfile":   true,
+}
+
+// languages maps lower-cased extensions to canonical language names.
+var languages = map[string]string{
+	"go":   "Go",
+	"rs":   "Rust",
+	"md":   "Markdown",
+	"json": "JSON",
+	"js":   "JavaScript",
+	"ts":   "TypeScript",
+	"yaml": "YAML",
+	"yml":  "YAML",
+	"toml": "TOML",
+	"sh":   "Shell",
+	"bash": "Shell",
+	"html": "HTML",
+	"htm":  "HTML",
+	"css":  "CSS",
+	"sql":  "SQL",
+	"py":   "Python",
+	"java": "Java",
+	"c":    "C",
+	"h":    "C",
+	"cpp":  "C++",
+	"hpp":  "C++",
+	"cc":   "C++",
+	"cxx":  "C++",
+	"tf":   "Terraform",
+	"txt":  "Text",
+	"text": "Text",
+}
+
+// Scan walks target (relative to repoRoot) and builds an index that is
+// byte-for-byte identical to the one produced by `xray scan`.
+func Scan(repoRoot, target string) (*Index, error) {
+	index, _, err := ScanWith(repoRoot, target, ScanOptions{})
+	return index, err
+}
+
+// ScanWith is Scan with an incremental cache. Files whose size and
+// modification time match their cache entry are not read again. It returns
+// the cache entries for the files of the new index.
+func ScanWith(repoRoot, target string, opts ScanOptions) (*Index, map[string]CachedFile, error) {
+	base := filepath.Join(repoRoot, filepath.FromSlash(target))
+
+	index := &Index{
+		SchemaVersion: SchemaVersion,
+		Root:          filepath.Base(repoRoot),
+		Target:        target,
+		Files:         []FileNode{},
+		Languages:     map[string]int{},
+		TopDirs:       map[string]int{},
+		ModuleFiles:   []string{},
+	}
+
+	// .git is ignored by the walker, so it is detected explicitly.
+	if _, err := os.Lstat(filepath.Join(base, ".git")); err == nil {
+		index.ModuleFiles = append(index.ModuleFiles, ".git")
+	}
+
+	updated := make(map[string]CachedFile)
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("reading directory entry: %w", err)
+		}
+		if path != base && ignoredDirs[d.Name()] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Symlinks are not followed, but resolve to their target's type.
+		// Entries that cannot be stat'ed, such as dangling symlinks, are
+		// skipped.
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		node, ok := opts.lookup(rel, info)
+		if !ok {
+			node, err = scanFile(path, rel, info.Size())
+			if err != nil {
+				return err
+			}
+		}
+		updated[rel] = CachedFile{Size: node.Size, ModTime: info.ModTime().UnixNano(), Hash: node.Hash, LOC: node.LOC}
+		index.Files = append(index.Files, node)
+		index.Stats.TotalSize += node.Size
+
+		if node.Lang != "Unknown" {
+			index.Languages[node.Lang]++
+		}
+		index.TopDirs[topDir(rel)]++
+
+		if !strings.Contains(rel, "/") && moduleFiles[rel] {
+			index.ModuleFiles = append(index.ModuleFiles, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scanning %s: %w", target, err)
+	}
+
+	sort.Slice(index.Files, func(i, j int) bool { return index.Files[i].Path < index.Files[j].Path })
+	sort.Strings(index.ModuleFiles)
+	index.Stats.FileCount = len(index.Files)
+
+	digest, err := Digest(index)
+	if err != nil {
+		return nil, nil, err
+	}
+	index.Digest = digest
+
+	return index, updated, nil
+}
+
+// WriteIndex writes index as canonical JSON to outDir/index.json.
+func WriteIndex(index *Index, outDir string) (string, error) {
+	data, err := canonjson.Marshal(index)
+	if err != nil {
+		return "", fmt.Errorf("encoding index: %w", err)
+	}
+	outFile := filepath.Join(outDir, "index.json")
+	if err := projection.AtomicWrite(outFile, data); err != nil {
+		return "", err
+	}
+	return outFile, nil
+}
+
+func scanFile(path, rel string, size int64) (FileNode, error) {
+	f, err := os.Open(path) //nolint:gosec // path comes from the directory walk
+	if err != nil {
+		return FileNode{}, fmt.Errorf("opening %s: %w", rel, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	var content []byte
+	if size > 0 && size <= LOCBigFileCapBytes {
+		content, err = io.ReadAll(io.TeeReader(f, h))
+	} else {
+		_, err = io.Copy(h, f)
+	}
+	if err != nil {
+		return FileNode{}, fmt.Errorf("reading %s: %w", rel, err)
+	}
+
+	return FileNode{
+		Path: rel,
+		Size: size,
+		Hash: "sha256:" + hex.EncodeToString(h.Sum(nil)),
+		Lang: DetectLanguage(rel),
+		LOC:  CountLOC(content),
+	}, nil
+}
+
+// DetectLanguage returns the language for a path based on its file name or
+// extension, or "Unknown".
+func DetectLanguage(path string) string {
+	name := filepath.Base(filepath.FromSlash(path))
+	switch {
+	case strings.EqualFold(name, "Dockerfile"):
+		return "Dockerfile"
+	case strings.EqualFold(name, "Makefile"):
+		return "Makefile"
+	}
+
+	// Like Rust's Path::extension, a leading dot does not start an extension.
+	i := strings.LastIndexByte(name, '.')
+	if i <= 0 {
+		return "Unknown"
+	}
+	if lang, ok := languages[strings.ToLower(name[i+1:])]; ok {
+		return lang
+	}
+	return "Unknown"
+}
+
+// CountLOC counts logical lines (Rust's str::lines): a trailing newline does
+// not start a new line. Invalid UTF-8 counts as 0.
+func CountLOC(content []byte) int {
+	if len(content) == 0 || !utf8.Valid(content) {
+		return 0
+	}
+	n := strings.Count(string(content), "\n")
+	if content[len(content)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// Digest returns the hex SHA-256 of the canonical JSON of index with an empty
+// digest field. Files and module files must be strictly sorted.
+func Digest(index *Index) (string, error) {
+	if err := ValidateInvariants(index); err != nil {
+		return "", err
+	}
+	clone := *index
+	clone.Digest = ""
+	return canonjson.Sum256(clone)
+}
+
+// ValidateInvariants mirrors validate_invariants in rust/xray/src/canonical.rs:
+// files and module files strictly sorted, and stats, languages and topDirs
+// consistent with the file list.
+func ValidateInvariants(index *Index) error {
+	for i := 1; i < len(index.Files); i++ {
+		prev, cur := index.Files[i-1].Path, index.Files[i].Path
+		if prev == cur {
+			return fmt.Errorf("duplicate file path at index %d: %s", i-1, cur)
+		}
+		if prev > cur {
+			return fmt.Errorf("files not sorted at index %d: %s > %s", i-1, prev, cur)
+		}
+	}
+	for i := 1; i < len(index.ModuleFiles); i++ {
+		if index.ModuleFiles[i-1] >= index.ModuleFiles[i] {
+			return fmt.Errorf("moduleFiles not sorted or duplicated at index %d: %s", i-1, index.ModuleFiles[i])
+		}
+	}
+
+	if index.Stats.FileCount != len(index.Files) {
+		return fmt.Errorf("file count mismatch: files=%d vs stats.fileCount=%d", len(index.Files), index.Stats.FileCount)
+	}
+
+	var totalSize int64
+	langs := map[string]int{}
+	topDirs := map[string]int{}
+	for _, f := range index.Files {
+		totalSize += f.Size
+		if f.Lang != "Unknown" {
+			langs[f.Lang]++
+		}
+		topDirs[topDir(f.Path)]++
+	}
+	if totalSize != index.Stats.TotalSize {
+		return fmt.Errorf("total size mismatch: computed=%d vs stats.totalSize=%d", totalSize, index.Stats.TotalSize)
+	}
+	if !equalCounts(langs, index.Languages) {
+		return fmt.Errorf("languages aggregate mismatch: computed %v, stored %v", langs, index.Languages)
+	}
+	if !equalCounts(topDirs, index.TopDirs) {
+		return fmt.Errorf("topDirs aggregate mismatch: computed %v, stored %v", topDirs, index.TopDirs)
+	}
+	return nil
+}
+
+// topDir returns the first path segment, or "." for files at the root.
+func topDir(path string) string {
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i]
+	}
+	return "."
+}
+
+func equalCounts(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
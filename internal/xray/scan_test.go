@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package xray
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountLOC(t *testing.T) {
+	assert.Equal(t, 0, CountLOC(nil))
+	assert.Equal(t, 1, CountLOC([]byte("a")))
+	assert.Equal(t, 1, CountLOC([]byte("a\n")))
+	assert.Equal(t, 2, CountLOC([]byte("a\nb")))
+	assert.Equal(t, 2, CountLOC([]byte("a\r\nb\r\n")))
+	assert.Equal(t, 0, CountLOC([]byte{0, 159, 146, 150}))
+}
+
+func TestDetectLanguage(t *testing.T) {
+	cases := map[string]string{
+		"main.go":         "Go",
+		"lib/mod.RS":      "Rust",
+		"ci.yml":          "YAML",
+		"src/x.hpp":       "C++",
+		"Dockerfile":      "Dockerfile",
+		"sub/makefile":    "Makefile",
+		".gitignore":      "Unknown",
+		"LICENSE":         "Unknown",
+		"archive.tar.bz2": "Unknown",
+	}
+	for path, want := range cases {
+		assert.Equal(t, want, DetectLanguage(path), path)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	files := map[string]string{
+		"go.mod":                    "module x\n",
+		"Makefile":                  "all:\n\techo hi\n",
+		"README":                    "readme",
+		"cmd/main.go":               "package main\n\nfunc main() {}",
+		"cmd/sub/go.mod":            "module y\n",
+		"node_modules/pkg/index.js": "ignored",
+		"build":                     "ignored file named like an ignored dir",
+		".git/HEAD":                 "ref: refs/heads/main\n",
+		".cortex/data/index.json":   "{}",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	index, err := Scan(root, ".")
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(index.Files))
+	for _, f := range index.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"Makefile", "README", "cmd/main.go", "cmd/sub/go.mod", "go.mod"}, paths)
+	assert.Equal(t, []string{".git", "Makefile", "go.mod"}, index.ModuleFiles)
+	assert.Equal(t, map[string]int{"Go": 1, "Makefile": 1}, index.Languages)
+	assert.Equal(t, map[string]int{".": 3, "cmd": 2}, index.TopDirs)
+	assert.Equal(t, "repo", index.Root)
+	assert.Equal(t, 5, index.Stats.FileCount)
+
+	main := index.Files[2]
+	assert.Equal(t, 3, main.LOC)
+	assert.Equal(t, int64(28), main.Size)
+	assert.Equal(t, "sha256:c2932f891c62e0b9b44aefea81cab9a32ee2d2d6395382318ed771d94cb3144c", main.Hash)
+
+	require.NoError(t, ValidateInvariants(index))
+	again, err := Scan(root, ".")
+	require.NoError(t, err)
+	assert.Equal(t, index.Digest, again.Digest)
+}
+
+func TestScan_SkipsDanglingSymlink(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("a\n"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "dangling")))
+
+	index, err := Scan(root, ".")
+	require.NoError(t, err)
+	require.Len(t, index.Files, 1)
+	assert.Equal(t, "a.txt", index.Files[0].Path)
+}
+
+func TestDigest_RefusesInvalidIndex(t *testing.T) {
+	index := &Index{
+		Files:     []FileNode{{Path: "b", Lang: "Unknown"}, {Path: "a", Lang: "Unknown"}},
+		Stats:     RepoStats{FileCount: 2},
+		Languages: map[string]int{},
+		TopDirs:   map[string]int{".": 2},
+	}
+	_, err := Digest(index)
+	assert.ErrorContains(t, err, "files not sorted")
+
+	index.Files[0], index.Files[1] = index.Files[1], index.Files[0]
+	index.Stats.FileCount = 3
+	_, err = Digest(index)
+	assert.ErrorContains(t, err, "file count mismatch")
+
+	index.Stats.FileCount = 2
+	_, err = Digest(index)
+	assert.NoError(t, err)
+}
+
+func TestDigest_KnownValue(t *testing.T) {
+	// Computed independently as sha256 of the canonical (sorted-key, compact) JSON.
+	index := &Index{
+		SchemaVersion: SchemaVersion,
+		Root:          "repo",
+		Target:        ".",
+		Files:         []FileNode{{Path: "a.go", Size: 1, Hash: "sha256:x", Lang: "Go", LOC: 1}},
+		Languages:     map[string]int{"Go": 1},
+		TopDirs:       map[string]int{".": 1},
+		ModuleFiles:   []string{},
+		Stats:         RepoStats{FileCount: 1, TotalSize: 1},
+	}
+	digest, err := Digest(index)
+	require.NoError(t, err)
+	assert.Equal(t, "db80bf53f7a557b1d28ebae9764b0e9304d0ab2d46a332e3747f4109f3ce2336", digest)
+}
+
+func TestScanWith_Cache(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\n"), 0o644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	full, files, err := ScanWith(root, ".", ScanOptions{})
+	require.NoError(t, err)
+	require.Contains(t, files, "a.txt")
+
+	// A trusted entry with matching size and mtime is reused without reading
+	// the file, so a planted hash shows up in the index.
+	planted := files["a.txt"]
+	planted.Hash = "sha256:cached"
+	opts := ScanOptions{Cache: map[string]CachedFile{"a.txt": planted}, TrustBefore: time.Now()}
+	index, _, err := ScanWith(root, ".", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:cached", index.Files[0].Hash)
+
+	// Entries modified at or after TrustBefore are re-read.
+	opts.TrustBefore = old
+	index, _, err = ScanWith(root, ".", opts)
+	require.NoError(t, err)
+	assert.Equal(t, full.Files[0].Hash, index.Files[0].Hash)
+
+	// A size change invalidates the entry.
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o644))
+	require.NoError(t, os.Chtimes(path, old, old))
+	opts.TrustBefore = time.Now()
+	index, _, err = ScanWith(root, ".", opts)
+	require.NoError(t, err)
+	assert.NotEqual(t, "sha256:cached", index.Files[0].Hash)
+	assert.Equal(t, 3, index.Files[0].LOC)
+}
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package cortexrun runs Cortex skills in-process, for programs such as bots
+// and servers that embed Cortex instead of shelling out to `cortex run`.
+//
+// A run behaves like `cortex run all` (or `cortex run <skill>...` with
+// WithSkills): it honours .cortex/config.yaml, expands per-module skills and
+// records its state and history in the state directory, so `cortex run
+// report` and `cortex run history` see it.
+//
+//	report, err := cortexrun.Run(ctx, repoRoot, cortexrun.WithSkills("test:go"))
+//	if err != nil {
+//		return err // the run could not start
+//	}
+//	if !report.Passed() {
+//		for _, r := range report.Failed() {
+//			log.Printf("%s: %s", r.Skill, r.Note)
+//		}
+//	}
+package cortexrun
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/bartekus/cortex/internal/config"
+	"github.com/bartekus/cortex/internal/findings"
+	"github.com/bartekus/cortex/internal/fingerprint"
+	"github.com/bartekus/cortex/internal/git"
+	"github.com/bartekus/cortex/internal/runner"
+	"github.com/bartekus/cortex/internal/scanner"
+	"github.com/bartekus/cortex/internal/skills"
+)
+
+// Errors returned by Run before any skill runs.
+var (
+	// ErrConfig reports an invalid .cortex/config.yaml or Go workspace.
+	ErrConfig = errors.New("invalid configuration")
+	// ErrUnknownSkill reports a WithSkills ID that selects no skill.
+	ErrUnknownSkill = errors.New("unknown skill")
+	// ErrStateLocked reports that another run, in this or another process,
+	// holds the state directory. WithForce runs anyway.
+	ErrStateLocked = errors.New("state directory locked")
+)
+
+// Skill statuses.
+const (
+	StatusPass    = string(runner.StatusPass)
+	StatusFail    = string(runner.StatusFail)
+	StatusSkip    = string(runner.StatusSkip)
+	StatusTimeout = string(runner.StatusTimeout)
+)
+
+// SkillResult is the outcome of one skill.
+type SkillResult struct {
+	Skill string `json:"skill"`
+	// Status is one of StatusPass, StatusFail, StatusSkip or StatusTimeout.
+	Status string `json:"status"`
+	// ExitCode follows the CLI contract taxonomy (0 pass, 1 violations,
+	// 2 config or missing tool, 3 promoted warnings, 4 execution error).
+	ExitCode int    `json:"exit_code"`
+	Note     string `json:"note,omitempty"`
+	// Attempts is how many times the skill ran when it was retried.
+	Attempts int  `json:"attempts,omitempty"`
+	Flaky    bool `json:"flaky,omitempty"`
+	// Duration is the wall time of the skill, retries included.
+	Duration time.Duration `json:"duration"`
+}
+
+// Failed reports whether the skill failed or timed out.
+func (r SkillResult) Failed() bool {
+	return runner.SkillStatus(r.Status).Failed()
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	// Commit is the HEAD the run checked; empty outside git.
+	Commit string `json:"commit,omitempty"`
+	// Results are in execution order.
+	Results []SkillResult `json:"results"`
+	// ExitCode is what `cortex run` would exit with: 0, or the highest exit
+	// code among the failed skills.
+	ExitCode int `json:"exit_code"`
+}
+
+// Passed reports whether no skill failed.
+func (r *Report) Passed() bool {
+	return r.ExitCode == runner.ExitOK
+}
+
+// Failed returns the results of the failed skills.
+func (r *Report) Failed() []SkillResult {
+	var out []SkillResult
+	for _, res := range r.Results {
+		if res.Failed() {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Result returns the result of the skill with the given ID.
+func (r *Report) Result(id string) (SkillResult, bool) {
+	for _, res := range r.Results {
+		if res.Skill == id {
+			return res, true
+		}
+	}
+	return SkillResult{}, false
+}
+
+type options struct {
+	skills        []string
+	out           io.Writer
+	stateDir      string
+	failOnWarning bool
+	files         []string
+	force         bool
+	log           *slog.Logger
+}
+
+// Option configures Run.
+type Option func(*options)
+
+// WithSkills runs only the given skill IDs, in order, instead of every
+// registered skill. IDs follow `cortex run <skill>`: "test:go" selects all
+// module variants, "test:go@." the root module only.
+func WithSkills(ids ...string) Option {
+	return func(o *options) { o.skills = append(o.skills, ids...) }
+}
+
+// WithOutput writes the progress log of `cortex run` to w. By default it is
+// discarded.
+func WithOutput(w io.Writer) Option {
+	return func(o *options) { o.out = w }
+}
+
+// WithStateDir records run state in dir, relative to the repository root
+// unless absolute. The default is .cortex/run, shared with the CLI.
+func WithStateDir(dir string) Option {
+	return func(o *options) { o.stateDir = dir }
+}
+
+// WithFailOnWarning fails skills that only found warnings (exit code 3),
+// like --fail-on-warning.
+func WithFailOnWarning(fail bool) Option {
+	return func(o *options) { o.failOnWarning = fail }
+}
+
+// WithLogger sends the structured diagnostics of the runner and skills to
+// l. By default they are discarded.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) { o.log = l }
+}
+
+// WithFiles restricts skills that support it to the given repo-relative
+// files, like --files0.
+func WithFiles(files ...string) Option {
+	return func(o *options) { o.files = append(o.files, files...) }
+}
+
+// WithForce runs even when another run holds the state directory, like
+// --force.
+func WithForce(force bool) Option {
+	return func(o *options) { o.force = force }
+}
+
+// Run executes skills in repoRoot and returns their results. Failing skills
+// are reported in the Report, not as an error; the error is non-nil only
+// when the run could not start or its state could not be written.
+func Run(ctx context.Context, repoRoot string, opts ...Option) (*Report, error) {
+	o := options{out: io.Discard, stateDir: filepath.Join(".cortex", "run")}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	root, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("resolving repo root: %w", err)
+	}
+	stateDir := o.stateDir
+	if !filepath.IsAbs(stateDir) {
+		stateDir = filepath.Join(root, stateDir)
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConfig, err)
+	}
+	scn := scanner.New(root)
+	mods, err := scn.Modules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: discovering Go modules: %v", ErrConfig, err)
+	}
+	tracked, err := scn.TrackedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nested, err := config.LoadNested(root, tracked)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConfig, err)
+	}
+
+	// Outside a git checkout history records simply carry no commit or
+	// fingerprint.
+	commit, _ := git.RevParse(ctx, root, "HEAD")
+	var fpDigest string
+	if fp, err := fingerprint.Compute(ctx, root); err == nil {
+		fpDigest = fp.Digest()
+	}
+
+	r := runner.NewRunner(skills.ForModules(skills.Registry, mods), runner.NewStateStore(stateDir), &runner.Deps{
+		RepoRoot:      root,
+		StateDir:      stateDir,
+		Scanner:       scn,
+		FailOnWarning: o.failOnWarning,
+		Commit:        commit,
+		Fingerprint:   fpDigest,
+		TargetFiles:   o.files,
+		Log:           o.log,
+	})
+	r.SetOutput(o.out)
+	if err := r.ApplyConfig(cfg.Skills); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConfig, err)
+	}
+	gen := findings.NewGeneratedFilter(root, scanner.NewGenerated(root, cfg.Files.Generated))
+	sup := findings.NewSuppressor(root, time.Now())
+	overrides := findings.NewOverrides(root, cfg.Rules, nested, o.failOnWarning)
+	r.SetResultFilter(func(res runner.SkillResult) runner.SkillResult { return overrides.Apply(sup.Apply(gen.Apply(res))) })
+	r.SetForce(o.force)
+
+	if len(o.skills) > 0 {
+		err = r.RunList(ctx, o.skills)
+	} else {
+		err = r.RunAll(ctx)
+	}
+	if errors.Is(err, runner.ErrSkillNotFound) {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownSkill, err)
+	}
+	var lockErr *runner.LockedError
+	if errors.As(err, &lockErr) {
+		return nil, fmt.Errorf("%w: %v", ErrStateLocked, err)
+	}
+	var runErr *runner.RunError
+	if err != nil && !errors.As(err, &runErr) {
+		return nil, err
+	}
+
+	report := &Report{Commit: commit, Results: []SkillResult{}}
+	if runErr != nil {
+		report.ExitCode = runErr.ExitCode()
+	}
+	for _, res := range r.Results() {
+		report.Results = append(report.Results, SkillResult{
+			Skill:    res.Skill,
+			Status:   string(res.Status),
+			ExitCode: res.ExitCode,
+			Note:     res.Note,
+			Attempts: res.Attempts,
+			Flaky:    res.Flaky,
+			Duration: time.Duration(res.DurationMS) * time.Millisecond,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package cortexrun
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bartekus/cortex/internal/git"
+)
+
+// Feature: CLI_COMMAND_RUN
+// Spec: spec/cli/run.md
+
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}} {
+		_, err := git.Run(context.Background(), dir, args...)
+		require.NoError(t, err)
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	dir := gitRepo(t, map[string]string{
+		"docs/Untitled.md": "# draft\n",
+		"docs/ok.md":       "# ok\n",
+	})
+	var out bytes.Buffer
+	stateDir := t.TempDir()
+
+	report, err := Run(context.Background(), dir,
+		WithSkills("docs:doc-patterns", "docs:validate-spec"),
+		WithOutput(&out),
+		WithStateDir(stateDir))
+	require.NoError(t, err)
+
+	assert.False(t, report.Passed())
+	assert.Equal(t, 1, report.ExitCode)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "docs:doc-patterns", report.Results[0].Skill)
+	assert.Equal(t, StatusFail, report.Results[0].Status)
+	assert.Contains(t, report.Results[0].Note, "docs/Untitled.md: filename contains 'untitled'")
+	assert.Equal(t, []SkillResult{report.Results[0]}, report.Failed())
+
+	res, ok := report.Result("docs:validate-spec")
+	require.True(t, ok)
+	assert.Equal(t, StatusSkip, res.Status)
+
+	assert.Contains(t, out.String(), "FAIL: docs:doc-patterns (exit 1)")
+	assert.FileExists(t, filepath.Join(stateDir, "last-run.json"))
+	assert.NoDirExists(t, filepath.Join(dir, ".cortex"))
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := gitRepo(t, map[string]string{"README.md": "# x\n"})
+	ctx := context.Background()
+
+	_, err := Run(ctx, dir, WithSkills("no:such-skill"), WithStateDir(t.TempDir()))
+	assert.ErrorIs(t, err, ErrUnknownSkill)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".cortex"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".cortex", "config.yaml"), []byte("skills:\n  retry:\n    \"*\": {count: -1}\n"), 0o644))
+	_, err = Run(ctx, dir, WithStateDir(t.TempDir()))
+	assert.ErrorIs(t, err, ErrConfig)
+	assert.ErrorContains(t, err, "skills.retry.*: count and backoff must not be negative")
+}
@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Diff output formats accepted by the drift commands.
+const (
+	DiffFormatText = "text"
+	DiffFormatJSON = "json"
+)
+
+// DefaultDiffContext is the number of unchanged lines shown around each change.
+const DefaultDiffContext = 3
+
+// DefaultDiffMaxHunks caps how many hunks are rendered before truncating.
+const DefaultDiffMaxHunks = 20
+
+// DiffOptions controls how a unified diff is computed and rendered.
+type DiffOptions struct {
+	// Context is the number of unchanged lines kept around each change.
+	Context int
+	// MaxHunks limits the number of hunks in the result (0 means unlimited).
+	MaxHunks int
+}
+
+// DefaultDiffOptions returns the options used by the drift commands.
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{Context: DefaultDiffContext, MaxHunks: DefaultDiffMaxHunks}
+}
+
+// DiffLine is a single line of a hunk.
+// Op is one of " " (context), "-" (only in old) or "+" (only in new).
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Hunk is a contiguous region of changes with surrounding context.
+// Line numbers are 1-based, matching unified diff headers.
+type Hunk struct {
+	OldStart int        `json:"old_start"`
+	OldLines int        `json:"old_lines"`
+	NewStart int        `json:"new_start"`
+	NewLines int        `json:"new_lines"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+// Diff is a line-based unified diff between two texts.
+type Diff struct {
+	OldName   string `json:"old_name"`
+	NewName   string `json:"new_name"`
+	Hunks     []Hunk `json:"hunks"`
+	Truncated bool   `json:"truncated"`
+	// TotalHunks is the number of hunks before MaxHunks was applied.
+	TotalHunks int `json:"total_hunks"`
+}
+
+// Empty reports whether the two inputs were identical.
+func (d Diff) Empty() bool {
+	return d.TotalHunks == 0
+}
+
+// String renders the diff in unified format.
+func (d Diff) String() string {
+	if d.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n", d.OldName)
+	fmt.Fprintf(&b, "+++ %s\n", d.NewName)
+	for _, h := range d.Hunks {
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+		for _, l := range h.Lines {
+			b.WriteString(l.Op)
+			b.WriteString(l.Text)
+			b.WriteByte('\n')
+		}
+	}
+	if d.Truncated {
+		fmt.Fprintf(&b, "... (%d more hunks not shown)\n", d.TotalHunks-len(d.Hunks))
+	}
+	return b.String()
+}
+
+// JSON renders the diff as indented JSON for tooling.
+func (d Diff) JSON() ([]byte, error) {
+	if d.Hunks == nil {
+		d.Hunks = []Hunk{}
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Render formats the diff according to format (text or json).
+func (d Diff) Render(format string) (string, error) {
+	switch format {
+	case "", DiffFormatText:
+		return d.String(), nil
+	case DiffFormatJSON:
+		data, err := d.JSON()
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal diff: %w", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported diff format %q (expected %s or %s)", format, DiffFormatText, DiffFormatJSON)
+	}
+}
+
+// ValidateDiffFormat checks that format is a supported --diff-format value.
+func ValidateDiffFormat(format string) error {
+	_, err := Diff{}.Render(format)
+	return err
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// UnifiedDiff computes a line-based unified diff from oldText to newText.
+func UnifiedDiff(oldName, newName, oldText, newText string, opts DiffOptions) Diff {
+	d := Diff{OldName: oldName, NewName: newName}
+	if oldText == newText {
+		return d
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+	hunks := buildHunks(ops, opts.Context)
+
+	d.TotalHunks = len(hunks)
+	if opts.MaxHunks > 0 && len(hunks) > opts.MaxHunks {
+		hunks = hunks[:opts.MaxHunks]
+		d.Truncated = true
+	}
+	d.Hunks = hunks
+	return d
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffOp is one edit step with the 0-based line indexes it consumes.
+type diffOp struct {
+	op     string
+	text   string
+	oldIdx int
+	newIdx int
+}
+
+// diffLines returns the edit script turning a into b, based on the
+// longest common subsequence. Common prefix and suffix are trimmed first
+// so typical drift (a few changed lines) stays cheap.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{op: " ", text: a[i], oldIdx: i, newIdx: i})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+
+	// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{op: " ", text: midA[i], oldIdx: prefix + i, newIdx: prefix + j})
+			i++
+			j++
+		case j >= len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{op: "-", text: midA[i], oldIdx: prefix + i, newIdx: prefix + j})
+			i++
+		default:
+			ops = append(ops, diffOp{op: "+", text: midB[j], oldIdx: prefix + i, newIdx: prefix + j})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		oi := len(a) - suffix + k
+		ni := len(b) - suffix + k
+		ops = append(ops, diffOp{op: " ", text: a[oi], oldIdx: oi, newIdx: ni})
+	}
+	return ops
+}
+
+// buildHunks groups changes that are within 2*context lines of each other.
+func buildHunks(ops []diffOp, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var hunks []Hunk
+	i := 0
+	for i < len(ops) {
+		// Find the next change.
+		for i < len(ops) && ops[i].op == " " {
+			i++
+		}
+		if i >= len(ops) {
+			break
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend while the gap between changes fits inside shared context.
+		end := i
+		for end < len(ops) {
+			if ops[end].op != " " {
+				end++
+				continue
+			}
+			gap := end
+			for gap < len(ops) && ops[gap].op == " " {
+				gap++
+			}
+			if gap < len(ops) && gap-end <= 2*context {
+				end = gap
+				continue
+			}
+			end += context
+			if end > len(ops) {
+				end = len(ops)
+			}
+			break
+		}
+
+		hunks = append(hunks, newHunk(ops[start:end]))
+		i = end
+	}
+	return hunks
+}
+
+func newHunk(ops []diffOp) Hunk {
+	h := Hunk{
+		OldStart: ops[0].oldIdx + 1,
+		NewStart: ops[0].newIdx + 1,
+		Lines:    make([]DiffLine, 0, len(ops)),
+	}
+	for _, op := range ops {
+		switch op.op {
+		case " ":
+			h.OldLines++
+			h.NewLines++
+		case "-":
+			h.OldLines++
+		case "+":
+			h.NewLines++
+		}
+		h.Lines = append(h.Lines, DiffLine{Op: op.op, Text: op.text})
+	}
+	// Unified diff convention: an empty side points at the line before.
+	if h.OldLines == 0 {
+		h.OldStart--
+	}
+	if h.NewLines == 0 {
+		h.NewStart--
+	}
+	return h
+}
+
+// DriftError reports that generated content no longer matches a committed fixture.
+// It carries the structured diff so callers can render it in different formats.
+type DriftError struct {
+	Message string
+	Diff    Diff
+}
+
+func (e *DriftError) Error() string {
+	if e.Diff.Empty() {
+		return e.Message
+	}
+	return e.Message + "\n\n" + e.Diff.String()
+}
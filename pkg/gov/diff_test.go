@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff_Identical(t *testing.T) {
+	d := UnifiedDiff("a", "b", "x\ny\n", "x\ny\n", DefaultDiffOptions())
+	assert.True(t, d.Empty())
+	assert.Equal(t, "", d.String())
+}
+
+func TestUnifiedDiff_SingleChange(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	updated := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+
+	d := UnifiedDiff("old", "new", old, updated, DiffOptions{Context: 2})
+
+	want := `--- old
++++ new
+@@ -3,5 +3,5 @@
+ 3
+ 4
+-5
++five
+ 6
+ 7
+`
+	assert.Equal(t, want, d.String())
+}
+
+func TestUnifiedDiff_InsertAndDelete(t *testing.T) {
+	d := UnifiedDiff("old", "new", "a\nb\n", "a\nb\nc\n", DiffOptions{Context: 1})
+	assert.Equal(t, "--- old\n+++ new\n@@ -2 +2,2 @@\n b\n+c\n", d.String())
+
+	d = UnifiedDiff("old", "new", "a\n", "", DiffOptions{})
+	assert.Equal(t, "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n", d.String())
+}
+
+func TestUnifiedDiff_MergesNearbyHunks(t *testing.T) {
+	old := "a\nb\nc\nd\ne\n"
+	updated := "A\nb\nc\nd\nE\n"
+
+	merged := UnifiedDiff("old", "new", old, updated, DiffOptions{Context: 2})
+	require.Len(t, merged.Hunks, 1)
+
+	split := UnifiedDiff("old", "new", old, updated, DiffOptions{Context: 1})
+	require.Len(t, split.Hunks, 2)
+}
+
+func TestUnifiedDiff_MaxHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 50; i++ {
+		oldLines = append(oldLines, "same", "same", "same", "old")
+		newLines = append(newLines, "same", "same", "same", "new")
+	}
+
+	d := UnifiedDiff("old", "new", strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"), DiffOptions{Context: 0, MaxHunks: 5})
+	assert.True(t, d.Truncated)
+	assert.Len(t, d.Hunks, 5)
+	assert.Equal(t, 50, d.TotalHunks)
+	assert.Contains(t, d.String(), "... (45 more hunks not shown)")
+}
+
+func TestDiff_RenderJSON(t *testing.T) {
+	d := UnifiedDiff("old", "new", "a\n", "b\n", DefaultDiffOptions())
+
+	out, err := d.Render(DiffFormatJSON)
+	require.NoError(t, err)
+
+	var decoded Diff
+	require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	assert.Equal(t, d, decoded)
+
+	_, err = d.Render("yaml")
+	assert.Error(t, err)
+}
+
+func TestCompareHelp_ReturnsDriftError(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "help.txt")
+	require.NoError(t, os.WriteFile(fixture, []byte("Usage:\n  cortex [command]\nFlags:\n  -h, --help\n"), 0o600))
+
+	require.NoError(t, CompareHelp("Usage:\ncortex [command]\nFlags:\n-h, --help\n", fixture))
+
+	err := CompareHelp("Usage:\ncortex [command]\nFlags:\n-h, --help\n-v, --verbose\n", fixture)
+	require.Error(t, err)
+
+	var drift *DriftError
+	require.ErrorAs(t, err, &drift)
+	assert.Contains(t, err.Error(), "+-v, --verbose")
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GeneratedArtifact is a committed generated file or directory together with
+// the function that re-renders it.
+type GeneratedArtifact struct {
+	// Path is the repo-relative location of the committed artifact.
+	Path string
+	// Dir marks artifacts that are a directory of generated files.
+	Dir bool
+	// Render writes a fresh copy of the artifact to dst.
+	// For Dir artifacts dst is a directory, otherwise it is a file path.
+	Render func(dst string) error
+}
+
+// GeneratedDrift is the difference between one committed generated file and
+// its freshly rendered counterpart.
+type GeneratedDrift struct {
+	Path string `json:"path"`
+	Diff Diff   `json:"diff"`
+}
+
+// GeneratedDriftError lists every generated file that no longer matches
+// what the generators produce.
+type GeneratedDriftError struct {
+	Drifts []GeneratedDrift
+}
+
+func (e *GeneratedDriftError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "generated docs drift detected in %d file(s); regenerate and commit them:", len(e.Drifts))
+	for _, d := range e.Drifts {
+		b.WriteString("\n\n")
+		b.WriteString(d.Diff.String())
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// CheckGeneratedDrift re-renders each artifact into a temporary directory and
+// diffs the result against the committed copy under repoRoot.
+// Artifacts that are not present in the repository are skipped and returned
+// so callers can report them.
+func CheckGeneratedDrift(repoRoot string, artifacts []GeneratedArtifact, opts DiffOptions) (skipped []string, err error) {
+	tmpDir, err := os.MkdirTemp("", "cortex-drift-generated-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	var drifts []GeneratedDrift
+	for i, a := range artifacts {
+		committed := filepath.Join(repoRoot, filepath.FromSlash(a.Path))
+		if _, statErr := os.Stat(committed); os.IsNotExist(statErr) {
+			skipped = append(skipped, a.Path)
+			continue
+		}
+
+		rendered := filepath.Join(tmpDir, fmt.Sprintf("%d", i), filepath.Base(committed))
+		if err := os.MkdirAll(filepath.Dir(rendered), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to prepare render dir for %s: %w", a.Path, err)
+		}
+		if err := a.Render(rendered); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", a.Path, err)
+		}
+
+		var found []GeneratedDrift
+		if a.Dir {
+			found, err = diffGeneratedDir(a.Path, committed, rendered, opts)
+		} else {
+			found, err = diffGeneratedFile(a.Path, committed, rendered, opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, found...)
+	}
+
+	if len(drifts) > 0 {
+		return skipped, &GeneratedDriftError{Drifts: drifts}
+	}
+	return skipped, nil
+}
+
+func diffGeneratedFile(relPath, committed, rendered string, opts DiffOptions) ([]GeneratedDrift, error) {
+	oldText, err := readOptional(committed)
+	if err != nil {
+		return nil, err
+	}
+	newText, err := readOptional(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	d := UnifiedDiff("a/"+relPath, "b/"+relPath, oldText, newText, opts)
+	if d.Empty() {
+		return nil, nil
+	}
+	return []GeneratedDrift{{Path: relPath, Diff: d}}, nil
+}
+
+func diffGeneratedDir(relPath, committed, rendered string, opts DiffOptions) ([]GeneratedDrift, error) {
+	names := make(map[string]bool)
+	for _, dir := range []string{committed, rendered} {
+		files, err := listFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			names[f] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var drifts []GeneratedDrift
+	for _, name := range sorted {
+		found, err := diffGeneratedFile(
+			relPath+"/"+name,
+			filepath.Join(committed, filepath.FromSlash(name)),
+			filepath.Join(rendered, filepath.FromSlash(name)),
+			opts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, found...)
+	}
+	return drifts, nil
+}
+
+// listFiles returns slash-separated paths of all regular files under dir.
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// readOptional reads a file, treating a missing file as empty.
+func readOptional(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // paths are derived from the artifact list
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestCheckGeneratedDrift(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "docs/__generated__/a.md"), "# A\n")
+	writeFile(t, filepath.Join(root, "docs/__generated__/ctx/index.md"), "# Index\nold\n")
+	writeFile(t, filepath.Join(root, "docs/__generated__/ctx/stale.md"), "stale\n")
+
+	artifacts := []GeneratedArtifact{
+		{
+			Path:   "docs/__generated__/a.md",
+			Render: func(dst string) error { return os.WriteFile(dst, []byte("# A\n"), 0o600) },
+		},
+		{
+			Path: "docs/__generated__/ctx",
+			Dir:  true,
+			Render: func(dst string) error {
+				writeFile(t, filepath.Join(dst, "index.md"), "# Index\nnew\n")
+				writeFile(t, filepath.Join(dst, "files.md"), "# Files\n")
+				return nil
+			},
+		},
+		{
+			Path:   "docs/__generated__/missing.md",
+			Render: func(dst string) error { t.Fatal("missing artifacts must not be rendered"); return nil },
+		},
+	}
+
+	skipped, err := CheckGeneratedDrift(root, artifacts, DefaultDiffOptions())
+	assert.Equal(t, []string{"docs/__generated__/missing.md"}, skipped)
+
+	var drift *GeneratedDriftError
+	require.ErrorAs(t, err, &drift)
+
+	paths := make([]string, 0, len(drift.Drifts))
+	for _, d := range drift.Drifts {
+		paths = append(paths, d.Path)
+	}
+	assert.Equal(t, []string{
+		"docs/__generated__/ctx/files.md",
+		"docs/__generated__/ctx/index.md",
+		"docs/__generated__/ctx/stale.md",
+	}, paths)
+	assert.Contains(t, err.Error(), "-old\n+new")
+}
+
+func TestCheckGeneratedDrift_Clean(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "out.md"), "same\n")
+
+	_, err := CheckGeneratedDrift(root, []GeneratedArtifact{{
+		Path:   "out.md",
+		Render: func(dst string) error { return os.WriteFile(dst, []byte("same\n"), 0o600) },
+	}}, DefaultDiffOptions())
+	assert.NoError(t, err)
+}
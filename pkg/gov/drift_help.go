@@ -17,10 +17,19 @@ package gov
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+
+	"github.com/spf13/cobra"
 )
 
+// RootHelpFixture is the name of the fixture holding the root command's
+// help in a help fixture directory.
+const RootHelpFixture = "help.sample.txt"
+
 // NormalizeHelp applies normalization rules to CLI help output.
 // - keep only “Usage / Available Commands / Flags” blocks
 // - strip extra whitespace
@@ -70,6 +79,7 @@ func NormalizeHelp(input string) string {
 }
 
 // CompareHelp compares generated help with fixture.
+// On mismatch it returns a *DriftError whose diff goes from the fixture to the generated help.
 func CompareHelp(generated, fixturePath string) error {
 	fixtureBytes, err := os.ReadFile(fixturePath)
 	if err != nil {
@@ -80,12 +90,148 @@ func CompareHelp(generated, fixturePath string) error {
 	normFixture := NormalizeHelp(string(fixtureBytes))
 
 	if normGenerated != normFixture {
-		// Create a diff or just error
-		// For simplicity, error with lengths or just "mismatch"
-		// To be helpful, we could show a diff, but that requires a diff library or manual impl.
-		return fmt.Errorf("CLI help drift detected!\nFixture (%s) length: %d\nGenerated length: %d\n\nGenerated:\n%s\n\nFixture:\n%s",
-			fixturePath, len(normFixture), len(normGenerated), normGenerated, normFixture)
+		return &DriftError{
+			Message: fmt.Sprintf("CLI help drift detected (fixture %s)", fixturePath),
+			Diff:    UnifiedDiff(fixturePath, "generated", normFixture+"\n", normGenerated+"\n", DefaultDiffOptions()),
+		}
+	}
+
+	return nil
+}
+
+// HelpPage is the normalized help of one command.
+type HelpPage struct {
+	// Path is the command path without the root name, such as
+	// "gov drift help"; it is empty for the root command.
+	Path string
+	// Text is the normalized help, ending in a newline.
+	Text string
+}
+
+// Fixture returns the slash-separated name of the page's fixture in a help
+// fixture directory: RootHelpFixture for the root command, and the path
+// with spaces turned into directories otherwise ("gov/drift/help.txt").
+func (p HelpPage) Fixture() string {
+	if p.Path == "" {
+		return RootHelpFixture
+	}
+	return strings.ReplaceAll(p.Path, " ", "/") + ".txt"
+}
+
+// WalkHelp returns the help of root and of every available subcommand,
+// depth first in name order. Hidden and deprecated commands and the
+// generated help command are skipped. The default help flag, help command
+// and completion command, which cobra only adds on execution, are added
+// first so that the pages do not depend on what ran before.
+func WalkHelp(root *cobra.Command) []HelpPage {
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	var pages []HelpPage
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		c.InitDefaultHelpFlag()
+		path := strings.TrimPrefix(strings.TrimPrefix(c.CommandPath(), root.Name()), " ")
+		pages = append(pages, HelpPage{Path: path, Text: NormalizeHelp(c.UsageString()) + "\n"})
+
+		subs := slices.Clone(c.Commands())
+		slices.SortStableFunc(subs, func(a, b *cobra.Command) int { return strings.Compare(a.Name(), b.Name()) })
+		for _, sub := range subs {
+			if sub.IsAvailableCommand() {
+				walk(sub)
+			}
+		}
+	}
+	walk(root)
+	return pages
+}
+
+// HelpDriftError lists every help fixture that no longer matches the help
+// of its command, including fixtures of commands that are gone.
+type HelpDriftError struct {
+	Drifts []GeneratedDrift
+}
+
+func (e *HelpDriftError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CLI help drift detected in %d file(s); rerun with --update to regenerate the fixtures:", len(e.Drifts))
+	for _, d := range e.Drifts {
+		b.WriteString("\n\n")
+		b.WriteString(d.Diff.String())
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// CompareHelpTree compares pages with the fixtures in dir. A missing
+// fixture and a fixture with no page are drift too. On mismatch it returns
+// a *HelpDriftError whose diffs go from the fixtures to the pages.
+func CompareHelpTree(pages []HelpPage, dir string, opts DiffOptions) error {
+	want := map[string]string{}
+	existing, err := listFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range existing {
+		if strings.HasSuffix(name, ".txt") {
+			want[name] = ""
+		}
+	}
+	got := make(map[string]string, len(pages))
+	for _, p := range pages {
+		got[p.Fixture()] = p.Text
+		want[p.Fixture()] = ""
 	}
 
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var drifts []GeneratedDrift
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		old, err := readOptional(path)
+		if err != nil {
+			return err
+		}
+		d := UnifiedDiff(filepath.ToSlash(path), "generated", old, got[name], opts)
+		if !d.Empty() {
+			drifts = append(drifts, GeneratedDrift{Path: filepath.ToSlash(path), Diff: d})
+		}
+	}
+	if len(drifts) > 0 {
+		return &HelpDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// WriteHelpTree writes the fixture of every page under dir and removes
+// the fixtures of commands that are gone, so that a later CompareHelpTree
+// finds no drift.
+func WriteHelpTree(pages []HelpPage, dir string) error {
+	keep := make(map[string]bool, len(pages))
+	for _, p := range pages {
+		keep[p.Fixture()] = true
+		path := filepath.Join(dir, filepath.FromSlash(p.Fixture()))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(p.Text), 0o644); err != nil { //nolint:gosec // fixtures are committed files
+			return err
+		}
+	}
+
+	existing, err := listFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range existing {
+		if strings.HasSuffix(name, ".txt") && !keep[name] {
+			if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
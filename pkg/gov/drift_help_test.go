@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helpTree() *cobra.Command {
+	root := &cobra.Command{Use: "tool"}
+	root.PersistentFlags().Bool("verbose", false, "verbose output")
+	parent := &cobra.Command{Use: "db", Short: "Database commands"}
+	migrate := &cobra.Command{Use: "migrate", Short: "Run migrations", Run: func(*cobra.Command, []string) {}}
+	migrate.Flags().Int("steps", 0, "Steps to run")
+	parent.AddCommand(migrate)
+	parent.AddCommand(&cobra.Command{Use: "hidden", Hidden: true, Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(&cobra.Command{Use: "apply", Short: "Apply changes", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(parent)
+	return root
+}
+
+func TestWalkHelp(t *testing.T) {
+	pages := WalkHelp(helpTree())
+
+	var fixtures []string
+	for _, p := range pages {
+		fixtures = append(fixtures, p.Fixture())
+	}
+	assert.Equal(t, []string{
+		"help.sample.txt",
+		"apply.txt",
+		"completion.txt",
+		"completion/bash.txt",
+		"completion/fish.txt",
+		"completion/powershell.txt",
+		"completion/zsh.txt",
+		"db.txt",
+		"db/migrate.txt",
+	}, fixtures)
+
+	migrate := pages[len(pages)-1]
+	assert.Equal(t, "db migrate", migrate.Path)
+	assert.Equal(t, "Usage:\ntool db migrate [flags]\nFlags:\n-h, --help        help for migrate\n--steps int   Steps to run\nGlobal Flags:\n--verbose   verbose output\n", migrate.Text)
+}
+
+func TestCompareHelpTree(t *testing.T) {
+	dir := t.TempDir()
+	pages := WalkHelp(helpTree())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gone.txt"), []byte("Usage:\ntool gone\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a fixture\n"), 0o600))
+
+	err := CompareHelpTree(pages, dir, DefaultDiffOptions())
+	var drift *HelpDriftError
+	require.ErrorAs(t, err, &drift)
+	assert.Len(t, drift.Drifts, len(pages)+1, "every page is missing and gone.txt is stale")
+
+	require.NoError(t, WriteHelpTree(pages, dir))
+	require.NoError(t, CompareHelpTree(pages, dir, DefaultDiffOptions()))
+	assert.NoFileExists(t, filepath.Join(dir, "gone.txt"))
+	assert.FileExists(t, filepath.Join(dir, "README.md"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db", "migrate.txt"), []byte("Usage:\ntool db migrate [flags]\n"), 0o600))
+	err = CompareHelpTree(pages, dir, DefaultDiffOptions())
+	require.ErrorAs(t, err, &drift)
+	require.Len(t, drift.Drifts, 1)
+	assert.Equal(t, filepath.ToSlash(filepath.Join(dir, "db", "migrate.txt")), drift.Drifts[0].Path)
+	assert.Contains(t, err.Error(), "+--steps int   Steps to run")
+}
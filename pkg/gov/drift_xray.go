@@ -1,12 +1,14 @@
 package gov
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
+
+	"github.com/bartekus/cortex/internal/canonjson"
 )
 
 type XrayIndex struct {
@@ -44,13 +46,25 @@ func CheckXrayDrift(path string) error {
 	if !sort.SliceIsSorted(index.Files, func(i, j int) bool {
 		return index.Files[i].Path < index.Files[j].Path
 	}) {
+		paths := make([]string, len(index.Files))
+		for i, f := range index.Files {
+			paths[i] = f.Path
+		}
+		sorted := append([]string(nil), paths...)
+		sort.Strings(sorted)
+
 		// Find the unsorted one for better error message
-		for i := 0; i < len(index.Files)-1; i++ {
-			if index.Files[i].Path > index.Files[i+1].Path {
-				return fmt.Errorf("XRAY fixture files are not sorted: %s > %s", index.Files[i].Path, index.Files[i+1].Path)
+		msg := "XRAY fixture files are not sorted (unknown position)"
+		for i := 0; i < len(paths)-1; i++ {
+			if paths[i] > paths[i+1] {
+				msg = fmt.Sprintf("XRAY fixture files are not sorted: %s > %s", paths[i], paths[i+1])
+				break
 			}
 		}
-		return fmt.Errorf("XRAY fixture files are not sorted (unknown position)")
+		return &DriftError{
+			Message: msg,
+			Diff:    UnifiedDiff(path, "sorted", strings.Join(paths, "\n"), strings.Join(sorted, "\n"), DefaultDiffOptions()),
+		}
 	}
 
 	// Check for uniqueness
@@ -61,8 +75,10 @@ func CheckXrayDrift(path string) error {
 	}
 
 	// 3. Verify Digest
-	var rawMap map[string]interface{}
-	if err := json.Unmarshal(data, &rawMap); err != nil {
+	var rawMap map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&rawMap); err != nil {
 		return fmt.Errorf("failed to parse JSON map: %w", err)
 	}
 
@@ -73,16 +89,16 @@ func CheckXrayDrift(path string) error {
 	// Remove digest for calculation
 	delete(rawMap, "digest")
 
-	canonicalJSON, err := json.Marshal(rawMap)
+	calculatedDigest, err := canonjson.Sum256(rawMap)
 	if err != nil {
-		return fmt.Errorf("failed to marshal canonical JSON: %w", err)
+		return fmt.Errorf("failed to compute canonical digest: %w", err)
 	}
 
-	hash := sha256.Sum256(canonicalJSON)
-	calculatedDigest := hex.EncodeToString(hash[:])
-
 	if index.Digest != calculatedDigest {
-		return fmt.Errorf("XRAY fixture digest mismatch!\nExpected: %s\nCalculated: %s\n(Note: Calculated over canonical JSON without 'digest' field)", index.Digest, calculatedDigest)
+		return &DriftError{
+			Message: "XRAY fixture digest mismatch (calculated over canonical JSON without 'digest' field)",
+			Diff:    UnifiedDiff(path, "calculated", "digest: "+index.Digest, "digest: "+calculatedDigest, DefaultDiffOptions()),
+		}
 	}
 
 	return nil
@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bartekus/cortex/internal/projection"
+)
+
+// featureFields is the canonical key order of a feature entry.
+var featureFields = []string{"id", "title", "governance", "implementation", "spec", "owner", "group", "tests", "depends_on"}
+
+// quotedFields are written double-quoted in new entries.
+var quotedFields = map[string]bool{"title": true, "spec": true}
+
+// Document is an editable features.yaml. Edits go through the YAML node
+// tree, so entry order, unknown keys and comments are preserved.
+type Document struct {
+	root *yaml.Node
+	list *yaml.Node // the features sequence
+}
+
+// LoadDocument reads features.yaml for editing.
+func LoadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %w", err)
+	}
+	return ParseDocument(data)
+}
+
+// ParseDocument parses features.yaml content for editing.
+func ParseDocument(data []byte) (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse registry YAML: %w", err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("registry must be a mapping with a features list")
+	}
+	list := mappingValue(root.Content[0], "features")
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("registry must be a mapping with a features list")
+	}
+	return &Document{root: &root, list: list}, nil
+}
+
+// Registry decodes the document.
+func (d *Document) Registry() (*Registry, error) {
+	var reg Registry
+	if err := d.root.Decode(&reg); err != nil {
+		return nil, fmt.Errorf("failed to decode registry: %w", err)
+	}
+	return &reg, nil
+}
+
+// Validate checks the registry structure and its dependency graph.
+func (d *Document) Validate() error {
+	reg, err := d.Registry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Validate(); err != nil {
+		return err
+	}
+	return reg.ValidateDependencies()
+}
+
+// Add appends a feature entry. Empty tests/depends_on are written as [].
+func (d *Document) Add(f Feature) error {
+	if d.find(f.ID) != nil {
+		return fmt.Errorf("feature %s already exists", f.ID)
+	}
+	entry := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	values := map[string]string{
+		"id":             f.ID,
+		"title":          f.Title,
+		"governance":     string(f.Governance),
+		"implementation": string(f.Implementation),
+		"spec":           f.Spec,
+		"owner":          f.Owner,
+		"group":          f.Group,
+	}
+	for _, key := range featureFields {
+		var value *yaml.Node
+		switch key {
+		case "tests":
+			value = flowList(f.Tests)
+		case "depends_on":
+			value = flowList(f.DependsOn)
+		default:
+			value = scalar(values[key])
+			if quotedFields[key] {
+				value.Style = yaml.DoubleQuotedStyle
+			}
+		}
+		entry.Content = append(entry.Content, scalar(key), value)
+	}
+	d.list.Content = append(d.list.Content, entry)
+	return nil
+}
+
+// Set changes one field of feature id. Lists (tests, depends_on) take a
+// comma-separated value; an empty value clears them.
+func (d *Document) Set(id, field, value string) error {
+	entry := d.find(id)
+	if entry == nil {
+		return fmt.Errorf("feature %s not found", id)
+	}
+	if field == "id" {
+		return fmt.Errorf("use rename to change a feature ID")
+	}
+	known := false
+	for _, k := range featureFields {
+		known = known || k == field
+	}
+	if !known {
+		return fmt.Errorf("unknown feature field %q (must be one of %s)", field, strings.Join(featureFields[1:], ", "))
+	}
+
+	var node *yaml.Node
+	if field == "tests" || field == "depends_on" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		node = flowList(items)
+	} else {
+		node = scalar(value)
+	}
+
+	if existing := mappingValue(entry, field); existing != nil {
+		// Keep the existing style (quoting, flow lists) and comments.
+		node.Style = existing.Style
+		node.HeadComment, node.LineComment, node.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+		*existing = *node
+		return nil
+	}
+	if quotedFields[field] {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	entry.Content = append(entry.Content, scalar(field), node)
+	return nil
+}
+
+// Rename changes a feature ID and every depends_on reference to it.
+func (d *Document) Rename(oldID, newID string) error {
+	entry := d.find(oldID)
+	if entry == nil {
+		return fmt.Errorf("feature %s not found", oldID)
+	}
+	if d.find(newID) != nil {
+		return fmt.Errorf("feature %s already exists", newID)
+	}
+	mappingValue(entry, "id").Value = newID
+	for _, e := range d.list.Content {
+		deps := mappingValue(e, "depends_on")
+		if deps == nil || deps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, dep := range deps.Content {
+			if dep.Value == oldID {
+				dep.Value = newID
+			}
+		}
+	}
+	return nil
+}
+
+// Bytes encodes the document canonically: two-space indentation and one
+// blank line before every entry after the first (and before its comments).
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d.root); err != nil {
+		return nil, fmt.Errorf("encoding registry: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding registry: %w", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	out := make([]string, 0, len(lines)+len(d.list.Content))
+	seen := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "  - ") {
+			if seen {
+				// Back up over the entry's head comment.
+				at := len(out)
+				for at > 0 && strings.HasPrefix(out[at-1], "  #") {
+					at--
+				}
+				if at > 0 && out[at-1] != "" {
+					out = append(out[:at], append([]string{""}, out[at:]...)...)
+				}
+			}
+			seen = true
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// Write validates the document and writes it atomically to path.
+func (d *Document) Write(path string) error {
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("registry validation failed: %w", err)
+	}
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	return projection.AtomicWrite(path, data)
+}
+
+func (d *Document) find(id string) *yaml.Node {
+	for _, e := range d.list.Content {
+		if v := mappingValue(e, "id"); v != nil && v.Value == id {
+			return e
+		}
+	}
+	return nil
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func flowList(items []string) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	for _, item := range items {
+		n.Content = append(n.Content, scalar(item))
+	}
+	return n
+}
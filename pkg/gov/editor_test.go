@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package gov
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const editorFixture = `# Registry header
+
+features:
+  # --- Core ---
+  - id: CORE
+    title: "Core"
+    governance: approved
+    implementation: done
+    spec: "spec/core.md"
+    owner: bart
+    group: core
+    tests: []
+    depends_on: []
+
+  - id: CLI
+    title: "CLI"
+    governance: approved
+    implementation: wip # tracked in roadmap
+    spec: "spec/cli.md"
+    owner: bart
+    group: cli
+    tests: []
+    depends_on: [CORE]
+`
+
+func TestDocument_RoundTripIsCanonical(t *testing.T) {
+	doc, err := ParseDocument([]byte(editorFixture))
+	require.NoError(t, err)
+	out, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, editorFixture, string(out))
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "spec", "features.yaml"))
+	require.NoError(t, err)
+	doc, err = ParseDocument(data)
+	require.NoError(t, err)
+	out, err = doc.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(out), "spec/features.yaml is not in canonical form")
+}
+
+func TestDocument_Edits(t *testing.T) {
+	doc, err := ParseDocument([]byte(editorFixture))
+	require.NoError(t, err)
+
+	require.NoError(t, doc.Set("CLI", "implementation", "done"))
+	require.NoError(t, doc.Set("CLI", "title", "CLI Surface"))
+	require.NoError(t, doc.Rename("CORE", "CORE_CONTRACT"))
+	require.NoError(t, doc.Add(Feature{
+		ID: "NEW", Title: "New", Governance: GovDraft, Implementation: ImplTodo,
+		Spec: "spec/new.md", Owner: "bart", Group: "cli", DependsOn: []string{"CLI"},
+	}))
+	require.NoError(t, doc.Validate())
+
+	out, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "  # --- Core ---\n  - id: CORE_CONTRACT\n")
+	assert.Contains(t, string(out), "    title: \"CLI Surface\"\n")
+	assert.Contains(t, string(out), "    implementation: done # tracked in roadmap\n")
+	assert.Contains(t, string(out), "    depends_on: [CORE_CONTRACT]\n\n  - id: NEW\n    title: \"New\"\n")
+	assert.Contains(t, string(out), "    tests: []\n    depends_on: [CLI]\n")
+
+	assert.Error(t, doc.Add(Feature{ID: "NEW"}))
+	assert.Error(t, doc.Set("MISSING", "title", "x"))
+	assert.Error(t, doc.Set("CLI", "id", "x"))
+	assert.Error(t, doc.Set("CLI", "colour", "x"))
+	assert.Error(t, doc.Rename("CLI", "NEW"))
+
+	require.NoError(t, doc.Set("CLI", "depends_on", "MISSING"))
+	assert.Error(t, doc.Validate())
+	assert.Error(t, doc.Write(filepath.Join(t.TempDir(), "features.yaml")))
+}
@@ -29,19 +29,19 @@ const (
 )
 
 type Feature struct {
-	ID             string              `yaml:"id"`
-	Title          string              `yaml:"title"`
-	Governance     GovernanceState     `yaml:"governance"`
-	Implementation ImplementationState `yaml:"implementation"`
-	Spec           string              `yaml:"spec"`
-	Owner          string              `yaml:"owner"`
-	Group          string              `yaml:"group"`
-	Tests          []string            `yaml:"tests"`
-	DependsOn      []string            `yaml:"depends_on"`
+	ID             string              `yaml:"id" json:"id"`
+	Title          string              `yaml:"title" json:"title"`
+	Governance     GovernanceState     `yaml:"governance" json:"governance"`
+	Implementation ImplementationState `yaml:"implementation" json:"implementation"`
+	Spec           string              `yaml:"spec" json:"spec"`
+	Owner          string              `yaml:"owner" json:"owner"`
+	Group          string              `yaml:"group" json:"group"`
+	Tests          []string            `yaml:"tests" json:"tests"`
+	DependsOn      []string            `yaml:"depends_on" json:"depends_on"`
 }
 
 type Registry struct {
-	Features []Feature `yaml:"features"`
+	Features []Feature `yaml:"features" json:"features"`
 }
 
 func LoadRegistry(path string) (*Registry, error) {
@@ -15,31 +15,86 @@ See https://www.gnu.org/licenses/ for license details.
 package introspect
 
 import (
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
+// SchemaVersion is the schema_version of the cli.json dump.
+const SchemaVersion = "1.0"
+
+// Dump is the versioned cli.json document written by `cortex gov cli-dump-json`.
+type Dump struct {
+	SchemaVersion string        `json:"schema_version"`
+	Commands      []CommandInfo `json:"commands"`
+}
+
+// NewDump introspects root and wraps the command tree in a versioned document.
+func NewDump(root *cobra.Command) Dump {
+	return Dump{SchemaVersion: SchemaVersion, Commands: Introspect(root)}
+}
+
+// ExitCodesAnnotation is the cobra annotation listing the exit codes a
+// command may return, comma-separated (e.g. "0,1,4"). Subcommands inherit
+// the codes of their nearest annotated ancestor.
+const ExitCodesAnnotation = "cortex.exit_codes"
+
+// SetExitCodes records the exit codes cmd and its subcommands may return.
+func SetExitCodes(cmd *cobra.Command, codes ...int) {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = strconv.Itoa(code)
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[ExitCodesAnnotation] = strings.Join(parts, ",")
+}
+
 // CommandInfo represents information about a Cobra command and its flags.
 type CommandInfo struct {
-	Use         string        `json:"use"`
-	Short       string        `json:"short"`
-	Long        string        `json:"long"`
+	Use     string   `json:"use"`
+	Aliases []string `json:"aliases,omitempty"`
+	Short   string   `json:"short"`
+	Long    string   `json:"long"`
+	// Hidden commands are left out of help but are still part of the CLI.
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated is cobra's deprecation message, printed when the command
+	// runs.
+	Deprecated string   `json:"deprecated,omitempty"`
+	Args       ArgsInfo `json:"args"`
+	// ExitCodes are the codes of ExitCodesAnnotation, sorted, or nil when
+	// neither the command nor an ancestor declares them.
+	ExitCodes   []int         `json:"exit_codes,omitempty"`
 	Flags       []FlagInfo    `json:"flags"`
 	Subcommands []CommandInfo `json:"subcommands,omitempty"`
 }
 
+// ArgsInfo is the number of positional arguments a command accepts.
+type ArgsInfo struct {
+	Min int `json:"min"`
+	// Max is -1 when the number is unbounded.
+	Max int `json:"max"`
+}
+
 // FlagInfo represents information about a CLI flag.
 type FlagInfo struct {
-	Name       string `json:"name"`
-	Shorthand  string `json:"shorthand"`
-	Type       string `json:"type"`
-	Default    string `json:"default"`
-	Usage      string `json:"usage"`
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand"`
+	Type      string `json:"type"`
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+	// Persistent flags apply to subcommands too; they are defined by the
+	// command or Inherited from an ancestor.
 	Persistent bool   `json:"persistent"`
+	Inherited  bool   `json:"inherited,omitempty"`
 	Required   bool   `json:"required"`
+	Hidden     bool   `json:"hidden,omitempty"`
+	Deprecated string `json:"deprecated,omitempty"`
 }
 
 // Introspect introspects a Cobra command tree and returns information about all commands and flags.
@@ -56,10 +111,15 @@ func collectCommands(cmd *cobra.Command, commands *[]CommandInfo, includeRoot bo
 	}
 
 	info := CommandInfo{
-		Use:   cmd.Use,
-		Short: cmd.Short,
-		Long:  cmd.Long,
-		Flags: collectFlags(cmd),
+		Use:        cmd.Use,
+		Aliases:    cmd.Aliases,
+		Short:      cmd.Short,
+		Long:       cmd.Long,
+		Hidden:     cmd.Hidden,
+		Deprecated: cmd.Deprecated,
+		Args:       collectArgs(cmd),
+		ExitCodes:  collectExitCodes(cmd),
+		Flags:      collectFlags(cmd),
 	}
 
 	// Collect subcommands (direct children only, they will recursively collect their own subcommands)
@@ -72,10 +132,6 @@ func collectCommands(cmd *cobra.Command, commands *[]CommandInfo, includeRoot bo
 		if subcmd.IsAdditionalHelpTopicCommand() && subcmd.Parent() == nil {
 			continue
 		}
-		// Skip if command is hidden
-		if subcmd.Hidden {
-			continue
-		}
 		// Collect this subcommand and its descendants recursively
 		var subcmdInfos []CommandInfo
 		collectCommands(subcmd, &subcmdInfos, true)
@@ -112,7 +168,9 @@ func collectFlags(cmd *cobra.Command) []FlagInfo {
 	// Collect inherited flags from parent commands (but only if they're not already collected)
 	cmd.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
 		if _, exists := flagMap[flag.Name]; !exists {
-			flagMap[flag.Name] = flagToInfo(flag, true)
+			info := flagToInfo(flag, true)
+			info.Inherited = true
+			flagMap[flag.Name] = info
 		}
 	})
 
@@ -137,7 +195,9 @@ func flagToInfo(flag *pflag.Flag, persistent bool) FlagInfo {
 		Shorthand:  flag.Shorthand,
 		Usage:      flag.Usage,
 		Persistent: persistent,
-		Required:   false, // pflag doesn't expose this directly, would need custom logic
+		Required:   slices.Contains(flag.Annotations[cobra.BashCompOneRequiredFlag], "true"),
+		Hidden:     flag.Hidden,
+		Deprecated: flag.Deprecated,
 	}
 
 	// Determine type
@@ -153,6 +213,57 @@ func flagToInfo(flag *pflag.Flag, persistent bool) FlagInfo {
 	return info
 }
 
+// maxProbedArgs is the argument count from which a command's arguments
+// count as unbounded.
+const maxProbedArgs = 8
+
+// collectArgs finds the arity of cmd by validating placeholder arguments
+// of every count up to maxProbedArgs. Validators that check the values,
+// such as cobra.OnlyValidArgs, reject the placeholders.
+func collectArgs(cmd *cobra.Command) ArgsInfo {
+	info := ArgsInfo{Min: -1}
+	for n := 0; n <= maxProbedArgs; n++ {
+		args := make([]string, n)
+		for i := range args {
+			args[i] = "arg"
+		}
+		if cmd.ValidateArgs(args) != nil {
+			continue
+		}
+		if info.Min < 0 {
+			info.Min = n
+		}
+		info.Max = n
+	}
+	switch {
+	case info.Min < 0:
+		return ArgsInfo{}
+	case info.Max == maxProbedArgs:
+		info.Max = -1
+	}
+	return info
+}
+
+// collectExitCodes returns the exit codes annotated on cmd or its nearest
+// annotated ancestor. Codes that do not parse are skipped.
+func collectExitCodes(cmd *cobra.Command) []int {
+	for c := cmd; c != nil; c = c.Parent() {
+		value, ok := c.Annotations[ExitCodesAnnotation]
+		if !ok {
+			continue
+		}
+		codes := []int{}
+		for _, part := range strings.Split(value, ",") {
+			if code, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+				codes = append(codes, code)
+			}
+		}
+		sort.Ints(codes)
+		return codes
+	}
+	return nil
+}
+
 // inferFlagType attempts to infer the flag type from its value representation.
 func inferFlagType(flag *pflag.Flag) string {
 	valueType := flag.Value.Type()
@@ -182,3 +182,58 @@ func TestGetCommandFlags(t *testing.T) {
 		t.Error("expected to find 'version' flag")
 	}
 }
+
+func TestIntrospect_CommandContract(t *testing.T) {
+	root := &cobra.Command{Use: "cortex"}
+	root.PersistentFlags().Bool("verbose", false, "verbose output")
+	SetExitCodes(root, 4, 0, 1)
+
+	get := &cobra.Command{
+		Use:        "get <key>",
+		Aliases:    []string{"g"},
+		Deprecated: "use fetch",
+		Args:       cobra.ExactArgs(1),
+		Run:        func(*cobra.Command, []string) {},
+	}
+	get.Flags().String("out", "", "output path")
+	_ = get.MarkFlagRequired("out")
+	get.Flags().Bool("old", false, "old flag")
+	_ = get.Flags().MarkDeprecated("old", "no longer used")
+	secret := &cobra.Command{Use: "secret", Hidden: true, Args: cobra.MinimumNArgs(1), Run: func(*cobra.Command, []string) {}}
+	SetExitCodes(secret, 0)
+	root.AddCommand(get, secret)
+
+	cmds := Introspect(root)[0].Subcommands
+	if len(cmds) != 2 {
+		t.Fatalf("expected hidden commands to be included, got %d subcommands", len(cmds))
+	}
+
+	g := cmds[0]
+	if len(g.Aliases) != 1 || g.Aliases[0] != "g" || g.Deprecated != "use fetch" {
+		t.Errorf("unexpected aliases %v or deprecation %q", g.Aliases, g.Deprecated)
+	}
+	if g.Args != (ArgsInfo{Min: 1, Max: 1}) {
+		t.Errorf("expected exactly 1 arg, got %+v", g.Args)
+	}
+	if len(g.ExitCodes) != 3 || g.ExitCodes[0] != 0 || g.ExitCodes[2] != 4 {
+		t.Errorf("expected the root's exit codes sorted, got %v", g.ExitCodes)
+	}
+	flags := map[string]FlagInfo{}
+	for _, f := range g.Flags {
+		flags[f.Name] = f
+	}
+	if !flags["out"].Required {
+		t.Error("expected --out to be required")
+	}
+	if !flags["old"].Hidden || flags["old"].Deprecated != "no longer used" {
+		t.Errorf("expected --old to be hidden and deprecated, got %+v", flags["old"])
+	}
+	if v := flags["verbose"]; !v.Persistent || !v.Inherited {
+		t.Errorf("expected --verbose to be inherited, got %+v", v)
+	}
+
+	s := cmds[1]
+	if !s.Hidden || s.Args != (ArgsInfo{Min: 1, Max: -1}) || len(s.ExitCodes) != 1 {
+		t.Errorf("unexpected hidden command %+v", s)
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package llm
+
+// Feature: LLM_PROVIDER
+// Spec: spec/system/llm.md
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/bartekus/cortex/internal/projection"
+)
+
+// CassetteSchemaVersion is the schema_version of a cassette file.
+const CassetteSchemaVersion = "1.0"
+
+// ErrNotRecorded reports a request a cassette has no response for.
+var ErrNotRecorded = errors.New("request not recorded")
+
+// Cassette is a file of recorded exchanges, sorted by key.
+type Cassette struct {
+	SchemaVersion string     `json:"schema_version"`
+	Exchanges     []Exchange `json:"exchanges"`
+}
+
+// Exchange is a recorded request and the response it got.
+type Exchange struct {
+	// Key is Key(Request).
+	Key      string   `json:"key"`
+	Provider string   `json:"provider"`
+	Model    string   `json:"model"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Key identifies a request: "sha256:<hex>" of its JSON encoding. The
+// provider and model are not part of it, so a cassette replays under any
+// configuration.
+func Key(req Request) string {
+	data, _ := json.Marshal(req) // a Request always encodes
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ReadCassette reads the cassette at path.
+func ReadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: cassette path chosen by the caller
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+	}
+	if c.SchemaVersion != CassetteSchemaVersion {
+		return nil, fmt.Errorf("cassette %s: unsupported schema_version %q", path, c.SchemaVersion)
+	}
+	return &c, nil
+}
+
+// Recorder is a Provider that passes requests to another one and records
+// every exchange to a cassette file.
+type Recorder struct {
+	p    Provider
+	path string
+
+	mu        sync.Mutex
+	exchanges map[string]Exchange
+}
+
+// Record returns a Recorder around p writing to path. Exchanges already in
+// the cassette are kept; a request recorded again replaces its exchange.
+func Record(p Provider, path string) (*Recorder, error) {
+	r := &Recorder{p: p, path: path, exchanges: map[string]Exchange{}}
+	c, err := ReadCassette(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if c != nil {
+		for _, e := range c.Exchanges {
+			r.exchanges[e.Key] = e
+		}
+	}
+	return r, nil
+}
+
+// Name returns the name of the recorded provider.
+func (r *Recorder) Name() string { return r.p.Name() }
+
+// Model returns the model of the recorded provider.
+func (r *Recorder) Model() string { return r.p.Model() }
+
+// Complete passes req on and writes the exchange to the cassette.
+func (r *Recorder) Complete(ctx context.Context, req Request) (*Response, error) {
+	resp, err := r.p.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := Key(req)
+	r.exchanges[key] = Exchange{Key: key, Provider: r.p.Name(), Model: r.p.Model(), Request: req, Response: *resp}
+	c := Cassette{SchemaVersion: CassetteSchemaVersion, Exchanges: make([]Exchange, 0, len(r.exchanges))}
+	for _, e := range r.exchanges {
+		c.Exchanges = append(c.Exchanges, e)
+	}
+	sort.Slice(c.Exchanges, func(i, j int) bool { return c.Exchanges[i].Key < c.Exchanges[j].Key })
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := projection.AtomicWrite(r.path, append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("writing cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer is a Provider answering from a cassette, without network access.
+type Replayer struct {
+	exchanges map[string]Exchange
+}
+
+// Replay returns a Replayer for the cassette at path.
+func Replay(path string) (*Replayer, error) {
+	c, err := ReadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{exchanges: make(map[string]Exchange, len(c.Exchanges))}
+	for _, e := range c.Exchanges {
+		r.exchanges[e.Key] = e
+	}
+	return r, nil
+}
+
+// Name returns "replay".
+func (r *Replayer) Name() string { return "replay" }
+
+// Model returns "replay".
+func (r *Replayer) Model() string { return "replay" }
+
+// Complete returns the recorded response to req, or ErrNotRecorded.
+func (r *Replayer) Complete(_ context.Context, req Request) (*Response, error) {
+	key := Key(req)
+	e, ok := r.exchanges[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+	}
+	resp := e.Response
+	return &resp, nil
+}
@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package llm is the gateway to large language models for Cortex's
+// optional generative features, such as polishing commit drafts.
+//
+// Deterministic artifacts never depend on a model: the purity skill only
+// allows this package to be imported under cmd/, so commands inject model
+// output into features explicitly and only when the user opts in. Tests use
+// Record and Replay instead of a live provider.
+//
+//	p, err := llm.New(llm.Config{Provider: llm.ProviderOllama})
+//	if err != nil {
+//		return err
+//	}
+//	resp, err := p.Complete(ctx, llm.Request{
+//		System:   "You write commit messages.",
+//		Messages: []llm.Message{{Role: llm.RoleUser, Content: draft}},
+//	})
+package llm
+
+// Feature: LLM_PROVIDER
+// Spec: spec/system/llm.md
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by New.
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
+)
+
+// Default endpoints and models per provider.
+const (
+	DefaultOpenAIBaseURL    = "https://api.openai.com/v1"
+	DefaultOpenAIModel      = "gpt-4o-mini"
+	DefaultAnthropicBaseURL = "https://api.anthropic.com"
+	DefaultAnthropicModel   = "claude-3-5-haiku-latest"
+	DefaultOllamaBaseURL    = "http://localhost:11434"
+	DefaultOllamaModel      = "llama3.2"
+)
+
+// DefaultMaxTokens caps a completion when Request.MaxTokens is zero.
+const DefaultMaxTokens = 1024
+
+// Message roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is a turn of a conversation.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a completion request.
+type Request struct {
+	// System is the system prompt.
+	System   string    `json:"system,omitempty"`
+	Messages []Message `json:"messages"`
+	// MaxTokens caps the completion; zero means DefaultMaxTokens.
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// Temperature is passed through; zero asks for the most likely output.
+	Temperature float64 `json:"temperature"`
+}
+
+// Response is a completion.
+type Response struct {
+	Text string `json:"text"`
+}
+
+// Provider completes conversations.
+type Provider interface {
+	Name() string
+	Model() string
+	Complete(ctx context.Context, req Request) (*Response, error)
+}
+
+// Config selects and configures a provider.
+type Config struct {
+	Provider string
+	Model    string
+	BaseURL  string
+	// APIKey defaults to APIKey(Provider).
+	APIKey string
+	// Client defaults to an http.Client with a 120s timeout.
+	Client *http.Client
+}
+
+// New builds the provider named in cfg, filling in defaults.
+func New(cfg Config) (Provider, error) {
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 120 * time.Second}
+	}
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = APIKey(cfg.Provider)
+	}
+
+	base := provider{model: cfg.Model, apiKey: apiKey, client: client}
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		base.baseURL, base.model = orDefault(cfg.BaseURL, DefaultOpenAIBaseURL), orDefault(cfg.Model, DefaultOpenAIModel)
+		return &openAIProvider{base}, nil
+	case ProviderAnthropic:
+		base.baseURL, base.model = orDefault(cfg.BaseURL, DefaultAnthropicBaseURL), orDefault(cfg.Model, DefaultAnthropicModel)
+		return &anthropicProvider{base}, nil
+	case ProviderOllama:
+		base.baseURL, base.model = orDefault(cfg.BaseURL, DefaultOllamaBaseURL), orDefault(cfg.Model, DefaultOllamaModel)
+		return &ollamaProvider{base}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (must be %q, %q or %q)", cfg.Provider, ProviderOpenAI, ProviderAnthropic, ProviderOllama)
+	}
+}
+
+// APIKey returns the API key for provider from the environment:
+// $CORTEX_LLM_API_KEY, then $OPENAI_API_KEY or $ANTHROPIC_API_KEY.
+func APIKey(provider string) string {
+	if key := os.Getenv("CORTEX_LLM_API_KEY"); key != "" {
+		return key
+	}
+	switch provider {
+	case ProviderOpenAI:
+		return os.Getenv("OPENAI_API_KEY")
+	case ProviderAnthropic:
+		return os.Getenv("ANTHROPIC_API_KEY")
+	}
+	return ""
+}
+
+// provider holds what the HTTP providers share.
+type provider struct {
+	baseURL string
+	model   string
+	apiKey  string
+	client  *http.Client
+}
+
+func (p *provider) Model() string { return p.model }
+
+// messages returns the conversation with the system prompt as a leading
+// "system" message, for APIs without a separate field.
+func messages(req Request) []Message {
+	out := make([]Message, 0, len(req.Messages)+1)
+	if req.System != "" {
+		out = append(out, Message{Role: "system", Content: req.System})
+	}
+	return append(out, req.Messages...)
+}
+
+func maxTokens(req Request) int {
+	if req.MaxTokens > 0 {
+		return req.MaxTokens
+	}
+	return DefaultMaxTokens
+}
+
+// openAIProvider talks to any OpenAI-compatible /chat/completions endpoint.
+type openAIProvider struct{ provider }
+
+func (p *openAIProvider) Name() string { return ProviderOpenAI }
+
+func (p *openAIProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	var resp struct {
+		Choices []struct {
+			Message Message `json:"message"`
+		} `json:"choices"`
+	}
+	body := map[string]any{
+		"model":       p.model,
+		"messages":    messages(req),
+		"max_tokens":  maxTokens(req),
+		"temperature": req.Temperature,
+	}
+	headers := map[string]string{}
+	if p.apiKey != "" {
+		headers["Authorization"] = "Bearer " + p.apiKey
+	}
+	if err := postJSON(ctx, p.client, strings.TrimRight(p.baseURL, "/")+"/chat/completions", headers, body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("completion has no choices")
+	}
+	return &Response{Text: resp.Choices[0].Message.Content}, nil
+}
+
+// anthropicProvider talks to the Anthropic Messages API.
+type anthropicProvider struct{ provider }
+
+func (p *anthropicProvider) Name() string { return ProviderAnthropic }
+
+func (p *anthropicProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	body := map[string]any{
+		"model":       p.model,
+		"messages":    req.Messages,
+		"max_tokens":  maxTokens(req),
+		"temperature": req.Temperature,
+	}
+	if req.System != "" {
+		body["system"] = req.System
+	}
+	headers := map[string]string{"anthropic-version": "2023-06-01"}
+	if p.apiKey != "" {
+		headers["x-api-key"] = p.apiKey
+	}
+	if err := postJSON(ctx, p.client, strings.TrimRight(p.baseURL, "/")+"/v1/messages", headers, body, &resp); err != nil {
+		return nil, err
+	}
+	var text strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	return &Response{Text: text.String()}, nil
+}
+
+// ollamaProvider talks to a local ollama server's /api/chat endpoint.
+type ollamaProvider struct{ provider }
+
+func (p *ollamaProvider) Name() string { return ProviderOllama }
+
+func (p *ollamaProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	var resp struct {
+		Message Message `json:"message"`
+	}
+	body := map[string]any{
+		"model":    p.model,
+		"messages": messages(req),
+		"stream":   false,
+		"options":  map[string]any{"num_predict": maxTokens(req), "temperature": req.Temperature},
+	}
+	if err := postJSON(ctx, p.client, strings.TrimRight(p.baseURL, "/")+"/api/chat", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &Response{Text: resp.Message.Content}, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response from %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return nil
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Feature: LLM_PROVIDER
+// Spec: spec/system/llm.md
+
+var request = Request{
+	System:   "Be brief.",
+	Messages: []Message{{Role: RoleUser, Content: "Hi"}},
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req struct {
+			Model     string    `json:"model"`
+			Messages  []Message `json:"messages"`
+			MaxTokens int       `json:"max_tokens"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, DefaultOpenAIModel, req.Model)
+		assert.Equal(t, []Message{{Role: "system", Content: "Be brief."}, {Role: RoleUser, Content: "Hi"}}, req.Messages)
+		assert.Equal(t, DefaultMaxTokens, req.MaxTokens)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Hello"}}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Provider: ProviderOpenAI, BaseURL: srv.URL + "/v1/", APIKey: "secret"})
+	require.NoError(t, err)
+	resp, err := p.Complete(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Text)
+}
+
+func TestAnthropicProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("x-api-key"))
+		assert.Equal(t, "2023-06-01", r.Header.Get("anthropic-version"))
+		var req struct {
+			System   string    `json:"system"`
+			Messages []Message `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Be brief.", req.System)
+		assert.Equal(t, request.Messages, req.Messages)
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"Hel"},{"type":"text","text":"lo"}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Provider: ProviderAnthropic, BaseURL: srv.URL, APIKey: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAnthropicModel, p.Model())
+	resp, err := p.Complete(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Text)
+}
+
+func TestOllamaProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.False(t, req.Stream)
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"Hello"}}`))
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Provider: ProviderOllama, BaseURL: srv.URL, Model: "qwen"})
+	require.NoError(t, err)
+	assert.Equal(t, "qwen", p.Model())
+	resp, err := p.Complete(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Text)
+}
+
+func TestProvider_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	p, err := New(Config{Provider: ProviderOpenAI, BaseURL: srv.URL})
+	require.NoError(t, err)
+	_, err = p.Complete(context.Background(), request)
+	assert.ErrorContains(t, err, "bad key")
+
+	_, err = New(Config{Provider: "gpt"})
+	assert.Error(t, err)
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Setenv("CORTEX_LLM_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "o")
+	t.Setenv("ANTHROPIC_API_KEY", "a")
+	assert.Equal(t, "o", APIKey(ProviderOpenAI))
+	assert.Equal(t, "a", APIKey(ProviderAnthropic))
+	assert.Equal(t, "", APIKey(ProviderOllama))
+	t.Setenv("CORTEX_LLM_API_KEY", "c")
+	assert.Equal(t, "c", APIKey(ProviderAnthropic))
+}
+
+// echo answers with the last message and counts calls.
+type echo struct{ calls int }
+
+func (e *echo) Name() string  { return "echo" }
+func (e *echo) Model() string { return "v1" }
+func (e *echo) Complete(_ context.Context, req Request) (*Response, error) {
+	e.calls++
+	return &Response{Text: req.Messages[len(req.Messages)-1].Content}, nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "llm.json")
+	e := &echo{}
+	rec, err := Record(e, path)
+	require.NoError(t, err)
+	assert.Equal(t, "echo", rec.Name())
+	other := Request{Messages: []Message{{Role: RoleUser, Content: "Bye"}}}
+	for _, req := range []Request{request, other} {
+		_, err := rec.Complete(context.Background(), req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, e.calls)
+
+	c, err := ReadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, c.Exchanges, 2)
+	assert.Less(t, c.Exchanges[0].Key, c.Exchanges[1].Key)
+	assert.Equal(t, "v1", c.Exchanges[0].Model)
+
+	rep, err := Replay(path)
+	require.NoError(t, err)
+	resp, err := rep.Complete(context.Background(), other)
+	require.NoError(t, err)
+	assert.Equal(t, "Bye", resp.Text)
+	_, err = rep.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "new"}}})
+	assert.True(t, errors.Is(err, ErrNotRecorded))
+
+	// Recording again keeps the earlier exchanges.
+	rec, err = Record(&echo{}, path)
+	require.NoError(t, err)
+	_, err = rec.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "new"}}})
+	require.NoError(t, err)
+	c, err = ReadCassette(path)
+	require.NoError(t, err)
+	assert.Len(t, c.Exchanges, 3)
+}
@@ -8,10 +8,77 @@ pub enum BlobBackend {
     Db,
 }
 
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
 pub enum Compression {
     None,
     Zstd,
+    Gzip,
+}
+
+impl Compression {
+    /// Reads `CORTEX_BLOB_COMPRESSION` (`none`, `zstd` or `gzip`); unset or
+    /// empty means `none`.
+    pub fn from_env() -> anyhow::Result<Self> {
+        match std::env::var("CORTEX_BLOB_COMPRESSION")
+            .unwrap_or_default()
+            .trim()
+        {
+            "" | "none" => Ok(Compression::None),
+            "zstd" => Ok(Compression::Zstd),
+            "gzip" => Ok(Compression::Gzip),
+            other => Err(anyhow::anyhow!(
+                "Invalid CORTEX_BLOB_COMPRESSION {:?} (expected none, zstd or gzip)",
+                other
+            )),
+        }
+    }
+}
+
+/// Default of `FilePolicy::max_file_bytes`, the XRAY LOC cap.
+pub const DEFAULT_MAX_FILE_BYTES: u64 = 2 * 1024 * 1024;
+
+/// Binary and large-file policy of the snapshot tools. Files larger than
+/// `max_file_bytes` are captured as metadata only (path, size and content
+/// hash) and their content is never read into memory whole.
+#[derive(Debug, Clone, Copy, PartialEq, Serialize, Deserialize)]
+pub struct FilePolicy {
+    /// Size limit in bytes; 0 means no limit.
+    pub max_file_bytes: u64,
+}
+
+impl Default for FilePolicy {
+    fn default() -> Self {
+        Self {
+            max_file_bytes: DEFAULT_MAX_FILE_BYTES,
+        }
+    }
+}
+
+impl FilePolicy {
+    /// Reads `CORTEX_MAX_FILE_BYTES`; unset or empty means the default and
+    /// `0` disables the limit.
+    pub fn from_env() -> anyhow::Result<Self> {
+        match std::env::var("CORTEX_MAX_FILE_BYTES")
+            .unwrap_or_default()
+            .trim()
+        {
+            "" => Ok(Self::default()),
+            other => other
+                .parse()
+                .map(|max_file_bytes| Self { max_file_bytes })
+                .map_err(|_| {
+                    anyhow::anyhow!(
+                        "Invalid CORTEX_MAX_FILE_BYTES {:?} (expected a byte count)",
+                        other
+                    )
+                }),
+        }
+    }
+
+    /// Whether a file of `size` bytes is over the limit.
+    pub fn is_large(&self, size: u64) -> bool {
+        self.max_file_bytes > 0 && size > self.max_file_bytes
+    }
 }
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
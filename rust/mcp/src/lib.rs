@@ -1,7 +1,9 @@
 pub mod config;
 pub mod io;
+pub mod prompts;
 pub mod protocol;
 pub mod resolver;
+pub mod resources;
 pub mod router;
 pub mod skills;
 pub mod snapshot;
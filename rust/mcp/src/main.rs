@@ -44,20 +44,27 @@ fn main() -> Result<()> {
 
     // 2. Setup Stores & Tools
     // Persistent store
-    let config = cortex_mcp::config::StorageConfig::default();
+    let mut config = cortex_mcp::config::StorageConfig::default();
+    config.compression = cortex_mcp::config::Compression::from_env()?;
     log::info!(
-        "[cortex-mcp] storage data_dir: {}",
-        config.data_dir.display()
+        "[cortex-mcp] storage data_dir: {} compression: {:?}",
+        config.data_dir.display(),
+        config.compression
     );
     let store = Arc::new(cortex_mcp::snapshot::store::Store::new(config)?);
 
     // LeaseStore (currently in-memory, Option A)
     let lease_store = Arc::new(cortex_mcp::snapshot::lease::LeaseStore::new());
 
-    let snapshot_tools = Arc::new(cortex_mcp::snapshot::tools::SnapshotTools::new(
-        lease_store.clone(),
-        store.clone(),
-    ));
+    let file_policy = cortex_mcp::config::FilePolicy::from_env()?;
+    log::info!(
+        "[cortex-mcp] file policy: max_file_bytes {}",
+        file_policy.max_file_bytes
+    );
+    let snapshot_tools = Arc::new(
+        cortex_mcp::snapshot::tools::SnapshotTools::new(lease_store.clone(), store.clone())
+            .with_file_policy(file_policy),
+    );
 
     let workspace_tools = Arc::new(cortex_mcp::workspace::WorkspaceTools::new(
         lease_store.clone(),
@@ -0,0 +1,498 @@
+// Feature: MCP_PROMPTS
+// Spec: spec/mcp/prompts.md
+
+//! Prompt templates for governance workflows (`prompts/list`, `prompts/get`).
+//!
+//! Prompts are rendered from the data the CLI reports use: the feature
+//! traceability report written by `cortex reports feature-traceability` and
+//! the snapshot store. The same report and snapshots always render the same
+//! text, so a prompt can be reproduced from its arguments alone.
+
+use crate::router::CortexError;
+use crate::snapshot::tools::SnapshotTools;
+use anyhow::Result;
+use serde::Deserialize;
+use serde_json::{json, Value};
+use std::collections::BTreeMap;
+use std::fmt::Write;
+use std::path::PathBuf;
+
+/// Feature traceability report, relative to the repository root.
+pub const TRACEABILITY_REPORT: &str = ".cortex/reports/feature-traceability.json";
+
+/// Budget for the spec text and for the diff of a prompt; the rest is cut
+/// at a file or line boundary and the cut is noted in the prompt.
+pub const MAX_SECTION_BYTES: usize = 256 * 1024;
+
+/// Commit types accepted by the commit health report.
+const COMMIT_TYPES: &str = "chore, ci, docs, feat, fix, refactor, test";
+
+/// Longest commit header accepted by the commit health report.
+const MAX_HEADER_LENGTH: usize = 72;
+
+/// Prompts served, sorted by name: name, description, arguments.
+const PROMPTS: &[(&str, &str, &[(&str, &str, bool)])] = &[
+    (
+        "draft_commit_message",
+        "Draft a Conventional Commit message for the changes in a snapshot.",
+        &[
+            ("snapshot_id", "Snapshot holding the changes.", true),
+            (
+                "from_snapshot_id",
+                "Base snapshot (default: the snapshot's parent).",
+                false,
+            ),
+            (
+                "feature_id",
+                "Commit scope (default: the feature touching the most files).",
+                false,
+            ),
+        ],
+    ),
+    (
+        "review_diff_against_spec",
+        "Review the changes in a snapshot against a feature's spec.",
+        &[
+            (
+                "feature_id",
+                "Feature whose spec the changes must follow.",
+                true,
+            ),
+            ("snapshot_id", "Snapshot holding the changes.", true),
+            (
+                "from_snapshot_id",
+                "Base snapshot (default: the snapshot's parent).",
+                false,
+            ),
+        ],
+    ),
+];
+
+/// Subset of the feature traceability report used by the prompts.
+#[derive(Deserialize)]
+struct Report {
+    #[serde(default)]
+    features: BTreeMap<String, Feature>,
+}
+
+#[derive(Deserialize)]
+struct Feature {
+    #[serde(default)]
+    status: String,
+    spec: SpecInfo,
+    implementation: FilesInfo,
+    tests: FilesInfo,
+    #[serde(default)]
+    problems: Option<Vec<Problem>>,
+}
+
+#[derive(Deserialize)]
+struct SpecInfo {
+    #[serde(default)]
+    path: String,
+}
+
+#[derive(Deserialize)]
+struct FilesInfo {
+    #[serde(default)]
+    files: Option<Vec<String>>,
+}
+
+#[derive(Deserialize)]
+struct Problem {
+    code: String,
+    severity: String,
+    message: String,
+}
+
+impl Feature {
+    fn files(&self) -> impl Iterator<Item = &String> {
+        let spec = (!self.spec.path.is_empty()).then_some(&self.spec.path);
+        spec.into_iter()
+            .chain(self.implementation.files.iter().flatten())
+            .chain(self.tests.files.iter().flatten())
+    }
+}
+
+impl Report {
+    /// Maps spec, implementation and test files to their feature, as the
+    /// commit draft report does.
+    fn index(&self) -> BTreeMap<&str, &str> {
+        let mut index = BTreeMap::new();
+        for (id, feature) in &self.features {
+            for path in feature.files() {
+                index.insert(path.as_str(), id.as_str());
+            }
+        }
+        index
+    }
+
+    fn feature(&self, id: &str) -> Result<&Feature> {
+        self.features.get(id).ok_or_else(|| {
+            CortexError::NotFound(format!("Feature {} is not in {}", id, TRACEABILITY_REPORT))
+                .into()
+        })
+    }
+}
+
+/// A file changed between two snapshots.
+struct Change {
+    path: String,
+    kind: String,
+}
+
+/// Changes of `snapshot_id` against its base, with their unified diffs.
+struct ChangeSet {
+    snapshot_id: String,
+    base_id: String,
+    changes: Vec<Change>,
+    diff: String,
+}
+
+/// Serves the prompts of the repository at `root`.
+pub struct Prompts {
+    root: PathBuf,
+}
+
+impl Prompts {
+    pub fn new(root: impl Into<PathBuf>) -> Self {
+        Self { root: root.into() }
+    }
+
+    /// Result of `prompts/list`.
+    pub fn list(&self) -> Value {
+        let prompts: Vec<Value> = PROMPTS
+            .iter()
+            .map(|(name, description, args)| {
+                let arguments: Vec<Value> = args
+                    .iter()
+                    .map(|(name, description, required)| {
+                        json!({ "name": name, "description": description, "required": required })
+                    })
+                    .collect();
+                json!({ "name": name, "description": description, "arguments": arguments })
+            })
+            .collect();
+        json!({ "prompts": prompts })
+    }
+
+    /// Result of `prompts/get`. `args` holds the prompt arguments as strings.
+    pub fn get(&self, name: &str, args: &Value, snapshots: &SnapshotTools) -> Result<Value> {
+        let (_, description, params) = PROMPTS
+            .iter()
+            .find(|(n, _, _)| *n == name)
+            .ok_or_else(|| CortexError::NotFound(format!("Prompt not found: {}", name)))?;
+        for (arg, _, required) in params.iter() {
+            if *required && string_arg(args, arg).is_none() {
+                return Err(CortexError::InvalidArgument(format!(
+                    "Prompt {} requires argument {}",
+                    name, arg
+                ))
+                .into());
+            }
+        }
+
+        let text = match name {
+            "draft_commit_message" => self.draft_commit_message(args, snapshots)?,
+            _ => self.review_diff_against_spec(args, snapshots)?,
+        };
+        Ok(json!({
+            "description": description,
+            "messages": [{ "role": "user", "content": { "type": "text", "text": text } }]
+        }))
+    }
+
+    fn review_diff_against_spec(&self, args: &Value, snapshots: &SnapshotTools) -> Result<String> {
+        let report = self.report()?;
+        let feature_id = string_arg(args, "feature_id").unwrap_or_default();
+        let feature = report.feature(feature_id)?;
+        let set = self.changes(args, snapshots)?;
+        let index = report.index();
+
+        let mut out = String::new();
+        writeln!(
+            out,
+            "Review the changes in snapshot {} (base {}) against the spec of feature {}.",
+            set.snapshot_id, set.base_id, feature_id
+        )?;
+        writeln!(
+            out,
+            "List every change that contradicts the spec, behavior the spec does not describe, \
+             and spec requirements the changes leave unimplemented or untested. \
+             Cite the spec section and the file for each finding."
+        )?;
+
+        writeln!(out, "\n## Feature {}\n", feature_id)?;
+        writeln!(out, "- Status: {}", or_none(&feature.status))?;
+        writeln!(out, "- Spec: {}", or_none(&feature.spec.path))?;
+        writeln!(
+            out,
+            "- Implementation: {}",
+            or_none(&join(feature.implementation.files.iter().flatten()))
+        )?;
+        writeln!(
+            out,
+            "- Tests: {}",
+            or_none(&join(feature.tests.files.iter().flatten()))
+        )?;
+        let problems = feature.problems.as_deref().unwrap_or_default();
+        if !problems.is_empty() {
+            writeln!(out, "\nOpen traceability problems:\n")?;
+            for p in problems {
+                writeln!(out, "- [{}] {}: {}", p.severity, p.code, p.message)?;
+            }
+        }
+
+        if !feature.spec.path.is_empty() {
+            writeln!(out, "\n## Spec ({})\n", feature.spec.path)?;
+            let spec = std::fs::read(self.root.join(&feature.spec.path)).map_err(|e| {
+                CortexError::NotFound(format!("Cannot read spec {}: {}", feature.spec.path, e))
+            })?;
+            writeln!(out, "{}", truncate(&String::from_utf8_lossy(&spec)))?;
+        }
+
+        writeln!(out, "\n## Changed files\n")?;
+        for c in &set.changes {
+            let owner = index.get(c.path.as_str()).copied().unwrap_or("");
+            let mark = if owner == feature_id {
+                " (this feature)"
+            } else {
+                ""
+            };
+            writeln!(out, "- {} {}{}", c.kind, c.path, mark)?;
+        }
+        write_diff(&mut out, &set)?;
+        Ok(out)
+    }
+
+    fn draft_commit_message(&self, args: &Value, snapshots: &SnapshotTools) -> Result<String> {
+        let report = self.report()?;
+        let set = self.changes(args, snapshots)?;
+        let index = report.index();
+
+        // Most-touched feature, ties broken lexicographically.
+        let mut counts: BTreeMap<&str, usize> = BTreeMap::new();
+        for c in &set.changes {
+            if let Some(id) = index.get(c.path.as_str()) {
+                *counts.entry(*id).or_default() += 1;
+            }
+        }
+        let mut primary: Option<(&str, usize)> = None;
+        for (id, n) in &counts {
+            if primary.map_or(true, |(_, best)| *n > best) {
+                primary = Some((*id, *n));
+            }
+        }
+
+        let mut out = String::new();
+        writeln!(
+            out,
+            "Draft a commit message for the changes in snapshot {} (base {}).",
+            set.snapshot_id, set.base_id
+        )?;
+        writeln!(
+            out,
+            "The header must be `<type>(<FEATURE_ID>): <summary>`, at most {} characters, \
+             with type one of {}. Follow it with a blank line and a body that says what \
+             changed and why, wrapped at 72 columns.",
+            MAX_HEADER_LENGTH, COMMIT_TYPES
+        )?;
+        match (string_arg(args, "feature_id"), primary) {
+            (Some(id), _) => {
+                report.feature(id)?;
+                writeln!(out, "\nScope: {}", id)?;
+            }
+            (None, Some((id, n))) => writeln!(
+                out,
+                "\nScope: {} (touches {} of {} changed files)",
+                id,
+                n,
+                set.changes.len()
+            )?,
+            (None, None) => writeln!(
+                out,
+                "\nNo changed file belongs to a feature; pick the scope from the diff."
+            )?,
+        }
+
+        writeln!(out, "\n## Changed files\n")?;
+        for c in &set.changes {
+            match index.get(c.path.as_str()) {
+                Some(id) => writeln!(out, "- {} {} ({})", c.kind, c.path, id)?,
+                None => writeln!(out, "- {} {}", c.kind, c.path)?,
+            }
+        }
+        write_diff(&mut out, &set)?;
+        Ok(out)
+    }
+
+    fn report(&self) -> Result<Report> {
+        let path = self.root.join(TRACEABILITY_REPORT);
+        if !path.is_file() {
+            return Err(CortexError::NotFound(format!(
+                "No feature traceability report at {}; run `cortex reports feature-traceability`",
+                TRACEABILITY_REPORT
+            ))
+            .into());
+        }
+        let data = std::fs::read(path)?;
+        serde_json::from_slice(&data).map_err(|e| {
+            CortexError::Internal(format!("Invalid {}: {}", TRACEABILITY_REPORT, e)).into()
+        })
+    }
+
+    /// Changes between `snapshot_id` and `from_snapshot_id`, or the parent
+    /// the snapshot was derived from.
+    fn changes(&self, args: &Value, snapshots: &SnapshotTools) -> Result<ChangeSet> {
+        let snapshot_id = string_arg(args, "snapshot_id").unwrap_or_default();
+        let base_id = match string_arg(args, "from_snapshot_id") {
+            Some(id) => id.to_string(),
+            None => snapshots.snapshot_info(&self.root, Some(snapshot_id.to_string()))?
+                ["derived_from"]
+                .as_str()
+                .ok_or_else(|| {
+                    CortexError::InvalidArgument(format!(
+                        "Snapshot {} has no parent; pass from_snapshot_id",
+                        snapshot_id
+                    ))
+                })?
+                .to_string(),
+        };
+
+        let res = snapshots.snapshot_changes(
+            &self.root,
+            Some(snapshot_id.to_string()),
+            Some(base_id.clone()),
+        )?;
+        let mut set = ChangeSet {
+            snapshot_id: snapshot_id.to_string(),
+            base_id,
+            changes: Vec::new(),
+            diff: String::new(),
+        };
+        for c in res["files_changed"].as_array().into_iter().flatten() {
+            let path = c["path"].as_str().unwrap_or_default().to_string();
+            let diff = snapshots.snapshot_diff(
+                &self.root,
+                &path,
+                "snapshot",
+                None,
+                Some(set.snapshot_id.clone()),
+                Some(set.base_id.clone()),
+            )?;
+            append_within_budget(&mut set.diff, diff["diff"].as_str().unwrap_or_default());
+            set.changes.push(Change {
+                path,
+                kind: c["type"].as_str().unwrap_or_default().to_string(),
+            });
+        }
+        Ok(set)
+    }
+}
+
+fn string_arg<'a>(args: &'a Value, name: &str) -> Option<&'a str> {
+    args.get(name)
+        .and_then(|v| v.as_str())
+        .filter(|s| !s.is_empty())
+}
+
+fn or_none(s: &str) -> &str {
+    if s.is_empty() {
+        "(none)"
+    } else {
+        s
+    }
+}
+
+fn join<'a>(items: impl Iterator<Item = &'a String>) -> String {
+    items.map(String::as_str).collect::<Vec<_>>().join(", ")
+}
+
+/// Appends one file's diff while the total stays within MAX_SECTION_BYTES;
+/// once a diff does not fit, the rest are replaced by a single note.
+fn append_within_budget(diff: &mut String, file_diff: &str) {
+    if diff.ends_with(OMITTED) {
+        return;
+    }
+    if diff.len() + file_diff.len() <= MAX_SECTION_BYTES {
+        diff.push_str(file_diff);
+        // Binary notes and files without a final newline end mid-line.
+        if !diff.is_empty() && !diff.ends_with('\n') {
+            diff.push('\n');
+        }
+    } else {
+        diff.push_str(OMITTED);
+    }
+}
+
+const OMITTED: &str = "\n[diff truncated: read the remaining files with snapshot.diff]\n";
+
+fn write_diff(out: &mut String, set: &ChangeSet) -> Result<()> {
+    writeln!(out, "\n## Diff\n")?;
+    if set.diff.is_empty() {
+        writeln!(out, "(no textual changes)")?;
+    } else {
+        writeln!(out, "```diff\n{}```", set.diff)?;
+    }
+    Ok(())
+}
+
+/// Cuts `text` to MAX_SECTION_BYTES at a line boundary.
+fn truncate(text: &str) -> String {
+    if text.len() <= MAX_SECTION_BYTES {
+        return text.to_string();
+    }
+    let cut = text.as_bytes()[..MAX_SECTION_BYTES]
+        .iter()
+        .rposition(|&b| b == b'\n')
+        .map_or(0, |i| i + 1);
+    format!(
+        "{}[truncated: {} of {} bytes shown]\n",
+        &text[..cut],
+        cut,
+        text.len()
+    )
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_list_is_sorted() {
+        let res = Prompts::new(".").list();
+        let names: Vec<&str> = res["prompts"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .map(|p| p["name"].as_str().unwrap())
+            .collect();
+        let mut sorted = names.clone();
+        sorted.sort();
+        assert_eq!(names, sorted);
+    }
+
+    #[test]
+    fn test_truncate_cuts_at_line_boundary() {
+        let line = "x".repeat(1023) + "\n";
+        let text = line.repeat(MAX_SECTION_BYTES / 1024 + 1);
+        let out = truncate(&text);
+        assert!(out.starts_with(&line.repeat(MAX_SECTION_BYTES / 1024)));
+        assert!(out.ends_with(&format!(
+            "[truncated: {} of {} bytes shown]\n",
+            MAX_SECTION_BYTES,
+            text.len()
+        )));
+    }
+
+    #[test]
+    fn test_diff_budget_omits_remaining_files() {
+        let mut diff = String::new();
+        append_within_budget(&mut diff, "Binary files a/x and b/x differ");
+        assert_eq!(diff, "Binary files a/x and b/x differ\n");
+        append_within_budget(&mut diff, &"y".repeat(MAX_SECTION_BYTES));
+        append_within_budget(&mut diff, "small\n");
+        assert!(diff.ends_with(OMITTED));
+        assert!(!diff.contains("small"));
+    }
+}
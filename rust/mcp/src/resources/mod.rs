@@ -0,0 +1,273 @@
+// Feature: MCP_RESOURCES
+// Spec: spec/mcp/resources.md
+
+//! Read-only repository resources (`resources/list`, `resources/read`).
+//!
+//! Resources are the context artifacts Cortex generates or governs: the XRAY
+//! index, the feature registry and the generated docs. Each is addressed by
+//! `cortex://repo/<repo-relative path>` and versioned by the SHA-256 of its
+//! content, so a client can cache it until the version changes.
+
+use crate::router::CortexError;
+use anyhow::Result;
+use base64::Engine;
+use serde_json::{json, Value};
+use sha2::{Digest, Sha256};
+use std::path::{Path, PathBuf};
+
+/// URI prefix of every resource.
+pub const URI_PREFIX: &str = "cortex://repo/";
+
+/// Largest resource served; larger files are `TOO_LARGE`.
+pub const MAX_RESOURCE_BYTES: u64 = 16 * 1024 * 1024;
+
+/// Directory whose files are all exposed, recursively.
+const GENERATED_DOCS: &str = "docs/__generated__";
+
+/// Single files exposed when present: path, name, description.
+const ARTIFACTS: &[(&str, &str, &str)] = &[
+    (
+        ".cortex/data/index.json",
+        "XRAY index",
+        "Repository index written by `cortex context xray scan`: files, languages and digests.",
+    ),
+    (
+        "spec/features.yaml",
+        "Feature registry",
+        "Features with their specs, owners, status and dependencies.",
+    ),
+];
+
+struct Resource {
+    path: String,
+    name: String,
+    description: String,
+}
+
+/// Serves the resources of the repository at `root`.
+pub struct Resources {
+    root: PathBuf,
+}
+
+impl Resources {
+    pub fn new(root: impl Into<PathBuf>) -> Self {
+        Self { root: root.into() }
+    }
+
+    /// Resources that currently exist, sorted by URI.
+    fn catalog(&self) -> Result<Vec<Resource>> {
+        let mut out = Vec::new();
+        for (path, name, description) in ARTIFACTS {
+            if self.root.join(path).is_file() {
+                out.push(Resource {
+                    path: path.to_string(),
+                    name: name.to_string(),
+                    description: description.to_string(),
+                });
+            }
+        }
+
+        let docs = self.root.join(GENERATED_DOCS);
+        if docs.is_dir() {
+            // Symlinks are not followed, so every resource stays inside root.
+            for entry in walkdir::WalkDir::new(&docs).sort_by_file_name() {
+                let entry = entry?;
+                if !entry.file_type().is_file() {
+                    continue;
+                }
+                let rel = entry
+                    .path()
+                    .strip_prefix(&self.root)?
+                    .components()
+                    .map(|c| c.as_os_str().to_string_lossy())
+                    .collect::<Vec<_>>()
+                    .join("/");
+                let name = rel.strip_prefix("docs/").unwrap_or(&rel).to_string();
+                out.push(Resource {
+                    path: rel,
+                    name,
+                    description: "Generated documentation".to_string(),
+                });
+            }
+        }
+
+        out.sort_by(|a, b| a.path.cmp(&b.path));
+        Ok(out)
+    }
+
+    /// Result of `resources/list`. Files over `MAX_RESOURCE_BYTES` are left
+    /// out, so one oversized doc does not hide the others.
+    pub fn list(&self) -> Result<Value> {
+        let mut resources = Vec::new();
+        for r in self.catalog()? {
+            if std::fs::metadata(self.root.join(&r.path))?.len() > MAX_RESOURCE_BYTES {
+                continue;
+            }
+            let data = self.load(&r.path)?;
+            resources.push(json!({
+                "uri": format!("{}{}", URI_PREFIX, r.path),
+                "name": r.name,
+                "description": r.description,
+                "mimeType": mime_type(&r.path),
+                "size": data.len(),
+                "_meta": { "version": version(&data) }
+            }));
+        }
+        Ok(json!({ "resources": resources }))
+    }
+
+    /// Result of `resources/read`. Only listed resources can be read.
+    pub fn read(&self, uri: &str) -> Result<Value> {
+        let path = uri.strip_prefix(URI_PREFIX).ok_or_else(|| {
+            CortexError::InvalidArgument(format!("Unsupported resource URI: {}", uri))
+        })?;
+        if !self.catalog()?.iter().any(|r| r.path == path) {
+            return Err(CortexError::NotFound(format!("Resource not found: {}", uri)).into());
+        }
+
+        let data = self.load(path)?;
+        let mut content = json!({
+            "uri": uri,
+            "mimeType": mime_type(path),
+            "_meta": { "version": version(&data) }
+        });
+        match String::from_utf8(data) {
+            Ok(text) => content["text"] = json!(text),
+            Err(e) => {
+                content["blob"] =
+                    json!(base64::engine::general_purpose::STANDARD.encode(e.into_bytes()))
+            }
+        }
+        Ok(json!({ "contents": [content] }))
+    }
+
+    fn load(&self, path: &str) -> Result<Vec<u8>> {
+        let full = self.root.join(path);
+        let size = std::fs::metadata(&full)?.len();
+        if size > MAX_RESOURCE_BYTES {
+            return Err(CortexError::TooLarge(format!(
+                "Resource {} is {} bytes (limit {})",
+                path, size, MAX_RESOURCE_BYTES
+            ))
+            .into());
+        }
+        Ok(std::fs::read(full)?)
+    }
+}
+
+/// Version of a resource: the SHA-256 of its content.
+fn version(data: &[u8]) -> String {
+    format!("sha256:{}", hex::encode(Sha256::digest(data)))
+}
+
+fn mime_type(path: &str) -> &'static str {
+    match Path::new(path).extension().and_then(|e| e.to_str()) {
+        Some("json") => "application/json",
+        Some("yaml") | Some("yml") => "application/yaml",
+        Some("md") => "text/markdown",
+        Some("ndjson") => "application/x-ndjson",
+        _ => "text/plain",
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn repo() -> tempfile::TempDir {
+        let dir = tempfile::tempdir().unwrap();
+        let root = dir.path();
+        std::fs::create_dir_all(root.join("spec")).unwrap();
+        std::fs::write(root.join("spec/features.yaml"), "features: []\n").unwrap();
+        std::fs::create_dir_all(root.join("docs/__generated__/context")).unwrap();
+        std::fs::write(root.join("docs/__generated__/context/b.md"), "# B\n").unwrap();
+        std::fs::write(root.join("docs/__generated__/a.md"), "# A\n").unwrap();
+        std::fs::write(root.join("docs/other.md"), "not exposed").unwrap();
+        dir
+    }
+
+    fn code(e: anyhow::Error) -> &'static str {
+        e.downcast_ref::<CortexError>().unwrap().code()
+    }
+
+    #[test]
+    fn test_list_is_sorted_and_versioned() {
+        let dir = repo();
+        let res = Resources::new(dir.path()).list().unwrap();
+        let uris: Vec<&str> = res["resources"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .map(|r| r["uri"].as_str().unwrap())
+            .collect();
+        assert_eq!(
+            uris,
+            vec![
+                "cortex://repo/docs/__generated__/a.md",
+                "cortex://repo/docs/__generated__/context/b.md",
+                "cortex://repo/spec/features.yaml",
+            ]
+        );
+        let features = &res["resources"][2];
+        assert_eq!(features["mimeType"], "application/yaml");
+        assert_eq!(features["_meta"]["version"], version(b"features: []\n"));
+    }
+
+    #[test]
+    fn test_list_skips_oversized_files() {
+        let dir = repo();
+        let big = std::fs::File::create(dir.path().join("docs/__generated__/big.md")).unwrap();
+        big.set_len(MAX_RESOURCE_BYTES + 1).unwrap();
+
+        let resources = Resources::new(dir.path());
+        let res = resources.list().unwrap();
+        assert_eq!(res["resources"].as_array().unwrap().len(), 3);
+        assert_eq!(
+            code(
+                resources
+                    .read("cortex://repo/docs/__generated__/big.md")
+                    .unwrap_err()
+            ),
+            "TOO_LARGE"
+        );
+    }
+
+    #[test]
+    fn test_read_returns_text_and_version() {
+        let dir = repo();
+        let resources = Resources::new(dir.path());
+        let res = resources
+            .read("cortex://repo/docs/__generated__/a.md")
+            .unwrap();
+        assert_eq!(res["contents"][0]["text"], "# A\n");
+        assert_eq!(res["contents"][0]["_meta"]["version"], version(b"# A\n"));
+
+        std::fs::write(dir.path().join("docs/__generated__/a.md"), "# A2\n").unwrap();
+        let res = resources
+            .read("cortex://repo/docs/__generated__/a.md")
+            .unwrap();
+        assert_eq!(res["contents"][0]["_meta"]["version"], version(b"# A2\n"));
+    }
+
+    #[test]
+    fn test_read_rejects_unlisted_paths() {
+        let dir = repo();
+        let resources = Resources::new(dir.path());
+        assert_eq!(
+            code(resources.read("cortex://repo/docs/other.md").unwrap_err()),
+            "NOT_FOUND"
+        );
+        assert_eq!(
+            code(
+                resources
+                    .read("cortex://repo/docs/__generated__/../other.md")
+                    .unwrap_err()
+            ),
+            "NOT_FOUND"
+        );
+        assert_eq!(
+            code(resources.read("file:///etc/passwd").unwrap_err()),
+            "INVALID_ARGUMENT"
+        );
+    }
+}
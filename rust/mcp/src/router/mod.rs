@@ -41,6 +41,9 @@ pub enum CortexError {
     RepoChanged(String),
     PermissionDenied(String),
     TooLarge(String),
+    /// Stored data failed an integrity check (missing, undecodable or
+    /// hash mismatch).
+    Corrupt(String),
     Internal(String),
 }
 
@@ -52,6 +55,7 @@ impl CortexError {
             CortexError::RepoChanged(_) => "REPO_CHANGED",
             CortexError::PermissionDenied(_) => "PERMISSION_DENIED",
             CortexError::TooLarge(_) => "TOO_LARGE",
+            CortexError::Corrupt(_) => "CORRUPT",
             CortexError::Internal(_) => "INTERNAL",
         }
     }
@@ -63,6 +67,7 @@ impl CortexError {
             | CortexError::RepoChanged(m)
             | CortexError::PermissionDenied(m)
             | CortexError::TooLarge(m)
+            | CortexError::Corrupt(m)
             | CortexError::Internal(m) => m.as_str(),
         }
     }
@@ -75,6 +80,9 @@ impl std::fmt::Display for CortexError {
 }
 
 impl std::error::Error for CortexError {}
+use crate::prompts::Prompts;
+use crate::resources::Resources;
+use crate::snapshot::search::GrepOptions;
 use crate::snapshot::tools::SnapshotTools;
 use crate::workspace::WorkspaceTools;
 
@@ -83,6 +91,8 @@ pub struct Router {
     mounts: MountRegistry,
     snapshot_tools: Arc<SnapshotTools>,
     workspace_tools: Arc<WorkspaceTools>,
+    resources: Resources,
+    prompts: Prompts,
 }
 
 impl Router {
@@ -97,9 +107,23 @@ impl Router {
             mounts,
             snapshot_tools,
             workspace_tools,
+            resources: Resources::new("."),
+            prompts: Prompts::new("."),
         }
     }
 
+    /// Serves resources from `resources` instead of the working directory.
+    pub fn with_resources(mut self, resources: Resources) -> Self {
+        self.resources = resources;
+        self
+    }
+
+    /// Renders prompts from `prompts` instead of the working directory.
+    pub fn with_prompts(mut self, prompts: Prompts) -> Self {
+        self.prompts = prompts;
+        self
+    }
+
     pub fn handle_request(&self, req: &JsonRpcRequest) -> JsonRpcResponse {
         match req.method.as_str() {
             "initialize" => json_rpc_ok(
@@ -143,7 +167,7 @@ impl Router {
                         },
                         {
                             "name": "snapshot.list",
-                            "description": "List files in a snapshot or worktree. Supports pagination via limit/offset.",
+                            "description": "List files in a snapshot or worktree. Pages hold at most 1000 entries; pass next_cursor back as cursor to continue.",
                             "inputSchema": {
                                 "type": "object",
                                 "properties": {
@@ -152,8 +176,9 @@ impl Router {
                                     "mode": { "type": "string", "enum": ["worktree", "snapshot"] },
                                     "lease_id": { "type": "string" },
                                     "snapshot_id": { "type": "string" },
-                                    "limit": { "type": "integer" },
-                                    "offset": { "type": "integer" }
+                                    "limit": { "type": "integer", "minimum": 1, "maximum": 1000 },
+                                    "offset": { "type": "integer" },
+                                    "cursor": { "type": "string" }
                                 },
                                 "required": ["repo_root", "path", "mode"]
                             }
@@ -175,7 +200,7 @@ impl Router {
                         },
                         {
                             "name": "snapshot.grep",
-                            "description": "Search for a pattern",
+                            "description": "Search for an RE2 pattern, with the semantics of cortex grep. Pages hold at most page_size matching lines (default 100, max 1000); pass next_cursor back as cursor to continue. max_matches caps the matches over all pages.",
                             "inputSchema": {
                                 "type": "object",
                                 "properties": {
@@ -185,7 +210,15 @@ impl Router {
                                     "mode": { "type": "string", "enum": ["worktree", "snapshot"] },
                                     "lease_id": { "type": "string" },
                                     "snapshot_id": { "type": "string" },
-                                    "case_insensitive": { "type": "boolean" }
+                                    "case": { "type": "string", "enum": ["sensitive", "insensitive", "smart"] },
+                                    "case_insensitive": { "type": "boolean" },
+                                    "fixed_strings": { "type": "boolean" },
+                                    "include": { "type": "array", "items": { "type": "string" } },
+                                    "exclude": { "type": "array", "items": { "type": "string" } },
+                                    "max_matches": { "type": "integer", "minimum": 0 },
+                                    "context": { "type": "integer", "minimum": 0 },
+                                    "page_size": { "type": "integer", "minimum": 1, "maximum": 1000 },
+                                    "cursor": { "type": "string" }
                                 },
                                 "required": ["repo_root", "pattern", "mode"]
                             }
@@ -290,6 +323,29 @@ impl Router {
                                 },
                                 "required": ["repo_root", "snapshot_id"]
                             }
+                        },
+                        {
+                            "name": "snapshot.history",
+                            "description": "Walk a snapshot's provenance chain (parent, creating tool, patch digest, base head_sha) back to its root",
+                            "inputSchema": {
+                                "type": "object",
+                                "properties": {
+                                    "repo_root": { "type": "string" },
+                                    "snapshot_id": { "type": "string" }
+                                },
+                                "required": ["snapshot_id"]
+                            }
+                        },
+                        {
+                            "name": "snapshot.verify",
+                            "description": "Revalidate all stored blobs: presence, size, codec and content hash",
+                            "inputSchema": {
+                                "type": "object",
+                                "properties": {
+                                    "repo_root": { "type": "string" }
+                                },
+                                "required": []
+                            }
                         }
                     ]
                 }),
@@ -400,6 +456,7 @@ impl Router {
                             .get("offset")
                             .and_then(|v| v.as_u64())
                             .map(|u| u as usize);
+                        let cursor = args.get("cursor").and_then(|s| s.as_str());
 
                         if let (Some(root), Some(p), Some(m)) = (repo_root, path, mode) {
                             match self.snapshot_tools.snapshot_list(
@@ -410,6 +467,7 @@ impl Router {
                                 snapshot_id.map(|s| s.to_string()),
                                 limit,
                                 offset,
+                                cursor.map(|s| s.to_string()),
                             ) {
                                 Ok(res) => json_rpc_ok(
                                     req.id.clone(),
@@ -471,10 +529,16 @@ impl Router {
                         let mode = args.get("mode").and_then(|s| s.as_str());
                         let lease_id = args.get("lease_id").and_then(|s| s.as_str());
                         let snapshot_id = args.get("snapshot_id").and_then(|s| s.as_str());
-                        let case_insensitive = args
-                            .get("case_insensitive")
-                            .and_then(|b| b.as_bool())
-                            .unwrap_or(false);
+                        let page_size = args
+                            .get("page_size")
+                            .and_then(|v| v.as_u64())
+                            .map(|u| u as usize);
+                        let cursor = args.get("cursor").and_then(|s| s.as_str());
+
+                        let opts = match GrepOptions::from_args(args) {
+                            Ok(opts) => opts,
+                            Err(e) => return map_error(req.id.clone(), e),
+                        };
 
                         if let (Some(root), Some(pat), Some(m)) = (repo_root, pattern, mode) {
                             match self.snapshot_tools.snapshot_grep(
@@ -484,7 +548,9 @@ impl Router {
                                 m,
                                 lease_id.map(|s| s.to_string()),
                                 snapshot_id.map(|s| s.to_string()),
-                                case_insensitive,
+                                &opts,
+                                page_size,
+                                cursor.map(|s| s.to_string()),
                             ) {
                                 Ok(res) => json_rpc_ok(
                                     req.id.clone(),
@@ -582,6 +648,23 @@ impl Router {
                             json_rpc_error(req.id.clone(), -32602, "Missing required arguments")
                         }
                     }
+                    "snapshot.history" => match args.get("snapshot_id").and_then(|s| s.as_str()) {
+                        Some(sid) => match self.snapshot_tools.snapshot_history(sid) {
+                            Ok(res) => json_rpc_ok(
+                                req.id.clone(),
+                                json!({ "content": [{ "type": "json", "json": res }] }),
+                            ),
+                            Err(e) => map_error(req.id.clone(), e),
+                        },
+                        None => json_rpc_error(req.id.clone(), -32602, "Missing snapshot_id"),
+                    },
+                    "snapshot.verify" => match self.snapshot_tools.snapshot_verify() {
+                        Ok(res) => json_rpc_ok(
+                            req.id.clone(),
+                            json!({ "content": [{ "type": "json", "json": res }] }),
+                        ),
+                        Err(e) => map_error(req.id.clone(), e),
+                    },
                     "workspace.apply_patch" => {
                         let repo_root = args.get("repo_root").and_then(|s| s.as_str());
                         let patch = args.get("patch").and_then(|s| s.as_str());
@@ -686,6 +769,40 @@ impl Router {
                     _ => json_rpc_error(req.id.clone(), -32601, "Tool not found"),
                 }
             }
+            "resources/list" => match self.resources.list() {
+                Ok(res) => json_rpc_ok(req.id.clone(), res),
+                Err(e) => map_error(req.id.clone(), e),
+            },
+            "resources/read" => {
+                let uri = req
+                    .params
+                    .as_ref()
+                    .and_then(|p| p.get("uri"))
+                    .and_then(|u| u.as_str());
+                match uri {
+                    Some(uri) => match self.resources.read(uri) {
+                        Ok(res) => json_rpc_ok(req.id.clone(), res),
+                        Err(e) => map_error(req.id.clone(), e),
+                    },
+                    None => json_rpc_error(req.id.clone(), -32602, "Missing uri"),
+                }
+            }
+            "prompts/list" => json_rpc_ok(req.id.clone(), self.prompts.list()),
+            "prompts/get" => {
+                let params = req.params.as_ref();
+                let name = params.and_then(|p| p.get("name")).and_then(|n| n.as_str());
+                let args = params
+                    .and_then(|p| p.get("arguments"))
+                    .cloned()
+                    .unwrap_or_else(|| json!({}));
+                match name {
+                    Some(name) => match self.prompts.get(name, &args, &self.snapshot_tools) {
+                        Ok(res) => json_rpc_ok(req.id.clone(), res),
+                        Err(e) => map_error(req.id.clone(), e),
+                    },
+                    None => json_rpc_error(req.id.clone(), -32602, "Missing name"),
+                }
+            }
             "notifications/initialized" => JsonRpcResponse {
                 jsonrpc: "2.0".into(),
                 result: None,
@@ -704,7 +821,9 @@ impl Router {
 fn get_server_capabilities() -> Value {
     json!({
         "tools": { "listChanged": true },
-        // Add other server capabilities here if needed (e.g. logging, resources)
+        // Versions change with content; clients poll resources/list.
+        "resources": { "subscribe": false, "listChanged": false },
+        "prompts": { "listChanged": false },
     })
 }
 
@@ -0,0 +1,109 @@
+// Feature: MCP_SNAPSHOT_WORKSPACE_SUBSTRATE
+// Spec: spec/mcp/snapshot-workspace-v1.md
+
+//! Blob codecs. A blob is stored encoded by the codec configured when it was
+//! first written; the codec's name is recorded in the `blobs` table so reads
+//! decode it whatever the current configuration is.
+
+use crate::config::Compression;
+use anyhow::Result;
+use std::io::{Read, Write};
+
+pub trait Codec: Send + Sync {
+    /// Name recorded in `blobs.compression`.
+    fn name(&self) -> &'static str;
+    fn encode(&self, data: &[u8]) -> Result<Vec<u8>>;
+    fn decode(&self, data: &[u8]) -> Result<Vec<u8>>;
+}
+
+struct Identity;
+
+impl Codec for Identity {
+    fn name(&self) -> &'static str {
+        "none"
+    }
+
+    fn encode(&self, data: &[u8]) -> Result<Vec<u8>> {
+        Ok(data.to_vec())
+    }
+
+    fn decode(&self, data: &[u8]) -> Result<Vec<u8>> {
+        Ok(data.to_vec())
+    }
+}
+
+struct Zstd;
+
+impl Codec for Zstd {
+    fn name(&self) -> &'static str {
+        "zstd"
+    }
+
+    fn encode(&self, data: &[u8]) -> Result<Vec<u8>> {
+        Ok(zstd::stream::encode_all(data, 3)?) // Level 3 default
+    }
+
+    fn decode(&self, data: &[u8]) -> Result<Vec<u8>> {
+        Ok(zstd::stream::decode_all(data)?)
+    }
+}
+
+struct Gzip;
+
+impl Codec for Gzip {
+    fn name(&self) -> &'static str {
+        "gzip"
+    }
+
+    fn encode(&self, data: &[u8]) -> Result<Vec<u8>> {
+        // Header mtime and OS are fixed by GzEncoder, so output is deterministic.
+        let mut enc = flate2::write::GzEncoder::new(Vec::new(), flate2::Compression::default());
+        enc.write_all(data)?;
+        Ok(enc.finish()?)
+    }
+
+    fn decode(&self, data: &[u8]) -> Result<Vec<u8>> {
+        let mut out = Vec::new();
+        flate2::read::GzDecoder::new(data).read_to_end(&mut out)?;
+        Ok(out)
+    }
+}
+
+static CODECS: [&dyn Codec; 3] = [&Identity, &Zstd, &Gzip];
+
+/// The codec new blobs are written with.
+pub fn for_compression(compression: &Compression) -> &'static dyn Codec {
+    match compression {
+        Compression::None => &Identity,
+        Compression::Zstd => &Zstd,
+        Compression::Gzip => &Gzip,
+    }
+}
+
+/// The codec recorded under `name`, if this build knows it.
+pub fn by_name(name: &str) -> Option<&'static dyn Codec> {
+    CODECS.iter().copied().find(|c| c.name() == name)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_codecs_round_trip() {
+        let data = b"hello hello hello hello\n".repeat(100);
+        for codec in CODECS {
+            let encoded = codec.encode(&data).unwrap();
+            assert_eq!(codec.decode(&encoded).unwrap(), data, "{}", codec.name());
+            assert_eq!(codec.encode(&data).unwrap(), encoded, "{} must be deterministic", codec.name());
+            assert_eq!(by_name(codec.name()).unwrap().name(), codec.name());
+        }
+        assert!(by_name("lz4").is_none());
+    }
+
+    #[test]
+    fn test_decode_garbage_fails() {
+        assert!(by_name("zstd").unwrap().decode(b"not zstd").is_err());
+        assert!(by_name("gzip").unwrap().decode(b"not gzip").is_err());
+    }
+}
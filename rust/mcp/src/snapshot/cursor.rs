@@ -0,0 +1,192 @@
+// Feature: MCP_SNAPSHOT_WORKSPACE_SUBSTRATE
+// Spec: spec/mcp/snapshot-workspace-v1.md
+
+//! Pagination cursors for the list and grep tools.
+//!
+//! A cursor is opaque to clients: `v1.<offset>.<scope>.<digest>`, where
+//! `digest` hashes the tool, its arguments and the offset, and `scope`
+//! hashes the state being paged (the snapshot ID, or the worktree status
+//! hash). A cursor is therefore only accepted for the request that issued
+//! it, and is rejected with `REPO_CHANGED` once the worktree has moved on.
+
+use crate::router::CortexError;
+use anyhow::Result;
+use sha2::{Digest, Sha256};
+
+/// Largest page any tool returns; larger requests are clamped.
+pub const MAX_PAGE_SIZE: usize = 1000;
+/// Default page of `snapshot.list`, in entries.
+pub const DEFAULT_LIST_PAGE_SIZE: usize = 1000;
+/// Default page of `snapshot.grep`, in matching lines.
+pub const DEFAULT_GREP_PAGE_SIZE: usize = 100;
+
+/// Returns the page size for a request, enforcing `MAX_PAGE_SIZE`.
+pub fn page_size(requested: Option<usize>, default: usize) -> Result<usize> {
+    match requested {
+        None => Ok(default),
+        Some(0) => {
+            Err(CortexError::InvalidArgument("Page size must be at least 1".to_string()).into())
+        }
+        Some(n) => Ok(n.min(MAX_PAGE_SIZE)),
+    }
+}
+
+/// The request a cursor is bound to.
+pub struct Query<'a> {
+    pub tool: &'a str,
+    /// Arguments that select the result set, excluding the page size.
+    pub params: Vec<&'a str>,
+    /// State being paged: snapshot ID or worktree status hash.
+    pub scope: &'a str,
+}
+
+impl Query<'_> {
+    fn digest(&self, offset: usize) -> String {
+        let mut hasher = Sha256::new();
+        hasher.update(self.tool.as_bytes());
+        for p in &self.params {
+            hasher.update([0]);
+            hasher.update(p.as_bytes());
+        }
+        hasher.update([0]);
+        hasher.update(offset.to_string().as_bytes());
+        hex::encode(hasher.finalize())[..16].to_string()
+    }
+
+    fn scope_tag(&self) -> String {
+        hex::encode(Sha256::digest(self.scope.as_bytes()))[..8].to_string()
+    }
+
+    /// Cursor of the page starting at `offset`.
+    pub fn cursor(&self, offset: usize) -> String {
+        format!("v1.{}.{}.{}", offset, self.scope_tag(), self.digest(offset))
+    }
+
+    /// Offset encoded in `cursor`, after checking it was issued for this query.
+    pub fn offset(&self, cursor: &str) -> Result<usize> {
+        let invalid =
+            || CortexError::InvalidArgument(format!("Invalid cursor for this request: {}", cursor));
+        let parts: Vec<&str> = cursor.split('.').collect();
+        let ["v1", offset, scope, digest] = parts.as_slice() else {
+            return Err(invalid().into());
+        };
+        let offset: usize = offset.parse().map_err(|_| invalid())?;
+        if *digest != self.digest(offset) {
+            return Err(invalid().into());
+        }
+        if *scope != self.scope_tag() {
+            return Err(CortexError::RepoChanged(
+                "State changed since the cursor was issued; restart from the first page"
+                    .to_string(),
+            )
+            .into());
+        }
+        Ok(offset)
+    }
+
+    /// Start offset of a request: the cursor's, else `offset`, else 0.
+    pub fn start(&self, cursor: Option<&str>, offset: Option<usize>) -> Result<usize> {
+        match cursor {
+            Some(c) => self.offset(c),
+            None => Ok(offset.unwrap_or(0)),
+        }
+    }
+}
+
+/// Picks one page out of a stream of items seen in a stable order.
+pub struct Window {
+    start: usize,
+    size: usize,
+    seen: usize,
+    /// Set once an item past the page has been seen.
+    pub has_more: bool,
+}
+
+impl Window {
+    pub fn new(start: usize, size: usize) -> Self {
+        Self {
+            start,
+            size,
+            seen: 0,
+            has_more: false,
+        }
+    }
+
+    /// Counts the next item and reports whether it is on the page.
+    pub fn admit(&mut self) -> bool {
+        let i = self.seen;
+        self.seen += 1;
+        if i >= self.start + self.size {
+            self.has_more = true;
+            return false;
+        }
+        i >= self.start
+    }
+
+    /// Offset of the next page.
+    pub fn end(&self) -> usize {
+        self.start + self.size
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn query<'a>(scope: &'a str, path: &'a str) -> Query<'a> {
+        Query {
+            tool: "snapshot.list",
+            params: vec!["worktree", path],
+            scope,
+        }
+    }
+
+    #[test]
+    fn test_cursor_round_trip() {
+        let q = query("s1", "src");
+        let c = q.cursor(40);
+        assert_eq!(c, q.cursor(40), "cursors must be deterministic");
+        assert_eq!(q.offset(&c).unwrap(), 40);
+        assert_eq!(q.start(Some(&c), Some(7)).unwrap(), 40);
+        assert_eq!(q.start(None, Some(7)).unwrap(), 7);
+    }
+
+    #[test]
+    fn test_cursor_rejects_other_requests() {
+        let c = query("s1", "src").cursor(40);
+        let code = |r: Result<usize>| r.unwrap_err().downcast_ref::<CortexError>().unwrap().code();
+
+        assert_eq!(code(query("s1", "docs").offset(&c)), "INVALID_ARGUMENT");
+        assert_eq!(
+            code(query("s1", "src").offset(&c.replace(".40.", ".41."))),
+            "INVALID_ARGUMENT"
+        );
+        assert_eq!(
+            code(query("s1", "src").offset("garbage")),
+            "INVALID_ARGUMENT"
+        );
+        assert_eq!(code(query("s2", "src").offset(&c)), "REPO_CHANGED");
+    }
+
+    #[test]
+    fn test_page_size_is_clamped() {
+        assert_eq!(page_size(None, 100).unwrap(), 100);
+        assert_eq!(page_size(Some(5000), 100).unwrap(), MAX_PAGE_SIZE);
+        assert!(page_size(Some(0), 100).is_err());
+    }
+
+    #[test]
+    fn test_window() {
+        let mut w = Window::new(2, 3);
+        let on_page: Vec<usize> = (0..10).filter(|_| w.admit()).collect();
+        assert_eq!(on_page.len(), 3);
+        assert!(w.has_more);
+        assert_eq!(w.end(), 5);
+
+        let mut w = Window::new(0, 5);
+        for _ in 0..5 {
+            assert!(w.admit());
+        }
+        assert!(!w.has_more, "exactly one full page has no more");
+    }
+}
@@ -1,3 +1,4 @@
+use crate::util::canonical_json;
 use anyhow::{anyhow, Result};
 use serde::{Deserialize, Serialize}; // Kept because Fingerprint::to_canonical_json still uses it
 use sha2::{Digest, Sha256}; // Kept because Fingerprint::compute still uses it
@@ -37,25 +38,26 @@ impl Fingerprint {
         };
 
         // 2. index_oid
-        let write_tree_output = Command::new("git")
-            .arg("write-tree")
+        // git ls-files --stage -z
+        // Listing the index writes no objects, unlike `git write-tree`, and
+        // works on a conflicted index, whose unmerged stages it lists.
+        let ls_files_output = Command::new("git")
+            .args(["ls-files", "--stage", "-z"])
             .current_dir(repo_root)
             .output()?;
 
-        let index_oid = if write_tree_output.status.success() {
-            String::from_utf8_lossy(&write_tree_output.stdout)
-                .trim()
-                .to_string()
-        } else {
-            // "no tree possible" -> e.g. merge conflict state when index is invalid?
-            // Spec: "Empty string only if a tree is provably impossible"
-            "".to_string()
-        };
+        if !ls_files_output.status.success() {
+            return Err(anyhow!("Failed to run git ls-files"));
+        }
+
+        let index_oid = hex::encode(Sha256::digest(&ls_files_output.stdout));
 
         // 3. status_hash
-        // git status --porcelain=v1 -z
+        // git status --porcelain=v1 -z --untracked-files=all
+        // Untracked directories are listed file by file, so adding a file to
+        // one changes the hash.
         let status_output = Command::new("git")
-            .args(["status", "--porcelain=v1", "-z"])
+            .args(["status", "--porcelain=v1", "-z", "--untracked-files=all"])
             .current_dir(repo_root)
             .output()?;
 
@@ -74,9 +76,7 @@ impl Fingerprint {
 
     /// Canonical JSON representation for snapshot ID derivation
     pub fn to_canonical_json(&self) -> Result<String> {
-        let val = serde_json::to_value(self)?;
-        // sort keys
-        Ok(serde_json::to_string(&val)?)
+        canonical_json::to_string(self)
     }
 }
 
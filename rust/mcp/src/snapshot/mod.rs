@@ -1,4 +1,7 @@
+pub mod codec;
+pub mod cursor;
 pub mod lease;
+pub mod search;
 pub mod store;
 pub mod tools;
 
@@ -0,0 +1,411 @@
+// Feature: MCP_SNAPSHOT_WORKSPACE_SUBSTRATE
+// Spec: spec/mcp/snapshot-workspace-v1.md
+
+//! Search options of `snapshot.grep`.
+//!
+//! These follow the semantics of `cortex grep` (Go `internal/search`) so a
+//! search returns the same matches through the CLI and through MCP: case
+//! modes, fixed strings, gitignore-like include/exclude globs, a match cap
+//! and context lines.
+
+use crate::router::CortexError;
+use crate::snapshot::cursor::Window;
+use anyhow::Result;
+use regex::{Regex, RegexBuilder};
+use serde_json::{json, Map, Value};
+
+/// Files with a NUL byte in this many leading bytes are binary and skipped.
+pub const BINARY_SNIFF_BYTES: usize = 8000;
+
+#[derive(Clone, Copy, Debug, Default, PartialEq)]
+pub enum CaseMode {
+    #[default]
+    Sensitive,
+    Insensitive,
+    /// Insensitive unless the pattern contains an upper-case letter.
+    Smart,
+}
+
+impl CaseMode {
+    pub fn parse(s: &str) -> Result<Self> {
+        match s {
+            "sensitive" => Ok(CaseMode::Sensitive),
+            "insensitive" => Ok(CaseMode::Insensitive),
+            "smart" => Ok(CaseMode::Smart),
+            _ => Err(CortexError::InvalidArgument(format!(
+                "Unknown case mode {} (want sensitive, insensitive or smart)",
+                s
+            ))
+            .into()),
+        }
+    }
+
+    fn name(&self) -> &'static str {
+        match self {
+            CaseMode::Sensitive => "sensitive",
+            CaseMode::Insensitive => "insensitive",
+            CaseMode::Smart => "smart",
+        }
+    }
+}
+
+#[derive(Clone, Debug, Default)]
+pub struct GrepOptions {
+    pub case: CaseMode,
+    pub fixed_strings: bool,
+    pub include: Vec<String>,
+    pub exclude: Vec<String>,
+    /// Cap on matches over all pages; None or 0 means no cap.
+    pub max_matches: Option<usize>,
+    /// Lines reported before and after each match.
+    pub context: usize,
+}
+
+impl GrepOptions {
+    /// Reads the options from `snapshot.grep` arguments. The legacy
+    /// `case_insensitive` flag applies when `case` is absent.
+    pub fn from_args(args: &Map<String, Value>) -> Result<Self> {
+        let strings = |name: &str| -> Vec<String> {
+            args.get(name)
+                .and_then(|v| v.as_array())
+                .map(|arr| {
+                    arr.iter()
+                        .filter_map(|v| v.as_str().map(|s| s.to_string()))
+                        .collect()
+                })
+                .unwrap_or_default()
+        };
+        let case = match args.get("case").and_then(|v| v.as_str()) {
+            Some(c) => CaseMode::parse(c)?,
+            None if args.get("case_insensitive").and_then(|v| v.as_bool()) == Some(true) => {
+                CaseMode::Insensitive
+            }
+            None => CaseMode::Sensitive,
+        };
+        let opts = Self {
+            case,
+            fixed_strings: args
+                .get("fixed_strings")
+                .and_then(|v| v.as_bool())
+                .unwrap_or(false),
+            include: strings("include"),
+            exclude: strings("exclude"),
+            max_matches: args
+                .get("max_matches")
+                .and_then(|v| v.as_u64())
+                .map(|n| n as usize),
+            context: args.get("context").and_then(|v| v.as_u64()).unwrap_or(0) as usize,
+        };
+        for glob in opts.include.iter().chain(&opts.exclude) {
+            validate_glob(glob)?;
+        }
+        Ok(opts)
+    }
+
+    pub fn regex(&self, pattern: &str) -> Result<Regex> {
+        let expr = if self.fixed_strings {
+            regex::escape(pattern)
+        } else {
+            pattern.to_string()
+        };
+        let insensitive = match self.case {
+            CaseMode::Sensitive => false,
+            CaseMode::Insensitive => true,
+            CaseMode::Smart => !pattern.chars().any(char::is_uppercase),
+        };
+        RegexBuilder::new(&expr)
+            .case_insensitive(insensitive)
+            .build()
+            .map_err(|e| CortexError::InvalidArgument(format!("Invalid regex: {}", e)).into())
+    }
+
+    /// Whether `path` passes the include and exclude globs.
+    pub fn admits(&self, path: &str) -> bool {
+        if !self.include.is_empty() && !self.include.iter().any(|g| match_glob(g, path)) {
+            return false;
+        }
+        !self.exclude.iter().any(|g| match_glob(g, path))
+    }
+
+    /// Canonical form of the options, hashed into cursors so a cursor only
+    /// continues the same search.
+    pub fn cursor_key(&self) -> String {
+        format!(
+            "{}\0{}\0{}\0{}\0{}\0{}",
+            self.case.name(),
+            self.fixed_strings,
+            self.include.join("\u{1}"),
+            self.exclude.join("\u{1}"),
+            self.max_matches.unwrap_or(0),
+            self.context
+        )
+    }
+}
+
+/// Whether `data` looks binary, by git's heuristic.
+pub fn is_binary(data: &[u8]) -> bool {
+    data.iter().take(BINARY_SNIFF_BYTES).any(|&b| b == 0)
+}
+
+/// Matches files line by line across a paged search, counting matches
+/// against the cap.
+pub struct Scan<'a> {
+    re: &'a Regex,
+    opts: &'a GrepOptions,
+    matched: usize,
+    /// Set when the cap cut off further matches.
+    pub max_matches_reached: bool,
+}
+
+impl<'a> Scan<'a> {
+    pub fn new(re: &'a Regex, opts: &'a GrepOptions) -> Self {
+        Self {
+            re,
+            opts,
+            matched: 0,
+            max_matches_reached: false,
+        }
+    }
+
+    /// Whether the search should stop: the page is full or the cap is hit.
+    pub fn done(&self, window: &Window) -> bool {
+        window.has_more || self.max_matches_reached
+    }
+
+    /// Matching lines of `text` that fall in `window`.
+    pub fn file(&mut self, text: &str, window: &mut Window) -> Vec<Value> {
+        let lines: Vec<&str> = text.lines().collect();
+        let mut out = Vec::new();
+        for (i, line) in lines.iter().enumerate() {
+            let Some(m) = self.re.find(line) else {
+                continue;
+            };
+            if let Some(max) = self.opts.max_matches.filter(|&n| n > 0) {
+                if self.matched >= max {
+                    self.max_matches_reached = true;
+                    break;
+                }
+            }
+            self.matched += 1;
+            if window.admit() {
+                let mut hit = json!({ "line": i + 1, "col": m.start() + 1, "text": line });
+                let n = self.opts.context;
+                if n > 0 {
+                    hit["before"] = json!(lines[i.saturating_sub(n)..i]);
+                    hit["after"] = json!(lines[i + 1..(i + 1 + n).min(lines.len())]);
+                }
+                out.push(hit);
+            }
+            if window.has_more {
+                break;
+            }
+        }
+        out
+    }
+}
+
+/// Whether the slash-separated `path` matches `glob`, with gitignore-like
+/// anchoring: a glob without `/` matches any path element; a glob with `/`
+/// is anchored at the root and matches the path or one of its parent
+/// directories. `*`, `?` and `[...]` match within an element and a `**`
+/// element matches zero or more elements.
+pub fn match_glob(glob: &str, path: &str) -> bool {
+    let segs: Vec<&str> = path.split('/').collect();
+    if !glob.contains('/') {
+        return segs.iter().any(|s| match_element(glob, s));
+    }
+    let pats: Vec<&str> = glob.trim_start_matches('/').split('/').collect();
+    (1..=segs.len())
+        .rev()
+        .any(|n| match_elements(&pats, &segs[..n]))
+}
+
+pub fn validate_glob(glob: &str) -> Result<()> {
+    let invalid = || CortexError::InvalidArgument(format!("Invalid glob: {}", glob));
+    if glob.is_empty() {
+        return Err(invalid().into());
+    }
+    for pat in glob.split('/') {
+        let chars: Vec<char> = pat.chars().collect();
+        let mut i = 0;
+        while i < chars.len() {
+            match chars[i] {
+                '\\' if i + 1 == chars.len() => return Err(invalid().into()),
+                '\\' => i += 2,
+                '[' => match class_end(&chars, i) {
+                    Some(end) => i = end + 1,
+                    None => return Err(invalid().into()),
+                },
+                _ => i += 1,
+            }
+        }
+    }
+    Ok(())
+}
+
+fn match_elements(pats: &[&str], segs: &[&str]) -> bool {
+    match pats.split_first() {
+        None => segs.is_empty(),
+        Some((&"**", rest)) => (0..=segs.len()).any(|i| match_elements(rest, &segs[i..])),
+        Some((pat, rest)) => match segs.split_first() {
+            Some((seg, segs)) => match_element(pat, seg) && match_elements(rest, segs),
+            None => false,
+        },
+    }
+}
+
+/// Matches one path element as Go's `path.Match` does.
+fn match_element(pat: &str, name: &str) -> bool {
+    let pat: Vec<char> = pat.chars().collect();
+    let name: Vec<char> = name.chars().collect();
+    match_chars(&pat, &name)
+}
+
+fn match_chars(pat: &[char], name: &[char]) -> bool {
+    let Some(&p) = pat.first() else {
+        return name.is_empty();
+    };
+    match p {
+        '*' => (0..=name.len()).any(|i| match_chars(&pat[1..], &name[i..])),
+        '?' => !name.is_empty() && match_chars(&pat[1..], &name[1..]),
+        '[' => {
+            let Some(end) = class_end(pat, 0) else {
+                return false;
+            };
+            match name.first() {
+                Some(&c) if class_matches(&pat[1..end], c) => {
+                    match_chars(&pat[end + 1..], &name[1..])
+                }
+                _ => false,
+            }
+        }
+        '\\' if pat.len() > 1 => {
+            name.first() == Some(&pat[1]) && match_chars(&pat[2..], &name[1..])
+        }
+        _ => name.first() == Some(&p) && match_chars(&pat[1..], &name[1..]),
+    }
+}
+
+/// Index of the `]` closing the class that opens at `start`.
+fn class_end(pat: &[char], start: usize) -> Option<usize> {
+    let mut i = start + 1;
+    if pat.get(i) == Some(&'^') {
+        i += 1;
+    }
+    let first = i;
+    while i < pat.len() {
+        match pat[i] {
+            '\\' => i += 2,
+            ']' if i > first => return Some(i),
+            _ => i += 1,
+        }
+    }
+    None
+}
+
+/// Whether `c` is in the class body (between `[` and `]`).
+fn class_matches(body: &[char], c: char) -> bool {
+    let (negated, body) = match body.split_first() {
+        Some(('^', rest)) => (true, rest),
+        _ => (false, body),
+    };
+    let mut found = false;
+    let mut i = 0;
+    while i < body.len() {
+        let lo = if body[i] == '\\' {
+            i += 1;
+            body[i]
+        } else {
+            body[i]
+        };
+        i += 1;
+        let hi = if i + 1 < body.len() && body[i] == '-' {
+            i += 1;
+            let hi = if body[i] == '\\' {
+                i += 1;
+                body[i]
+            } else {
+                body[i]
+            };
+            i += 1;
+            hi
+        } else {
+            lo
+        };
+        if lo <= c && c <= hi {
+            found = true;
+        }
+    }
+    found != negated
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_match_glob() {
+        let cases = [
+            ("*.go", "a/b/c.go", true),
+            ("vendor", "x/vendor/y.go", true),
+            ("internal/search", "internal/search/glob.go", true),
+            ("/internal", "internal/x.go", true),
+            ("internal/*.go", "internal/a/b.go", false),
+            ("internal/**/*.go", "internal/a/b.go", true),
+            ("internal/**/*.go", "internal/b.go", true),
+            ("**/testdata", "a/b/testdata/f.txt", true),
+            ("docs/*.md", "spec/docs/a.md", false),
+            ("[a-c]?.rs", "src/b1.rs", true),
+            ("[^a-c]?.rs", "src/b1.rs", false),
+        ];
+        for (glob, path, want) in cases {
+            assert_eq!(match_glob(glob, path), want, "{} ~ {}", glob, path);
+        }
+        assert!(validate_glob("[a").is_err());
+        assert!(validate_glob("src/**/*.rs").is_ok());
+    }
+
+    #[test]
+    fn test_case_modes() {
+        let opts = |case| GrepOptions {
+            case,
+            ..Default::default()
+        };
+        assert!(!opts(CaseMode::Sensitive)
+            .regex("foo")
+            .unwrap()
+            .is_match("Foo"));
+        assert!(opts(CaseMode::Insensitive)
+            .regex("foo")
+            .unwrap()
+            .is_match("Foo"));
+        assert!(opts(CaseMode::Smart).regex("foo").unwrap().is_match("Foo"));
+        assert!(!opts(CaseMode::Smart).regex("Foo").unwrap().is_match("foo"));
+
+        let fixed = GrepOptions {
+            fixed_strings: true,
+            ..Default::default()
+        };
+        assert!(!fixed.regex("a.b").unwrap().is_match("axb"));
+    }
+
+    #[test]
+    fn test_scan_caps_matches_and_adds_context() {
+        let opts = GrepOptions {
+            max_matches: Some(2),
+            context: 1,
+            ..Default::default()
+        };
+        let re = opts.regex("hit").unwrap();
+        let mut scan = Scan::new(&re, &opts);
+        let mut window = Window::new(0, 100);
+
+        let lines = scan.file("one\nhit\nx hit\nhit\n", &mut window);
+        assert_eq!(lines.len(), 2);
+        assert_eq!(lines[0]["before"], json!(["one"]));
+        assert_eq!(lines[1]["col"], 3);
+        assert_eq!(lines[1]["after"], json!(["hit"]));
+        assert!(scan.max_matches_reached);
+        assert!(scan.done(&window));
+    }
+}
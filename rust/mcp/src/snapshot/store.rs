@@ -1,4 +1,7 @@
-use crate::config::{BlobBackend, Compression, StorageConfig};
+use crate::config::{BlobBackend, StorageConfig};
+use crate::router::CortexError;
+use crate::snapshot::codec;
+use crate::util::canonical_json;
 use anyhow::{anyhow, Result};
 use rusqlite::{params, Connection, OptionalExtension};
 use serde::{Deserialize, Serialize};
@@ -14,8 +17,15 @@ pub struct Entry {
     pub path: String,
     #[serde(default)]
     pub size: u64, // Added size to match schema requirement
+    /// Why the content was not captured (`too_large`); `blob` is then the
+    /// content hash of a blob that is not in the store.
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub omitted: Option<String>,
 }
 
+/// `Entry::omitted` of files over the size limit of `FilePolicy`.
+pub const OMITTED_TOO_LARGE: &str = "too_large";
+
 #[derive(Serialize, Deserialize, Clone, Debug)]
 pub struct SnapshotInfo {
     pub snapshot_id: String,
@@ -27,6 +37,52 @@ pub struct SnapshotInfo {
     pub derived_from: Option<String>,
     pub applied_patch_hash: Option<String>,
     pub label: Option<String>,
+    /// Tool that created the snapshot; None for snapshots stored before
+    /// provenance was recorded.
+    pub created_by: Option<String>,
+}
+
+impl SnapshotInfo {
+    pub fn provenance(&self) -> Provenance {
+        Provenance {
+            parent: self.derived_from.clone(),
+            created_by: self.created_by.clone(),
+            patch_digest: self.applied_patch_hash.clone(),
+            base_head_sha: self.head_sha.clone(),
+        }
+    }
+}
+
+/// Where a snapshot came from.
+#[derive(Serialize, Deserialize, Clone, Debug, Default, PartialEq)]
+pub struct Provenance {
+    /// Snapshot this one was derived from.
+    pub parent: Option<String>,
+    /// MCP tool that created the snapshot (e.g. `snapshot.create`).
+    pub created_by: Option<String>,
+    /// Digest of the patch applied to the parent.
+    pub patch_digest: Option<String>,
+    /// HEAD commit of the worktree the chain started from.
+    pub base_head_sha: String,
+}
+
+/// Result of `Store::snapshot_history`.
+#[derive(Serialize, Deserialize, Clone, Debug, PartialEq)]
+pub struct History {
+    /// The snapshot and its ancestors, newest first.
+    pub chain: Vec<HistoryEntry>,
+    /// False when an ancestor is no longer in the store; `missing` names it.
+    pub complete: bool,
+    pub missing: Option<String>,
+}
+
+#[derive(Serialize, Deserialize, Clone, Debug, PartialEq)]
+pub struct HistoryEntry {
+    pub snapshot_id: String,
+    pub created_at: Option<i64>,
+    pub label: Option<String>,
+    #[serde(flatten)]
+    pub provenance: Provenance,
 }
 
 #[derive(Serialize, Deserialize, Clone, Debug)]
@@ -42,16 +98,17 @@ impl Manifest {
     }
 
     pub fn to_canonical_json(&self) -> Result<String> {
-        let val = serde_json::to_value(self)?;
-        let s = serde_json::to_string(&val)?;
-        Ok(s)
+        canonical_json::to_string(self)
     }
 
+    /// Derives the snapshot ID (spec/mcp/snapshot-workspace-v1.md §2.4) as
+    /// `internal/canonjson.SnapshotID` does: the fingerprint is
+    /// re-encoded canonically, whatever encoding `fingerprint_json` uses.
     pub fn compute_snapshot_id(&self, fingerprint_json: &str) -> Result<String> {
-        // Use bytes for strict determinism
+        let fingerprint = canonical_json::canonicalize(fingerprint_json)?;
         let manifest_json = self.to_canonical_json()?;
         let mut hasher = Sha256::new();
-        hasher.update(fingerprint_json.as_bytes());
+        hasher.update(fingerprint.as_bytes());
         hasher.update(b"\n");
         hasher.update(manifest_json.as_bytes());
         let hash = hex::encode(hasher.finalize());
@@ -59,8 +116,23 @@ impl Manifest {
     }
 }
 
+#[derive(Serialize, Deserialize, Clone, Debug, PartialEq)]
+pub struct BlobProblem {
+    pub hash: String,
+    pub problem: String,
+}
+
+/// Result of `Store::verify_blobs`. Problems are sorted by hash.
+#[derive(Serialize, Deserialize, Clone, Debug, PartialEq)]
+pub struct VerifyReport {
+    pub blobs: usize,
+    pub stored_bytes: u64,
+    pub problems: Vec<BlobProblem>,
+}
+
 pub trait BlobStore: Send + Sync {
-    fn put(&self, data: &[u8], compression: Compression) -> Result<String>;
+    /// Stores `data` (already encoded) under `hash`, the hash of the raw content.
+    fn put(&self, hash: &str, data: &[u8]) -> Result<()>;
     fn get(&self, hash: &str) -> Result<Option<Vec<u8>>>;
     fn has(&self, hash: &str) -> Result<bool>;
 }
@@ -104,15 +176,11 @@ impl FsBlobStore {
 }
 
 impl BlobStore for FsBlobStore {
-    fn put(&self, data: &[u8], _compression: Compression) -> Result<String> {
-        let digest = Sha256::digest(data);
-        let hash_val = hex::encode(digest);
-        let hash = format!("sha256:{}", hash_val);
-
-        let path = self.path_for(&hash)?;
+    fn put(&self, hash: &str, data: &[u8]) -> Result<()> {
+        let path = self.path_for(hash)?;
 
         if path.exists() {
-            return Ok(hash);
+            return Ok(());
         }
 
         if let Some(parent) = path.parent() {
@@ -125,7 +193,7 @@ impl BlobStore for FsBlobStore {
         tmp.write_all(data)?;
         tmp.persist(&path).map_err(|e| e.error)?;
 
-        Ok(hash)
+        Ok(())
     }
 
     fn get(&self, hash: &str) -> Result<Option<Vec<u8>>> {
@@ -220,71 +288,55 @@ impl Store {
             );
             "#,
         )?;
+
+        // Provenance: stores created before it was recorded lack the column.
+        let has_created_by = conn
+            .prepare("SELECT 1 FROM pragma_table_info('snapshots') WHERE name = 'created_by'")?
+            .exists([])?;
+        if !has_created_by {
+            conn.execute("ALTER TABLE snapshots ADD COLUMN created_by TEXT", [])?;
+        }
+
+        // Metadata-only entries: likewise added later.
+        let has_omitted = conn
+            .prepare("SELECT 1 FROM pragma_table_info('manifest_entries') WHERE name = 'omitted'")?
+            .exists([])?;
+        if !has_omitted {
+            conn.execute("ALTER TABLE manifest_entries ADD COLUMN omitted TEXT", [])?;
+        }
         Ok(())
     }
 
     // BlobStore Proxy with Compression logic
-    // NOTE: The returned hash is the SHA256 of the *stored* bytes (which may be compressed).
-    // This implies that if compression is used, the manifest stores the hash of the compressed data.
-    // Decompression happens transparently on get() by consulting the DB.
+    // Blobs are addressed by the SHA256 of their raw content, whatever codec
+    // stores them, so manifests and snapshot IDs do not depend on the
+    // compression setting. The codec is recorded in `blobs.compression` and
+    // get() decodes with it. A blob already stored keeps its original codec.
     pub fn put_blob(&self, data: &[u8]) -> Result<String> {
-        // Handle compression
-        let (stored_data, alg) = match self.config.compression {
-            Compression::Zstd => {
-                let compressed = zstd::stream::encode_all(data, 3)?; // Level 3 default
-                (compressed, "zstd")
-            }
-            Compression::None => (data.to_vec(), "none"),
-        };
+        let hash = format!("sha256:{}", hex::encode(Sha256::digest(data)));
+
+        let conn = self.conn.lock().unwrap();
+        let known: bool = conn
+            .query_row("SELECT 1 FROM blobs WHERE hash = ?1", params![hash], |_| {
+                Ok(true)
+            })
+            .optional()?
+            .unwrap_or(false);
+        if known && self.blob_store.has(&hash)? {
+            return Ok(hash);
+        }
 
-        // Note: BlobStore::put computes hash of *provided* data.
-        // If we pass compressed data, the hash will be of compressed data.
-        // Wait, spec says "hash of raw bytes" usually?
-        // "content-addressed" implies hash is of content.
-        // If we store compressed, we usually want hash of *original* content to look it up?
-        // But FsBlobStore writes to `path_for(hash)`.
-        // If `hash` is sha256(compressed), then we have a CompressedBlobStore.
-        // But if we want `get(original_hash)` to work, we have a problem if we only store compressed.
-
-        // Correct approach for opaque blob store:
-        // 1. Compute hash of ORIGINAL data (ID).
-        // 2. We want to store `compressed` data but indexed by `original_hash`?
-        // FsBlobStore::put computes hash internally.
-
-        // If FsBlobStore computes hash of what it gets, then we get hash(compressed).
-        // That breaks "content-addressed" if we expect hash(original).
-
-        // Solution:
-        // We need explicit `put_with_hash` or `put` returns the hash of what was stored.
-        // If we use hash(compressed), then the Manifest must store hash(compressed).
-        // But `Entry` usually stores hash of content?
-        // The user said: "get() must decompress based on SQLite metadata".
-        // If I request `get(H)`, and H is hash(original), and DB says "H is zstd",
-        // then `FsBlobStore` must have stored it under H?
-        // But `FsBlobStore::put` derives path from data.
-
-        // If `FsBlobStore` enforces "path = hash(content)", then storing compressed data under `hash(original)` violates that if checked.
-        // But `FsBlobStore` *implementation* currently does: `let digest = Sha256::digest(data); ... path_for(hash)`.
-        // So it stores under `hash(compressed)`.
-
-        // This means if we compress, the "blob hash" returned is the hash of the compressed bytes.
-        // The Manifest will contain `hash(compressed)`.
-        // When we read, we get `hash(compressed)`, we read bytes, we see in DB that `hash(compressed)` is zstd, we decompress.
-        // The decompressed data has `hash(original)`.
-        // This is fine! The "blob pointer" in manifest points to the physical blob.
-
-        let hash = self
-            .blob_store
-            .put(&stored_data, self.config.compression.clone())?;
+        let codec = codec::for_compression(&self.config.compression);
+        let stored_data = codec.encode(data)?;
+        self.blob_store.put(&hash, &stored_data)?;
 
         // Update metadata
-        let conn = self.conn.lock().unwrap();
         conn.execute(
-            "INSERT OR IGNORE INTO blobs (hash, size_bytes, compression, storage, created_at) VALUES (?1, ?2, ?3, ?4, unixepoch())",
+            "INSERT OR REPLACE INTO blobs (hash, size_bytes, compression, storage, refcount, created_at) VALUES (?1, ?2, ?3, ?4, COALESCE((SELECT refcount FROM blobs WHERE hash = ?1), 0), unixepoch())",
             params![
                 hash,
                 stored_data.len() as i64,
-                alg,
+                codec.name(),
                 match self.config.blob_backend { BlobBackend::Fs => "fs", BlobBackend::Db => "db" }
             ]
         )?;
@@ -292,6 +344,34 @@ impl Store {
         Ok(hash)
     }
 
+    /// Decodes the stored bytes of blob `hash` and checks them against it.
+    /// Blobs written before codecs were content-addressed are named by the
+    /// hash of their stored bytes; those are accepted too.
+    fn decode_blob(hash: &str, stored: &[u8], compression: &str) -> Result<Vec<u8>> {
+        let codec = codec::by_name(compression).ok_or_else(|| {
+            CortexError::Corrupt(format!(
+                "Blob {} uses unknown codec {:?}",
+                hash, compression
+            ))
+        })?;
+        let data = codec.decode(stored).map_err(|e| {
+            CortexError::Corrupt(format!(
+                "Blob {} cannot be decoded as {}: {}",
+                hash, compression, e
+            ))
+        })?;
+
+        let digest = |b: &[u8]| format!("sha256:{}", hex::encode(Sha256::digest(b)));
+        if digest(&data) != hash && digest(stored) != hash {
+            return Err(CortexError::Corrupt(format!(
+                "Blob {} content does not match its hash",
+                hash
+            ))
+            .into());
+        }
+        Ok(data)
+    }
+
     // Snapshot Metadata & Manifest
     // Replaces the legacy put_snapshot with a full version
     #[allow(clippy::too_many_arguments)]
@@ -305,6 +385,32 @@ impl Store {
         derived_from: Option<&str>,
         applied_patch_hash: Option<&str>,
         label: Option<&str>,
+    ) -> Result<()> {
+        let provenance = Provenance {
+            parent: derived_from.map(str::to_string),
+            created_by: None,
+            patch_digest: applied_patch_hash.map(str::to_string),
+            base_head_sha: head_sha.to_string(),
+        };
+        self.put_snapshot_with(
+            id,
+            repo_root,
+            fingerprint_json,
+            manifest_bytes,
+            &provenance,
+            label,
+        )
+    }
+
+    /// Stores a snapshot with its provenance.
+    pub fn put_snapshot_with(
+        &self,
+        id: &str,
+        repo_root: &str,
+        fingerprint_json: &str,
+        manifest_bytes: &[u8],
+        provenance: &Provenance,
+        label: Option<&str>,
     ) -> Result<()> {
         let manifest: Manifest = serde_json::from_slice(manifest_bytes)?;
         let manifest_hash = format!("sha256:{}", hex::encode(Sha256::digest(manifest_bytes)));
@@ -325,8 +431,9 @@ impl Store {
 
         if exists.is_some() {
             // Decrement contents of old snapshot
-            let mut stmt =
-                tx.prepare("SELECT blob_hash FROM manifest_entries WHERE snapshot_id = ?1")?;
+            let mut stmt = tx.prepare(
+                "SELECT blob_hash FROM manifest_entries WHERE snapshot_id = ?1 AND omitted IS NULL",
+            )?;
             let blobs = stmt.query_map(params![id], |row| row.get::<_, String>(0))?;
 
             // Gather to avoid borrow issues while executing updates
@@ -343,17 +450,18 @@ impl Store {
 
         // 2. Insert/Replace snapshot
         tx.execute(
-            "INSERT OR REPLACE INTO snapshots (snapshot_id, repo_root, head_sha, fingerprint_json, manifest_hash, manifest_bytes, created_at, derived_from, applied_patch_hash, label) VALUES (?1, ?2, ?3, ?4, ?5, ?6, unixepoch(), ?7, ?8, ?9)",
+            "INSERT OR REPLACE INTO snapshots (snapshot_id, repo_root, head_sha, fingerprint_json, manifest_hash, manifest_bytes, created_at, derived_from, applied_patch_hash, label, created_by) VALUES (?1, ?2, ?3, ?4, ?5, ?6, unixepoch(), ?7, ?8, ?9, ?10)",
             params![
                 id,
                 repo_root,
-                head_sha,
+                provenance.base_head_sha,
                 fingerprint_json,
                 manifest_hash,
                 manifest_bytes,
-                derived_from,
-                applied_patch_hash,
-                label
+                provenance.parent,
+                provenance.patch_digest,
+                label,
+                provenance.created_by
             ]
         )?;
 
@@ -364,9 +472,18 @@ impl Store {
         )?;
 
         // 4. Insert new entries
-        let mut stmt = tx.prepare("INSERT INTO manifest_entries (snapshot_id, path, blob_hash, size_bytes) VALUES (?1, ?2, ?3, ?4)")?;
+        let mut stmt = tx.prepare("INSERT INTO manifest_entries (snapshot_id, path, blob_hash, size_bytes, omitted) VALUES (?1, ?2, ?3, ?4, ?5)")?;
         for entry in &manifest.entries {
-            stmt.execute(params![id, entry.path, entry.blob, entry.size])?;
+            stmt.execute(params![
+                id,
+                entry.path,
+                entry.blob,
+                entry.size,
+                entry.omitted
+            ])?;
+            if entry.omitted.is_some() {
+                continue; // metadata only: no blob to reference
+            }
 
             // Refcount increment
             let row_count = tx.execute(
@@ -404,7 +521,7 @@ impl Store {
 
     pub fn get_snapshot_info(&self, id: &str) -> Result<Option<SnapshotInfo>> {
         let conn = self.conn.lock().unwrap();
-        let mut stmt = conn.prepare("SELECT snapshot_id, repo_root, head_sha, fingerprint_json, manifest_hash, created_at, derived_from, applied_patch_hash, label FROM snapshots WHERE snapshot_id = ?1")?;
+        let mut stmt = conn.prepare("SELECT snapshot_id, repo_root, head_sha, fingerprint_json, manifest_hash, created_at, derived_from, applied_patch_hash, label, created_by FROM snapshots WHERE snapshot_id = ?1")?;
         let mut rows = stmt.query(params![id])?;
 
         if let Some(row) = rows.next()? {
@@ -418,21 +535,60 @@ impl Store {
                 derived_from: row.get(6)?,
                 applied_patch_hash: row.get(7)?,
                 label: row.get(8)?,
+                created_by: row.get(9)?,
             }))
         } else {
             Ok(None)
         }
     }
 
+    /// Walks the parent chain of snapshot `id`, newest first. The walk stops
+    /// at a snapshot without a parent or at a parent no longer stored.
+    pub fn snapshot_history(&self, id: &str) -> Result<History> {
+        let mut history = History {
+            chain: Vec::new(),
+            complete: true,
+            missing: None,
+        };
+        let mut seen = std::collections::HashSet::new();
+        let mut next = Some(id.to_string());
+        while let Some(sid) = next {
+            if !seen.insert(sid.clone()) {
+                return Err(CortexError::Corrupt(format!(
+                    "Snapshot history of {} has a cycle at {}",
+                    id, sid
+                ))
+                .into());
+            }
+            let Some(info) = self.get_snapshot_info(&sid)? else {
+                if history.chain.is_empty() {
+                    return Err(CortexError::NotFound(format!("Snapshot not found: {}", sid)).into());
+                }
+                history.complete = false;
+                history.missing = Some(sid);
+                break;
+            };
+            next = info.derived_from.clone();
+            history.chain.push(HistoryEntry {
+                snapshot_id: info.snapshot_id.clone(),
+                created_at: info.created_at,
+                label: info.label.clone(),
+                provenance: info.provenance(),
+            });
+        }
+        Ok(history)
+    }
+
     // List entries from DB (faster than parsing manifest JSON)
     pub fn list_snapshot_entries(&self, id: &str) -> Result<Vec<Entry>> {
         let conn = self.conn.lock().unwrap();
-        let mut stmt = conn.prepare("SELECT path, blob_hash, size_bytes FROM manifest_entries WHERE snapshot_id = ?1 ORDER BY path ASC")?;
+        let mut stmt = conn.prepare("SELECT path, blob_hash, size_bytes, omitted FROM manifest_entries WHERE snapshot_id = ?1 ORDER BY path ASC")?;
         let rows = stmt.query_map(params![id], |row| {
             Ok(Entry {
                 path: row.get(0)?,
                 blob: row.get(1)?,
                 size: row.get(2)?,
+                omitted: row.get(3)?,
             })
         })?;
 
@@ -455,27 +611,85 @@ impl Store {
 
     pub fn get_blob(&self, hash: &str) -> Result<Option<Vec<u8>>> {
         // Read bytes from backend
-        let maybe_bytes = self.blob_store.get(hash)?;
-        if let Some(mut bytes) = maybe_bytes {
-            // Check compression in DB
-            let conn = self.conn.lock().unwrap();
-            let compression: Option<String> = conn
-                .query_row(
-                    "SELECT compression FROM blobs WHERE hash = ?1",
-                    params![hash],
-                    |row| row.get(0),
-                )
-                .optional()?;
+        let Some(bytes) = self.blob_store.get(hash)? else {
+            return Ok(None);
+        };
+
+        // Check compression in DB
+        let conn = self.conn.lock().unwrap();
+        let compression: Option<String> = conn
+            .query_row(
+                "SELECT compression FROM blobs WHERE hash = ?1",
+                params![hash],
+                |row| row.get(0),
+            )
+            .optional()?;
+        drop(conn);
 
-            if let Some(s) = compression {
-                if s == "zstd" {
-                    // Decompress
-                    bytes = zstd::stream::decode_all(std::io::Cursor::new(bytes))?;
+        let compression = compression.ok_or_else(|| {
+            CortexError::Corrupt(format!("Blob {} has no metadata row", hash))
+        })?;
+        Self::decode_blob(hash, &bytes, &compression).map(Some)
+    }
+
+    /// Re-reads every blob, checking that it is present, has the recorded
+    /// size, decodes with its recorded codec and matches its hash, and that
+    /// every manifest entry references a known blob.
+    pub fn verify_blobs(&self) -> Result<VerifyReport> {
+        let conn = self.conn.lock().unwrap();
+        let mut stmt =
+            conn.prepare("SELECT hash, size_bytes, compression FROM blobs ORDER BY hash ASC")?;
+        let rows: Vec<(String, u64, String)> = stmt
+            .query_map([], |row| Ok((row.get(0)?, row.get(1)?, row.get(2)?)))?
+            .collect::<Result<_, _>>()?;
+        drop(stmt);
+
+        let mut stmt = conn.prepare(
+            "SELECT DISTINCT blob_hash FROM manifest_entries WHERE omitted IS NULL AND blob_hash NOT IN (SELECT hash FROM blobs) ORDER BY blob_hash ASC",
+        )?;
+        let dangling: Vec<String> = stmt
+            .query_map([], |row| row.get(0))?
+            .collect::<Result<_, _>>()?;
+        drop(stmt);
+        drop(conn);
+
+        let mut report = VerifyReport {
+            blobs: rows.len(),
+            stored_bytes: 0,
+            problems: Vec::new(),
+        };
+        for (hash, size, compression) in rows {
+            let problem = match self.blob_store.get(&hash) {
+                Err(e) => Some(format!("unreadable: {}", e)),
+                Ok(None) => Some("missing blob content".to_string()),
+                Ok(Some(stored)) => {
+                    report.stored_bytes += stored.len() as u64;
+                    if stored.len() as u64 != size {
+                        Some(format!(
+                            "stored size {} does not match recorded size {}",
+                            stored.len(),
+                            size
+                        ))
+                    } else {
+                        Self::decode_blob(&hash, &stored, &compression)
+                            .err()
+                            .map(|e| e.to_string())
+                    }
                 }
+            };
+            if let Some(problem) = problem {
+                report.problems.push(BlobProblem { hash, problem });
             }
-            return Ok(Some(bytes));
         }
-        Ok(None)
+        for hash in dangling {
+            report.problems.push(BlobProblem {
+                hash,
+                problem: "referenced by a manifest but has no metadata row".to_string(),
+            });
+        }
+        report.problems.sort_by(|a, b| a.hash.cmp(&b.hash));
+
+        Ok(report)
     }
 
     pub fn validate_path(path: &str) -> Result<()> {
@@ -541,7 +755,7 @@ impl Store {
         // 4. Verify blobs exist
         // We can do a batched check or one by one.
         // For now, one by one check "has".
-        for entry in &entries {
+        for entry in entries.iter().filter(|e| e.omitted.is_none()) {
             // Check DB
             let conn = self.conn.lock().unwrap();
             let blob_exists: bool = conn
@@ -555,18 +769,20 @@ impl Store {
             drop(conn);
 
             if !blob_exists {
-                return Err(anyhow!(
+                return Err(CortexError::Corrupt(format!(
                     "Snapshot corrupt: missing blob DB entry for {}",
                     entry.blob
-                ));
+                ))
+                .into());
             }
 
             // Check backend
             if !self.blob_store.has(&entry.blob)? {
-                return Err(anyhow!(
+                return Err(CortexError::Corrupt(format!(
                     "Snapshot corrupt: missing blob content for {}",
                     entry.blob
-                ));
+                ))
+                .into());
             }
         }
 
@@ -577,6 +793,122 @@ impl Store {
 #[cfg(test)]
 mod tests {
     use super::*;
+    use crate::config::Compression;
+
+    fn blob_path(dir: &std::path::Path, hash: &str) -> PathBuf {
+        let (algo, val) = hash.split_once(':').unwrap();
+        dir.join("blobs").join(algo).join(&val[..2]).join(val)
+    }
+
+    fn store_with(dir: &std::path::Path, compression: Compression) -> Store {
+        Store::new(StorageConfig {
+            data_dir: dir.to_path_buf(),
+            blob_backend: BlobBackend::Fs,
+            compression,
+        })
+        .unwrap()
+    }
+
+    #[test]
+    fn test_snapshot_history_walks_parents() {
+        let dir = tempfile::tempdir().unwrap();
+        let store = store_with(dir.path(), Compression::None);
+        let blob = store.put_blob(b"x").unwrap();
+        let manifest = format!(r#"{{"entries":[{{"path":"x","blob":"{}","size":1}}]}}"#, blob);
+
+        let put = |id: &str, parent: Option<&str>, tool: &str| {
+            let provenance = Provenance {
+                parent: parent.map(str::to_string),
+                created_by: Some(tool.to_string()),
+                patch_digest: parent.map(|p| format!("patch-of-{}", p)),
+                base_head_sha: "head".to_string(),
+            };
+            store
+                .put_snapshot_with(id, "/repo", "{}", manifest.as_bytes(), &provenance, None)
+                .unwrap();
+        };
+        put("s1", None, "snapshot.create");
+        put("s2", Some("s1"), "workspace.apply_patch");
+        put("s3", Some("s2"), "workspace.apply_patch");
+        put("orphan", Some("gone"), "workspace.apply_patch");
+
+        let history = store.snapshot_history("s3").unwrap();
+        let ids: Vec<&str> = history.chain.iter().map(|e| e.snapshot_id.as_str()).collect();
+        assert_eq!(ids, ["s3", "s2", "s1"]);
+        assert!(history.complete);
+        assert_eq!(history.chain[0].provenance.created_by.as_deref(), Some("workspace.apply_patch"));
+        assert_eq!(history.chain[0].provenance.patch_digest.as_deref(), Some("patch-of-s2"));
+        assert_eq!(history.chain[2].provenance.parent, None);
+
+        let history = store.snapshot_history("orphan").unwrap();
+        assert!(!history.complete);
+        assert_eq!(history.missing.as_deref(), Some("gone"));
+
+        let err = store.snapshot_history("nope").unwrap_err();
+        assert_eq!(err.downcast_ref::<CortexError>().unwrap().code(), "NOT_FOUND");
+    }
+
+    #[test]
+    fn test_compressed_blobs_are_content_addressed() {
+        let data = b"compress me compress me compress me\n".repeat(50);
+        let mut hashes = Vec::new();
+        for compression in [Compression::None, Compression::Zstd, Compression::Gzip] {
+            let dir = tempfile::tempdir().unwrap();
+            let store = store_with(dir.path(), compression.clone());
+            let hash = store.put_blob(&data).unwrap();
+            assert_eq!(store.get_blob(&hash).unwrap().unwrap(), data);
+
+            let stored = std::fs::read(blob_path(dir.path(), &hash)).unwrap();
+            if compression != Compression::None {
+                assert!(stored.len() < data.len(), "{:?} should compress", compression);
+            }
+            hashes.push(hash);
+        }
+        assert!(hashes.windows(2).all(|w| w[0] == w[1]), "hash must not depend on codec");
+    }
+
+    #[test]
+    fn test_blob_keeps_codec_across_config_changes() {
+        let dir = tempfile::tempdir().unwrap();
+        let hash = store_with(dir.path(), Compression::Gzip)
+            .put_blob(b"written as gzip")
+            .unwrap();
+
+        let store = store_with(dir.path(), Compression::Zstd);
+        assert_eq!(store.put_blob(b"written as gzip").unwrap(), hash);
+        assert_eq!(store.get_blob(&hash).unwrap().unwrap(), b"written as gzip");
+    }
+
+    #[test]
+    fn test_verify_blobs_reports_corruption() {
+        let dir = tempfile::tempdir().unwrap();
+        let store = store_with(dir.path(), Compression::Zstd);
+        let good = store.put_blob(b"good").unwrap();
+        let flipped = store.put_blob(b"flipped").unwrap();
+        let missing = store.put_blob(b"missing").unwrap();
+
+        let report = store.verify_blobs().unwrap();
+        assert_eq!(report.blobs, 3);
+        assert!(report.problems.is_empty(), "{:?}", report.problems);
+
+        let path = blob_path(dir.path(), &flipped);
+        let mut bytes = std::fs::read(&path).unwrap();
+        let last = bytes.len() - 1;
+        bytes[last] ^= 0xff;
+        std::fs::write(&path, bytes).unwrap();
+        std::fs::remove_file(blob_path(dir.path(), &missing)).unwrap();
+
+        let report = store.verify_blobs().unwrap();
+        let mut bad: Vec<&str> = report.problems.iter().map(|p| p.hash.as_str()).collect();
+        let mut want = vec![flipped.as_str(), missing.as_str()];
+        want.sort();
+        bad.sort();
+        assert_eq!(bad, want);
+        assert!(!report.problems.iter().any(|p| p.hash == good));
+
+        let err = store.get_blob(&flipped).unwrap_err();
+        assert_eq!(err.downcast_ref::<CortexError>().unwrap().code(), "CORRUPT");
+    }
 
     #[test]
     fn test_validate_path() {
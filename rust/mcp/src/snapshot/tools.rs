@@ -1,5 +1,9 @@
+use crate::config::FilePolicy;
+use crate::router::CortexError;
+use crate::snapshot::cursor as paging;
 use crate::snapshot::lease::{Fingerprint, LeaseStore};
-use crate::snapshot::store::{Entry, Manifest, Store};
+use crate::snapshot::search::{is_binary, GrepOptions, Scan};
+use crate::snapshot::store::{Entry, Manifest, Provenance, Store, OMITTED_TOO_LARGE};
 use anyhow::{anyhow, Result};
 use base64::Engine;
 use serde_json::json;
@@ -15,15 +19,34 @@ use walkdir;
 pub struct SnapshotTools {
     lease_store: Arc<LeaseStore>,
     store: Arc<Store>,
+    policy: FilePolicy,
 }
 
 impl SnapshotTools {
     pub fn new(lease_store: Arc<LeaseStore>, store: Arc<Store>) -> Self {
-        Self { lease_store, store }
+        Self {
+            lease_store,
+            store,
+            policy: FilePolicy::default(),
+        }
+    }
+
+    /// Replaces the default binary and large-file policy.
+    pub fn with_file_policy(mut self, policy: FilePolicy) -> Self {
+        self.policy = policy;
+        self
     }
 
     // --- Helpers ---
 
+    fn too_large(&self, path: &str, size: u64) -> anyhow::Error {
+        CortexError::TooLarge(format!(
+            "{} is {} bytes, over the {}-byte file limit; its content is not captured",
+            path, size, self.policy.max_file_bytes
+        ))
+        .into()
+    }
+
     fn check_lease(&self, lease_id: Option<&str>, repo_root: &Path) -> Result<String> {
         // If lease_id provided, check it.
         // If not, spec says "All hybrid read tools ... issue lease when missing".
@@ -82,15 +105,22 @@ impl SnapshotTools {
         snapshot_id: Option<String>,
         limit: Option<usize>,
         offset: Option<usize>,
+        cursor: Option<String>,
     ) -> Result<serde_json::Value> {
         let repo_root = repo_root.canonicalize()?;
         let target_path = self.resolve_path(&repo_root, path)?;
 
-        let limit = limit.unwrap_or(1000); // Default limit? Or unlimited? Let's say 1000 safety cap.
-        let offset = offset.unwrap_or(0);
+        let limit = paging::page_size(limit, paging::DEFAULT_LIST_PAGE_SIZE)?;
 
         if mode == "worktree" {
             let lid = self.check_lease(lease_id.as_deref(), &repo_root)?;
+            let status_hash = self.lease_store.get_fingerprint(&lid).unwrap().status_hash;
+            let query = paging::Query {
+                tool: "snapshot.list",
+                params: vec![mode, path],
+                scope: &status_hash,
+            };
+            let offset = query.start(cursor.as_deref(), offset)?;
 
             // Walk dir efficiently
             let mut entries = Vec::new();
@@ -162,6 +192,8 @@ impl SnapshotTools {
 
             let fp = self.lease_store.get_fingerprint(&lid).unwrap();
 
+            let next_cursor = ((offset + limit) < total).then(|| query.cursor(offset + limit));
+
             Ok(json!({
                 "snapshot_id": format!("sha256:{}", fp.status_hash),
                 "path": path,
@@ -169,6 +201,8 @@ impl SnapshotTools {
                 "entries": entries,
                 "total": total,
                 "truncated": (offset + limit) < total,
+                "next_cursor": next_cursor,
+                "page_size": limit,
                 "lease_id": lid,
                 "fingerprint": fp,
                 "cache_key": format!("{}:sha256:{}", lid, fp.status_hash),
@@ -182,6 +216,13 @@ impl SnapshotTools {
             self.store.validate_snapshot(&snap_id)?;
             let manifest_entries = self.store.list_snapshot_entries(&snap_id)?;
 
+            let query = paging::Query {
+                tool: "snapshot.list",
+                params: vec![mode, snap_id.as_str(), path],
+                scope: snap_id.as_str(),
+            };
+            let offset = query.start(cursor.as_deref(), offset)?;
+
             let mut result_entries = Vec::new();
             let mut dirs_seen = std::collections::HashSet::new();
 
@@ -218,12 +259,16 @@ impl SnapshotTools {
                              }));
                         }
                     } else {
-                        temp_entries.push(json!({
+                        let mut file = json!({
                             "path": entry.path,
                             "type": "file",
                             "size": entry.size,
                             "sha": entry.blob
-                        }));
+                        });
+                        if let Some(reason) = &entry.omitted {
+                            file["omitted"] = json!(reason);
+                        }
+                        temp_entries.push(file);
                     }
                 }
             }
@@ -241,12 +286,16 @@ impl SnapshotTools {
                 result_entries.extend_from_slice(&temp_entries[offset..end]);
             }
 
+            let next_cursor = ((offset + limit) < total).then(|| query.cursor(offset + limit));
+
             Ok(json!({
                 "snapshot_id": snap_id,
                 "path": path,
                 "mode": "snapshot",
                 "entries": result_entries,
                 "truncated": (offset + limit) < total, // Simple check
+                "next_cursor": next_cursor,
+                "page_size": limit,
                 "total": total,
                 "cache_key": snap_id,
                 "cache_hint": "immutable"
@@ -296,12 +345,26 @@ impl SnapshotTools {
 
             let p = self.resolve_path(&repo_root, &path_str)?;
             if p.is_file() {
+                let size = std::fs::metadata(&p)?.len();
+                if self.policy.is_large(size) {
+                    // Metadata only: hashed as a stream, never stored.
+                    let mut hasher = Sha256::new();
+                    std::io::copy(&mut std::fs::File::open(&p)?, &mut hasher)?;
+                    entries.push(Entry {
+                        path: path_str,
+                        blob: format!("sha256:{}", hex::encode(hasher.finalize())),
+                        size,
+                        omitted: Some(OMITTED_TOO_LARGE.to_string()),
+                    });
+                    continue;
+                }
                 let content = std::fs::read(&p)?;
                 let blob_hash = self.store.put_blob(&content)?;
                 entries.push(Entry {
                     path: path_str,
                     blob: blob_hash,
                     size: content.len() as u64,
+                    omitted: None,
                 });
             }
         }
@@ -313,14 +376,18 @@ impl SnapshotTools {
 
         // Store manifest
         let manifest_bytes = manifest.to_canonical_json()?.into_bytes();
-        self.store.put_snapshot(
+        let provenance = Provenance {
+            parent: None,
+            created_by: Some("snapshot.create".to_string()),
+            patch_digest: None,
+            base_head_sha: fp.head_oid.clone(),
+        };
+        self.store.put_snapshot_with(
             &snap_id,
             &repo_root.to_string_lossy(),
-            &fp.head_oid,
             &fp_json,
             &manifest_bytes,
-            None,
-            None,
+            &provenance,
             None,
         )?;
 
@@ -350,6 +417,10 @@ impl SnapshotTools {
             if !target_path.exists() || !target_path.is_file() {
                 return Err(anyhow!("File not found or not a file: {}", path));
             }
+            let size = std::fs::metadata(&target_path)?.len();
+            if self.policy.is_large(size) {
+                return Err(self.too_large(path, size));
+            }
 
             let content = std::fs::read(&target_path)?;
             // base64 encode
@@ -398,12 +469,15 @@ impl SnapshotTools {
                 .iter()
                 .find(|e| e.path == path)
                 .ok_or_else(|| anyhow!("File not found in snapshot: {}", path))?;
+            if entry.omitted.is_some() {
+                return Err(self.too_large(path, entry.size));
+            }
 
             let content = self.store.get_blob(&entry.blob)?.ok_or_else(|| {
-                anyhow!(
+                CortexError::Corrupt(format!(
                     "Snapshot corrupted: referenced blob {} not found in store",
                     entry.blob
-                )
+                ))
             })?;
 
             use base64::{engine::general_purpose, Engine as _};
@@ -439,20 +513,26 @@ impl SnapshotTools {
         mode: &str,
         lease_id: Option<String>,
         snapshot_id: Option<String>,
-        case_insensitive: bool,
+        opts: &GrepOptions,
+        page_size: Option<usize>,
+        cursor: Option<String>,
     ) -> Result<serde_json::Value> {
         let repo_root = repo_root.canonicalize()?;
-
-        let mut builder = regex::RegexBuilder::new(pattern);
-        if case_insensitive {
-            builder.case_insensitive(true);
-        }
-        let re = builder
-            .build()
-            .map_err(|e| anyhow!("Invalid regex: {}", e))?;
+        let page_size = paging::page_size(page_size, paging::DEFAULT_GREP_PAGE_SIZE)?;
+        let option_key = opts.cursor_key();
+        let path_list = paths.as_ref().map(|p| p.join("\0")).unwrap_or_default();
+        let re = opts.regex(pattern)?;
 
         if mode == "worktree" {
             let lid = self.check_lease(lease_id.as_deref(), &repo_root)?;
+            let status_hash = self.lease_store.get_fingerprint(&lid).unwrap().status_hash;
+            let query = paging::Query {
+                tool: "snapshot.grep",
+                params: vec![mode, pattern, &option_key, &path_list],
+                scope: &status_hash,
+            };
+            let mut window = paging::Window::new(query.start(cursor.as_deref(), None)?, page_size);
+            let mut scan = Scan::new(&re, opts);
 
             let roots = if let Some(p) = paths {
                 p.iter()
@@ -467,11 +547,9 @@ impl SnapshotTools {
             // Actually, we process files in order.
             let mut matches: Vec<serde_json::Value> = Vec::new();
             let mut candidates_touched = Vec::new();
-            let mut truncated = false;
-            let mut total_matches = 0;
 
             for root in roots {
-                if truncated {
+                if scan.done(&window) {
                     break;
                 }
                 for entry in walkdir::WalkDir::new(&root).sort_by_file_name() {
@@ -479,33 +557,23 @@ impl SnapshotTools {
                     if entry.file_type().is_file() {
                         let path = entry.path();
                         let rel = path.strip_prefix(&repo_root)?.to_string_lossy().to_string();
+                        if !opts.admits(&rel) {
+                            continue;
+                        }
 
                         candidates_touched.push(rel.clone());
 
-                        let mut f = std::fs::File::open(path)?;
-                        let mut buffer = [0; 512];
-                        let n = std::io::Read::read(&mut f, &mut buffer)?;
-                        if buffer[..n].contains(&0) {
+                        if self.policy.is_large(entry.metadata()?.len()) {
                             continue;
                         }
-
-                        let content = std::fs::read_to_string(path)?;
-                        let mut file_lines = Vec::new();
-
-                        for (i, line) in content.lines().enumerate() {
-                            if re.is_match(line) {
-                                file_lines.push(json!({
-                                    "line": i + 1,
-                                    "col": 1, // Regex doesn't give col easily without more work, stub 1
-                                    "text": line
-                                }));
-                                total_matches += 1;
-                                if total_matches >= 100 {
-                                    truncated = true;
-                                    break;
-                                }
-                            }
+                        let data = std::fs::read(path)?;
+                        if is_binary(&data) {
+                            continue;
                         }
+                        let Ok(content) = String::from_utf8(data) else {
+                            continue;
+                        };
+                        let file_lines = scan.file(&content, &mut window);
 
                         if !file_lines.is_empty() {
                             matches.push(json!({
@@ -514,7 +582,7 @@ impl SnapshotTools {
                             }));
                         }
 
-                        if truncated {
+                        if scan.done(&window) {
                             break;
                         }
                     }
@@ -529,7 +597,10 @@ impl SnapshotTools {
                 "query": pattern,
                 "mode": "worktree",
                 "matches": matches,
-                "truncated": truncated,
+                "truncated": window.has_more,
+                "max_matches_reached": scan.max_matches_reached,
+                "next_cursor": window.has_more.then(|| query.cursor(window.end())),
+                "page_size": page_size,
                 "lease_id": lid,
                 "fingerprint": fp,
                 "cache_key": format!("{}:grep:{}:sha256:{}", lid, pattern, fp.status_hash),
@@ -543,9 +614,15 @@ impl SnapshotTools {
 
             let manifest_entries = self.store.list_snapshot_entries(&sid)?;
 
+            let query = paging::Query {
+                tool: "snapshot.grep",
+                params: vec![mode, sid.as_str(), pattern, &option_key, &path_list],
+                scope: sid.as_str(),
+            };
+            let mut window = paging::Window::new(query.start(cursor.as_deref(), None)?, page_size);
+            let mut scan = Scan::new(&re, opts);
+
             let mut matches: Vec<serde_json::Value> = Vec::new();
-            let mut truncated = false;
-            let mut total_matches = 0;
 
             // Filter by paths if provided
             let mut candidate_entries = Vec::new();
@@ -572,35 +649,25 @@ impl SnapshotTools {
 
             // Iterate candidates (already sorted by manifest order)
             for entry in candidate_entries {
-                if truncated {
+                if scan.done(&window) {
                     break;
                 }
+                if !opts.admits(&entry.path)
+                    || entry.omitted.is_some()
+                    || self.policy.is_large(entry.size)
+                {
+                    continue;
+                }
 
                 // Get blob content
                 if let Some(content) = self.store.get_blob(&entry.blob)? {
-                    // Binary check
-                    if content.iter().take(512).any(|&b| b == 0) {
+                    if is_binary(&content) {
                         continue;
                     }
 
-                    // Decode as string
-                    // If valid utf8?
+                    // Files that are not UTF-8 are skipped, as binaries are.
                     if let Ok(text) = String::from_utf8(content) {
-                        let mut file_lines = Vec::new();
-                        for (i, line) in text.lines().enumerate() {
-                            if re.is_match(line) {
-                                file_lines.push(json!({
-                                    "line": i + 1,
-                                    "col": 1,
-                                    "text": line
-                                }));
-                                total_matches += 1;
-                                if total_matches >= 100 {
-                                    truncated = true;
-                                    break;
-                                }
-                            }
-                        }
+                        let file_lines = scan.file(&text, &mut window);
 
                         if !file_lines.is_empty() {
                             matches.push(json!({
@@ -621,7 +688,10 @@ impl SnapshotTools {
                 "query": pattern,
                 "mode": "snapshot",
                 "matches": matches,
-                "truncated": truncated,
+                "truncated": window.has_more,
+                "max_matches_reached": scan.max_matches_reached,
+                "next_cursor": window.has_more.then(|| query.cursor(window.end())),
+                "page_size": page_size,
                 "cache_key": sid, // In snapshot mode, result stable for (sid, pattern)
                 "cache_hint": "immutable"
             }))
@@ -675,6 +745,13 @@ impl SnapshotTools {
             // Find entry in snapshot
             if let Ok(entries) = self.store.list_snapshot_entries(&sid) {
                 if let Some(entry) = entries.iter().find(|e| e.path == path) {
+                    if entry.omitted.is_some() {
+                        return Ok(json!({
+                            "diff": format!("Large files a/{} and b/{} differ", path, path),
+                            "snapshot_id": sid,
+                            "cache_hint": "immutable"
+                        }));
+                    }
                     if let Some(blob) = self.store.get_blob(&entry.blob)? {
                         // Try decode utf8, if binary?
                         // similar can diff bytes but usually we diff text.
@@ -700,6 +777,13 @@ impl SnapshotTools {
                 self.store.validate_snapshot(&from_sid)?;
                 if let Ok(entries) = self.store.list_snapshot_entries(&from_sid) {
                     if let Some(entry) = entries.iter().find(|e| e.path == path) {
+                        if entry.omitted.is_some() {
+                            return Ok(json!({
+                                "diff": format!("Large files a/{} and b/{} differ", path, path),
+                                "snapshot_id": sid,
+                                "cache_hint": "immutable"
+                            }));
+                        }
                         if let Some(blob) = self.store.get_blob(&entry.blob)? {
                             if blob.iter().take(512).any(|&b| b == 0) {
                                 // Base is binary.
@@ -837,6 +921,29 @@ impl SnapshotTools {
         }))
     }
 
+    /// Revalidates every stored blob. The store is shared by all repos the
+    /// server handles, so the report covers the whole store.
+    pub fn snapshot_verify(&self) -> Result<serde_json::Value> {
+        let report = self.store.verify_blobs()?;
+        Ok(json!({
+            "ok": report.problems.is_empty(),
+            "blobs": report.blobs,
+            "stored_bytes": report.stored_bytes,
+            "problems": report.problems,
+        }))
+    }
+
+    /// Walks the provenance chain of a snapshot back to its root.
+    pub fn snapshot_history(&self, snapshot_id: &str) -> Result<serde_json::Value> {
+        let history = self.store.snapshot_history(snapshot_id)?;
+        Ok(json!({
+            "snapshot_id": snapshot_id,
+            "chain": history.chain,
+            "complete": history.complete,
+            "missing": history.missing,
+        }))
+    }
+
     pub fn snapshot_export(
         &self,
         _repo_root: &Path,
@@ -854,8 +961,13 @@ impl SnapshotTools {
 
         let mut included_files = 0;
         let mut total_bytes = 0;
+        let mut omitted_files = 0;
 
         for entry in entries {
+            if entry.omitted.is_some() {
+                omitted_files += 1;
+                continue;
+            }
             if let Some(content) = self.store.get_blob(&entry.blob)? {
                 let mut header = tar::Header::new_gnu();
                 header.set_size(content.len() as u64);
@@ -890,7 +1002,8 @@ impl SnapshotTools {
             "summary": {
                 "included_files": included_files,
                 "included_bytes": total_bytes,
-                "truncated": false
+                "omitted_files": omitted_files,
+                "truncated": omitted_files > 0
             },
             "bundle": format!("base64:{}", encoded_bundle),
             "cache_key": snap_id,
@@ -924,6 +1037,7 @@ impl SnapshotTools {
                 "derived_from": info.derived_from,
                 "applied_patch_hash": info.applied_patch_hash,
                 "label": info.label,
+                "provenance": info.provenance(),
                 "cache_hint": "immutable"
             }))
         } else {
@@ -948,6 +1062,7 @@ mod tests {
     use super::*;
     use crate::config::{BlobBackend, Compression, StorageConfig};
     use crate::snapshot::lease::LeaseStore;
+    use crate::snapshot::search::CaseMode;
 
     #[test]
     fn test_snapshot_grep_basics() {
@@ -1018,7 +1133,12 @@ mod tests {
                 "snapshot",
                 None,
                 Some(sid.to_string()),
-                true, // case insensitive
+                &GrepOptions {
+                    case: CaseMode::Insensitive,
+                    ..Default::default()
+                },
+                None,
+                None,
             )
             .unwrap();
 
@@ -1036,7 +1156,12 @@ mod tests {
                 "snapshot",
                 None,
                 Some(sid.to_string()),
-                true,
+                &GrepOptions {
+                    case: CaseMode::Insensitive,
+                    ..Default::default()
+                },
+                None,
+                None,
             )
             .unwrap();
         assert!(res2["matches"].as_array().unwrap().is_empty()); // c/text2 has no match
@@ -1049,7 +1174,12 @@ mod tests {
                 "snapshot",
                 None,
                 Some(sid.to_string()),
-                true,
+                &GrepOptions {
+                    case: CaseMode::Insensitive,
+                    ..Default::default()
+                },
+                None,
+                None,
             )
             .unwrap();
         assert_eq!(res3["matches"].as_array().unwrap().len(), 1);
@@ -0,0 +1,115 @@
+//! Canonical JSON (spec/mcp/snapshot-workspace-v1.md §1.1): object keys
+//! sorted by bytes at every level, no insignificant whitespace, and only the
+//! standard string escapes (`\"`, `\\`, `\b`, `\f`, `\n`, `\r`, `\t` and
+//! `\u00XX` for other control characters). It is byte for byte the encoding
+//! of the Go package `internal/canonjson`; both are checked against
+//! `spec/fixtures/canonjson/vectors.json`.
+//!
+//! Numbers are written as serde_json prints them, which matches Go for
+//! integers, the only numbers in fingerprints and manifests.
+
+use anyhow::Result;
+use serde::Serialize;
+use serde_json::Value;
+use std::fmt::Write;
+
+/// Returns the canonical JSON encoding of `v`.
+pub fn to_string<T: Serialize>(v: &T) -> Result<String> {
+    let value = serde_json::to_value(v)?;
+    let mut out = String::new();
+    encode(&mut out, &value);
+    Ok(out)
+}
+
+/// Re-encodes the JSON document `json` canonically.
+pub fn canonicalize(json: &str) -> Result<String> {
+    let value: Value = serde_json::from_str(json)?;
+    let mut out = String::new();
+    encode(&mut out, &value);
+    Ok(out)
+}
+
+fn encode(out: &mut String, v: &Value) {
+    match v {
+        Value::Null => out.push_str("null"),
+        Value::Bool(b) => out.push_str(if *b { "true" } else { "false" }),
+        Value::Number(n) => out.push_str(&n.to_string()),
+        Value::String(s) => encode_str(out, s),
+        Value::Array(items) => {
+            out.push('[');
+            for (i, item) in items.iter().enumerate() {
+                if i > 0 {
+                    out.push(',');
+                }
+                encode(out, item);
+            }
+            out.push(']');
+        }
+        Value::Object(map) => {
+            let mut keys: Vec<&String> = map.keys().collect();
+            keys.sort();
+            out.push('{');
+            for (i, k) in keys.iter().enumerate() {
+                if i > 0 {
+                    out.push(',');
+                }
+                encode_str(out, k);
+                out.push(':');
+                encode(out, &map[k.as_str()]);
+            }
+            out.push('}');
+        }
+    }
+}
+
+fn encode_str(out: &mut String, s: &str) {
+    out.push('"');
+    for c in s.chars() {
+        match c {
+            '"' => out.push_str("\\\""),
+            '\\' => out.push_str("\\\\"),
+            '\u{8}' => out.push_str("\\b"),
+            '\u{c}' => out.push_str("\\f"),
+            '\n' => out.push_str("\\n"),
+            '\r' => out.push_str("\\r"),
+            '\t' => out.push_str("\\t"),
+            c if (c as u32) < 0x20 => {
+                let _ = write!(out, "\\u{:04x}", c as u32);
+            }
+            c => out.push(c),
+        }
+    }
+    out.push('"');
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::snapshot::store::Manifest;
+
+    const VECTORS: &str = include_str!(concat!(
+        env!("CARGO_MANIFEST_DIR"),
+        "/../../spec/fixtures/canonjson/vectors.json"
+    ));
+
+    #[test]
+    fn shared_vectors_encode_like_go() {
+        let vectors: Value = serde_json::from_str(VECTORS).unwrap();
+        for e in vectors["encodings"].as_array().unwrap() {
+            let got = canonicalize(e["input"].as_str().unwrap()).unwrap();
+            assert_eq!(got, e["canonical"].as_str().unwrap(), "{}", e["name"]);
+        }
+    }
+
+    #[test]
+    fn shared_vectors_derive_go_snapshot_ids() {
+        let vectors: Value = serde_json::from_str(VECTORS).unwrap();
+        for s in vectors["snapshot_ids"].as_array().unwrap() {
+            let manifest: Manifest = serde_json::from_value(s["manifest"].clone()).unwrap();
+            // Any encoding of the fingerprint derives the same ID.
+            let fingerprint = serde_json::to_string_pretty(&s["fingerprint"]).unwrap();
+            let id = manifest.compute_snapshot_id(&fingerprint).unwrap();
+            assert_eq!(id, s["snapshot_id"].as_str().unwrap(), "{}", s["name"]);
+        }
+    }
+}
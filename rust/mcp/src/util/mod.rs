@@ -1,2 +1,3 @@
+pub mod canonical_json;
 pub mod paths;
 pub mod stable;
@@ -199,6 +199,12 @@ impl WorkspaceTools {
             let temp_path = temp.path();
             let entries = self.store.list_snapshot_entries(&snap_id)?;
 
+            // Entries captured as metadata only have no content to write;
+            // they carry over to the new snapshot unless the patch creates
+            // the path.
+            let (omitted, entries): (Vec<_>, Vec<_>) =
+                entries.into_iter().partition(|e| e.omitted.is_some());
+
             for entry in entries {
                 if let Some(content) = self.store.get_blob(&entry.blob)? {
                     let full_path = temp_path.join(&entry.path);
@@ -251,9 +257,15 @@ impl WorkspaceTools {
                             path: rel_path,
                             blob: blob_id,
                             size: content.len() as u64,
+                            omitted: None,
                         });
                     }
                 }
+                for entry in omitted {
+                    if !new_entries.iter().any(|e| e.path == entry.path) {
+                        new_entries.push(entry);
+                    }
+                }
 
                 // Create new snapshot
                 let new_manifest = crate::snapshot::store::Manifest::new(new_entries); // sorts automatically
@@ -267,15 +279,19 @@ impl WorkspaceTools {
                 let patch_hash =
                     format!("sha256:{}", hex::encode(Sha256::digest(patch.as_bytes())));
 
-                self.store.put_snapshot(
+                let provenance = crate::snapshot::store::Provenance {
+                    parent: Some(snap_id.clone()),
+                    created_by: Some("workspace.apply_patch".to_string()),
+                    patch_digest: Some(patch_hash),
+                    base_head_sha: base_info.head_sha.clone(), // Preserve base head_sha
+                };
+                self.store.put_snapshot_with(
                     &new_snap_id,
                     &base_info.repo_root,        // Preserve base repo_root
-                    &base_info.head_sha,         // Preserve base head_sha
                     &base_info.fingerprint_json, // Preserve base fingerprint
                     manifest_json.as_bytes(),
-                    Some(&snap_id),    // derived_from
-                    Some(&patch_hash), // applied_patch_hash
-                    None,              // label
+                    &provenance,
+                    None, // label
                 )?;
 
                 Ok(serde_json::json!({
@@ -0,0 +1,185 @@
+use cortex_mcp::io::fs::RealFs;
+use cortex_mcp::prompts::Prompts;
+use cortex_mcp::resolver::order::ResolveEngine;
+use cortex_mcp::router::mounts::MountRegistry;
+use cortex_mcp::router::{JsonRpcRequest, Router};
+use cortex_mcp::snapshot::{lease::LeaseStore, tools::SnapshotTools};
+use cortex_mcp::workspace::WorkspaceTools;
+use serde_json::{json, Value};
+use std::path::{Path, PathBuf};
+use std::process::Command;
+use std::sync::Arc;
+
+// Feature: MCP_PROMPTS
+// Spec: spec/mcp/prompts.md
+
+const REPORT: &str = r#"{
+  "schema_version": "1.0",
+  "summary": {},
+  "features": {
+    "DEMO": {
+      "status": "done",
+      "spec": { "present": true, "path": "spec/demo.md" },
+      "implementation": { "present": true, "files": ["src/demo.rs"] },
+      "tests": { "present": false, "files": null },
+      "commits": { "present": false, "shas": null },
+      "problems": [
+        { "code": "MISSING_TESTS", "severity": "warning", "message": "No tests", "details": {} }
+      ]
+    }
+  }
+}"#;
+
+fn setup(dir: &Path) -> (Router, Arc<SnapshotTools>, PathBuf) {
+    let data_dir = dir.join("data");
+    let repo = dir.join("repo");
+    std::fs::create_dir_all(&data_dir).unwrap();
+    std::fs::create_dir_all(repo.join("src")).unwrap();
+    std::fs::create_dir_all(repo.join("spec")).unwrap();
+    std::fs::create_dir_all(repo.join(".cortex/reports")).unwrap();
+    Command::new("git")
+        .arg("init")
+        .current_dir(&repo)
+        .output()
+        .unwrap();
+    std::fs::write(
+        repo.join(".cortex/reports/feature-traceability.json"),
+        REPORT,
+    )
+    .unwrap();
+    std::fs::write(repo.join("spec/demo.md"), "# Demo\nGreets the world.\n").unwrap();
+
+    let resolver = Arc::new(ResolveEngine::new(RealFs, Vec::<PathBuf>::new()));
+    let config = cortex_mcp::config::StorageConfig {
+        data_dir,
+        blob_backend: cortex_mcp::config::BlobBackend::Fs,
+        compression: cortex_mcp::config::Compression::None,
+    };
+    let store = Arc::new(cortex_mcp::snapshot::store::Store::new(config).unwrap());
+    let lease_store = Arc::new(LeaseStore::new());
+    let snapshot_tools = Arc::new(SnapshotTools::new(lease_store.clone(), store.clone()));
+    let workspace_tools = Arc::new(WorkspaceTools::new(lease_store.clone(), store.clone()));
+
+    let router = Router::new(
+        resolver,
+        MountRegistry::new(),
+        snapshot_tools.clone(),
+        workspace_tools,
+    )
+    .with_prompts(Prompts::new(&repo));
+    (router, snapshot_tools, repo)
+}
+
+fn snapshot(tools: &SnapshotTools, repo: &Path, files: &[(&str, &str)]) -> String {
+    for (path, content) in files {
+        std::fs::write(repo.join(path), content).unwrap();
+    }
+    let paths = files.iter().map(|(p, _)| p.to_string()).collect();
+    let res = tools.snapshot_create(repo, None, Some(paths)).unwrap();
+    res["snapshot_id"].as_str().unwrap().to_string()
+}
+
+fn get(router: &Router, params: Value) -> (Option<Value>, Option<Value>) {
+    let resp = router.handle_request(&JsonRpcRequest {
+        jsonrpc: "2.0".to_string(),
+        method: "prompts/get".to_string(),
+        params: Some(params),
+        id: Some(json!(1)),
+    });
+    (resp.result, resp.error)
+}
+
+fn text(result: Option<Value>) -> String {
+    result.unwrap()["messages"][0]["content"]["text"]
+        .as_str()
+        .unwrap()
+        .to_string()
+}
+
+#[test]
+fn test_prompts_render_from_report_and_snapshots() {
+    let dir = tempfile::tempdir().unwrap();
+    let (router, tools, repo) = setup(dir.path());
+    let base = snapshot(
+        &tools,
+        &repo,
+        &[("src/demo.rs", "fn hello() {}\n"), ("notes.txt", "a\n")],
+    );
+    let head = snapshot(
+        &tools,
+        &repo,
+        &[
+            ("src/demo.rs", "fn hello() {}\nfn bye() {}\n"),
+            ("notes.txt", "a\n"),
+        ],
+    );
+    let args = json!({ "feature_id": "DEMO", "snapshot_id": head, "from_snapshot_id": base });
+
+    let (res, err) = get(
+        &router,
+        json!({ "name": "review_diff_against_spec", "arguments": args }),
+    );
+    assert!(err.is_none(), "{:?}", err);
+    let review = text(res);
+    assert!(review.contains("- Spec: spec/demo.md"));
+    assert!(review.contains("Greets the world."));
+    assert!(review.contains("[warning] MISSING_TESTS: No tests"));
+    assert!(review.contains("- modified src/demo.rs (this feature)"));
+    assert!(review.contains("+fn bye() {}"));
+    assert!(!review.contains("notes.txt"));
+
+    // Same inputs, same prompt.
+    let (res, _) = get(
+        &router,
+        json!({ "name": "review_diff_against_spec", "arguments": args }),
+    );
+    assert_eq!(text(res), review);
+
+    let (res, err) = get(
+        &router,
+        json!({ "name": "draft_commit_message", "arguments": { "snapshot_id": head, "from_snapshot_id": base } }),
+    );
+    assert!(err.is_none(), "{:?}", err);
+    let draft = text(res);
+    assert!(draft.contains("Scope: DEMO (touches 1 of 1 changed files)"));
+    assert!(draft.contains("- modified src/demo.rs (DEMO)"));
+}
+
+#[test]
+fn test_prompts_errors() {
+    let dir = tempfile::tempdir().unwrap();
+    let (router, tools, repo) = setup(dir.path());
+    let head = snapshot(&tools, &repo, &[("src/demo.rs", "fn hello() {}\n")]);
+
+    let (_, err) = get(&router, json!({ "arguments": {} }));
+    assert_eq!(err.unwrap()["code"], -32602);
+
+    let (_, err) = get(&router, json!({ "name": "nope" }));
+    assert_eq!(err.unwrap()["code"], "NOT_FOUND");
+
+    let (_, err) = get(
+        &router,
+        json!({ "name": "review_diff_against_spec", "arguments": { "snapshot_id": head } }),
+    );
+    assert_eq!(err.unwrap()["code"], "INVALID_ARGUMENT");
+
+    // A snapshot taken from the worktree has no parent to diff against.
+    let (_, err) = get(
+        &router,
+        json!({ "name": "draft_commit_message", "arguments": { "snapshot_id": head } }),
+    );
+    assert_eq!(err.unwrap()["code"], "INVALID_ARGUMENT");
+
+    let (_, err) = get(
+        &router,
+        json!({ "name": "review_diff_against_spec", "arguments": { "feature_id": "OTHER", "snapshot_id": head } }),
+    );
+    assert_eq!(err.unwrap()["code"], "NOT_FOUND");
+
+    std::fs::remove_file(repo.join(".cortex/reports/feature-traceability.json")).unwrap();
+    let (_, err) = get(
+        &router,
+        json!({ "name": "draft_commit_message", "arguments": { "snapshot_id": head } }),
+    );
+    assert_eq!(err.unwrap()["code"], "NOT_FOUND");
+}
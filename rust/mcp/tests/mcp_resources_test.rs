@@ -0,0 +1,92 @@
+use cortex_mcp::io::fs::RealFs;
+use cortex_mcp::resolver::order::ResolveEngine;
+use cortex_mcp::resources::Resources;
+use cortex_mcp::router::mounts::MountRegistry;
+use cortex_mcp::router::{JsonRpcRequest, Router};
+use cortex_mcp::snapshot::{lease::LeaseStore, tools::SnapshotTools};
+use cortex_mcp::workspace::WorkspaceTools;
+use serde_json::{json, Value};
+use std::path::{Path, PathBuf};
+use std::sync::Arc;
+
+// Feature: MCP_RESOURCES
+// Spec: spec/mcp/resources.md
+
+fn router(data_dir: &Path, repo: &Path) -> Router {
+    let resolver = Arc::new(ResolveEngine::new(RealFs, Vec::<PathBuf>::new()));
+    let config = cortex_mcp::config::StorageConfig {
+        data_dir: data_dir.to_path_buf(),
+        blob_backend: cortex_mcp::config::BlobBackend::Fs,
+        compression: cortex_mcp::config::Compression::None,
+    };
+    let store = Arc::new(cortex_mcp::snapshot::store::Store::new(config).unwrap());
+    let lease_store = Arc::new(LeaseStore::new());
+    let snapshot_tools = Arc::new(SnapshotTools::new(lease_store.clone(), store.clone()));
+    let workspace_tools = Arc::new(WorkspaceTools::new(lease_store.clone(), store.clone()));
+
+    Router::new(
+        resolver,
+        MountRegistry::new(),
+        snapshot_tools,
+        workspace_tools,
+    )
+    .with_resources(Resources::new(repo))
+}
+
+fn call(router: &Router, method: &str, params: Value) -> (Option<Value>, Option<Value>) {
+    let resp = router.handle_request(&JsonRpcRequest {
+        jsonrpc: "2.0".to_string(),
+        method: method.to_string(),
+        params: Some(params),
+        id: Some(json!(1)),
+    });
+    (resp.result, resp.error)
+}
+
+#[test]
+fn test_resources_list_and_read() {
+    let data = tempfile::tempdir().unwrap();
+    let repo = tempfile::tempdir().unwrap();
+    std::fs::create_dir_all(repo.path().join(".cortex/data")).unwrap();
+    std::fs::write(
+        repo.path().join(".cortex/data/index.json"),
+        "{\"files\":[]}",
+    )
+    .unwrap();
+    let router = router(data.path(), repo.path());
+
+    let (res, _) = call(&router, "initialize", json!({}));
+    assert_eq!(
+        res.unwrap()["capabilities"]["resources"]["subscribe"],
+        false
+    );
+
+    let (res, err) = call(&router, "resources/list", json!({}));
+    assert!(err.is_none());
+    let list = res.unwrap();
+    let resources = list["resources"].as_array().unwrap();
+    assert_eq!(resources.len(), 1);
+    assert_eq!(resources[0]["uri"], "cortex://repo/.cortex/data/index.json");
+    assert_eq!(resources[0]["mimeType"], "application/json");
+    let version = resources[0]["_meta"]["version"].clone();
+
+    let (res, err) = call(
+        &router,
+        "resources/read",
+        json!({ "uri": "cortex://repo/.cortex/data/index.json" }),
+    );
+    assert!(err.is_none());
+    let content = &res.unwrap()["contents"][0];
+    assert_eq!(content["text"], "{\"files\":[]}");
+    assert_eq!(content["_meta"]["version"], version);
+
+    let (_, err) = call(
+        &router,
+        "resources/read",
+        json!({ "uri": "cortex://repo/spec/features.yaml" }),
+    );
+    assert_eq!(err.unwrap()["code"], "NOT_FOUND");
+
+    let (_, err) = call(&router, "resources/read", json!({}));
+    assert_eq!(err.unwrap()["code"], -32602);
+}
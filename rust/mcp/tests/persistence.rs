@@ -25,6 +25,7 @@ fn test_persistence_survives_restart() -> anyhow::Result<()> {
             path: "file.txt".to_string(),
             blob: hash.clone(),
             size: content.len() as u64,
+            omitted: None,
         }]);
         let manifest_bytes = serde_json::to_vec(&manifest)?;
 
@@ -107,6 +108,7 @@ fn test_invariant_missing_blob_row() -> anyhow::Result<()> {
         path: "ghost.txt".to_string(),
         blob: fake_hash.to_string(),
         size: 100,
+        omitted: None,
     }]);
     let manifest_bytes = serde_json::to_vec(&manifest)?;
 
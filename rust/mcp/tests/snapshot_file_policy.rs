@@ -0,0 +1,106 @@
+use anyhow::Result;
+use cortex_mcp::config::{BlobBackend, Compression, FilePolicy, StorageConfig};
+use cortex_mcp::router::CortexError;
+use cortex_mcp::snapshot::lease::LeaseStore;
+use cortex_mcp::snapshot::search::GrepOptions;
+use cortex_mcp::snapshot::store::Store;
+use cortex_mcp::snapshot::tools::SnapshotTools;
+use sha2::{Digest, Sha256};
+use std::path::PathBuf;
+use std::sync::Arc;
+
+const BIG: &str = "x marks a file over the limit\n";
+
+fn setup() -> Result<(SnapshotTools, tempfile::TempDir, PathBuf, String)> {
+    let dir = tempfile::tempdir()?;
+    let data_dir = dir.path().join("data");
+    std::fs::create_dir(&data_dir)?;
+    let repo = dir.path().join("repo");
+    std::fs::create_dir(&repo)?;
+    std::process::Command::new("git")
+        .arg("init")
+        .current_dir(&repo)
+        .output()?;
+
+    let store = Arc::new(Store::new(StorageConfig {
+        data_dir,
+        blob_backend: BlobBackend::Fs,
+        compression: Compression::None,
+    })?);
+    let tools = SnapshotTools::new(Arc::new(LeaseStore::new()), store)
+        .with_file_policy(FilePolicy { max_file_bytes: 16 });
+
+    std::fs::write(repo.join("big.txt"), BIG)?;
+    std::fs::write(repo.join("small.txt"), "x\n")?;
+    let res = tools.snapshot_create(
+        &repo,
+        None,
+        Some(vec!["big.txt".to_string(), "small.txt".to_string()]),
+    )?;
+    let sid = res["snapshot_id"].as_str().unwrap().to_string();
+    Ok((tools, dir, repo, sid))
+}
+
+#[test]
+fn test_large_file_is_captured_as_metadata() -> Result<()> {
+    let (tools, _dir, repo, sid) = setup()?;
+
+    let res = tools.snapshot_list(
+        &repo,
+        "",
+        "snapshot",
+        None,
+        Some(sid.clone()),
+        None,
+        None,
+        None,
+    )?;
+    let entries = res["entries"].as_array().unwrap();
+    assert_eq!(entries[0]["path"], "big.txt");
+    assert_eq!(entries[0]["omitted"], "too_large");
+    assert_eq!(entries[0]["size"], BIG.len());
+    assert_eq!(
+        entries[0]["sha"],
+        format!("sha256:{}", hex::encode(Sha256::digest(BIG)))
+    );
+    assert!(entries[1].get("omitted").is_none());
+
+    let err = tools
+        .snapshot_file(&repo, "big.txt", "snapshot", None, Some(sid.clone()))
+        .unwrap_err();
+    assert_eq!(
+        err.downcast_ref::<CortexError>().unwrap().code(),
+        "TOO_LARGE"
+    );
+    tools.snapshot_file(&repo, "small.txt", "snapshot", None, Some(sid.clone()))?;
+
+    let res = tools.snapshot_verify()?;
+    assert_eq!(res["ok"], true, "metadata-only entries reference no blob");
+    Ok(())
+}
+
+#[test]
+fn test_large_file_content_is_not_searched_or_exported() -> Result<()> {
+    let (tools, _dir, repo, sid) = setup()?;
+
+    let res = tools.snapshot_grep(
+        &repo,
+        "x",
+        None,
+        "snapshot",
+        None,
+        Some(sid.clone()),
+        &GrepOptions::default(),
+        None,
+        None,
+    )?;
+    let matches = res["matches"].as_array().unwrap();
+    assert_eq!(matches.len(), 1);
+    assert_eq!(matches[0]["path"], "small.txt");
+
+    let res = tools.snapshot_export(&repo, Some(sid))?;
+    assert_eq!(res["summary"]["included_files"], 1);
+    assert_eq!(res["summary"]["omitted_files"], 1);
+    assert_eq!(res["summary"]["truncated"], true);
+    Ok(())
+}
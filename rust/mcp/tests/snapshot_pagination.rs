@@ -1,6 +1,7 @@
 use anyhow::Result;
 use cortex_mcp::config::{BlobBackend, Compression, StorageConfig};
 use cortex_mcp::snapshot::lease::LeaseStore;
+use cortex_mcp::snapshot::search::GrepOptions;
 use cortex_mcp::snapshot::store::Store;
 use cortex_mcp::snapshot::tools::SnapshotTools;
 use std::sync::Arc;
@@ -58,14 +59,14 @@ fn test_worktree_pagination() -> Result<()> {
     }
 
     // List all
-    let res = tools.snapshot_list(&root, "", "worktree", None, None, None, None)?;
+    let res = tools.snapshot_list(&root, "", "worktree", None, None, None, None, None)?;
     let entries = res["entries"].as_array().unwrap();
     assert_eq!(entries.len(), 10);
     assert_eq!(res["total"], 10);
     assert_eq!(res["truncated"], false);
 
     // Page 1: Limit 4, Offset 0 -> f0..f3
-    let res = tools.snapshot_list(&root, "", "worktree", None, None, Some(4), Some(0))?;
+    let res = tools.snapshot_list(&root, "", "worktree", None, None, Some(4), Some(0), None)?;
     let entries = res["entries"].as_array().unwrap();
     assert_eq!(entries.len(), 4);
     assert_eq!(res["total"], 10);
@@ -74,14 +75,14 @@ fn test_worktree_pagination() -> Result<()> {
     assert_eq!(entries[3]["path"], "f3.txt");
 
     // Page 2: Limit 4, Offset 4 -> f4..f7
-    let res = tools.snapshot_list(&root, "", "worktree", None, None, Some(4), Some(4))?;
+    let res = tools.snapshot_list(&root, "", "worktree", None, None, Some(4), Some(4), None)?;
     let entries = res["entries"].as_array().unwrap();
     assert_eq!(entries.len(), 4);
     assert_eq!(res["truncated"], true); // 4+4 < 10
     assert_eq!(entries[0]["path"], "f4.txt");
 
     // Page 3: Limit 4, Offset 8 -> f8..f9
-    let res = tools.snapshot_list(&root, "", "worktree", None, None, Some(4), Some(8))?;
+    let res = tools.snapshot_list(&root, "", "worktree", None, None, Some(4), Some(8), None)?;
     let entries = res["entries"].as_array().unwrap();
     assert_eq!(entries.len(), 2);
     assert_eq!(res["truncated"], false); // 8+4 >= 10
@@ -115,6 +116,7 @@ fn test_snapshot_pagination() -> Result<()> {
         Some(snap_id.clone()),
         None,
         None,
+        None,
     )?;
     let entries = res["entries"].as_array().unwrap();
     assert_eq!(entries.len(), 10);
@@ -129,6 +131,7 @@ fn test_snapshot_pagination() -> Result<()> {
         Some(snap_id.clone()),
         Some(3),
         Some(2),
+        None,
     )?;
     let entries = res["entries"].as_array().unwrap();
     assert_eq!(entries.len(), 3);
@@ -138,3 +141,99 @@ fn test_snapshot_pagination() -> Result<()> {
 
     Ok(())
 }
+
+#[test]
+fn test_cursor_pagination() -> Result<()> {
+    let (_, _, tools, dir) = setup()?;
+    let root = dir.path().join("repo");
+
+    let mut paths = Vec::new();
+    for i in 0..5 {
+        let name = format!("f{}.txt", i);
+        std::fs::write(root.join(&name), "hit\nmiss\nhit\n")?;
+        paths.push(name);
+    }
+    let snap_res = tools.snapshot_create(&root, None, Some(paths))?;
+    let snap_id = snap_res["snapshot_id"].as_str().unwrap().to_string();
+
+    // Follow next_cursor through the listing: 5 entries, pages of 2.
+    let mut listed = Vec::new();
+    let mut cursor = None;
+    loop {
+        let res = tools.snapshot_list(
+            &root,
+            "",
+            "snapshot",
+            None,
+            Some(snap_id.clone()),
+            Some(2),
+            None,
+            cursor,
+        )?;
+        assert_eq!(res["page_size"], 2);
+        for e in res["entries"].as_array().unwrap() {
+            listed.push(e["path"].as_str().unwrap().to_string());
+        }
+        match res["next_cursor"].as_str() {
+            Some(c) => cursor = Some(c.to_string()),
+            None => break,
+        }
+    }
+    assert_eq!(
+        listed,
+        vec!["f0.txt", "f1.txt", "f2.txt", "f3.txt", "f4.txt"]
+    );
+
+    // Grep pages count matching lines: 10 hits, pages of 3.
+    let grep = |cursor: Option<String>| {
+        tools.snapshot_grep(
+            &root,
+            "hit",
+            None,
+            "snapshot",
+            None,
+            Some(snap_id.clone()),
+            &GrepOptions::default(),
+            Some(3),
+            cursor,
+        )
+    };
+    let first = grep(None)?;
+    assert_eq!(first["truncated"], true);
+    let c1 = first["next_cursor"].as_str().unwrap().to_string();
+    assert_eq!(
+        grep(None)?["next_cursor"],
+        c1,
+        "cursors must be deterministic"
+    );
+
+    let mut hits = 0;
+    let mut page = first;
+    loop {
+        for m in page["matches"].as_array().unwrap() {
+            hits += m["lines"].as_array().unwrap().len();
+        }
+        match page["next_cursor"].as_str() {
+            Some(c) => page = grep(Some(c.to_string()))?,
+            None => break,
+        }
+    }
+    assert_eq!(hits, 10);
+
+    // A cursor only applies to the request that issued it.
+    let err = tools
+        .snapshot_list(
+            &root,
+            "",
+            "snapshot",
+            None,
+            Some(snap_id.clone()),
+            Some(2),
+            None,
+            Some(c1),
+        )
+        .unwrap_err();
+    assert!(err.to_string().contains("Invalid cursor"));
+
+    Ok(())
+}